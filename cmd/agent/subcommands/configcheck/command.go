@@ -32,6 +32,7 @@ type cliParams struct {
 	*command.GlobalParams
 
 	verbose bool
+	dryRun  dryRunParams
 }
 
 // Commands returns a slice of subcommands for the 'agent' command.
@@ -44,7 +45,12 @@ func Commands(globalParams *command.GlobalParams) []*cobra.Command {
 		Use:     "configcheck",
 		Aliases: []string{"checkconfig"},
 		Short:   "Print all configurations loaded & resolved of a running agent",
-		Long:    ``,
+		Long: `Print all configurations loaded & resolved of a running agent.
+
+With --dry-run, lint an autodiscovery template read from a check configuration
+file, or from a file holding container labels or pod annotations, and resolve
+it against a service of the running agent (--entity) or against a service
+described in a file (--service), without scheduling it.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return fxutil.OneShot(run,
 				fx.Supply(cliParams),
@@ -57,11 +63,26 @@ func Commands(globalParams *command.GlobalParams) []*cobra.Command {
 		},
 	}
 	configCheckCommand.Flags().BoolVarP(&cliParams.verbose, "verbose", "v", false, "print additional debug info")
+	configCheckCommand.Flags().BoolVar(&cliParams.dryRun.enabled, "dry-run", false, "lint and resolve a template instead of printing the loaded configurations")
+	configCheckCommand.Flags().StringVar(&cliParams.dryRun.templatePath, "template", "", "[dry-run] path to the template")
+	configCheckCommand.Flags().StringVar(&cliParams.dryRun.templateSource, "template-source", templateSourceFile, "[dry-run] format of the template: file (check configuration file), labels (YAML map of container labels) or annotations (YAML map of pod annotations)")
+	configCheckCommand.Flags().StringVar(&cliParams.dryRun.checkName, "check-name", "", "[dry-run] name of the check configured by a template file, defaults to the name of the file")
+	configCheckCommand.Flags().StringVar(&cliParams.dryRun.containerName, "container-name", "", "[dry-run] name of the container targeted by pod annotations")
+	configCheckCommand.Flags().StringVar(&cliParams.dryRun.entity, "entity", "", "[dry-run] service ID or container ID of a service of the running agent to resolve the template against")
+	configCheckCommand.Flags().StringVar(&cliParams.dryRun.servicePath, "service", "", "[dry-run] path to a YAML description of a service to resolve the template against")
 
 	return []*cobra.Command{configCheckCommand}
 }
 
 func run(config config.Component, cliParams *cliParams, _ log.Component) error {
+	if cliParams.dryRun.enabled {
+		var b bytes.Buffer
+		color.Output = &b
+		err := runDryRun(config, &cliParams.dryRun, color.Output)
+		fmt.Println(b.String())
+		return err
+	}
+
 	endpoint, err := apiutil.NewIPCEndpoint(config, "/agent/config-check")
 	if err != nil {
 		return err
@@ -26,3 +26,16 @@ func TestCommand(t *testing.T) {
 			require.Equal(t, true, secretParams.Enabled)
 		})
 }
+
+func TestDryRunCommand(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"configcheck", "--dry-run", "--template", "conf.yaml", "--service", "service.yaml"},
+		run,
+		func(cliParams *cliParams, coreParams core.BundleParams, secretParams secrets.Params) {
+			require.Equal(t, true, cliParams.dryRun.enabled)
+			require.Equal(t, "conf.yaml", cliParams.dryRun.templatePath)
+			require.Equal(t, templateSourceFile, cliParams.dryRun.templateSource)
+			require.Equal(t, "service.yaml", cliParams.dryRun.servicePath)
+		})
+}
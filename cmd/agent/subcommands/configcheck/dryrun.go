@@ -0,0 +1,385 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package configcheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/fatih/color"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/common/utils"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/configresolver"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/listeners"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/providers"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/providers/names"
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	apiutil "github.com/DataDog/datadog-agent/pkg/api/util"
+	pkgconfig "github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/flare"
+	"github.com/DataDog/datadog-agent/pkg/util/containers"
+)
+
+const (
+	templateSourceFile        = "file"
+	templateSourceLabels      = "labels"
+	templateSourceAnnotations = "annotations"
+
+	// defaultDryRunServiceID is the ID of a service described in a file
+	// without a service_id.
+	defaultDryRunServiceID = "dry-run://service"
+)
+
+// dryRunParams are the command-line arguments of the dry-run mode
+type dryRunParams struct {
+	enabled        bool
+	templatePath   string
+	templateSource string
+	checkName      string
+	containerName  string
+	entity         string
+	servicePath    string
+}
+
+// runDryRun lints the templates given on the command line and, when a service
+// is given, resolves them against it like the autodiscovery would.
+func runDryRun(config config.Component, params *dryRunParams, w io.Writer) error {
+	if params.templatePath == "" {
+		return errors.New("a template is required in dry-run mode, use --template")
+	}
+	if params.entity != "" && params.servicePath != "" {
+		return errors.New("--entity and --service are mutually exclusive")
+	}
+
+	var svc listeners.Service
+	entityName := params.entity
+	if params.servicePath != "" {
+		fakeSvc, err := loadFakeService(params.servicePath)
+		if err != nil {
+			return err
+		}
+		svc = fakeSvc
+		entityName = fakeSvc.GetServiceID()
+	}
+
+	templates, err := loadTemplates(params, entityName)
+	if err != nil {
+		return err
+	}
+
+	failed := false
+	for _, tpl := range templates {
+		if errs := configresolver.LintTemplate(tpl); len(errs) > 0 {
+			failed = true
+			printDryRunErrors(w, fmt.Sprintf("Template %s", tpl.Name), errs)
+		}
+	}
+
+	var response integration.DryRunResponse
+	switch {
+	case params.entity != "":
+		response, err = dryRunOnAgent(config, templates, params.entity)
+		if err != nil {
+			return err
+		}
+	case svc != nil:
+		response = dryRunLocally(templates, svc)
+	default:
+		if !failed {
+			fmt.Fprintf(w, "%d template(s) found in %s, no errors found\n", len(templates), params.templatePath)
+		}
+		return lintResult(failed)
+	}
+
+	fmt.Fprintf(w, "=== Resolving against service %s ===\n", color.CyanString(response.ServiceID))
+	for _, result := range response.Results {
+		if len(result.Errors) > 0 {
+			failed = true
+			errs := make([]error, 0, len(result.Errors))
+			for _, e := range result.Errors {
+				errs = append(errs, errors.New(e))
+			}
+			printDryRunErrors(w, fmt.Sprintf("Unable to resolve template %s", result.Template.Name), errs)
+			continue
+		}
+		if result.Resolved != nil {
+			flare.PrintConfig(w, *result.Resolved, "")
+		}
+	}
+
+	return lintResult(failed)
+}
+
+func lintResult(failed bool) error {
+	if failed {
+		return errors.New("the dry-run found errors in the templates")
+	}
+	return nil
+}
+
+func printDryRunErrors(w io.Writer, title string, errs []error) {
+	fmt.Fprintf(w, "\n%s:\n", color.RedString(title))
+	for _, err := range errs {
+		fmt.Fprintf(w, "* %s\n", err)
+	}
+}
+
+// dryRunLocally resolves templates against a service described on the command
+// line.
+func dryRunLocally(templates []integration.Config, svc listeners.Service) integration.DryRunResponse {
+	response := integration.DryRunResponse{ServiceID: svc.GetServiceID()}
+	for _, tpl := range templates {
+		resolved, errs := configresolver.DryRun(tpl, svc)
+
+		result := integration.DryRunResult{Template: tpl}
+		for _, err := range errs {
+			result.Errors = append(result.Errors, err.Error())
+		}
+		if len(errs) == 0 {
+			result.Resolved = &resolved
+		}
+		response.Results = append(response.Results, result)
+	}
+	return response
+}
+
+// dryRunOnAgent resolves templates against a service of the running agent.
+func dryRunOnAgent(config config.Component, templates []integration.Config, entity string) (integration.DryRunResponse, error) {
+	var response integration.DryRunResponse
+
+	body, err := json.Marshal(integration.DryRunRequest{Templates: templates, Entity: entity})
+	if err != nil {
+		return response, err
+	}
+
+	addr, err := pkgconfig.GetIPCAddress()
+	if err != nil {
+		return response, err
+	}
+
+	if err := apiutil.SetAuthToken(config); err != nil {
+		return response, err
+	}
+
+	url := fmt.Sprintf("https://%v:%v/agent/config-check/dry-run", addr, config.GetInt("cmd_port"))
+	res, err := apiutil.DoPost(apiutil.GetClient(false), url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		var errMap map[string]string
+		if json.Unmarshal(res, &errMap) == nil && errMap["error"] != "" {
+			return response, fmt.Errorf("the agent ran into an error while resolving the templates: %s", errMap["error"])
+		}
+		return response, fmt.Errorf("the agent ran into an error while resolving the templates: %v", err)
+	}
+
+	if err := json.Unmarshal(res, &response); err != nil {
+		return response, fmt.Errorf("unable to parse dry-run response: %v", err)
+	}
+
+	return response, nil
+}
+
+// loadTemplates reads the templates of a check configuration file, or of a
+// file holding container labels or pod annotations. Templates from labels
+// and annotations target the entity they are defined on.
+func loadTemplates(params *dryRunParams, entityName string) ([]integration.Config, error) {
+	if entityName == "" {
+		entityName = defaultDryRunServiceID
+	}
+
+	switch params.templateSource {
+	case templateSourceFile:
+		name := params.checkName
+		if name == "" {
+			name = checkNameFromPath(params.templatePath)
+		}
+		tpl, err := providers.GetIntegrationConfigFromFile(name, params.templatePath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read template %s: %w", params.templatePath, err)
+		}
+		tpl.Provider = names.File
+		return []integration.Config{tpl}, nil
+
+	case templateSourceLabels, templateSourceAnnotations:
+		var metadata map[string]string
+		content, err := os.ReadFile(params.templatePath)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(content, &metadata); err != nil {
+			return nil, fmt.Errorf("unable to parse %s as a map of %s: %w", params.templatePath, params.templateSource, err)
+		}
+
+		var templates []integration.Config
+		var errs []error
+		var provider string
+		if params.templateSource == templateSourceLabels {
+			templates, errs = utils.ExtractTemplatesFromContainerLabels(entityName, metadata)
+			provider = names.Container
+		} else {
+			if params.containerName == "" {
+				return nil, errors.New("a container name is required to read templates from annotations, use --container-name")
+			}
+			templates, errs = utils.ExtractTemplatesFromAnnotations(entityName, metadata, params.containerName)
+			provider = names.Kubernetes
+		}
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("unable to read templates from %s: %w", params.templateSource, errors.Join(errs...))
+		}
+		if len(templates) == 0 {
+			return nil, fmt.Errorf("no templates found in the %s of %s", params.templateSource, params.templatePath)
+		}
+
+		for i := range templates {
+			templates[i].Provider = provider
+			templates[i].Source = params.templateSource + ":" + params.templatePath
+		}
+		return templates, nil
+
+	default:
+		return nil, fmt.Errorf("unknown template source %q, expected one of %s, %s or %s", params.templateSource, templateSourceFile, templateSourceLabels, templateSourceAnnotations)
+	}
+}
+
+// checkNameFromPath returns the name of the check configured by a file, e.g.
+// "redisdb" for "conf.d/redisdb.d/conf.yaml" or "conf.d/redisdb.yaml".
+func checkNameFromPath(path string) string {
+	if dir := filepath.Base(filepath.Dir(path)); strings.HasSuffix(dir, ".d") && dir != "conf.d" {
+		return strings.TrimSuffix(dir, ".d")
+	}
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// fakeServiceDefinition describes a service for the dry-run mode, e.g.
+//
+//	service_id: docker://redis
+//	ad_identifiers: [redis]
+//	hosts: {bridge: 172.17.0.2}
+//	ports: [{port: 6379, name: redis}]
+type fakeServiceDefinition struct {
+	ServiceID     string            `yaml:"service_id"`
+	ADIdentifiers []string          `yaml:"ad_identifiers"`
+	Hosts         map[string]string `yaml:"hosts"`
+	Ports         []struct {
+		Port int    `yaml:"port"`
+		Name string `yaml:"name"`
+	} `yaml:"ports"`
+	Pid      int               `yaml:"pid"`
+	Hostname string            `yaml:"hostname"`
+	Tags     []string          `yaml:"tags"`
+	Extra    map[string]string `yaml:"extra"`
+}
+
+// fakeService is a listeners.Service described in a file.
+type fakeService struct {
+	def   fakeServiceDefinition
+	ports []listeners.ContainerPort
+}
+
+var _ listeners.Service = &fakeService{}
+
+func loadFakeService(path string) (*fakeService, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := &fakeService{}
+	if err := yaml.UnmarshalStrict(content, &svc.def); err != nil {
+		return nil, fmt.Errorf("unable to parse service %s: %w", path, err)
+	}
+
+	if svc.def.ServiceID == "" {
+		svc.def.ServiceID = defaultDryRunServiceID
+	}
+	if len(svc.def.ADIdentifiers) == 0 {
+		svc.def.ADIdentifiers = []string{svc.def.ServiceID}
+	}
+	for _, port := range svc.def.Ports {
+		svc.ports = append(svc.ports, listeners.ContainerPort{Port: port.Port, Name: port.Name})
+	}
+
+	return svc, nil
+}
+
+// Equal returns whether the two fakeService are equal
+func (s *fakeService) Equal(o listeners.Service) bool {
+	return reflect.DeepEqual(s, o)
+}
+
+// GetServiceID returns the service ID
+func (s *fakeService) GetServiceID() string {
+	return s.def.ServiceID
+}
+
+// GetADIdentifiers returns the service AD identifiers
+func (s *fakeService) GetADIdentifiers(context.Context) ([]string, error) {
+	return s.def.ADIdentifiers, nil
+}
+
+// GetHosts returns the service hosts
+func (s *fakeService) GetHosts(context.Context) (map[string]string, error) {
+	return s.def.Hosts, nil
+}
+
+// GetPorts returns the service ports
+func (s *fakeService) GetPorts(context.Context) ([]listeners.ContainerPort, error) {
+	return s.ports, nil
+}
+
+// GetTags returns the service tags
+func (s *fakeService) GetTags() ([]string, error) {
+	return s.def.Tags, nil
+}
+
+// GetPid returns the service pid
+func (s *fakeService) GetPid(context.Context) (int, error) {
+	if s.def.Pid == 0 {
+		return 0, errors.New("no pid in the service description")
+	}
+	return s.def.Pid, nil
+}
+
+// GetHostname returns the service hostname
+func (s *fakeService) GetHostname(context.Context) (string, error) {
+	if s.def.Hostname == "" {
+		return "", errors.New("no hostname in the service description")
+	}
+	return s.def.Hostname, nil
+}
+
+// IsReady returns true
+func (s *fakeService) IsReady(context.Context) bool {
+	return true
+}
+
+// HasFilter returns false
+func (s *fakeService) HasFilter(containers.FilterType) bool {
+	return false
+}
+
+// GetExtraConfig returns the extra configuration of the service
+func (s *fakeService) GetExtraConfig(key string) (string, error) {
+	value, found := s.def.Extra[key]
+	if !found {
+		return "", fmt.Errorf("extra config %q not in the service description", key)
+	}
+	return value, nil
+}
+
+// FilterTemplates does nothing
+func (s *fakeService) FilterTemplates(map[string]integration.Config) {
+}
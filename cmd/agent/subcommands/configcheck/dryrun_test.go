@@ -0,0 +1,113 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package configcheck
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestRunDryRun(t *testing.T) {
+	color.NoColor = true
+
+	dir := t.TempDir()
+	servicePath := writeFile(t, dir, "service.yaml", `
+service_id: docker://abcd
+ad_identifiers: [redis]
+hosts: {bridge: 172.17.0.2}
+ports: [{port: 6379, name: redis}]
+tags: ["env:dev"]
+`)
+
+	tests := []struct {
+		name           string
+		params         dryRunParams
+		expectedErr    string
+		expectedOutput []string
+	}{
+		{
+			name: "resolved file template",
+			params: dryRunParams{
+				templatePath:   writeFile(t, dir, "redisdb.d/conf.yaml", "ad_identifiers: [redis]\ninstances:\n- host: '%%host%%'\n  port: '%%port_redis%%'\n"),
+				templateSource: templateSourceFile,
+				servicePath:    servicePath,
+			},
+			expectedOutput: []string{
+				"=== Resolving against service docker://abcd ===",
+				"=== redisdb check ===",
+				"host: 172.17.0.2\nport: 6379\ntags:\n- env:dev\n",
+			},
+		},
+		{
+			name: "lint only",
+			params: dryRunParams{
+				templatePath:   writeFile(t, dir, "lint.yaml", "ad_identifiers: [redis, ' redis']\ninstances:\n- host: '%%hots%%'\n"),
+				templateSource: templateSourceFile,
+			},
+			expectedErr: "the dry-run found errors in the templates",
+			expectedOutput: []string{
+				"Template lint:",
+				"* %%hots%%: unknown template variable",
+				`* invalid AD identifier " redis": leading or trailing whitespace`,
+			},
+		},
+		{
+			name: "unresolvable label template",
+			params: dryRunParams{
+				templatePath:   writeFile(t, dir, "labels.yaml", "com.datadoghq.ad.check_names: '[\"redisdb\"]'\ncom.datadoghq.ad.init_configs: '[{}]'\ncom.datadoghq.ad.instances: '[{\"host\": \"%%host%%\", \"port\": \"%%port_admin%%\"}]'\n"),
+				templateSource: templateSourceLabels,
+				servicePath:    servicePath,
+			},
+			expectedErr: "the dry-run found errors in the templates",
+			expectedOutput: []string{
+				"Unable to resolve template redisdb:",
+				"* %%port_admin%%: port admin not found, skipping container docker://abcd",
+			},
+		},
+		{
+			name: "annotations without container name",
+			params: dryRunParams{
+				templatePath:   writeFile(t, dir, "annotations.yaml", "ad.datadoghq.com/redis.checks: '{}'\n"),
+				templateSource: templateSourceAnnotations,
+			},
+			expectedErr: "a container name is required to read templates from annotations, use --container-name",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := runDryRun(nil, &tc.params, &out)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			for _, expected := range tc.expectedOutput {
+				assert.Contains(t, out.String(), expected)
+			}
+		})
+	}
+}
+
+func TestCheckNameFromPath(t *testing.T) {
+	assert.Equal(t, "redisdb", checkNameFromPath("/etc/datadog-agent/conf.d/redisdb.d/conf.yaml"))
+	assert.Equal(t, "redisdb", checkNameFromPath("/etc/datadog-agent/conf.d/redisdb.yaml"))
+	assert.Equal(t, "auto_conf", checkNameFromPath("auto_conf.yaml"))
+}
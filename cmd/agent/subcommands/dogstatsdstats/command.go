@@ -11,6 +11,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"go.uber.org/fx"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/DataDog/datadog-agent/comp/core/config"
 	"github.com/DataDog/datadog-agent/comp/core/log"
 	"github.com/DataDog/datadog-agent/comp/dogstatsd/serverDebug/serverdebugimpl"
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
 	"github.com/DataDog/datadog-agent/pkg/api/util"
 	pkgconfig "github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
@@ -36,6 +38,7 @@ type cliParams struct {
 	dsdStatsFilePath string
 	jsonStatus       bool
 	prettyPrintJSON  bool
+	tagCardinality   bool
 }
 
 // Commands returns a slice of subcommands for the 'agent' command.
@@ -60,6 +63,7 @@ func Commands(globalParams *command.GlobalParams) []*cobra.Command {
 	dogstatsdStatsCmd.Flags().BoolVarP(&cliParams.jsonStatus, "json", "j", false, "print out raw json")
 	dogstatsdStatsCmd.Flags().BoolVarP(&cliParams.prettyPrintJSON, "pretty-json", "p", false, "pretty print JSON")
 	dogstatsdStatsCmd.Flags().StringVarP(&cliParams.dsdStatsFilePath, "file", "o", "", "Output the dogstatsd-stats command to a file")
+	dogstatsdStatsCmd.Flags().BoolVarP(&cliParams.tagCardinality, "tag-cardinality", "c", false, "print the tags with the most distinct values instead of the metrics stats")
 
 	return []*cobra.Command{dogstatsdStatsCmd}
 }
@@ -75,6 +79,9 @@ func requestDogstatsdStats(log log.Component, config config.Component, cliParams
 		return err
 	}
 	urlstr := fmt.Sprintf("https://%v:%v/agent/dogstatsd-stats", ipcAddress, pkgconfig.Datadog().GetInt("cmd_port"))
+	if cliParams.tagCardinality {
+		urlstr += "?tag_cardinality=true"
+	}
 
 	// Set session token
 	e = util.SetAuthToken(config)
@@ -109,7 +116,11 @@ func requestDogstatsdStats(log log.Component, config config.Component, cliParams
 	} else if cliParams.jsonStatus {
 		s = string(r)
 	} else {
-		s, e = serverdebugimpl.FormatDebugStats(r)
+		if cliParams.tagCardinality {
+			s, e = formatTagCardinalityStats(r)
+		} else {
+			s, e = serverdebugimpl.FormatDebugStats(r)
+		}
 		if e != nil {
 			fmt.Printf("Could not format the statistics, the data must be inconsistent. You may want to try the JSON output. Contact the support if you continue having issues.\n")
 			return nil
@@ -137,3 +148,27 @@ func requestDogstatsdStats(log log.Component, config config.Component, cliParams
 
 	return nil
 }
+
+// formatTagCardinalityStats returns a printable version of tag cardinality stats.
+func formatTagCardinalityStats(stats []byte) (string, error) {
+	var tagStats []aggregator.TagCardinalityStats
+	if err := json.Unmarshal(stats, &tagStats); err != nil {
+		return "", err
+	}
+
+	buf := bytes.NewBuffer(nil)
+
+	header := fmt.Sprintf("%-40s | %-20s | %-10s | %-10s\n", "Metric", "Tag", "Values", "Limited")
+	buf.WriteString(header)
+	buf.WriteString(strings.Repeat("-", len(header)) + "\n")
+
+	for _, ts := range tagStats {
+		buf.WriteString(fmt.Sprintf("%-40s | %-20s | %-10d | %-10d\n", ts.Metric, ts.Tag, ts.Values, ts.Limited))
+	}
+
+	if len(tagStats) == 0 {
+		buf.WriteString("No tags tracked yet.")
+	}
+
+	return buf.String(), nil
+}
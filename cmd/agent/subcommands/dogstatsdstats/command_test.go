@@ -26,3 +26,25 @@ func TestCommand(t *testing.T) {
 			require.Equal(t, false, secretParams.Enabled)
 		})
 }
+
+func TestCommandTagCardinality(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"dogstatsd-stats", "--tag-cardinality"},
+		requestDogstatsdStats,
+		func(cliParams *cliParams, _ core.BundleParams, _ secrets.Params) {
+			require.True(t, cliParams.tagCardinality)
+		})
+}
+
+func TestFormatTagCardinalityStats(t *testing.T) {
+	s, err := formatTagCardinalityStats([]byte(`[{"metric":"requests","tag":"user_id","values":100,"limited":42}]`))
+	require.NoError(t, err)
+	require.Contains(t, s, "requests")
+	require.Contains(t, s, "user_id")
+	require.Contains(t, s, "42")
+
+	s, err = formatTagCardinalityStats([]byte(`[]`))
+	require.NoError(t, err)
+	require.Contains(t, s, "No tags tracked yet.")
+}
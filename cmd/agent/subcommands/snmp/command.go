@@ -37,6 +37,7 @@ import (
 	"github.com/DataDog/datadog-agent/comp/forwarder/eventplatformreceiver/eventplatformreceiverimpl"
 	"github.com/DataDog/datadog-agent/comp/forwarder/orchestrator/orchestratorimpl"
 	"github.com/DataDog/datadog-agent/comp/snmptraps/snmplog"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/profilegen"
 	"github.com/DataDog/datadog-agent/pkg/logs/message"
 	"github.com/DataDog/datadog-agent/pkg/networkdevice/metadata"
 	"github.com/DataDog/datadog-agent/pkg/snmp/gosnmplib"
@@ -93,6 +94,14 @@ var levelOpts = NewOptions(OptPairs[gosnmp.SnmpV3MsgFlags]{
 	{"authPriv", gosnmp.AuthPriv},
 })
 
+// profileGenParams are the options of the generate-profile command.
+type profileGenParams struct {
+	Name                string
+	OutputFile          string
+	SysObjectIDWildcard bool
+	WalkFile            string
+}
+
 // argsType is an alias so we can inject the args via fx.
 type argsType []string
 
@@ -154,24 +163,7 @@ func Commands(globalParams *command.GlobalParams) []*cobra.Command {
 			return nil
 		},
 	}
-	snmpWalkCmd.Flags().VarP(versionOpts.Flag(&connParams.Version), "snmp-version", "v", fmt.Sprintf("Specify SNMP version to use (%s)", versionOpts.OptsStr()))
-
-	// snmp v1 or v2c specific
-	snmpWalkCmd.Flags().StringVarP(&connParams.CommunityString, "community-string", "C", "", "Set the community string")
-
-	// snmp v3 specific
-	snmpWalkCmd.Flags().VarP(authOpts.Flag(&connParams.AuthProtocol), "auth-protocol", "a", fmt.Sprintf("Set authentication protocol (%s)", authOpts.OptsStr()))
-	snmpWalkCmd.Flags().StringVarP(&connParams.AuthKey, "auth-key", "A", "", "Set authentication protocol pass phrase")
-	snmpWalkCmd.Flags().VarP(levelOpts.Flag(&connParams.SecurityLevel), "security-level", "l", fmt.Sprintf("Set security level (%s)", levelOpts.OptsStr()))
-	snmpWalkCmd.Flags().StringVarP(&connParams.Context, "context", "N", "", "Set context name")
-	snmpWalkCmd.Flags().StringVarP(&connParams.Username, "user-name", "u", "", "Set security name")
-	snmpWalkCmd.Flags().VarP(privOpts.Flag(&connParams.PrivProtocol), "priv-protocol", "x", fmt.Sprintf("Set privacy protocol (%s)", privOpts.OptsStr()))
-	snmpWalkCmd.Flags().StringVarP(&connParams.PrivKey, "priv-key", "X", "", "Set privacy protocol pass phrase")
-
-	// general communication options
-	snmpWalkCmd.Flags().IntVarP(&connParams.Retries, "retries", "r", defaultRetries, "Set the number of retries")
-	snmpWalkCmd.Flags().IntVarP(&connParams.Timeout, "timeout", "t", defaultTimeout, "Set the request timeout (in seconds)")
-	snmpWalkCmd.Flags().BoolVar(&connParams.UseUnconnectedUDPSocket, "use-unconnected-udp-socket", defaultUseUnconnectedUDPSocket, "If specified, changes net connection to be unconnected UDP socket")
+	addConnectionFlags(snmpWalkCmd, connParams)
 
 	snmpCmd.AddCommand(snmpWalkCmd)
 
@@ -212,32 +204,72 @@ func Commands(globalParams *command.GlobalParams) []*cobra.Command {
 			return nil
 		},
 	}
-	// TODO is there a way to merge these flags with snmpWalkCmd flags, without cobra changing the docs to mark them as "global flags"?
-	snmpScanCmd.Flags().VarP(versionOpts.Flag(&connParams.Version), "snmp-version", "v", fmt.Sprintf("Specify SNMP version to use (%s)", versionOpts.OptsStr()))
+	addConnectionFlags(snmpScanCmd, connParams)
 
-	// snmp v1 or v2c specific
-	snmpScanCmd.Flags().StringVarP(&connParams.CommunityString, "community-string", "C", "", "Set the community string")
+	// This command does nothing until the backend supports it, so it isn't enabled yet.
+	// snmpCmd.AddCommand(snmpScanCmd)
 
-	// snmp v3 specific
-	snmpScanCmd.Flags().VarP(authOpts.Flag(&connParams.AuthProtocol), "auth-protocol", "a", fmt.Sprintf("Set authentication protocol (%s)", authOpts.OptsStr()))
-	snmpScanCmd.Flags().StringVarP(&connParams.AuthKey, "auth-key", "A", "", "Set authentication protocol pass phrase")
-	snmpScanCmd.Flags().VarP(levelOpts.Flag(&connParams.SecurityLevel), "security-level", "l", fmt.Sprintf("Set security level (%s)", levelOpts.OptsStr()))
-	snmpScanCmd.Flags().StringVarP(&connParams.Context, "context", "N", "", "Set context name")
-	snmpScanCmd.Flags().StringVarP(&connParams.Username, "user-name", "u", "", "Set security name")
-	snmpScanCmd.Flags().VarP(privOpts.Flag(&connParams.PrivProtocol), "priv-protocol", "x", fmt.Sprintf("Set privacy protocol (%s)", privOpts.OptsStr()))
-	snmpScanCmd.Flags().StringVarP(&connParams.PrivKey, "priv-key", "X", "", "Set privacy protocol pass phrase")
+	genParams := &profileGenParams{}
+	snmpGenerateProfileCmd := &cobra.Command{
+		Use:   "generate-profile <IP Address>[:Port]",
+		Short: "Generate a profile from a device walk.",
+		Long: `Walk the SNMP tree for a device and generate a profile from the MIBs it answers on.
+		The generated profile extends the standard base profiles and should be reviewed before use.
+		Flags that aren't specified will be pulled from the agent SNMP config if possible.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
 
-	// general communication options
-	snmpScanCmd.Flags().IntVarP(&connParams.Retries, "retries", "r", defaultRetries, "Set the number of retries")
-	snmpScanCmd.Flags().IntVarP(&connParams.Timeout, "timeout", "t", defaultTimeout, "Set the request timeout (in seconds)")
-	snmpScanCmd.Flags().BoolVar(&connParams.UseUnconnectedUDPSocket, "use-unconnected-udp-socket", defaultUseUnconnectedUDPSocket, "If specified, changes net connection to be unconnected UDP socket")
+			err := fxutil.OneShot(generateProfile,
+				fx.Supply(connParams, genParams, globalParams, cmd),
+				fx.Provide(func() argsType { return args }),
+				fx.Supply(core.BundleParams{
+					ConfigParams: config.NewAgentParams(globalParams.ConfFilePath, config.WithExtraConfFiles(globalParams.ExtraConfFilePath)),
+					SecretParams: secrets.NewEnabledParams(),
+					LogParams:    logimpl.ForOneShot(command.LoggerName, "off", true)}),
+				core.Bundle(),
+			)
+			if err != nil {
+				var ue configErr
+				if errors.As(err, &ue) {
+					fmt.Println("Usage:", cmd.UseLine())
+				}
+				return err
+			}
+			return nil
+		},
+	}
+	addConnectionFlags(snmpGenerateProfileCmd, connParams)
+	snmpGenerateProfileCmd.Flags().StringVar(&genParams.Name, "name", "", "Set the profile name (derived from the device vendor by default)")
+	snmpGenerateProfileCmd.Flags().StringVarP(&genParams.OutputFile, "output", "o", "", "Write the profile to this file instead of stdout")
+	snmpGenerateProfileCmd.Flags().BoolVar(&genParams.SysObjectIDWildcard, "sysobjectid-wildcard", false, "Match the whole product family of the device instead of its exact sysObjectID")
+	snmpGenerateProfileCmd.Flags().StringVar(&genParams.WalkFile, "from-file", "", "Read the walk from a .snmprec file instead of querying a device")
 
-	// This command does nothing until the backend supports it, so it isn't enabled yet.
-	// snmpCmd.AddCommand(snmpScanCmd)
+	snmpCmd.AddCommand(snmpGenerateProfileCmd)
 
 	return []*cobra.Command{snmpCmd}
 }
 
+// addConnectionFlags adds the flags used to connect to a device to the given command.
+func addConnectionFlags(cmd *cobra.Command, connParams *connectionParams) {
+	cmd.Flags().VarP(versionOpts.Flag(&connParams.Version), "snmp-version", "v", fmt.Sprintf("Specify SNMP version to use (%s)", versionOpts.OptsStr()))
+
+	// snmp v1 or v2c specific
+	cmd.Flags().StringVarP(&connParams.CommunityString, "community-string", "C", "", "Set the community string")
+
+	// snmp v3 specific
+	cmd.Flags().VarP(authOpts.Flag(&connParams.AuthProtocol), "auth-protocol", "a", fmt.Sprintf("Set authentication protocol (%s)", authOpts.OptsStr()))
+	cmd.Flags().StringVarP(&connParams.AuthKey, "auth-key", "A", "", "Set authentication protocol pass phrase")
+	cmd.Flags().VarP(levelOpts.Flag(&connParams.SecurityLevel), "security-level", "l", fmt.Sprintf("Set security level (%s)", levelOpts.OptsStr()))
+	cmd.Flags().StringVarP(&connParams.Context, "context", "N", "", "Set context name")
+	cmd.Flags().StringVarP(&connParams.Username, "user-name", "u", "", "Set security name")
+	cmd.Flags().VarP(privOpts.Flag(&connParams.PrivProtocol), "priv-protocol", "x", fmt.Sprintf("Set privacy protocol (%s)", privOpts.OptsStr()))
+	cmd.Flags().StringVarP(&connParams.PrivKey, "priv-key", "X", "", "Set privacy protocol pass phrase")
+
+	// general communication options
+	cmd.Flags().IntVarP(&connParams.Retries, "retries", "r", defaultRetries, "Set the number of retries")
+	cmd.Flags().IntVarP(&connParams.Timeout, "timeout", "t", defaultTimeout, "Set the request timeout (in seconds)")
+	cmd.Flags().BoolVar(&connParams.UseUnconnectedUDPSocket, "use-unconnected-udp-socket", defaultUseUnconnectedUDPSocket, "If specified, changes net connection to be unconnected UDP socket")
+}
+
 // maybeSplitIP splits an address into a host and port if possible.
 // The return value is (host, port, ok) where ok will be true if and only if
 // the parsing succeeded. If it fails, we assume that this address is only an
@@ -469,6 +501,89 @@ func gatherPDUs(snmp *gosnmp.GoSNMP) ([]*gosnmp.SnmpPDU, error) {
 	return pdus, nil
 }
 
+// generateProfile walks a device (or reads a recorded walk) and prints a profile generated from it.
+func generateProfile(connParams *connectionParams, genParams *profileGenParams, args argsType, conf config.Component, logger log.Component) error {
+	var pdus []gosnmp.SnmpPDU
+	if genParams.WalkFile != "" {
+		if len(args) > 0 {
+			return confErrf("unexpected arguments; no IP address is expected with --from-file.")
+		}
+		f, err := os.Open(genParams.WalkFile)
+		if err != nil {
+			return fmt.Errorf("unable to open walk file: %w", err)
+		}
+		defer f.Close()
+		if pdus, err = gosnmplib.ParseSnmprec(f); err != nil {
+			return fmt.Errorf("unable to read walk file %s: %w", genParams.WalkFile, err)
+		}
+	} else {
+		// Parse args
+		if len(args) == 0 {
+			return confErrf("missing argument: IP address")
+		}
+		deviceAddr := args[0]
+		if len(args) > 1 {
+			return confErrf("unexpected extra arguments; only one argument expected.")
+		}
+		// Parse port from IP address
+		connParams.IPAddress, connParams.Port, _ = maybeSplitIP(deviceAddr)
+		agentErr := setDefaultsFromAgent(connParams, conf)
+		if agentErr != nil {
+			// Warn that we couldn't contact the agent, but keep going in case the
+			// user provided enough arguments to do this anyway.
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", agentErr)
+		}
+		// Establish connection
+		snmp, err := newSNMP(connParams, logger)
+		if err != nil {
+			// newSNMP only returns config errors, so any problem is a usage error
+			return configErr{err}
+		}
+		if err := snmp.Connect(); err != nil {
+			return fmt.Errorf("unable to connect to SNMP agent on %s:%d: %w", snmp.LocalAddr, snmp.Port, err)
+		}
+		defer snmp.Conn.Close()
+		walked, err := gatherPDUs(snmp)
+		if err != nil {
+			return fmt.Errorf("unable to walk SNMP agent on %s:%d: %w", snmp.Target, snmp.Port, err)
+		}
+		for _, pdu := range walked {
+			pdus = append(pdus, *pdu)
+		}
+	}
+
+	result, err := profilegen.Generate(pdus, profilegen.Options{
+		Name:                genParams.Name,
+		SysObjectIDWildcard: genParams.SysObjectIDWildcard,
+	})
+	if err != nil {
+		return err
+	}
+	content, err := profilegen.Marshal(result.Profile)
+	if err != nil {
+		return fmt.Errorf("unable to marshal profile: %w", err)
+	}
+	content = append([]byte("# Generated by `agent snmp generate-profile`, review it before use.\n"), content...)
+
+	fmt.Fprintf(os.Stderr, "Matched MIBs: %s\n", strings.Join(result.MatchedMIBs, ", "))
+	if len(result.UnmatchedSubtrees) > 0 {
+		fmt.Fprintf(os.Stderr, "Vendor OIDs not covered by the profile, consider adding them manually:\n")
+		for _, subtree := range result.UnmatchedSubtrees {
+			fmt.Fprintf(os.Stderr, "  %s\n", subtree)
+		}
+	}
+
+	if genParams.OutputFile == "" {
+		_, err = os.Stdout.Write(content)
+		return err
+	}
+	if err := os.WriteFile(genParams.OutputFile, content, 0644); err != nil {
+		return fmt.Errorf("unable to write profile: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Profile %s written to %s\n", result.Profile.Name, genParams.OutputFile)
+	return nil
+}
+
 // snmpwalk prints every SNMP value, in the style of the unix snmpwalk command.
 func snmpwalk(connParams *connectionParams, args argsType, conf config.Component, logger log.Component) error {
 	// Parse args
@@ -36,6 +36,19 @@ func TestCommand(t *testing.T) {
 			require.Equal(t, argsType{"1.2.3.4", "10.9.8.7"}, args)
 			require.True(t, cliParams.UseUnconnectedUDPSocket)
 		})
+
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"snmp", "generate-profile", "1.2.3.4", "-C", "private", "--name", "my-device", "-o", "my-device.yaml", "--sysobjectid-wildcard"},
+		generateProfile,
+		func(cliParams *connectionParams, genParams *profileGenParams, args argsType) {
+			require.Equal(t, argsType{"1.2.3.4"}, args)
+			require.Equal(t, "private", cliParams.CommunityString)
+			require.Equal(t, "my-device", genParams.Name)
+			require.Equal(t, "my-device.yaml", genParams.OutputFile)
+			require.True(t, genParams.SysObjectIDWildcard)
+			require.Empty(t, genParams.WalkFile)
+		})
 }
 
 func TestSplitIP(t *testing.T) {
@@ -52,11 +52,26 @@ type MutateRequest struct {
 	DynamicClient dynamic.Interface
 	// APIClient holds a Kubernetes client
 	APIClient kubernetes.Interface
+	// DryRun indicates that the changes will not be persisted
+	DryRun bool
 }
 
 // WebhookFunc is the function that runs the webhook logic
 type WebhookFunc func(request *MutateRequest) ([]byte, error)
 
+// ValidationResult contains the outcome of a validation request
+type ValidationResult struct {
+	// Allowed indicates whether the request is admitted
+	Allowed bool
+	// Message explains why the request is denied
+	Message string
+	// Warnings are returned to the API client, even if the request is admitted
+	Warnings []string
+}
+
+// ValidatingWebhookFunc is the function that runs the validating webhook logic
+type ValidatingWebhookFunc func(request *MutateRequest) (*ValidationResult, error)
+
 // Server TODO <container-integrations>
 type Server struct {
 	decoder runtime.Decoder
@@ -94,7 +109,21 @@ func (s *Server) initDecoder() {
 // Register must be called to register the desired webhook handlers before calling Run.
 func (s *Server) Register(uri string, webhookName string, f WebhookFunc, dc dynamic.Interface, apiClient kubernetes.Interface) {
 	s.mux.HandleFunc(uri, func(w http.ResponseWriter, r *http.Request) {
-		s.mutateHandler(w, r, webhookName, f, dc, apiClient)
+		s.handle(w, r, webhookName, func(request *MutateRequest) *admiv1.AdmissionResponse {
+			jsonPatch, err := f(request)
+			return mutationResponse(jsonPatch, err)
+		}, dc, apiClient)
+	})
+}
+
+// RegisterValidating adds a validating admission webhook handler.
+// RegisterValidating must be called to register the desired webhook handlers before calling Run.
+func (s *Server) RegisterValidating(uri string, webhookName string, f ValidatingWebhookFunc, dc dynamic.Interface, apiClient kubernetes.Interface) {
+	s.mux.HandleFunc(uri, func(w http.ResponseWriter, r *http.Request) {
+		s.handle(w, r, webhookName, func(request *MutateRequest) *admiv1.AdmissionResponse {
+			result, err := f(request)
+			return validationResponse(result, err)
+		}, dc, apiClient)
 	})
 }
 
@@ -134,9 +163,9 @@ func (s *Server) Run(mainCtx context.Context, client kubernetes.Interface) error
 	return server.Shutdown(shutdownCtx)
 }
 
-// mutateHandler contains the main logic responsible for handling mutation requests.
+// handle contains the main logic responsible for handling admission requests.
 // It supports both v1 and v1beta1 requests.
-func (s *Server) mutateHandler(w http.ResponseWriter, r *http.Request, webhookName string, mutateFunc WebhookFunc, dc dynamic.Interface, apiClient kubernetes.Interface) {
+func (s *Server) handle(w http.ResponseWriter, r *http.Request, webhookName string, respond func(*MutateRequest) *admiv1.AdmissionResponse, dc dynamic.Interface, apiClient kubernetes.Interface) {
 	metrics.WebhooksReceived.Inc(webhookName)
 
 	start := time.Now()
@@ -187,9 +216,9 @@ func (s *Server) mutateHandler(w http.ResponseWriter, r *http.Request, webhookNa
 			UserInfo:      &admissionReviewReq.Request.UserInfo,
 			DynamicClient: dc,
 			APIClient:     apiClient,
+			DryRun:        admissionReviewReq.Request.DryRun != nil && *admissionReviewReq.Request.DryRun,
 		}
-		jsonPatch, err := mutateFunc(&mutateRequest)
-		admissionReviewResp.Response = mutationResponse(jsonPatch, err)
+		admissionReviewResp.Response = respond(&mutateRequest)
 		admissionReviewResp.Response.UID = admissionReviewReq.Request.UID
 		response = admissionReviewResp
 	case admiv1beta1.SchemeGroupVersion.WithKind("AdmissionReview"):
@@ -206,9 +235,9 @@ func (s *Server) mutateHandler(w http.ResponseWriter, r *http.Request, webhookNa
 			UserInfo:      &admissionReviewReq.Request.UserInfo,
 			DynamicClient: dc,
 			APIClient:     apiClient,
+			DryRun:        admissionReviewReq.Request.DryRun != nil && *admissionReviewReq.Request.DryRun,
 		}
-		jsonPatch, err := mutateFunc(&mutateRequest)
-		admissionReviewResp.Response = responseV1ToV1beta1(mutationResponse(jsonPatch, err))
+		admissionReviewResp.Response = responseV1ToV1beta1(respond(&mutateRequest))
 		admissionReviewResp.Response.UID = admissionReviewReq.Request.UID
 		response = admissionReviewResp
 	default:
@@ -249,6 +278,36 @@ func mutationResponse(jsonPatch []byte, err error) *admiv1.AdmissionResponse {
 	}
 }
 
+// validationResponse returns the adequate v1.AdmissionResponse based on the validation result.
+func validationResponse(result *ValidationResult, err error) *admiv1.AdmissionResponse {
+	if err != nil {
+		log.Warnf("Failed to validate: %v", err)
+
+		return &admiv1.AdmissionResponse{
+			Result: &metav1.Status{
+				Message: err.Error(),
+			},
+			Allowed: true,
+		}
+	}
+
+	resp := &admiv1.AdmissionResponse{
+		Allowed:  result.Allowed,
+		Warnings: result.Warnings,
+	}
+
+	if !result.Allowed {
+		resp.Result = &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Reason:  metav1.StatusReasonInvalid,
+			Code:    http.StatusUnprocessableEntity,
+			Message: result.Message,
+		}
+	}
+
+	return resp
+}
+
 // responseV1ToV1beta1 converts a v1.AdmissionResponse into a v1beta1.AdmissionResponse.
 func responseV1ToV1beta1(resp *admiv1.AdmissionResponse) *admiv1beta1.AdmissionResponse {
 	var patchType *admiv1beta1.PatchType
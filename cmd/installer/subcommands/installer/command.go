@@ -8,6 +8,7 @@ package installer
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -64,7 +65,7 @@ func Commands(_ *command.GlobalParams) []*cobra.Command {
 
 // UnprivilegedCommands returns the unprivileged installer subcommands.
 func UnprivilegedCommands(_ *command.GlobalParams) []*cobra.Command {
-	return []*cobra.Command{versionCommand(), defaultPackagesCommand()}
+	return []*cobra.Command{versionCommand(), defaultPackagesCommand(), mirrorCommand()}
 }
 
 type cmd struct {
@@ -197,6 +198,37 @@ func defaultPackagesCommand() *cobra.Command {
 	}
 }
 
+func mirrorCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mirror <catalog> <mirror-url>",
+		Short: "Copy the packages of a catalog to a mirror",
+		Long: `Copy the packages of a catalog and their signatures to a mirror.
+
+The catalog is a JSON file listing the packages to mirror: {"packages": [{"package": "datadog-agent", "version": "7.55.0-1"}]}.
+Packages can also set an explicit "url", as in the remote updates catalog.
+The mirror is either a registry (oci://<registry>/<prefix>) or an OCI layout directory (file://<path>).`,
+		GroupID: "installer",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) (err error) {
+			c := newCmd("mirror")
+			defer func() { c.Stop(err) }()
+			c.span.SetTag("params.catalog", args[0])
+			c.span.SetTag("params.mirror", args[1])
+			rawCatalog, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("could not read catalog: %w", err)
+			}
+			var catalog installer.MirrorCatalog
+			err = json.Unmarshal(rawCatalog, &catalog)
+			if err != nil {
+				return fmt.Errorf("could not parse catalog: %w", err)
+			}
+			return installer.Mirror(c.ctx, c.env, catalog, args[1])
+		},
+	}
+	return cmd
+}
+
 func bootstrapCommand() *cobra.Command {
 	var timeout time.Duration
 	cmd := &cobra.Command{
@@ -69,6 +69,7 @@ func commonPolicyCommands(globalParams *command.GlobalParams) []*cobra.Command {
 	}
 
 	commonPolicyCmd.AddCommand(evalCommands(globalParams)...)
+	commonPolicyCmd.AddCommand(policyTestCommands(globalParams)...)
 	commonPolicyCmd.AddCommand(commonCheckPoliciesCommands(globalParams)...)
 	commonPolicyCmd.AddCommand(commonReloadPoliciesCommands(globalParams)...)
 	commonPolicyCmd.AddCommand(downloadPolicyCommands(globalParams)...)
@@ -542,24 +543,32 @@ func eventDataFromJSON(file string) (eval.Event, error) {
 	event := m.NewDefaultEventWithType(kind)
 	event.Init()
 
-	for k, v := range eventData.Values {
+	if err := setEventValues(event, eventData.Values); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// setEventValues sets the given field values to the event
+func setEventValues(event eval.Event, values map[string]interface{}) error {
+	for k, v := range values {
 		switch v := v.(type) {
 		case json.Number:
 			value, err := v.Int64()
 			if err != nil {
-				return nil, err
+				return err
 			}
 			if err := event.SetFieldValue(k, int(value)); err != nil {
-				return nil, err
+				return err
 			}
 		default:
 			if err := event.SetFieldValue(k, v); err != nil {
-				return nil, err
+				return err
 			}
 		}
 	}
-
-	return event, nil
+	return nil
 }
 
 func evalRule(_ log.Component, _ config.Component, _ secrets.Component, evalArgs *evalCliParams) error {
@@ -201,6 +201,14 @@ func TestEvalCommand(t *testing.T) {
 		func() {})
 }
 
+func TestPolicyTestCommand(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"runtime", "policy", "test", "--tests=tests"},
+		runPolicyTests,
+		func() {})
+}
+
 func TestCheckPoliciesCommand(t *testing.T) {
 	fxutil.TestOneShotSubcommand(t,
 		Commands(&command.GlobalParams{}),
@@ -0,0 +1,461 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux || windows
+
+// Package runtime holds runtime related files
+package runtime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+	"gopkg.in/yaml.v3"
+
+	"github.com/DataDog/datadog-agent/cmd/security-agent/command"
+	"github.com/DataDog/datadog-agent/comp/core"
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	"github.com/DataDog/datadog-agent/comp/core/log"
+	"github.com/DataDog/datadog-agent/comp/core/log/logimpl"
+	"github.com/DataDog/datadog-agent/comp/core/secrets"
+	pkgconfig "github.com/DataDog/datadog-agent/pkg/config"
+	secconfig "github.com/DataDog/datadog-agent/pkg/security/config"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+	"github.com/DataDog/datadog-agent/pkg/security/seclog"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+type policyTestCliParams struct {
+	*command.GlobalParams
+
+	dir   string
+	tests string
+}
+
+func policyTestCommands(globalParams *command.GlobalParams) []*cobra.Command {
+	policyTestArgs := &policyTestCliParams{
+		GlobalParams: globalParams,
+	}
+
+	policyTestCmd := &cobra.Command{
+		Use:   "test",
+		Short: "Replay test cases of events against the policies and report the results",
+		Long: `Replay test cases of events against the policies and report the results.
+
+The test cases are read from a YAML or JSON file, or from all the .yaml, .yml and .json files of a directory:
+
+tests:
+  - name: shadow access
+    events:
+      - type: open
+        values:
+          process.pid: 42
+          open.file.path: /etc/shadow
+    expected:
+      rules: [shadow_access]
+      actions: ["shadow_access:set"]
+      variables:
+        process.shadow_accessed: true
+
+Each test case is replayed against a new rule set. 'rules' lists the IDs of the rules matched by the events,
+in order, 'actions' the actions triggered by these rules and 'variables' the values of variables once all the
+events have been replayed. Scoped variables are read in the scope of the last event. Expectations which are
+not specified aren't checked.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return fxutil.OneShot(runPolicyTests,
+				fx.Supply(policyTestArgs),
+				fx.Supply(core.BundleParams{
+					ConfigParams: config.NewSecurityAgentParams(globalParams.ConfigFilePaths),
+					SecretParams: secrets.NewEnabledParams(),
+					LogParams:    logimpl.ForOneShot(command.LoggerName, "off", false)}),
+				core.Bundle(),
+			)
+		},
+	}
+
+	policyTestCmd.Flags().StringVar(&policyTestArgs.dir, "policies-dir", pkgconfig.DefaultRuntimePoliciesDir, "Path to policies directory")
+	policyTestCmd.Flags().StringVar(&policyTestArgs.tests, "tests", "", "Test cases file or directory")
+	_ = policyTestCmd.MarkFlagRequired("tests")
+
+	return []*cobra.Command{policyTestCmd}
+}
+
+// PolicyTestFile defines the content of a policy test cases file
+type PolicyTestFile struct {
+	Tests []*PolicyTestCase `yaml:"tests"`
+}
+
+// PolicyTestCase defines a sequence of events and the expected results of their evaluation
+type PolicyTestCase struct {
+	Name     string                `yaml:"name"`
+	Events   []PolicyTestEvent     `yaml:"events"`
+	Expected PolicyTestExpectation `yaml:"expected"`
+}
+
+// PolicyTestEvent defines an event of a test case
+type PolicyTestEvent struct {
+	Type   eval.EventType         `yaml:"type"`
+	Values map[string]interface{} `yaml:"values"`
+}
+
+// PolicyTestExpectation defines the expected results of a test case
+type PolicyTestExpectation struct {
+	Rules     []string               `yaml:"rules"`
+	Actions   []string               `yaml:"actions"`
+	Variables map[string]interface{} `yaml:"variables"`
+}
+
+// policyTestResult holds the results of the evaluation of the events of a test case
+type policyTestResult struct {
+	rules     []string
+	actions   []string
+	variables map[string]interface{}
+}
+
+// policyTestListener records the rules matched by the events of a test case
+type policyTestListener struct {
+	result *policyTestResult
+}
+
+func (l *policyTestListener) RuleMatch(rule *rules.Rule, event eval.Event) bool {
+	l.result.rules = append(l.result.rules, rule.ID)
+
+	ctx := eval.NewContext(event)
+	for _, action := range rule.Definition.Actions {
+		if !action.IsAccepted(ctx) {
+			continue
+		}
+
+		var name string
+		switch {
+		case action.Set != nil:
+			name = "set"
+		case action.Kill != nil:
+			name = rules.KillAction
+		case action.CoreDump != nil:
+			name = "coredump"
+		case action.Hash != nil:
+			name = "hash"
+		default:
+			continue
+		}
+		l.result.actions = append(l.result.actions, rule.ID+":"+name)
+	}
+
+	return true
+}
+
+func (l *policyTestListener) EventDiscarderFound(_ *rules.RuleSet, _ eval.Event, _ eval.Field, _ eval.EventType) {
+}
+
+// policyTestPipeline builds the events of a test case. As the process and container resolvers of the
+// probe, it shares the process cache entry of a pid and the context of a container between the events
+// so that scoped variables can be used.
+type policyTestPipeline struct {
+	processes  map[int]*model.ProcessCacheEntry
+	containers map[string]*model.ContainerContext
+}
+
+func newPolicyTestPipeline() *policyTestPipeline {
+	return &policyTestPipeline{
+		processes:  make(map[int]*model.ProcessCacheEntry),
+		containers: make(map[string]*model.ContainerContext),
+	}
+}
+
+func (p *policyTestPipeline) newEvent(data PolicyTestEvent) (eval.Event, error) {
+	kind := secconfig.ParseEvalEventType(data.Type)
+	if kind == model.UnknownEventType {
+		return nil, fmt.Errorf("unknown event type `%s`", data.Type)
+	}
+
+	m := &model.Model{}
+	event := m.NewDefaultEventWithType(kind).(*model.Event)
+	event.Init()
+
+	pid, _ := data.Values["process.pid"].(int)
+	entry := p.processes[pid]
+	if entry == nil {
+		entry = &model.ProcessCacheEntry{}
+		p.processes[pid] = entry
+	}
+	event.ProcessCacheEntry = entry
+	event.ProcessContext = &entry.ProcessContext
+
+	if containerID, _ := data.Values["container.id"].(string); containerID != "" {
+		container := p.containers[containerID]
+		if container == nil {
+			container = &model.ContainerContext{}
+			p.containers[containerID] = container
+		}
+		event.ContainerContext = container
+	}
+
+	if err := setEventValues(event, data.Values); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+func runPolicyTests(_ log.Component, _ config.Component, _ secrets.Component, args *policyTestCliParams) error {
+	return runPolicyTestsLocal(args, os.Stdout)
+}
+
+func runPolicyTestsLocal(args *policyTestCliParams, writer io.Writer) error {
+	files, err := policyTestFiles(args.tests)
+	if err != nil {
+		return err
+	}
+
+	// check the policies before running the tests
+	if _, _, err := newPolicyTestRuleSet(args.dir); err != nil {
+		return err
+	}
+
+	var passed, failed int
+	for _, file := range files {
+		testCases, err := loadPolicyTestFile(file)
+		if err != nil {
+			return err
+		}
+
+		for _, testCase := range testCases {
+			diffs, err := runPolicyTest(args.dir, testCase)
+			if err != nil {
+				return fmt.Errorf("test `%s` of %s: %w", testCase.Name, file, err)
+			}
+
+			if len(diffs) == 0 {
+				passed++
+				fmt.Fprintf(writer, "PASS  %s: %s\n", file, testCase.Name)
+				continue
+			}
+
+			failed++
+			fmt.Fprintf(writer, "FAIL  %s: %s\n", file, testCase.Name)
+			for _, diff := range diffs {
+				fmt.Fprintf(writer, "      %s\n", strings.ReplaceAll(strings.TrimRight(diff, "\n"), "\n", "\n      "))
+			}
+		}
+	}
+
+	if passed+failed == 0 {
+		return errors.New("no test cases found")
+	}
+
+	fmt.Fprintf(writer, "\n%d passed, %d failed\n", passed, failed)
+
+	if failed > 0 {
+		return fmt.Errorf("%d policy test(s) failed", failed)
+	}
+	return nil
+}
+
+// policyTestFiles returns the test cases files of the given path
+func policyTestFiles(path string) ([]string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !fi.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		switch filepath.Ext(entry.Name()) {
+		case ".yaml", ".yml", ".json":
+			if !entry.IsDir() {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no test cases file found in %s", path)
+	}
+
+	return files, nil
+}
+
+func loadPolicyTestFile(file string) ([]*PolicyTestCase, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// JSON test cases are decoded as YAML as well
+	var content PolicyTestFile
+	if err := yaml.NewDecoder(f).Decode(&content); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", file, err)
+	}
+
+	for i, testCase := range content.Tests {
+		if testCase.Name == "" {
+			testCase.Name = fmt.Sprintf("#%d", i+1)
+		}
+		if len(testCase.Events) == 0 {
+			return nil, fmt.Errorf("test `%s` of %s has no events", testCase.Name, file)
+		}
+	}
+
+	return content.Tests, nil
+}
+
+// newPolicyTestRuleSet returns a new rule set with the policies of the given directory
+func newPolicyTestRuleSet(dir string) (*rules.RuleSet, *eval.Opts, error) {
+	// enabled all the rules
+	enabled := map[eval.EventType]bool{"*": true}
+
+	ruleOpts := rules.NewRuleOpts(enabled)
+	evalOpts := newEvalOpts(false)
+	ruleOpts.WithLogger(seclog.DefaultLogger)
+
+	agentVersionFilter, err := newAgentVersionFilter()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create agent version filter: %w", err)
+	}
+
+	loaderOpts := rules.PolicyLoaderOpts{
+		MacroFilters: []rules.MacroFilter{
+			agentVersionFilter,
+		},
+		RuleFilters: []rules.RuleFilter{
+			agentVersionFilter,
+		},
+	}
+
+	provider, err := rules.NewPoliciesDirProvider(dir, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	loader := rules.NewPolicyLoader(provider)
+
+	ruleSet := rules.NewRuleSet(&model.Model{}, newFakeEvent, ruleOpts, evalOpts)
+	if err := ruleSet.LoadPolicies(loader, loaderOpts); err.ErrorOrNil() != nil {
+		return nil, nil, err
+	}
+
+	return ruleSet, evalOpts, nil
+}
+
+// runPolicyTest replays the events of a test case and returns the differences with the expected results
+func runPolicyTest(dir string, testCase *PolicyTestCase) ([]string, error) {
+	ruleSet, evalOpts, err := newPolicyTestRuleSet(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &policyTestResult{}
+	ruleSet.AddListener(&policyTestListener{result: result})
+
+	pipeline := newPolicyTestPipeline()
+
+	var lastEvent eval.Event
+	for i, data := range testCase.Events {
+		event, err := pipeline.newEvent(data)
+		if err != nil {
+			return nil, fmt.Errorf("event #%d: %w", i+1, err)
+		}
+		ruleSet.Evaluate(event)
+		lastEvent = event
+	}
+
+	expected := testCase.Expected
+
+	if len(expected.Variables) > 0 {
+		result.variables = make(map[string]interface{}, len(expected.Variables))
+
+		ctx := eval.NewContext(lastEvent)
+		for name := range expected.Variables {
+			result.variables[name] = policyTestVariableValue(evalOpts.VariableStore.Get(name), ctx)
+		}
+	}
+
+	var diffs []string
+	if expected.Rules != nil {
+		if diff := cmp.Diff(expected.Rules, result.rules, emptyIsNil); diff != "" {
+			diffs = append(diffs, "rules (-expected +actual):\n"+diff)
+		}
+	}
+	if expected.Actions != nil {
+		if diff := cmp.Diff(expected.Actions, result.actions, emptyIsNil); diff != "" {
+			diffs = append(diffs, "actions (-expected +actual):\n"+diff)
+		}
+	}
+	if len(expected.Variables) > 0 {
+		names := make([]string, 0, len(expected.Variables))
+		for name := range expected.Variables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if diff := cmp.Diff(normalizeValue(expected.Variables[name]), normalizeValue(result.variables[name])); diff != "" {
+				diffs = append(diffs, fmt.Sprintf("variable %s (-expected +actual):\n%s", name, diff))
+			}
+		}
+	}
+
+	return diffs, nil
+}
+
+// emptyIsNil compares empty and nil slices as equal, so that `rules: []` expects no match
+var emptyIsNil = cmp.FilterValues(func(x, y []string) bool {
+	return len(x) == 0 && len(y) == 0
+}, cmp.Comparer(func(_, _ []string) bool {
+	return true
+}))
+
+// policyTestVariableValue returns the value of a variable, nil if the variable isn't defined
+func policyTestVariableValue(variable eval.VariableValue, ctx *eval.Context) interface{} {
+	if variable == nil {
+		return nil
+	}
+
+	if evaluator, ok := variable.GetEvaluator().(eval.Evaluator); ok {
+		return evaluator.Eval(ctx)
+	}
+	return nil
+}
+
+// normalizeValue converts the expected and actual values of variables to the same types
+func normalizeValue(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return value
+	}
+
+	// an empty array is the zero value of array variables
+	if array, ok := normalized.([]interface{}); ok && len(array) == 0 {
+		return nil
+	}
+	return normalized
+}
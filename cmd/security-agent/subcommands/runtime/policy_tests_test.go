@@ -0,0 +1,131 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package runtime
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicy = `
+macros:
+  - id: shadow_files
+    values: ["/etc/shadow", "/etc/gshadow"]
+rules:
+  - id: shadow_access
+    expression: open.file.path in shadow_files
+    actions:
+      - set:
+          name: shadow_accessed
+          value: true
+          scope: process
+      - set:
+          name: shadow_files
+          field: open.file.path
+          append: true
+  - id: shadow_then_exec
+    expression: exec.file.path == "/usr/bin/curl" && ${process.shadow_accessed}
+    actions:
+      - kill:
+          signal: SIGKILL
+`
+
+const testCases = `
+tests:
+  - name: shadow access then curl
+    events:
+      - type: open
+        values:
+          process.pid: 42
+          open.file.path: /etc/shadow
+      - type: exec
+        values:
+          process.pid: 42
+          exec.file.path: /usr/bin/curl
+    expected:
+      rules: [shadow_access, shadow_then_exec]
+      actions: ["shadow_access:set", "shadow_access:set", "shadow_then_exec:kill"]
+      variables:
+        process.shadow_accessed: true
+        shadow_files: ["/etc/shadow"]
+  - name: curl of another process
+    events:
+      - type: open
+        values:
+          process.pid: 42
+          open.file.path: /etc/gshadow
+      - type: exec
+        values:
+          process.pid: 43
+          exec.file.path: /usr/bin/curl
+    expected:
+      rules: [shadow_access]
+      variables:
+        process.shadow_accessed: false
+`
+
+const failingTestCases = `{
+  "tests": [{
+    "name": "wrong expectation",
+    "events": [{"type": "open", "values": {"process.pid": 42, "open.file.path": "/etc/passwd"}}],
+    "expected": {"rules": ["shadow_access"]}
+  }]
+}`
+
+func writePolicyTestFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestRunPolicyTests(t *testing.T) {
+	policiesDir := writePolicyTestFiles(t, map[string]string{"test.policy": testPolicy})
+
+	t.Run("pass", func(t *testing.T) {
+		testsDir := writePolicyTestFiles(t, map[string]string{"shadow.yaml": testCases})
+
+		var output bytes.Buffer
+		err := runPolicyTestsLocal(&policyTestCliParams{dir: policiesDir, tests: testsDir}, &output)
+		require.NoError(t, err, output.String())
+		assert.Contains(t, output.String(), "PASS  "+filepath.Join(testsDir, "shadow.yaml")+": shadow access then curl")
+		assert.Contains(t, output.String(), "2 passed, 0 failed")
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		testsDir := writePolicyTestFiles(t, map[string]string{"shadow.yaml": testCases, "failing.json": failingTestCases})
+
+		var output bytes.Buffer
+		err := runPolicyTestsLocal(&policyTestCliParams{dir: policiesDir, tests: testsDir}, &output)
+		assert.EqualError(t, err, "1 policy test(s) failed")
+		assert.Contains(t, output.String(), "FAIL  "+filepath.Join(testsDir, "failing.json")+": wrong expectation")
+		assert.Contains(t, output.String(), "rules (-expected +actual)")
+		assert.Contains(t, output.String(), "2 passed, 1 failed")
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		policiesDir := writePolicyTestFiles(t, map[string]string{"test.policy": "rules:\n  - id: invalid\n    expression: open.file.path ==\n"})
+		testsDir := writePolicyTestFiles(t, map[string]string{"shadow.yaml": testCases})
+
+		err := runPolicyTestsLocal(&policyTestCliParams{dir: policiesDir, tests: testsDir}, &bytes.Buffer{})
+		assert.ErrorContains(t, err, "rule `invalid` error")
+	})
+
+	t.Run("unknown event type", func(t *testing.T) {
+		testsDir := writePolicyTestFiles(t, map[string]string{"unknown.yaml": "tests:\n  - name: unknown\n    events:\n      - type: foo\n"})
+
+		err := runPolicyTestsLocal(&policyTestCliParams{dir: policiesDir, tests: testsDir}, &bytes.Buffer{})
+		assert.ErrorContains(t, err, "unknown event type `foo`")
+	})
+}
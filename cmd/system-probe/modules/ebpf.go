@@ -10,6 +10,8 @@ package modules
 import (
 	"fmt"
 	"net/http"
+	"strconv"
+	"sync"
 	"time"
 
 	"go.uber.org/atomic"
@@ -21,6 +23,7 @@ import (
 	"github.com/DataDog/datadog-agent/comp/core/telemetry"
 	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/ebpf/probe/ebpfcheck"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/ebpf/probe/ebpfcheck/model"
 	"github.com/DataDog/datadog-agent/pkg/ebpf"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/optional"
@@ -51,19 +54,78 @@ var _ module.Module = &ebpfModule{}
 type ebpfModule struct {
 	*ebpfcheck.Probe
 	lastCheck *atomic.Int64
+	// probeLock serializes the check and the inspection requests, which share the entry count buffers
+	probeLock sync.Mutex
 }
 
 func (o *ebpfModule) Register(httpMux *module.Router) error {
 	// Limit concurrency to one as the probe check is not thread safe (mainly in the entry count buffers)
 	httpMux.HandleFunc("/check", utils.WithConcurrencyLimit(1, func(w http.ResponseWriter, req *http.Request) {
+		o.probeLock.Lock()
+		defer o.probeLock.Unlock()
 		o.lastCheck.Store(time.Now().Unix())
 		stats := o.Probe.GetAndFlush()
 		utils.WriteAsJSON(w, stats)
 	}))
 
+	httpMux.HandleFunc("/programs", utils.WithConcurrencyLimit(1, func(w http.ResponseWriter, req *http.Request) {
+		o.probeLock.Lock()
+		defer o.probeLock.Unlock()
+		progs, err := o.Probe.ListPrograms()
+		if err != nil {
+			o.handleError(w, req, http.StatusInternalServerError, err)
+			return
+		}
+		utils.WriteAsJSON(w, progs)
+	}))
+
+	httpMux.HandleFunc("/maps", utils.WithConcurrencyLimit(1, func(w http.ResponseWriter, req *http.Request) {
+		o.probeLock.Lock()
+		defer o.probeLock.Unlock()
+		maps, err := o.Probe.ListMaps()
+		if err != nil {
+			o.handleError(w, req, http.StatusInternalServerError, err)
+			return
+		}
+		utils.WriteAsJSON(w, maps)
+	}))
+
+	httpMux.HandleFunc("/maps/dump", utils.WithConcurrencyLimit(utils.DefaultMaxConcurrentRequests, func(w http.ResponseWriter, req *http.Request) {
+		qs := req.URL.Query()
+		dumpReq := model.EBPFMapDumpRequest{
+			Map:    qs.Get("map"),
+			Cursor: qs.Get("cursor"),
+			Filter: qs.Get("filter"),
+		}
+		if dumpReq.Map == "" {
+			o.handleError(w, req, http.StatusBadRequest, fmt.Errorf("map query parameter is required"))
+			return
+		}
+		if limit := qs.Get("limit"); limit != "" {
+			var err error
+			if dumpReq.Limit, err = strconv.Atoi(limit); err != nil {
+				o.handleError(w, req, http.StatusBadRequest, fmt.Errorf("limit query parameter is not an integer: %w", err))
+				return
+			}
+		}
+		dump, err := o.Probe.DumpMap(dumpReq)
+		if err != nil {
+			o.handleError(w, req, http.StatusBadRequest, err)
+			return
+		}
+		utils.WriteAsJSON(w, dump)
+	}))
+
 	return nil
 }
 
+func (o *ebpfModule) handleError(w http.ResponseWriter, req *http.Request, status int, err error) {
+	log.Errorf("module ebpf: failed to handle %s request: %s", req.URL.Path, err)
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(status)
+	w.Write([]byte(err.Error())) //nolint:errcheck
+}
+
 func (o *ebpfModule) GetStats() map[string]interface{} {
 	return map[string]interface{}{
 		"last_check": o.lastCheck.Load(),
@@ -28,9 +28,12 @@ import (
 	coreconfig "github.com/DataDog/datadog-agent/pkg/config/setup"
 	"github.com/DataDog/datadog-agent/pkg/network"
 	networkconfig "github.com/DataDog/datadog-agent/pkg/network/config"
+	dnsdebugging "github.com/DataDog/datadog-agent/pkg/network/dns/debugging"
 	"github.com/DataDog/datadog-agent/pkg/network/encoding/marshal"
+	cassandradebugging "github.com/DataDog/datadog-agent/pkg/network/protocols/cassandra/debugging"
 	httpdebugging "github.com/DataDog/datadog-agent/pkg/network/protocols/http/debugging"
 	kafkadebugging "github.com/DataDog/datadog-agent/pkg/network/protocols/kafka/debugging"
+	memcacheddebugging "github.com/DataDog/datadog-agent/pkg/network/protocols/memcached/debugging"
 	postgresdebugging "github.com/DataDog/datadog-agent/pkg/network/protocols/postgres/debugging"
 	"github.com/DataDog/datadog-agent/pkg/network/protocols/telemetry"
 	"github.com/DataDog/datadog-agent/pkg/network/tracer"
@@ -194,6 +197,54 @@ func (nt *networkTracer) Register(httpMux *module.Router) error {
 		utils.WriteAsJSON(w, postgresdebugging.Postgres(cs.Postgres))
 	})
 
+	httpMux.HandleFunc("/debug/memcached_monitoring", func(w http.ResponseWriter, req *http.Request) {
+		if !coreconfig.SystemProbe.GetBool("service_monitoring_config.enable_memcached_monitoring") {
+			writeDisabledProtocolMessage("memcached", w)
+			return
+		}
+		id := getClientID(req)
+		cs, err := nt.tracer.GetActiveConnections(id)
+		if err != nil {
+			log.Errorf("unable to retrieve connections: %s", err)
+			w.WriteHeader(500)
+			return
+		}
+
+		utils.WriteAsJSON(w, memcacheddebugging.Memcached(cs.Memcached))
+	})
+
+	httpMux.HandleFunc("/debug/cassandra_monitoring", func(w http.ResponseWriter, req *http.Request) {
+		if !coreconfig.SystemProbe.GetBool("service_monitoring_config.enable_cassandra_monitoring") {
+			writeDisabledProtocolMessage("cassandra", w)
+			return
+		}
+		id := getClientID(req)
+		cs, err := nt.tracer.GetActiveConnections(id)
+		if err != nil {
+			log.Errorf("unable to retrieve connections: %s", err)
+			w.WriteHeader(500)
+			return
+		}
+
+		utils.WriteAsJSON(w, cassandradebugging.Cassandra(cs.Cassandra))
+	})
+
+	httpMux.HandleFunc("/debug/dns_monitoring", func(w http.ResponseWriter, req *http.Request) {
+		if !coreconfig.SystemProbe.GetBool("system_probe_config.collect_dns_stats") {
+			writeDisabledProtocolMessage("dns", w)
+			return
+		}
+		id := getClientID(req)
+		cs, err := nt.tracer.GetActiveConnections(id)
+		if err != nil {
+			log.Errorf("unable to retrieve connections: %s", err)
+			w.WriteHeader(500)
+			return
+		}
+
+		utils.WriteAsJSON(w, dnsdebugging.DNS(cs.Conns))
+	})
+
 	httpMux.HandleFunc("/debug/http2_monitoring", func(w http.ResponseWriter, req *http.Request) {
 		if !coreconfig.SystemProbe.GetBool("service_monitoring_config.enable_http2_monitoring") {
 			writeDisabledProtocolMessage("http2", w)
@@ -0,0 +1,213 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package ebpf is the ebpf system-probe subcommand
+package ebpf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+
+	"github.com/DataDog/datadog-agent/cmd/system-probe/api/client"
+	"github.com/DataDog/datadog-agent/cmd/system-probe/command"
+	"github.com/DataDog/datadog-agent/comp/core"
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	"github.com/DataDog/datadog-agent/comp/core/log/logimpl"
+	"github.com/DataDog/datadog-agent/comp/core/sysprobeconfig"
+	"github.com/DataDog/datadog-agent/comp/core/sysprobeconfig/sysprobeconfigimpl"
+	"github.com/DataDog/datadog-agent/pkg/api/util"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/ebpf/probe/ebpfcheck/model"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+// cliParams are the command-line arguments for this subcommand
+type cliParams struct {
+	*command.GlobalParams
+
+	// args contains the positional command-line arguments
+	args []string
+
+	json   bool
+	limit  int
+	cursor string
+	filter string
+}
+
+// Commands returns a slice of subcommands for the 'system-probe' command.
+func Commands(globalParams *command.GlobalParams) []*cobra.Command {
+	cliParams := &cliParams{
+		GlobalParams: globalParams,
+	}
+	oneShot := func(fct interface{}) func(cmd *cobra.Command, args []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			cliParams.args = args
+			return fxutil.OneShot(fct,
+				fx.Supply(cliParams),
+				fx.Supply(core.BundleParams{
+					ConfigParams:         config.NewAgentParams("", config.WithConfigMissingOK(true)),
+					SysprobeConfigParams: sysprobeconfigimpl.NewParams(sysprobeconfigimpl.WithSysProbeConfFilePath(globalParams.ConfFilePath)),
+					LogParams:            logimpl.ForOneShot("SYS-PROBE", "off", false),
+				}),
+				// no need to provide sysprobe logger since ForOneShot ignores config values
+				core.Bundle(),
+			)
+		}
+	}
+
+	ebpfCommand := &cobra.Command{
+		Use:   "ebpf",
+		Short: "Inspect the eBPF programs and maps loaded by a running system-probe",
+		Long:  `Inspect the eBPF programs and maps loaded by a running system-probe. The ebpf_check module must be enabled.`,
+	}
+	ebpfCommand.PersistentFlags().BoolVar(&cliParams.json, "json", false, "print the raw JSON output")
+
+	programsCommand := &cobra.Command{
+		Use:   "programs",
+		Short: "List the eBPF programs owned by system-probe, with their attach points and runtime stats",
+		Args:  cobra.NoArgs,
+		RunE:  oneShot(listPrograms),
+	}
+
+	mapsCommand := &cobra.Command{
+		Use:   "maps",
+		Short: "List the eBPF maps owned by system-probe, with their fill ratio and memory usage",
+		Args:  cobra.NoArgs,
+		RunE:  oneShot(listMaps),
+	}
+
+	dumpCommand := &cobra.Command{
+		Use:   "dump <map name or ID>",
+		Short: "Dump the entries of an eBPF map owned by system-probe",
+		Long: `Dump the entries of an eBPF map owned by system-probe. Keys and values are decoded with the
+agent types when the map has a registered decoder, and printed as hex otherwise.
+Use the printed cursor to get the next page of entries.`,
+		Args: cobra.ExactArgs(1),
+		RunE: oneShot(dumpMap),
+	}
+	dumpCommand.Flags().IntVar(&cliParams.limit, "limit", 100, "maximum number of entries to print")
+	dumpCommand.Flags().StringVar(&cliParams.cursor, "cursor", "", "cursor returned by a previous dump, to print the next entries")
+	dumpCommand.Flags().StringVar(&cliParams.filter, "filter", "", "only print the entries whose decoded key or value contains this string")
+
+	ebpfCommand.AddCommand(programsCommand, mapsCommand, dumpCommand)
+	return []*cobra.Command{ebpfCommand}
+}
+
+func getEBPF(sysprobeconfig sysprobeconfig.Component, path string, out interface{}) ([]byte, error) {
+	cfg := sysprobeconfig.SysProbeObject()
+	client := client.Get(cfg.SocketAddress)
+
+	r, err := util.DoGet(client, "http://localhost/ebpf"+path, util.CloseConnection)
+	if err != nil {
+		if len(r) > 0 {
+			return nil, fmt.Errorf("%s", strings.TrimSpace(string(r)))
+		}
+		return nil, fmt.Errorf("Could not reach system-probe: %s\nMake sure system-probe is running with the ebpf_check module enabled before running this command", err)
+	}
+	if err := json.Unmarshal(r, out); err != nil {
+		return nil, fmt.Errorf("unable to parse system-probe response: %w", err)
+	}
+	return r, nil
+}
+
+func listPrograms(sysprobeconfig sysprobeconfig.Component, cliParams *cliParams) error {
+	var progs []model.EBPFProgramInfo
+	raw, err := getEBPF(sysprobeconfig, "/programs", &progs)
+	if err != nil {
+		return err
+	}
+	if cliParams.json {
+		fmt.Println(string(raw))
+		return nil
+	}
+	printPrograms(os.Stdout, progs)
+	return nil
+}
+
+func printPrograms(w io.Writer, progs []model.EBPFProgramInfo) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tMODULE\tTYPE\tATTACH POINTS\tRUN COUNT\tRUNTIME\tMEMLOCK")
+	for _, p := range progs {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%d\t%s\t%d\n", p.ID, p.Name, p.Module, p.Type, strings.Join(p.AttachPoints, ","), p.RunCount, p.Runtime, p.Memlock)
+	}
+	tw.Flush()
+}
+
+func listMaps(sysprobeconfig sysprobeconfig.Component, cliParams *cliParams) error {
+	var maps []model.EBPFMapInfo
+	raw, err := getEBPF(sysprobeconfig, "/maps", &maps)
+	if err != nil {
+		return err
+	}
+	if cliParams.json {
+		fmt.Println(string(raw))
+		return nil
+	}
+	printMaps(os.Stdout, maps)
+	return nil
+}
+
+func printMaps(w io.Writer, maps []model.EBPFMapInfo) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tMODULE\tTYPE\tENTRIES\tMAX ENTRIES\tFILL\tMEMLOCK")
+	for _, m := range maps {
+		entries, fill := "-", "-"
+		if m.FillRatio >= 0 {
+			entries = strconv.FormatInt(m.Entries, 10)
+			fill = fmt.Sprintf("%.1f%%", m.FillRatio*100)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%d\t%s\t%d\n", m.ID, m.Name, m.Module, m.Type, entries, m.MaxEntries, fill, m.Memlock)
+	}
+	tw.Flush()
+}
+
+func dumpMap(sysprobeconfig sysprobeconfig.Component, cliParams *cliParams) error {
+	qs := url.Values{}
+	qs.Set("map", cliParams.args[0])
+	qs.Set("limit", strconv.Itoa(cliParams.limit))
+	if cliParams.cursor != "" {
+		qs.Set("cursor", cliParams.cursor)
+	}
+	if cliParams.filter != "" {
+		qs.Set("filter", cliParams.filter)
+	}
+
+	var dump model.EBPFMapDump
+	raw, err := getEBPF(sysprobeconfig, "/maps/dump?"+qs.Encode(), &dump)
+	if err != nil {
+		return err
+	}
+	if cliParams.json {
+		fmt.Println(string(raw))
+		return nil
+	}
+	printDump(os.Stdout, dump)
+	return nil
+}
+
+func printDump(w io.Writer, dump model.EBPFMapDump) {
+	fmt.Fprintf(w, "map %s (id %d, module %s)\n", dump.Name, dump.ID, dump.Module)
+	for _, e := range dump.Entries {
+		fmt.Fprintf(w, "key: %s\n", e.Key)
+		if len(e.Value) == 1 {
+			fmt.Fprintf(w, "  value: %s\n", e.Value[0])
+			continue
+		}
+		for cpu, v := range e.Value {
+			fmt.Fprintf(w, "  value[cpu %d]: %s\n", cpu, v)
+		}
+	}
+	if dump.NextCursor != "" {
+		fmt.Fprintf(w, "next page: --cursor %s\n", dump.NextCursor)
+	}
+}
@@ -0,0 +1,69 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package ebpf
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/cmd/system-probe/command"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/ebpf/probe/ebpfcheck/model"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+func TestProgramsCommand(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"ebpf", "programs"},
+		listPrograms,
+		func() {})
+}
+
+func TestMapsCommand(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"ebpf", "maps", "--json"},
+		listMaps,
+		func(cliParams *cliParams) {
+			assert.True(t, cliParams.json)
+		})
+}
+
+func TestDumpCommand(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"ebpf", "dump", "conn_stats", "--limit", "10", "--filter", "10.0.0.1"},
+		dumpMap,
+		func(cliParams *cliParams) {
+			assert.Equal(t, []string{"conn_stats"}, cliParams.args)
+			assert.Equal(t, 10, cliParams.limit)
+			assert.Equal(t, "10.0.0.1", cliParams.filter)
+		})
+}
+
+func TestPrintDump(t *testing.T) {
+	var b bytes.Buffer
+	printDump(&b, model.EBPFMapDump{
+		ID:     12,
+		Name:   "conn_stats",
+		Module: "npm_tracer",
+		Entries: []model.EBPFMapEntry{
+			{RawKey: "01", Key: "k1", Value: []string{"v1"}},
+			{RawKey: "02", Key: "k2", Value: []string{"v2", "v3"}},
+		},
+		NextCursor: "02",
+	})
+	assert.Equal(t, `map conn_stats (id 12, module npm_tracer)
+key: k1
+  value: v1
+key: k2
+  value[cpu 0]: v2
+  value[cpu 1]: v3
+next page: --cursor 02
+`, b.String())
+}
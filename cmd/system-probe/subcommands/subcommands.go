@@ -10,6 +10,7 @@ import (
 	"github.com/DataDog/datadog-agent/cmd/system-probe/command"
 	cmdconfig "github.com/DataDog/datadog-agent/cmd/system-probe/subcommands/config"
 	cmddebug "github.com/DataDog/datadog-agent/cmd/system-probe/subcommands/debug"
+	cmdebpf "github.com/DataDog/datadog-agent/cmd/system-probe/subcommands/ebpf"
 	cmdmodrestart "github.com/DataDog/datadog-agent/cmd/system-probe/subcommands/modrestart"
 	cmdrun "github.com/DataDog/datadog-agent/cmd/system-probe/subcommands/run"
 	cmdruntime "github.com/DataDog/datadog-agent/cmd/system-probe/subcommands/runtime"
@@ -26,5 +27,6 @@ func SysprobeSubcommands() []command.SubcommandFactory {
 		cmddebug.Commands,
 		cmdconfig.Commands,
 		cmdruntime.Commands,
+		cmdebpf.Commands,
 	}
 }
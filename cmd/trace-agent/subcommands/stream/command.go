@@ -0,0 +1,183 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package stream implements 'trace-agent stream'.
+package stream
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+
+	"github.com/DataDog/datadog-agent/cmd/trace-agent/subcommands"
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	"github.com/DataDog/datadog-agent/comp/core/secrets"
+	"github.com/DataDog/datadog-agent/pkg/api/util"
+	"github.com/DataDog/datadog-agent/pkg/trace/api"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+)
+
+// cliParams are the command-line arguments for this subcommand
+type cliParams struct {
+	service  string
+	resource string
+	traceID  uint64
+	// duration is the duration of the stream, 0 meaning until interrupted.
+	duration time.Duration
+	// json prints the chunks as received from the trace-agent.
+	json bool
+}
+
+// MakeCommand returns the stream subcommand for the 'trace-agent' command.
+func MakeCommand(globalParamsGetter func() *subcommands.GlobalParams) *cobra.Command {
+	params := &cliParams{}
+	cmd := &cobra.Command{
+		Use:   "stream",
+		Short: "Stream the traces being processed by a running trace-agent",
+		Long: `Stream the trace chunks received by a running trace-agent, along with their
+sampling decision, or the reason they were dropped before sampling, and their
+resources before and after obfuscation.`,
+		RunE: func(*cobra.Command, []string) error {
+			return fxutil.OneShot(streamTraces,
+				fx.Supply(params),
+				fx.Supply(config.NewAgentParams(globalParamsGetter().ConfPath)),
+				fx.Supply(optional.NewNoneOption[secrets.Component]()),
+				config.Module(),
+			)
+		},
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&params.service, "service", "", "Filter by service")
+	cmd.Flags().StringVar(&params.resource, "resource", "", "Filter by resource (substring, before or after obfuscation)")
+	cmd.Flags().Uint64Var(&params.traceID, "trace-id", 0, "Filter by trace ID")
+	cmd.Flags().DurationVarP(&params.duration, "duration", "d", 0, "Duration of the stream (default: 0, infinite)")
+	cmd.Flags().BoolVar(&params.json, "json", false, "Print the chunks as JSON")
+	cmd.PreRunE = func(*cobra.Command, []string) error {
+		if params.duration < 0 {
+			return fmt.Errorf("duration must be a positive value")
+		}
+		return nil
+	}
+	return cmd
+}
+
+func streamTraces(config config.Component, params *cliParams) error {
+	if err := util.SetAuthToken(config); err != nil {
+		return err
+	}
+	port := config.GetInt("apm_config.debug.port")
+	if port <= 0 {
+		return fmt.Errorf("invalid apm_config.debug.port -- %d", port)
+	}
+
+	ctx := context.Background()
+	if params.duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, params.duration)
+		defer cancel()
+	}
+	err := stream(ctx, streamURL(port, params), util.GetAuthToken(), func(line []byte) error {
+		return printChunk(os.Stdout, line, params.json)
+	})
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil
+	}
+	if err != nil {
+		fmt.Printf("Could not reach trace-agent: %v\nMake sure the trace-agent is running and apm_config.debug.port is enabled.\n", err)
+	}
+	return err
+}
+
+// streamURL returns the URL of the trace stream endpoint with the requested filters.
+func streamURL(port int, params *cliParams) string {
+	q := url.Values{}
+	if params.service != "" {
+		q.Set("service", params.service)
+	}
+	if params.resource != "" {
+		q.Set("resource", params.resource)
+	}
+	if params.traceID != 0 {
+		q.Set("trace_id", strconv.FormatUint(params.traceID, 10))
+	}
+	u := url.URL{
+		Scheme:   "http",
+		Host:     fmt.Sprintf("127.0.0.1:%d", port),
+		Path:     "/debug/traces",
+		RawQuery: q.Encode(),
+	}
+	return u.String()
+}
+
+// stream reads the trace stream at url and calls onLine for each received chunk.
+func stream(ctx context.Context, url, token string, onLine func([]byte) error) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("trace-agent responded with %q: %s", resp.Status, body)
+	}
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if err := onLine(scanner.Bytes()); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	return nil
+}
+
+// printChunk writes the chunk encoded in line to w, as is or in a human readable format.
+func printChunk(w io.Writer, line []byte, raw bool) error {
+	if raw {
+		_, err := fmt.Fprintf(w, "%s\n", line)
+		return err
+	}
+	var c api.StreamedChunk
+	if err := json.Unmarshal(line, &c); err != nil {
+		return fmt.Errorf("invalid chunk received: %w", err)
+	}
+	decision := "dropped by " + c.Sampler + " sampler"
+	if c.Kept {
+		decision = "kept by " + c.Sampler + " sampler"
+	} else if c.DropReason != "" {
+		decision = "dropped before sampling (" + c.DropReason + ")"
+	}
+	fmt.Fprintf(w, "%s trace_id=%d service=%q resource=%q env=%q priority=%d %s, %d spans\n",
+		c.Time.Format(time.RFC3339), c.TraceID, c.Service, c.Resource, c.Env, c.Priority, decision, len(c.Spans))
+	for _, s := range c.Spans {
+		fmt.Fprintf(w, "  span_id=%d parent_id=%d service=%q name=%q resource=%q\n", s.SpanID, s.ParentID, s.Service, s.Name, s.Resource)
+		if s.RawResource != "" {
+			fmt.Fprintf(w, "    before obfuscation: %q\n", s.RawResource)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package stream
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/cmd/trace-agent/subcommands"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+func TestStreamCommand(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		[]*cobra.Command{MakeCommand(func() *subcommands.GlobalParams {
+			return &subcommands.GlobalParams{}
+		})},
+		[]string{"stream", "--service", "web", "--trace-id", "42", "-d", "10s"},
+		streamTraces,
+		func(params *cliParams) {
+			assert.Equal(t, "web", params.service)
+			assert.EqualValues(t, 42, params.traceID)
+			assert.Equal(t, 10*time.Second, params.duration)
+		})
+}
+
+func TestStreamURL(t *testing.T) {
+	assert.Equal(t, "http://127.0.0.1:5012/debug/traces", streamURL(5012, &cliParams{}))
+	assert.Equal(t,
+		"http://127.0.0.1:5012/debug/traces?resource=GET+%2Fusers&service=web&trace_id=42",
+		streamURL(5012, &cliParams{service: "web", resource: "GET /users", traceID: 42}))
+}
+
+func TestPrintChunk(t *testing.T) {
+	line := []byte(`{"time":"2024-01-02T03:04:05Z","trace_id":42,"service":"web","resource":"SELECT ?","priority":1,"kept":true,"sampler":"priority","spans":[{"span_id":1,"parent_id":0,"service":"web","name":"query","resource":"SELECT ?","raw_resource":"SELECT 1"}]}`)
+
+	var buf bytes.Buffer
+	require.NoError(t, printChunk(&buf, line, false))
+	assert.Equal(t, `2024-01-02T03:04:05Z trace_id=42 service="web" resource="SELECT ?" env="" priority=1 kept by priority sampler, 1 spans
+  span_id=1 parent_id=0 service="web" name="query" resource="SELECT ?"
+    before obfuscation: "SELECT 1"
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, printChunk(&buf, line, true))
+	assert.Equal(t, string(line)+"\n", buf.String())
+
+	buf.Reset()
+	dropped := []byte(`{"time":"2024-01-02T03:04:05Z","trace_id":43,"service":"web","resource":"GET /health","priority":1,"kept":false,"sampler":"","drop_reason":"ignore_resources","spans":[{"span_id":1,"parent_id":0,"service":"web","name":"request","resource":"GET /health"}]}`)
+	require.NoError(t, printChunk(&buf, dropped, false))
+	assert.Equal(t, `2024-01-02T03:04:05Z trace_id=43 service="web" resource="GET /health" env="" priority=1 dropped before sampling (ignore_resources), 1 spans
+  span_id=1 parent_id=0 service="web" name="request" resource="GET /health"
+`, buf.String())
+
+	assert.Error(t, printChunk(&buf, []byte("{"), false))
+}
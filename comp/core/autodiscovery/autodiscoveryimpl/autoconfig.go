@@ -11,6 +11,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"sort"
 	"sync"
@@ -21,6 +22,7 @@ import (
 
 	api "github.com/DataDog/datadog-agent/comp/api/api/def"
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/configresolver"
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/listeners"
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/providers"
@@ -98,6 +100,7 @@ type provides struct {
 	StatusProvider status.InformationProvider
 	Endpoint       api.AgentEndpointProvider
 	EndpointRaw    api.AgentEndpointProvider
+	DryRunEndpoint api.AgentEndpointProvider
 	FlareProvider  flaretypes.Provider
 }
 
@@ -115,8 +118,9 @@ func newProvides(deps dependencies) provides {
 		Comp:           c,
 		StatusProvider: status.NewInformationProvider(autodiscoveryStatus.GetProvider(c)),
 
-		Endpoint:      api.NewAgentEndpointProvider(c.(*AutoConfig).writeConfigCheck, "/config-check", "GET"),
-		FlareProvider: flaretypes.NewProvider(c.(*AutoConfig).fillFlare),
+		Endpoint:       api.NewAgentEndpointProvider(c.(*AutoConfig).writeConfigCheck, "/config-check", "GET"),
+		DryRunEndpoint: api.NewAgentEndpointProvider(c.(*AutoConfig).writeDryRun, "/config-check/dry-run", "POST"),
+		FlareProvider:  flaretypes.NewProvider(c.(*AutoConfig).fillFlare),
 	}
 }
 
@@ -214,6 +218,56 @@ func (ac *AutoConfig) writeConfigCheck(w http.ResponseWriter, r *http.Request) {
 	w.Write(jsonConfig)
 }
 
+// writeDryRun resolves the templates of the request against an active service
+// without scheduling them, and writes the scrubbed results.
+func (ac *AutoConfig) writeDryRun(w http.ResponseWriter, r *http.Request) {
+	var request integration.DryRunRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		httputils.SetJSONError(w, fmt.Errorf("unable to parse dry-run request: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	svc, found := ac.cfgMgr.findService(request.Entity)
+	if !found {
+		httputils.SetJSONError(w, fmt.Errorf("no autodiscovery service found for entity %q", request.Entity), http.StatusNotFound)
+		return
+	}
+
+	response := integration.DryRunResponse{ServiceID: svc.GetServiceID()}
+	for _, tpl := range request.Templates {
+		// templates read from labels or annotations target the entity of the
+		// request, which may be a shorthand of the service ID
+		for i, adID := range tpl.ADIdentifiers {
+			if adID == request.Entity {
+				tpl.ADIdentifiers = append([]string{}, tpl.ADIdentifiers...)
+				tpl.ADIdentifiers[i] = svc.GetServiceID()
+			}
+		}
+
+		resolved, errs := configresolver.DryRun(tpl, svc)
+
+		result := integration.DryRunResult{
+			Template: ac.scrubConfigs([]integration.Config{tpl})[0],
+		}
+		for _, err := range errs {
+			result.Errors = append(result.Errors, err.Error())
+		}
+		if len(errs) == 0 {
+			result.Resolved = &ac.scrubConfigs([]integration.Config{resolved})[0]
+		}
+
+		response.Results = append(response.Results, result)
+	}
+
+	jsonResponse, err := json.Marshal(response)
+	if err != nil {
+		httputils.SetJSONError(w, err, 500)
+		return
+	}
+
+	w.Write(jsonResponse)
+}
+
 // GetConfigCheck returns scrubbed information from all configuration providers
 func (ac *AutoConfig) GetConfigCheck() integration.ConfigCheckResponse {
 	var response integration.ConfigCheckResponse
@@ -6,6 +6,7 @@
 package autodiscoveryimpl
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -588,6 +589,74 @@ func TestWriteConfigEndpoint(t *testing.T) {
 	}
 }
 
+func TestWriteDryRunEndpoint(t *testing.T) {
+	deps := createDeps(t)
+	ctx := context.Background()
+
+	mockResolver := MockSecretResolver{t, nil}
+	ac := getAutoConfig(scheduler.NewController(), &mockResolver, deps.WMeta, deps.TaggerComp, deps.LogsComp)
+	ac.processNewService(ctx, &dummyService{
+		ID:            "docker://abcd",
+		ADIdentifiers: []string{"redis"},
+		Hosts:         map[string]string{"bridge": "172.17.0.2"},
+	})
+
+	tpl := integration.Config{
+		Name:          "redisdb",
+		ADIdentifiers: []string{"redis"},
+		Instances:     []integration.Data{integration.Data("host: %%host%%\nport: %%port%%\npassword: secret")},
+	}
+
+	testCases := []struct {
+		name           string
+		entity         string
+		expectedCode   int
+		expectedResult integration.DryRunResponse
+	}{
+		{
+			name:         "Unknown entity",
+			entity:       "efgh",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "Known entity",
+			entity:       "abcd",
+			expectedCode: http.StatusOK,
+			expectedResult: integration.DryRunResponse{
+				ServiceID: "docker://abcd",
+				Results: []integration.DryRunResult{
+					{
+						Template: integration.Config{
+							Name:          "redisdb",
+							ADIdentifiers: []string{"redis"},
+							Instances:     []integration.Data{integration.Data("host: %%host%%\nport: %%port%%\npassword: \"********\"")},
+						},
+						Errors: []string{"%%port%%: no port found for container docker://abcd - ignoring it"},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			body, err := json.Marshal(integration.DryRunRequest{Templates: []integration.Config{tpl}, Entity: tc.entity})
+			require.NoError(t, err)
+
+			responseRecorder := httptest.NewRecorder()
+			ac.writeDryRun(responseRecorder, httptest.NewRequest("POST", "http://example.com", bytes.NewReader(body)))
+			require.Equal(t, tc.expectedCode, responseRecorder.Code)
+			if tc.expectedCode != http.StatusOK {
+				return
+			}
+
+			var result integration.DryRunResponse
+			require.NoError(t, json.Unmarshal(responseRecorder.Body.Bytes(), &result))
+			assert.Equal(t, tc.expectedResult, result)
+		})
+	}
+}
+
 type Deps struct {
 	fx.In
 	WMeta      optional.Option[workloadmeta.Component]
@@ -8,6 +8,7 @@ package autodiscoveryimpl
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/configresolver"
@@ -46,6 +47,10 @@ type configManager interface {
 	// The call is made with the manager's lock held, so callers should perform
 	// minimal work within f.
 	mapOverLoadedConfigs(func(map[string]integration.Config))
+
+	// findService returns the active service with the given service ID, or
+	// the one created from the given entity, e.g. a container ID.
+	findService(entity string) (listeners.Service, bool)
 }
 
 // serviceAndADIDs bundles a service and its associated AD identifiers.
@@ -292,6 +297,31 @@ func (cm *reconcilingConfigManager) mapOverLoadedConfigs(f func(map[string]integ
 	f(cm.scheduledConfigs)
 }
 
+// findService implements configManager#findService.
+func (cm *reconcilingConfigManager) findService(entity string) (listeners.Service, bool) {
+	cm.m.Lock()
+	defer cm.m.Unlock()
+
+	if svcAndADIDs, found := cm.activeServices[entity]; found {
+		return svcAndADIDs.svc, true
+	}
+
+	// service IDs are entity names, e.g. docker://<container ID>
+	var match listeners.Service
+	for svcID, svcAndADIDs := range cm.activeServices {
+		if !strings.HasSuffix(svcID, "://"+entity) {
+			continue
+		}
+		if match != nil {
+			// the entity is ambiguous
+			return nil, false
+		}
+		match = svcAndADIDs.svc
+	}
+
+	return match, match != nil
+}
+
 // reconcileService calculates the current set of resolved templates for the
 // given service and calculates the difference from what is currently recorded
 // in cm.serviceResolutions.  It updates cm.serviceResolutions and returns the
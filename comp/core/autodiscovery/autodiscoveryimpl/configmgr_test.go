@@ -539,6 +539,33 @@ func (suite *ReconcilingConfigManagerSuite) TestServiceTemplateFiltering() {
 	)
 }
 
+// Active services are found by service ID, or by the entity they were created
+// from
+func (suite *ConfigManagerSuite) TestFindService() {
+	dockerSvc := &dummyService{ID: "docker://abcd", ADIdentifiers: []string{"redis"}}
+	suite.cm.processNewService(myService.ADIdentifiers, myService)
+	suite.cm.processNewService(dockerSvc.ADIdentifiers, dockerSvc)
+
+	svc, found := suite.cm.findService("my-service")
+	suite.True(found)
+	suite.Equal(myService, svc)
+
+	svc, found = suite.cm.findService("docker://abcd")
+	suite.True(found)
+	suite.Equal(dockerSvc, svc)
+
+	svc, found = suite.cm.findService("abcd")
+	suite.True(found)
+	suite.Equal(dockerSvc, svc)
+
+	_, found = suite.cm.findService("bcd")
+	suite.False(found)
+
+	suite.cm.processDelService(context.TODO(), dockerSvc)
+	_, found = suite.cm.findService("abcd")
+	suite.False(found)
+}
+
 func TestReconcilingConfigManagement(t *testing.T) {
 	mockResolver := MockSecretResolver{}
 	suite.Run(t, &ReconcilingConfigManagerSuite{
@@ -0,0 +1,250 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package configresolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/listeners"
+)
+
+// TemplateVariableError represents a template variable that can't be resolved
+type TemplateVariableError struct {
+	// Variable is the template variable, without the surrounding %%
+	Variable string
+	Err      error
+}
+
+// Error returns the error message
+func (e *TemplateVariableError) Error() string {
+	return fmt.Sprintf("%%%%%s%%%%: %s", e.Variable, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *TemplateVariableError) Unwrap() error {
+	return e.Err
+}
+
+// templateVariable is a template variable found in a template, e.g. the
+// "%%port_http%%" variable has the name "port" and the key "http".
+type templateVariable struct {
+	name string
+	key  string
+}
+
+func (v templateVariable) String() string {
+	if v.key == "" {
+		return v.name
+	}
+	return v.name + "_" + v.key
+}
+
+// variablesRequiringKey are the template variables that can't be resolved
+// without a key.
+var variablesRequiringKey = map[string]struct{}{
+	"env":   {},
+	"extra": {},
+	"kube":  {},
+}
+
+// LintTemplate returns the errors of a template that can be found without
+// resolving it against a service: unparsable data, unknown template variables
+// and invalid AD identifiers.
+func LintTemplate(tpl integration.Config) []error {
+	var errs []error
+
+	for _, toResolve := range listDataToResolve(&tpl) {
+		if len(*toResolve.data) == 0 {
+			continue
+		}
+		var tree interface{}
+		if err := toResolve.parser.unmarshal([]byte(strings.ReplaceAll(string(*toResolve.data), "%%", "‰")), &tree); err != nil {
+			errs = append(errs, fmt.Errorf("unable to parse %s: %w", toResolve.dtype, err))
+		}
+	}
+
+	for _, v := range listTemplateVariables(tpl) {
+		if _, found := templateVariables[v.name]; !found {
+			errs = append(errs, &TemplateVariableError{Variable: v.String(), Err: errors.New("unknown template variable")})
+			continue
+		}
+		if _, found := variablesRequiringKey[v.name]; found && v.key == "" {
+			errs = append(errs, &TemplateVariableError{Variable: v.String(), Err: fmt.Errorf("missing name, use %%%%%s_<name>%%%%", v.name)})
+			continue
+		}
+		if !tpl.IsTemplate() && v.name != "env" {
+			errs = append(errs, &TemplateVariableError{Variable: v.String(), Err: errors.New("requires a service, but the config has no ad_identifiers")})
+		}
+	}
+
+	errs = append(errs, lintADIdentifiers(tpl)...)
+
+	return errs
+}
+
+func lintADIdentifiers(tpl integration.Config) []error {
+	var errs []error
+
+	seen := make(map[string]struct{}, len(tpl.ADIdentifiers))
+	for _, adID := range tpl.ADIdentifiers {
+		switch {
+		case adID == "":
+			errs = append(errs, errors.New("invalid empty AD identifier"))
+			continue
+		case strings.TrimSpace(adID) != adID:
+			errs = append(errs, fmt.Errorf("invalid AD identifier %q: leading or trailing whitespace", adID))
+		case strings.Contains(adID, "%%"):
+			errs = append(errs, fmt.Errorf("invalid AD identifier %q: template variables are not supported in AD identifiers", adID))
+		case strings.HasSuffix(adID, "://"):
+			errs = append(errs, fmt.Errorf("invalid AD identifier %q: missing entity ID", adID))
+		}
+
+		if _, found := seen[adID]; found {
+			errs = append(errs, fmt.Errorf("duplicate AD identifier %q", adID))
+		}
+		seen[adID] = struct{}{}
+	}
+
+	for _, advancedID := range tpl.AdvancedADIdentifiers {
+		switch {
+		case advancedID.KubeService.IsEmpty() && advancedID.KubeEndpoints.IsEmpty():
+			errs = append(errs, errors.New("invalid advanced AD identifier: one of kube_service or kube_endpoints is required"))
+		case !advancedID.KubeService.IsEmpty() && !advancedID.KubeEndpoints.IsEmpty():
+			errs = append(errs, errors.New("invalid advanced AD identifier: kube_service and kube_endpoints are mutually exclusive"))
+		case !advancedID.KubeService.IsEmpty():
+			errs = append(errs, lintKubeNamespacedName("kube_service", advancedID.KubeService)...)
+		default:
+			errs = append(errs, lintKubeNamespacedName("kube_endpoints", advancedID.KubeEndpoints)...)
+		}
+	}
+
+	return errs
+}
+
+func lintKubeNamespacedName(kind string, name integration.KubeNamespacedName) []error {
+	var errs []error
+	if name.Name == "" {
+		errs = append(errs, fmt.Errorf("invalid advanced AD identifier: missing %s name", kind))
+	}
+	if name.Namespace == "" {
+		errs = append(errs, fmt.Errorf("invalid advanced AD identifier: missing %s namespace", kind))
+	}
+	return errs
+}
+
+// ResolveTemplateVariables resolves each template variable of a template
+// against a service, and returns a TemplateVariableError for each of them that
+// can't be resolved. It allows to pinpoint why Resolve fails.
+func ResolveTemplateVariables(tpl integration.Config, svc listeners.Service) []error {
+	ctx := context.TODO()
+
+	var errs []error
+	for _, v := range listTemplateVariables(tpl) {
+		getter, found := templateVariables[v.name]
+		if !found {
+			errs = append(errs, &TemplateVariableError{Variable: v.String(), Err: errors.New("unknown template variable")})
+			continue
+		}
+
+		if _, err := getter(ctx, v.key, svc); err != nil {
+			errs = append(errs, &TemplateVariableError{Variable: v.String(), Err: err})
+		}
+	}
+
+	return errs
+}
+
+// DryRun resolves a template against a service like the autodiscovery would,
+// without scheduling the result. It returns the resolved config, and every
+// error that would prevent it from being scheduled.
+func DryRun(tpl integration.Config, svc listeners.Service) (integration.Config, []error) {
+	ctx := context.TODO()
+
+	var errs []error
+
+	if tpl.IsTemplate() {
+		adIDs, err := svc.GetADIdentifiers(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to get the AD identifiers of service %s: %w", svc.GetServiceID(), err))
+		} else if !matchesADIdentifiers(tpl, adIDs) {
+			errs = append(errs, fmt.Errorf("none of the AD identifiers of the template match service %s, whose AD identifiers are %v", svc.GetServiceID(), adIDs))
+		}
+	}
+
+	varErrs := ResolveTemplateVariables(tpl, svc)
+	errs = append(errs, varErrs...)
+
+	resolved, err := Resolve(tpl, svc)
+	if err != nil && len(varErrs) == 0 {
+		errs = append(errs, err)
+	}
+
+	return resolved, errs
+}
+
+func matchesADIdentifiers(tpl integration.Config, adIDs []string) bool {
+	for _, tplADID := range tpl.ADIdentifiers {
+		for _, adID := range adIDs {
+			if tplADID == adID {
+				return true
+			}
+		}
+	}
+
+	// advanced AD identifiers are matched against the AD identifiers of the
+	// kube services and endpoints, which embed their namespaced name
+	for _, advancedID := range tpl.AdvancedADIdentifiers {
+		for _, adID := range adIDs {
+			if !advancedID.KubeService.IsEmpty() && adID == "kube_service://"+advancedID.KubeService.Namespace+"/"+advancedID.KubeService.Name {
+				return true
+			}
+			if !advancedID.KubeEndpoints.IsEmpty() && strings.HasPrefix(adID, "kube_endpoint_uid://"+advancedID.KubeEndpoints.Namespace+"/"+advancedID.KubeEndpoints.Name+"/") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// listTemplateVariables returns the template variables found in the init
+// config, instances and logs config of a template, in order of appearance and
+// without duplicates.
+func listTemplateVariables(tpl integration.Config) []templateVariable {
+	var vars []templateVariable
+	seen := make(map[templateVariable]struct{})
+
+	for _, toResolve := range listDataToResolve(&tpl) {
+		data := strings.ReplaceAll(string(*toResolve.data), "%%", "‰")
+		for _, match := range varPattern.FindAllStringSubmatch(data, -1) {
+			v := templateVariable{name: match[1], key: match[2]}
+			if _, found := seen[v]; found {
+				continue
+			}
+			seen[v] = struct{}{}
+			vars = append(vars, v)
+		}
+	}
+
+	return vars
+}
+
+func (t dataType) String() string {
+	switch t {
+	case dataInit:
+		return "init_config"
+	case dataInstance:
+		return "instance"
+	case dataLogs:
+		return "logs config"
+	default:
+		return "unknown data"
+	}
+}
@@ -0,0 +1,160 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package configresolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/listeners"
+)
+
+func errorStrings(errs []error) []string {
+	var res []string
+	for _, err := range errs {
+		res = append(res, err.Error())
+	}
+	return res
+}
+
+func TestLintTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		tpl      integration.Config
+		expected []string
+	}{
+		{
+			name: "valid template",
+			tpl: integration.Config{
+				Name:          "redisdb",
+				ADIdentifiers: []string{"redis"},
+				InitConfig:    integration.Data("{}"),
+				Instances:     []integration.Data{integration.Data("host: %%host%%\nport: %%port_redis%%\npassword: '%%env_REDIS_PASSWORD%%'")},
+			},
+		},
+		{
+			name: "unknown and incomplete template variables",
+			tpl: integration.Config{
+				Name:          "redisdb",
+				ADIdentifiers: []string{"redis"},
+				Instances:     []integration.Data{integration.Data("host: %%hots%%\npassword: '%%env%%'\nnamespace: %%kube%%")},
+			},
+			expected: []string{
+				"%%hots%%: unknown template variable",
+				"%%env%%: missing name, use %%env_<name>%%",
+				"%%kube%%: missing name, use %%kube_<name>%%",
+			},
+		},
+		{
+			name: "service variables without AD identifiers",
+			tpl: integration.Config{
+				Name:      "redisdb",
+				Instances: []integration.Data{integration.Data("host: %%host%%\npassword: '%%env_REDIS_PASSWORD%%'")},
+			},
+			expected: []string{
+				"%%host%%: requires a service, but the config has no ad_identifiers",
+			},
+		},
+		{
+			name: "unparsable instance",
+			tpl: integration.Config{
+				Name:          "redisdb",
+				ADIdentifiers: []string{"redis"},
+				Instances:     []integration.Data{integration.Data("host: [%%host%%")},
+			},
+			expected: []string{
+				"unable to parse instance: yaml: line 1: did not find expected ',' or ']'",
+			},
+		},
+		{
+			name: "invalid AD identifiers",
+			tpl: integration.Config{
+				Name:          "redisdb",
+				ADIdentifiers: []string{"redis", "", " redis", "redis", "%%env_IMAGE%%", "docker://"},
+				AdvancedADIdentifiers: []integration.AdvancedADIdentifier{
+					{},
+					{KubeService: integration.KubeNamespacedName{Name: "redis"}},
+					{
+						KubeService:   integration.KubeNamespacedName{Name: "redis", Namespace: "default"},
+						KubeEndpoints: integration.KubeNamespacedName{Name: "redis", Namespace: "default"},
+					},
+				},
+				Instances: []integration.Data{integration.Data("host: localhost")},
+			},
+			expected: []string{
+				"invalid empty AD identifier",
+				`invalid AD identifier " redis": leading or trailing whitespace`,
+				`duplicate AD identifier "redis"`,
+				`invalid AD identifier "%%env_IMAGE%%": template variables are not supported in AD identifiers`,
+				`invalid AD identifier "docker://": missing entity ID`,
+				"invalid advanced AD identifier: one of kube_service or kube_endpoints is required",
+				"invalid advanced AD identifier: missing kube_service namespace",
+				"invalid advanced AD identifier: kube_service and kube_endpoints are mutually exclusive",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, errorStrings(LintTemplate(tc.tpl)))
+		})
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	svc := &dummyService{
+		ID:            "docker://redis-1",
+		ADIdentifiers: []string{"docker://redis-1", "redis"},
+		Hosts:         map[string]string{"bridge": "172.17.0.2"},
+		Ports:         []listeners.ContainerPort{{Port: 6379, Name: "redis"}},
+	}
+
+	t.Run("resolved", func(t *testing.T) {
+		tpl := integration.Config{
+			Name:          "redisdb",
+			ADIdentifiers: []string{"redis"},
+			Instances:     []integration.Data{integration.Data("host: %%host%%\nport: %%port_redis%%")},
+		}
+
+		resolved, errs := DryRun(tpl, svc)
+		require.Empty(t, errs)
+		assert.Equal(t, "docker://redis-1", resolved.ServiceID)
+		assert.Equal(t, []integration.Data{integration.Data("host: 172.17.0.2\nport: 6379\ntags:\n- foo:bar\n")}, resolved.Instances)
+	})
+
+	t.Run("errors per variable", func(t *testing.T) {
+		tpl := integration.Config{
+			Name:          "redisdb",
+			ADIdentifiers: []string{"redis"},
+			Instances:     []integration.Data{integration.Data("host: %%host_overlay%%\nport: %%port_admin%%\npassword: '%%env_DRY_RUN_UNSET_PASSWORD%%'")},
+		}
+
+		_, errs := DryRun(tpl, svc)
+		require.Len(t, errs, 2)
+		assert.Equal(t, "%%port_admin%%: port admin not found, skipping container docker://redis-1", errs[0].Error())
+		assert.Equal(t, "%%env_DRY_RUN_UNSET_PASSWORD%%: failed to retrieve envvar DRY_RUN_UNSET_PASSWORD, skipping service docker://redis-1", errs[1].Error())
+
+		var varErr *TemplateVariableError
+		assert.ErrorAs(t, errs[0], &varErr)
+		assert.Equal(t, "port_admin", varErr.Variable)
+	})
+
+	t.Run("AD identifiers mismatch", func(t *testing.T) {
+		tpl := integration.Config{
+			Name:          "redisdb",
+			ADIdentifiers: []string{"postgres"},
+			Instances:     []integration.Data{integration.Data("host: %%host%%")},
+		}
+
+		_, errs := DryRun(tpl, svc)
+		assert.Equal(t, []string{
+			"none of the AD identifiers of the template match service docker://redis-1, whose AD identifiers are [docker://redis-1 redis]",
+		}, errorStrings(errs))
+	})
+}
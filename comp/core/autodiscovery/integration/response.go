@@ -12,3 +12,26 @@ type ConfigCheckResponse struct {
 	ConfigErrors    map[string]string   `json:"config_errors"`
 	Unresolved      map[string][]Config `json:"unresolved"`
 }
+
+// DryRunRequest holds a request to resolve templates against a service without
+// scheduling them
+type DryRunRequest struct {
+	Templates []Config `json:"templates"`
+	// Entity is the ID of the service, or the ID of the workloadmeta entity
+	// it was created from, e.g. a container ID
+	Entity string `json:"entity"`
+}
+
+// DryRunResponse holds the dry-run resolution response
+type DryRunResponse struct {
+	ServiceID string         `json:"service_id"`
+	Results   []DryRunResult `json:"results"`
+}
+
+// DryRunResult holds the result of the dry-run resolution of a template. The
+// resolved config is only set when the resolution succeeds.
+type DryRunResult struct {
+	Template Config   `json:"template"`
+	Resolved *Config  `json:"resolved,omitempty"`
+	Errors   []string `json:"errors,omitempty"`
+}
@@ -0,0 +1,286 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build consul
+
+package listeners
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+
+	consul "github.com/hashicorp/consul/api"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/containers"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	consulServiceEntityPrefix = "consul_service://"
+	consulADIdentifierPrefix  = "_consul."
+	consulCatalogWaitTime     = 5 * time.Minute
+	consulCatalogRetry        = 10 * time.Second
+)
+
+// ConsulCatalogListener discovers the service instances registered in the
+// Consul catalog for the local Consul node, using blocking queries.
+type ConsulCatalogListener struct {
+	newService chan<- Service
+	delService chan<- Service
+	services   map[string]*ConsulCatalogService
+	client     *consul.Client
+	nodeName   string
+	cancel     context.CancelFunc
+	// retryInterval is the delay before retrying after a failed query
+	retryInterval time.Duration
+}
+
+// ConsulCatalogService is a service instance registered in the Consul
+// catalog.
+type ConsulCatalogService struct {
+	entity        string
+	adIdentifiers []string
+	hosts         map[string]string
+	ports         []ContainerPort
+	tags          []string
+}
+
+// Make sure ConsulCatalogService implements the Service interface
+var _ Service = &ConsulCatalogService{}
+
+// NewConsulCatalogListener returns a new ConsulCatalogListener.
+func NewConsulCatalogListener(Config) (ServiceListener, error) {
+	clientCfg := consul.DefaultConfig()
+
+	if address := config.Datadog().GetString("consul_catalog.address"); address != "" {
+		clientCfg.Address = address
+	}
+	if token := config.Datadog().GetString("consul_catalog.token"); token != "" {
+		clientCfg.Token = token
+	}
+	clientCfg.TLSConfig.CAFile = config.Datadog().GetString("consul_catalog.ca_file")
+	clientCfg.TLSConfig.CertFile = config.Datadog().GetString("consul_catalog.cert_file")
+	clientCfg.TLSConfig.KeyFile = config.Datadog().GetString("consul_catalog.key_file")
+
+	client, err := consul.NewClient(clientCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConsulCatalogListener{
+		services:      make(map[string]*ConsulCatalogService),
+		client:        client,
+		nodeName:      config.Datadog().GetString("consul_catalog.node_name"),
+		retryInterval: consulCatalogRetry,
+	}, nil
+}
+
+// Listen starts watching the services of the node.
+func (l *ConsulCatalogListener) Listen(newSvc chan<- Service, delSvc chan<- Service) {
+	l.newService = newSvc
+	l.delService = delSvc
+
+	ctx, cancel := context.WithCancel(context.Background())
+	l.cancel = cancel
+
+	go l.run(ctx)
+}
+
+// Stop stops the ConsulCatalogListener.
+func (l *ConsulCatalogListener) Stop() {
+	l.cancel()
+}
+
+func (l *ConsulCatalogListener) run(ctx context.Context) {
+	var index uint64
+
+	for {
+		if l.nodeName == "" {
+			nodeName, err := l.client.Agent().NodeName()
+			if err != nil {
+				log.Warnf("Cannot get the name of the local Consul node, retrying in %s: %v", l.retryInterval, err)
+				if !l.wait(ctx) {
+					return
+				}
+				continue
+			}
+			l.nodeName = nodeName
+		}
+
+		q := &consul.QueryOptions{
+			WaitIndex: index,
+			WaitTime:  consulCatalogWaitTime,
+		}
+		nodeServices, meta, err := l.client.Catalog().NodeServiceList(l.nodeName, q.WithContext(ctx))
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Warnf("Cannot list the services of Consul node %s, retrying in %s: %v", l.nodeName, l.retryInterval, err)
+			if !l.wait(ctx) {
+				return
+			}
+			continue
+		}
+
+		// The index can go backwards when the Consul state is restored, in
+		// which case the next query must not block.
+		index = meta.LastIndex
+		if index < q.WaitIndex {
+			index = 0
+		}
+
+		l.refreshServices(nodeServices)
+	}
+}
+
+// wait waits for the retry interval, and returns false if the context is
+// cancelled in the meantime.
+func (l *ConsulCatalogListener) wait(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(l.retryInterval):
+		return true
+	}
+}
+
+func (l *ConsulCatalogListener) refreshServices(nodeServices *consul.CatalogNodeServiceList) {
+	var instances []*ConsulCatalogService
+	if nodeServices != nil && nodeServices.Node != nil {
+		for _, service := range nodeServices.Services {
+			instances = append(instances, newConsulCatalogService(nodeServices.Node, service))
+		}
+	}
+
+	seen := make(map[string]struct{}, len(instances))
+
+	for _, svc := range instances {
+		seen[svc.entity] = struct{}{}
+
+		if old, found := l.services[svc.entity]; found {
+			if old.Equal(svc) {
+				continue
+			}
+			l.delService <- old
+		}
+
+		l.services[svc.entity] = svc
+		l.newService <- svc
+	}
+
+	for entity, svc := range l.services {
+		if _, found := seen[entity]; !found {
+			l.delService <- svc
+			delete(l.services, entity)
+		}
+	}
+}
+
+func newConsulCatalogService(node *consul.Node, service *consul.AgentService) *ConsulCatalogService {
+	address := service.Address
+	if address == "" {
+		address = node.Address
+	}
+
+	var ports []ContainerPort
+	if service.Port != 0 {
+		ports = append(ports, ContainerPort{Port: service.Port, Name: service.Service})
+	}
+
+	tags := []string{
+		"consul_service:" + service.Service,
+		"consul_node:" + node.Node,
+	}
+	if node.Datacenter != "" {
+		tags = append(tags, "consul_datacenter:"+node.Datacenter)
+	}
+	tags = append(tags, service.Tags...)
+	sort.Strings(tags)
+
+	entity := consulServiceEntityPrefix + node.Node + "/" + service.ID
+
+	return &ConsulCatalogService{
+		entity:        entity,
+		adIdentifiers: []string{entity, consulADIdentifierPrefix + service.Service},
+		hosts:         map[string]string{"host": address},
+		ports:         ports,
+		tags:          tags,
+	}
+}
+
+// Equal returns whether the two ConsulCatalogService are equal
+func (s *ConsulCatalogService) Equal(o Service) bool {
+	s2, ok := o.(*ConsulCatalogService)
+	if !ok {
+		return false
+	}
+
+	return s.entity == s2.entity &&
+		reflect.DeepEqual(s.adIdentifiers, s2.adIdentifiers) &&
+		reflect.DeepEqual(s.hosts, s2.hosts) &&
+		reflect.DeepEqual(s.ports, s2.ports) &&
+		reflect.DeepEqual(s.tags, s2.tags)
+}
+
+// GetServiceID returns the entity name of the service instance
+func (s *ConsulCatalogService) GetServiceID() string {
+	return s.entity
+}
+
+// GetADIdentifiers returns the entity name of the service instance and its
+// _consul.<service> identifier
+func (s *ConsulCatalogService) GetADIdentifiers(context.Context) ([]string, error) {
+	return s.adIdentifiers, nil
+}
+
+// GetHosts returns the address of the service instance
+func (s *ConsulCatalogService) GetHosts(context.Context) (map[string]string, error) {
+	return s.hosts, nil
+}
+
+// GetPorts returns the port of the service instance
+func (s *ConsulCatalogService) GetPorts(context.Context) ([]ContainerPort, error) {
+	return s.ports, nil
+}
+
+// GetTags returns the Consul tags of the service instance, including the
+// tags it was registered with
+func (s *ConsulCatalogService) GetTags() ([]string, error) {
+	return s.tags, nil
+}
+
+// GetPid is not supported for Consul services
+func (s *ConsulCatalogService) GetPid(context.Context) (int, error) {
+	return -1, ErrNotSupported
+}
+
+// GetHostname is not supported for Consul services
+func (s *ConsulCatalogService) GetHostname(context.Context) (string, error) {
+	return "", ErrNotSupported
+}
+
+// IsReady returns true, as the catalog only holds registered services
+func (s *ConsulCatalogService) IsReady(context.Context) bool {
+	return true
+}
+
+// HasFilter returns false, as Consul services are not filtered
+func (s *ConsulCatalogService) HasFilter(containers.FilterType) bool {
+	return false
+}
+
+// GetExtraConfig is not supported for Consul services
+func (s *ConsulCatalogService) GetExtraConfig(string) (string, error) {
+	return "", ErrNotSupported
+}
+
+// FilterTemplates does nothing.
+func (s *ConsulCatalogService) FilterTemplates(map[string]integration.Config) {
+}
@@ -0,0 +1,133 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build consul
+
+package listeners
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	consul "github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+)
+
+// fakeConsulCatalog serves the agent and node services endpoints of the
+// Consul HTTP API, including blocking queries.
+type fakeConsulCatalog struct {
+	mu       sync.Mutex
+	index    uint64
+	services []*consul.AgentService
+	changed  chan struct{}
+}
+
+func newFakeConsulCatalog() *fakeConsulCatalog {
+	return &fakeConsulCatalog{index: 1, changed: make(chan struct{})}
+}
+
+func (c *fakeConsulCatalog) setServices(services ...*consul.AgentService) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.services = services
+	c.index++
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+func (c *fakeConsulCatalog) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Consul-LastContact", "0")
+	w.Header().Set("X-Consul-KnownLeader", "true")
+
+	switch r.URL.Path {
+	case "/v1/agent/self":
+		_ = json.NewEncoder(w).Encode(map[string]map[string]interface{}{
+			"Config": {"NodeName": "node1"},
+		})
+
+	case "/v1/catalog/node-services/node1":
+		waitIndex, _ := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64)
+
+		c.mu.Lock()
+		index, changed := c.index, c.changed
+		c.mu.Unlock()
+
+		if waitIndex >= index {
+			select {
+			case <-changed:
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		w.Header().Set("X-Consul-Index", strconv.FormatUint(c.index, 10))
+		_ = json.NewEncoder(w).Encode(&consul.CatalogNodeServiceList{
+			Node:     &consul.Node{Node: "node1", Address: "10.0.0.1", Datacenter: "dc1"},
+			Services: c.services,
+		})
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestConsulCatalogListener(t *testing.T) {
+	catalog := newFakeConsulCatalog()
+	catalog.setServices(&consul.AgentService{ID: "redis-1", Service: "redis", Port: 6379, Tags: []string{"primary", "env:prod"}})
+
+	server := httptest.NewServer(catalog)
+	defer server.Close()
+
+	cfg := configmock.New(t)
+	cfg.SetWithoutSource("consul_catalog.address", server.URL)
+
+	l, err := NewConsulCatalogListener(nil)
+	require.NoError(t, err)
+
+	newSvc := make(chan Service, 10)
+	delSvc := make(chan Service, 10)
+	l.Listen(newSvc, delSvc)
+	defer l.Stop()
+
+	expected := &ConsulCatalogService{
+		entity:        "consul_service://node1/redis-1",
+		adIdentifiers: []string{"consul_service://node1/redis-1", "_consul.redis"},
+		hosts:         map[string]string{"host": "10.0.0.1"},
+		ports:         []ContainerPort{{Port: 6379, Name: "redis"}},
+		tags:          []string{"consul_datacenter:dc1", "consul_node:node1", "consul_service:redis", "env:prod", "primary"},
+	}
+	assert.Equal(t, expected, receiveConsulService(t, newSvc))
+
+	// services are replaced when their tags change
+	catalog.setServices(&consul.AgentService{ID: "redis-1", Service: "redis", Port: 6379, Tags: []string{"replica", "env:prod"}})
+	assert.Equal(t, expected, receiveConsulService(t, delSvc))
+	retagged := *expected
+	retagged.tags = []string{"consul_datacenter:dc1", "consul_node:node1", "consul_service:redis", "env:prod", "replica"}
+	assert.Equal(t, &retagged, receiveConsulService(t, newSvc))
+
+	// deregistered services are removed
+	catalog.setServices()
+	assert.Equal(t, &retagged, receiveConsulService(t, delSvc))
+}
+
+func receiveConsulService(t *testing.T, ch <-chan Service) Service {
+	select {
+	case svc := <-ch:
+		return svc
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for a service")
+		return nil
+	}
+}
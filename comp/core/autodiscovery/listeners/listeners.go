@@ -13,13 +13,16 @@ import (
 
 const (
 	cloudFoundryBBSListenerName = "cloudfoundry_bbs"
+	consulCatalogListenerName   = "consul_catalog"
 	containerListenerName       = "container"
 	environmentListenerName     = "environment"
 	kubeEndpointsListenerName   = "kube_endpoints"
 	kubeServicesListenerName    = "kube_services"
 	kubeletListenerName         = "kubelet"
+	nomadListenerName           = "nomad"
 	snmpListenerName            = "snmp"
 	staticConfigListenerName    = "static config"
+	systemdUnitsListenerName    = "systemd_units"
 	dbmAuroraListenerName       = "database-monitoring-aurora"
 )
 
@@ -27,12 +30,15 @@ const (
 func RegisterListeners(serviceListenerFactories map[string]ServiceListenerFactory, wmeta optional.Option[workloadmeta.Component]) {
 	// register the available listeners
 	Register(cloudFoundryBBSListenerName, NewCloudFoundryListener, serviceListenerFactories)
+	Register(consulCatalogListenerName, NewConsulCatalogListener, serviceListenerFactories)
 	Register(containerListenerName, func(config Config) (ServiceListener, error) { return NewContainerListener(config, wmeta) }, serviceListenerFactories)
 	Register(environmentListenerName, NewEnvironmentListener, serviceListenerFactories)
 	Register(kubeEndpointsListenerName, NewKubeEndpointsListener, serviceListenerFactories)
 	Register(kubeServicesListenerName, NewKubeServiceListener, serviceListenerFactories)
 	Register(kubeletListenerName, func(config Config) (ServiceListener, error) { return NewKubeletListener(config, wmeta) }, serviceListenerFactories)
+	Register(nomadListenerName, NewNomadListener, serviceListenerFactories)
 	Register(snmpListenerName, NewSNMPListener, serviceListenerFactories)
 	Register(staticConfigListenerName, NewStaticConfigListener, serviceListenerFactories)
+	Register(systemdUnitsListenerName, func(config Config) (ServiceListener, error) { return NewSystemdUnitListener(config, wmeta) }, serviceListenerFactories)
 	Register(dbmAuroraListenerName, NewDBMAuroraListener, serviceListenerFactories)
 }
@@ -0,0 +1,253 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build nomad
+
+package listeners
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/util/containers"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/nomad"
+)
+
+const (
+	nomadADIdentifierPrefix = "_nomad."
+	nomadRetryInterval      = 10 * time.Second
+)
+
+// NomadListener discovers the running tasks of the allocations of the local
+// Nomad client node, using blocking queries on the Nomad HTTP API.
+type NomadListener struct {
+	newService chan<- Service
+	delService chan<- Service
+	services   map[string]*NomadService
+	client     *api.Client
+	cancel     context.CancelFunc
+	// retryInterval is the delay before retrying after a failed query
+	retryInterval time.Duration
+}
+
+// NomadService is a running task of a Nomad allocation.
+type NomadService struct {
+	entity        string
+	adIdentifiers []string
+	hosts         map[string]string
+	ports         []ContainerPort
+	tags          []string
+}
+
+// Make sure NomadService implements the Service interface
+var _ Service = &NomadService{}
+
+// NewNomadListener returns a new NomadListener.
+func NewNomadListener(Config) (ServiceListener, error) {
+	client, err := nomad.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &NomadListener{
+		services:      make(map[string]*NomadService),
+		client:        client,
+		retryInterval: nomadRetryInterval,
+	}, nil
+}
+
+// Listen starts watching the allocations of the node.
+func (l *NomadListener) Listen(newSvc chan<- Service, delSvc chan<- Service) {
+	l.newService = newSvc
+	l.delService = delSvc
+
+	ctx, cancel := context.WithCancel(context.Background())
+	l.cancel = cancel
+
+	go l.run(ctx)
+}
+
+// Stop stops the NomadListener.
+func (l *NomadListener) Stop() {
+	l.cancel()
+}
+
+func (l *NomadListener) run(ctx context.Context) {
+	var (
+		nodeID string
+		index  uint64
+		err    error
+	)
+
+	for {
+		if nodeID == "" {
+			nodeID, err = nomad.LocalNodeID(l.client)
+			if err != nil {
+				log.Warnf("Cannot get the ID of the local Nomad node, retrying in %s: %v", l.retryInterval, err)
+				if !l.wait(ctx) {
+					return
+				}
+				continue
+			}
+		}
+
+		allocs, newIndex, err := nomad.NodeAllocations(ctx, l.client, nodeID, index)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Warnf("Cannot list the allocations of Nomad node %s, retrying in %s: %v", nodeID, l.retryInterval, err)
+			if !l.wait(ctx) {
+				return
+			}
+			continue
+		}
+
+		// The index can go backwards when the Nomad state is restored, in
+		// which case the next query must not block.
+		if newIndex < index {
+			newIndex = 0
+		}
+		index = newIndex
+
+		l.refreshServices(nomad.RunningTasks(allocs))
+	}
+}
+
+// wait waits for the retry interval, and returns false if the context is
+// cancelled in the meantime.
+func (l *NomadListener) wait(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(l.retryInterval):
+		return true
+	}
+}
+
+func (l *NomadListener) refreshServices(tasks []*nomad.Task) {
+	seen := make(map[string]struct{}, len(tasks))
+
+	for _, task := range tasks {
+		svc := newNomadService(task)
+		seen[svc.entity] = struct{}{}
+
+		if old, found := l.services[svc.entity]; found {
+			if old.Equal(svc) {
+				continue
+			}
+			l.delService <- old
+		}
+
+		l.services[svc.entity] = svc
+		l.newService <- svc
+	}
+
+	for entity, svc := range l.services {
+		if _, found := seen[entity]; !found {
+			l.delService <- svc
+			delete(l.services, entity)
+		}
+	}
+}
+
+func newNomadService(task *nomad.Task) *NomadService {
+	ports := make([]ContainerPort, 0, len(task.Ports))
+	for _, port := range task.Ports {
+		ports = append(ports, ContainerPort{Port: port.Value, Name: port.Label})
+	}
+
+	tags := []string{
+		"nomad_namespace:" + task.Namespace,
+		"nomad_job:" + task.JobID,
+		"nomad_group:" + task.Group,
+		"nomad_task:" + task.Name,
+	}
+
+	return &NomadService{
+		entity: task.EntityName(),
+		adIdentifiers: []string{
+			task.EntityName(),
+			nomadADIdentifierPrefix + task.JobID + "." + task.Name,
+		},
+		hosts: task.Hosts,
+		ports: ports,
+		tags:  tags,
+	}
+}
+
+// Equal returns whether the two NomadService are equal
+func (s *NomadService) Equal(o Service) bool {
+	s2, ok := o.(*NomadService)
+	if !ok {
+		return false
+	}
+
+	return s.entity == s2.entity &&
+		reflect.DeepEqual(s.adIdentifiers, s2.adIdentifiers) &&
+		reflect.DeepEqual(s.hosts, s2.hosts) &&
+		reflect.DeepEqual(s.ports, s2.ports) &&
+		reflect.DeepEqual(s.tags, s2.tags)
+}
+
+// GetServiceID returns the entity name of the task
+func (s *NomadService) GetServiceID() string {
+	return s.entity
+}
+
+// GetADIdentifiers returns the entity name of the task and its
+// _nomad.<job>.<task> identifier
+func (s *NomadService) GetADIdentifiers(context.Context) ([]string, error) {
+	return s.adIdentifiers, nil
+}
+
+// GetHosts returns the IP addresses of the task, by network mode
+func (s *NomadService) GetHosts(context.Context) (map[string]string, error) {
+	return s.hosts, nil
+}
+
+// GetPorts returns the ports allocated to the task
+func (s *NomadService) GetPorts(context.Context) ([]ContainerPort, error) {
+	return s.ports, nil
+}
+
+// GetTags returns the Nomad tags of the task
+func (s *NomadService) GetTags() ([]string, error) {
+	return s.tags, nil
+}
+
+// GetPid is not supported for Nomad tasks
+func (s *NomadService) GetPid(context.Context) (int, error) {
+	return -1, ErrNotSupported
+}
+
+// GetHostname is not supported for Nomad tasks
+func (s *NomadService) GetHostname(context.Context) (string, error) {
+	return "", ErrNotSupported
+}
+
+// IsReady returns true, as only running tasks are discovered
+func (s *NomadService) IsReady(context.Context) bool {
+	return true
+}
+
+// HasFilter returns false, as Nomad tasks are not filtered
+func (s *NomadService) HasFilter(containers.FilterType) bool {
+	return false
+}
+
+// GetExtraConfig is not supported for Nomad tasks
+func (s *NomadService) GetExtraConfig(string) (string, error) {
+	return "", ErrNotSupported
+}
+
+// FilterTemplates does nothing.
+func (s *NomadService) FilterTemplates(map[string]integration.Config) {
+}
@@ -0,0 +1,90 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build nomad
+
+package listeners
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+	"github.com/DataDog/datadog-agent/pkg/util/nomad/nomadmock"
+)
+
+func nomadTestAllocation(allocID string, port int) *api.Allocation {
+	return &api.Allocation{
+		ID:           allocID,
+		Namespace:    "default",
+		JobID:        "frontend",
+		TaskGroup:    "web",
+		ClientStatus: api.AllocClientStatusRunning,
+		TaskStates: map[string]*api.TaskState{
+			"nginx": {State: "running"},
+		},
+		AllocatedResources: &api.AllocatedResources{
+			Shared: api.AllocatedSharedResources{
+				Networks: []*api.NetworkResource{
+					{Mode: "host", IP: "10.0.0.1", DynamicPorts: []api.Port{{Label: "http", Value: port}}},
+				},
+			},
+		},
+	}
+}
+
+func receiveService(t *testing.T, ch <-chan Service) Service {
+	select {
+	case svc := <-ch:
+		return svc
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for a service")
+		return nil
+	}
+}
+
+func TestNomadListener(t *testing.T) {
+	server := nomadmock.NewFakeNomadServer("node1")
+	server.SetAllocations(nomadTestAllocation("alloc1", 28080))
+
+	cfg := configmock.New(t)
+	cfg.SetWithoutSource("nomad.address", server.Start(t))
+
+	l, err := NewNomadListener(nil)
+	require.NoError(t, err)
+
+	newSvc := make(chan Service, 10)
+	delSvc := make(chan Service, 10)
+	l.Listen(newSvc, delSvc)
+	defer l.Stop()
+
+	expected := &NomadService{
+		entity:        "nomad_task://alloc1/nginx",
+		adIdentifiers: []string{"nomad_task://alloc1/nginx", "_nomad.frontend.nginx"},
+		hosts:         map[string]string{"host": "10.0.0.1"},
+		ports:         []ContainerPort{{Port: 28080, Name: "http"}},
+		tags:          []string{"nomad_namespace:default", "nomad_job:frontend", "nomad_group:web", "nomad_task:nginx"},
+	}
+	assert.Equal(t, expected, receiveService(t, newSvc))
+
+	// a new port replaces the service
+	server.SetAllocations(nomadTestAllocation("alloc1", 28081))
+	assert.Equal(t, expected, receiveService(t, delSvc))
+	updated := receiveService(t, newSvc).(*NomadService)
+	assert.Equal(t, []ContainerPort{{Port: 28081, Name: "http"}}, updated.ports)
+
+	// stopped tasks are removed
+	stopped := nomadTestAllocation("alloc1", 28081)
+	stopped.ClientStatus = "complete"
+	server.SetAllocations(stopped)
+	assert.Equal(t, updated, receiveService(t, delSvc))
+
+	assert.Empty(t, newSvc)
+	assert.Empty(t, delSvc)
+}
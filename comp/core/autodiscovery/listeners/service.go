@@ -64,6 +64,8 @@ func (s *service) GetServiceID() string {
 		return containers.BuildEntityName(string(e.Runtime), e.ID)
 	case *workloadmeta.KubernetesPod:
 		return kubelet.PodUIDToEntityName(e.ID)
+	case *workloadmeta.SystemdUnit:
+		return systemdUnitTaggerEntityName(e.ID)
 	default:
 		entityID := s.entity.GetID()
 		log.Errorf("cannot build AD entity ID for kind %q, ID %q", entityID.Kind, entityID.ID)
@@ -94,6 +96,8 @@ func (s *service) GetTags() ([]string, error) {
 		taggerEntity = containers.BuildTaggerEntityName(e.ID)
 	case *workloadmeta.KubernetesPod:
 		taggerEntity = kubelet.PodUIDToTaggerEntityName(e.ID)
+	case *workloadmeta.SystemdUnit:
+		taggerEntity = systemdUnitTaggerEntityName(e.ID)
 	default:
 		entityID := s.entity.GetID()
 		log.Errorf("cannot build AD entity ID for kind %q, ID %q", entityID.Kind, entityID.ID)
@@ -213,3 +217,9 @@ func (s *service) GetExtraConfig(key string) (string, error) {
 
 	return result, nil
 }
+
+// systemdUnitTaggerEntityName returns the tagger entity, which is also the AD
+// entity, of a systemd unit.
+func systemdUnitTaggerEntityName(unit string) string {
+	return "systemd_unit://" + unit
+}
@@ -8,6 +8,7 @@ package listeners
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"strconv"
@@ -18,8 +19,11 @@ import (
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/persistentcache"
 	"github.com/DataDog/datadog-agent/pkg/snmp"
+	"github.com/DataDog/datadog-agent/pkg/snmp/gosnmplib"
 	"github.com/DataDog/datadog-agent/pkg/util/containers"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
+
+	"github.com/gosnmp/gosnmp"
 )
 
 const (
@@ -58,13 +62,27 @@ type snmpSubnet struct {
 	cacheKey       string
 	devices        map[string]string
 	deviceFailures map[string]int
+
+	// crawl mode, devices are discovered by following the LLDP and CDP
+	// neighbours of the seeds
+	seeds           []net.IP
+	allowedNetworks []net.IPNet
 }
 
 type snmpJob struct {
 	subnet    *snmpSubnet
 	currentIP net.IP
+	// neighbors is set for crawl jobs, it receives the management addresses
+	// of the LLDP and CDP neighbours of the device once it has been checked
+	neighbors chan<- []net.IP
 }
 
+// Don't make it a method, to be overridden in tests
+var fetchNeighbors = gosnmplib.FetchNeighborAddresses
+
+// Don't make it a method, to be overridden in tests
+var probeEngineID = gosnmplib.ProbeEngineID
+
 // NewSNMPListener creates a SNMPListener
 func NewSNMPListener(Config) (ServiceListener, error) {
 	snmpConfig, err := snmp.NewListenerConfig()
@@ -140,6 +158,11 @@ var worker = func(l *SNMPListener, jobs <-chan snmpJob) {
 }
 
 func (l *SNMPListener) checkDevice(job snmpJob) {
+	var neighbors []net.IP
+	if job.neighbors != nil {
+		defer func() { job.neighbors <- neighbors }()
+	}
+
 	deviceIP := job.currentIP.String()
 	params, err := job.subnet.config.BuildSNMPParams(deviceIP)
 	if err != nil {
@@ -153,8 +176,21 @@ func (l *SNMPListener) checkDevice(job snmpJob) {
 	} else {
 		defer params.Conn.Close()
 
-		// Since `params<GoSNMP>.ContextEngineID` is empty
-		// `params.GetNext` might lead to multiple SNMP GET calls when using SNMP v3
+		if job.subnet.config.ProbeEngineID && params.Version == gosnmp.Version3 {
+			// The unauthenticated engine discovery is much cheaper than an
+			// authenticated request for devices that don't answer SNMPv3. The
+			// discovered engine is reused by the next requests of the session.
+			engineID, err := probeEngineID(params)
+			if err != nil {
+				log.Debugf("SNMP engine ID discovery to %s error: %v", deviceIP, err)
+				l.deleteService(entityID, job.subnet)
+				return
+			}
+			log.Debugf("SNMP engine ID discovery to %s success: %x", deviceIP, engineID)
+		}
+
+		// Unless the engine was probed, `params<GoSNMP>.ContextEngineID` is empty
+		// and `params.GetNext` might lead to multiple SNMP GET calls when using SNMP v3
 		value, err := params.GetNext([]string{snmp.DeviceReachableGetNextOid})
 		if err != nil {
 			log.Debugf("SNMP get to %s error: %v", deviceIP, err)
@@ -165,6 +201,13 @@ func (l *SNMPListener) checkDevice(job snmpJob) {
 		} else {
 			log.Debugf("SNMP get to %s success: %v", deviceIP, value.Variables[0].Value)
 			l.createService(entityID, job.subnet, deviceIP, true)
+
+			if job.neighbors != nil {
+				neighbors, err = fetchNeighbors(params)
+				if err != nil {
+					log.Debugf("SNMP neighbors of %s error: %v", deviceIP, err)
+				}
+			}
 		}
 	}
 }
@@ -172,16 +215,6 @@ func (l *SNMPListener) checkDevice(job snmpJob) {
 func (l *SNMPListener) checkDevices() {
 	subnets := []snmpSubnet{}
 	for _, config := range l.config.Configs {
-		ipAddr, ipNet, err := net.ParseCIDR(config.Network)
-		if err != nil {
-			log.Errorf("Couldn't parse SNMP network: %s", err)
-			continue
-		}
-
-		startingIP := ipAddr.Mask(ipNet.Mask)
-
-		configHash := config.Digest(config.Network)
-		cacheKey := fmt.Sprintf("snmp:%s", configHash)
 		adIdentifier := config.ADIdentifier
 		if adIdentifier == "" {
 			adIdentifier = "snmp"
@@ -190,15 +223,42 @@ func (l *SNMPListener) checkDevices() {
 		subnet := snmpSubnet{
 			adIdentifier:   adIdentifier,
 			config:         config,
-			startingIP:     startingIP,
-			network:        *ipNet,
-			cacheKey:       cacheKey,
 			devices:        map[string]string{},
 			deviceFailures: map[string]int{},
 		}
+
+		// In crawl mode, the network is optional: it is only scanned if set
+		if config.Network != "" || !config.Crawl.Enabled {
+			ipAddr, ipNet, err := net.ParseCIDR(config.Network)
+			if err != nil {
+				log.Errorf("Couldn't parse SNMP network: %s", err)
+				continue
+			}
+			subnet.startingIP = ipAddr.Mask(ipNet.Mask)
+			subnet.network = *ipNet
+		}
+
+		if config.Crawl.Enabled {
+			if err := initCrawl(&subnet); err != nil {
+				log.Errorf("Couldn't configure SNMP crawl: %s", err)
+				continue
+			}
+		}
+
+		cacheKeyAddress := config.Network
+		if cacheKeyAddress == "" {
+			cacheKeyAddress = strings.Join(config.Crawl.Seeds, tagSeparator)
+		}
+		configHash := config.Digest(cacheKeyAddress)
+		subnet.cacheKey = fmt.Sprintf("snmp:%s", configHash)
+
 		subnets = append(subnets, subnet)
+	}
 
-		l.loadCache(&subnet)
+	// loadCache keeps pointers to the subnets, it must be called once the
+	// slice won't grow anymore
+	for i := range subnets {
+		l.loadCache(&subnets[i])
 	}
 
 	if l.config.Workers == 0 {
@@ -225,6 +285,15 @@ func (l *SNMPListener) checkDevices() {
 		for i := range subnets {
 			// Use `&subnets[i]` to pass the correct pointer address to snmpJob{}
 			subnet = &subnets[i]
+			if len(subnet.seeds) > 0 {
+				if stopped := l.crawl(subnet, jobs); stopped {
+					return
+				}
+			}
+
+			if subnet.startingIP == nil {
+				continue
+			}
 			startingIP := make(net.IP, len(subnet.startingIP))
 			copy(startingIP, subnet.startingIP)
 			for currentIP := startingIP; subnet.network.Contains(currentIP); incrementIP(currentIP) {
@@ -257,6 +326,102 @@ func (l *SNMPListener) checkDevices() {
 	}
 }
 
+// initCrawl parses the crawl configuration of the subnet. Neighbours are only
+// followed inside the allowed networks, which default to the network of the
+// subnet when set. One of them is required, so that the crawl stays bounded.
+func initCrawl(subnet *snmpSubnet) error {
+	crawlConfig := subnet.config.Crawl
+	if len(crawlConfig.Seeds) == 0 {
+		return errors.New("no seed addresses given")
+	}
+	if crawlConfig.MaxDepth < 0 {
+		return fmt.Errorf("invalid max depth %d", crawlConfig.MaxDepth)
+	}
+	for _, seed := range crawlConfig.Seeds {
+		ip := net.ParseIP(seed)
+		if ip == nil {
+			return fmt.Errorf("invalid seed address %q", seed)
+		}
+		subnet.seeds = append(subnet.seeds, ip)
+	}
+
+	allowedNetworks := crawlConfig.AllowedNetworks
+	if len(allowedNetworks) == 0 && subnet.config.Network != "" {
+		allowedNetworks = []string{subnet.config.Network}
+	}
+	if len(allowedNetworks) == 0 {
+		return errors.New("allowed_networks is required when network_address is not set")
+	}
+	for _, network := range allowedNetworks {
+		_, ipNet, err := net.ParseCIDR(network)
+		if err != nil {
+			return fmt.Errorf("invalid allowed network %q: %w", network, err)
+		}
+		subnet.allowedNetworks = append(subnet.allowedNetworks, *ipNet)
+	}
+	return nil
+}
+
+// isCrawlAllowed returns whether a device found while crawling should be
+// checked. Only the devices of the allowed networks are.
+func (s *snmpSubnet) isCrawlAllowed(ip net.IP) bool {
+	if s.config.IsIPIgnored(ip) {
+		return false
+	}
+	for _, network := range s.allowedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// crawl checks the seeds of the subnet, then follows the LLDP and CDP
+// neighbours of the devices it finds, level by level, up to the maximum depth.
+// It returns true if the listener was stopped.
+func (l *SNMPListener) crawl(subnet *snmpSubnet, jobs chan<- snmpJob) bool {
+	visited := map[string]bool{}
+	frontier := subnet.seeds
+	for depth := 0; depth <= subnet.config.Crawl.MaxDepth && len(frontier) > 0; depth++ {
+		// The neighbours of the devices at the last level are not needed
+		var neighbors chan []net.IP
+		if depth < subnet.config.Crawl.MaxDepth {
+			neighbors = make(chan []net.IP, len(frontier))
+		}
+
+		pending := 0
+		for _, ip := range frontier {
+			if visited[ip.String()] || !subnet.isCrawlAllowed(ip) {
+				continue
+			}
+			visited[ip.String()] = true
+
+			jobs <- snmpJob{
+				subnet:    subnet,
+				currentIP: ip,
+				neighbors: neighbors,
+			}
+			pending++
+
+			select {
+			case <-l.stop:
+				return true
+			default:
+			}
+		}
+
+		if neighbors == nil {
+			break
+		}
+		frontier = nil
+		for ; pending > 0; pending-- {
+			frontier = append(frontier, <-neighbors...)
+		}
+		log.Debugf("SNMP crawl found %d neighbors at depth %d", len(frontier), depth+1)
+	}
+	return false
+}
+
 func (l *SNMPListener) createService(entityID string, subnet *snmpSubnet, deviceIP string, writeCache bool) {
 	l.Lock()
 	defer l.Unlock()
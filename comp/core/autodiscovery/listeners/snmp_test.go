@@ -7,8 +7,10 @@ package listeners
 
 import (
 	"fmt"
+	"net"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/config"
 	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
@@ -157,6 +159,110 @@ func TestSNMPListenerIgnoredAdresses(t *testing.T) {
 	assert.Equal(t, "192.168.0.0", job.subnet.startingIP.String())
 }
 
+func TestSNMPListenerCrawl(t *testing.T) {
+	newSvc := make(chan Service, 10)
+	delSvc := make(chan Service, 10)
+	testChan := make(chan snmpJob, 10)
+
+	maxDepth := 2
+	snmpConfig := snmp.Config{
+		Community:          "public",
+		IgnoredIPAddresses: map[string]bool{"10.0.0.3": true},
+		Crawl: snmp.CrawlConfig{
+			Enabled:         true,
+			Seeds:           []string{"10.0.0.1"},
+			MaxDepthConfig:  &maxDepth,
+			AllowedNetworks: []string{"10.0.0.0/24"},
+		},
+	}
+	listenerConfig := snmp.ListenerConfig{
+		Configs: []snmp.Config{snmpConfig},
+		Workers: 1,
+	}
+
+	mockConfig := configmock.New(t)
+	mockConfig.SetWithoutSource("network_devices.autodiscovery", listenerConfig)
+
+	topology := map[string][]net.IP{
+		"10.0.0.1": {net.ParseIP("10.0.0.2"), net.ParseIP("10.0.0.3"), net.ParseIP("192.168.1.1")},
+		"10.0.0.2": {net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.4")},
+		"10.0.0.4": {net.ParseIP("10.0.0.5")},
+	}
+	worker = func(l *SNMPListener, jobs <-chan snmpJob) {
+		for {
+			job := <-jobs
+			testChan <- job
+			if job.neighbors != nil {
+				job.neighbors <- topology[job.currentIP.String()]
+			}
+		}
+	}
+
+	l, err := NewSNMPListener(&config.Listeners{})
+	assert.Equal(t, nil, err)
+	l.Listen(newSvc, delSvc)
+
+	// depth 0
+	job := <-testChan
+	assert.Equal(t, "10.0.0.1", job.currentIP.String())
+	assert.NotNil(t, job.neighbors)
+
+	// depth 1: 10.0.0.3 is ignored and 192.168.1.1 is not in the allowed networks
+	job = <-testChan
+	assert.Equal(t, "10.0.0.2", job.currentIP.String())
+	assert.NotNil(t, job.neighbors)
+
+	// depth 2: 10.0.0.1 was already visited, the neighbors of the last level
+	// are not fetched
+	job = <-testChan
+	assert.Equal(t, "10.0.0.4", job.currentIP.String())
+	assert.Nil(t, job.neighbors)
+	assert.Nil(t, job.subnet.startingIP)
+
+	select {
+	case job = <-testChan:
+		assert.Failf(t, "unexpected job", "%s", job.currentIP)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestSNMPListenerCrawlInvalidSeed(t *testing.T) {
+	subnet := snmpSubnet{
+		config: snmp.Config{
+			Network: "10.0.0.0/24",
+			Crawl: snmp.CrawlConfig{
+				Enabled: true,
+				Seeds:   []string{"not-an-ip"},
+			},
+		},
+	}
+	assert.EqualError(t, initCrawl(&subnet), `invalid seed address "not-an-ip"`)
+
+	subnet.config.Crawl.Seeds = nil
+	assert.EqualError(t, initCrawl(&subnet), "no seed addresses given")
+
+	subnet.config.Crawl.Seeds = []string{"10.0.0.1"}
+	subnet.config.Crawl.MaxDepth = -1
+	assert.EqualError(t, initCrawl(&subnet), "invalid max depth -1")
+
+	// allowed networks default to the subnet network
+	subnet.config.Crawl.MaxDepth = 0
+	assert.NoError(t, initCrawl(&subnet))
+	assert.True(t, subnet.isCrawlAllowed(net.ParseIP("10.0.0.42")))
+	assert.False(t, subnet.isCrawlAllowed(net.ParseIP("10.0.1.1")))
+
+	// the crawl must be bounded by allowed networks
+	subnet = snmpSubnet{
+		config: snmp.Config{
+			Crawl: snmp.CrawlConfig{
+				Enabled: true,
+				Seeds:   []string{"10.0.0.1"},
+			},
+		},
+	}
+	assert.EqualError(t, initCrawl(&subnet), "allowed_networks is required when network_address is not set")
+}
+
 func TestExtraConfig(t *testing.T) {
 	truePtr := true
 	fivePtr := 5
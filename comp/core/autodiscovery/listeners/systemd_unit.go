@@ -0,0 +1,64 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !serverless
+
+package listeners
+
+import (
+	"errors"
+
+	"github.com/DataDog/datadog-agent/comp/core/tagger"
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+)
+
+// systemdUnitADIdentifierPrefix is the prefix of the AD identifier of systemd
+// units, templates can target a unit with `ad_identifiers: [_systemd.nginx.service]`.
+const systemdUnitADIdentifierPrefix = "_systemd."
+
+// SystemdUnitListener listens to systemd units through a subscription to the
+// workloadmeta store.
+type SystemdUnitListener struct {
+	workloadmetaListener
+}
+
+// NewSystemdUnitListener returns a new SystemdUnitListener.
+func NewSystemdUnitListener(_ Config, wmeta optional.Option[workloadmeta.Component]) (ServiceListener, error) {
+	const name = "ad-systemdunitlistener"
+	l := &SystemdUnitListener{}
+	filter := workloadmeta.NewFilterBuilder().
+		SetSource(workloadmeta.SourceAll).
+		AddKind(workloadmeta.KindSystemdUnit).Build()
+
+	wmetaInstance, ok := wmeta.Get()
+	if !ok {
+		return nil, errors.New("workloadmeta store is not initialized")
+	}
+	var err error
+	l.workloadmetaListener, err = newWorkloadmetaListener(name, filter, l.createSystemdUnitService, wmetaInstance)
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+func (l *SystemdUnitListener) createSystemdUnitService(entity workloadmeta.Entity) {
+	unit := entity.(*workloadmeta.SystemdUnit)
+
+	svc := &service{
+		entity:        unit,
+		tagsHash:      tagger.GetEntityHash(systemdUnitTaggerEntityName(unit.ID), tagger.ChecksCardinality()),
+		adIdentifiers: []string{systemdUnitADIdentifierPrefix + unit.ID},
+		// units run on the host, checks reach them through the loopback interface
+		hosts: map[string]string{"host": "127.0.0.1"},
+		pid:   int(unit.MainPID),
+		ready: unit.ActiveState == "active",
+	}
+
+	svcID := buildSvcID(unit.GetID())
+	l.AddService(svcID, svc, "")
+}
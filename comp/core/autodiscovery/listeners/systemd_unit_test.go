@@ -0,0 +1,55 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !serverless
+
+package listeners
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+)
+
+func TestCreateSystemdUnitService(t *testing.T) {
+	unit := &workloadmeta.SystemdUnit{
+		EntityID: workloadmeta.EntityID{
+			Kind: workloadmeta.KindSystemdUnit,
+			ID:   "nginx.service",
+		},
+		ActiveState: "active",
+		MainPID:     1234,
+	}
+
+	wlm := newTestWorkloadmetaListener(t)
+	listener := &SystemdUnitListener{workloadmetaListener: wlm}
+
+	listener.createSystemdUnitService(unit)
+
+	svc := wlm.services["systemd_unit://nginx.service"].service
+	assert.Equal(t, "systemd_unit://nginx.service", svc.GetServiceID())
+
+	adIdentifiers, _ := svc.GetADIdentifiers(context.Background())
+	assert.Equal(t, []string{"_systemd.nginx.service"}, adIdentifiers)
+
+	pid, _ := svc.GetPid(context.Background())
+	assert.Equal(t, 1234, pid)
+	assert.True(t, svc.IsReady(context.Background()))
+
+	wlm.assertServices(map[string]wlmListenerSvc{
+		"systemd_unit://nginx.service": {
+			service: &service{
+				entity:        unit,
+				adIdentifiers: []string{"_systemd.nginx.service"},
+				hosts:         map[string]string{"host": "127.0.0.1"},
+				pid:           1234,
+				ready:         true,
+			},
+		},
+	})
+}
@@ -18,12 +18,18 @@ import (
 	ddErrors "github.com/DataDog/datadog-agent/pkg/errors"
 	"github.com/DataDog/datadog-agent/pkg/util/clusteragent"
 	"github.com/DataDog/datadog-agent/pkg/util/hostname"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/hostinfo"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
 const (
 	defaultGraceDuration = 60 * time.Second
 	postStatusTimeout    = time.Duration(5 * time.Second)
+
+	zoneNodeLabel         = "topology.kubernetes.io/zone"
+	regionNodeLabel       = "topology.kubernetes.io/region"
+	legacyZoneNodeLabel   = "failure-domain.beta.kubernetes.io/zone"
+	legacyRegionNodeLabel = "failure-domain.beta.kubernetes.io/region"
 )
 
 // ClusterChecksConfigProvider implements the ConfigProvider interface
@@ -35,6 +41,8 @@ type ClusterChecksConfigProvider struct {
 	heartbeat        time.Time
 	lastChange       int64
 	identifier       string
+	zone             string
+	region           string
 	flushedConfigs   bool
 }
 
@@ -64,6 +72,8 @@ func NewClusterChecksConfigProvider(providerConfig *config.ConfigurationProvider
 		}
 	}
 
+	c.zone, c.region = getNodeTopology()
+
 	if providerConfig.GraceTimeSeconds > 0 {
 		c.graceDuration = time.Duration(providerConfig.GraceTimeSeconds) * time.Second
 	}
@@ -114,6 +124,8 @@ func (c *ClusterChecksConfigProvider) IsUpToDate(ctx context.Context) (bool, err
 
 	status := types.NodeStatus{
 		LastChange: c.lastChange,
+		Zone:       c.zone,
+		Region:     c.region,
 	}
 
 	reply, err := c.dcaClient.PostClusterCheckStatus(ctx, c.identifier, status)
@@ -218,6 +230,52 @@ func (c *ClusterChecksConfigProvider) postHeartbeat(ctx context.Context) error {
 	return err
 }
 
+// getNodeTopology returns the zone and region where the agent runs, so that
+// the cluster-agent can dispatch checks according to their topology affinity.
+// They are taken from the configuration, or from the well-known topology labels
+// of the node when running on Kubernetes.
+func getNodeTopology() (string, string) {
+	zone := config.Datadog().GetString("cluster_checks.node_zone")
+	region := config.Datadog().GetString("cluster_checks.node_region")
+	if zone != "" && region != "" {
+		return zone, region
+	}
+
+	if !config.IsKubernetes() {
+		return zone, region
+	}
+
+	nodeInfo, err := hostinfo.NewNodeInfo()
+	if err != nil {
+		log.Debugf("Cannot get node info to detect the node topology: %v", err)
+		return zone, region
+	}
+
+	labels, err := nodeInfo.GetNodeLabels(context.TODO())
+	if err != nil {
+		log.Debugf("Cannot get node labels to detect the node topology: %v", err)
+		return zone, region
+	}
+
+	if zone == "" {
+		zone = firstNonEmptyLabel(labels, zoneNodeLabel, legacyZoneNodeLabel)
+	}
+	if region == "" {
+		region = firstNonEmptyLabel(labels, regionNodeLabel, legacyRegionNodeLabel)
+	}
+
+	return zone, region
+}
+
+func firstNonEmptyLabel(labels map[string]string, names ...string) string {
+	for _, name := range names {
+		if value := labels[name]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
 // GetConfigErrors is not implemented for the ClusterChecksConfigProvider
 func (c *ClusterChecksConfigProvider) GetConfigErrors() map[string]ErrorMsgSet {
 	return make(map[string]ErrorMsgSet)
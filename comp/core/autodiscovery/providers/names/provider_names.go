@@ -22,6 +22,7 @@ const (
 	KubeServicesFile   = "kubernetes-services-file"
 	KubeEndpoints      = "kubernetes-endpoints"
 	KubeEndpointsFile  = "kubernetes-endpoints-file"
+	Nomad              = "nomad"
 	PrometheusPods     = "prometheus-pods"
 	PrometheusServices = "prometheus-services"
 	RemoteConfig       = "remote-config"
@@ -44,6 +45,7 @@ const (
 	KubeServicesFileRegisterName   = "kube_services_file"
 	KubeEndpointsRegisterName      = "kube_endpoints"
 	KubeEndpointsFileRegisterName  = "kube_endpoints_file"
+	NomadRegisterName              = "nomad"
 	PrometheusPodsRegisterName     = "prometheus_pods"
 	PrometheusServicesRegisterName = "prometheus_services"
 	RemoteConfigRegisterName       = "remote_config"
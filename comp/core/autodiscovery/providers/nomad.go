@@ -0,0 +1,155 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build nomad
+
+package providers
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/common/utils"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/providers/names"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/nomad"
+)
+
+// NomadConfigProvider implements the ConfigProvider interface for the
+// templates defined in the meta and the service tags of the Nomad tasks
+// running on the local node. They use the same keys as container labels, for
+// instance "com.datadoghq.ad.checks".
+type NomadConfigProvider struct {
+	client       *api.Client
+	nodeID       string
+	lastIndex    uint64
+	configErrors map[string]ErrorMsgSet
+	mu           sync.RWMutex
+}
+
+// NewNomadConfigProvider returns a new NomadConfigProvider
+func NewNomadConfigProvider(*config.ConfigurationProviders) (ConfigProvider, error) {
+	client, err := nomad.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &NomadConfigProvider{
+		client:       client,
+		configErrors: make(map[string]ErrorMsgSet),
+	}, nil
+}
+
+// String returns a string representation of the NomadConfigProvider
+func (p *NomadConfigProvider) String() string {
+	return names.Nomad
+}
+
+// Collect retrieves the templates of the running tasks
+func (p *NomadConfigProvider) Collect(ctx context.Context) ([]integration.Config, error) {
+	if err := p.ensureNodeID(); err != nil {
+		return nil, err
+	}
+
+	allocs, index, err := nomad.NodeAllocations(ctx, p.client, p.nodeID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var configs []integration.Config
+	configErrors := make(map[string]ErrorMsgSet)
+
+	for _, task := range nomad.RunningTasks(allocs) {
+		entityName := task.EntityName()
+
+		taskConfigs, errs := utils.ExtractTemplatesFromContainerLabels(entityName, templateLabels(task))
+		if len(errs) > 0 {
+			errMsgSet := make(ErrorMsgSet)
+			for _, err := range errs {
+				errMsgSet[err.Error()] = struct{}{}
+			}
+			configErrors[entityName] = errMsgSet
+		}
+
+		for idx := range taskConfigs {
+			taskConfigs[idx].Source = names.Nomad + ":" + entityName
+		}
+
+		configs = append(configs, taskConfigs...)
+	}
+
+	p.mu.Lock()
+	p.lastIndex = index
+	p.configErrors = configErrors
+	p.mu.Unlock()
+
+	return configs, nil
+}
+
+// IsUpToDate returns whether the allocations of the node have changed since
+// the last call to Collect
+func (p *NomadConfigProvider) IsUpToDate(ctx context.Context) (bool, error) {
+	if err := p.ensureNodeID(); err != nil {
+		return false, err
+	}
+
+	_, index, err := nomad.NodeAllocations(ctx, p.client, p.nodeID, 0)
+	if err != nil {
+		return false, err
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return index == p.lastIndex, nil
+}
+
+// GetConfigErrors returns a map of configuration errors for each task
+func (p *NomadConfigProvider) GetConfigErrors() map[string]ErrorMsgSet {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	errors := make(map[string]ErrorMsgSet, len(p.configErrors))
+	for entity, errs := range p.configErrors {
+		errors[entity] = errs
+	}
+
+	return errors
+}
+
+func (p *NomadConfigProvider) ensureNodeID() error {
+	if p.nodeID != "" {
+		return nil
+	}
+
+	nodeID, err := nomad.LocalNodeID(p.client)
+	if err != nil {
+		return err
+	}
+
+	p.nodeID = nodeID
+	return nil
+}
+
+// templateLabels returns the labels holding the templates of a task: its
+// meta, overridden by its "key=value" service tags.
+func templateLabels(task *nomad.Task) map[string]string {
+	labels := make(map[string]string, len(task.Meta))
+	for k, v := range task.Meta {
+		labels[k] = v
+	}
+
+	for _, tag := range task.ServiceTags {
+		if k, v, found := strings.Cut(tag, "="); found {
+			labels[k] = v
+		}
+	}
+
+	return labels
+}
@@ -0,0 +1,13 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !nomad
+
+package providers
+
+import "github.com/DataDog/datadog-agent/pkg/config"
+
+// NewNomadConfigProvider is not supported without the nomad build tag
+var NewNomadConfigProvider func(providerConfig *config.ConfigurationProviders) (ConfigProvider, error)
@@ -0,0 +1,96 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build nomad
+
+package providers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+	"github.com/DataDog/datadog-agent/pkg/util/nomad/nomadmock"
+)
+
+func TestNomadConfigProvider(t *testing.T) {
+	groupName := "cache"
+	alloc := &api.Allocation{
+		ID:           "alloc1",
+		Namespace:    "default",
+		JobID:        "backend",
+		TaskGroup:    groupName,
+		ClientStatus: api.AllocClientStatusRunning,
+		Job: &api.Job{
+			Meta: map[string]string{
+				"com.datadoghq.ad.checks": `{"redisdb": {"instances": [{"host": "%%host%%", "port": "%%port%%"}]}}`,
+			},
+			TaskGroups: []*api.TaskGroup{
+				{
+					Name: &groupName,
+					Tasks: []*api.Task{
+						{
+							Name: "redis",
+							Services: []*api.Service{
+								{Name: "redis", Tags: []string{`com.datadoghq.ad.logs=[{"service": "redis"}]`, "primary"}},
+							},
+						},
+					},
+				},
+			},
+		},
+		TaskStates: map[string]*api.TaskState{
+			"redis": {State: "running"},
+		},
+	}
+
+	server := nomadmock.NewFakeNomadServer("node1")
+	server.SetAllocations(alloc)
+
+	cfg := configmock.New(t)
+	cfg.SetWithoutSource("nomad.address", server.Start(t))
+
+	provider, err := NewNomadConfigProvider(nil)
+	require.NoError(t, err)
+	p := provider.(*NomadConfigProvider)
+
+	configs, err := p.Collect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []integration.Config{
+		{
+			Name:          "redisdb",
+			ADIdentifiers: []string{"nomad_task://alloc1/redis"},
+			InitConfig:    integration.Data("{}"),
+			Instances:     []integration.Data{integration.Data(`{"host":"%%host%%","port":"%%port%%"}`)},
+			Source:        "nomad:nomad_task://alloc1/redis",
+		},
+		{
+			Name:          "redisdb",
+			ADIdentifiers: []string{"nomad_task://alloc1/redis"},
+			LogsConfig:    integration.Data(`[{"service":"redis"}]`),
+			Source:        "nomad:nomad_task://alloc1/redis",
+		},
+	}, configs)
+	assert.Empty(t, p.GetConfigErrors())
+
+	upToDate, err := p.IsUpToDate(context.Background())
+	require.NoError(t, err)
+	assert.True(t, upToDate)
+
+	server.SetAllocations()
+
+	upToDate, err = p.IsUpToDate(context.Background())
+	require.NoError(t, err)
+	assert.False(t, upToDate)
+
+	configs, err = p.Collect(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, configs)
+}
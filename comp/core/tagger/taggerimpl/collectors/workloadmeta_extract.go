@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"strconv"
 	"strings"
 
 	k8smetadata "github.com/DataDog/datadog-agent/comp/core/tagger/k8s_metadata"
@@ -157,6 +158,8 @@ func (c *WorkloadMetaCollector) processEvents(evBundle workloadmeta.EventBundle)
 				// tagInfos = append(tagInfos, c.handleProcess(ev)...) No tags for now
 			case workloadmeta.KindKubernetesDeployment:
 				// tagInfos = append(tagInfos, c.handleDeployment(ev)...) No tags for now
+			case workloadmeta.KindSystemdUnit:
+				tagInfos = append(tagInfos, c.handleSystemdUnit(ev)...)
 			default:
 				log.Errorf("cannot handle event for entity %q with kind %q", entityID.ID, entityID.Kind)
 			}
@@ -311,6 +314,51 @@ func (c *WorkloadMetaCollector) handleHostTags(ev workloadmeta.Event) []*types.T
 	}
 }
 
+// handleSystemdUnit tags the unit and the processes it runs, so that
+// telemetry emitted by host services can be attributed to their unit.
+func (c *WorkloadMetaCollector) handleSystemdUnit(ev workloadmeta.Event) []*types.TagInfo {
+	unit := ev.Entity.(*workloadmeta.SystemdUnit)
+
+	tagList := taglist.NewTagList()
+	tagList.AddLow(tags.SystemdUnit, unit.ID)
+
+	low, orch, high, standard := tagList.Compute()
+	tagInfos := []*types.TagInfo{
+		{
+			Source:               systemdUnitSource,
+			Entity:               buildTaggerEntityID(unit.EntityID),
+			HighCardTags:         high,
+			OrchestratorCardTags: orch,
+			LowCardTags:          low,
+			StandardTags:         standard,
+		},
+	}
+
+	pids := unit.PIDs
+	if len(pids) == 0 && unit.MainPID > 0 {
+		pids = []int32{unit.MainPID}
+	}
+
+	for _, pid := range pids {
+		processID := workloadmeta.EntityID{
+			Kind: workloadmeta.KindProcess,
+			ID:   strconv.Itoa(int(pid)),
+		}
+		c.registerChild(unit.EntityID, processID)
+
+		tagInfos = append(tagInfos, &types.TagInfo{
+			Source:               systemdUnitSource,
+			Entity:               buildTaggerEntityID(processID),
+			HighCardTags:         high,
+			OrchestratorCardTags: orch,
+			LowCardTags:          low,
+			StandardTags:         standard,
+		})
+	}
+
+	return tagInfos
+}
+
 func (c *WorkloadMetaCollector) labelsToTags(labels map[string]string, tags *taglist.TagList) {
 	// standard tags from labels
 	c.extractFromMapWithFn(labels, standardDockerLabels, tags.AddStandard)
@@ -783,6 +831,8 @@ func buildTaggerEntityID(entityID workloadmeta.EntityID) string {
 		return fmt.Sprintf("host://%s", entityID.ID)
 	case workloadmeta.KindKubernetesMetadata:
 		return fmt.Sprintf("kubernetes_metadata://%s", entityID.ID)
+	case workloadmeta.KindSystemdUnit:
+		return fmt.Sprintf("systemd_unit://%s", entityID.ID)
 	default:
 		log.Errorf("can't recognize entity %q with kind %q; trying %s://%s as tagger entity",
 			entityID.ID, entityID.Kind, entityID.ID, entityID.Kind)
@@ -34,6 +34,7 @@ const (
 	processSource        = workloadmetaCollectorName + "-" + string(workloadmeta.KindProcess)
 	hostSource           = workloadmetaCollectorName + "-" + string(workloadmeta.KindHost)
 	kubeMetadataSource   = workloadmetaCollectorName + "-" + string(workloadmeta.KindKubernetesMetadata)
+	systemdUnitSource    = workloadmetaCollectorName + "-" + string(workloadmeta.KindSystemdUnit)
 
 	clusterTagNamePrefix = "kube_cluster_name"
 )
@@ -215,4 +216,5 @@ func init() {
 	CollectorPriorities[taskSource] = types.NodeOrchestrator
 	CollectorPriorities[containerSource] = types.NodeRuntime
 	CollectorPriorities[containerImageSource] = types.NodeRuntime
+	CollectorPriorities[systemdUnitSource] = types.NodeRuntime
 }
@@ -2022,6 +2022,60 @@ func TestHandleContainerImage(t *testing.T) {
 	}
 }
 
+func TestHandleSystemdUnit(t *testing.T) {
+	store := fxutil.Test[workloadmetamock.Mock](t, fx.Options(
+		logimpl.MockModule(),
+		config.MockModule(),
+		fx.Supply(workloadmeta.NewParams()),
+		workloadmetafxmock.MockModule(),
+	))
+
+	unit := &workloadmeta.SystemdUnit{
+		EntityID: workloadmeta.EntityID{
+			Kind: workloadmeta.KindSystemdUnit,
+			ID:   "nginx.service",
+		},
+		ActiveState: "active",
+		MainPID:     1234,
+		PIDs:        []int32{1234, 1235},
+	}
+
+	collector := NewWorkloadMetaCollector(context.Background(), store, nil)
+
+	actual := collector.handleSystemdUnit(workloadmeta.Event{
+		Type:   workloadmeta.EventTypeSet,
+		Entity: unit,
+	})
+
+	expectedTagInfo := func(entity string) *types.TagInfo {
+		return &types.TagInfo{
+			Source:               systemdUnitSource,
+			Entity:               entity,
+			HighCardTags:         []string{},
+			OrchestratorCardTags: []string{},
+			LowCardTags:          []string{"systemd_unit:nginx.service"},
+			StandardTags:         []string{},
+		}
+	}
+	assertTagInfoListEqual(t, []*types.TagInfo{
+		expectedTagInfo("systemd_unit://nginx.service"),
+		expectedTagInfo("process://1234"),
+		expectedTagInfo("process://1235"),
+	}, actual)
+
+	// processes are deleted with their unit
+	deleted := collector.handleDelete(workloadmeta.Event{
+		Type:   workloadmeta.EventTypeUnset,
+		Entity: unit,
+	})
+	assertTagInfoListEqual(t, []*types.TagInfo{
+		{Source: systemdUnitSource, Entity: "systemd_unit://nginx.service", DeleteEntity: true},
+		{Source: systemdUnitSource, Entity: "process://1234", DeleteEntity: true},
+		{Source: systemdUnitSource, Entity: "process://1235", DeleteEntity: true},
+	}, deleted)
+	assert.Empty(t, collector.children)
+}
+
 func TestHandleDelete(t *testing.T) {
 	const (
 		podName       = "datadog-agent-foobar"
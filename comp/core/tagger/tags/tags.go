@@ -110,6 +110,9 @@ const (
 	// Language is the tag for the process language
 	Language = "language"
 
+	// SystemdUnit is the tag for the systemd unit running a process
+	SystemdUnit = "systemd_unit"
+
 	// MarathonApp is the tag for the Marathon app ID
 	MarathonApp = "marathon_app"
 
@@ -0,0 +1,203 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build cri
+
+package crio
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/DataDog/datadog-agent/comp/core/workloadmeta/collectors/util"
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	"github.com/DataDog/datadog-agent/pkg/util/containers"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// containerInfo is the verbose information of a container status returned by
+// CRI-O under the "info" key.
+type containerInfo struct {
+	SandboxID   string      `json:"sandboxID"`
+	Pid         int         `json:"pid"`
+	RuntimeSpec *specs.Spec `json:"runtimeSpec"`
+}
+
+// buildContainer generates a workloadmeta.Container from the status of a
+// CRI-O container. c.mu must be held.
+func (c *collector) buildContainer(containerID string, podSandboxID string) (*workloadmeta.Container, error) {
+	resp, err := c.client.ContainerStatus(containerID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := resp.GetStatus()
+	if status == nil {
+		return nil, fmt.Errorf("empty status for container %s", containerID)
+	}
+
+	info := parseContainerInfo(containerID, resp.GetInfo())
+	if podSandboxID == "" {
+		podSandboxID = info.SandboxID
+	}
+
+	imageName := status.GetImage().GetImage()
+	imageID := c.resolveImageID(imageName, status.ImageRef)
+
+	image, err := workloadmeta.NewContainerImage(imageID, imageName)
+	if err != nil {
+		log.Debugf("cannot split image name %q: %s", imageName, err)
+	}
+
+	image.RepoDigest = util.ExtractRepoDigestFromImage(imageID, image.Registry, c.store) // "sha256:digest"
+	if image.RepoDigest == "" {
+		// CRI-O returns the repo digest reference of the image as image ref
+		if _, digest, found := strings.Cut(status.ImageRef, "@"); found {
+			image.RepoDigest = digest
+		}
+	}
+
+	container := &workloadmeta.Container{
+		EntityID: workloadmeta.EntityID{
+			Kind: workloadmeta.KindContainer,
+			ID:   status.Id,
+		},
+		EntityMeta: workloadmeta.EntityMeta{
+			Name:   status.GetMetadata().GetName(),
+			Labels: status.Labels,
+		},
+		Image:         image,
+		Runtime:       workloadmeta.ContainerRuntimeCRIO,
+		RuntimeFlavor: workloadmeta.ContainerRuntimeFlavorDefault,
+		State:         extractState(status),
+		PID:           info.Pid,
+	}
+
+	if ip := c.sandboxIP(podSandboxID); ip != "" {
+		container.NetworkIPs = map[string]string{"": ip}
+	}
+
+	if spec := info.RuntimeSpec; spec != nil {
+		container.Hostname = spec.Hostname
+		container.EnvVars = extractEnvVars(spec)
+		if spec.Linux != nil {
+			container.CgroupPath = extractCgroupPath(spec.Linux.CgroupsPath)
+		}
+	}
+
+	return container, nil
+}
+
+// sandboxIP returns the IP of a pod sandbox, caching it as it doesn't change
+// during the lifetime of the sandbox. c.mu must be held.
+func (c *collector) sandboxIP(podSandboxID string) string {
+	if podSandboxID == "" {
+		return ""
+	}
+
+	if ip, found := c.sandboxIPs[podSandboxID]; found {
+		return ip
+	}
+
+	resp, err := c.client.PodSandboxStatus(podSandboxID)
+	if err != nil {
+		log.Debugf("Cannot get status of CRI-O pod sandbox %s: %v", podSandboxID, err)
+		return ""
+	}
+
+	// Host network sandboxes have no IP, which is cached as well
+	ip := resp.GetStatus().GetNetwork().GetIp()
+	c.sandboxIPs[podSandboxID] = ip
+
+	return ip
+}
+
+func parseContainerInfo(containerID string, info map[string]string) containerInfo {
+	var parsed containerInfo
+
+	raw, found := info["info"]
+	if !found {
+		return parsed
+	}
+
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		log.Debugf("Cannot parse verbose status of CRI-O container %s: %v", containerID, err)
+	}
+
+	return parsed
+}
+
+func extractState(status *criv1.ContainerStatus) workloadmeta.ContainerState {
+	state := workloadmeta.ContainerState{
+		Running:   status.State == criv1.ContainerState_CONTAINER_RUNNING,
+		Status:    extractStatus(status.State),
+		CreatedAt: time.Unix(0, status.CreatedAt),
+	}
+
+	if status.StartedAt > 0 {
+		state.StartedAt = time.Unix(0, status.StartedAt)
+	}
+
+	if status.FinishedAt > 0 {
+		state.FinishedAt = time.Unix(0, status.FinishedAt)
+	}
+
+	if status.State == criv1.ContainerState_CONTAINER_EXITED {
+		exitCode := int64(status.ExitCode)
+		state.ExitCode = &exitCode
+	}
+
+	return state
+}
+
+func extractStatus(state criv1.ContainerState) workloadmeta.ContainerStatus {
+	switch state {
+	case criv1.ContainerState_CONTAINER_CREATED:
+		return workloadmeta.ContainerStatusCreated
+	case criv1.ContainerState_CONTAINER_RUNNING:
+		return workloadmeta.ContainerStatusRunning
+	case criv1.ContainerState_CONTAINER_EXITED:
+		return workloadmeta.ContainerStatusStopped
+	}
+
+	return workloadmeta.ContainerStatusUnknown
+}
+
+func extractEnvVars(spec *specs.Spec) map[string]string {
+	envs := make(map[string]string)
+	if spec.Process == nil {
+		return envs
+	}
+
+	filter := containers.EnvVarFilterFromConfig()
+	for _, env := range spec.Process.Env {
+		name, value, found := strings.Cut(env, "=")
+		if !found {
+			continue
+		}
+
+		if filter.IsIncluded(name) {
+			envs[name] = value
+		}
+	}
+
+	return envs
+}
+
+// extractCgroupPath extracts the cgroup path from the cgroupsPath of the
+// runtime spec. With the systemd cgroup manager, it has the form
+// "slice:prefix:name", e.g. "kubepods-burstable-pod1234.slice:crio:5678".
+func extractCgroupPath(path string) string {
+	res := path
+	if l := strings.Split(path, ":"); len(l) == 3 {
+		res = l[0] + "/" + l[1] + "-" + l[2] + ".scope"
+	}
+	return res
+}
@@ -0,0 +1,344 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build cri
+
+// Package crio implements the CRI-O Workloadmeta collector.
+package crio
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"go.uber.org/fx"
+	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	agentErrors "github.com/DataDog/datadog-agent/pkg/errors"
+	"github.com/DataDog/datadog-agent/pkg/status/health"
+	"github.com/DataDog/datadog-agent/pkg/util/containers/cri"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	collectorID   = "crio"
+	componentName = "workloadmeta-crio"
+
+	// crioRuntimeName is the runtime name returned by CRI-O in the CRI
+	// Version call
+	crioRuntimeName = "cri-o"
+)
+
+// criClient is the subset of the CRI API used by the collector.
+type criClient interface {
+	ListContainers() ([]*criv1.Container, error)
+	ContainerStatus(containerID string) (*criv1.ContainerStatusResponse, error)
+	ListPodSandboxes() ([]*criv1.PodSandbox, error)
+	PodSandboxStatus(podSandboxID string) (*criv1.PodSandboxStatusResponse, error)
+	ListImages() ([]*criv1.Image, error)
+	ImageStatus(image string) (*criv1.ImageStatusResponse, error)
+	GetContainerEvents(ctx context.Context) (criv1.RuntimeService_GetContainerEventsClient, error)
+}
+
+type collector struct {
+	id      string
+	store   workloadmeta.Component
+	catalog workloadmeta.AgentType
+	client  criClient
+
+	// mu serializes the updates coming from Pull and from the container
+	// events stream, and protects the fields below.
+	mu sync.Mutex
+
+	// streaming is true while the container events stream is up. Containers
+	// are then updated from the events, otherwise Pull lists them.
+	streaming      bool
+	seenContainers map[string]struct{}
+	sandboxIPs     map[string]string
+
+	// imageSpecs caches the OCI config of the images by image ID, as getting
+	// it requires a verbose status call and it never changes for a given ID.
+	imageSpecs map[string]*ocispec.Image
+	// imageIDs maps image references (ID, tags and digests) to image IDs.
+	imageIDs map[string]string
+}
+
+// NewCollector returns a new CRI-O collector provider and an error
+func NewCollector() (workloadmeta.CollectorProvider, error) {
+	return workloadmeta.CollectorProvider{
+		Collector: newCollector(),
+	}, nil
+}
+
+// GetFxOptions returns the FX framework options for the collector
+func GetFxOptions() fx.Option {
+	return fx.Provide(NewCollector)
+}
+
+func newCollector() *collector {
+	return &collector{
+		id:             collectorID,
+		catalog:        workloadmeta.NodeAgent | workloadmeta.ProcessAgent,
+		seenContainers: make(map[string]struct{}),
+		sandboxIPs:     make(map[string]string),
+		imageSpecs:     make(map[string]*ocispec.Image),
+		imageIDs:       make(map[string]string),
+	}
+}
+
+func (c *collector) Start(ctx context.Context, store workloadmeta.Component) error {
+	if !config.IsFeaturePresent(config.Cri) {
+		return agentErrors.NewDisabled(componentName, "Agent is not running on CRI")
+	}
+
+	criUtil, err := cri.GetUtil()
+	if err != nil {
+		return err
+	}
+
+	// containerd also serves the CRI, but its containers are collected by
+	// the containerd collector
+	if runtime := criUtil.GetRuntime(); runtime != crioRuntimeName {
+		return agentErrors.NewDisabled(componentName, fmt.Sprintf("CRI runtime is %q, not CRI-O", runtime))
+	}
+
+	return c.start(ctx, store, criUtil)
+}
+
+func (c *collector) start(ctx context.Context, store workloadmeta.Component, client criClient) error {
+	c.store = store
+	c.client = client
+
+	// Subscribe before listing the containers so that no change happening in
+	// between is missed.
+	events, err := c.client.GetContainerEvents(ctx)
+	if err != nil {
+		log.Infof("Cannot subscribe to CRI-O container events, containers will be polled: %v", err)
+	}
+
+	c.mu.Lock()
+	c.streaming = err == nil
+	err = c.pull(true)
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if events != nil {
+		go c.stream(ctx, events)
+	}
+
+	return nil
+}
+
+func (c *collector) Pull(_ context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.pull(!c.streaming)
+}
+
+func (c *collector) GetID() string {
+	return c.id
+}
+
+func (c *collector) GetTargetCatalog() workloadmeta.AgentType {
+	return c.catalog
+}
+
+// pull lists the images, and the containers if listContainers is true, and
+// notifies the store of the changes since the previous call. c.mu must be
+// held.
+func (c *collector) pull(listContainers bool) error {
+	var events []workloadmeta.CollectorEvent
+
+	if err := c.pruneSandboxIPs(); err != nil {
+		return err
+	}
+
+	// Images are listed first so that the image IDs of the containers can be
+	// resolved from the listing.
+	if imageMetadataCollectionIsEnabled() {
+		imageEvents, err := c.listImageEvents()
+		if err != nil {
+			return err
+		}
+		events = append(events, imageEvents...)
+	}
+
+	if listContainers {
+		containerEvents, err := c.listContainerEvents()
+		if err != nil {
+			return err
+		}
+		events = append(events, containerEvents...)
+	}
+
+	if len(events) > 0 {
+		c.store.Notify(events)
+	}
+
+	return nil
+}
+
+// pruneSandboxIPs forgets the IPs of the pod sandboxes that are gone. c.mu
+// must be held.
+func (c *collector) pruneSandboxIPs() error {
+	sandboxes, err := c.client.ListPodSandboxes()
+	if err != nil {
+		return err
+	}
+
+	existingSandboxes := make(map[string]struct{}, len(sandboxes))
+	for _, sandbox := range sandboxes {
+		existingSandboxes[sandbox.Id] = struct{}{}
+	}
+	for id := range c.sandboxIPs {
+		if _, found := existingSandboxes[id]; !found {
+			delete(c.sandboxIPs, id)
+		}
+	}
+
+	return nil
+}
+
+func (c *collector) listContainerEvents() ([]workloadmeta.CollectorEvent, error) {
+	containers, err := c.client.ListContainers()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{}, len(containers))
+	events := make([]workloadmeta.CollectorEvent, 0, len(containers))
+
+	for _, criContainer := range containers {
+		container, err := c.buildContainer(criContainer.Id, criContainer.PodSandboxId)
+		if err != nil {
+			log.Debugf("Cannot get status of CRI-O container %s: %v", criContainer.Id, err)
+			continue
+		}
+
+		seen[container.ID] = struct{}{}
+		events = append(events, workloadmeta.CollectorEvent{
+			Type:   workloadmeta.EventTypeSet,
+			Source: workloadmeta.SourceRuntime,
+			Entity: container,
+		})
+	}
+
+	for id := range c.seenContainers {
+		if _, found := seen[id]; !found {
+			events = append(events, containerUnsetEvent(id))
+		}
+	}
+
+	c.seenContainers = seen
+
+	return events, nil
+}
+
+func (c *collector) stream(ctx context.Context, events criv1.RuntimeService_GetContainerEventsClient) {
+	healthHandle := health.RegisterLiveness(componentName)
+	defer func() {
+		if err := healthHandle.Deregister(); err != nil {
+			log.Warnf("error de-registering health check: %s", err)
+		}
+	}()
+
+	eventsChan := make(chan *criv1.ContainerEventResponse)
+	errorsChan := make(chan error, 1)
+
+	go func() {
+		for {
+			ev, err := events.Recv()
+			if err != nil {
+				errorsChan <- err
+				return
+			}
+
+			select {
+			case eventsChan <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-healthHandle.C:
+
+		case ev := <-eventsChan:
+			c.handleContainerEvent(ev)
+
+		case err := <-errorsChan:
+			if ctx.Err() != nil {
+				return
+			}
+
+			// CRI-O only serves container events when pod events are
+			// enabled in its configuration
+			log.Infof("CRI-O container events stream stopped, containers will be polled: %v", err)
+
+			c.mu.Lock()
+			c.streaming = false
+			c.mu.Unlock()
+			return
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *collector) handleContainerEvent(ev *criv1.ContainerEventResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var event workloadmeta.CollectorEvent
+
+	switch ev.ContainerEventType {
+	case criv1.ContainerEventType_CONTAINER_DELETED_EVENT:
+		delete(c.seenContainers, ev.ContainerId)
+		event = containerUnsetEvent(ev.ContainerId)
+	default:
+		// The events don't carry the pod sandbox ID, it is read from the
+		// verbose status of the container instead
+		container, err := c.buildContainer(ev.ContainerId, "")
+		if err != nil {
+			log.Debugf("Cannot get status of CRI-O container %s: %v", ev.ContainerId, err)
+			return
+		}
+
+		c.seenContainers[container.ID] = struct{}{}
+		event = workloadmeta.CollectorEvent{
+			Type:   workloadmeta.EventTypeSet,
+			Source: workloadmeta.SourceRuntime,
+			Entity: container,
+		}
+	}
+
+	c.store.Notify([]workloadmeta.CollectorEvent{event})
+}
+
+func containerUnsetEvent(containerID string) workloadmeta.CollectorEvent {
+	return workloadmeta.CollectorEvent{
+		Type:   workloadmeta.EventTypeUnset,
+		Source: workloadmeta.SourceRuntime,
+		Entity: &workloadmeta.Container{
+			EntityID: workloadmeta.EntityID{
+				Kind: workloadmeta.KindContainer,
+				ID:   containerID,
+			},
+		},
+	}
+}
+
+func imageMetadataCollectionIsEnabled() bool {
+	return config.Datadog().GetBool("container_image.enabled")
+}
@@ -0,0 +1,281 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build cri
+
+package crio
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+	"github.com/DataDog/datadog-agent/pkg/util/containers/cri"
+	"github.com/DataDog/datadog-agent/pkg/util/containers/cri/crimock"
+)
+
+const (
+	testImageID   = "0123456789abcdef"
+	testImageName = "docker.io/library/nginx:1.25"
+	testImageRef  = "docker.io/library/nginx@sha256:fedcba9876543210"
+
+	testContainerInfo = `{
+		"sandboxID": "sandbox1",
+		"pid": 1234,
+		"runtimeSpec": {
+			"hostname": "web-0",
+			"process": {"env": ["PATH=/usr/bin", "DD_SERVICE=web"]},
+			"linux": {"cgroupsPath": "kubepods-besteffort-pod1.slice:crio:container1"}
+		}
+	}`
+
+	testImageInfo = `{
+		"imageSpec": {
+			"architecture": "amd64",
+			"os": "linux",
+			"config": {"Labels": {"maintainer": "nginx"}},
+			"rootfs": {"type": "layers", "diff_ids": ["sha256:layer1", "sha256:layer2"]},
+			"history": [
+				{"created_by": "ADD rootfs.tar /"},
+				{"created_by": "ENV FOO=bar", "empty_layer": true},
+				{"created_by": "RUN apt-get install nginx"}
+			]
+		}
+	}`
+)
+
+var testCreatedAt = time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+type fakeWorkloadmetaStore struct {
+	workloadmeta.Component
+
+	mu             sync.Mutex
+	notifiedEvents []workloadmeta.CollectorEvent
+}
+
+func (store *fakeWorkloadmetaStore) Notify(events []workloadmeta.CollectorEvent) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.notifiedEvents = append(store.notifiedEvents, events...)
+}
+
+func (store *fakeWorkloadmetaStore) GetImage(_ string) (*workloadmeta.ContainerImageMetadata, error) {
+	return nil, errors.New("not found")
+}
+
+func (store *fakeWorkloadmetaStore) popEvents() []workloadmeta.CollectorEvent {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	events := store.notifiedEvents
+	store.notifiedEvents = nil
+	return events
+}
+
+func newTestServer(t *testing.T) *crimock.FakeCRIServer {
+	server := crimock.NewFakeCRIServer(crioRuntimeName)
+	server.PutPodSandbox(&criv1.PodSandboxStatus{
+		Id:      "sandbox1",
+		State:   criv1.PodSandboxState_SANDBOX_READY,
+		Network: &criv1.PodSandboxNetworkStatus{Ip: "10.0.0.5"},
+	})
+	server.PutImage(&criv1.Image{
+		Id:          testImageID,
+		RepoTags:    []string{testImageName},
+		RepoDigests: []string{testImageRef},
+		Size_:       4242,
+	}, map[string]string{"info": testImageInfo})
+	return server
+}
+
+func putTestContainer(server *crimock.FakeCRIServer) {
+	server.PutContainer("sandbox1", &criv1.ContainerStatus{
+		Id:        "container1",
+		Metadata:  &criv1.ContainerMetadata{Name: "nginx"},
+		State:     criv1.ContainerState_CONTAINER_RUNNING,
+		CreatedAt: testCreatedAt.UnixNano(),
+		StartedAt: testCreatedAt.Add(time.Second).UnixNano(),
+		Image:     &criv1.ImageSpec{Image: testImageName},
+		ImageRef:  testImageRef,
+		Labels:    map[string]string{"io.kubernetes.pod.name": "web-0"},
+	}, map[string]string{"info": testContainerInfo})
+}
+
+func expectedContainer(t *testing.T) *workloadmeta.Container {
+	image, err := workloadmeta.NewContainerImage("sha256:"+testImageID, testImageName)
+	require.NoError(t, err)
+	image.RepoDigest = "sha256:fedcba9876543210"
+
+	return &workloadmeta.Container{
+		EntityID: workloadmeta.EntityID{
+			Kind: workloadmeta.KindContainer,
+			ID:   "container1",
+		},
+		EntityMeta: workloadmeta.EntityMeta{
+			Name:   "nginx",
+			Labels: map[string]string{"io.kubernetes.pod.name": "web-0"},
+		},
+		Image:    image,
+		EnvVars:  map[string]string{"DD_SERVICE": "web"},
+		Hostname: "web-0",
+		NetworkIPs: map[string]string{
+			"": "10.0.0.5",
+		},
+		Runtime:       workloadmeta.ContainerRuntimeCRIO,
+		RuntimeFlavor: workloadmeta.ContainerRuntimeFlavorDefault,
+		State: workloadmeta.ContainerState{
+			Running:   true,
+			Status:    workloadmeta.ContainerStatusRunning,
+			CreatedAt: time.Unix(0, testCreatedAt.UnixNano()),
+			StartedAt: time.Unix(0, testCreatedAt.Add(time.Second).UnixNano()),
+		},
+		PID:        1234,
+		CgroupPath: "kubepods-besteffort-pod1.slice/crio-container1.scope",
+	}
+}
+
+func startCollector(t *testing.T, server *crimock.FakeCRIServer, store *fakeWorkloadmetaStore) *collector {
+	client, err := cri.NewUtil(server.Start(t), time.Second, time.Second)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	c := newCollector()
+	require.NoError(t, c.start(ctx, store, client))
+	return c
+}
+
+func TestPull(t *testing.T) {
+	cfg := configmock.New(t)
+	cfg.SetWithoutSource("container_image.enabled", true)
+
+	server := newTestServer(t)
+	server.EventsUnimplemented = true
+	putTestContainer(server)
+
+	store := &fakeWorkloadmetaStore{}
+	c := startCollector(t, server, store)
+
+	expectedImage := &workloadmeta.ContainerImageMetadata{
+		EntityID: workloadmeta.EntityID{
+			Kind: workloadmeta.KindContainerImageMetadata,
+			ID:   "sha256:" + testImageID,
+		},
+		EntityMeta: workloadmeta.EntityMeta{
+			Name:   testImageName,
+			Labels: map[string]string{"maintainer": "nginx"},
+		},
+		RepoTags:     []string{testImageName},
+		RepoDigests:  []string{testImageRef},
+		SizeBytes:    4242,
+		OS:           "linux",
+		Architecture: "amd64",
+		Layers: []workloadmeta.ContainerImageLayer{
+			{Digest: "sha256:layer1", History: &ocispec.History{CreatedBy: "ADD rootfs.tar /"}},
+			{Digest: "sha256:layer2", History: &ocispec.History{CreatedBy: "RUN apt-get install nginx"}},
+		},
+	}
+
+	assert.Equal(t, []workloadmeta.CollectorEvent{
+		{Type: workloadmeta.EventTypeSet, Source: workloadmeta.SourceRuntime, Entity: expectedImage},
+		{Type: workloadmeta.EventTypeSet, Source: workloadmeta.SourceRuntime, Entity: expectedContainer(t)},
+	}, store.popEvents())
+
+	// CRI-O doesn't serve the events, so the containers are polled
+	assert.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return !c.streaming
+	}, 5*time.Second, 10*time.Millisecond)
+
+	server.DeleteContainer("container1")
+	server.DeleteImage(testImageID)
+	require.NoError(t, c.Pull(context.Background()))
+
+	assert.Equal(t, []workloadmeta.CollectorEvent{
+		{
+			Type:   workloadmeta.EventTypeUnset,
+			Source: workloadmeta.SourceRuntime,
+			Entity: &workloadmeta.ContainerImageMetadata{
+				EntityID: workloadmeta.EntityID{Kind: workloadmeta.KindContainerImageMetadata, ID: "sha256:" + testImageID},
+			},
+		},
+		{
+			Type:   workloadmeta.EventTypeUnset,
+			Source: workloadmeta.SourceRuntime,
+			Entity: &workloadmeta.Container{
+				EntityID: workloadmeta.EntityID{Kind: workloadmeta.KindContainer, ID: "container1"},
+			},
+		},
+	}, store.popEvents())
+}
+
+func TestStream(t *testing.T) {
+	cfg := configmock.New(t)
+	cfg.SetWithoutSource("container_image.enabled", false)
+
+	server := newTestServer(t)
+	store := &fakeWorkloadmetaStore{}
+	c := startCollector(t, server, store)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, server.WaitForSubscriber(ctx))
+
+	// Containers are not listed by Pull while the events are streamed
+	assert.Empty(t, store.popEvents())
+	putTestContainer(server)
+	require.NoError(t, c.Pull(context.Background()))
+	assert.Empty(t, store.popEvents())
+
+	server.SendEvent(&criv1.ContainerEventResponse{
+		ContainerId:        "container1",
+		ContainerEventType: criv1.ContainerEventType_CONTAINER_STARTED_EVENT,
+	})
+
+	var events []workloadmeta.CollectorEvent
+	require.Eventually(t, func() bool {
+		events = append(events, store.popEvents()...)
+		return len(events) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []workloadmeta.CollectorEvent{
+		{Type: workloadmeta.EventTypeSet, Source: workloadmeta.SourceRuntime, Entity: expectedContainer(t)},
+	}, events)
+
+	server.DeleteContainer("container1")
+	server.SendEvent(&criv1.ContainerEventResponse{
+		ContainerId:        "container1",
+		ContainerEventType: criv1.ContainerEventType_CONTAINER_DELETED_EVENT,
+	})
+
+	events = nil
+	require.Eventually(t, func() bool {
+		events = append(events, store.popEvents()...)
+		return len(events) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []workloadmeta.CollectorEvent{
+		{
+			Type:   workloadmeta.EventTypeUnset,
+			Source: workloadmeta.SourceRuntime,
+			Entity: &workloadmeta.Container{
+				EntityID: workloadmeta.EntityID{Kind: workloadmeta.KindContainer, ID: "container1"},
+			},
+		},
+	}, events)
+}
+
+func TestExtractCgroupPath(t *testing.T) {
+	assert.Equal(t, "kubepods-pod1.slice/crio-abc.scope", extractCgroupPath("kubepods-pod1.slice:crio:abc"))
+	assert.Equal(t, "/kubepods/pod1/abc", extractCgroupPath("/kubepods/pod1/abc"))
+}
@@ -0,0 +1,222 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build cri
+
+package crio
+
+import (
+	"encoding/json"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// imageInfo is the verbose information of an image status returned by CRI-O
+// under the "info" key.
+type imageInfo struct {
+	ImageSpec *ocispec.Image `json:"imageSpec"`
+}
+
+// listImageEvents lists the images and returns the events for the images
+// that exist and for the ones that are gone since the previous call. c.mu
+// must be held.
+func (c *collector) listImageEvents() ([]workloadmeta.CollectorEvent, error) {
+	images, err := c.client.ListImages()
+	if err != nil {
+		return nil, err
+	}
+
+	imageSpecs := make(map[string]*ocispec.Image, len(images))
+	imageIDs := make(map[string]string, len(images))
+	events := make([]workloadmeta.CollectorEvent, 0, len(images))
+
+	for _, image := range images {
+		id := normalizeImageID(image.Id)
+
+		spec, found := c.imageSpecs[id]
+		if !found {
+			spec = c.getImageSpec(image.Id)
+		}
+		imageSpecs[id] = spec
+
+		imageIDs[image.Id] = id
+		for _, ref := range append(append([]string{}, image.RepoTags...), image.RepoDigests...) {
+			imageIDs[ref] = id
+		}
+
+		events = append(events, workloadmeta.CollectorEvent{
+			Type:   workloadmeta.EventTypeSet,
+			Source: workloadmeta.SourceRuntime,
+			Entity: buildImage(id, image, spec),
+		})
+	}
+
+	for id := range c.imageSpecs {
+		if _, found := imageSpecs[id]; found {
+			continue
+		}
+
+		events = append(events, workloadmeta.CollectorEvent{
+			Type:   workloadmeta.EventTypeUnset,
+			Source: workloadmeta.SourceRuntime,
+			Entity: &workloadmeta.ContainerImageMetadata{
+				EntityID: workloadmeta.EntityID{
+					Kind: workloadmeta.KindContainerImageMetadata,
+					ID:   id,
+				},
+			},
+		})
+	}
+
+	c.imageSpecs = imageSpecs
+	c.imageIDs = imageIDs
+
+	return events, nil
+}
+
+// getImageSpec returns the OCI config of an image, or nil if CRI-O doesn't
+// return it.
+func (c *collector) getImageSpec(imageID string) *ocispec.Image {
+	resp, err := c.client.ImageStatus(imageID)
+	if err != nil {
+		log.Debugf("Cannot get status of CRI-O image %s: %v", imageID, err)
+		return nil
+	}
+
+	raw, found := resp.GetInfo()["info"]
+	if !found {
+		return nil
+	}
+
+	var info imageInfo
+	if err := json.Unmarshal([]byte(raw), &info); err != nil {
+		log.Debugf("Cannot parse verbose status of CRI-O image %s: %v", imageID, err)
+		return nil
+	}
+
+	return info.ImageSpec
+}
+
+// resolveImageID returns the ID of the image of a container, given the image
+// name and the image ref of its status. c.mu must be held.
+func (c *collector) resolveImageID(imageName string, imageRef string) string {
+	for _, ref := range []string{imageRef, imageName} {
+		if id, found := c.imageIDs[ref]; found {
+			return id
+		}
+	}
+
+	// The image isn't known yet, either because images aren't listed or
+	// because it has been pulled since the last listing.
+	for _, ref := range []string{imageRef, imageName} {
+		if ref == "" {
+			continue
+		}
+
+		resp, err := c.client.ImageStatus(ref)
+		if err != nil {
+			log.Debugf("Cannot get status of CRI-O image %s: %v", ref, err)
+			continue
+		}
+
+		if image := resp.GetImage(); image != nil {
+			id := normalizeImageID(image.Id)
+			c.imageIDs[ref] = id
+			return id
+		}
+	}
+
+	return ""
+}
+
+func buildImage(id string, image *criv1.Image, spec *ocispec.Image) *workloadmeta.ContainerImageMetadata {
+	wlmImage := &workloadmeta.ContainerImageMetadata{
+		EntityID: workloadmeta.EntityID{
+			Kind: workloadmeta.KindContainerImageMetadata,
+			ID:   id,
+		},
+		EntityMeta: workloadmeta.EntityMeta{
+			Name: imageName(id, image),
+		},
+		RepoTags:    image.RepoTags,
+		RepoDigests: image.RepoDigests,
+		SizeBytes:   int64(image.Size_),
+	}
+
+	if spec == nil {
+		return wlmImage
+	}
+
+	wlmImage.OS = spec.OS
+	wlmImage.OSVersion = spec.OSVersion
+	wlmImage.Architecture = spec.Architecture
+	wlmImage.Variant = spec.Variant
+	wlmImage.Labels = spec.Config.Labels
+	wlmImage.Layers = getLayersWithHistory(*spec)
+
+	return wlmImage
+}
+
+// imageName returns the name used for an image: its first tag, or its first
+// digest for untagged images.
+func imageName(id string, image *criv1.Image) string {
+	if len(image.RepoTags) > 0 {
+		return image.RepoTags[0]
+	}
+
+	if len(image.RepoDigests) > 0 {
+		name, _, _ := strings.Cut(image.RepoDigests[0], "@")
+		return name
+	}
+
+	return id
+}
+
+// getLayersWithHistory returns the layers of an image from its OCI config.
+// The config doesn't reference the compressed layer blobs, so the layers are
+// identified by the digest of their uncompressed content.
+func getLayersWithHistory(ocispecImage ocispec.Image) []workloadmeta.ContainerImageLayer {
+	var layers []workloadmeta.ContainerImageLayer
+
+	// The history entries that don't have an associated layer are flagged
+	// with emptyLayer = true. History is optional in the OCI spec.
+	historyIndex := 0
+	for _, diffID := range ocispecImage.RootFS.DiffIDs {
+		historyFound := false
+		for ; historyIndex < len(ocispecImage.History); historyIndex++ {
+			if !ocispecImage.History[historyIndex].EmptyLayer {
+				historyFound = true
+				break
+			}
+		}
+
+		layer := workloadmeta.ContainerImageLayer{
+			Digest: diffID.String(),
+		}
+		if historyFound {
+			layer.History = &ocispecImage.History[historyIndex]
+			historyIndex++
+		}
+
+		layers = append(layers, layer)
+	}
+
+	return layers
+}
+
+// normalizeImageID prefixes image IDs with their digest algorithm, as CRI-O
+// returns bare sha256 hex digests, so that they match the IDs used by the
+// other runtimes.
+func normalizeImageID(id string) string {
+	if id == "" || strings.Contains(id, ":") {
+		return id
+	}
+	return "sha256:" + id
+}
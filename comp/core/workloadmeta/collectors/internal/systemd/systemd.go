@@ -0,0 +1,262 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build systemd
+
+// Package systemd implements the systemd units Workloadmeta collector.
+package systemd
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/dbus"
+	"go.uber.org/fx"
+
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	dderrors "github.com/DataDog/datadog-agent/pkg/errors"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	systemdutil "github.com/DataDog/datadog-agent/pkg/util/systemd"
+)
+
+const (
+	collectorID   = "systemd"
+	componentName = "workloadmeta-systemd"
+
+	serviceSuffix = ".service"
+)
+
+// systemdClient is the subset of the D-Bus API used by the collector.
+type systemdClient interface {
+	ListUnits(ctx context.Context) ([]dbus.UnitStatus, error)
+	GetUnitProperties(ctx context.Context, unit string) (map[string]interface{}, error)
+	GetServiceProperties(ctx context.Context, unit string) (map[string]interface{}, error)
+	Close()
+}
+
+type dbusClient struct {
+	conn *dbus.Conn
+}
+
+func (c *dbusClient) ListUnits(ctx context.Context) ([]dbus.UnitStatus, error) {
+	return c.conn.ListUnitsContext(ctx)
+}
+
+func (c *dbusClient) GetUnitProperties(ctx context.Context, unit string) (map[string]interface{}, error) {
+	return c.conn.GetUnitPropertiesContext(ctx, unit)
+}
+
+func (c *dbusClient) GetServiceProperties(ctx context.Context, unit string) (map[string]interface{}, error) {
+	return c.conn.GetUnitTypePropertiesContext(ctx, unit, "Service")
+}
+
+func (c *dbusClient) Close() {
+	c.conn.Close()
+}
+
+type dependencies struct {
+	fx.In
+
+	Config config.Component
+}
+
+type collector struct {
+	id         string
+	config     config.Component
+	client     systemdClient
+	newClient  func(ctx context.Context, privateSocket string) (systemdClient, error)
+	store      workloadmeta.Component
+	catalog    workloadmeta.AgentType
+	cgroupRoot string
+	seen       map[workloadmeta.EntityID]struct{}
+}
+
+// NewCollector returns a new systemd collector provider and an error
+func NewCollector(deps dependencies) (workloadmeta.CollectorProvider, error) {
+	return workloadmeta.CollectorProvider{
+		Collector: &collector{
+			id:        collectorID,
+			config:    deps.Config,
+			newClient: newDBusClient,
+			seen:      make(map[workloadmeta.EntityID]struct{}),
+			catalog:   workloadmeta.NodeAgent | workloadmeta.ProcessAgent,
+		},
+	}, nil
+}
+
+// GetFxOptions returns the FX framework options for the collector
+func GetFxOptions() fx.Option {
+	return fx.Provide(NewCollector)
+}
+
+func newDBusClient(ctx context.Context, privateSocket string) (systemdClient, error) {
+	conn, err := systemdutil.NewConnection(ctx, privateSocket)
+	if err != nil {
+		return nil, err
+	}
+	return &dbusClient{conn: conn}, nil
+}
+
+// Start the collector for the provided workloadmeta component
+func (c *collector) Start(ctx context.Context, store workloadmeta.Component) error {
+	if !c.config.GetBool("systemd_units.enabled") {
+		return dderrors.NewDisabled(componentName, "systemd_units.enabled is false")
+	}
+
+	client, err := c.newClient(ctx, c.config.GetString("systemd_units.private_socket"))
+	if err != nil {
+		return dderrors.NewDisabled(componentName, "cannot connect to systemd: "+err.Error())
+	}
+
+	c.client = client
+	c.store = store
+	c.cgroupRoot = c.config.GetString("container_cgroup_root")
+
+	go func() {
+		<-ctx.Done()
+		c.client.Close()
+	}()
+
+	return nil
+}
+
+func (c *collector) Pull(ctx context.Context) error {
+	units, err := c.client.ListUnits(ctx)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[workloadmeta.EntityID]struct{})
+	events := make([]workloadmeta.CollectorEvent, 0, len(units))
+
+	for _, status := range units {
+		// inactive units have no process to tag nor anything to monitor
+		if !strings.HasSuffix(status.Name, serviceSuffix) || status.ActiveState == "inactive" {
+			continue
+		}
+
+		unit := c.buildUnit(ctx, status)
+		seen[unit.EntityID] = struct{}{}
+		events = append(events, workloadmeta.CollectorEvent{
+			Type:   workloadmeta.EventTypeSet,
+			Source: workloadmeta.SourceSystemd,
+			Entity: unit,
+		})
+	}
+
+	for seenID := range c.seen {
+		if _, ok := seen[seenID]; ok {
+			continue
+		}
+
+		events = append(events, workloadmeta.CollectorEvent{
+			Type:   workloadmeta.EventTypeUnset,
+			Source: workloadmeta.SourceSystemd,
+			Entity: &workloadmeta.SystemdUnit{
+				EntityID: seenID,
+			},
+		})
+	}
+
+	c.seen = seen
+
+	c.store.Notify(events)
+
+	return nil
+}
+
+func (c *collector) GetID() string {
+	return c.id
+}
+
+func (c *collector) GetTargetCatalog() workloadmeta.AgentType {
+	return c.catalog
+}
+
+func (c *collector) buildUnit(ctx context.Context, status dbus.UnitStatus) *workloadmeta.SystemdUnit {
+	unit := &workloadmeta.SystemdUnit{
+		EntityID: workloadmeta.EntityID{
+			Kind: workloadmeta.KindSystemdUnit,
+			ID:   status.Name,
+		},
+		Description: status.Description,
+		LoadState:   status.LoadState,
+		ActiveState: status.ActiveState,
+		SubState:    status.SubState,
+	}
+
+	unitProperties, err := c.client.GetUnitProperties(ctx, status.Name)
+	if err != nil {
+		log.Debugf("Cannot get properties of systemd unit %s: %v", status.Name, err)
+	} else {
+		unit.FragmentPath = getString(unitProperties, "FragmentPath")
+		unit.UnitFileState = getString(unitProperties, "UnitFileState")
+		if activeEnter := getUint64(unitProperties, "ActiveEnterTimestamp"); activeEnter > 0 {
+			unit.ActiveEnterTimestamp = time.UnixMicro(int64(activeEnter))
+		}
+	}
+
+	serviceProperties, err := c.client.GetServiceProperties(ctx, status.Name)
+	if err != nil {
+		log.Debugf("Cannot get service properties of systemd unit %s: %v", status.Name, err)
+		return unit
+	}
+
+	unit.MainPID = int32(getUint64(serviceProperties, "MainPID"))
+	unit.ControlGroup = getString(serviceProperties, "ControlGroup")
+	if unit.ControlGroup != "" {
+		unit.PIDs = c.readCgroupPIDs(unit.ControlGroup)
+	}
+
+	return unit
+}
+
+// readCgroupPIDs returns the PIDs of the processes in the given cgroup. Both
+// the cgroup v2 unified hierarchy and the cgroup v1 systemd hierarchy are
+// looked up.
+func (c *collector) readCgroupPIDs(controlGroup string) []int32 {
+	for _, hierarchy := range []string{"", "systemd"} {
+		f, err := os.Open(filepath.Join(c.cgroupRoot, hierarchy, controlGroup, "cgroup.procs"))
+		if err != nil {
+			continue
+		}
+		defer f.Close()
+
+		var pids []int32
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			pid, err := strconv.ParseInt(strings.TrimSpace(scanner.Text()), 10, 32)
+			if err != nil {
+				continue
+			}
+			pids = append(pids, int32(pid))
+		}
+		return pids
+	}
+
+	log.Debugf("Cannot find the cgroup.procs file of cgroup %s under %s", controlGroup, c.cgroupRoot)
+	return nil
+}
+
+func getString(properties map[string]interface{}, name string) string {
+	value, _ := properties[name].(string)
+	return value
+}
+
+func getUint64(properties map[string]interface{}, name string) uint64 {
+	switch value := properties[name].(type) {
+	case uint64:
+		return value
+	case uint32:
+		return uint64(value)
+	}
+	return 0
+}
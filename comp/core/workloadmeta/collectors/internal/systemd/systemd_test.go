@@ -0,0 +1,147 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build systemd
+
+package systemd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/dbus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+)
+
+type fakeWorkloadmetaStore struct {
+	workloadmeta.Component
+	notifiedEvents []workloadmeta.CollectorEvent
+}
+
+func (store *fakeWorkloadmetaStore) Notify(events []workloadmeta.CollectorEvent) {
+	store.notifiedEvents = append(store.notifiedEvents, events...)
+}
+
+type fakeSystemdClient struct {
+	units             []dbus.UnitStatus
+	unitProperties    map[string]map[string]interface{}
+	serviceProperties map[string]map[string]interface{}
+}
+
+func (c *fakeSystemdClient) ListUnits(_ context.Context) ([]dbus.UnitStatus, error) {
+	return c.units, nil
+}
+
+func (c *fakeSystemdClient) GetUnitProperties(_ context.Context, unit string) (map[string]interface{}, error) {
+	if props, ok := c.unitProperties[unit]; ok {
+		return props, nil
+	}
+	return nil, errors.New("unknown unit")
+}
+
+func (c *fakeSystemdClient) GetServiceProperties(_ context.Context, unit string) (map[string]interface{}, error) {
+	if props, ok := c.serviceProperties[unit]; ok {
+		return props, nil
+	}
+	return nil, errors.New("unknown unit")
+}
+
+func (c *fakeSystemdClient) Close() {}
+
+func TestPull(t *testing.T) {
+	cgroupRoot := t.TempDir()
+	cgroupDir := filepath.Join(cgroupRoot, "system.slice", "nginx.service")
+	require.NoError(t, os.MkdirAll(cgroupDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(cgroupDir, "cgroup.procs"), []byte("1234\n1235\n"), 0644))
+
+	activeEnter := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	client := &fakeSystemdClient{
+		units: []dbus.UnitStatus{
+			{Name: "nginx.service", Description: "nginx web server", LoadState: "loaded", ActiveState: "active", SubState: "running"},
+			{Name: "cron.service", Description: "cron", LoadState: "loaded", ActiveState: "failed", SubState: "failed"},
+			{Name: "stopped.service", LoadState: "loaded", ActiveState: "inactive", SubState: "dead"},
+			{Name: "tmp.mount", LoadState: "loaded", ActiveState: "active", SubState: "mounted"},
+		},
+		unitProperties: map[string]map[string]interface{}{
+			"nginx.service": {
+				"FragmentPath":         "/lib/systemd/system/nginx.service",
+				"UnitFileState":        "enabled",
+				"ActiveEnterTimestamp": uint64(activeEnter.UnixMicro()),
+			},
+		},
+		serviceProperties: map[string]map[string]interface{}{
+			"nginx.service": {
+				"MainPID":      uint32(1234),
+				"ControlGroup": "/system.slice/nginx.service",
+			},
+		},
+	}
+
+	store := &fakeWorkloadmetaStore{}
+	c := collector{
+		client:     client,
+		store:      store,
+		cgroupRoot: cgroupRoot,
+		seen:       make(map[workloadmeta.EntityID]struct{}),
+	}
+
+	require.NoError(t, c.Pull(context.TODO()))
+
+	expected := []workloadmeta.CollectorEvent{
+		{
+			Type:   workloadmeta.EventTypeSet,
+			Source: workloadmeta.SourceSystemd,
+			Entity: &workloadmeta.SystemdUnit{
+				EntityID: workloadmeta.EntityID{
+					Kind: workloadmeta.KindSystemdUnit,
+					ID:   "nginx.service",
+				},
+				Description:          "nginx web server",
+				LoadState:            "loaded",
+				ActiveState:          "active",
+				SubState:             "running",
+				MainPID:              1234,
+				PIDs:                 []int32{1234, 1235},
+				ControlGroup:         "/system.slice/nginx.service",
+				FragmentPath:         "/lib/systemd/system/nginx.service",
+				UnitFileState:        "enabled",
+				ActiveEnterTimestamp: time.UnixMicro(activeEnter.UnixMicro()),
+			},
+		},
+		{
+			Type:   workloadmeta.EventTypeSet,
+			Source: workloadmeta.SourceSystemd,
+			Entity: &workloadmeta.SystemdUnit{
+				EntityID: workloadmeta.EntityID{
+					Kind: workloadmeta.KindSystemdUnit,
+					ID:   "cron.service",
+				},
+				Description: "cron",
+				LoadState:   "loaded",
+				ActiveState: "failed",
+				SubState:    "failed",
+			},
+		},
+	}
+	assert.Equal(t, expected, store.notifiedEvents)
+
+	// nginx stops, an unset event is expected for it
+	client.units = client.units[1:]
+	store.notifiedEvents = nil
+	require.NoError(t, c.Pull(context.TODO()))
+
+	require.Len(t, store.notifiedEvents, 2)
+	assert.Equal(t, workloadmeta.EventTypeSet, store.notifiedEvents[0].Type)
+	assert.Equal(t, workloadmeta.EventTypeUnset, store.notifiedEvents[1].Type)
+	assert.Equal(t, "nginx.service", store.notifiedEvents[1].Entity.GetID().ID)
+}
@@ -100,6 +100,14 @@ type Component interface {
 	// filter evaluates to true.
 	ListProcessesWithFilter(filterFunc EntityFilterFunc[*Process]) []*Process
 
+	// GetSystemdUnit returns metadata about a systemd unit.  It fetches the
+	// entity with kind KindSystemdUnit and the given unit name.
+	GetSystemdUnit(name string) (*SystemdUnit, error)
+
+	// ListSystemdUnits returns metadata about all known systemd units,
+	// equivalent to all entities with kind KindSystemdUnit.
+	ListSystemdUnits() []*SystemdUnit
+
 	// Notify notifies the store with a slice of events.  It should only be
 	// used by workloadmeta collectors.
 	Notify(events []CollectorEvent)
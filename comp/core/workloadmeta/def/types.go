@@ -48,6 +48,7 @@ const (
 	KindContainerImageMetadata Kind = "container_image_metadata"
 	KindProcess                Kind = "process"
 	KindHost                   Kind = "host"
+	KindSystemdUnit            Kind = "systemd_unit"
 )
 
 // Source is the source name of an entity.
@@ -88,6 +89,10 @@ const (
 
 	// SourceHost represents entities detected by the host such as host tags.
 	SourceHost Source = "host"
+
+	// SourceSystemd represents entities detected by querying systemd over
+	// D-Bus, such as systemd units.
+	SourceSystemd Source = "systemd"
 )
 
 // ContainerRuntime is the container runtime used by a container.
@@ -1229,6 +1234,83 @@ func (p HostTags) String(verbose bool) string {
 	return sb.String()
 }
 
+// SystemdUnit is an Entity that represents a systemd unit. Its ID is the unit
+// name (e.g. `nginx.service`).
+type SystemdUnit struct {
+	EntityID
+
+	Description string
+
+	// LoadState, ActiveState and SubState are the states reported by
+	// systemd, e.g. `loaded`, `active` and `running`.
+	LoadState   string
+	ActiveState string
+	SubState    string
+
+	// MainPID is the PID of the main process of the unit, 0 if it has none.
+	MainPID int32
+
+	// PIDs are the PIDs of all the processes running in the unit cgroup.
+	PIDs []int32
+
+	// ControlGroup is the cgroup path of the unit, relative to the cgroup
+	// root (e.g. `/system.slice/nginx.service`).
+	ControlGroup string
+
+	// FragmentPath is the path of the unit file and UnitFileState its
+	// enablement state, e.g. `enabled` or `disabled`.
+	FragmentPath  string
+	UnitFileState string
+
+	ActiveEnterTimestamp time.Time
+}
+
+var _ Entity = &SystemdUnit{}
+
+// GetID implements Entity#GetID.
+func (u SystemdUnit) GetID() EntityID {
+	return u.EntityID
+}
+
+// DeepCopy implements Entity#DeepCopy.
+func (u SystemdUnit) DeepCopy() Entity {
+	cp := deepcopy.Copy(u).(SystemdUnit)
+	return &cp
+}
+
+// Merge implements Entity#Merge.
+func (u *SystemdUnit) Merge(e Entity) error {
+	otherUnit, ok := e.(*SystemdUnit)
+	if !ok {
+		return fmt.Errorf("cannot merge SystemdUnit with different kind %T", e)
+	}
+
+	return merge(u, otherUnit)
+}
+
+// String implements Entity#String.
+func (u SystemdUnit) String(verbose bool) string {
+	var sb strings.Builder
+
+	_, _ = fmt.Fprintln(&sb, "----------- Entity ID -----------")
+	_, _ = fmt.Fprint(&sb, u.EntityID.String(verbose))
+
+	_, _ = fmt.Fprintln(&sb, "----------- Unit Info -----------")
+	_, _ = fmt.Fprintln(&sb, "Description:", u.Description)
+	_, _ = fmt.Fprintln(&sb, "State:", u.LoadState, u.ActiveState, u.SubState)
+	_, _ = fmt.Fprintln(&sb, "Main PID:", u.MainPID)
+	_, _ = fmt.Fprintln(&sb, "Control Group:", u.ControlGroup)
+
+	if verbose {
+		_, _ = fmt.Fprintln(&sb, "PIDs:", u.PIDs)
+		_, _ = fmt.Fprintln(&sb, "Unit File:", u.FragmentPath)
+		_, _ = fmt.Fprintln(&sb, "Unit File State:", u.UnitFileState)
+		_, _ = fmt.Fprintln(&sb, "Active Since:", u.ActiveEnterTimestamp)
+	}
+
+	return sb.String()
+}
+
 // CollectorEvent is an event generated by a metadata collector, to be handled
 // by the metadata store.
 type CollectorEvent struct {
@@ -789,6 +789,50 @@ func TestGetProcess(t *testing.T) {
 	}
 }
 
+func TestGetSystemdUnit(t *testing.T) {
+	deps := fxutil.Test[dependencies](t, fx.Options(
+		logimpl.MockModule(),
+		config.MockModule(),
+		fx.Supply(wmdef.NewParams()),
+	))
+
+	s := newWorkloadmetaObject(deps)
+
+	unit := &wmdef.SystemdUnit{
+		EntityID: wmdef.EntityID{
+			Kind: wmdef.KindSystemdUnit,
+			ID:   "nginx.service",
+		},
+		ActiveState: "active",
+		MainPID:     123,
+	}
+
+	s.handleEvents([]wmdef.CollectorEvent{
+		{
+			Type:   wmdef.EventTypeSet,
+			Source: fooSource,
+			Entity: unit,
+		},
+	})
+
+	gotUnit, err := s.GetSystemdUnit("nginx.service")
+	assert.NoError(t, err)
+	assert.Equal(t, unit, gotUnit)
+	assert.Equal(t, []*wmdef.SystemdUnit{unit}, s.ListSystemdUnits())
+
+	s.handleEvents([]wmdef.CollectorEvent{
+		{
+			Type:   wmdef.EventTypeUnset,
+			Source: fooSource,
+			Entity: unit,
+		},
+	})
+
+	_, err = s.GetSystemdUnit("nginx.service")
+	assert.True(t, errors.IsNotFound(err))
+	assert.Empty(t, s.ListSystemdUnits())
+}
+
 func TestListContainers(t *testing.T) {
 	container := &wmdef.Container{
 		EntityID: wmdef.EntityID{
@@ -84,6 +84,8 @@ func enrichMetricType(dogstatsdMetricType metricType) metrics.MetricType {
 		return metrics.SetType
 	case timingType:
 		return metrics.HistogramType
+	case sketchType, histogramBucketsType:
+		return metrics.DistributionType
 	}
 	return metrics.GaugeType
 }
@@ -158,6 +160,7 @@ func enrichMetricSample(dest []metrics.MetricSample, ddSample dogstatsdMetricSam
 		Value:      ddSample.value,
 		SampleRate: ddSample.sampleRate,
 		RawValue:   ddSample.setValue,
+		Sketch:     ddSample.sketch,
 		Timestamp:  tsToFloatForSamples(ddSample.ts),
 		OriginInfo: extractedOrigin,
 		ListenerID: listenerID,
@@ -17,6 +17,8 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/containers/metrics/provider"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/optional"
+
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/quantile"
 )
 
 type messageType int
@@ -151,9 +153,21 @@ func (p *parser) parseMetricSample(message []byte) (dogstatsdMetricSample, error
 	var setValue []byte
 	var values []float64
 	var value float64
-	if metricType == setType {
+	var sketch *quantile.Sketch
+	switch metricType {
+	case setType:
 		setValue = rawValue // special case for the set type, we obviously don't support multiple values for this type
-	} else {
+	case sketchType:
+		sketch, err = parseSketchValue(rawValue)
+		if err != nil {
+			return dogstatsdMetricSample{}, fmt.Errorf("could not parse dogstatsd sketch: %v", err)
+		}
+	case histogramBucketsType:
+		sketch, err = parseHistogramBucketsValue(rawValue)
+		if err != nil {
+			return dogstatsdMetricSample{}, fmt.Errorf("could not parse dogstatsd histogram buckets: %v", err)
+		}
+	default:
 		// In case the list contains only one value, dogstatsd 1.0
 		// protocol, we directly parse it as a float64. This avoids
 		// pulling a slice from the float64List and greatly improve
@@ -184,6 +198,10 @@ func (p *parser) parseMetricSample(message []byte) (dogstatsdMetricSample, error
 			tags = p.parseTags(optionalField[1:])
 		// sample rate
 		case bytes.HasPrefix(optionalField, sampleRateFieldPrefix):
+			if sketch != nil {
+				// the counts of client-side aggregated distributions are not scaled by a sample rate
+				return dogstatsdMetricSample{}, fmt.Errorf("dogstatsd sample rate is not supported for sketches and histogram buckets")
+			}
 			sampleRate, err = parseMetricSampleSampleRate(optionalField[1:])
 			if err != nil {
 				return dogstatsdMetricSample{}, fmt.Errorf("could not parse dogstatsd sample rate %q", optionalField)
@@ -193,6 +211,10 @@ func (p *parser) parseMetricSample(message []byte) (dogstatsdMetricSample, error
 			if !p.readTimestamps {
 				continue
 			}
+			if sketch != nil {
+				// timestamped samples aren't aggregated, which client-side aggregated distributions must be
+				return dogstatsdMetricSample{}, fmt.Errorf("dogstatsd timestamp is not supported for sketches and histogram buckets")
+			}
 			ts, err := strconv.ParseInt(string(optionalField[len(timestampFieldPrefix):]), 10, 0)
 			if err != nil {
 				return dogstatsdMetricSample{}, fmt.Errorf("could not parse dogstatsd timestamp %q: %v", optionalField[len(timestampFieldPrefix):], err)
@@ -212,6 +234,7 @@ func (p *parser) parseMetricSample(message []byte) (dogstatsdMetricSample, error
 		value:       value,
 		values:      values,
 		setValue:    string(setValue),
+		sketch:      sketch,
 		metricType:  metricType,
 		sampleRate:  sampleRate,
 		tags:        tags,
@@ -9,6 +9,8 @@ import (
 	"bytes"
 	"fmt"
 	"time"
+
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/quantile"
 )
 
 type metricType int
@@ -20,6 +22,10 @@ const (
 	histogramType
 	setType
 	timingType
+	// sketchType and histogramBucketsType are distributions aggregated by the
+	// client, sent as a whole once per flush.
+	sketchType
+	histogramBucketsType
 )
 
 var (
@@ -29,6 +35,8 @@ var (
 	distributionSymbol = []byte("d")
 	setSymbol          = []byte("s")
 	timingSymbol       = []byte("ms")
+	sketchSymbol       = []byte("sk")
+	bucketsSymbol      = []byte("hb")
 
 	tagsFieldPrefix       = []byte("#")
 	sampleRateFieldPrefix = []byte("@")
@@ -42,7 +50,9 @@ type dogstatsdMetricSample struct {
 	// use for multiple value messages
 	values []float64
 	// use to store set's values
-	setValue   string
+	setValue string
+	// use to store the values of sketch and histogram buckets messages
+	sketch     *quantile.Sketch
 	metricType metricType
 	sampleRate float64
 	tags       []string
@@ -88,6 +98,10 @@ func parseMetricSampleMetricType(rawMetricType []byte) (metricType, error) {
 		return setType, nil
 	case bytes.Equal(rawMetricType, timingSymbol):
 		return timingType, nil
+	case bytes.Equal(rawMetricType, sketchSymbol):
+		return sketchType, nil
+	case bytes.Equal(rawMetricType, bucketsSymbol):
+		return histogramBucketsType, nil
 	}
 	return 0, fmt.Errorf("invalid metric type: %q", rawMetricType)
 }
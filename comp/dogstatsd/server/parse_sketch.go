@@ -0,0 +1,124 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package server
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"math"
+
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/quantile"
+	"github.com/DataDog/sketches-go/ddsketch"
+	"github.com/DataDog/sketches-go/ddsketch/store"
+)
+
+const (
+	// maxEncodedSketchSize is the maximum size of the decoded DDSketch of a sketch packet.
+	maxEncodedSketchSize = 64 * 1024
+	// maxHistogramBuckets is the maximum number of buckets of a histogram buckets packet.
+	maxHistogramBuckets = 1024
+	// maxHistogramBucketCount is the maximum number of values counted in a single bucket.
+	maxHistogramBucketCount = 1 << 32
+)
+
+// parseSketchValue parses the value of a sketch packet, which is the base64
+// (standard encoding) representation of a DDSketch serialized by the sketches-go
+// library, including its index mapping:
+//
+//	<name>:<base64 encoded sketch>|sk|...
+func parseSketchValue(rawValue []byte) (*quantile.Sketch, error) {
+	if base64.StdEncoding.DecodedLen(len(rawValue)) > maxEncodedSketchSize {
+		return nil, fmt.Errorf("sketch is larger than %d bytes", maxEncodedSketchSize)
+	}
+	encoded := make([]byte, base64.StdEncoding.DecodedLen(len(rawValue)))
+	n, err := base64.StdEncoding.Decode(encoded, rawValue)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sketch encoding: %v", err)
+	}
+	// a sparse store keeps the memory used by the decoded sketch proportional to
+	// the number of encoded bins, whatever their indexes
+	dds, err := ddsketch.DecodeDDSketch(encoded[:n], store.SparseStoreConstructor, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sketch: %v", err)
+	}
+	if count := dds.GetCount(); count <= 0 || math.IsInf(count, 0) || math.IsNaN(count) {
+		return nil, fmt.Errorf("invalid sketch count: %v", count)
+	}
+	sketch, err := quantile.ConvertDDSketchIntoSketch(dds)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sketch: %v", err)
+	}
+	return sketch, nil
+}
+
+// parseHistogramBucketsValue parses the value of a histogram buckets packet,
+// which is a list of buckets separated by colons, each bucket being made of its
+// lower bound, its upper bound and the number of values it counts:
+//
+//	<name>:<lower>,<upper>,<count>:<lower>,<upper>,<count>|hb|...
+//
+// As for the buckets of OpenMetrics histograms, the upper bound of the last bucket
+// can be +Inf, in which case its values are counted at its lower bound. Values are
+// otherwise interpolated over the bucket.
+func parseHistogramBucketsValue(rawValue []byte) (*quantile.Sketch, error) {
+	if n := bytes.Count(rawValue, colonSeparator) + 1; n > maxHistogramBuckets {
+		return nil, fmt.Errorf("too many histogram buckets: %d (max %d)", n, maxHistogramBuckets)
+	}
+
+	var agent quantile.Agent
+	var rawBucket []byte
+	for rawValue != nil {
+		if idx := bytes.Index(rawValue, colonSeparator); idx == -1 {
+			rawBucket, rawValue = rawValue, nil
+		} else {
+			rawBucket, rawValue = rawValue[:idx], rawValue[idx+len(colonSeparator):]
+		}
+		lower, upper, count, err := parseHistogramBucket(rawBucket)
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			continue
+		}
+		if math.IsInf(upper, 1) {
+			upper = lower
+		}
+		agent.InsertInterpolate(lower, upper, count)
+	}
+
+	sketch := agent.Finish()
+	if sketch == nil {
+		return nil, fmt.Errorf("histogram buckets are empty")
+	}
+	return sketch, nil
+}
+
+// parseHistogramBucket parses a single <lower>,<upper>,<count> bucket.
+func parseHistogramBucket(rawBucket []byte) (float64, float64, uint, error) {
+	rawLower, rest, ok := bytes.Cut(rawBucket, commaSeparator)
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("invalid histogram bucket: %q", rawBucket)
+	}
+	rawUpper, rawCount, ok := bytes.Cut(rest, commaSeparator)
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("invalid histogram bucket: %q", rawBucket)
+	}
+
+	lower, err := parseFloat64(rawLower)
+	if err != nil || math.IsInf(lower, 0) || math.IsNaN(lower) {
+		return 0, 0, 0, fmt.Errorf("invalid histogram bucket lower bound: %q", rawLower)
+	}
+	upper, err := parseFloat64(rawUpper)
+	if err != nil || math.IsInf(upper, -1) || math.IsNaN(upper) || upper < lower {
+		return 0, 0, 0, fmt.Errorf("invalid histogram bucket upper bound: %q", rawUpper)
+	}
+	count, err := parseInt64(rawCount)
+	if err != nil || count < 0 || count > maxHistogramBucketCount {
+		return 0, 0, 0, fmt.Errorf("invalid histogram bucket count: %q", rawCount)
+	}
+	return lower, upper, uint(count), nil
+}
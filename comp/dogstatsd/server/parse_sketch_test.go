@@ -0,0 +1,176 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package server
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/DataDog/sketches-go/ddsketch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+)
+
+func encodeTestSketch(t testing.TB, values ...float64) string {
+	sketch, err := ddsketch.NewDefaultDDSketch(0.01)
+	require.NoError(t, err)
+	for _, v := range values {
+		require.NoError(t, sketch.Add(v))
+	}
+	var b []byte
+	sketch.Encode(&b, false)
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func TestParseSketch(t *testing.T) {
+	encoded := encodeTestSketch(t, 1, 2, 3, 4, 100)
+	sample, err := parseMetricSample(t, make(map[string]any), []byte("daemon:"+encoded+"|sk|#sometag1:somevalue1"))
+
+	require.NoError(t, err)
+
+	assert.Equal(t, "daemon", sample.name)
+	assert.Equal(t, sketchType, sample.metricType)
+	assert.Equal(t, []string{"sometag1:somevalue1"}, sample.tags)
+	require.NotNil(t, sample.sketch)
+	assert.EqualValues(t, 5, sample.sketch.Basic.Cnt)
+	assert.InDelta(t, 1, sample.sketch.Basic.Min, 0.02)
+	assert.InDelta(t, 100, sample.sketch.Basic.Max, 2)
+	require.Nil(t, sample.values)
+}
+
+func TestParseSketchErrors(t *testing.T) {
+	tooLarge := strings.Repeat("A", base64.StdEncoding.EncodedLen(maxEncodedSketchSize+1))
+	for _, message := range []string{
+		"daemon:!!!|sk",
+		"daemon:" + base64.StdEncoding.EncodeToString([]byte("not a sketch")) + "|sk",
+		"daemon:" + encodeTestSketch(t) + "|sk",
+		"daemon:" + tooLarge + "|sk",
+		"daemon:" + encodeTestSketch(t, 1) + "|sk|@0.5",
+	} {
+		_, err := parseMetricSample(t, make(map[string]any), []byte(message))
+		assert.Error(t, err, message)
+	}
+}
+
+func TestParseHistogramBuckets(t *testing.T) {
+	sample, err := parseMetricSample(t, make(map[string]any), []byte("daemon:0,10,5:10,20,0:20,+Inf,2|hb|#sometag1:somevalue1"))
+
+	require.NoError(t, err)
+
+	assert.Equal(t, "daemon", sample.name)
+	assert.Equal(t, histogramBucketsType, sample.metricType)
+	assert.Equal(t, []string{"sometag1:somevalue1"}, sample.tags)
+	require.NotNil(t, sample.sketch)
+	assert.EqualValues(t, 7, sample.sketch.Basic.Cnt)
+	assert.InDelta(t, 0, sample.sketch.Basic.Min, 0.01)
+	// values of the +Inf bucket are counted at its lower bound
+	assert.InDelta(t, 20, sample.sketch.Basic.Max, 0.2)
+	require.Nil(t, sample.values)
+}
+
+func TestParseHistogramBucketsErrors(t *testing.T) {
+	tooMany := strings.TrimSuffix(strings.Repeat("0,1,1:", maxHistogramBuckets+1), ":")
+	for _, message := range []string{
+		"daemon:0,10|hb",
+		"daemon:0,10,1,1|hb",
+		"daemon:10,0,1|hb",
+		"daemon:-Inf,0,1|hb",
+		"daemon:0,NaN,1|hb",
+		"daemon:0,10,-1|hb",
+		"daemon:0,10,1.5|hb",
+		"daemon:0,10,0|hb",
+		"daemon:" + tooMany + "|hb",
+	} {
+		_, err := parseMetricSample(t, make(map[string]any), []byte(message))
+		assert.Error(t, err, message)
+	}
+}
+
+func TestParseSketchWithSampleRate(t *testing.T) {
+	// client-side aggregated counts can't be scaled, so sampled packets are rejected
+	_, err := parseMetricSample(t, make(map[string]any), []byte("daemon:0,10,5:20,+Inf,2|hb|@0.5|#sometag1:somevalue1"))
+	assert.EqualError(t, err, "dogstatsd sample rate is not supported for sketches and histogram buckets")
+
+	_, err = parseMetricSample(t, make(map[string]any), []byte("daemon:"+encodeTestSketch(t, 1)+"|sk|@0.5"))
+	assert.EqualError(t, err, "dogstatsd sample rate is not supported for sketches and histogram buckets")
+}
+
+func TestParseSketchWithTimestamp(t *testing.T) {
+	cfg := map[string]any{"dogstatsd_no_aggregation_pipeline": true}
+
+	_, err := parseMetricSample(t, cfg, []byte("daemon:0,10,5|hb|T1657100430"))
+	assert.Error(t, err)
+
+	// timestamps are ignored when the no aggregation pipeline is disabled
+	cfg["dogstatsd_no_aggregation_pipeline"] = false
+	sample, err := parseMetricSample(t, cfg, []byte("daemon:0,10,5|hb|T1657100430"))
+	assert.NoError(t, err)
+	assert.Zero(t, sample.ts)
+}
+
+func TestEnrichSketch(t *testing.T) {
+	sample, err := parseMetricSample(t, make(map[string]any), []byte("daemon:0,10,5|hb"))
+	require.NoError(t, err)
+
+	samples := enrichMetricSample(nil, sample, "", "", enrichConfig{})
+	require.Len(t, samples, 1)
+	assert.Equal(t, metrics.DistributionType, samples[0].Mtype)
+	assert.Same(t, sample.sketch, samples[0].Sketch)
+}
+
+func runParseSketchBenchmark(b *testing.B, buildRawSample func(size int) []byte) {
+	deps := newServerDeps(b)
+	stringInternerTelemetry := newSiTelemetry(false, deps.Telemetry)
+	parser := newParser(deps.Config, newFloat64ListPool(deps.Telemetry), 1, deps.WMeta, stringInternerTelemetry)
+
+	conf := enrichConfig{
+		defaultHostname:           "default-hostname",
+		entityIDPrecedenceEnabled: true,
+	}
+
+	for i := 1; i <= 1024; i *= 4 {
+		b.Run(fmt.Sprintf("%d", i), func(sb *testing.B) {
+			rawSample := buildRawSample(i)
+			sb.ResetTimer()
+			samples := make([]metrics.MetricSample, 0, 1)
+
+			for n := 0; n < sb.N; n++ {
+				parsed, err := parser.parseMetricSample(rawSample)
+				if err != nil {
+					sb.Fatal(err)
+				}
+
+				benchSamples = enrichMetricSample(samples, parsed, "", "", conf)
+			}
+		})
+	}
+}
+
+// BenchmarkParseSketch benchmarks sketches of a growing number of distinct values.
+func BenchmarkParseSketch(b *testing.B) {
+	runParseSketchBenchmark(b, func(size int) []byte {
+		values := make([]float64, size)
+		for i := range values {
+			values[i] = float64(i * 10)
+		}
+		return []byte(fmt.Sprintf("daemon:%s|sk|#tag0:val0,tag1:val1", encodeTestSketch(b, values...)))
+	})
+}
+
+// BenchmarkParseHistogramBuckets benchmarks a growing number of histogram buckets.
+func BenchmarkParseHistogramBuckets(b *testing.B) {
+	runParseSketchBenchmark(b, func(size int) []byte {
+		buckets := make([]string, size)
+		for i := range buckets {
+			buckets[i] = fmt.Sprintf("%d,%d,100", i*10, (i+1)*10)
+		}
+		return []byte(fmt.Sprintf("daemon:%s|hb|#tag0:val0,tag1:val1", strings.Join(buckets, ":")))
+	})
+}
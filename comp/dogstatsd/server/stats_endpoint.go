@@ -9,10 +9,11 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
 	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
 )
 
-func (s *server) writeStats(w http.ResponseWriter, _ *http.Request) {
+func (s *server) writeStats(w http.ResponseWriter, r *http.Request) {
 	s.log.Info("Got a request for the Dogstatsd stats.")
 
 	if !s.config.GetBool("use_dogstatsd") {
@@ -26,6 +27,11 @@ func (s *server) writeStats(w http.ResponseWriter, _ *http.Request) {
 		return
 	}
 
+	if r.URL.Query().Get("tag_cardinality") == "true" {
+		s.writeTagCardinalityStats(w)
+		return
+	}
+
 	if !s.config.GetBool("dogstatsd_metrics_stats_enable") {
 		w.Header().Set("Content-Type", "application/json")
 		body, _ := json.Marshal(map[string]string{
@@ -54,3 +60,31 @@ func (s *server) writeStats(w http.ResponseWriter, _ *http.Request) {
 
 	w.Write(jsonStats)
 }
+
+// writeTagCardinalityStats writes the tags of the metrics received by dogstatsd which
+// have the most distinct values.
+func (s *server) writeTagCardinalityStats(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if !s.config.GetBool("dogstatsd_tag_cardinality_enabled") {
+		body, _ := json.Marshal(map[string]string{
+			"error":      "Dogstatsd tag cardinality tracking not enabled in the Agent configuration",
+			"error_type": "not enabled",
+		})
+		w.WriteHeader(400)
+		w.Write(body)
+		return
+	}
+
+	stats := aggregator.GetTagCardinalityStats()
+	if stats == nil {
+		stats = []aggregator.TagCardinalityStats{}
+	}
+	jsonStats, err := json.Marshal(stats)
+	if err != nil {
+		httputils.SetJSONError(w, s.log.Errorf("Error getting marshalled Dogstatsd tag cardinality stats: %s", err), 500)
+		return
+	}
+
+	w.Write(jsonStats)
+}
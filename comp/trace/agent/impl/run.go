@@ -73,6 +73,13 @@ func runAgentSidekicks(ag component) error {
 		log.Errorf("could not set auth token: %s", err)
 	} else {
 		ag.Agent.DebugServer.AddRoute("/config", ag.config.GetConfigHandler())
+		ag.Agent.DebugServer.AddRoute("/debug/traces", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			// the stream exposes resources before obfuscation
+			if apiutil.Validate(w, req) != nil {
+				return
+			}
+			ag.Agent.TraceStream.ServeHTTP(w, req)
+		}))
 	}
 
 	api.AttachEndpoint(api.Endpoint{
@@ -2463,6 +2463,44 @@ func TestPeerServiceAggregation(t *testing.T) {
 	})
 }
 
+func TestDiskBuffer(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		config := fxutil.Test[Component](t, fx.Options(
+			corecomp.MockModule(),
+			MockModule(),
+		))
+		cfg := config.Object()
+
+		require.NotNil(t, cfg)
+		assert.False(t, cfg.DiskBuffer.Enabled)
+		assert.Equal(t, int64(256*1024*1024), cfg.DiskBuffer.MaxSizeBytes)
+		assert.Equal(t, 6*time.Hour, cfg.DiskBuffer.MaxAge)
+		assert.True(t, strings.HasSuffix(cfg.DiskBuffer.Path, filepath.Join("trace-agent", "disk_buffer")))
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		overrides := map[string]interface{}{
+			"apm_config.disk_buffer.enabled":         true,
+			"apm_config.disk_buffer.path":            "/var/lib/datadog/apm",
+			"apm_config.disk_buffer.max_size_mb":     64,
+			"apm_config.disk_buffer.max_age_seconds": 600,
+		}
+
+		config := fxutil.Test[Component](t, fx.Options(
+			corecomp.MockModule(),
+			fx.Replace(corecomp.MockParams{Overrides: overrides}),
+			MockModule(),
+		))
+		cfg := config.Object()
+
+		require.NotNil(t, cfg)
+		assert.True(t, cfg.DiskBuffer.Enabled)
+		assert.Equal(t, "/var/lib/datadog/apm", cfg.DiskBuffer.Path)
+		assert.Equal(t, int64(64*1024*1024), cfg.DiskBuffer.MaxSizeBytes)
+		assert.Equal(t, 10*time.Minute, cfg.DiskBuffer.MaxAge)
+	})
+}
+
 func TestComputeStatsBySpanKind(t *testing.T) {
 	t.Run("disabled", func(t *testing.T) {
 		config := fxutil.Test[Component](t, fx.Options(
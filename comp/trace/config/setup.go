@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -542,6 +543,18 @@ func applyDatadogConfig(c *config.AgentConfig, core corecompcfg.Component) error
 	if core.IsSet("apm_config.sync_flushing") {
 		c.SynchronousFlushing = core.GetBool("apm_config.sync_flushing")
 	}
+	c.DiskBuffer.Enabled = core.GetBool("apm_config.disk_buffer.enabled")
+	if core.IsSet("apm_config.disk_buffer.path") {
+		c.DiskBuffer.Path = core.GetString("apm_config.disk_buffer.path")
+	} else {
+		c.DiskBuffer.Path = filepath.Join(core.GetString("run_path"), "trace-agent", "disk_buffer")
+	}
+	if mb := core.GetInt64("apm_config.disk_buffer.max_size_mb"); mb > 0 {
+		c.DiskBuffer.MaxSizeBytes = mb * 1024 * 1024
+	}
+	if core.IsSet("apm_config.disk_buffer.max_age_seconds") {
+		c.DiskBuffer.MaxAge = getDuration(core.GetInt("apm_config.disk_buffer.max_age_seconds"))
+	}
 
 	// undocumented deprecated
 	if core.IsSet("apm_config.analyzed_rate_by_service") {
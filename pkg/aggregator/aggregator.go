@@ -191,6 +191,7 @@ func init() {
 	aggregatorExpvars.Set("OrchestratorManifests", &aggregatorOrchestratorManifests)
 	aggregatorExpvars.Set("OrchestratorManifestsErrors", &aggregatorOrchestratorManifestsErrors)
 	aggregatorExpvars.Set("DogstatsdContexts", &aggregatorDogstatsdContexts)
+	aggregatorExpvars.Set("TagCardinality", expvar.Func(func() interface{} { return GetTagCardinalityStats() }))
 	aggregatorExpvars.Set("EventPlatformEvents", &aggregatorEventPlatformEvents)
 	aggregatorExpvars.Set("EventPlatformEventsErrors", &aggregatorEventPlatformEventsErrors)
 
@@ -75,6 +75,9 @@ type contextResolver struct {
 	keyGenerator     *ckey.KeyGenerator
 	taggerBuffer     *tagset.HashingTagsAccumulator
 	metricBuffer     *tagset.HashingTagsAccumulator
+	// cardinalityGuard limits the number of distinct values of the tags of new
+	// contexts, it is nil when the cardinality of tags is not tracked.
+	cardinalityGuard *tagCardinalityGuard
 }
 
 // generateContextKey generates the contextKey associated with the context of the metricSample
@@ -105,7 +108,21 @@ func (cr *contextResolver) trackContext(metricSampleContext metrics.MetricSample
 
 	contextKey, taggerKey, metricKey := cr.generateContextKey(metricSampleContext) // the generator will remove duplicates (and doesn't mind the order)
 
-	if entry, ok := cr.contextsByKey[contextKey]; !ok {
+	entry, ok := cr.contextsByKey[contextKey]
+	if !ok && cr.cardinalityGuard != nil {
+		// only new contexts can add tag values, check them against the limit and
+		// track them at once, since the guard is shared with the other samplers
+		cr.cardinalityGuard.mu.Lock()
+		defer cr.cardinalityGuard.mu.Unlock()
+		if limitedTags, limited := cr.cardinalityGuard.applyLocked(metricSampleContext.GetName(), cr.metricBuffer.Get()); limited {
+			cr.metricBuffer.Reset()
+			cr.metricBuffer.Append(limitedTags...)
+			contextKey, taggerKey, metricKey = cr.generateContextKey(metricSampleContext)
+			entry, ok = cr.contextsByKey[contextKey]
+		}
+	}
+
+	if !ok {
 		mtype := metricSampleContext.GetMetricType()
 		context := &Context{
 			Name:       metricSampleContext.GetName(),
@@ -125,6 +142,10 @@ func (cr *contextResolver) trackContext(metricSampleContext metrics.MetricSample
 		cr.countsByMtype[mtype]++
 		cr.bytesByMtype[mtype] += uint64(context.SizeInBytes())
 		cr.dataBytesByMtype[mtype] += uint64(context.DataSizeInBytes())
+
+		if cr.cardinalityGuard != nil {
+			cr.cardinalityGuard.trackLocked(context.Name, context.metricTags.Tags())
+		}
 	} else {
 		// We can't assign to a field of a struct contained in map
 		cr.contextsByKey[contextKey] = resolverEntry{
@@ -153,6 +174,9 @@ func (cr *contextResolver) remove(expiredContextKey ckey.ContextKey) {
 		cr.countsByMtype[context.mtype]--
 		cr.bytesByMtype[context.mtype] -= uint64(context.SizeInBytes())
 		cr.dataBytesByMtype[context.mtype] -= uint64(context.DataSizeInBytes())
+		if cr.cardinalityGuard != nil {
+			cr.cardinalityGuard.release(context.Name, context.metricTags.Tags())
+		}
 		context.release()
 	}
 }
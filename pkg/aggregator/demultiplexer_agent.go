@@ -165,11 +165,18 @@ func initAgentDemultiplexer(
 
 	statsdWorkers := make([]*timeSamplerWorker, statsdPipelinesCount)
 
+	// the tag cardinality guard is shared by the samplers since contexts are sharded, not tag values
+	tagCardinalityGuard := newTagCardinalityGuardFromConfig(config.Datadog())
+	activeTagCardinalityGuard.Store(tagCardinalityGuard)
+
 	for i := 0; i < statsdPipelinesCount; i++ {
 		// the sampler
 		tagsStore := tags.NewStore(config.Datadog().GetBool("aggregator_use_tags_store"), fmt.Sprintf("timesampler #%d", i))
 
 		statsdSampler := NewTimeSampler(TimeSamplerID(i), bucketSize, tagsStore, agg.hostname)
+		if tagCardinalityGuard != nil {
+			statsdSampler.setTagCardinalityGuard(tagCardinalityGuard)
+		}
 
 		// its worker (process loop + flush/serialization mechanism)
 
@@ -13,6 +13,10 @@ import (
 	"github.com/DataDog/opentelemetry-mapping-go/pkg/quantile"
 )
 
+// sketchConfig is the configuration of the sketches of the map, used to merge
+// sketches aggregated elsewhere.
+var sketchConfig = quantile.Default()
+
 type sketchMap map[int64]map[ckey.ContextKey]*quantile.Agent
 
 // Len returns the number of sketches stored
@@ -48,6 +52,12 @@ func (m sketchMap) insertInterp(ts int64, ck ckey.ContextKey, lower float64, upp
 	return true
 }
 
+// merge a sketch aggregated elsewhere into the sketch for the given (ts, contextKey)
+// NOTE: ts is truncated to bucketSize
+func (m sketchMap) merge(ts int64, ck ckey.ContextKey, sketch *quantile.Sketch) {
+	m.getOrCreate(ts, ck).Sketch.Merge(sketchConfig, sketch)
+}
+
 func (m sketchMap) getOrCreate(ts int64, ck ckey.ContextKey) *quantile.Agent {
 	// level 1: ts -> ctx
 	byCtx, ok := m[ts]
@@ -0,0 +1,312 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package aggregator
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// tagCardinalityPlaceholder replaces the values of tags over the cardinality limit
+	// when they are collapsed.
+	tagCardinalityPlaceholder = "cardinality_limited"
+	// tagCardinalityTopN is the number of tag keys reported by GetTagCardinalityStats.
+	tagCardinalityTopN = 10
+	// tagCardinalityMaxTelemetryKeys is the maximum number of metric and tag key pairs
+	// reported as labels of the telemetry. Other pairs are reported under
+	// tagCardinalityOtherLabel, so that the telemetry stays bounded.
+	tagCardinalityMaxTelemetryKeys = 100
+	tagCardinalityOtherLabel       = "other"
+)
+
+// tagCardinalityAction is what is done to the values of tags over the cardinality limit.
+type tagCardinalityAction int
+
+const (
+	// tagCardinalityCollapse replaces the value of the tag by tagCardinalityPlaceholder.
+	tagCardinalityCollapse tagCardinalityAction = iota
+	// tagCardinalityDrop removes the tag.
+	tagCardinalityDrop
+)
+
+var (
+	// tag values are never used as labels, and the number of label sets is capped
+	tlmTagCardinalityLimited = telemetry.NewCounter("aggregator", "dogstatsd_tag_cardinality_limited",
+		[]string{"metric_name", "tag_key"}, "Count of dogstatsd tag values collapsed or dropped because of the tag cardinality limit")
+
+	// activeTagCardinalityGuard is the guard shared by the time samplers of the running demultiplexer.
+	activeTagCardinalityGuard = atomic.NewPointer[tagCardinalityGuard](nil)
+
+	// timeNow is overridden in tests
+	timeNow = time.Now
+)
+
+// TagCardinalityStats describes the distinct values of a tag key of a metric.
+type TagCardinalityStats struct {
+	Metric string `json:"metric"`
+	Tag    string `json:"tag"`
+	// Values is the number of distinct values of the tag in the tracking window.
+	Values int `json:"values"`
+	// Limited is the number of new contexts whose value of the tag was collapsed
+	// or dropped because of the cardinality limit.
+	Limited uint64 `json:"limited"`
+}
+
+// GetTagCardinalityStats returns the metric and tag key pairs with the most distinct
+// values among the dogstatsd contexts, or nil if tag cardinality tracking is disabled.
+func GetTagCardinalityStats() []TagCardinalityStats {
+	g := activeTagCardinalityGuard.Load()
+	if g == nil {
+		return nil
+	}
+	return g.topN(tagCardinalityTopN)
+}
+
+type tagCardinalityKey struct {
+	metric string
+	tag    string
+}
+
+type tagValueEntry struct {
+	refs     int   // number of tracked contexts using the value
+	released int64 // time at which the last context using the value was removed
+}
+
+type tagCardinality struct {
+	values  map[string]*tagValueEntry
+	limited uint64
+}
+
+// tagCardinalityGuard tracks the number of distinct values of every tag key of every
+// metric among the contexts of the time samplers, and keeps it under a limit by
+// collapsing or dropping the values which would take it over the limit.
+//
+// A value is counted as long as a context using it is tracked, and for a sliding
+// window once the last of these contexts has expired, so that values of short lived
+// contexts still count towards the limit. Only the tags sent by the clients are
+// considered, and only new contexts are checked, so samples of existing contexts are
+// not slowed down. The guard is shared by all the time samplers, which shard their
+// contexts but not their tag values, so a new context is checked and tracked under
+// the same lock: otherwise concurrent contexts could all pass the check.
+type tagCardinalityGuard struct {
+	limit  int   // max number of distinct values of a tag, 0 to only track them
+	window int64 // in seconds
+	action tagCardinalityAction
+
+	mu   sync.Mutex
+	keys map[tagCardinalityKey]*tagCardinality
+	// telemetryKeys are the metric and tag key pairs reported as labels of the telemetry
+	telemetryKeys map[tagCardinalityKey]struct{}
+}
+
+func newTagCardinalityGuard(limit int, window int64, action tagCardinalityAction) *tagCardinalityGuard {
+	return &tagCardinalityGuard{
+		limit:  limit,
+		window: window,
+		action: action,
+		keys:   make(map[tagCardinalityKey]*tagCardinality),
+
+		telemetryKeys: make(map[tagCardinalityKey]struct{}),
+	}
+}
+
+// newTagCardinalityGuardFromConfig returns the guard configured by the
+// dogstatsd_tag_cardinality_* settings, or nil if it is disabled.
+func newTagCardinalityGuardFromConfig(cfg config.Reader) *tagCardinalityGuard {
+	if !cfg.GetBool("dogstatsd_tag_cardinality_enabled") {
+		return nil
+	}
+	action := tagCardinalityCollapse
+	switch a := cfg.GetString("dogstatsd_tag_cardinality_limit_action"); a {
+	case "collapse":
+	case "drop":
+		action = tagCardinalityDrop
+	default:
+		log.Warnf("Unknown dogstatsd_tag_cardinality_limit_action %q, tag values over the limit will be collapsed", a)
+	}
+	limit := cfg.GetInt("dogstatsd_tag_cardinality_limit")
+	if limit < 0 {
+		limit = 0
+	}
+	return newTagCardinalityGuard(limit, cfg.GetInt64("dogstatsd_tag_cardinality_window_seconds"), action)
+}
+
+// splitTag returns the key and the value of a tag, ok is false for tags without
+// value, which aren't tracked.
+func splitTag(tag string) (key string, value string, ok bool) {
+	key, value, ok = strings.Cut(tag, ":")
+	return key, value, ok && value != tagCardinalityPlaceholder
+}
+
+// applyLocked returns the tags to use for a new context of the given metric, in which
+// the values which would take the number of distinct values of their key over the
+// limit are collapsed or dropped. tags is returned as is when no value is over the
+// limit. g.mu must be held until the context is tracked.
+func (g *tagCardinalityGuard) applyLocked(metric string, tags []string) ([]string, bool) {
+	if g.limit == 0 {
+		return tags, false
+	}
+
+	var out []string
+	for i, tag := range tags {
+		key, value, ok := splitTag(tag)
+		if ok {
+			k := tagCardinalityKey{metric, key}
+			tc := g.keys[k]
+			if tc != nil && len(tc.values) >= g.limit && tc.values[value] == nil {
+				if tc.limited == 0 {
+					log.Warnf("Tag %q of metric %q has more than %d distinct values, new values are now %s", key, metric, g.limit, g.actionString())
+				}
+				tc.limited++
+				g.incTelemetryLocked(k)
+				if out == nil {
+					out = append(make([]string, 0, len(tags)), tags[:i]...)
+				}
+				if g.action == tagCardinalityCollapse {
+					out = append(out, key+":"+tagCardinalityPlaceholder)
+				}
+				continue
+			}
+		}
+		if out != nil {
+			out = append(out, tag)
+		}
+	}
+	if out == nil {
+		return tags, false
+	}
+	return out, true
+}
+
+// incTelemetryLocked counts a limited value of the tag key of a metric in the
+// telemetry. The first pairs limited are reported as labels, the others are
+// aggregated. g.mu must be held.
+func (g *tagCardinalityGuard) incTelemetryLocked(k tagCardinalityKey) {
+	if _, ok := g.telemetryKeys[k]; !ok {
+		if len(g.telemetryKeys) >= tagCardinalityMaxTelemetryKeys {
+			tlmTagCardinalityLimited.Inc(tagCardinalityOtherLabel, tagCardinalityOtherLabel)
+			return
+		}
+		g.telemetryKeys[k] = struct{}{}
+	}
+	tlmTagCardinalityLimited.Inc(k.metric, k.tag)
+}
+
+func (g *tagCardinalityGuard) actionString() string {
+	if g.action == tagCardinalityDrop {
+		return "dropped"
+	}
+	return "collapsed to " + tagCardinalityPlaceholder
+}
+
+// trackLocked counts the tag values of a new context. g.mu must be held.
+func (g *tagCardinalityGuard) trackLocked(metric string, tags []string) {
+	for _, tag := range tags {
+		key, value, ok := splitTag(tag)
+		if !ok {
+			continue
+		}
+		k := tagCardinalityKey{metric, key}
+		tc := g.keys[k]
+		if tc == nil {
+			tc = &tagCardinality{values: make(map[string]*tagValueEntry)}
+			g.keys[k] = tc
+		}
+		e := tc.values[value]
+		if e == nil {
+			e = &tagValueEntry{}
+			tc.values[value] = e
+		}
+		e.refs++
+	}
+}
+
+// release stops counting the tag values of an expired context. The values are
+// still counted for the duration of the window.
+func (g *tagCardinalityGuard) release(metric string, tags []string) {
+	now := timeNow().Unix()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, tag := range tags {
+		key, value, ok := splitTag(tag)
+		if !ok {
+			continue
+		}
+		tc := g.keys[tagCardinalityKey{metric, key}]
+		if tc == nil {
+			continue
+		}
+		if e := tc.values[value]; e != nil && e.refs > 0 {
+			e.refs--
+			if e.refs == 0 {
+				e.released = now
+			}
+		}
+	}
+}
+
+// expire forgets the values which haven't been used by any context for the
+// duration of the window.
+func (g *tagCardinalityGuard) expire() {
+	now := timeNow().Unix()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for k, tc := range g.keys {
+		for value, e := range tc.values {
+			if e.refs == 0 && e.released+g.window < now {
+				delete(tc.values, value)
+			}
+		}
+		if len(tc.values) == 0 {
+			delete(g.keys, k)
+		}
+	}
+}
+
+// topN returns the n metric and tag key pairs with the most distinct values.
+func (g *tagCardinalityGuard) topN(n int) []TagCardinalityStats {
+	g.mu.Lock()
+	stats := make([]TagCardinalityStats, 0, len(g.keys))
+	for k, tc := range g.keys {
+		stats = append(stats, TagCardinalityStats{
+			Metric:  k.metric,
+			Tag:     k.tag,
+			Values:  len(tc.values),
+			Limited: tc.limited,
+		})
+	}
+	g.mu.Unlock()
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Values != stats[j].Values {
+			return stats[i].Values > stats[j].Values
+		}
+		if stats[i].Limited != stats[j].Limited {
+			return stats[i].Limited > stats[j].Limited
+		}
+		if stats[i].Metric != stats[j].Metric {
+			return stats[i].Metric < stats[j].Metric
+		}
+		return stats[i].Tag < stats[j].Tag
+	})
+	if len(stats) > n {
+		stats = stats[:n]
+	}
+	return stats
+}
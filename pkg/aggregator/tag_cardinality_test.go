@@ -0,0 +1,183 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build test
+
+package aggregator
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/internal/tags"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+)
+
+func setTimeNow(t *testing.T, now time.Time) {
+	old := timeNow
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = old })
+}
+
+func TestTagCardinalityGuard(t *testing.T) {
+	t.Run("collapse", func(t *testing.T) {
+		g := newTagCardinalityGuard(2, 60, tagCardinalityCollapse)
+		for _, user := range []string{"user:a", "user:b"} {
+			out, limited := g.applyLocked("metric", []string{"env:prod", user})
+			assert.False(t, limited)
+			g.trackLocked("metric", out)
+		}
+
+		out, limited := g.applyLocked("metric", []string{"env:prod", "user:c", "flag"})
+		assert.True(t, limited)
+		assert.Equal(t, []string{"env:prod", "user:" + tagCardinalityPlaceholder, "flag"}, out)
+		g.trackLocked("metric", out)
+
+		// known values and other metrics aren't limited
+		_, limited = g.applyLocked("metric", []string{"env:prod", "user:a"})
+		assert.False(t, limited)
+		_, limited = g.applyLocked("other", []string{"user:c"})
+		assert.False(t, limited)
+
+		assert.Equal(t, []TagCardinalityStats{
+			{Metric: "metric", Tag: "user", Values: 2, Limited: 1},
+			{Metric: "metric", Tag: "env", Values: 1},
+		}, g.topN(10))
+		assert.Len(t, g.topN(1), 1)
+	})
+
+	t.Run("drop", func(t *testing.T) {
+		g := newTagCardinalityGuard(1, 60, tagCardinalityDrop)
+		g.trackLocked("metric", []string{"user:a"})
+		out, limited := g.applyLocked("metric", []string{"env:prod", "user:b"})
+		assert.True(t, limited)
+		assert.Equal(t, []string{"env:prod"}, out)
+	})
+
+	t.Run("track only", func(t *testing.T) {
+		g := newTagCardinalityGuard(0, 60, tagCardinalityCollapse)
+		g.trackLocked("metric", []string{"user:a"})
+		_, limited := g.applyLocked("metric", []string{"user:b"})
+		assert.False(t, limited)
+	})
+
+	t.Run("telemetry", func(t *testing.T) {
+		g := newTagCardinalityGuard(1, 60, tagCardinalityCollapse)
+		for i := 0; i <= tagCardinalityMaxTelemetryKeys; i++ {
+			metric := fmt.Sprintf("telemetry.metric%d", i)
+			g.trackLocked(metric, []string{"user:a"})
+			_, limited := g.applyLocked(metric, []string{"user:b"})
+			assert.True(t, limited)
+		}
+
+		// tag values are never used as labels, and pairs over the cap are aggregated
+		assert.Equal(t, 1.0, tlmTagCardinalityLimited.WithValues("telemetry.metric0", "user").Get())
+		assert.Equal(t, 0.0, tlmTagCardinalityLimited.WithValues(fmt.Sprintf("telemetry.metric%d", tagCardinalityMaxTelemetryKeys), "user").Get())
+		assert.Equal(t, 1.0, tlmTagCardinalityLimited.WithValues(tagCardinalityOtherLabel, tagCardinalityOtherLabel).Get())
+		assert.Len(t, g.telemetryKeys, tagCardinalityMaxTelemetryKeys)
+	})
+
+	t.Run("window", func(t *testing.T) {
+		now := time.Unix(1000, 0)
+		setTimeNow(t, now)
+
+		g := newTagCardinalityGuard(1, 60, tagCardinalityCollapse)
+		g.trackLocked("metric", []string{"user:a"})
+		g.trackLocked("metric", []string{"user:a", "env:prod"})
+
+		g.release("metric", []string{"user:a"})
+		setTimeNow(t, now.Add(2*time.Minute))
+		g.expire()
+		// still used by a context
+		_, limited := g.applyLocked("metric", []string{"user:b"})
+		assert.True(t, limited)
+
+		g.release("metric", []string{"user:a", "env:prod"})
+		setTimeNow(t, now.Add(3*time.Minute))
+		g.expire()
+		// released within the window
+		_, limited = g.applyLocked("metric", []string{"user:b"})
+		assert.True(t, limited)
+
+		setTimeNow(t, now.Add(5*time.Minute))
+		g.expire()
+		_, limited = g.applyLocked("metric", []string{"user:b"})
+		assert.False(t, limited)
+		assert.Empty(t, g.topN(10))
+	})
+}
+
+func testTimeSamplerTagCardinality(t *testing.T, store *tags.Store) {
+	sampler := testTimeSampler(store)
+	sampler.setTagCardinalityGuard(newTagCardinalityGuard(2, 60, tagCardinalityCollapse))
+
+	for _, user := range []string{"user:a", "user:b", "user:c", "user:d"} {
+		sampler.sample(&metrics.MetricSample{
+			Name:       "my.metric.name",
+			Value:      1,
+			Mtype:      metrics.CountType,
+			Tags:       []string{"env:prod", user},
+			SampleRate: 1,
+		}, 12345.0)
+	}
+
+	series, _ := flushSerie(sampler, 12360.0)
+	var users []string
+	for _, serie := range series {
+		serie.Tags.ForEach(func(tag string) {
+			if tag != "env:prod" {
+				users = append(users, tag)
+			}
+		})
+		if serie.Tags.Find(func(tag string) bool { return tag == "user:"+tagCardinalityPlaceholder }) {
+			assert.Equal(t, 2.0, serie.Points[0].Value)
+		}
+	}
+	sort.Strings(users)
+	assert.Equal(t, []string{"user:a", "user:b", "user:" + tagCardinalityPlaceholder}, users)
+
+	stats := sampler.contextResolver.resolver.cardinalityGuard.topN(1)
+	require.Len(t, stats, 1)
+	assert.Equal(t, TagCardinalityStats{Metric: "my.metric.name", Tag: "user", Values: 2, Limited: 2}, stats[0])
+}
+
+func TestTimeSamplerTagCardinality(t *testing.T) {
+	testWithTagsStore(t, testTimeSamplerTagCardinality)
+}
+
+func TestTimeSamplersSharedTagCardinality(t *testing.T) {
+	guard := newTagCardinalityGuard(5, 60, tagCardinalityCollapse)
+
+	// the samplers share the guard, the limit holds across them
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		sampler := testTimeSampler(tags.NewStore(true, "test"))
+		sampler.setTagCardinalityGuard(guard)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				sampler.sample(&metrics.MetricSample{
+					Name:       "my.metric.name",
+					Value:      1,
+					Mtype:      metrics.CountType,
+					Tags:       []string{fmt.Sprintf("user:%d-%d", i, j)},
+					SampleRate: 1,
+				}, 12345.0)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	stats := guard.topN(1)
+	require.Len(t, stats, 1)
+	assert.Equal(t, TagCardinalityStats{Metric: "my.metric.name", Tag: "user", Values: 5, Limited: 195}, stats[0])
+}
@@ -68,6 +68,12 @@ func NewTimeSampler(id TimeSamplerID, interval int64, cache *tags.Store, hostnam
 	return s
 }
 
+// setTagCardinalityGuard makes the sampler limit the cardinality of the tags of its
+// new contexts with g, which may be shared with other samplers.
+func (s *TimeSampler) setTagCardinalityGuard(g *tagCardinalityGuard) {
+	s.contextResolver.resolver.cardinalityGuard = g
+}
+
 func (s *TimeSampler) calculateBucketStart(timestamp float64) int64 {
 	return int64(timestamp) - int64(timestamp)%s.interval
 }
@@ -88,6 +94,10 @@ func (s *TimeSampler) sample(metricSample *metrics.MetricSample, timestamp float
 
 	switch metricSample.Mtype {
 	case metrics.DistributionType:
+		if metricSample.Sketch != nil {
+			s.sketchMap.merge(bucketStart, contextKey, metricSample.Sketch)
+			break
+		}
 		s.sketchMap.insert(bucketStart, contextKey, metricSample.Value, metricSample.SampleRate)
 	default:
 		// If it's a new bucket, initialize it
@@ -225,6 +235,9 @@ func (s *TimeSampler) flush(timestamp float64, series metrics.SerieSink, sketche
 	s.flushSketches(cutoffTime, sketches)
 	// expiring contexts
 	s.contextResolver.expireContexts(int64(timestamp))
+	if g := s.contextResolver.resolver.cardinalityGuard; g != nil {
+		g.expire()
+	}
 	s.lastCutOffTime = cutoffTime
 
 	s.updateMetrics()
@@ -444,6 +444,52 @@ func TestSketchContextSampling(t *testing.T) {
 	testWithTagsStore(t, testSketchContextSampling)
 }
 
+func testSketchMerge(t *testing.T, store *tags.Store) {
+	sampler := testTimeSampler(store)
+
+	clientSketch := &quantile.Sketch{}
+	clientSketch.Insert(quantile.Default(), 2, 3, 4)
+
+	mSample1 := metrics.MetricSample{
+		Name:       "test.metric.name",
+		Value:      1,
+		Mtype:      metrics.DistributionType,
+		Tags:       []string{"a", "b"},
+		SampleRate: 1,
+	}
+	mSample2 := metrics.MetricSample{
+		Name:       "test.metric.name",
+		Mtype:      metrics.DistributionType,
+		Tags:       []string{"a", "b"},
+		SampleRate: 1,
+		Sketch:     clientSketch,
+	}
+	sampler.sample(&mSample1, 10001)
+	sampler.sample(&mSample2, 10002)
+	sampler.sample(&mSample2, 10003)
+
+	_, flushed := flushSerie(sampler, 10010)
+	expSketch := &quantile.Sketch{}
+	expSketch.Insert(quantile.Default(), 1, 2, 3, 4, 2, 3, 4)
+
+	require.Equal(t, 1, len(flushed))
+	metrics.AssertSketchSeriesEqual(t, &metrics.SketchSeries{
+		Name:     "test.metric.name",
+		Tags:     tagset.CompositeTagsFromSlice([]string{"a", "b"}),
+		Interval: 10,
+		Points: []metrics.SketchPoint{
+			{Ts: 10000, Sketch: expSketch},
+		},
+		ContextKey: generateContextKey(&mSample1),
+	}, flushed[0])
+
+	// the sketch sent by the client is left untouched
+	assert.EqualValues(t, 3, clientSketch.Basic.Cnt)
+}
+func TestSketchMerge(t *testing.T) {
+	testWithTagsStore(t, testSketchMerge)
+}
+
 func testBucketSamplingWithSketchAndSeries(t *testing.T, store *tags.Store) {
 	sampler := testTimeSampler(store)
 
@@ -30,6 +30,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate/config"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate/cwsinstrumentation"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate/tagsfromlabels"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/validate/annotations"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/autoscaling/workload"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
@@ -38,6 +39,7 @@ import (
 type Controller interface {
 	Run(stopCh <-chan struct{})
 	EnabledWebhooks() []MutatingWebhook
+	EnabledValidatingWebhooks() []ValidatingWebhook
 }
 
 // NewController returns the adequate implementation of the Controller interface.
@@ -52,14 +54,14 @@ func NewController(
 	pa workload.PodPatcher,
 ) Controller {
 	if config.useAdmissionV1() {
-		return NewControllerV1(client, secretInformer, admissionInterface.V1().MutatingWebhookConfigurations(), isLeaderFunc, isLeaderNotif, config, wmeta, pa)
+		return NewControllerV1(client, secretInformer, admissionInterface.V1().MutatingWebhookConfigurations(), admissionInterface.V1().ValidatingWebhookConfigurations(), isLeaderFunc, isLeaderNotif, config, wmeta, pa)
 	}
 
 	return NewControllerV1beta1(client, secretInformer, admissionInterface.V1beta1().MutatingWebhookConfigurations(), isLeaderFunc, isLeaderNotif, config, wmeta, pa)
 }
 
-// MutatingWebhook represents a mutating webhook
-type MutatingWebhook interface {
+// Webhook contains the methods shared by mutating and validating webhooks
+type Webhook interface {
 	// Name returns the name of the webhook
 	Name() string
 	// IsEnabled returns whether the webhook is enabled
@@ -75,10 +77,22 @@ type MutatingWebhook interface {
 	// LabelSelectors returns the label selectors that specify when the webhook
 	// should be invoked
 	LabelSelectors(useNamespaceSelector bool) (namespaceSelector *metav1.LabelSelector, objectSelector *metav1.LabelSelector)
+}
+
+// MutatingWebhook represents a mutating webhook
+type MutatingWebhook interface {
+	Webhook
 	// MutateFunc returns the function that mutates the resources
 	MutateFunc() admission.WebhookFunc
 }
 
+// ValidatingWebhook represents a validating webhook
+type ValidatingWebhook interface {
+	Webhook
+	// ValidateFunc returns the function that validates the resources
+	ValidateFunc() admission.ValidatingWebhookFunc
+}
+
 // mutatingWebhooks returns the list of mutating webhooks. Notice that the order
 // of the webhooks returned is the order in which they will be executed. For
 // now, the only restriction is that the agent sidecar webhook needs to go after
@@ -110,19 +124,29 @@ func mutatingWebhooks(wmeta workloadmeta.Component, pa workload.PodPatcher) []Mu
 	return webhooks
 }
 
+// validatingWebhooks returns the list of validating webhooks. Unlike mutating
+// webhooks, they are run in parallel by the API server, so their order doesn't
+// matter.
+func validatingWebhooks() []ValidatingWebhook {
+	return []ValidatingWebhook{
+		annotations.NewWebhook(),
+	}
+}
+
 // controllerBase acts as a base class for ControllerV1 and ControllerV1beta1.
 // It contains the shared fields and provides shared methods.
 // For the nolint:structcheck see https://github.com/golangci/golangci-lint/issues/537
 type controllerBase struct {
-	clientSet        kubernetes.Interface //nolint:structcheck
-	config           Config
-	secretsLister    corelisters.SecretLister
-	secretsSynced    cache.InformerSynced //nolint:structcheck
-	webhooksSynced   cache.InformerSynced //nolint:structcheck
-	queue            workqueue.RateLimitingInterface
-	isLeaderFunc     func() bool
-	isLeaderNotif    <-chan struct{}
-	mutatingWebhooks []MutatingWebhook
+	clientSet          kubernetes.Interface //nolint:structcheck
+	config             Config
+	secretsLister      corelisters.SecretLister
+	secretsSynced      cache.InformerSynced //nolint:structcheck
+	webhooksSynced     cache.InformerSynced //nolint:structcheck
+	queue              workqueue.RateLimitingInterface
+	isLeaderFunc       func() bool
+	isLeaderNotif      <-chan struct{}
+	mutatingWebhooks   []MutatingWebhook
+	validatingWebhooks []ValidatingWebhook
 }
 
 // EnabledWebhooks returns the list of enabled webhooks.
@@ -138,6 +162,19 @@ func (c *controllerBase) EnabledWebhooks() []MutatingWebhook {
 	return res
 }
 
+// EnabledValidatingWebhooks returns the list of enabled validating webhooks.
+func (c *controllerBase) EnabledValidatingWebhooks() []ValidatingWebhook {
+	var res []ValidatingWebhook
+
+	for _, webhook := range c.validatingWebhooks {
+		if webhook.IsEnabled() {
+			res = append(res, webhook)
+		}
+	}
+
+	return res
+}
+
 // enqueueOnLeaderNotif watches leader notifications and triggers a
 // reconciliation in case the current process becomes leader.
 // This ensures that the latest configuration of the leader
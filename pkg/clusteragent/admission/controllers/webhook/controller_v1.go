@@ -35,8 +35,14 @@ import (
 // It uses the admissionregistration/v1 API.
 type ControllerV1 struct {
 	controllerBase
-	webhooksLister   admissionlisters.MutatingWebhookConfigurationLister
-	webhookTemplates []admiv1.MutatingWebhook
+	webhooksLister             admissionlisters.MutatingWebhookConfigurationLister
+	webhookTemplates           []admiv1.MutatingWebhook
+	validatingWebhooksLister   admissionlisters.ValidatingWebhookConfigurationLister
+	validatingWebhooksSynced   cache.InformerSynced
+	validatingWebhookTemplates []admiv1.ValidatingWebhook
+	// leftoverValidatingWebhookChecked is set once the validating webhook left
+	// over when validating webhooks were enabled has been looked up
+	leftoverValidatingWebhookChecked bool
 }
 
 // NewControllerV1 returns a new Webhook Controller using admissionregistration/v1.
@@ -44,6 +50,7 @@ func NewControllerV1(
 	client kubernetes.Interface,
 	secretInformer coreinformers.SecretInformer,
 	webhookInformer admissioninformers.MutatingWebhookConfigurationInformer,
+	validatingWebhookInformer admissioninformers.ValidatingWebhookConfigurationInformer,
 	isLeaderFunc func() bool,
 	isLeaderNotif <-chan struct{},
 	config Config,
@@ -61,6 +68,7 @@ func NewControllerV1(
 	controller.isLeaderFunc = isLeaderFunc
 	controller.isLeaderNotif = isLeaderNotif
 	controller.mutatingWebhooks = mutatingWebhooks(wmeta, pa)
+	controller.validatingWebhooks = validatingWebhooks()
 	controller.generateTemplates()
 
 	if _, err := secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -79,6 +87,22 @@ func NewControllerV1(
 		log.Errorf("cannot add event handler to webhook informer: %v", err)
 	}
 
+	// The validating webhook configurations are only watched when a validating
+	// webhook is enabled, as the permission to watch them may not be granted
+	// otherwise
+	if len(controller.validatingWebhookTemplates) > 0 {
+		controller.validatingWebhooksLister = validatingWebhookInformer.Lister()
+		controller.validatingWebhooksSynced = validatingWebhookInformer.Informer().HasSynced
+
+		if _, err := validatingWebhookInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    controller.handleWebhook,
+			UpdateFunc: controller.handleValidatingWebhookUpdate,
+			DeleteFunc: controller.handleWebhook,
+		}); err != nil {
+			log.Errorf("cannot add event handler to validating webhook informer: %v", err)
+		}
+	}
+
 	return controller
 }
 
@@ -90,7 +114,12 @@ func (c *ControllerV1) Run(stopCh <-chan struct{}) {
 	log.Infof("Starting webhook controller for secret %s/%s and webhook %s - Using admissionregistration/v1", c.config.getSecretNs(), c.config.getSecretName(), c.config.getWebhookName())
 	defer log.Infof("Stopping webhook controller for secret %s/%s and webhook %s", c.config.getSecretNs(), c.config.getSecretName(), c.config.getWebhookName())
 
-	if ok := cache.WaitForCacheSync(stopCh, c.secretsSynced, c.webhooksSynced); !ok {
+	cacheSyncs := []cache.InformerSynced{c.secretsSynced, c.webhooksSynced}
+	if c.validatingWebhooksSynced != nil {
+		cacheSyncs = append(cacheSyncs, c.validatingWebhooksSynced)
+	}
+
+	if ok := cache.WaitForCacheSync(stopCh, cacheSyncs...); !ok {
 		return
 	}
 
@@ -135,13 +164,49 @@ func (c *ControllerV1) handleWebhookUpdate(oldObj, newObj interface{}) {
 	c.handleWebhook(newObj)
 }
 
-// reconcile creates/updates the webhook object on new events.
+// handleValidatingWebhookUpdate handles the new validating Webhook reported in
+// update events.
+// It can be a callback function for update events.
+func (c *ControllerV1) handleValidatingWebhookUpdate(oldObj, newObj interface{}) {
+	if !c.isLeaderFunc() {
+		return
+	}
+
+	newWebhook, ok := newObj.(*admiv1.ValidatingWebhookConfiguration)
+	if !ok {
+		log.Debugf("Expected ValidatingWebhookConfiguration object, got: %v", newObj)
+		return
+	}
+
+	oldWebhook, ok := oldObj.(*admiv1.ValidatingWebhookConfiguration)
+	if !ok {
+		log.Debugf("Expected ValidatingWebhookConfiguration object, got: %v", oldObj)
+		return
+	}
+
+	if newWebhook.ResourceVersion == oldWebhook.ResourceVersion {
+		return
+	}
+
+	c.handleWebhook(newObj)
+}
+
+// reconcile creates/updates the webhook objects on new events.
 func (c *ControllerV1) reconcile() error {
 	secret, err := c.getSecret()
 	if err != nil {
 		return err
 	}
 
+	if err := c.reconcileMutatingWebhook(secret); err != nil {
+		return err
+	}
+
+	return c.reconcileValidatingWebhook(secret)
+}
+
+// reconcileMutatingWebhook creates/updates the MutatingWebhookConfiguration object.
+func (c *ControllerV1) reconcileMutatingWebhook(secret *corev1.Secret) error {
 	webhook, err := c.webhooksLister.Get(c.config.getWebhookName())
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -157,6 +222,58 @@ func (c *ControllerV1) reconcile() error {
 	return c.updateWebhook(secret, webhook)
 }
 
+// reconcileValidatingWebhook creates/updates the ValidatingWebhookConfiguration
+// object, or deletes it when no validating webhook is enabled.
+func (c *ControllerV1) reconcileValidatingWebhook(secret *corev1.Secret) error {
+	if len(c.validatingWebhookTemplates) == 0 {
+		c.deleteLeftoverValidatingWebhook()
+		return nil
+	}
+
+	webhook, err := c.validatingWebhooksLister.Get(c.config.getWebhookName())
+	if err != nil {
+		if errors.IsNotFound(err) {
+			log.Infof("Validating Webhook %s was not found, creating it", c.config.getWebhookName())
+			return c.createValidatingWebhook(secret)
+		}
+
+		return err
+	}
+
+	log.Debugf("The validating Webhook %s was found, updating it", c.config.getWebhookName())
+
+	return c.updateValidatingWebhook(secret, webhook)
+}
+
+// deleteLeftoverValidatingWebhook deletes the ValidatingWebhookConfiguration
+// object created before validating webhooks were disabled. As the validating
+// webhook configurations are not watched, it is only looked up once. The
+// deletion is best effort since the permissions to get and delete it may not
+// have been granted.
+func (c *ControllerV1) deleteLeftoverValidatingWebhook() {
+	if c.leftoverValidatingWebhookChecked {
+		return
+	}
+	c.leftoverValidatingWebhookChecked = true
+
+	webhooks := c.clientSet.AdmissionregistrationV1().ValidatingWebhookConfigurations()
+	webhook, err := webhooks.Get(context.TODO(), c.config.getWebhookName(), metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			log.Debugf("Couldn't get the validating Webhook %s: %v", c.config.getWebhookName(), err)
+		}
+		return
+	}
+
+	err = webhooks.Delete(context.TODO(), webhook.GetName(), metav1.DeleteOptions{})
+	switch {
+	case err == nil:
+		log.Infof("No validating webhook is enabled, deleted the validating Webhook %s", webhook.GetName())
+	case !errors.IsNotFound(err):
+		log.Debugf("Couldn't delete the validating Webhook %s: %v", webhook.GetName(), err)
+	}
+}
+
 // createWebhook creates a new MutatingWebhookConfiguration object.
 func (c *ControllerV1) createWebhook(secret *corev1.Secret) error {
 	webhook := &admiv1.MutatingWebhookConfiguration{
@@ -183,6 +300,32 @@ func (c *ControllerV1) updateWebhook(secret *corev1.Secret, webhook *admiv1.Muta
 	return err
 }
 
+// createValidatingWebhook creates a new ValidatingWebhookConfiguration object.
+func (c *ControllerV1) createValidatingWebhook(secret *corev1.Secret) error {
+	webhook := &admiv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: c.config.getWebhookName(),
+		},
+		Webhooks: c.newValidatingWebhooks(secret),
+	}
+
+	_, err := c.clientSet.AdmissionregistrationV1().ValidatingWebhookConfigurations().Create(context.TODO(), webhook, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		log.Infof("Validating Webhook %s already exists", webhook.GetName())
+		return nil
+	}
+
+	return err
+}
+
+// updateValidatingWebhook stores a new configuration in the ValidatingWebhookConfiguration object.
+func (c *ControllerV1) updateValidatingWebhook(secret *corev1.Secret, webhook *admiv1.ValidatingWebhookConfiguration) error {
+	webhook = webhook.DeepCopy()
+	webhook.Webhooks = c.newValidatingWebhooks(secret)
+	_, err := c.clientSet.AdmissionregistrationV1().ValidatingWebhookConfigurations().Update(context.TODO(), webhook, metav1.UpdateOptions{})
+	return err
+}
+
 // newWebhooks generates MutatingWebhook objects from config templates with updated CABundle from Secret.
 func (c *ControllerV1) newWebhooks(secret *corev1.Secret) []admiv1.MutatingWebhook {
 	webhooks := []admiv1.MutatingWebhook{}
@@ -194,6 +337,17 @@ func (c *ControllerV1) newWebhooks(secret *corev1.Secret) []admiv1.MutatingWebho
 	return webhooks
 }
 
+// newValidatingWebhooks generates ValidatingWebhook objects from config templates with updated CABundle from Secret.
+func (c *ControllerV1) newValidatingWebhooks(secret *corev1.Secret) []admiv1.ValidatingWebhook {
+	webhooks := []admiv1.ValidatingWebhook{}
+	for _, tpl := range c.validatingWebhookTemplates {
+		tpl.ClientConfig.CABundle = certificate.GetCABundle(secret.Data)
+		webhooks = append(webhooks, tpl)
+	}
+
+	return webhooks
+}
+
 func (c *ControllerV1) generateTemplates() {
 	webhooks := []admiv1.MutatingWebhook{}
 
@@ -218,6 +372,30 @@ func (c *ControllerV1) generateTemplates() {
 	}
 
 	c.webhookTemplates = webhooks
+
+	validatingTemplates := []admiv1.ValidatingWebhook{}
+
+	for _, webhook := range c.validatingWebhooks {
+		if !webhook.IsEnabled() {
+			continue
+		}
+
+		nsSelector, objSelector := webhook.LabelSelectors(c.config.useNamespaceSelector())
+
+		validatingTemplates = append(
+			validatingTemplates,
+			c.getValidatingWebhookSkeleton(
+				webhook.Name(),
+				webhook.Endpoint(),
+				webhook.Operations(),
+				webhook.Resources(),
+				nsSelector,
+				objSelector,
+			),
+		)
+	}
+
+	c.validatingWebhookTemplates = validatingTemplates
 }
 
 func (c *ControllerV1) getWebhookSkeleton(nameSuffix, path string, operations []admiv1.OperationType, resources []string, namespaceSelector, objectSelector *metav1.LabelSelector) admiv1.MutatingWebhook {
@@ -260,6 +438,26 @@ func (c *ControllerV1) getWebhookSkeleton(nameSuffix, path string, operations []
 	return webhook
 }
 
+// getValidatingWebhookSkeleton returns a validating webhook with the same
+// settings as the mutating ones. Validating webhooks have no side effects, so
+// they are also invoked for dry-run requests.
+func (c *ControllerV1) getValidatingWebhookSkeleton(nameSuffix, path string, operations []admiv1.OperationType, resources []string, namespaceSelector, objectSelector *metav1.LabelSelector) admiv1.ValidatingWebhook {
+	mutatingWebhook := c.getWebhookSkeleton(nameSuffix, path, operations, resources, namespaceSelector, objectSelector)
+
+	return admiv1.ValidatingWebhook{
+		Name:                    mutatingWebhook.Name,
+		ClientConfig:            mutatingWebhook.ClientConfig,
+		Rules:                   mutatingWebhook.Rules,
+		FailurePolicy:           mutatingWebhook.FailurePolicy,
+		MatchPolicy:             mutatingWebhook.MatchPolicy,
+		SideEffects:             mutatingWebhook.SideEffects,
+		TimeoutSeconds:          mutatingWebhook.TimeoutSeconds,
+		AdmissionReviewVersions: mutatingWebhook.AdmissionReviewVersions,
+		NamespaceSelector:       mutatingWebhook.NamespaceSelector,
+		ObjectSelector:          mutatingWebhook.ObjectSelector,
+	}
+}
+
 func (c *ControllerV1) getAdmiV1FailurePolicy() admiv1.FailurePolicyType {
 	policy := strings.ToLower(c.config.getFailurePolicy())
 	switch policy {
@@ -22,8 +22,10 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 
 	"github.com/DataDog/datadog-agent/comp/core"
 	configComp "github.com/DataDog/datadog-agent/comp/core/config"
@@ -142,6 +144,126 @@ func TestUpdateOutdatedWebhookV1(t *testing.T) {
 	}, waitFor, tick, "Work queue isn't empty")
 }
 
+func TestCreateValidatingWebhookV1(t *testing.T) {
+	mockConfig := configmock.New(t)
+	mockConfig.SetWithoutSource("admission_controller.validate_annotations.enabled", true)
+	f := newFixtureV1(t)
+
+	data, err := certificate.GenerateSecretData(time.Now(), time.Now().Add(365*24*time.Hour), []string{"my.svc.dns"})
+	if err != nil {
+		t.Fatalf("Failed to create the Secret: %v", err)
+	}
+
+	secret := buildSecret(data, v1Cfg)
+	f.populateSecretsCache(secret)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	c := f.run(stopCh)
+
+	var webhook *admiv1.ValidatingWebhookConfiguration
+	require.Eventually(t, func() bool {
+		webhook, err = c.validatingWebhooksLister.Get(v1Cfg.getWebhookName())
+		return err == nil
+	}, waitFor, tick)
+
+	require.Len(t, webhook.Webhooks, 1)
+	assert.Equal(t, "datadog.webhook.validate.annotations", webhook.Webhooks[0].Name)
+	assert.Equal(t, "/validateannotations", *webhook.Webhooks[0].ClientConfig.Service.Path)
+	assert.Equal(t, admiv1.SideEffectClassNone, *webhook.Webhooks[0].SideEffects)
+	assert.Equal(t, certificate.GetCABundle(secret.Data), webhook.Webhooks[0].ClientConfig.CABundle)
+
+	assert.Len(t, c.EnabledValidatingWebhooks(), 1)
+}
+
+func TestNoValidatingWebhookV1(t *testing.T) {
+	f := newFixtureV1(t)
+	_, _ = f.client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Create(context.TODO(), &admiv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: v1Cfg.getWebhookName()},
+	}, metav1.CreateOptions{})
+
+	data, err := certificate.GenerateSecretData(time.Now(), time.Now().Add(365*24*time.Hour), []string{"my.svc.dns"})
+	if err != nil {
+		t.Fatalf("Failed to create the Secret: %v", err)
+	}
+
+	secret := buildSecret(data, v1Cfg)
+	f.populateSecretsCache(secret)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	c := f.run(stopCh)
+
+	// the validating webhook left over when validating webhooks were enabled is deleted
+	assert.Eventually(t, func() bool {
+		_, err := f.client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.TODO(), v1Cfg.getWebhookName(), metav1.GetOptions{})
+		return errors.IsNotFound(err)
+	}, waitFor, tick)
+
+	assert.Empty(t, c.EnabledValidatingWebhooks())
+}
+
+func TestNoValidatingWebhookNotDeletedV1(t *testing.T) {
+	f := newFixtureV1(t)
+
+	data, err := certificate.GenerateSecretData(time.Now(), time.Now().Add(365*24*time.Hour), []string{"my.svc.dns"})
+	if err != nil {
+		t.Fatalf("Failed to create the Secret: %v", err)
+	}
+
+	secret := buildSecret(data, v1Cfg)
+	f.populateSecretsCache(secret)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	c := f.run(stopCh)
+
+	// the mutating webhook is created by the reconciliation
+	require.Eventually(t, func() bool {
+		_, err := c.webhooksLister.Get(v1Cfg.getWebhookName())
+		return err == nil
+	}, waitFor, tick)
+	c.triggerReconciliation()
+	require.Eventually(t, func() bool {
+		return c.queue.Len() == 0
+	}, waitFor, tick)
+
+	// the validating webhook doesn't exist, so no deletion is attempted
+	for _, action := range f.client.Actions() {
+		assert.False(t, action.Matches("delete", "validatingwebhookconfigurations"), "unexpected action %v", action)
+	}
+}
+
+func TestValidatingWebhooksNotWatchedV1(t *testing.T) {
+	f := newFixtureV1(t)
+	// the validating webhook configurations can't be listed without the
+	// permission to do so
+	f.client.PrependReactor("list", "validatingwebhookconfigurations", func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+		return true, nil, errors.NewForbidden(action.GetResource().GroupResource(), "", fmt.Errorf("forbidden"))
+	})
+
+	data, err := certificate.GenerateSecretData(time.Now(), time.Now().Add(365*24*time.Hour), []string{"my.svc.dns"})
+	if err != nil {
+		t.Fatalf("Failed to create the Secret: %v", err)
+	}
+
+	secret := buildSecret(data, v1Cfg)
+	f.populateSecretsCache(secret)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	c := f.run(stopCh)
+
+	assert.Nil(t, c.validatingWebhooksSynced)
+
+	// the mutating webhook is reconciled without waiting for the validating
+	// webhook configurations
+	assert.Eventually(t, func() bool {
+		_, err := c.webhooksLister.Get(v1Cfg.getWebhookName())
+		return err == nil
+	}, waitFor, tick)
+}
+
 func TestAdmissionControllerFailureModeIgnore(t *testing.T) {
 	mockConfig := configmock.New(t)
 	f := newFixtureV1(t)
@@ -1096,6 +1218,7 @@ func (f *fixtureV1) createController() (*ControllerV1, informers.SharedInformerF
 		f.client,
 		factory.Core().V1().Secrets(),
 		factory.Admissionregistration().V1().MutatingWebhookConfigurations(),
+		factory.Admissionregistration().V1().ValidatingWebhookConfigurations(),
 		func() bool { return true },
 		make(chan struct{}),
 		v1Cfg,
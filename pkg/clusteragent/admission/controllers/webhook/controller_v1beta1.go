@@ -54,6 +54,13 @@ func NewControllerV1beta1(client kubernetes.Interface, secretInformer coreinform
 	controller.mutatingWebhooks = mutatingWebhooks(wmeta, pa)
 	controller.generateTemplates()
 
+	// Validating webhooks are only managed with admissionregistration/v1
+	for _, webhook := range validatingWebhooks() {
+		if webhook.IsEnabled() {
+			log.Warnf("Validating webhook %s requires the admissionregistration/v1 API, it will not be registered", webhook.Name())
+		}
+	}
+
 	if _, err := secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    controller.handleSecret,
 		UpdateFunc: controller.handleSecretUpdate,
@@ -33,6 +33,13 @@ const (
 	StatusError   = "error"
 )
 
+// Validation status tags
+const (
+	ValidationValid    = "valid"
+	ValidationWarned   = "warned"
+	ValidationRejected = "rejected"
+)
+
 // Telemetry metrics
 var (
 	ReconcileSuccess = telemetry.NewGaugeWithOpts("admission_webhooks", "reconcile_success",
@@ -99,4 +106,10 @@ var (
 	PatchErrors = telemetry.NewCounterWithOpts("admission_webhooks", "patcher_errors",
 		[]string{}, "Number of patch errors.",
 		telemetry.Options{NoDoubleUnderscoreSep: true})
+	ValidationAttempts = telemetry.NewCounterWithOpts("admission_webhooks", "validation_attempts",
+		[]string{"webhook_name", "namespace", "status", "dry_run"}, "Number of validation attempts by webhook, namespace and result.",
+		telemetry.Options{NoDoubleUnderscoreSep: true})
+	ValidationErrors = telemetry.NewCounterWithOpts("admission_webhooks", "validation_errors",
+		[]string{"webhook_name", "namespace", "dry_run"}, "Number of invalid annotations and labels found by webhook and namespace.",
+		telemetry.Options{NoDoubleUnderscoreSep: true})
 )
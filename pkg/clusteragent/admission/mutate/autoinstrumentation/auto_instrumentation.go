@@ -170,6 +170,12 @@ func (l language) defaultLibVersion() string {
 	return langVersion
 }
 
+// IsSupportedLanguage returns whether the libraries of a language can be
+// injected, as in the admission.datadoghq.com/<language>-lib.version annotation
+func IsSupportedLanguage(lang string) bool {
+	return slices.Contains(supportedLanguages, language(lang))
+}
+
 // Webhook is the auto instrumentation webhook
 type Webhook struct {
 	name              string
@@ -35,11 +35,12 @@ type ControllerContext struct {
 	StopCh              chan struct{}
 }
 
-// StartControllers starts the secret and webhook controllers
-func StartControllers(ctx ControllerContext, wmeta workloadmeta.Component, pa workload.PodPatcher) ([]webhook.MutatingWebhook, error) {
+// StartControllers starts the secret and webhook controllers, and returns the
+// enabled mutating and validating webhooks
+func StartControllers(ctx ControllerContext, wmeta workloadmeta.Component, pa workload.PodPatcher) ([]webhook.MutatingWebhook, []webhook.ValidatingWebhook, error) {
 	if !config.Datadog().GetBool("admission_controller.enabled") {
 		log.Info("Admission controller is disabled")
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	certConfig := secret.NewCertConfig(
@@ -60,12 +61,12 @@ func StartControllers(ctx ControllerContext, wmeta workloadmeta.Component, pa wo
 
 	nsSelectorEnabled, err := useNamespaceSelector(ctx.Client.Discovery())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	v1Enabled, err := UseAdmissionV1(ctx.Client.Discovery())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	webhookConfig := webhook.NewConfig(v1Enabled, nsSelectorEnabled)
@@ -92,11 +93,14 @@ func StartControllers(ctx ControllerContext, wmeta workloadmeta.Component, pa wo
 
 	if v1Enabled {
 		informers[apiserver.WebhooksInformer] = ctx.WebhookInformers.Admissionregistration().V1().MutatingWebhookConfigurations().Informer()
+		if len(webhookController.EnabledValidatingWebhooks()) > 0 {
+			informers[apiserver.ValidatingWebhooksInformer] = ctx.WebhookInformers.Admissionregistration().V1().ValidatingWebhookConfigurations().Informer()
+		}
 		getWebhookStatus = getWebhookStatusV1
 	} else {
 		informers[apiserver.WebhooksInformer] = ctx.WebhookInformers.Admissionregistration().V1beta1().MutatingWebhookConfigurations().Informer()
 		getWebhookStatus = getWebhookStatusV1beta1
 	}
 
-	return webhookController.EnabledWebhooks(), apiserver.SyncInformers(informers, 0)
+	return webhookController.EnabledWebhooks(), webhookController.EnabledValidatingWebhooks(), apiserver.SyncInformers(informers, 0)
 }
@@ -0,0 +1,514 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+// Package annotations implements the webhook that validates the Datadog
+// annotations and labels of pods, so that typos are reported when the pod is
+// created instead of silently disabling a check or a log source.
+package annotations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	admiv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/DataDog/datadog-agent/cmd/cluster-agent/admission"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/common/utils"
+	logsconfig "github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	admCommon "github.com/DataDog/datadog-agent/pkg/clusteragent/admission/common"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/metrics"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate/autoinstrumentation"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate/common"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	webhookName = "validate_annotations"
+
+	// warnMode admits invalid pods, and returns the validation errors as
+	// warnings
+	warnMode = "warn"
+	// rejectMode denies invalid pods
+	rejectMode = "reject"
+
+	admissionPrefix = "admission.datadoghq.com/"
+	allLanguages    = "all"
+)
+
+// podADAnnotations are the AD annotations that don't target a container
+var podADAnnotations = map[string]func(string) error{
+	"tags":             validateTags,
+	"exclude":          validateBool,
+	"metrics_exclude":  validateBool,
+	"logs_exclude":     validateBool,
+	"tolerate-unready": validateBool,
+}
+
+// containerADAnnotations are the AD annotations that target a container, as
+// in ad.datadoghq.com/<identifier>.<suffix>. The check and logs templates are
+// validated by the autodiscovery parser, and only need extra validation here.
+var containerADAnnotations = map[string]func(string) error{
+	"checks":                    validateChecks,
+	"check_names":               noValidation,
+	"init_configs":              noValidation,
+	"instances":                 noValidation,
+	"logs":                      validateLogs,
+	"check.id":                  noValidation,
+	"ignore_autodiscovery_tags": validateBool,
+	"tags":                      validateTags,
+	"exclude":                   validateBool,
+	"metrics_exclude":           validateBool,
+	"logs_exclude":              validateBool,
+}
+
+// templateADAnnotations are the container AD annotations that define the
+// check and logs templates. They target the identifier of the container,
+// which is set by the check.id annotation, or defaults to the container name.
+var templateADAnnotations = map[string]struct{}{
+	"checks":                    {},
+	"check_names":               {},
+	"init_configs":              {},
+	"instances":                 {},
+	"logs":                      {},
+	"ignore_autodiscovery_tags": {},
+}
+
+// checkKeys are the keys supported in each check of the checks annotation
+var checkKeys = map[string]struct{}{
+	"name":                      {},
+	"init_config":               {},
+	"instances":                 {},
+	"ignore_autodiscovery_tags": {},
+}
+
+// admissionAnnotations are the admission annotations that are set by the
+// Cluster Agent itself, and are valid as long as they exist
+var admissionAnnotations = map[string]struct{}{
+	"rc.id":                      {},
+	"rc.rev":                     {},
+	"cws-instrumentation.status": {},
+}
+
+// Webhook is the webhook that validates the Datadog annotations and labels of
+// pods
+type Webhook struct {
+	name       string
+	isEnabled  bool
+	endpoint   string
+	resources  []string
+	operations []admiv1.OperationType
+	reject     bool
+}
+
+// NewWebhook returns a new Webhook
+func NewWebhook() *Webhook {
+	mode := strings.ToLower(config.Datadog().GetString("admission_controller.validate_annotations.mode"))
+	if mode != warnMode && mode != rejectMode {
+		log.Warnf("Unknown annotation validation mode %q - defaulting to %q", mode, warnMode)
+		mode = warnMode
+	}
+
+	return &Webhook{
+		name:       webhookName,
+		isEnabled:  config.Datadog().GetBool("admission_controller.validate_annotations.enabled"),
+		endpoint:   config.Datadog().GetString("admission_controller.validate_annotations.endpoint"),
+		resources:  []string{"pods"},
+		operations: []admiv1.OperationType{admiv1.Create, admiv1.Update},
+		reject:     mode == rejectMode,
+	}
+}
+
+// Name returns the name of the webhook
+func (w *Webhook) Name() string {
+	return w.name
+}
+
+// IsEnabled returns whether the webhook is enabled
+func (w *Webhook) IsEnabled() bool {
+	return w.isEnabled
+}
+
+// Endpoint returns the endpoint of the webhook
+func (w *Webhook) Endpoint() string {
+	return w.endpoint
+}
+
+// Resources returns the kubernetes resources for which the webhook should
+// be invoked
+func (w *Webhook) Resources() []string {
+	return w.resources
+}
+
+// Operations returns the operations on the resources specified for which
+// the webhook should be invoked
+func (w *Webhook) Operations() []admiv1.OperationType {
+	return w.operations
+}
+
+// LabelSelectors returns the label selectors that specify when the webhook
+// should be invoked
+func (w *Webhook) LabelSelectors(useNamespaceSelector bool) (namespaceSelector *metav1.LabelSelector, objectSelector *metav1.LabelSelector) {
+	return common.DefaultLabelSelectors(useNamespaceSelector)
+}
+
+// ValidateFunc returns the function that validates the resources
+func (w *Webhook) ValidateFunc() admission.ValidatingWebhookFunc {
+	return w.validate
+}
+
+// validate checks the Datadog annotations and labels of a pod
+func (w *Webhook) validate(request *admission.MutateRequest) (*admission.ValidationResult, error) {
+	dryRun := strconv.FormatBool(request.DryRun)
+
+	var pod corev1.Pod
+	if err := json.Unmarshal(request.Raw, &pod); err != nil {
+		metrics.ValidationAttempts.Inc(w.name, request.Namespace, metrics.StatusError, dryRun)
+		return nil, fmt.Errorf("failed to decode raw object: %v", err)
+	}
+
+	ns := request.Namespace
+	if ns == "" {
+		ns = pod.GetNamespace()
+	}
+
+	errs := ValidatePod(&pod)
+	if len(errs) == 0 {
+		metrics.ValidationAttempts.Inc(w.name, ns, metrics.ValidationValid, dryRun)
+		return &admission.ValidationResult{Allowed: true}, nil
+	}
+
+	metrics.ValidationErrors.Add(float64(len(errs)), w.name, ns, dryRun)
+
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+
+	if w.reject {
+		metrics.ValidationAttempts.Inc(w.name, ns, metrics.ValidationRejected, dryRun)
+		return &admission.ValidationResult{
+			Allowed: false,
+			Message: "invalid Datadog annotations: " + strings.Join(messages, "; "),
+		}, nil
+	}
+
+	log.Debugf("Pod %s has invalid Datadog annotations: %s", common.PodString(&pod), strings.Join(messages, "; "))
+	metrics.ValidationAttempts.Inc(w.name, ns, metrics.ValidationWarned, dryRun)
+
+	return &admission.ValidationResult{
+		Allowed:  true,
+		Warnings: messages,
+	}, nil
+}
+
+// ValidatePod returns an error for each invalid Datadog annotation or label of
+// a pod, sorted by annotation or label key.
+func ValidatePod(pod *corev1.Pod) []error {
+	var errs []error
+
+	annotations := pod.GetAnnotations()
+
+	// the templates of a container are looked up by its identifier, which is
+	// its name unless a custom one is set with the check.id annotation
+	containers := make(map[string]struct{}, len(pod.Spec.InitContainers)+len(pod.Spec.Containers))
+	identifiers := make(map[string]string, len(pod.Spec.InitContainers)+len(pod.Spec.Containers))
+	for _, cs := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for _, c := range cs {
+			containers[c.Name] = struct{}{}
+			identifier := c.Name
+			if id, found := utils.ExtractCheckIDFromPodAnnotations(annotations, c.Name); found {
+				identifier = id
+			}
+			identifiers[identifier] = c.Name
+		}
+	}
+
+	templateIdentifiers := map[string]struct{}{}
+
+	for _, key := range sortedKeys(annotations) {
+		value := annotations[key]
+
+		switch {
+		case strings.HasPrefix(key, utils.KubeAnnotationPrefix):
+			identifier, err := validateADAnnotation(strings.TrimPrefix(key, utils.KubeAnnotationPrefix), value, containers, identifiers)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("annotation %q: %w", key, err))
+			}
+			if identifier != "" {
+				templateIdentifiers[identifier] = struct{}{}
+			}
+		case strings.HasPrefix(key, admissionPrefix):
+			if err := validateAdmissionAnnotation(strings.TrimPrefix(key, admissionPrefix), value, containers); err != nil {
+				errs = append(errs, fmt.Errorf("annotation %q: %w", key, err))
+			}
+		}
+	}
+
+	// the check and logs templates are parsed like the autodiscovery does
+	for _, identifier := range sortedKeys(templateIdentifiers) {
+		_, templateErrs := utils.ExtractTemplatesFromAnnotations(identifier, annotations, identifier)
+		for _, err := range templateErrs {
+			errs = append(errs, fmt.Errorf("templates of container %q: %w", identifiers[identifier], err))
+		}
+	}
+
+	labels := pod.GetLabels()
+	for _, key := range sortedKeys(labels) {
+		if err := validateAdmissionLabel(key, labels[key]); err != nil {
+			errs = append(errs, fmt.Errorf("label %q: %w", key, err))
+		}
+	}
+
+	return errs
+}
+
+// validateADAnnotation validates an annotation with the AD prefix. It returns
+// the identifier targeted by the annotation if it's a check or logs template.
+func validateADAnnotation(name string, value string, containers map[string]struct{}, identifiers map[string]string) (string, error) {
+	if !strings.Contains(name, ".") {
+		validate, known := podADAnnotations[name]
+		if !known {
+			return "", errors.New("unknown annotation")
+		}
+		return "", validate(value)
+	}
+
+	// custom identifiers can contain dots, so the suffix is matched from the
+	// end of the annotation
+	target, suffix := splitADAnnotation(name)
+	validate, known := containerADAnnotations[suffix]
+	if !known {
+		return "", fmt.Errorf("unknown annotation suffix %q", suffix)
+	}
+
+	if _, isTemplate := templateADAnnotations[suffix]; !isTemplate {
+		// check.id, tags and exclusions target the container name
+		if _, exists := containers[target]; !exists {
+			return "", fmt.Errorf("container %q is not in the pod spec", target)
+		}
+		return "", validate(value)
+	}
+
+	if _, exists := identifiers[target]; !exists {
+		for identifier, container := range identifiers {
+			if container == target {
+				return "", fmt.Errorf("container %q uses the custom identifier %q set by its check.id annotation", target, identifier)
+			}
+		}
+		return "", fmt.Errorf("%q is neither a container in the pod spec nor the identifier set by a check.id annotation", target)
+	}
+
+	var templateIdentifier string
+	switch suffix {
+	case "checks", "check_names", "logs":
+		templateIdentifier = target
+	}
+
+	return templateIdentifier, validate(value)
+}
+
+// splitADAnnotation splits a container AD annotation into its target and its
+// suffix. The longest known suffix wins, so that check.id isn't read as the id
+// suffix of the "<target>.check" target. Unknown suffixes start at the first
+// dot, as container names can't contain dots.
+func splitADAnnotation(name string) (string, string) {
+	var target, suffix string
+	for known := range containerADAnnotations {
+		prefix, found := strings.CutSuffix(name, "."+known)
+		if found && prefix != "" && len(known) > len(suffix) {
+			target, suffix = prefix, known
+		}
+	}
+	if suffix == "" {
+		target, suffix, _ = strings.Cut(name, ".")
+	}
+	return target, suffix
+}
+
+// validateAdmissionAnnotation validates an annotation with the admission
+// prefix
+func validateAdmissionAnnotation(name string, value string, containers map[string]struct{}) error {
+	if _, known := admissionAnnotations[name]; known {
+		return nil
+	}
+
+	// <language>-lib.config.v1
+	if lang, found := strings.CutSuffix(name, "-lib.config.v1"); found && !strings.Contains(lang, ".") {
+		if err := validateLanguage(lang); err != nil {
+			return err
+		}
+		return decodeStrict(value, &admCommon.LibConfig{})
+	}
+
+	// [<container>.]<language>-lib.version and [<container>.]<language>-lib.custom-image
+	for _, suffix := range []string{"-lib.version", "-lib.custom-image"} {
+		prefix, found := strings.CutSuffix(name, suffix)
+		if !found {
+			continue
+		}
+
+		container, lang, targetsContainer := strings.Cut(prefix, ".")
+		if !targetsContainer {
+			lang = container
+		} else if _, exists := containers[container]; !exists {
+			return fmt.Errorf("container %q is not in the pod spec", container)
+		}
+
+		if lang == allLanguages && targetsContainer {
+			return errors.New("the \"all\" language can't target a container")
+		}
+
+		if value == "" {
+			return errors.New("empty value")
+		}
+
+		return validateLanguage(lang)
+	}
+
+	return errors.New("unknown annotation")
+}
+
+// validateAdmissionLabel validates a label with the admission prefix
+func validateAdmissionLabel(key string, value string) error {
+	switch key {
+	case admCommon.EnabledLabelKey, "admission.datadoghq.com/cws-instrumentation.enabled":
+		return validateBool(value)
+	case admCommon.InjectionModeLabelKey:
+		switch value {
+		case "hostip", "service", "socket":
+			return nil
+		}
+		return fmt.Errorf("invalid value %q, should be either 'hostip', 'service' or 'socket'", value)
+	}
+
+	if strings.HasPrefix(key, admissionPrefix) {
+		return errors.New("unknown label")
+	}
+
+	return nil
+}
+
+func validateLanguage(lang string) error {
+	if lang == allLanguages || autoinstrumentation.IsSupportedLanguage(lang) {
+		return nil
+	}
+	return fmt.Errorf("unsupported language %q", lang)
+}
+
+func validateBool(value string) error {
+	if _, err := strconv.ParseBool(value); err != nil {
+		return fmt.Errorf("invalid boolean %q", value)
+	}
+	return nil
+}
+
+// validateTags validates a JSON object whose values are tag values or lists
+// of tag values
+func validateTags(value string) error {
+	var tags map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &tags); err != nil {
+		return fmt.Errorf("cannot parse tags: %w", err)
+	}
+
+	for _, name := range sortedKeys(tags) {
+		switch tags[name].(type) {
+		case string, []interface{}:
+		default:
+			return fmt.Errorf("invalid value for tag %q, must be a string or an array", name)
+		}
+	}
+
+	return nil
+}
+
+// validateChecks reports the unknown keys of the checks, which are ignored by
+// the autodiscovery parser. Parsing errors are reported by the parser itself.
+func validateChecks(value string) error {
+	var checks map[string]map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(value), &checks); err != nil {
+		return nil
+	}
+
+	for _, check := range sortedKeys(checks) {
+		for _, key := range sortedKeys(checks[check]) {
+			if _, known := checkKeys[key]; !known {
+				return fmt.Errorf("unknown key %q in check %q", key, check)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateLogs parses the logs configs like the logs agent does, and reports
+// unknown keys and invalid processing rules. Syntax errors are reported by the
+// autodiscovery parser.
+func validateLogs(value string) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal([]byte(value), &raw); err != nil {
+		return nil
+	}
+
+	var configs []*logsconfig.LogsConfig
+	if err := decodeStrict(value, &configs); err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		if cfg == nil {
+			continue
+		}
+
+		// the type is set by the logs agent depending on the container
+		// runtime when it's not specified
+		if cfg.Type != "" {
+			if err := cfg.Validate(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := logsconfig.ValidateProcessingRules(cfg.ProcessingRules); err != nil {
+			return err
+		}
+		if err := logsconfig.CompileProcessingRules(cfg.ProcessingRules); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func noValidation(string) error {
+	return nil
+}
+
+// decodeStrict decodes a JSON value, and fails on unknown keys
+func decodeStrict(value string, v interface{}) error {
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("cannot parse JSON: %w", err)
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
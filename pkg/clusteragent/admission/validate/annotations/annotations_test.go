@@ -0,0 +1,220 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package annotations
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/DataDog/datadog-agent/cmd/cluster-agent/admission"
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+)
+
+func fakePod(annotations map[string]string, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "redis",
+			Namespace:   "default",
+			Annotations: annotations,
+			Labels:      labels,
+		},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "init"}},
+			Containers:     []corev1.Container{{Name: "redis"}, {Name: "proxy"}},
+		},
+	}
+}
+
+func TestValidatePod(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		labels      map[string]string
+		expected    []string
+	}{
+		{
+			name: "valid annotations and labels",
+			annotations: map[string]string{
+				"ad.datadoghq.com/redis.checks":                    `{"redisdb": {"init_config": {}, "instances": [{"host": "%%host%%", "port": "6379"}]}}`,
+				"ad.datadoghq.com/redis.logs":                      `[{"source": "redis", "service": "cache", "log_processing_rules": [{"type": "exclude_at_match", "name": "exclude_info", "pattern": "INFO"}]}]`,
+				"ad.datadoghq.com/proxy.check_names":               `["http_check"]`,
+				"ad.datadoghq.com/proxy.init_configs":              `[{}]`,
+				"ad.datadoghq.com/proxy.instances":                 `[{"url": "http://%%host%%"}]`,
+				"ad.datadoghq.com/proxy.logs_exclude":              "true",
+				"ad.datadoghq.com/init.exclude":                    "true",
+				"ad.datadoghq.com/tags":                            `{"team": "cache", "components": ["a", "b"]}`,
+				"ad.datadoghq.com/redis.tags":                      `{"role": "primary"}`,
+				"admission.datadoghq.com/java-lib.version":         "v1",
+				"admission.datadoghq.com/redis.python-lib.version": "v2",
+				"admission.datadoghq.com/java-lib.config.v1":       `{"library_language": "java", "tracing_sampling_rate": 0.5}`,
+				"admission.datadoghq.com/rc.id":                    "abc",
+				"unrelated.example.com/annotation":                 "{",
+			},
+			labels: map[string]string{
+				"admission.datadoghq.com/enabled":     "true",
+				"admission.datadoghq.com/config.mode": "socket",
+				"app":                                 "redis",
+			},
+		},
+		{
+			name: "invalid JSON",
+			annotations: map[string]string{
+				"ad.datadoghq.com/redis.checks": `{"redisdb": {"instances": [{"host": "%%host%%"}]}`,
+				"ad.datadoghq.com/proxy.logs":   `[{"source": "nginx"`,
+				"ad.datadoghq.com/tags":         `team:cache`,
+			},
+			expected: []string{
+				`annotation "ad.datadoghq.com/tags": cannot parse tags: invalid character 'e' in literal true (expecting 'r')`,
+				`templates of container "proxy": could not extract logs config: in logs: unexpected end of JSON input`,
+				`templates of container "redis": cannot parse check configuration: unexpected end of JSON input`,
+			},
+		},
+		{
+			name: "unknown keys",
+			annotations: map[string]string{
+				"ad.datadoghq.com/redis.checks":                 `{"redisdb": {"instances": [{"host": "%%host%%"}], "init_configs": {}}}`,
+				"ad.datadoghq.com/redis.logs":                   `[{"source": "redis", "sevrice": "cache"}]`,
+				"ad.datadoghq.com/redis.check_name":             `["redisdb"]`,
+				"ad.datadoghq.com/exclude_logs":                 "true",
+				"admission.datadoghq.com/java-lib.config.v1":    `{"library_language": "java", "tracing_sample_rate": 0.5}`,
+				"admission.datadoghq.com/golang-lib.version":    "v1",
+				"admission.datadoghq.com/java-lib.versions":     "v1",
+				"admission.datadoghq.com/redis.all-lib.version": "v1",
+			},
+			labels: map[string]string{
+				"admission.datadoghq.com/enable": "true",
+			},
+			expected: []string{
+				`annotation "ad.datadoghq.com/exclude_logs": unknown annotation`,
+				`annotation "ad.datadoghq.com/redis.check_name": unknown annotation suffix "check_name"`,
+				`annotation "ad.datadoghq.com/redis.checks": unknown key "init_configs" in check "redisdb"`,
+				`annotation "ad.datadoghq.com/redis.logs": cannot parse JSON: json: unknown field "sevrice"`,
+				`annotation "admission.datadoghq.com/golang-lib.version": unsupported language "golang"`,
+				`annotation "admission.datadoghq.com/java-lib.config.v1": cannot parse JSON: json: unknown field "tracing_sample_rate"`,
+				`annotation "admission.datadoghq.com/java-lib.versions": unknown annotation`,
+				`annotation "admission.datadoghq.com/redis.all-lib.version": the "all" language can't target a container`,
+				`label "admission.datadoghq.com/enable": unknown label`,
+			},
+		},
+		{
+			name: "containers not in the pod spec",
+			annotations: map[string]string{
+				"ad.datadoghq.com/redis-server.checks":         `{"redisdb": {"instances": [{"host": "%%host%%"}]}}`,
+				"admission.datadoghq.com/app.java-lib.version": "v1",
+			},
+			expected: []string{
+				`annotation "ad.datadoghq.com/redis-server.checks": "redis-server" is neither a container in the pod spec nor the identifier set by a check.id annotation`,
+				`annotation "admission.datadoghq.com/app.java-lib.version": container "app" is not in the pod spec`,
+			},
+		},
+		{
+			name: "custom identifiers",
+			annotations: map[string]string{
+				"ad.datadoghq.com/redis.check.id":       "redis.primary",
+				"ad.datadoghq.com/redis.primary.checks": `{"redisdb": {"instances": [{"host": "%%host%%", "port": "6379"}]}}`,
+				"ad.datadoghq.com/redis.primary.logs":   `[{"source": "redis"}]`,
+				"ad.datadoghq.com/redis.tags":           `{"role": "primary"}`,
+				"ad.datadoghq.com/proxy.check.id":       "nginx",
+				"ad.datadoghq.com/nginx.checks":         `{"nginx": {"instances": [{"url": "http://%%host%%"}]}}`,
+			},
+		},
+		{
+			name: "custom identifier mismatch",
+			annotations: map[string]string{
+				"ad.datadoghq.com/redis.check.id":  "primary",
+				"ad.datadoghq.com/redis.checks":    `{"redisdb": {"instances": [{"host": "%%host%%"}]}}`,
+				"ad.datadoghq.com/primary.tags":    `{"role": "primary"}`,
+				"ad.datadoghq.com/server.check.id": "other",
+			},
+			expected: []string{
+				`annotation "ad.datadoghq.com/primary.tags": container "primary" is not in the pod spec`,
+				`annotation "ad.datadoghq.com/redis.checks": container "redis" uses the custom identifier "primary" set by its check.id annotation`,
+				`annotation "ad.datadoghq.com/server.check.id": container "server" is not in the pod spec`,
+			},
+		},
+		{
+			name: "invalid values",
+			annotations: map[string]string{
+				"ad.datadoghq.com/redis.logs":    `[{"type": "file", "source": "redis"}, {"log_processing_rules": [{"type": "mask_sequences", "name": "mask"}]}]`,
+				"ad.datadoghq.com/redis.exclude": "yes",
+				"ad.datadoghq.com/tags":          `{"team": {"name": "cache"}}`,
+			},
+			labels: map[string]string{
+				"admission.datadoghq.com/enabled":     "on",
+				"admission.datadoghq.com/config.mode": "unix",
+			},
+			expected: []string{
+				`annotation "ad.datadoghq.com/redis.exclude": invalid boolean "yes"`,
+				`annotation "ad.datadoghq.com/redis.logs": file source must have a path`,
+				`annotation "ad.datadoghq.com/tags": invalid value for tag "team", must be a string or an array`,
+				`label "admission.datadoghq.com/config.mode": invalid value "unix", should be either 'hostip', 'service' or 'socket'`,
+				`label "admission.datadoghq.com/enabled": invalid boolean "on"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs []string
+			for _, err := range ValidatePod(fakePod(tt.annotations, tt.labels)) {
+				errs = append(errs, err.Error())
+			}
+			assert.Equal(t, tt.expected, errs)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	pod := fakePod(map[string]string{
+		"ad.datadoghq.com/redis.check_name": `["redisdb"]`,
+	}, nil)
+	raw, err := json.Marshal(pod)
+	require.NoError(t, err)
+
+	validPod := fakePod(nil, nil)
+	validRaw, err := json.Marshal(validPod)
+	require.NoError(t, err)
+
+	expectedError := `annotation "ad.datadoghq.com/redis.check_name": unknown annotation suffix "check_name"`
+
+	t.Run("warn mode", func(t *testing.T) {
+		configmock.New(t)
+		w := NewWebhook()
+
+		result, err := w.ValidateFunc()(&admission.MutateRequest{Raw: raw, Namespace: "default"})
+		require.NoError(t, err)
+		assert.Equal(t, &admission.ValidationResult{Allowed: true, Warnings: []string{expectedError}}, result)
+
+		result, err = w.ValidateFunc()(&admission.MutateRequest{Raw: validRaw, Namespace: "default"})
+		require.NoError(t, err)
+		assert.Equal(t, &admission.ValidationResult{Allowed: true}, result)
+	})
+
+	t.Run("reject mode", func(t *testing.T) {
+		mockConfig := configmock.New(t)
+		mockConfig.SetWithoutSource("admission_controller.validate_annotations.mode", "reject")
+		w := NewWebhook()
+
+		result, err := w.ValidateFunc()(&admission.MutateRequest{Raw: raw, Namespace: "default", DryRun: true})
+		require.NoError(t, err)
+		assert.Equal(t, &admission.ValidationResult{Allowed: false, Message: "invalid Datadog annotations: " + expectedError}, result)
+	})
+
+	t.Run("invalid object", func(t *testing.T) {
+		configmock.New(t)
+		w := NewWebhook()
+
+		_, err := w.ValidateFunc()(&admission.MutateRequest{Raw: []byte("{"), Namespace: "default"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,27 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+// Package validate contains validating webhooks registered in the admission
+// controller.
+//
+// Validating webhooks intercept requests to the Kubernetes API server like
+// mutating webhooks do, but they can't modify the objects. They either admit
+// or deny the request, and can return warnings that are displayed by kubectl.
+// They are run after all the mutating webhooks, in parallel.
+//
+// Each validating webhook needs to implement the "ValidatingWebhook" interface
+// of the "webhook" package. It's the same as the "MutatingWebhook" interface,
+// except that "MutateFunc" is replaced by "ValidateFunc", which returns the
+// function that validates the Kubernetes object.
+//
+// Validating webhooks must not have side effects: they are also invoked for
+// dry-run requests, like "kubectl apply --dry-run=server", which makes them a
+// convenient way to check manifests before applying them.
+//
+// The conventions described in the "mutate" package regarding configuration,
+// telemetry and performance also apply to validating webhooks.
+package validate
@@ -16,6 +16,7 @@ import (
 type CheckStatus struct {
 	WorkersNeeded float64
 	Runner        string
+	Affinity      topology
 }
 
 // RunnerStatus represents the status of a check runner
@@ -23,6 +24,7 @@ type RunnerStatus struct {
 	Workers     int
 	WorkersUsed float64
 	NumChecks   int
+	Topology    topology
 }
 
 func (ns RunnerStatus) utilization() float64 {
@@ -56,19 +58,62 @@ func newChecksDistribution(workersPerRunner map[string]int) checksDistribution {
 	}
 }
 
-// leastBusyRunner returns the runner with the lowest utilization. If there are
-// several options, it gives preference to preferredRunner. If preferredRunner
-// is not among the runners with the lowest utilization, it gives precedence to
-// the runner with the lowest number of checks deployed. excludeRunner can be set
+// withoutChecks returns a distribution with the same runners, workers and
+// topology as this one, but without any checks
+func (distribution *checksDistribution) withoutChecks() checksDistribution {
+	res := newChecksDistribution(distribution.runnerWorkers())
+
+	for runnerName, runnerStatus := range distribution.Runners {
+		res.Runners[runnerName].Topology = runnerStatus.Topology
+	}
+
+	return res
+}
+
+// eligibleRunners returns the runners that best satisfy the given affinity,
+// ignoring excludeRunner. If no runner satisfies it, or if the affinity is
+// empty, all the runners are returned.
+func (distribution *checksDistribution) eligibleRunners(affinity topology, excludeRunner string) map[string]struct{} {
+	allRunners := map[string]topology{}
+	runners := map[string]topology{}
+	for runnerName, runnerStatus := range distribution.Runners {
+		allRunners[runnerName] = runnerStatus.Topology
+		if runnerName != excludeRunner {
+			runners[runnerName] = runnerStatus.Topology
+		}
+	}
+
+	// The region of the preferred zone can be known from the excluded runner
+	affinity = resolveAffinity(affinity, allRunners)
+	best := bestAffinityLevel(affinity, runners)
+
+	res := map[string]struct{}{}
+	for runnerName, runnerTopology := range runners {
+		if affinity.match(runnerTopology) >= best {
+			res[runnerName] = struct{}{}
+		}
+	}
+
+	return res
+}
+
+// leastBusyRunner returns the runner with the lowest utilization among the
+// ones that best satisfy the affinity of the check. If there are several
+// options, it gives preference to preferredRunner. If preferredRunner is not
+// among the runners with the lowest utilization, it gives precedence to the
+// runner with the lowest number of checks deployed. excludeRunner can be set
 // to avoid assigning a check to a specific runner.
-func (distribution *checksDistribution) leastBusyRunner(preferredRunner string, excludeRunner string) string {
+func (distribution *checksDistribution) leastBusyRunner(affinity topology, preferredRunner string, excludeRunner string) string {
 	leastBusyRunner := ""
 	minUtilization := 0.0
 	numChecksLeastBusyRunner := 0
 
+	eligibleRunners := distribution.eligibleRunners(affinity, excludeRunner)
+
 	for runnerName, runnerStatus := range distribution.Runners {
-		// Allow exclusion of a runner from selection
-		if runnerName == excludeRunner {
+		// Only consider the runners that best satisfy the affinity. This also
+		// excludes excludeRunner.
+		if _, eligible := eligibleRunners[runnerName]; !eligible {
 			continue
 		}
 
@@ -90,13 +135,14 @@ func (distribution *checksDistribution) leastBusyRunner(preferredRunner string,
 	return leastBusyRunner
 }
 
-func (distribution *checksDistribution) addToLeastBusy(checkID string, workersNeeded float64, preferredRunner string, excludeRunner string) {
-	leastBusy := distribution.leastBusyRunner(preferredRunner, excludeRunner)
+func (distribution *checksDistribution) addToLeastBusy(checkID string, workersNeeded float64, affinity topology, preferredRunner string, excludeRunner string) {
+	leastBusy := distribution.leastBusyRunner(affinity, preferredRunner, excludeRunner)
 	if leastBusy == "" {
 		return
 	}
 
 	distribution.addCheck(checkID, workersNeeded, leastBusy)
+	distribution.Checks[checkID].Affinity = affinity
 }
 
 func (distribution *checksDistribution) addCheck(checkID string, workersNeeded float64, runner string) {
@@ -143,6 +189,14 @@ func (distribution *checksDistribution) workersNeededForCheck(checkID string) fl
 	return 0
 }
 
+func (distribution *checksDistribution) affinityForCheck(checkID string) topology {
+	if checkInfo, found := distribution.Checks[checkID]; found {
+		return checkInfo.Affinity
+	}
+
+	return topology{}
+}
+
 // Note: if there are several checks with the same number of workers needed,
 // they are returned in alphabetical order.
 // When distributing the checks, having the same order will help in minimizing
@@ -202,6 +256,29 @@ func (distribution *checksDistribution) numRunnersWithHighUtilization() int {
 	return withHighUtilization
 }
 
+// numChecksWithUnmetAffinity returns the number of checks that run on a runner
+// that doesn't satisfy their affinity as well as another runner would
+func (distribution *checksDistribution) numChecksWithUnmetAffinity() int {
+	runners := map[string]topology{}
+	for runnerName, runnerStatus := range distribution.Runners {
+		runners[runnerName] = runnerStatus.Topology
+	}
+
+	unmet := 0
+	for _, checkStatus := range distribution.Checks {
+		if checkStatus.Affinity.isEmpty() {
+			continue
+		}
+
+		affinity := resolveAffinity(checkStatus.Affinity, runners)
+		if affinity.match(runners[checkStatus.Runner]) < bestAffinityLevel(affinity, runners) {
+			unmet++
+		}
+	}
+
+	return unmet
+}
+
 func (distribution *checksDistribution) utilizationStdDev() float64 {
 	totalUtilization := 0.0
 	for _, runnerStatus := range distribution.Runners {
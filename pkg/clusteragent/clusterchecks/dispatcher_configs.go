@@ -31,12 +31,17 @@ func (d *dispatcher) getState() (types.StateResponse, error) {
 		Dangling: makeConfigArray(d.store.danglingConfigs),
 	}
 	for _, node := range d.store.nodes {
+		node.RLock()
 		n := types.StateNodeResponse{
 			Name:    node.name,
+			Zone:    node.topology.Zone,
+			Region:  node.topology.Region,
 			Configs: makeConfigArray(node.digestToConfig),
 		}
+		node.RUnlock()
 		response.Nodes = append(response.Nodes, n)
 	}
+	response.Placements = d.getPlacements()
 
 	return response, nil
 }
@@ -95,6 +100,7 @@ func (d *dispatcher) removeConfig(digest string) {
 	delete(d.store.digestToNode, digest)
 	delete(d.store.digestToConfig, digest)
 	delete(d.store.danglingConfigs, digest)
+	delete(d.store.digestToEndpoint, digest)
 
 	// This is a list because each instance in a config has its own check ID and
 	// all of them need to be deleted.
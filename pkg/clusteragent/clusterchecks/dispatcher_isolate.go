@@ -34,7 +34,7 @@ func (d *dispatcher) isolateCheck(isolateCheckID string) types.IsolateResponse {
 		}
 	}
 
-	proposedDistribution := newChecksDistribution(currentDistribution.runnerWorkers())
+	proposedDistribution := currentDistribution.withoutChecks()
 
 	for _, checkID := range currentDistribution.checksSortedByWorkersNeeded() {
 		if checkID == isolateCheckID {
@@ -48,6 +48,7 @@ func (d *dispatcher) isolateCheck(isolateCheckID string) types.IsolateResponse {
 		proposedDistribution.addToLeastBusy(
 			checkID,
 			workersNeededForCheck,
+			currentDistribution.affinityForCheck(checkID),
 			runnerForCheck,
 			isolateNode,
 		)
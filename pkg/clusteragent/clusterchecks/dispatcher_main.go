@@ -32,6 +32,7 @@ type dispatcher struct {
 	excludedChecks                map[string]struct{}
 	excludedChecksFromDispatching map[string]struct{}
 	rebalancingPeriod             time.Duration
+	endpointTopology              endpointTopologyFunc
 }
 
 func newDispatcher() *dispatcher {
@@ -60,6 +61,7 @@ func newDispatcher() *dispatcher {
 	}
 
 	d.rebalancingPeriod = config.Datadog().GetDuration("cluster_checks.rebalance_period")
+	d.endpointTopology = getEndpointTopologyFunc()
 
 	hname, _ := hostname.Get(context.TODO())
 	clusterTagValue := clustername.GetClusterName(context.TODO(), hname)
@@ -161,10 +163,13 @@ func (d *dispatcher) reschedule(configs []integration.Config) {
 
 // add stores and delegates a given configuration
 func (d *dispatcher) add(config integration.Config) {
-	target := d.getNodeToScheduleCheck()
+	affinity := d.resolveConfigAffinity(config)
+	target := d.getNodeToScheduleCheck(affinity)
 	if target == "" {
 		// If no node is found, store it in the danglingConfigs map for retrying later.
 		log.Warnf("No available node to dispatch %s:%s on, will retry later", config.Name, config.Digest())
+	} else if !affinity.isEmpty() {
+		log.Infof("Dispatching configuration %s:%s with affinity %s to node %s", config.Name, config.Digest(), affinity, target)
 	} else {
 		log.Infof("Dispatching configuration %s:%s to node %s", config.Name, config.Digest(), target)
 	}
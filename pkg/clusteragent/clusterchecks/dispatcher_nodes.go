@@ -54,6 +54,13 @@ func (d *dispatcher) processNodeStatus(nodeName, clientIP string, status types.N
 	node.Lock()
 	defer node.Unlock()
 	node.heartbeat = timestampNow()
+	if status.Zone != "" || status.Region != "" {
+		reported := topology{Zone: status.Zone, Region: status.Region}
+		if node.topology != reported {
+			log.Infof("Node %s reported its topology: %s", nodeName, reported)
+			node.topology = reported
+		}
+	}
 	// When we receive ExtraHeartbeatLastChangeValue, we only update heartbeat
 	if status.LastChange == types.ExtraHeartbeatLastChangeValue {
 		return true
@@ -88,20 +95,23 @@ func (d *dispatcher) processNodeStatus(nodeName, clientIP string, status types.N
 //
 // On the other hand, when advanced dispatching is not used, we can pick the
 // node with fewer checks. It's because the number of checks is kept up to date.
-func (d *dispatcher) getNodeToScheduleCheck() string {
+//
+// In both cases, only the nodes that best match the topology affinity of the
+// check are considered.
+func (d *dispatcher) getNodeToScheduleCheck(affinity topology) string {
 	if d.advancedDispatching {
-		return d.getRandomNode()
+		return d.getRandomNode(affinity)
 	}
 
-	return d.getNodeWithLessChecks()
+	return d.getNodeWithLessChecks(affinity)
 }
 
-func (d *dispatcher) getRandomNode() string {
+func (d *dispatcher) getRandomNode(affinity topology) string {
 	d.store.RLock()
 	defer d.store.RUnlock()
 
 	var nodes []string
-	for name := range d.store.nodes {
+	for name := range d.store.eligibleNodes(affinity) {
 		nodes = append(nodes, name)
 	}
 
@@ -112,14 +122,18 @@ func (d *dispatcher) getRandomNode() string {
 	return nodes[rand.Intn(len(nodes))]
 }
 
-func (d *dispatcher) getNodeWithLessChecks() string {
+func (d *dispatcher) getNodeWithLessChecks(affinity topology) string {
 	d.store.RLock()
 	defer d.store.RUnlock()
 
 	var selectedNode string
 	minNumChecks := 0
 
+	eligibleNodes := d.store.eligibleNodes(affinity)
 	for name, store := range d.store.nodes {
+		if _, eligible := eligibleNodes[name]; !eligible {
+			continue
+		}
 		if selectedNode == "" || len(store.digestToConfig) < minNumChecks {
 			selectedNode = name
 			minNumChecks = len(store.digestToConfig)
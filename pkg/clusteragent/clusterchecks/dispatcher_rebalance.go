@@ -120,7 +120,8 @@ func (d *dispatcher) pickCheckToMove(nodeName string) (string, int, error) {
 // if it satisfies the following
 // Diff(Ni) < Diff(Nj) (for each j != i, 0 <= j < len(nodes))
 // where Diff(N) is the difference between the busyness on N and the total average busyness.
-func pickNode(diffMap map[string]int, sourceNode string) string {
+// When eligibleNodes is not nil, only the nodes it contains are considered.
+func pickNode(diffMap map[string]int, sourceNode string, eligibleNodes map[string]struct{}) string {
 	firstItr := true
 	minDiff := 0
 	pickedNode := ""
@@ -128,6 +129,9 @@ func pickNode(diffMap map[string]int, sourceNode string) string {
 		if node == sourceNode {
 			continue
 		}
+		if _, eligible := eligibleNodes[node]; eligibleNodes != nil && !eligible {
+			continue
+		}
 		if diffMap[node] < minDiff || firstItr {
 			minDiff = diffMap[node]
 			pickedNode = node
@@ -137,6 +141,21 @@ func pickNode(diffMap map[string]int, sourceNode string) string {
 	return pickedNode
 }
 
+// eligibleNodesForCheck returns the nodes that a check can be moved to without
+// degrading how well its topology affinity is satisfied, or nil if the check
+// has no affinity
+func (d *dispatcher) eligibleNodesForCheck(checkID string) map[string]struct{} {
+	d.store.RLock()
+	defer d.store.RUnlock()
+
+	affinity := d.store.configAffinity(d.store.idToDigest[checkid.ID(checkID)])
+	if affinity.isEmpty() {
+		return nil
+	}
+
+	return d.store.eligibleNodes(affinity)
+}
+
 // moveCheck moves a check by its ID from a node to another
 func (d *dispatcher) moveCheck(src, dest, checkID string) error {
 	log.Debugf("Moving %s from %s to %s", checkID, src, dest)
@@ -211,7 +230,11 @@ func (d *dispatcher) rebalanceUsingBusyness() []types.RebalanceResponse {
 				break
 			}
 
-			destNodeName := pickNode(diffMap, sourceNodeName)
+			destNodeName := pickNode(diffMap, sourceNodeName, d.eligibleNodesForCheck(checkID))
+			if destNodeName == "" {
+				log.Debugf("No node satisfying the affinity of check %s to move it from node %s", checkID, sourceNodeName)
+				break
+			}
 			sourceDiff := diffMap[sourceNodeName]
 			destDiff := diffMap[destNodeName]
 
@@ -265,7 +288,9 @@ func (d *dispatcher) rebalanceUsingBusyness() []types.RebalanceResponse {
 // The implementation is a classical greedy algorithm. It sorts in descending
 // order all the cluster checks by the number of workers that we think that they
 // are going to require, and it goes one by one placing them in the runner with
-// the lowest utilization. When there are several candidate runners, first, if
+// the lowest utilization among the runners that best satisfy the topology
+// affinity of the check (same zone, then same region, if any runner reports
+// them). When there are several candidate runners, first, if
 // the current node is among the candidates, it leaves the check there to avoid
 // unnecessary check schedules and unschedules. If the current runner is not
 // among the candidates, it chooses the runner that contains fewer checks.
@@ -309,7 +334,7 @@ func (d *dispatcher) rebalanceUsingUtilization(force bool) []types.RebalanceResp
 
 	currentChecksDistribution := d.currentDistribution()
 
-	proposedDistribution := newChecksDistribution(currentChecksDistribution.runnerWorkers())
+	proposedDistribution := currentChecksDistribution.withoutChecks()
 
 	// First all the checks that are excluded from rebalancing are added to the
 	// same runner where they are currently running.
@@ -317,6 +342,7 @@ func (d *dispatcher) rebalanceUsingUtilization(force bool) []types.RebalanceResp
 		checkName := checkid.IDToCheckName(checkid.ID(checkID))
 		if _, excluded := d.excludedChecksFromDispatching[checkName]; excluded {
 			proposedDistribution.addCheck(checkID, check.WorkersNeeded, check.Runner)
+			proposedDistribution.Checks[checkID].Affinity = check.Affinity
 		}
 	}
 
@@ -327,6 +353,7 @@ func (d *dispatcher) rebalanceUsingUtilization(force bool) []types.RebalanceResp
 			proposedDistribution.addToLeastBusy(
 				checkID,
 				currentChecksDistribution.workersNeededForCheck(checkID),
+				currentChecksDistribution.affinityForCheck(checkID),
 				currentChecksDistribution.runnerForCheck(checkID),
 				"",
 			)
@@ -375,6 +402,9 @@ func (d *dispatcher) currentDistribution() checksDistribution {
 	}
 
 	distribution := newChecksDistribution(currentWorkersPerRunner)
+	for nodeName, nodeTopology := range d.store.nodesTopology() {
+		distribution.Runners[nodeName].Topology = nodeTopology
+	}
 
 	for nodeName, nodeStoreInfo := range d.store.nodes {
 		for checkID, stats := range nodeStoreInfo.clcRunnerStats {
@@ -383,7 +413,8 @@ func (d *dispatcher) currentDistribution() checksDistribution {
 			}
 
 			minCollectionInterval := defaults.DefaultCheckInterval
-			conf := d.store.digestToConfig[d.store.idToDigest[checkid.ID(checkID)]]
+			digest := d.store.idToDigest[checkid.ID(checkID)]
+			conf := d.store.digestToConfig[digest]
 			if len(conf.Instances) > 0 {
 				commonOptions := integration.CommonInstanceConfig{}
 				err := yaml.Unmarshal(conf.Instances[0], &commonOptions)
@@ -401,6 +432,7 @@ func (d *dispatcher) currentDistribution() checksDistribution {
 			}
 
 			distribution.addCheck(checkID, workersNeeded, nodeName)
+			distribution.Checks[checkID].Affinity = d.store.configAffinity(digest)
 		}
 	}
 
@@ -448,6 +480,14 @@ func setPredictedUtilization(distribution checksDistribution) {
 }
 
 func rebalanceIsWorthIt(currentDistribution checksDistribution, proposedDistribution checksDistribution, minPercImprovement int) bool {
+	// Honouring the topology affinity of the checks takes precedence over
+	// balancing the load.
+	currentUnmetAffinity := currentDistribution.numChecksWithUnmetAffinity()
+	proposedUnmetAffinity := proposedDistribution.numChecksWithUnmetAffinity()
+	if proposedUnmetAffinity != currentUnmetAffinity {
+		return proposedUnmetAffinity < currentUnmetAffinity
+	}
+
 	// If the current utilization stddev is already good enough, consider that
 	// rescheduling checks is not worth it, unless the new distribution has
 	// fewer runners with a high utilization or leaves fewer runners empty.
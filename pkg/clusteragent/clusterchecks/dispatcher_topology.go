@@ -0,0 +1,301 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build clusterchecks
+
+package clusterchecks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// topology describes where a node-agent runs, or where a check should
+// preferably run when used as an affinity
+type topology struct {
+	Zone   string `json:",omitempty"`
+	Region string `json:",omitempty"`
+}
+
+func (t topology) isEmpty() bool {
+	return t.Zone == "" && t.Region == ""
+}
+
+func (t topology) String() string {
+	var parts []string
+	if t.Zone != "" {
+		parts = append(parts, "zone:"+t.Zone)
+	}
+	if t.Region != "" {
+		parts = append(parts, "region:"+t.Region)
+	}
+	return strings.Join(parts, ",")
+}
+
+// affinityLevel ranks how well the topology of a node matches the affinity of
+// a check. Higher is better.
+type affinityLevel int
+
+const (
+	// affinityNone means that the node is neither in the preferred zone nor
+	// in the preferred region
+	affinityNone affinityLevel = iota
+	// affinityRegion means that the node is in the preferred region
+	affinityRegion
+	// affinityZone means that the node is in the preferred zone
+	affinityZone
+)
+
+// match returns how well the given node topology satisfies the affinity
+func (t topology) match(node topology) affinityLevel {
+	if t.Zone != "" && node.Zone == t.Zone {
+		return affinityZone
+	}
+	if t.Region != "" && node.Region == t.Region {
+		return affinityRegion
+	}
+	return affinityNone
+}
+
+// topologyHints holds the reserved fields that users can set in the instances
+// or in the init_config of a cluster check to express a topology affinity
+type topologyHints struct {
+	Zone   string `yaml:"cluster_check_zone"`
+	Region string `yaml:"cluster_check_region"`
+}
+
+// checkAffinity returns the topology where a configuration should preferably
+// be dispatched. The hints of the first instance take precedence over the ones
+// of the init_config.
+func checkAffinity(config integration.Config) topology {
+	affinity := topology{}
+
+	var sources []integration.Data
+	if len(config.Instances) > 0 {
+		sources = append(sources, config.Instances[0])
+	}
+	sources = append(sources, config.InitConfig)
+
+	for _, source := range sources {
+		if len(source) == 0 {
+			continue
+		}
+
+		hints := topologyHints{}
+		if err := yaml.Unmarshal(source, &hints); err != nil {
+			log.Debugf("Cannot read topology hints of config %s: %v", config.Name, err)
+			continue
+		}
+
+		if affinity.Zone == "" {
+			affinity.Zone = hints.Zone
+		}
+		if affinity.Region == "" {
+			affinity.Region = hints.Region
+		}
+	}
+
+	return affinity
+}
+
+// endpointTopologyFunc returns the topology of the node running the endpoint
+// identified by an AD service ID, and false if the service ID isn't an
+// endpoint or if its node is unknown
+type endpointTopologyFunc func(serviceID string) (topology, bool)
+
+// resolveConfigAffinity returns the topology where a configuration should
+// preferably be dispatched. Without topology hints, the checks of an endpoint
+// prefer the zone of the node running the endpoint. That topology is resolved
+// once, when the configuration is dispatched, and kept in the store. It is
+// looked up without holding the store lock.
+func (d *dispatcher) resolveConfigAffinity(config integration.Config) topology {
+	affinity := checkAffinity(config)
+	if !affinity.isEmpty() || d.endpointTopology == nil {
+		return affinity
+	}
+
+	digest := config.Digest()
+	d.store.RLock()
+	endpoint, found := d.store.digestToEndpoint[digest]
+	d.store.RUnlock()
+	if found {
+		return endpoint
+	}
+
+	endpoint, found = d.endpointTopology(config.ServiceID)
+	if !found {
+		return affinity
+	}
+
+	log.Debugf("Config %s:%s targets an endpoint in %s", config.Name, digest, endpoint)
+	d.store.Lock()
+	d.store.digestToEndpoint[digest] = endpoint
+	d.store.Unlock()
+
+	return endpoint
+}
+
+// configAffinity returns the affinity of a dispatched configuration, from its
+// topology hints or from the topology of its endpoint.
+// The store lock must be held by the caller.
+func (s *clusterStore) configAffinity(digest string) topology {
+	affinity := checkAffinity(s.digestToConfig[digest])
+	if affinity.isEmpty() {
+		affinity = s.digestToEndpoint[digest]
+	}
+	return affinity
+}
+
+// resolveAffinity completes an affinity that only specifies a zone with the
+// region of the nodes running in that zone, so that nodes in the same region
+// are preferred when no node runs in the zone.
+func resolveAffinity(affinity topology, nodes map[string]topology) topology {
+	if affinity.Zone == "" || affinity.Region != "" {
+		return affinity
+	}
+
+	for _, node := range orderedKeys(nodes) {
+		if nodes[node].Zone == affinity.Zone && nodes[node].Region != "" {
+			affinity.Region = nodes[node].Region
+			break
+		}
+	}
+
+	return affinity
+}
+
+// bestAffinityLevel returns the highest affinity level that can be reached by
+// one of the given nodes
+func bestAffinityLevel(affinity topology, nodes map[string]topology) affinityLevel {
+	best := affinityNone
+	for _, node := range nodes {
+		if level := affinity.match(node); level > best {
+			best = level
+		}
+	}
+	return best
+}
+
+// nodesTopology returns the topology reported by each node.
+// The store lock must be held by the caller.
+func (s *clusterStore) nodesTopology() map[string]topology {
+	res := make(map[string]topology, len(s.nodes))
+	for name, node := range s.nodes {
+		node.RLock()
+		res[name] = node.topology
+		node.RUnlock()
+	}
+	return res
+}
+
+// eligibleNodes returns the nodes that best satisfy the given affinity. If no
+// node satisfies it, or if the affinity is empty, all the nodes are returned.
+// The store lock must be held by the caller.
+func (s *clusterStore) eligibleNodes(affinity topology) map[string]struct{} {
+	res := make(map[string]struct{}, len(s.nodes))
+
+	nodes := s.nodesTopology()
+	affinity = resolveAffinity(affinity, nodes)
+	best := bestAffinityLevel(affinity, nodes)
+
+	for name, node := range nodes {
+		if affinity.match(node) >= best {
+			res[name] = struct{}{}
+		}
+	}
+
+	return res
+}
+
+// placementReason explains the placement of a check with the given affinity
+// on a node, given the topology of all the nodes
+func placementReason(affinity topology, node string, nodes map[string]topology) string {
+	affinity = resolveAffinity(affinity, nodes)
+	nodeTopology := nodes[node]
+	level := affinity.match(nodeTopology)
+	best := bestAffinityLevel(affinity, nodes)
+
+	switch {
+	case level == affinityZone:
+		return fmt.Sprintf("running in preferred zone %s", affinity.Zone)
+	case level == affinityRegion && best == affinityRegion && affinity.Zone != "":
+		return fmt.Sprintf("no agent reporting in zone %s, running in preferred region %s", affinity.Zone, affinity.Region)
+	case level == affinityRegion && best == affinityRegion:
+		return fmt.Sprintf("running in preferred region %s", affinity.Region)
+	case level < best:
+		return fmt.Sprintf("agents matching %s are available but the check runs on %s (%s), it will move on next rebalance", affinity, node, topologyOrUnknown(nodeTopology))
+	default:
+		return fmt.Sprintf("no agent reporting in %s, dispatched by load only", affinity)
+	}
+}
+
+func topologyOrUnknown(t topology) string {
+	if t.isEmpty() {
+		return "unknown topology"
+	}
+	return t.String()
+}
+
+// getPlacements explains the placement of the dispatched configurations that
+// have a topology affinity.
+// The store lock must be held by the caller.
+func (d *dispatcher) getPlacements() []types.PlacementResponse {
+	var placements []types.PlacementResponse
+
+	nodes := d.store.nodesTopology()
+	for digest, node := range d.store.digestToNode {
+		config := d.store.digestToConfig[digest]
+		affinity := d.store.configAffinity(digest)
+		if affinity.isEmpty() {
+			continue
+		}
+
+		placements = append(placements, types.PlacementResponse{
+			CheckName: config.Name,
+			Digest:    digest,
+			Node:      node,
+			Affinity:  affinity.String(),
+			Reason:    placementReason(affinity, node, nodes),
+		})
+	}
+
+	sort.Slice(placements, func(i, j int) bool {
+		if placements[i].CheckName == placements[j].CheckName {
+			return placements[i].Digest < placements[j].Digest
+		}
+		return placements[i].CheckName < placements[j].CheckName
+	})
+
+	return placements
+}
+
+// affinityStats returns the number of dispatched configurations that have a
+// topology affinity, and how many of them run on a node that doesn't match it.
+// The store lock must be held by the caller.
+func (d *dispatcher) affinityStats() (int, int) {
+	withAffinity, outsideAffinity := 0, 0
+
+	nodes := d.store.nodesTopology()
+	for digest, node := range d.store.digestToNode {
+		affinity := d.store.configAffinity(digest)
+		if affinity.isEmpty() {
+			continue
+		}
+
+		withAffinity++
+		if resolveAffinity(affinity, nodes).match(nodes[node]) == affinityNone {
+			outsideAffinity++
+		}
+	}
+
+	return withAffinity, outsideAffinity
+}
@@ -0,0 +1,139 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build clusterchecks && kubeapiserver
+
+package clusterchecks
+
+import (
+	"context"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	listersv1 "k8s.io/client-go/listers/core/v1"
+
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	kubeEndpointIDPrefix = "kube_endpoint_uid://"
+
+	zoneNodeLabel         = "topology.kubernetes.io/zone"
+	regionNodeLabel       = "topology.kubernetes.io/region"
+	legacyZoneNodeLabel   = "failure-domain.beta.kubernetes.io/zone"
+	legacyRegionNodeLabel = "failure-domain.beta.kubernetes.io/region"
+)
+
+// startEndpointTopologyInformers registers the endpoints and nodes informers
+// on the shared informer factory and starts them, as the factory may already
+// have been started when the handler runs
+func startEndpointTopologyInformers(ctx context.Context) {
+	cl, err := apiserver.GetAPIClient()
+	if err != nil {
+		log.Warnf("Cannot watch endpoints and nodes, the checks of endpoints won't prefer their zone: %v", err)
+		return
+	}
+
+	cl.InformerFactory.Core().V1().Endpoints().Informer()
+	cl.InformerFactory.Core().V1().Nodes().Informer()
+	cl.InformerFactory.Start(ctx.Done())
+}
+
+// getEndpointTopologyFunc resolves the topology of endpoints from the caches
+// of the shared informers, without querying the API server
+func getEndpointTopologyFunc() endpointTopologyFunc {
+	return func(serviceID string) (topology, bool) {
+		cl, err := apiserver.GetAPIClient()
+		if err != nil {
+			log.Debugf("Cannot get the topology of endpoint %s: %v", serviceID, err)
+			return topology{}, false
+		}
+
+		endpointsInformer := cl.InformerFactory.Core().V1().Endpoints()
+		nodesInformer := cl.InformerFactory.Core().V1().Nodes()
+		if !endpointsInformer.Informer().HasSynced() || !nodesInformer.Informer().HasSynced() {
+			log.Debugf("Cannot get the topology of endpoint %s: endpoints and nodes are not synced yet", serviceID)
+			return topology{}, false
+		}
+
+		return endpointTopology(serviceID, endpointsInformer.Lister(), nodesInformer.Lister())
+	}
+}
+
+// endpointTopology returns the topology of the node running the endpoint
+// identified by an AD service ID
+func endpointTopology(serviceID string, endpointsLister listersv1.EndpointsLister, nodesLister listersv1.NodeLister) (topology, bool) {
+	namespace, name, ip, ok := parseEndpointServiceID(serviceID)
+	if !ok {
+		return topology{}, false
+	}
+
+	endpoints, err := endpointsLister.Endpoints(namespace).Get(name)
+	if err != nil {
+		log.Debugf("Cannot get the topology of endpoint %s: %v", serviceID, err)
+		return topology{}, false
+	}
+
+	nodeName := endpointNodeName(endpoints, ip)
+	if nodeName == "" {
+		return topology{}, false
+	}
+
+	node, err := nodesLister.Get(nodeName)
+	if err != nil {
+		log.Debugf("Cannot get the labels of node %s running endpoint %s: %v", nodeName, serviceID, err)
+		return topology{}, false
+	}
+
+	t := nodeLabelsTopology(node.Labels)
+	return t, !t.isEmpty()
+}
+
+// parseEndpointServiceID splits the service ID of an endpoint, as built by
+// apiserver.EntityForEndpoints
+func parseEndpointServiceID(serviceID string) (string, string, string, bool) {
+	id, found := strings.CutPrefix(serviceID, kubeEndpointIDPrefix)
+	if !found {
+		return "", "", "", false
+	}
+
+	parts := strings.SplitN(id, "/", 3)
+	if len(parts) != 3 {
+		return "", "", "", false
+	}
+
+	return parts[0], parts[1], parts[2], true
+}
+
+// endpointNodeName returns the node of the endpoint address with the given IP
+func endpointNodeName(endpoints *v1.Endpoints, ip string) string {
+	for _, subset := range endpoints.Subsets {
+		for _, addr := range subset.Addresses {
+			if addr.IP == ip && addr.NodeName != nil {
+				return *addr.NodeName
+			}
+		}
+	}
+	return ""
+}
+
+// nodeLabelsTopology returns the topology of a node from its well-known
+// labels
+func nodeLabelsTopology(labels map[string]string) topology {
+	return topology{
+		Zone:   firstNonEmptyLabel(labels, zoneNodeLabel, legacyZoneNodeLabel),
+		Region: firstNonEmptyLabel(labels, regionNodeLabel, legacyRegionNodeLabel),
+	}
+}
+
+func firstNonEmptyLabel(labels map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if value := labels[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build clusterchecks && kubeapiserver
+
+package clusterchecks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	listersv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestParseEndpointServiceID(t *testing.T) {
+	namespace, name, ip, ok := parseEndpointServiceID("kube_endpoint_uid://default/redis/10.0.0.10")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"default", "redis", "10.0.0.10"}, []string{namespace, name, ip})
+
+	_, _, _, ok = parseEndpointServiceID("kube_service://default/redis")
+	assert.False(t, ok)
+	_, _, _, ok = parseEndpointServiceID("kube_endpoint_uid://default/redis")
+	assert.False(t, ok)
+}
+
+func TestEndpointNodeName(t *testing.T) {
+	node1, node2 := "node1", "node2"
+	endpoints := &v1.Endpoints{
+		Subsets: []v1.EndpointSubset{
+			{Addresses: []v1.EndpointAddress{{IP: "10.0.0.1", NodeName: &node1}}},
+			{Addresses: []v1.EndpointAddress{{IP: "10.0.0.2", NodeName: &node2}, {IP: "10.0.0.3"}}},
+		},
+	}
+
+	assert.Equal(t, "node2", endpointNodeName(endpoints, "10.0.0.2"))
+	assert.Equal(t, "", endpointNodeName(endpoints, "10.0.0.3"))
+	assert.Equal(t, "", endpointNodeName(endpoints, "10.0.0.4"))
+}
+
+func TestNodeLabelsTopology(t *testing.T) {
+	assert.Equal(t, topology{Zone: "us-east-1a", Region: "us-east-1"}, nodeLabelsTopology(map[string]string{
+		"topology.kubernetes.io/zone":              "us-east-1a",
+		"failure-domain.beta.kubernetes.io/zone":   "legacy",
+		"failure-domain.beta.kubernetes.io/region": "us-east-1",
+	}))
+	assert.True(t, nodeLabelsTopology(map[string]string{"app": "redis"}).isEmpty())
+}
+
+func TestEndpointTopology(t *testing.T) {
+	node1 := "node1"
+	endpointsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	require.NoError(t, endpointsIndexer.Add(&v1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "redis"},
+		Subsets: []v1.EndpointSubset{
+			{Addresses: []v1.EndpointAddress{{IP: "10.0.0.1", NodeName: &node1}, {IP: "10.0.0.2"}}},
+		},
+	}))
+	nodesIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, nodesIndexer.Add(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{"topology.kubernetes.io/zone": "us-east-1a"}},
+	}))
+	endpointsLister := listersv1.NewEndpointsLister(endpointsIndexer)
+	nodesLister := listersv1.NewNodeLister(nodesIndexer)
+
+	tp, found := endpointTopology("kube_endpoint_uid://default/redis/10.0.0.1", endpointsLister, nodesLister)
+	assert.True(t, found)
+	assert.Equal(t, topology{Zone: "us-east-1a"}, tp)
+
+	for _, serviceID := range []string{
+		"kube_endpoint_uid://default/redis/10.0.0.2", // no node
+		"kube_endpoint_uid://default/redis/10.0.0.3", // unknown address
+		"kube_endpoint_uid://default/memcached/10.0.0.1",
+		"kube_service://default/redis",
+	} {
+		_, found = endpointTopology(serviceID, endpointsLister, nodesLister)
+		assert.False(t, found, serviceID)
+	}
+}
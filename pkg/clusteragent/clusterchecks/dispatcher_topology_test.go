@@ -0,0 +1,263 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build clusterchecks
+
+package clusterchecks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+)
+
+func generateIntegrationWithAffinity(name string, instance string) integration.Config {
+	return integration.Config{
+		Name:         name,
+		ClusterCheck: true,
+		Instances:    []integration.Data{integration.Data(instance)},
+	}
+}
+
+func TestCheckAffinity(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   integration.Config
+		expected topology
+	}{
+		{
+			name:     "no hints",
+			config:   generateIntegrationWithAffinity("http_check", "url: http://example.com"),
+			expected: topology{},
+		},
+		{
+			name:     "instance hints",
+			config:   generateIntegrationWithAffinity("http_check", "url: http://example.com\ncluster_check_zone: us-east-1a\ncluster_check_region: us-east-1"),
+			expected: topology{Zone: "us-east-1a", Region: "us-east-1"},
+		},
+		{
+			name: "instance hints take precedence over init_config",
+			config: integration.Config{
+				Name:       "postgres",
+				InitConfig: integration.Data("cluster_check_zone: us-east-1b\ncluster_check_region: us-east-1"),
+				Instances:  []integration.Data{integration.Data("cluster_check_zone: us-east-1a")},
+			},
+			expected: topology{Zone: "us-east-1a", Region: "us-east-1"},
+		},
+		{
+			name: "invalid instance",
+			config: integration.Config{
+				Name:       "postgres",
+				InitConfig: integration.Data("cluster_check_region: us-east-1"),
+				Instances:  []integration.Data{integration.Data("{")},
+			},
+			expected: topology{Region: "us-east-1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, checkAffinity(test.config))
+		})
+	}
+}
+
+func TestPlacementReason(t *testing.T) {
+	nodes := map[string]topology{
+		"node-a": {Zone: "us-east-1a", Region: "us-east-1"},
+		"node-b": {Zone: "us-east-1b", Region: "us-east-1"},
+		"node-c": {Zone: "eu-west-1a", Region: "eu-west-1"},
+		"node-d": {},
+	}
+
+	assert.Equal(t, "running in preferred zone us-east-1a",
+		placementReason(topology{Zone: "us-east-1a"}, "node-a", nodes))
+	assert.Equal(t, "no agent reporting in zone us-east-1c, running in preferred region us-east-1",
+		placementReason(topology{Zone: "us-east-1c", Region: "us-east-1"}, "node-b", nodes))
+	assert.Equal(t, "running in preferred region eu-west-1",
+		placementReason(topology{Region: "eu-west-1"}, "node-c", nodes))
+	assert.Equal(t, "agents matching zone:us-east-1a,region:us-east-1 are available but the check runs on node-d (unknown topology), it will move on next rebalance",
+		placementReason(topology{Zone: "us-east-1a"}, "node-d", nodes))
+	assert.Equal(t, "no agent reporting in zone:ap-south-1a, dispatched by load only",
+		placementReason(topology{Zone: "ap-south-1a"}, "node-c", nodes))
+}
+
+func TestProcessNodeStatusTopology(t *testing.T) {
+	dispatcher := newDispatcher()
+
+	dispatcher.processNodeStatus("node1", "10.0.0.1", types.NodeStatus{Zone: "us-east-1a", Region: "us-east-1"})
+	node1, found := dispatcher.store.getNodeStore("node1")
+	require.True(t, found)
+	assert.Equal(t, topology{Zone: "us-east-1a", Region: "us-east-1"}, node1.topology)
+
+	// Extra heartbeats don't carry the topology, it must be kept
+	dispatcher.processNodeStatus("node1", "10.0.0.1", types.NodeStatus{LastChange: types.ExtraHeartbeatLastChangeValue})
+	assert.Equal(t, topology{Zone: "us-east-1a", Region: "us-east-1"}, node1.topology)
+
+	requireNotLocked(t, dispatcher.store)
+}
+
+func TestGetNodeWithLessChecksAffinity(t *testing.T) {
+	dispatcher := newDispatcher()
+
+	dispatcher.processNodeStatus("node-a", "10.0.0.1", types.NodeStatus{Zone: "us-east-1a", Region: "us-east-1"})
+	dispatcher.processNodeStatus("node-b", "10.0.0.2", types.NodeStatus{Zone: "us-east-1b", Region: "us-east-1"})
+	dispatcher.processNodeStatus("node-c", "10.0.0.3", types.NodeStatus{Zone: "eu-west-1a", Region: "eu-west-1"})
+
+	// node-a is the busiest, node-c the least busy
+	dispatcher.addConfig(generateIntegration("A1"), "node-a")
+	dispatcher.addConfig(generateIntegration("A2"), "node-a")
+	dispatcher.addConfig(generateIntegration("B1"), "node-b")
+
+	// No affinity: least busy node
+	assert.Equal(t, "node-c", dispatcher.getNodeWithLessChecks(topology{}))
+
+	// Zone affinity takes precedence over the load
+	assert.Equal(t, "node-a", dispatcher.getNodeWithLessChecks(topology{Zone: "us-east-1a"}))
+
+	// No node in the zone: least busy node of its region, resolved from the
+	// nodes reporting in the zone
+	assert.Equal(t, "node-b", dispatcher.getNodeWithLessChecks(topology{Zone: "us-east-1c", Region: "us-east-1"}))
+	assert.Equal(t, "node-b", dispatcher.getNodeWithLessChecks(topology{Region: "us-east-1"}))
+
+	// No node matching: least busy node
+	assert.Equal(t, "node-c", dispatcher.getNodeWithLessChecks(topology{Zone: "ap-south-1a"}))
+
+	requireNotLocked(t, dispatcher.store)
+}
+
+func TestScheduleWithAffinity(t *testing.T) {
+	dispatcher := newDispatcher()
+
+	dispatcher.processNodeStatus("node-a", "10.0.0.1", types.NodeStatus{Zone: "us-east-1a", Region: "us-east-1"})
+	dispatcher.processNodeStatus("node-b", "10.0.0.2", types.NodeStatus{Zone: "us-east-1b", Region: "us-east-1"})
+
+	config := generateIntegrationWithAffinity("postgres", "host: db.example.com\ncluster_check_zone: us-east-1b")
+	dispatcher.Schedule([]integration.Config{config, generateIntegration("A")})
+
+	configs, _, err := dispatcher.getClusterCheckConfigs("node-b")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"postgres"}, extractCheckNames(configs))
+
+	state, err := dispatcher.getState()
+	require.NoError(t, err)
+	require.Len(t, state.Placements, 1)
+	assert.Equal(t, "postgres", state.Placements[0].CheckName)
+	assert.Equal(t, "node-b", state.Placements[0].Node)
+	assert.Equal(t, "zone:us-east-1b", state.Placements[0].Affinity)
+	assert.Equal(t, "running in preferred zone us-east-1b", state.Placements[0].Reason)
+
+	stats := dispatcher.getStats()
+	assert.Equal(t, 1, stats.AffinityConfigs)
+	assert.Equal(t, 0, stats.OutsideAffinityConfigs)
+
+	requireNotLocked(t, dispatcher.store)
+}
+
+func TestScheduleWithEndpointAffinity(t *testing.T) {
+	dispatcher := newDispatcher()
+	resolved := 0
+	dispatcher.endpointTopology = func(serviceID string) (topology, bool) {
+		resolved++
+		if serviceID == "kube_endpoint_uid://default/redis/10.0.0.10" {
+			return topology{Zone: "us-east-1b", Region: "us-east-1"}, true
+		}
+		return topology{}, false
+	}
+
+	dispatcher.processNodeStatus("node-a", "10.0.0.1", types.NodeStatus{Zone: "us-east-1a", Region: "us-east-1"})
+	dispatcher.processNodeStatus("node-b", "10.0.0.2", types.NodeStatus{Zone: "us-east-1b", Region: "us-east-1"})
+
+	endpoint := generateIntegrationWithAffinity("redisdb", "host: 10.0.0.10")
+	endpoint.ServiceID = "kube_endpoint_uid://default/redis/10.0.0.10"
+	// explicit hints take precedence over the zone of the endpoint
+	hinted := generateIntegrationWithAffinity("postgres", "host: 10.0.0.11\ncluster_check_zone: us-east-1a")
+	hinted.ServiceID = "kube_endpoint_uid://default/redis/10.0.0.10"
+	dispatcher.Schedule([]integration.Config{endpoint, hinted})
+
+	configs, _, err := dispatcher.getClusterCheckConfigs("node-b")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"redisdb"}, extractCheckNames(configs))
+	configs, _, err = dispatcher.getClusterCheckConfigs("node-a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"postgres"}, extractCheckNames(configs))
+
+	state, err := dispatcher.getState()
+	require.NoError(t, err)
+	require.Len(t, state.Placements, 2)
+	assert.Equal(t, "redisdb", state.Placements[1].CheckName)
+	assert.Equal(t, "zone:us-east-1b,region:us-east-1", state.Placements[1].Affinity)
+	assert.Equal(t, "running in preferred zone us-east-1b", state.Placements[1].Reason)
+
+	// the topology of the endpoint is resolved once
+	dispatcher.reschedule([]integration.Config{configs[0]})
+	assert.Equal(t, 1, resolved)
+
+	requireNotLocked(t, dispatcher.store)
+}
+
+func TestAddToLeastBusyWithAffinity(t *testing.T) {
+	distribution := newChecksDistribution(map[string]int{
+		"runner-a1": 4,
+		"runner-a2": 4,
+		"runner-b":  4,
+		"runner-c":  4,
+	})
+	distribution.Runners["runner-a1"].Topology = topology{Zone: "us-east-1a", Region: "us-east-1"}
+	distribution.Runners["runner-a2"].Topology = topology{Zone: "us-east-1a", Region: "us-east-1"}
+	distribution.Runners["runner-b"].Topology = topology{Zone: "us-east-1b", Region: "us-east-1"}
+	distribution.Runners["runner-c"].Topology = topology{Zone: "eu-west-1a", Region: "eu-west-1"}
+
+	distribution.addCheck("check1", 3, "runner-a1")
+	distribution.addCheck("check2", 2, "runner-a2")
+	distribution.addCheck("check3", 1, "runner-b")
+
+	// Least busy runner of the zone, even if busier than the others
+	distribution.addToLeastBusy("zoneCheck", 1, topology{Zone: "us-east-1a"}, "", "")
+	assert.Equal(t, "runner-a2", distribution.runnerForCheck("zoneCheck"))
+	assert.Equal(t, topology{Zone: "us-east-1a"}, distribution.affinityForCheck("zoneCheck"))
+
+	// Excluding runners of the zone falls back to the region
+	distribution.addToLeastBusy("isolatedCheck", 1, topology{Zone: "us-east-1b"}, "", "runner-b")
+	assert.Equal(t, "runner-a1", distribution.runnerForCheck("isolatedCheck"))
+
+	// No affinity: least busy runner
+	distribution.addToLeastBusy("otherCheck", 1, topology{}, "", "")
+	assert.Equal(t, "runner-c", distribution.runnerForCheck("otherCheck"))
+}
+
+func TestRebalanceIsWorthItWithAffinity(t *testing.T) {
+	workersPerRunner := map[string]int{
+		"runner-a": 4,
+		"runner-b": 4,
+	}
+
+	newDistribution := func() checksDistribution {
+		distribution := newChecksDistribution(workersPerRunner)
+		distribution.Runners["runner-a"].Topology = topology{Zone: "us-east-1a"}
+		distribution.Runners["runner-b"].Topology = topology{Zone: "us-east-1b"}
+		return distribution
+	}
+
+	// Perfectly balanced, but check1 runs outside of its zone
+	currentDistribution := newDistribution()
+	currentDistribution.addCheck("check1", 1, "runner-a")
+	currentDistribution.Checks["check1"].Affinity = topology{Zone: "us-east-1b"}
+	currentDistribution.addCheck("check2", 1, "runner-b")
+	assert.Equal(t, 1, currentDistribution.numChecksWithUnmetAffinity())
+
+	proposedDistribution := newDistribution()
+	proposedDistribution.addCheck("check1", 1, "runner-b")
+	proposedDistribution.Checks["check1"].Affinity = topology{Zone: "us-east-1b"}
+	proposedDistribution.addCheck("check2", 1, "runner-b")
+	assert.Equal(t, 0, proposedDistribution.numChecksWithUnmetAffinity())
+
+	assert.True(t, rebalanceIsWorthIt(currentDistribution, proposedDistribution, 10))
+	assert.False(t, rebalanceIsWorthIt(proposedDistribution, currentDistribution, 10))
+}
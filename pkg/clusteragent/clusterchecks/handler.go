@@ -100,6 +100,10 @@ func (h *Handler) Run(ctx context.Context) {
 	}
 	h.m.Unlock()
 
+	if h.dispatcher.endpointTopology != nil {
+		startEndpointTopologyInformers(ctx)
+	}
+
 	for {
 		// Follower / unknown
 		select {
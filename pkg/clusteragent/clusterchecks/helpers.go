@@ -64,7 +64,7 @@ func busynessFunc(s types.CLCRunnerStats) int {
 }
 
 // orderedKeys sorts the keys of a map and return them in a slice
-func orderedKeys(m map[string]int) []string {
+func orderedKeys[V any](m map[string]V) []string {
 	keys := []string{}
 	for key := range m {
 		keys = append(keys, key)
@@ -60,12 +60,15 @@ func (d *dispatcher) getStats() *types.Stats {
 	for _, m := range d.store.digestToConfig {
 		checkNames[m.Name] = struct{}{}
 	}
+	affinityConfigs, outsideAffinityConfigs := d.affinityStats()
 	return &types.Stats{
-		Active:          d.store.active,
-		NodeCount:       len(d.store.nodes),
-		ActiveConfigs:   len(d.store.digestToNode),
-		DanglingConfigs: len(d.store.danglingConfigs),
-		TotalConfigs:    len(d.store.digestToConfig),
-		CheckNames:      checkNames,
+		Active:                 d.store.active,
+		NodeCount:              len(d.store.nodes),
+		ActiveConfigs:          len(d.store.digestToNode),
+		DanglingConfigs:        len(d.store.danglingConfigs),
+		TotalConfigs:           len(d.store.digestToConfig),
+		CheckNames:             checkNames,
+		AffinityConfigs:        affinityConfigs,
+		OutsideAffinityConfigs: outsideAffinityConfigs,
 	}
 }
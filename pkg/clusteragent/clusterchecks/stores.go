@@ -30,6 +30,7 @@ type clusterStore struct {
 	danglingConfigs  map[string]integration.Config            // Configs we could not dispatch to any node
 	endpointsConfigs map[string]map[string]integration.Config // Endpoints configs to be consumed by node agents
 	idToDigest       map[checkid.ID]string                    // link check IDs to check configs
+	digestToEndpoint map[string]topology                      // Topology of the node of the endpoint targeted by a config
 }
 
 func newClusterStore() *clusterStore {
@@ -47,6 +48,7 @@ func (s *clusterStore) reset() {
 	s.danglingConfigs = make(map[string]integration.Config)
 	s.endpointsConfigs = make(map[string]map[string]integration.Config)
 	s.idToDigest = make(map[checkid.ID]string)
+	s.digestToEndpoint = make(map[string]topology)
 }
 
 // getNodeStore retrieves the store struct for a given node name, if it exists
@@ -89,6 +91,7 @@ type nodeStore struct {
 	clcRunnerStats   types.CLCRunnersStats
 	busyness         int
 	workers          int
+	topology         topology
 }
 
 func newNodeStore(name, clientIP string) *nodeStore {
@@ -20,6 +20,10 @@ const (
 // NodeStatus holds the status report from the node-agent
 type NodeStatus struct {
 	LastChange int64 `json:"last_change"`
+	// Zone and Region describe where the node-agent runs, they are used
+	// to dispatch checks according to their topology affinity
+	Zone   string `json:"zone,omitempty"`
+	Region string `json:"region,omitempty"`
 }
 
 // StatusResponse holds the DCA response for a status report
@@ -59,14 +63,27 @@ type StateResponse struct {
 	Warmup     bool                 `json:"warmup"`
 	Nodes      []StateNodeResponse  `json:"nodes"`
 	Dangling   []integration.Config `json:"dangling"`
+	Placements []PlacementResponse  `json:"placements,omitempty"`
 }
 
 // StateNodeResponse is a chunk of StateResponse
 type StateNodeResponse struct {
 	Name    string               `json:"name"`
+	Zone    string               `json:"zone,omitempty"`
+	Region  string               `json:"region,omitempty"`
 	Configs []integration.Config `json:"configs"`
 }
 
+// PlacementResponse explains the placement of a configuration that has a
+// topology affinity. It is a chunk of StateResponse
+type PlacementResponse struct {
+	CheckName string `json:"check_name"`
+	Digest    string `json:"digest"`
+	Node      string `json:"node"`
+	Affinity  string `json:"affinity"`
+	Reason    string `json:"reason"`
+}
+
 // Stats holds statistics for the agent status command
 type Stats struct {
 	// Following
@@ -81,6 +98,10 @@ type Stats struct {
 	DanglingConfigs int
 	TotalConfigs    int
 	CheckNames      map[string]struct{}
+
+	// Topology-aware dispatching
+	AffinityConfigs        int
+	OutsideAffinityConfigs int
 }
 
 // LeaderIPCallback describes the leader-election method we
@@ -50,6 +50,9 @@ func NewCollectorInventory() *CollectorInventory {
 			k8sCollectors.NewVerticalPodAutoscalerCollectorVersions(),
 			k8sCollectors.NewHorizontalPodAutoscalerCollectorVersions(),
 			k8sCollectors.NewNetworkPolicyCollectorVersions(),
+			k8sCollectors.NewPodDisruptionBudgetCollectorVersions(),
+			k8sCollectors.NewEndpointSliceCollectorVersions(),
+			k8sCollectors.NewPriorityClassCollectorVersions(),
 		},
 	}
 }
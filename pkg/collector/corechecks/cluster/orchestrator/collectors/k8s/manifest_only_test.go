@@ -0,0 +1,198 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver && orchestrator
+
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	model "github.com/DataDog/agent-payload/v5/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/collectors"
+	"github.com/DataDog/datadog-agent/pkg/orchestrator"
+	"github.com/DataDog/datadog-agent/pkg/orchestrator/config"
+	"github.com/DataDog/datadog-agent/pkg/util/pointer"
+)
+
+const lastAppliedConfiguration = "kubectl.kubernetes.io/last-applied-configuration"
+
+var redactedAnnotations = map[string]string{
+	lastAppliedConfiguration: "-",
+	"team":                   "platform",
+}
+
+func newManifestRunConfig(client *fake.Clientset) *collectors.CollectorRunConfig {
+	cfg := config.NewDefaultOrchestratorConfig()
+	cfg.KubeClusterName = "test-cluster"
+	cfg.ExtraTags = []string{"env:test"}
+	cfg.IsManifestCollectionEnabled = true
+
+	return &collectors.CollectorRunConfig{
+		K8sCollectorRunConfig: collectors.K8sCollectorRunConfig{
+			OrchestratorInformerFactory: &collectors.OrchestratorInformerFactory{
+				InformerFactory: informers.NewSharedInformerFactory(client, 0),
+			},
+		},
+		ClusterID:   "cluster-id",
+		Config:      cfg,
+		MsgGroupRef: atomic.NewInt32(0),
+	}
+}
+
+// runManifestCollector initializes the collector, waits for its informer to
+// sync and returns the manifests it produced.
+func runManifestCollector(t *testing.T, collector collectors.K8sCollector, client *fake.Clientset) []*model.Manifest {
+	rcfg := newManifestRunConfig(client)
+	collector.Init(rcfg)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	informer := collector.Informer()
+	go informer.Run(stopCh)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.True(t, cache.WaitForCacheSync(ctx.Done(), informer.HasSynced))
+
+	result, err := collector.Run(rcfg)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.ResourcesListed)
+	assert.Equal(t, 1, result.ResourcesProcessed)
+	require.Len(t, result.Result.ManifestMessages, 1)
+
+	collectorManifest := result.Result.ManifestMessages[0].(*model.CollectorManifest)
+	assert.Equal(t, "test-cluster", collectorManifest.ClusterName)
+	assert.Equal(t, "cluster-id", collectorManifest.ClusterId)
+	assert.Equal(t, []string{"env:test", "kube_api_version:" + collector.Metadata().Version}, collectorManifest.Tags)
+	for _, manifest := range collectorManifest.Manifests {
+		assert.Equal(t, int32(collector.Metadata().NodeType), manifest.Type)
+	}
+
+	return collectorManifest.Manifests
+}
+
+func newObjectMeta(name, namespace, uid string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:            name,
+		Namespace:       namespace,
+		UID:             types.UID("e42e5adc-0749-11e8-a2b8-000c29dea4f6-" + uid),
+		ResourceVersion: "1234",
+		Annotations: map[string]string{
+			lastAppliedConfiguration: `{"apiVersion":"v1"}`,
+			"team":                   "platform",
+		},
+	}
+}
+
+func TestPodDisruptionBudgetCollector(t *testing.T) {
+	minAvailable := intstr.FromInt(2)
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: newObjectMeta("redis", "default", "pdb"),
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable: &minAvailable,
+			Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "redis"}},
+		},
+		Status: policyv1.PodDisruptionBudgetStatus{
+			CurrentHealthy:     2,
+			DesiredHealthy:     2,
+			DisruptionsAllowed: 0,
+			ExpectedPods:       2,
+		},
+	}
+
+	client := fake.NewSimpleClientset(pdb)
+	collector := NewPodDisruptionBudgetCollector()
+	assert.EqualValues(t, orchestrator.K8sPodDisruptionBudget, collector.Metadata().NodeType)
+	assert.Equal(t, "policy/v1/poddisruptionbudgets", collector.Metadata().FullName())
+	assert.False(t, collector.Metadata().IsMetadataProducer)
+
+	manifests := runManifestCollector(t, collector, client)
+	require.Len(t, manifests, 1)
+	assert.Equal(t, string(pdb.UID), manifests[0].Uid)
+	assert.Equal(t, "1234", manifests[0].ResourceVersion)
+
+	var actual policyv1.PodDisruptionBudget
+	require.NoError(t, json.Unmarshal(manifests[0].Content, &actual))
+	assert.Equal(t, redactedAnnotations, actual.Annotations)
+	assert.Equal(t, pdb.Spec, actual.Spec)
+	assert.Equal(t, int32(0), actual.Status.DisruptionsAllowed)
+}
+
+func TestEndpointSliceCollector(t *testing.T) {
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta:  newObjectMeta("redis-abcde", "default", "eps"),
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints: []discoveryv1.Endpoint{
+			{
+				Addresses:  []string{"10.0.0.1"},
+				Conditions: discoveryv1.EndpointConditions{Ready: pointer.Ptr(true)},
+				NodeName:   pointer.Ptr("node-1"),
+				Zone:       pointer.Ptr("us-east-1a"),
+			},
+		},
+		Ports: []discoveryv1.EndpointPort{
+			{Name: pointer.Ptr("redis"), Port: pointer.Ptr(int32(6379)), Protocol: pointer.Ptr(corev1.ProtocolTCP)},
+		},
+	}
+
+	client := fake.NewSimpleClientset(slice)
+	collector := NewEndpointSliceCollector()
+	assert.EqualValues(t, orchestrator.K8sEndpointSlice, collector.Metadata().NodeType)
+	assert.Equal(t, "discovery.k8s.io/v1/endpointslices", collector.Metadata().FullName())
+
+	manifests := runManifestCollector(t, collector, client)
+	require.Len(t, manifests, 1)
+	assert.Equal(t, string(slice.UID), manifests[0].Uid)
+
+	var actual discoveryv1.EndpointSlice
+	require.NoError(t, json.Unmarshal(manifests[0].Content, &actual))
+	assert.Equal(t, redactedAnnotations, actual.Annotations)
+	assert.Equal(t, slice.Endpoints, actual.Endpoints)
+	assert.Equal(t, slice.Ports, actual.Ports)
+}
+
+func TestPriorityClassCollector(t *testing.T) {
+	preemptLowerPriority := corev1.PreemptLowerPriority
+	priorityClass := &schedulingv1.PriorityClass{
+		ObjectMeta:       newObjectMeta("high-priority", "", "pc"),
+		Value:            1000000,
+		GlobalDefault:    false,
+		Description:      "Critical workloads",
+		PreemptionPolicy: &preemptLowerPriority,
+	}
+
+	client := fake.NewSimpleClientset(priorityClass)
+	collector := NewPriorityClassCollector()
+	assert.EqualValues(t, orchestrator.K8sPriorityClass, collector.Metadata().NodeType)
+	assert.Equal(t, "scheduling.k8s.io/v1/priorityclasses", collector.Metadata().FullName())
+
+	manifests := runManifestCollector(t, collector, client)
+	require.Len(t, manifests, 1)
+	assert.Equal(t, string(priorityClass.UID), manifests[0].Uid)
+
+	var actual schedulingv1.PriorityClass
+	require.NoError(t, json.Unmarshal(manifests[0].Content, &actual))
+	assert.Equal(t, redactedAnnotations, actual.Annotations)
+	assert.Equal(t, int32(1000000), actual.Value)
+	assert.Equal(t, &preemptLowerPriority, actual.PreemptionPolicy)
+}
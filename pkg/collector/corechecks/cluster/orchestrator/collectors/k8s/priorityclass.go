@@ -0,0 +1,93 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver && orchestrator
+
+package k8s
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/collectors"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/processors"
+	k8sProcessors "github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/processors/k8s"
+	"github.com/DataDog/datadog-agent/pkg/orchestrator"
+
+	"k8s.io/apimachinery/pkg/labels"
+	schedulingv1Informers "k8s.io/client-go/informers/scheduling/v1"
+	schedulingv1Listers "k8s.io/client-go/listers/scheduling/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NewPriorityClassCollectorVersions builds the group of collector versions.
+func NewPriorityClassCollectorVersions() collectors.CollectorVersions {
+	return collectors.NewCollectorVersions(
+		NewPriorityClassCollector(),
+	)
+}
+
+// PriorityClassCollector is a collector for Kubernetes PriorityClasses.
+type PriorityClassCollector struct {
+	informer  schedulingv1Informers.PriorityClassInformer
+	lister    schedulingv1Listers.PriorityClassLister
+	metadata  *collectors.CollectorMetadata
+	processor *processors.Processor
+}
+
+// NewPriorityClassCollector creates a new collector for the Kubernetes
+// PriorityClass resource. Only manifests are collected for this resource.
+func NewPriorityClassCollector() *PriorityClassCollector {
+	return &PriorityClassCollector{
+		metadata: &collectors.CollectorMetadata{
+			IsDefaultVersion:          true,
+			IsStable:                  false,
+			IsMetadataProducer:        false,
+			IsManifestProducer:        true,
+			SupportsManifestBuffering: true,
+			Name:                      "priorityclasses",
+			NodeType:                  orchestrator.K8sPriorityClass,
+			Version:                   "scheduling.k8s.io/v1",
+		},
+		processor: processors.NewProcessor(new(k8sProcessors.PriorityClassHandlers)),
+	}
+}
+
+// Informer returns the shared informer.
+func (c *PriorityClassCollector) Informer() cache.SharedInformer {
+	return c.informer.Informer()
+}
+
+// Init is used to initialize the collector.
+func (c *PriorityClassCollector) Init(rcfg *collectors.CollectorRunConfig) {
+	c.informer = rcfg.OrchestratorInformerFactory.InformerFactory.Scheduling().V1().PriorityClasses()
+	c.lister = c.informer.Lister()
+}
+
+// Metadata is used to access information about the collector.
+func (c *PriorityClassCollector) Metadata() *collectors.CollectorMetadata {
+	return c.metadata
+}
+
+// Run triggers the collection process.
+func (c *PriorityClassCollector) Run(rcfg *collectors.CollectorRunConfig) (*collectors.CollectorRunResult, error) {
+	list, err := c.lister.List(labels.Everything())
+	if err != nil {
+		return nil, collectors.NewListingError(err)
+	}
+
+	ctx := collectors.NewK8sProcessorContext(rcfg, c.metadata)
+
+	processResult, processed := c.processor.Process(ctx, list)
+
+	if processed == -1 {
+		return nil, collectors.ErrProcessingPanic
+	}
+
+	result := &collectors.CollectorRunResult{
+		Result:             processResult,
+		ResourcesListed:    len(list),
+		ResourcesProcessed: processed,
+	}
+
+	return result, nil
+}
@@ -0,0 +1,86 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build orchestrator
+
+package k8s
+
+import (
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	model "github.com/DataDog/agent-payload/v5/process"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/processors"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/processors/common"
+	"github.com/DataDog/datadog-agent/pkg/orchestrator/redact"
+)
+
+// PriorityClassHandlers implements the Handlers interface for Kubernetes PriorityClasses.
+// The agent payload has no metadata model for this resource yet, so only
+// manifests are produced.
+type PriorityClassHandlers struct {
+	common.BaseHandlers
+}
+
+// AfterMarshalling is a handler called after resource marshalling.
+//
+//nolint:revive // TODO(CAPP) Fix revive linter
+func (h *PriorityClassHandlers) AfterMarshalling(ctx processors.ProcessorContext, resource, resourceModel interface{}, yaml []byte) (skip bool) {
+	return
+}
+
+// BuildMessageBody is a handler called to build a message body out of a list of
+// extracted resources.
+//
+//nolint:revive // TODO(CAPP) Fix revive linter
+func (h *PriorityClassHandlers) BuildMessageBody(ctx processors.ProcessorContext, resourceModels []interface{}, groupSize int) model.MessageBody {
+	return nil
+}
+
+// ExtractResource is a handler called to extract the resource model out of a raw resource.
+//
+//nolint:revive // TODO(CAPP) Fix revive linter
+func (h *PriorityClassHandlers) ExtractResource(ctx processors.ProcessorContext, resource interface{}) (resourceModel interface{}) {
+	return nil
+}
+
+// ResourceList is a handler called to convert a list passed as a generic
+// interface to a list of generic interfaces.
+//
+//nolint:revive // TODO(CAPP) Fix revive linter
+func (h *PriorityClassHandlers) ResourceList(ctx processors.ProcessorContext, list interface{}) (resources []interface{}) {
+	resourceList := list.([]*schedulingv1.PriorityClass)
+	resources = make([]interface{}, 0, len(resourceList))
+
+	for _, resource := range resourceList {
+		resources = append(resources, resource)
+	}
+
+	return resources
+}
+
+// ResourceUID is a handler called to retrieve the resource UID.
+//
+//nolint:revive // TODO(CAPP) Fix revive linter
+func (h *PriorityClassHandlers) ResourceUID(ctx processors.ProcessorContext, resource interface{}) types.UID {
+	return resource.(*schedulingv1.PriorityClass).UID
+}
+
+// ResourceVersion is a handler called to retrieve the resource version.
+//
+//nolint:revive // TODO(CAPP) Fix revive linter
+func (h *PriorityClassHandlers) ResourceVersion(ctx processors.ProcessorContext, resource, resourceModel interface{}) string {
+	return resource.(*schedulingv1.PriorityClass).ResourceVersion
+}
+
+// ScrubBeforeExtraction is a handler called to redact the raw resource before
+// it is extracted as an internal resource model.
+//
+//nolint:revive // TODO(CAPP) Fix revive linter
+func (h *PriorityClassHandlers) ScrubBeforeExtraction(ctx processors.ProcessorContext, resource interface{}) {
+	r := resource.(*schedulingv1.PriorityClass)
+	redact.RemoveLastAppliedConfigurationAnnotation(r.Annotations)
+}
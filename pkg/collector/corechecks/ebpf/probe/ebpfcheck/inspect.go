@@ -0,0 +1,264 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package ebpfcheck
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/cilium/ebpf"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/ebpf/probe/ebpfcheck/model"
+	ddebpf "github.com/DataDog/datadog-agent/pkg/ebpf"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	defaultDumpLimit = 100
+	maxDumpLimit     = 1000
+)
+
+// ListPrograms returns the ebpf programs owned by the agent, with their attach points and memory lock usage
+func (k *Probe) ListPrograms() ([]model.EBPFProgramInfo, error) {
+	var stats model.EBPFStats
+	if err := k.getProgramStats(&stats); err != nil {
+		return nil, err
+	}
+
+	var progs []model.EBPFProgramInfo
+	for _, ps := range stats.Programs {
+		if ps.Module == "unknown" {
+			continue
+		}
+		info := model.EBPFProgramInfo{EBPFProgramStats: ps}
+		info.AttachPoints, _ = ddebpf.GetAttachPointsFromProgID(ps.ID)
+		memlock, err := programMemlock(ps.ID)
+		if err != nil {
+			log.Debugf("error getting program memlock prog_id=%d: %s", ps.ID, err)
+		}
+		info.Memlock = memlock
+		progs = append(progs, info)
+	}
+	return progs, nil
+}
+
+// ListMaps returns the ebpf maps owned by the agent, with their fill ratio and memory lock usage
+func (k *Probe) ListMaps() ([]model.EBPFMapInfo, error) {
+	var stats model.EBPFStats
+	if err := k.getMapStats(&stats); err != nil {
+		return nil, err
+	}
+
+	var maps []model.EBPFMapInfo
+	for _, ms := range stats.Maps {
+		if ms.Module == "unknown" {
+			continue
+		}
+		info := model.EBPFMapInfo{EBPFMapStats: ms, FillRatio: -1}
+		if ms.Entries >= 0 && ms.MaxEntries > 0 {
+			info.FillRatio = float64(ms.Entries) / float64(ms.MaxEntries)
+		}
+		memlock, err := mapMemlock(ms.ID)
+		if err != nil {
+			log.Debugf("error getting map memlock map_id=%d: %s", ms.ID, err)
+		}
+		info.Memlock = memlock
+		maps = append(maps, info)
+	}
+	return maps, nil
+}
+
+// DumpMap returns a page of the entries of an ebpf map owned by the agent. Entries are decoded
+// with the Go types registered for the map, if any.
+func (k *Probe) DumpMap(req model.EBPFMapDumpRequest) (*model.EBPFMapDump, error) {
+	m, dump, err := openOwnedMap(req.Map)
+	if err != nil {
+		return nil, err
+	}
+	defer m.Close()
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultDumpLimit
+	}
+	if limit > maxDumpLimit {
+		limit = maxDumpLimit
+	}
+
+	// a nil interface makes NextKey start from the first key
+	var cursor interface{}
+	if req.Cursor != "" {
+		key, err := hex.DecodeString(req.Cursor)
+		if err != nil || len(key) != int(m.KeySize()) {
+			return nil, fmt.Errorf("invalid cursor %q for map %s with key size %d", req.Cursor, dump.Name, m.KeySize())
+		}
+		cursor = key
+	}
+
+	var decodeKey, decodeValue func([]byte) (interface{}, error)
+	if decoder, ok := ddebpf.GetMapDecoder(dump.Name); ok {
+		decodeKey, decodeValue = decoder.DecodeKey, decoder.DecodeValue
+		dump.Decoded = true
+	}
+	perCPU := isPerCPU(m.Type())
+	valueSize := int(m.ValueSize())
+
+	// if the cursor key was deleted the kernel restarts the iteration from the first key,
+	// so we never scan more than the capacity of the map
+	var lastKey []byte
+	for scanned := uint32(0); scanned < m.MaxEntries(); scanned++ {
+		key, err := m.NextKeyBytes(cursor)
+		if err != nil {
+			return nil, fmt.Errorf("error iterating over map %s: %w", dump.Name, err)
+		}
+		if key == nil {
+			return dump, nil
+		}
+		cursor, lastKey = key, key
+
+		value, err := m.LookupBytes(key)
+		if err != nil {
+			return nil, fmt.Errorf("error looking up map %s: %w", dump.Name, err)
+		}
+		if value == nil {
+			// deleted since it was iterated over
+			continue
+		}
+
+		entry := model.EBPFMapEntry{
+			RawKey: hex.EncodeToString(key),
+			Key:    formatMapData(key, decodeKey),
+		}
+		if perCPU {
+			// per-CPU values are laid out one after the other, 8-byte aligned
+			stride := int(roundUpPow2(uint32(valueSize), 8))
+			for off := 0; off+valueSize <= len(value); off += stride {
+				entry.Value = append(entry.Value, formatMapData(value[off:off+valueSize], decodeValue))
+			}
+		} else {
+			entry.Value = []string{formatMapData(value, decodeValue)}
+		}
+		if req.Filter != "" && !entryMatches(entry, req.Filter) {
+			continue
+		}
+
+		dump.Entries = append(dump.Entries, entry)
+		if len(dump.Entries) == limit {
+			dump.NextCursor = entry.RawKey
+			return dump, nil
+		}
+	}
+	if lastKey != nil {
+		dump.NextCursor = hex.EncodeToString(lastKey)
+	}
+	return dump, nil
+}
+
+// formatMapData decodes a key or a value with the agent types, and falls back to hex
+func formatMapData(buf []byte, decode func([]byte) (interface{}, error)) string {
+	if decode != nil {
+		if v, err := decode(buf); err == nil {
+			return fmt.Sprintf("%+v", v)
+		}
+	}
+	return hex.EncodeToString(buf)
+}
+
+func entryMatches(entry model.EBPFMapEntry, filter string) bool {
+	if strings.Contains(entry.Key, filter) {
+		return true
+	}
+	for _, v := range entry.Value {
+		if strings.Contains(v, filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// openOwnedMap opens the map owned by the agent with the given ID or name
+func openOwnedMap(nameOrID string) (*ebpf.Map, *model.EBPFMapDump, error) {
+	if id, err := strconv.ParseUint(nameOrID, 10, 32); err == nil {
+		name, err := ddebpf.GetMapNameFromMapID(uint32(id))
+		if err != nil {
+			return nil, nil, fmt.Errorf("map %d is not owned by the agent", id)
+		}
+		module, _ := ddebpf.GetModuleFromMapID(uint32(id))
+		m, err := ebpf.NewMapFromID(ebpf.MapID(id))
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to open map %d: %w", id, err)
+		}
+		return m, &model.EBPFMapDump{ID: uint32(id), Name: name, Module: module}, nil
+	}
+
+	var found []ebpf.MapID
+	var err error
+	mapid := ebpf.MapID(0)
+	for mapid, err = ebpf.MapGetNextID(mapid); err == nil; mapid, err = ebpf.MapGetNextID(mapid) {
+		if name, err := ddebpf.GetMapNameFromMapID(uint32(mapid)); err == nil && name == nameOrID {
+			found = append(found, mapid)
+		}
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, nil, fmt.Errorf("error iterating over maps: %w", err)
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, nil, fmt.Errorf("no map named %s is owned by the agent", nameOrID)
+	case 1:
+		return openOwnedMap(strconv.FormatUint(uint64(found[0]), 10))
+	default:
+		return nil, nil, fmt.Errorf("several maps are named %s, select one by ID among %v", nameOrID, found)
+	}
+}
+
+func programMemlock(id uint32) (uint64, error) {
+	fd, err := ProgGetFdByID(&ProgGetFdByIDAttr{ID: id})
+	if err != nil {
+		return 0, err
+	}
+	defer syscall.Close(int(fd))
+	return fdinfoMemlock(int(fd))
+}
+
+func mapMemlock(id uint32) (uint64, error) {
+	m, err := ebpf.NewMapFromID(ebpf.MapID(id))
+	if err != nil {
+		return 0, err
+	}
+	defer m.Close()
+	return fdinfoMemlock(m.FD())
+}
+
+// fdinfoMemlock reads the memory charged by the kernel for a map or a program
+func fdinfoMemlock(fd int) (uint64, error) {
+	f, err := os.Open(fmt.Sprintf("/proc/self/fdinfo/%d", fd))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		value, ok := strings.CutPrefix(scanner.Text(), "memlock:")
+		if ok {
+			return strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, errors.New("memlock not found in fdinfo")
+}
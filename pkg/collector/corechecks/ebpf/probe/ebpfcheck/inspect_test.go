@@ -0,0 +1,81 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package ebpfcheck
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/rlimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/ebpf/probe/ebpfcheck/model"
+	ddebpf "github.com/DataDog/datadog-agent/pkg/ebpf"
+)
+
+type testInspectValue struct {
+	Count uint64
+}
+
+func TestDumpMap(t *testing.T) {
+	require.NoError(t, rlimit.RemoveMemlock())
+
+	m, err := ebpf.NewMap(&ebpf.MapSpec{Type: ebpf.Hash, KeySize: 4, ValueSize: 8, MaxEntries: 20})
+	require.NoError(t, err)
+	coll := &ebpf.Collection{Maps: map[string]*ebpf.Map{"test_inspect": m}}
+	ddebpf.AddNameMappingsCollection(coll, "test")
+	ddebpf.AddMapDecoder[uint32, testInspectValue]("test_inspect")
+	t.Cleanup(func() {
+		ddebpf.RemoveNameMappingsCollection(coll)
+		_ = m.Close()
+	})
+	for i := uint32(0); i < 10; i++ {
+		require.NoError(t, m.Put(i, uint64(i*100)))
+	}
+
+	k := &Probe{}
+	keys := map[string]string{}
+	req := model.EBPFMapDumpRequest{Map: "test_inspect", Limit: 4}
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 3, "too many pages")
+		dump, err := k.DumpMap(req)
+		require.NoError(t, err)
+		assert.True(t, dump.Decoded)
+		assert.Equal(t, "test", dump.Module)
+		for _, e := range dump.Entries {
+			keys[e.Key] = e.Value[0]
+		}
+		if dump.NextCursor == "" {
+			break
+		}
+		req.Cursor = dump.NextCursor
+	}
+	assert.Len(t, keys, 10)
+	assert.Equal(t, "{Count:700}", keys["7"])
+
+	dump, err := k.DumpMap(model.EBPFMapDumpRequest{Map: "test_inspect", Filter: "Count:300}"})
+	require.NoError(t, err)
+	require.Len(t, dump.Entries, 1)
+	assert.Equal(t, "3", dump.Entries[0].Key)
+
+	info, err := m.Info()
+	require.NoError(t, err)
+	id, _ := info.ID()
+	dump, err = k.DumpMap(model.EBPFMapDumpRequest{Map: fmt.Sprint(id), Filter: "Count:300}"})
+	require.NoError(t, err)
+	assert.Equal(t, "test_inspect", dump.Name)
+
+	_, err = k.DumpMap(model.EBPFMapDumpRequest{Map: "unknown_map"})
+	assert.EqualError(t, err, "no map named unknown_map is owned by the agent")
+
+	memlock, err := mapMemlock(uint32(id))
+	require.NoError(t, err)
+	assert.NotZero(t, memlock)
+}
@@ -47,3 +47,49 @@ type EBPFProgramStats struct {
 	VerifiedInsns   uint32
 	Type            ebpf.ProgramType
 }
+
+// EBPFProgramInfo describes an ebpf program owned by the agent, as reported by the inspection API
+type EBPFProgramInfo struct {
+	EBPFProgramStats
+	AttachPoints []string
+	Memlock      uint64
+}
+
+// EBPFMapInfo describes an ebpf map owned by the agent, as reported by the inspection API
+type EBPFMapInfo struct {
+	EBPFMapStats
+	FillRatio float64 // Negative when the number of entries could not be calculated
+	Memlock   uint64
+}
+
+// EBPFMapDumpRequest selects the entries of a map to dump
+type EBPFMapDumpRequest struct {
+	// Map is the name or the ID of the map
+	Map string
+	// Limit is the maximum number of entries returned
+	Limit int
+	// Cursor is the raw key, hex-encoded, after which the dump starts
+	Cursor string
+	// Filter only keeps the entries whose decoded key or value contains it
+	Filter string
+}
+
+// EBPFMapDump is a page of the entries of an ebpf map
+type EBPFMapDump struct {
+	ID      uint32
+	Name    string
+	Module  string
+	Decoded bool
+	Entries []EBPFMapEntry
+	// NextCursor is empty when the end of the map was reached
+	NextCursor string
+}
+
+// EBPFMapEntry is an entry of an ebpf map. Key and Value are decoded with the agent types
+// when the map has a registered decoder, and hex-encoded otherwise.
+type EBPFMapEntry struct {
+	RawKey string
+	Key    string
+	// Value holds one element per CPU for per-CPU maps
+	Value []string
+}
@@ -28,3 +28,18 @@ func (t *Probe) Close() {}
 func (t *Probe) GetAndFlush() model.EBPFStats {
 	return model.EBPFStats{}
 }
+
+// ListPrograms is not implemented on non-linux systems
+func (t *Probe) ListPrograms() ([]model.EBPFProgramInfo, error) {
+	return nil, ebpf.ErrNotImplemented
+}
+
+// ListMaps is not implemented on non-linux systems
+func (t *Probe) ListMaps() ([]model.EBPFMapInfo, error) {
+	return nil, ebpf.ErrNotImplemented
+}
+
+// DumpMap is not implemented on non-linux systems
+func (t *Probe) DumpMap(model.EBPFMapDumpRequest) (*model.EBPFMapDump, error) {
+	return nil, ebpf.ErrNotImplemented
+}
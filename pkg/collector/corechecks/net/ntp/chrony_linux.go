@@ -0,0 +1,245 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package ntp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// The chronyd command protocol is defined in candm.h, in the chrony sources. All the fields are in network order.
+const (
+	chronyProtocolVersion = 6
+	chronyPktTypeRequest  = 1
+	chronyPktTypeReply    = 2
+
+	chronyReqTracking = 33
+	chronyRpyTracking = 5
+	chronyStsSuccess  = 0
+
+	chronyReplyHeaderSize = 28
+	// chronyTrackingSize is the size of the tracking reply payload
+	chronyTrackingSize = 76
+	// chronyTrackingRequestSize is the size of the tracking request. Requests are padded to the size of their
+	// reply, so chronyd can't be used for amplification attacks.
+	chronyTrackingRequestSize = chronyReplyHeaderSize + chronyTrackingSize
+
+	chronyAddrInet4 = 1
+	chronyAddrInet6 = 2
+
+	chronyFloatExpBits  = 7
+	chronyFloatCoefBits = 32 - chronyFloatExpBits
+)
+
+var (
+	// defaultChronyAddresses are the chronyd command sockets of the host, then its command port, which only
+	// answers to monitoring requests such as tracking
+	defaultChronyAddresses = []string{"/var/run/chrony/chronyd.sock", "/host/var/run/chrony/chronyd.sock", "127.0.0.1:323"}
+
+	chronyClientSocketID atomic.Uint32
+)
+
+// chronyTracking is the payload of a tracking reply
+type chronyTracking struct {
+	RefID              uint32
+	IPAddr             [16]byte
+	IPFamily           uint16
+	_                  uint16
+	Stratum            uint16
+	LeapStatus         uint16
+	RefTimeSecHigh     uint32
+	RefTimeSecLow      uint32
+	RefTimeNsec        uint32
+	CurrentCorrection  uint32
+	LastOffset         uint32
+	RMSOffset          uint32
+	FreqPPM            uint32
+	ResidFreqPPM       uint32
+	SkewPPM            uint32
+	RootDelay          uint32
+	RootDispersion     uint32
+	LastUpdateInterval uint32
+}
+
+// getChronyState queries chronyd for its tracking state, on the given address or on the default ones
+func getChronyState(address string, timeout time.Duration) (*localSyncState, error) {
+	addresses := defaultChronyAddresses
+	if address != "" {
+		addresses = []string{address}
+	}
+
+	var errs []error
+	for _, addr := range addresses {
+		tracking, err := queryChronyTracking(addr, timeout)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", addr, err))
+			continue
+		}
+		return tracking.syncState(), nil
+	}
+	return nil, errors.Join(errs...)
+}
+
+// queryChronyTracking sends a tracking request to chronyd, either on its command socket if address is a path,
+// or on its command port
+func queryChronyTracking(address string, timeout time.Duration) (*chronyTracking, error) {
+	var conn net.Conn
+	var err error
+	if strings.HasPrefix(address, "/") {
+		conn, err = dialChronySocket(address)
+	} else {
+		conn, err = net.DialTimeout("udp", address, timeout)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err = conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+
+	sequence := rand.Uint32()
+	if _, err = conn.Write(chronyTrackingRequest(sequence)); err != nil {
+		return nil, err
+	}
+
+	reply := make([]byte, 1024)
+	n, err := conn.Read(reply)
+	if err != nil {
+		return nil, err
+	}
+	return parseChronyTrackingReply(reply[:n], sequence)
+}
+
+// dialChronySocket connects to the command socket of chronyd. The client socket must be bound, so chronyd can
+// send its reply, and be writable by the chrony user.
+func dialChronySocket(address string) (net.Conn, error) {
+	local := filepath.Join(os.TempDir(), fmt.Sprintf("datadog-agent-chronyc.%d.%d.sock", os.Getpid(), chronyClientSocketID.Add(1)))
+	conn, err := net.DialUnix("unixgram", &net.UnixAddr{Name: local, Net: "unixgram"}, &net.UnixAddr{Name: address, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	socket := &chronySocketConn{UnixConn: conn, local: local}
+	if err = os.Chmod(local, 0666); err != nil {
+		socket.Close()
+		return nil, err
+	}
+	return socket, nil
+}
+
+// chronySocketConn is a connection to the command socket of chronyd, whose client socket file is removed on close
+type chronySocketConn struct {
+	*net.UnixConn
+	local string
+}
+
+func (c *chronySocketConn) Close() error {
+	defer os.Remove(c.local)
+	return c.UnixConn.Close()
+}
+
+// chronyTrackingRequest returns a tracking request, padded to the size of its reply
+func chronyTrackingRequest(sequence uint32) []byte {
+	req := make([]byte, chronyTrackingRequestSize)
+	req[0] = chronyProtocolVersion
+	req[1] = chronyPktTypeRequest
+	binary.BigEndian.PutUint16(req[4:], chronyReqTracking)
+	binary.BigEndian.PutUint32(req[8:], sequence)
+	return req
+}
+
+func parseChronyTrackingReply(reply []byte, sequence uint32) (*chronyTracking, error) {
+	if len(reply) < chronyReplyHeaderSize {
+		return nil, fmt.Errorf("reply too short: %d bytes", len(reply))
+	}
+	if reply[0] != chronyProtocolVersion || reply[1] != chronyPktTypeReply {
+		return nil, fmt.Errorf("unexpected reply version %d and type %d", reply[0], reply[1])
+	}
+	if seq := binary.BigEndian.Uint32(reply[16:]); seq != sequence {
+		return nil, fmt.Errorf("unexpected reply sequence %d, expected %d", seq, sequence)
+	}
+	if status := binary.BigEndian.Uint16(reply[8:]); status != chronyStsSuccess {
+		return nil, fmt.Errorf("request failed with status %d", status)
+	}
+	if command, rpy := binary.BigEndian.Uint16(reply[4:]), binary.BigEndian.Uint16(reply[6:]); command != chronyReqTracking || rpy != chronyRpyTracking {
+		return nil, fmt.Errorf("unexpected reply %d to command %d", rpy, command)
+	}
+	if len(reply) < chronyTrackingRequestSize {
+		return nil, fmt.Errorf("tracking reply too short: %d bytes", len(reply))
+	}
+
+	var tracking chronyTracking
+	if err := binary.Read(bytes.NewReader(reply[chronyReplyHeaderSize:]), binary.BigEndian, &tracking); err != nil {
+		return nil, err
+	}
+	return &tracking, nil
+}
+
+func (t *chronyTracking) syncState() *localSyncState {
+	state := &localSyncState{
+		daemon:         "chrony",
+		leapStatus:     leapStatus(t.LeapStatus),
+		synchronized:   leapStatus(t.LeapStatus) != leapUnsynchronized,
+		hasSource:      true,
+		source:         t.source(),
+		stratum:        int(t.Stratum),
+		offset:         durationFromSeconds(chronyFloat(t.CurrentCorrection)),
+		rootDelay:      durationFromSeconds(chronyFloat(t.RootDelay)),
+		rootDispersion: durationFromSeconds(chronyFloat(t.RootDispersion)),
+	}
+	state.estimatedError = state.maxError()
+	return state
+}
+
+// source returns the address of the server chronyd is synchronized to, or the name of its reference clock,
+// such as PHC0 for a PTP hardware clock
+func (t *chronyTracking) source() string {
+	switch t.IPFamily {
+	case chronyAddrInet4:
+		return net.IP(t.IPAddr[:4]).String()
+	case chronyAddrInet6:
+		return net.IP(t.IPAddr[:]).String()
+	}
+	if t.RefID == 0 {
+		return ""
+	}
+	refID := binary.BigEndian.AppendUint32(nil, t.RefID)
+	return string(bytes.TrimRight(refID, "\x00"))
+}
+
+// chronyFloat decodes the floats of the chrony protocol: a 7-bit signed exponent followed by a 25-bit signed
+// coefficient
+func chronyFloat(f uint32) float64 {
+	exp := int32(f >> chronyFloatCoefBits)
+	if exp >= 1<<(chronyFloatExpBits-1) {
+		exp -= 1 << chronyFloatExpBits
+	}
+	exp -= chronyFloatCoefBits
+
+	coef := int32(f % (1 << chronyFloatCoefBits))
+	if coef >= 1<<(chronyFloatCoefBits-1) {
+		coef -= 1 << chronyFloatCoefBits
+	}
+	return float64(coef) * math.Pow(2, float64(exp))
+}
+
+// durationFromSeconds converts a number of seconds to a duration, rounded to the nanosecond
+func durationFromSeconds(s float64) time.Duration {
+	return time.Duration(math.Round(s * float64(time.Second)))
+}
@@ -0,0 +1,220 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package ntp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/beevik/ntp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+// encodeChronyFloat is the inverse of chronyFloat, ported from UTI_FloatHostToNetwork in the chrony sources
+func encodeChronyFloat(x float64) uint32 {
+	const coefMax = 1<<(chronyFloatCoefBits-1) - 1
+	var exp, coef, neg int32
+	if x < 0 {
+		x, neg = -x, 1
+	}
+	if x >= 1e-100 {
+		exp = int32(math.Log2(x)) + 1
+		coef = int32(x*math.Pow(2, float64(-exp+chronyFloatCoefBits)) + 0.5)
+		for coef > coefMax+neg {
+			coef >>= 1
+			exp++
+		}
+	}
+	if neg == 1 {
+		coef = int32(uint32(-coef) << chronyFloatExpBits >> chronyFloatExpBits)
+	}
+	return uint32(exp)<<chronyFloatCoefBits | uint32(coef)
+}
+
+// fakeChronyd answers to the tracking requests received on conn with the given tracking state
+func fakeChronyd(t *testing.T, conn net.PacketConn, tracking chronyTracking) {
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			req := buf[:n]
+			// chronyd drops the requests which are not padded to the size of their reply
+			if n != chronyTrackingRequestSize || req[0] != chronyProtocolVersion || req[1] != chronyPktTypeRequest ||
+				binary.BigEndian.Uint16(req[4:]) != chronyReqTracking {
+				continue
+			}
+
+			reply := make([]byte, chronyReplyHeaderSize, chronyTrackingRequestSize)
+			reply[0] = chronyProtocolVersion
+			reply[1] = chronyPktTypeReply
+			binary.BigEndian.PutUint16(reply[4:], chronyReqTracking)
+			binary.BigEndian.PutUint16(reply[6:], chronyRpyTracking)
+			copy(reply[16:20], req[8:12])
+			var payload bytes.Buffer
+			_ = binary.Write(&payload, binary.BigEndian, &tracking)
+			reply = append(reply, payload.Bytes()...)
+
+			_, _ = conn.WriteTo(reply, addr)
+		}
+	}()
+}
+
+func listenFakeChronydSocket(t *testing.T, tracking chronyTracking) string {
+	address := filepath.Join(t.TempDir(), "chronyd.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: address, Net: "unixgram"})
+	require.NoError(t, err)
+	fakeChronyd(t, conn, tracking)
+	return address
+}
+
+func testChronyTracking() chronyTracking {
+	tracking := chronyTracking{
+		RefID:             0xc0000201,
+		IPFamily:          chronyAddrInet4,
+		Stratum:           3,
+		LeapStatus:        uint16(leapNormal),
+		CurrentCorrection: encodeChronyFloat(-0.000125),
+		RootDelay:         encodeChronyFloat(0.004),
+		RootDispersion:    encodeChronyFloat(0.0005),
+	}
+	copy(tracking.IPAddr[:], net.IPv4(192, 0, 2, 1).To4())
+	return tracking
+}
+
+func TestChronyFloat(t *testing.T) {
+	for _, x := range []float64{0, 1, -1, 0.5, 1e-9, -0.000125, 0.004, 123456.789, -42.42} {
+		assert.InDelta(t, x, chronyFloat(encodeChronyFloat(x)), math.Abs(x)*1e-7, "%v", x)
+	}
+	// 2^23 * 2^(2-25)
+	assert.Equal(t, 1.0, chronyFloat(0x04800000))
+}
+
+func TestChronyTrackingSource(t *testing.T) {
+	tracking := testChronyTracking()
+	assert.Equal(t, "192.0.2.1", tracking.source())
+
+	tracking.IPFamily = chronyAddrInet6
+	copy(tracking.IPAddr[:], net.ParseIP("2001:db8::1"))
+	assert.Equal(t, "2001:db8::1", tracking.source())
+
+	// reference clocks have no address, their reference ID is their name
+	tracking.IPFamily = 0
+	tracking.RefID = binary.BigEndian.Uint32([]byte("PHC0"))
+	assert.Equal(t, "PHC0", tracking.source())
+
+	tracking.RefID = 0
+	assert.Equal(t, "", tracking.source())
+}
+
+func TestChronyStateFromSocket(t *testing.T) {
+	address := listenFakeChronydSocket(t, testChronyTracking())
+
+	state, err := getChronyState(address, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, &localSyncState{
+		daemon:         "chrony",
+		synchronized:   true,
+		leapStatus:     leapNormal,
+		estimatedError: 2625 * time.Microsecond,
+		hasSource:      true,
+		source:         "192.0.2.1",
+		stratum:        3,
+		offset:         -125 * time.Microsecond,
+		rootDelay:      4 * time.Millisecond,
+		rootDispersion: 500 * time.Microsecond,
+	}, state)
+
+	// the client socket is removed
+	matches, err := filepath.Glob(filepath.Join(os.TempDir(), fmt.Sprintf("datadog-agent-chronyc.%d.*", os.Getpid())))
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+func TestChronyStateFromCommandPort(t *testing.T) {
+	tracking := testChronyTracking()
+	tracking.LeapStatus = uint16(leapUnsynchronized)
+	tracking.Stratum = 0
+	tracking.IPFamily = 0
+	tracking.RefID = 0
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	fakeChronyd(t, conn, tracking)
+
+	state, err := getChronyState(conn.LocalAddr().String(), time.Second)
+	require.NoError(t, err)
+	assert.False(t, state.synchronized)
+	assert.Equal(t, leapUnsynchronized, state.leapStatus)
+	assert.Equal(t, "", state.source)
+}
+
+func TestChronyStateUnreachable(t *testing.T) {
+	_, err := getChronyState(filepath.Join(t.TempDir(), "chronyd.sock"), time.Second)
+	assert.Error(t, err)
+}
+
+func TestParseChronyTrackingReplyErrors(t *testing.T) {
+	_, err := parseChronyTrackingReply(make([]byte, 10), 1)
+	assert.EqualError(t, err, "reply too short: 10 bytes")
+
+	reply := make([]byte, chronyTrackingRequestSize)
+	reply[0] = chronyProtocolVersion
+	reply[1] = chronyPktTypeReply
+	binary.BigEndian.PutUint32(reply[16:], 2)
+	_, err = parseChronyTrackingReply(reply, 1)
+	assert.EqualError(t, err, "unexpected reply sequence 2, expected 1")
+
+	// STT_UNAUTH, returned when a command is not allowed on the command port
+	binary.BigEndian.PutUint16(reply[8:], 2)
+	_, err = parseChronyTrackingReply(reply, 2)
+	assert.EqualError(t, err, "request failed with status 2")
+}
+
+func TestNTPLocalSyncState(t *testing.T) {
+	offset = 1
+	ntpQuery = testNTPQuery
+	defer func() { ntpQuery = ntp.QueryWithOptions }()
+
+	address := listenFakeChronydSocket(t, testChronyTracking())
+	ntpCfg := []byte(fmt.Sprintf("collect_local_sync_state: true\nchrony_address: %s\nhosts: [ 0.datadog.pool.ntp.org ]\n", address))
+
+	ntpCheck := new(NTPCheck)
+	senderManager := mocksender.CreateDefaultDemultiplexer()
+	require.NoError(t, ntpCheck.Configure(senderManager, integration.FakeConfigHash, ntpCfg, []byte(""), "test"))
+	mockSender := mocksender.NewMockSenderWithSenderManager(ntpCheck.ID(), senderManager)
+	mockSender.SetupAcceptAll()
+
+	require.NoError(t, ntpCheck.Run())
+
+	tags := []string{"time_sync_daemon:chrony", "leap_status:normal", "sync_source:192.0.2.1"}
+	mockSender.AssertMetric(t, "Gauge", "ntp.local.synchronized", 1, "", tags)
+	mockSender.AssertMetric(t, "Gauge", "ntp.local.stratum", 3, "", tags)
+	mockSender.AssertMetric(t, "Gauge", "ntp.local.offset", -0.000125, "", tags)
+	mockSender.AssertMetric(t, "Gauge", "ntp.local.root_delay", 0.004, "", tags)
+	mockSender.AssertMetric(t, "Gauge", "ntp.local.root_dispersion", 0.0005, "", tags)
+	mockSender.AssertMetric(t, "Gauge", "ntp.local.estimated_error", 0.002625, "", tags)
+	mockSender.AssertServiceCheck(t, "ntp.local.in_sync", servicecheck.ServiceCheckOK, "", tags, "")
+	mockSender.AssertMetric(t, "Gauge", "ntp.offset", 1, "", nil)
+}
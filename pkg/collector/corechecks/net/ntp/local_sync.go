@@ -0,0 +1,91 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package ntp
+
+import (
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// leapStatus is the leap indicator of the local clock, with the NTP semantics
+type leapStatus uint32
+
+const (
+	leapNormal leapStatus = iota
+	leapInsertSecond
+	leapDeleteSecond
+	leapUnsynchronized
+)
+
+func (l leapStatus) String() string {
+	switch l {
+	case leapNormal:
+		return "normal"
+	case leapInsertSecond:
+		return "insert_second"
+	case leapDeleteSecond:
+		return "delete_second"
+	case leapUnsynchronized:
+		return "unsynchronized"
+	default:
+		return "unknown"
+	}
+}
+
+// localSyncState is the time synchronization state of the host, as reported by the local time daemon
+type localSyncState struct {
+	// daemon is the name of the daemon the state was read from
+	daemon       string
+	synchronized bool
+	leapStatus   leapStatus
+	// estimatedError is the estimated error of the local clock
+	estimatedError time.Duration
+
+	// hasSource is false when the state is read from the kernel, and the fields below are unknown
+	hasSource bool
+	// source is the server or reference clock the host is synchronized to
+	source         string
+	stratum        int
+	offset         time.Duration
+	rootDelay      time.Duration
+	rootDispersion time.Duration
+}
+
+func (c *NTPCheck) submitLocalSyncState(sender sender.Sender) {
+	state, err := getLocalSyncState(c.cfg)
+	if err != nil {
+		log.Warnf("Could not get the local time synchronization state: %s", err)
+		sender.ServiceCheck("ntp.local.in_sync", servicecheck.ServiceCheckUnknown, "", nil, err.Error())
+		return
+	}
+
+	tags := []string{"time_sync_daemon:" + state.daemon, "leap_status:" + state.leapStatus.String()}
+	if state.source != "" {
+		tags = append(tags, "sync_source:"+state.source)
+	}
+
+	synchronized := 0.0
+	if state.synchronized {
+		synchronized = 1.0
+	}
+	sender.Gauge("ntp.local.synchronized", synchronized, "", tags)
+	sender.Gauge("ntp.local.estimated_error", state.estimatedError.Seconds(), "", tags)
+	if state.hasSource {
+		sender.Gauge("ntp.local.stratum", float64(state.stratum), "", tags)
+		sender.Gauge("ntp.local.offset", state.offset.Seconds(), "", tags)
+		sender.Gauge("ntp.local.root_delay", state.rootDelay.Seconds(), "", tags)
+		sender.Gauge("ntp.local.root_dispersion", state.rootDispersion.Seconds(), "", tags)
+	}
+
+	if state.synchronized {
+		sender.ServiceCheck("ntp.local.in_sync", servicecheck.ServiceCheckOK, "", tags, "")
+	} else {
+		sender.ServiceCheck("ntp.local.in_sync", servicecheck.ServiceCheckCritical, "", tags, "The local clock is not synchronized")
+	}
+}
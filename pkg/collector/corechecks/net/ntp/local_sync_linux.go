@@ -0,0 +1,91 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package ntp
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// clock states returned by adjtimex, from linux/timex.h
+	timeInsert = 1
+	timeDelete = 2
+	timeError  = 5
+
+	// staUnsync is the status bit set by the kernel when the clock is not synchronized
+	staUnsync = 0x40
+	// maxDispersion is the maximum error above which systemd considers the clock as not synchronized
+	maxDispersion = 16 * time.Second
+)
+
+// for testing purpose
+var adjtimex = unix.Adjtimex
+
+// getLocalSyncState returns the synchronization state of the host clock. chronyd is queried first, then
+// systemd-timesyncd. When neither is reachable, which happens in containers not sharing the daemon sockets
+// of the host, the state is read from the kernel, whose clock is shared by all the containers.
+func getLocalSyncState(cfg *ntpConfig) (*localSyncState, error) {
+	timeout := time.Duration(cfg.instance.Timeout) * time.Second
+
+	state, err := getChronyState(cfg.instance.ChronyAddress, timeout)
+	if err == nil {
+		return state, nil
+	}
+	log.Debugf("Could not get the synchronization state from chronyd: %s", err)
+
+	state, err = getTimesyncdState(timeout)
+	if err == nil {
+		return state, nil
+	}
+	log.Debugf("Could not get the synchronization state from systemd-timesyncd: %s", err)
+
+	return getKernelSyncState()
+}
+
+// getKernelSyncState returns the synchronization state of the clock, as known by the kernel
+func getKernelSyncState() (*localSyncState, error) {
+	var tx unix.Timex
+	clockState, err := adjtimex(&tx)
+	if err != nil {
+		return nil, fmt.Errorf("adjtimex failed: %w", err)
+	}
+
+	state := &localSyncState{
+		daemon: "kernel",
+		// same definition as systemd, the error fields are in microseconds
+		synchronized:   tx.Status&staUnsync == 0 && time.Duration(tx.Maxerror)*time.Microsecond < maxDispersion,
+		estimatedError: time.Duration(tx.Esterror) * time.Microsecond,
+	}
+	switch clockState {
+	case timeInsert:
+		state.leapStatus = leapInsertSecond
+	case timeDelete:
+		state.leapStatus = leapDeleteSecond
+	case timeError:
+		state.leapStatus = leapUnsynchronized
+	}
+	if !state.synchronized {
+		state.leapStatus = leapUnsynchronized
+	}
+	return state, nil
+}
+
+// maxError returns the upper bound of the clock error from the state of its source, as defined by chrony:
+// |offset| + root dispersion + root delay / 2
+func (s *localSyncState) maxError() time.Duration {
+	offset := s.offset
+	if offset < 0 {
+		offset = -offset
+	}
+	return offset + s.rootDispersion + s.rootDelay/2
+}
@@ -0,0 +1,88 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package ntp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func fakeAdjtimex(clockState int, status int32, maxError, estError int64) func(*unix.Timex) (int, error) {
+	return func(tx *unix.Timex) (int, error) {
+		tx.Status = status
+		tx.Maxerror = maxError
+		tx.Esterror = estError
+		return clockState, nil
+	}
+}
+
+func TestKernelSyncState(t *testing.T) {
+	defer func() { adjtimex = unix.Adjtimex }()
+
+	adjtimex = fakeAdjtimex(0, 0, 1500, 200)
+	state, err := getKernelSyncState()
+	require.NoError(t, err)
+	assert.Equal(t, &localSyncState{daemon: "kernel", synchronized: true, leapStatus: leapNormal, estimatedError: 200 * time.Microsecond}, state)
+
+	adjtimex = fakeAdjtimex(timeInsert, 0, 1500, 200)
+	state, err = getKernelSyncState()
+	require.NoError(t, err)
+	assert.Equal(t, leapInsertSecond, state.leapStatus)
+
+	// the maximum error grows over 16s when the clock is not synchronized for a while
+	adjtimex = fakeAdjtimex(0, 0, 16000000, 200)
+	state, err = getKernelSyncState()
+	require.NoError(t, err)
+	assert.False(t, state.synchronized)
+
+	adjtimex = fakeAdjtimex(timeError, staUnsync, 1500, 200)
+	state, err = getKernelSyncState()
+	require.NoError(t, err)
+	assert.False(t, state.synchronized)
+	assert.Equal(t, leapUnsynchronized, state.leapStatus)
+}
+
+func TestTimesyncdSyncState(t *testing.T) {
+	msg := &timesyncdNTPMessage{
+		Leap:           uint32(leapNormal),
+		Stratum:        2,
+		RootDelay:      4000,
+		RootDispersion: 500,
+		// the server is 150us ahead, with a 1ms round trip
+		OriginateTimestamp:   1_000_000,
+		ReceiveTimestamp:     1_000_650,
+		TransmitTimestamp:    1_000_660,
+		DestinationTimestamp: 1_001_010,
+	}
+	kernel := &localSyncState{daemon: "kernel", synchronized: true, estimatedError: time.Millisecond}
+
+	assert.Equal(t, &localSyncState{
+		daemon:         "timesyncd",
+		synchronized:   true,
+		leapStatus:     leapNormal,
+		estimatedError: 2650 * time.Microsecond,
+		hasSource:      true,
+		source:         "time.example.com",
+		stratum:        2,
+		offset:         150 * time.Microsecond,
+		rootDelay:      4 * time.Millisecond,
+		rootDispersion: 500 * time.Microsecond,
+	}, timesyncdSyncState("time.example.com", msg, kernel))
+
+	// without kernel state, the leap indicator tells whether the server is synchronized
+	state := timesyncdSyncState("time.example.com", msg, nil)
+	assert.True(t, state.synchronized)
+
+	// no response received yet
+	state = timesyncdSyncState("time.example.com", &timesyncdNTPMessage{}, &localSyncState{synchronized: false, estimatedError: time.Second})
+	assert.Equal(t, &localSyncState{daemon: "timesyncd", leapStatus: leapUnsynchronized, estimatedError: time.Second}, state)
+}
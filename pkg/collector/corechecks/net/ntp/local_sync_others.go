@@ -0,0 +1,14 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !linux
+
+package ntp
+
+import "errors"
+
+func getLocalSyncState(*ntpConfig) (*localSyncState, error) {
+	return nil, errors.New("the local time synchronization state is only supported on Linux")
+}
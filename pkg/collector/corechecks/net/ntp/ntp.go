@@ -61,6 +61,8 @@ type ntpInstanceConfig struct {
 	Timeout                int      `yaml:"timeout"`
 	Version                int      `yaml:"version"`
 	UseLocalDefinedServers bool     `yaml:"use_local_defined_servers"`
+	CollectLocalSyncState  bool     `yaml:"collect_local_sync_state"`
+	ChronyAddress          string   `yaml:"chrony_address"`
 }
 
 type ntpInitConfig struct{}
@@ -178,6 +180,10 @@ func (c *NTPCheck) Run() error {
 	serviceCheckMessage := ""
 	offsetThreshold := c.cfg.instance.OffsetThreshold
 
+	if c.cfg.instance.CollectLocalSyncState {
+		c.submitLocalSyncState(sender)
+	}
+
 	clockOffset, err := c.queryOffset()
 	if err != nil {
 		log.Error(err)
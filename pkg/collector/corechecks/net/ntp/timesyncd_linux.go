@@ -0,0 +1,134 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package ntp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	timesyncdBusName    = "org.freedesktop.timesync1"
+	timesyncdObjectPath = "/org/freedesktop/timesync1"
+	timesyncdInterface  = "org.freedesktop.timesync1.Manager"
+
+	// hostSystemBusAddress is the system bus socket of the host, when mounted in the agent container
+	hostSystemBusAddress = "unix:path=/host/var/run/dbus/system_bus_socket"
+)
+
+// timesyncdNTPMessage is the NTPMessage property of systemd-timesyncd, holding the last NTP response it received.
+// The timestamps and durations are in microseconds.
+type timesyncdNTPMessage struct {
+	Leap                 uint32
+	Version              uint32
+	Mode                 uint32
+	Stratum              uint32
+	Precision            int32
+	RootDelay            uint64
+	RootDispersion       uint64
+	Reference            []byte
+	OriginateTimestamp   uint64
+	ReceiveTimestamp     uint64
+	TransmitTimestamp    uint64
+	DestinationTimestamp uint64
+	Ignored              bool
+	PacketCount          uint64
+	Jitter               uint64
+}
+
+// getTimesyncdState reads the state of systemd-timesyncd from its D-Bus properties
+func getTimesyncdState(timeout time.Duration) (*localSyncState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	conn, err := connectSystemBus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var properties map[string]dbus.Variant
+	// do not start timesyncd if it is not running
+	err = conn.Object(timesyncdBusName, timesyncdObjectPath).
+		CallWithContext(ctx, "org.freedesktop.DBus.Properties.GetAll", dbus.FlagNoAutoStart, timesyncdInterface).
+		Store(&properties)
+	if err != nil {
+		return nil, err
+	}
+
+	var serverName string
+	var msg timesyncdNTPMessage
+	if v, ok := properties["ServerName"]; ok {
+		if err = v.Store(&serverName); err != nil {
+			return nil, fmt.Errorf("invalid ServerName property: %w", err)
+		}
+	}
+	if v, ok := properties["NTPMessage"]; ok {
+		if err = v.Store(&msg); err != nil {
+			return nil, fmt.Errorf("invalid NTPMessage property: %w", err)
+		}
+	}
+
+	kernelState, err := getKernelSyncState()
+	if err != nil {
+		log.Debugf("Could not get the synchronization state from the kernel: %s", err)
+	}
+	return timesyncdSyncState(serverName, &msg, kernelState), nil
+}
+
+// connectSystemBus connects to the system bus, falling back to the one of the host in containers
+func connectSystemBus(ctx context.Context) (*dbus.Conn, error) {
+	conn, err := dbus.ConnectSystemBus(dbus.WithContext(ctx))
+	if err == nil {
+		return conn, nil
+	}
+	hostConn, hostErr := dbus.Connect(hostSystemBusAddress, dbus.WithContext(ctx))
+	if hostErr != nil {
+		return nil, errors.Join(err, hostErr)
+	}
+	return hostConn, nil
+}
+
+// timesyncdSyncState returns the synchronization state from the last NTP response received by timesyncd. As
+// timesyncd does not track whether the clock is synchronized, it is read from the kernel, like timedated does.
+func timesyncdSyncState(serverName string, msg *timesyncdNTPMessage, kernelState *localSyncState) *localSyncState {
+	state := &localSyncState{
+		daemon:     "timesyncd",
+		leapStatus: leapUnsynchronized,
+	}
+	// the stratum is only 0 before the first response
+	if msg.Stratum != 0 {
+		origin, receive := int64(msg.OriginateTimestamp), int64(msg.ReceiveTimestamp)
+		transmit, destination := int64(msg.TransmitTimestamp), int64(msg.DestinationTimestamp)
+
+		state.hasSource = true
+		state.source = serverName
+		state.leapStatus = leapStatus(msg.Leap)
+		state.stratum = int(msg.Stratum)
+		state.offset = time.Duration((receive-origin)+(transmit-destination)) * time.Microsecond / 2
+		state.rootDelay = time.Duration(msg.RootDelay) * time.Microsecond
+		state.rootDispersion = time.Duration(msg.RootDispersion) * time.Microsecond
+		state.estimatedError = state.maxError()
+	}
+
+	if kernelState != nil {
+		state.synchronized = kernelState.synchronized
+		if !state.hasSource {
+			state.estimatedError = kernelState.estimatedError
+		}
+	} else {
+		state.synchronized = state.hasSource && state.leapStatus != leapUnsynchronized
+	}
+	return state
+}
@@ -0,0 +1,242 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package dnsprobe implements the dns_probe core check, which tests whether a name resolves, optionally to the
+// expected answers
+package dnsprobe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/net/probe"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+)
+
+// CheckName is the name of the check
+const CheckName = "dns_probe"
+
+const defaultNameserverPort = 53
+
+var supportedRecordTypes = []uint16{dns.TypeA, dns.TypeAAAA, dns.TypeCNAME, dns.TypeMX, dns.TypeNS, dns.TypePTR, dns.TypeTXT}
+
+type instanceConfig struct {
+	Hostname       string   `yaml:"hostname"`
+	Nameserver     string   `yaml:"nameserver"`
+	NameserverPort int      `yaml:"nameserver_port"`
+	RecordType     string   `yaml:"record_type"`
+	Protocol       string   `yaml:"protocol"`
+	ResolvesAs     []string `yaml:"resolves_as"`
+}
+
+type prober struct {
+	hostname   string
+	nameserver string
+	port       int
+	recordType uint16
+	protocol   string
+	resolvesAs []string
+}
+
+// Factory creates a new check factory
+func Factory() optional.Option[func() check.Check] {
+	return probe.Factory(probe.Spec{
+		Name:      CheckName,
+		Status:    "can_resolve",
+		NewProber: func() probe.Prober { return &prober{} },
+	})
+}
+
+func (p *prober) Configure(rawInstance integration.Data) error {
+	var instance instanceConfig
+	if err := yaml.Unmarshal(rawInstance, &instance); err != nil {
+		return fmt.Errorf("invalid instance config: %s", err)
+	}
+	if instance.Hostname == "" {
+		return errors.New("hostname is required")
+	}
+
+	p.recordType = dns.TypeA
+	if instance.RecordType != "" {
+		recordType, ok := dns.StringToType[strings.ToUpper(instance.RecordType)]
+		if !ok || !slices.Contains(supportedRecordTypes, recordType) {
+			return fmt.Errorf("unsupported record type %s", instance.RecordType)
+		}
+		p.recordType = recordType
+	}
+
+	switch instance.Protocol {
+	case "", "udp":
+		p.protocol = "udp"
+	case "tcp":
+		p.protocol = "tcp"
+	default:
+		return fmt.Errorf("unsupported protocol %s, it must be udp or tcp", instance.Protocol)
+	}
+
+	p.port = defaultNameserverPort
+	if instance.NameserverPort != 0 {
+		p.port = instance.NameserverPort
+	}
+
+	p.hostname = instance.Hostname
+	p.nameserver = instance.Nameserver
+	p.resolvesAs = nil
+	for _, answer := range instance.ResolvesAs {
+		p.resolvesAs = append(p.resolvesAs, normalizeAnswer(answer, p.recordType))
+	}
+	slices.Sort(p.resolvesAs)
+	return nil
+}
+
+func (p *prober) Tags() []string {
+	tags := []string{"resolved_hostname:" + p.hostname, "record_type:" + dns.TypeToString[p.recordType]}
+	if p.nameserver != "" {
+		tags = append(tags, "nameserver:"+p.nameserver)
+	}
+	return tags
+}
+
+// Probe queries the configured nameserver, or the nameservers of the host in order until one answers. The
+// timeout of the check is split between the nameservers, so an unresponsive one doesn't use it all. The query time
+// of the nameserver which answered is reported, even when its answer is not the expected one.
+func (p *prober) Probe(ctx context.Context, s *probe.Submitter) error {
+	servers, err := p.nameservers()
+	if err != nil {
+		return err
+	}
+
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(p.hostname), p.recordType)
+
+	var errs []error
+	for i, server := range servers {
+		resp, rtt, err := p.exchange(ctx, msg, server, len(servers)-i)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		s.Gauge("query_time", rtt.Seconds())
+		return p.checkResponse(resp)
+	}
+	return fmt.Errorf("could not resolve %s: %w", p.hostname, errors.Join(errs...))
+}
+
+func (p *prober) nameservers() ([]string, error) {
+	port := strconv.Itoa(p.port)
+	if p.nameserver != "" {
+		return []string{net.JoinHostPort(p.nameserver, port)}, nil
+	}
+
+	hostServers, err := systemNameservers()
+	if err != nil {
+		return nil, err
+	}
+	servers := make([]string, 0, len(hostServers))
+	for _, server := range hostServers {
+		servers = append(servers, net.JoinHostPort(server, port))
+	}
+	return servers, nil
+}
+
+// exchange sends the query to the server, retrying over TCP when the UDP response is truncated. It gets its share
+// of the remaining time, if other servers remain to be queried. It returns the response and the time taken by the
+// server to answer, including the retry.
+func (p *prober) exchange(ctx context.Context, msg *dns.Msg, server string, remainingServers int) (*dns.Msg, time.Duration, error) {
+	if deadline, ok := ctx.Deadline(); ok && remainingServers > 1 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Until(deadline)/time.Duration(remainingServers))
+		defer cancel()
+	}
+
+	client := &dns.Client{Net: p.protocol}
+	resp, rtt, err := client.ExchangeContext(ctx, msg, server)
+	if err == nil && resp.Truncated && p.protocol == "udp" {
+		client.Net = "tcp"
+		var retryRTT time.Duration
+		resp, retryRTT, err = client.ExchangeContext(ctx, msg, server)
+		rtt += retryRTT
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", server, err)
+	}
+	return resp, rtt, nil
+}
+
+func (p *prober) checkResponse(resp *dns.Msg) error {
+	if resp.Rcode != dns.RcodeSuccess {
+		return fmt.Errorf("query for %s failed with %s", p.hostname, dns.RcodeToString[resp.Rcode])
+	}
+
+	var answers []string
+	for _, rr := range resp.Answer {
+		if answer, ok := answerValue(rr, p.recordType); ok {
+			answers = append(answers, normalizeAnswer(answer, p.recordType))
+		}
+	}
+	if len(answers) == 0 {
+		return fmt.Errorf("no %s record found for %s", dns.TypeToString[p.recordType], p.hostname)
+	}
+
+	if len(p.resolvesAs) > 0 {
+		slices.Sort(answers)
+		if !slices.Equal(answers, p.resolvesAs) {
+			return fmt.Errorf("%s resolves as [%s], expected [%s]", p.hostname, strings.Join(answers, ", "), strings.Join(p.resolvesAs, ", "))
+		}
+	}
+	return nil
+}
+
+// answerValue returns the value of a resource record of the queried type. The answer section also holds the
+// CNAME records followed to resolve the name, which are ignored for other record types.
+func answerValue(rr dns.RR, recordType uint16) (string, bool) {
+	if rr.Header().Rrtype != recordType {
+		return "", false
+	}
+	switch rr := rr.(type) {
+	case *dns.A:
+		return rr.A.String(), true
+	case *dns.AAAA:
+		return rr.AAAA.String(), true
+	case *dns.CNAME:
+		return rr.Target, true
+	case *dns.MX:
+		return rr.Mx, true
+	case *dns.NS:
+		return rr.Ns, true
+	case *dns.PTR:
+		return rr.Ptr, true
+	case *dns.TXT:
+		return strings.Join(rr.Txt, ""), true
+	}
+	return "", false
+}
+
+// normalizeAnswer returns the canonical form of an answer, so the expected answers can be compared to the actual
+// ones: addresses are formatted by the standard library, and names are lower case without trailing dot
+func normalizeAnswer(answer string, recordType uint16) string {
+	switch recordType {
+	case dns.TypeA, dns.TypeAAAA:
+		if ip := net.ParseIP(answer); ip != nil {
+			return ip.String()
+		}
+		return answer
+	case dns.TypeTXT:
+		return answer
+	default:
+		return strings.TrimSuffix(strings.ToLower(answer), ".")
+	}
+}
@@ -0,0 +1,29 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !windows
+
+package dnsprobe
+
+import (
+	"fmt"
+
+	"github.com/miekg/dns"
+)
+
+// for testing purpose
+var resolvConfPath = "/etc/resolv.conf"
+
+// systemNameservers returns the addresses of the nameservers of the host, read from resolv.conf
+func systemNameservers() ([]string, error) {
+	conf, err := dns.ClientConfigFromFile(resolvConfPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the nameservers of the host: %w", err)
+	}
+	if len(conf.Servers) == 0 {
+		return nil, fmt.Errorf("no nameserver found in %s", resolvConfPath)
+	}
+	return conf.Servers, nil
+}
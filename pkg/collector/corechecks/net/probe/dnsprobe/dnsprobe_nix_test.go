@@ -0,0 +1,42 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !windows
+
+package dnsprobe
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/net/probe"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+func TestDNSProbeSystemNameservers(t *testing.T) {
+	port := startDNSServer(t, testRecords)
+
+	// the first nameserver does not answer
+	l, err := net.ListenPacket("udp", "127.0.0.2:0")
+	if err != nil {
+		t.Skip("127.0.0.2 is not available")
+	}
+	l.Close()
+
+	resolvConfPath = filepath.Join(t.TempDir(), "resolv.conf")
+	defer func() { resolvConfPath = "/etc/resolv.conf" }()
+	require.NoError(t, os.WriteFile(resolvConfPath, []byte("nameserver 127.0.0.2\nnameserver 127.0.0.1\n"), 0644))
+
+	c, mockSender := probe.ConfigureCheck(t, Factory(), fmt.Sprintf("hostname: www.example.com\nnameserver_port: %d\ntimeout: 1", port))
+	require.NoError(t, c.Run())
+
+	tags := []string{"resolved_hostname:www.example.com", "record_type:A"}
+	mockSender.AssertServiceCheck(t, "dns_probe.can_resolve", servicecheck.ServiceCheckOK, "", tags, "")
+}
@@ -0,0 +1,144 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package dnsprobe
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/net/probe"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+// startDNSServer starts a DNS server on localhost, answering from records, and returns its port
+func startDNSServer(t *testing.T, records map[string][]string) int {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &dns.Server{
+		PacketConn: pc,
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+			resp := new(dns.Msg)
+			resp.SetReply(req)
+			q := req.Question[0]
+			rrs, ok := records[q.Name]
+			if !ok {
+				resp.Rcode = dns.RcodeNameError
+			}
+			for _, rr := range rrs {
+				resp.Answer = append(resp.Answer, dns.RR(mustRR(t, rr)))
+			}
+			_ = w.WriteMsg(resp)
+		}),
+	}
+	started := make(chan struct{})
+	server.NotifyStartedFunc = func() { close(started) }
+	go func() { _ = server.ActivateAndServe() }()
+	<-started
+	t.Cleanup(func() { _ = server.Shutdown() })
+
+	return pc.LocalAddr().(*net.UDPAddr).Port
+}
+
+func mustRR(t *testing.T, s string) dns.RR {
+	rr, err := dns.NewRR(s)
+	require.NoError(t, err)
+	return rr
+}
+
+var testRecords = map[string][]string{
+	"www.example.com.": {
+		"www.example.com. 60 IN CNAME web.example.com.",
+		"web.example.com. 60 IN A 192.0.2.1",
+		"web.example.com. 60 IN A 192.0.2.2",
+	},
+	"example.com.": {"example.com. 60 IN MX 10 Mail.Example.com."},
+}
+
+func TestDNSProbe(t *testing.T) {
+	port := startDNSServer(t, testRecords)
+
+	for _, tc := range []struct {
+		name     string
+		instance string
+		tags     []string
+		status   servicecheck.ServiceCheckStatus
+		message  string
+	}{
+		{
+			name:     "resolves",
+			instance: "hostname: www.example.com",
+			tags:     []string{"resolved_hostname:www.example.com", "record_type:A", "nameserver:127.0.0.1"},
+			status:   servicecheck.ServiceCheckOK,
+		},
+		{
+			name:     "resolves as expected",
+			instance: "hostname: www.example.com\nresolves_as: [192.0.2.2, 192.0.2.1]",
+			tags:     []string{"resolved_hostname:www.example.com", "record_type:A", "nameserver:127.0.0.1"},
+			status:   servicecheck.ServiceCheckOK,
+		},
+		{
+			name:     "resolves as expected name",
+			instance: "hostname: example.com\nrecord_type: mx\nresolves_as: [mail.example.com]",
+			tags:     []string{"resolved_hostname:example.com", "record_type:MX", "nameserver:127.0.0.1"},
+			status:   servicecheck.ServiceCheckOK,
+		},
+		{
+			name:     "unexpected answers",
+			instance: "hostname: www.example.com\nresolves_as: [192.0.2.1]",
+			tags:     []string{"resolved_hostname:www.example.com", "record_type:A", "nameserver:127.0.0.1"},
+			status:   servicecheck.ServiceCheckCritical,
+			message:  "www.example.com resolves as [192.0.2.1, 192.0.2.2], expected [192.0.2.1]",
+		},
+		{
+			name:     "no record of the type",
+			instance: "hostname: www.example.com\nrecord_type: AAAA",
+			tags:     []string{"resolved_hostname:www.example.com", "record_type:AAAA", "nameserver:127.0.0.1"},
+			status:   servicecheck.ServiceCheckCritical,
+			message:  "no AAAA record found for www.example.com",
+		},
+		{
+			name:     "nxdomain",
+			instance: "hostname: missing.example.com",
+			tags:     []string{"resolved_hostname:missing.example.com", "record_type:A", "nameserver:127.0.0.1"},
+			status:   servicecheck.ServiceCheckCritical,
+			message:  "query for missing.example.com failed with NXDOMAIN",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, mockSender := probe.ConfigureCheck(t, Factory(), fmt.Sprintf("%s\nnameserver: 127.0.0.1\nnameserver_port: %d", tc.instance, port))
+			require.NoError(t, c.Run())
+
+			mockSender.AssertServiceCheck(t, "dns_probe.can_resolve", tc.status, "", tc.tags, tc.message)
+			// the nameserver answered, even when the answer is not the expected one
+			mockSender.AssertCalled(t, "Gauge", "dns_probe.query_time", mock.AnythingOfType("float64"), "", tc.tags)
+			if tc.status == servicecheck.ServiceCheckOK {
+				mockSender.AssertMetric(t, "Gauge", "dns_probe.can_resolve", 1, "", tc.tags)
+				mockSender.AssertCalled(t, "Gauge", "dns_probe.response_time", mock.AnythingOfType("float64"), "", tc.tags)
+			} else {
+				mockSender.AssertMetric(t, "Gauge", "dns_probe.can_resolve", 0, "", tc.tags)
+			}
+		})
+	}
+}
+
+func TestDNSProbeConfig(t *testing.T) {
+	for instance, expectedErr := range map[string]string{
+		"record_type: A":                    "hostname is required",
+		"hostname: a\nrecord_type: SOA":     "unsupported record type SOA",
+		"hostname: a\nrecord_type: foo":     "unsupported record type foo",
+		"hostname: a\nprotocol: quic":       "unsupported protocol quic, it must be udp or tcp",
+		"hostname: a\nresolves_as: 1.2.3.4": "invalid instance config: yaml: unmarshal errors:\n  line 2: cannot unmarshal !!str `1.2.3.4` into []string",
+	} {
+		assert.EqualError(t, (&prober{}).Configure([]byte(instance)), expectedErr)
+	}
+}
@@ -0,0 +1,15 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build windows
+
+package dnsprobe
+
+import "errors"
+
+// systemNameservers returns an error, as the nameservers of Windows hosts are not read from a resolv.conf file
+func systemNameservers() ([]string, error) {
+	return nil, errors.New("the nameservers of the host can't be read on Windows, nameserver is required")
+}
@@ -0,0 +1,325 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package httpprobe implements the http_probe core check, which tests whether a URL answers with the expected
+// status and content, and whether its TLS certificate is valid
+package httpprobe
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/net/probe"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+)
+
+// CheckName is the name of the check
+const CheckName = "http_probe"
+
+const (
+	defaultStatusCode   = `(1|2|3)\d\d`
+	defaultMaxRedirects = 10
+	defaultDaysWarning  = 14
+	defaultDaysCritical = 7
+
+	// maxBodySize is the size of the response body read to match its content
+	maxBodySize = 10 * 1024 * 1024
+)
+
+type instanceConfig struct {
+	URL                        string            `yaml:"url"`
+	Method                     string            `yaml:"method"`
+	Headers                    map[string]string `yaml:"headers"`
+	Data                       string            `yaml:"data"`
+	HTTPResponseStatusCode     string            `yaml:"http_response_status_code"`
+	ContentMatch               string            `yaml:"content_match"`
+	ReverseContentMatch        bool              `yaml:"reverse_content_match"`
+	AllowRedirects             *bool             `yaml:"allow_redirects"`
+	MaxRedirects               int               `yaml:"max_redirects"`
+	TLSVerify                  *bool             `yaml:"tls_verify"`
+	TLSCACert                  string            `yaml:"tls_ca_cert"`
+	CheckCertificateExpiration *bool             `yaml:"check_certificate_expiration"`
+	DaysWarning                int               `yaml:"days_warning"`
+	DaysCritical               int               `yaml:"days_critical"`
+}
+
+type prober struct {
+	url                 *url.URL
+	method              string
+	headers             map[string]string
+	data                string
+	statusCode          *regexp.Regexp
+	contentMatch        *regexp.Regexp
+	reverseContentMatch bool
+	allowRedirects      bool
+	maxRedirects        int
+	tlsVerify           bool
+	rootCAs             *x509.CertPool
+	checkCertificate    bool
+	daysWarning         int
+	daysCritical        int
+}
+
+// Factory creates a new check factory
+func Factory() optional.Option[func() check.Check] {
+	return probe.Factory(probe.Spec{
+		Name:      CheckName,
+		Status:    "can_connect",
+		NewProber: func() probe.Prober { return &prober{} },
+	})
+}
+
+func (p *prober) Configure(rawInstance integration.Data) error {
+	var instance instanceConfig
+	if err := yaml.Unmarshal(rawInstance, &instance); err != nil {
+		return fmt.Errorf("invalid instance config: %s", err)
+	}
+
+	if instance.URL == "" {
+		return errors.New("url is required")
+	}
+	u, err := url.Parse(instance.URL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %s", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported url scheme %q, it must be http or https", u.Scheme)
+	}
+	p.url = u
+
+	p.method = http.MethodGet
+	if instance.Method != "" {
+		p.method = strings.ToUpper(instance.Method)
+	}
+	p.headers = instance.Headers
+	p.data = instance.Data
+
+	statusCode := defaultStatusCode
+	if instance.HTTPResponseStatusCode != "" {
+		statusCode = instance.HTTPResponseStatusCode
+	}
+	// the whole status code must match
+	if p.statusCode, err = regexp.Compile(`^(?:` + statusCode + `)$`); err != nil {
+		return fmt.Errorf("invalid http_response_status_code: %s", err)
+	}
+	p.contentMatch = nil
+	if instance.ContentMatch != "" {
+		if p.contentMatch, err = regexp.Compile(instance.ContentMatch); err != nil {
+			return fmt.Errorf("invalid content_match: %s", err)
+		}
+	}
+	p.reverseContentMatch = instance.ReverseContentMatch
+
+	p.allowRedirects = instance.AllowRedirects == nil || *instance.AllowRedirects
+	p.maxRedirects = defaultMaxRedirects
+	if instance.MaxRedirects > 0 {
+		p.maxRedirects = instance.MaxRedirects
+	}
+
+	p.tlsVerify = instance.TLSVerify == nil || *instance.TLSVerify
+	p.rootCAs = nil
+	if instance.TLSCACert != "" {
+		pem, err := os.ReadFile(instance.TLSCACert)
+		if err != nil {
+			return fmt.Errorf("could not read tls_ca_cert: %s", err)
+		}
+		p.rootCAs = x509.NewCertPool()
+		if !p.rootCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificate found in tls_ca_cert %s", instance.TLSCACert)
+		}
+	}
+
+	p.checkCertificate = u.Scheme == "https" && (instance.CheckCertificateExpiration == nil || *instance.CheckCertificateExpiration)
+	p.daysWarning = defaultDaysWarning
+	if instance.DaysWarning > 0 {
+		p.daysWarning = instance.DaysWarning
+	}
+	p.daysCritical = defaultDaysCritical
+	if instance.DaysCritical > 0 {
+		p.daysCritical = instance.DaysCritical
+	}
+	if p.daysCritical > p.daysWarning {
+		return fmt.Errorf("days_critical (%d) must be lower than days_warning (%d)", p.daysCritical, p.daysWarning)
+	}
+	return nil
+}
+
+func (p *prober) Tags() []string {
+	return []string{"url:" + p.url.String()}
+}
+
+// Probe sends the request, following the redirects if allowed, then checks the response
+func (p *prober) Probe(ctx context.Context, s *probe.Submitter) error {
+	state := &requestState{host: p.url.Hostname()}
+	status, body, err := p.do(ctx, state)
+
+	state.timings.submit(s)
+	s.Gauge("redirects", float64(state.redirects))
+	if p.checkCertificate {
+		p.submitCertificate(s, state, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !p.statusCode.MatchString(strconv.Itoa(status)) {
+		return fmt.Errorf("incorrect HTTP return code for url %s: expected %s, got %d", p.url, p.statusCode, status)
+	}
+	if p.contentMatch != nil {
+		matched := p.contentMatch.Match(body)
+		if matched && p.reverseContentMatch {
+			return fmt.Errorf("content %q found in the response", p.contentMatch)
+		}
+		if !matched && !p.reverseContentMatch {
+			return fmt.Errorf("content %q not found in the response", p.contentMatch)
+		}
+	}
+	return nil
+}
+
+// requestState holds what is learned while running a request
+type requestState struct {
+	timings   phaseTimings
+	redirects int
+	// host is the host of the URL being requested, which the certificate of the server must be valid for
+	host string
+	// certificates is the certificate chain presented by the first TLS server of the request
+	certificates []*x509.Certificate
+	// certificateErr is the error of the verification of the certificate chain
+	certificateErr error
+}
+
+func (p *prober) do(ctx context.Context, state *requestState) (int, []byte, error) {
+	var body io.Reader
+	if p.data != "" {
+		body = strings.NewReader(p.data)
+	}
+	req, err := http.NewRequestWithContext(state.timings.trace(ctx), p.method, p.url.String(), body)
+	if err != nil {
+		return 0, nil, err
+	}
+	for name, value := range p.headers {
+		req.Header.Set(name, value)
+		// the Host header is ignored by the client
+		if strings.EqualFold(name, "host") {
+			req.Host = value
+		}
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy:             http.ProxyFromEnvironment,
+			TLSClientConfig:   p.tlsConfig(state),
+			ForceAttemptHTTP2: true,
+			// each run measures the establishment of a new connection
+			DisableKeepAlives: true,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if !p.allowRedirects {
+				return http.ErrUseLastResponse
+			}
+			if len(via) > p.maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", p.maxRedirects)
+			}
+			state.redirects = len(via)
+			state.host = req.URL.Hostname()
+			return nil
+		},
+	}
+	defer client.CloseIdleConnections()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	start := time.Now()
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return 0, nil, fmt.Errorf("could not read the response: %w", err)
+	}
+	state.timings.add(&state.timings.transfer, start)
+	return resp.StatusCode, content, nil
+}
+
+// tlsConfig returns the TLS configuration of the request. The certificate chain is verified by the probe, so it
+// can be reported even when it is invalid. It is verified against the host of the URL rather than the server name
+// sent by the client, which is empty when the host is an IP address.
+func (p *prober) tlsConfig(state *requestState) *tls.Config {
+	return &tls.Config{
+		//nolint:gosec // the certificate chain is verified by VerifyConnection, if tls_verify is enabled
+		InsecureSkipVerify: true,
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			err := p.verifyCertificates(cs, state.host)
+			if state.certificates == nil {
+				state.certificates = cs.PeerCertificates
+				state.certificateErr = err
+			}
+			if p.tlsVerify {
+				return err
+			}
+			return nil
+		},
+	}
+}
+
+// verifyCertificates verifies the certificate chain presented by the server for host, like the default TLS
+// configuration
+func (p *prober) verifyCertificates(cs tls.ConnectionState, host string) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("no certificate presented by the server")
+	}
+	opts := x509.VerifyOptions{
+		DNSName:       host,
+		Intermediates: x509.NewCertPool(),
+		Roots:         p.rootCAs,
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := cs.PeerCertificates[0].Verify(opts)
+	return err
+}
+
+// submitCertificate reports the validity of the certificate of the server, and how long it remains valid
+func (p *prober) submitCertificate(s *probe.Submitter, state *requestState, requestErr error) {
+	if state.certificates == nil {
+		s.ServiceCheck("ssl_cert", servicecheck.ServiceCheckCritical, fmt.Sprintf("could not get the certificate: %s", requestErr))
+		return
+	}
+
+	left := time.Until(state.certificates[0].NotAfter)
+	daysLeft := left.Hours() / 24
+	s.Gauge("ssl.days_left", daysLeft)
+	s.Gauge("ssl.seconds_left", left.Seconds())
+
+	switch {
+	case state.certificateErr != nil:
+		s.ServiceCheck("ssl_cert", servicecheck.ServiceCheckCritical, state.certificateErr.Error())
+	case daysLeft < float64(p.daysCritical):
+		s.ServiceCheck("ssl_cert", servicecheck.ServiceCheckCritical, fmt.Sprintf("the certificate expires in %.1f days, less than %d", daysLeft, p.daysCritical))
+	case daysLeft < float64(p.daysWarning):
+		s.ServiceCheck("ssl_cert", servicecheck.ServiceCheckWarning, fmt.Sprintf("the certificate expires in %.1f days, less than %d", daysLeft, p.daysWarning))
+	default:
+		s.ServiceCheck("ssl_cert", servicecheck.ServiceCheckOK, "")
+	}
+}
@@ -0,0 +1,281 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package httpprobe
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/net/probe"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+func testHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "status: healthy")
+	})
+	mux.HandleFunc("/error", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s", r.Method, r.Header.Get("X-Probe"))
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ok", http.StatusFound)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	return mux
+}
+
+func TestHTTPProbe(t *testing.T) {
+	server := httptest.NewServer(testHandler())
+	defer server.Close()
+
+	for _, tc := range []struct {
+		name      string
+		path      string
+		instance  string
+		status    servicecheck.ServiceCheckStatus
+		message   string
+		redirects float64
+	}{
+		{
+			name:     "ok",
+			path:     "/ok",
+			instance: "content_match: 'status: \\w+'",
+			status:   servicecheck.ServiceCheckOK,
+		},
+		{
+			name:     "expected error status",
+			path:     "/error",
+			instance: "http_response_status_code: '5\\d\\d'",
+			status:   servicecheck.ServiceCheckOK,
+		},
+		{
+			name:    "unexpected status",
+			path:    "/error",
+			status:  servicecheck.ServiceCheckCritical,
+			message: "incorrect HTTP return code for url " + server.URL + "/error: expected ^(?:(1|2|3)\\d\\d)$, got 503",
+		},
+		{
+			name:     "content not found",
+			path:     "/ok",
+			instance: "content_match: unhealthy",
+			status:   servicecheck.ServiceCheckCritical,
+			message:  `content "unhealthy" not found in the response`,
+		},
+		{
+			name:     "reverse content match",
+			path:     "/ok",
+			instance: "content_match: healthy\nreverse_content_match: true",
+			status:   servicecheck.ServiceCheckCritical,
+			message:  `content "healthy" found in the response`,
+		},
+		{
+			name:     "method and headers",
+			path:     "/echo",
+			instance: "method: post\ndata: payload\nheaders:\n  X-Probe: datadog\ncontent_match: ^POST datadog$",
+			status:   servicecheck.ServiceCheckOK,
+		},
+		{
+			name:      "redirect",
+			path:      "/redirect",
+			instance:  "content_match: healthy",
+			status:    servicecheck.ServiceCheckOK,
+			redirects: 1,
+		},
+		{
+			name:     "redirect not followed",
+			path:     "/redirect",
+			instance: "allow_redirects: false\nhttp_response_status_code: '302'",
+			status:   servicecheck.ServiceCheckOK,
+		},
+		{
+			name:      "too many redirects",
+			path:      "/loop",
+			instance:  "max_redirects: 2",
+			status:    servicecheck.ServiceCheckCritical,
+			message:   `Get "/loop": stopped after 2 redirects`,
+			redirects: 2,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, mockSender := probe.ConfigureCheck(t, Factory(), fmt.Sprintf("url: %s%s\n%s", server.URL, tc.path, tc.instance))
+			require.NoError(t, c.Run())
+
+			tags := []string{"url:" + server.URL + tc.path}
+			mockSender.AssertServiceCheck(t, "http_probe.can_connect", tc.status, "", tags, tc.message)
+			mockSender.AssertMetric(t, "Gauge", "http_probe.redirects", tc.redirects, "", tags)
+			mockSender.AssertCalled(t, "Gauge", "http_probe.timings.first_byte", mock.AnythingOfType("float64"), "", tags)
+			mockSender.AssertNotCalled(t, "ServiceCheck", "http_probe.ssl_cert", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// newTLSServer starts a TLS server whose self-signed certificate is valid for the names or addresses of hosts and
+// expires after validity, and returns the path of the certificate
+func newTLSServer(t *testing.T, validity time.Duration, hosts ...string) (*httptest.Server, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "http probe test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(validity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(t.TempDir(), "cert.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644))
+
+	server := httptest.NewUnstartedServer(testHandler())
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server, certFile
+}
+
+func TestHTTPProbeCertificate(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		validity      time.Duration
+		hosts         []string
+		instance      string
+		status        servicecheck.ServiceCheckStatus
+		sslStatus     servicecheck.ServiceCheckStatus
+		sslMessage    string
+		trustedServer bool
+	}{
+		{
+			name:          "valid",
+			validity:      90 * 24 * time.Hour,
+			trustedServer: true,
+			status:        servicecheck.ServiceCheckOK,
+			sslStatus:     servicecheck.ServiceCheckOK,
+		},
+		{
+			name:          "expires soon",
+			validity:      10*24*time.Hour + time.Hour,
+			trustedServer: true,
+			status:        servicecheck.ServiceCheckOK,
+			sslStatus:     servicecheck.ServiceCheckWarning,
+			sslMessage:    "the certificate expires in 10.0 days, less than 14",
+		},
+		{
+			name:          "expires very soon",
+			validity:      10*24*time.Hour + time.Hour,
+			trustedServer: true,
+			instance:      "days_warning: 30\ndays_critical: 20",
+			status:        servicecheck.ServiceCheckOK,
+			sslStatus:     servicecheck.ServiceCheckCritical,
+			sslMessage:    "the certificate expires in 10.0 days, less than 20",
+		},
+		{
+			name:          "host mismatch",
+			validity:      90 * 24 * time.Hour,
+			hosts:         []string{"example.com"},
+			trustedServer: true,
+			status:        servicecheck.ServiceCheckCritical,
+			sslStatus:     servicecheck.ServiceCheckCritical,
+			sslMessage:    "x509: cannot validate certificate for 127.0.0.1 because it doesn't contain any IP SANs",
+		},
+		{
+			name:      "unknown authority",
+			validity:  90 * 24 * time.Hour,
+			status:    servicecheck.ServiceCheckCritical,
+			sslStatus: servicecheck.ServiceCheckCritical,
+		},
+		{
+			name:      "unknown authority without verification",
+			validity:  90 * 24 * time.Hour,
+			instance:  "tls_verify: false",
+			status:    servicecheck.ServiceCheckOK,
+			sslStatus: servicecheck.ServiceCheckCritical,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			hosts := tc.hosts
+			if hosts == nil {
+				hosts = []string{"127.0.0.1"}
+			}
+			server, certFile := newTLSServer(t, tc.validity, hosts...)
+			instance := fmt.Sprintf("url: %s/ok\n%s", server.URL, tc.instance)
+			if tc.trustedServer {
+				instance += "\ntls_ca_cert: " + certFile
+			}
+			c, mockSender := probe.ConfigureCheck(t, Factory(), instance)
+			require.NoError(t, c.Run())
+
+			tags := []string{"url:" + server.URL + "/ok"}
+			mockSender.AssertCalled(t, "ServiceCheck", "http_probe.can_connect", tc.status, "", tags, mock.Anything)
+			mockSender.AssertCalled(t, "Gauge", "http_probe.timings.tls_handshake", mock.AnythingOfType("float64"), "", tags)
+			mockSender.AssertMetricInRange(t, "Gauge", "http_probe.ssl.days_left", tc.validity.Hours()/24-1, tc.validity.Hours()/24, "", tags)
+			if tc.sslMessage != "" {
+				mockSender.AssertServiceCheck(t, "http_probe.ssl_cert", tc.sslStatus, "", tags, tc.sslMessage)
+			} else {
+				mockSender.AssertCalled(t, "ServiceCheck", "http_probe.ssl_cert", tc.sslStatus, "", tags, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestHTTPProbeCertificateUnreachable(t *testing.T) {
+	server, _ := newTLSServer(t, time.Hour, "127.0.0.1")
+	server.Close()
+
+	c, mockSender := probe.ConfigureCheck(t, Factory(), "url: "+server.URL)
+	require.NoError(t, c.Run())
+
+	tags := []string{"url:" + server.URL}
+	mockSender.AssertCalled(t, "ServiceCheck", "http_probe.can_connect", servicecheck.ServiceCheckCritical, "", tags, mock.Anything)
+	mockSender.AssertCalled(t, "ServiceCheck", "http_probe.ssl_cert", servicecheck.ServiceCheckCritical, "", tags, mock.Anything)
+	mockSender.AssertNotCalled(t, "Gauge", "http_probe.ssl.days_left", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHTTPProbeConfig(t *testing.T) {
+	for instance, expectedErr := range map[string]string{
+		"method: GET":                              "url is required",
+		"url: ftp://example.com":                   `unsupported url scheme "ftp", it must be http or https`,
+		"url: http://a\ncontent_match: (":          "invalid content_match: error parsing regexp: missing closing ): `(`",
+		"url: http://a\ndays_critical: 30":         "days_critical (30) must be lower than days_warning (14)",
+		"url: http://a\ntls_ca_cert: /nonexistent": "could not read tls_ca_cert: open /nonexistent: no such file or directory",
+	} {
+		assert.EqualError(t, (&prober{}).Configure([]byte(instance)), expectedErr)
+	}
+}
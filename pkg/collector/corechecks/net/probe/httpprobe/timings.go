@@ -0,0 +1,84 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package httpprobe
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/net/probe"
+)
+
+// phaseTimings are the durations of the phases of a request, summed over its redirects. The trace hooks can be
+// called concurrently, when several addresses are dialed in parallel.
+type phaseTimings struct {
+	mu sync.Mutex
+
+	dns          time.Duration
+	connect      time.Duration
+	tlsHandshake time.Duration
+	firstByte    time.Duration
+	transfer     time.Duration
+
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	wroteRequest time.Time
+}
+
+// trace returns a context tracing the phases of the requests made with it
+func (t *phaseTimings) trace(ctx context.Context) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { t.start(&t.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { t.end(&t.dns, &t.dnsStart) },
+		ConnectStart:         func(string, string) { t.start(&t.connectStart) },
+		ConnectDone:          func(string, string, error) { t.end(&t.connect, &t.connectStart) },
+		TLSHandshakeStart:    func() { t.start(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.end(&t.tlsHandshake, &t.tlsStart) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.start(&t.wroteRequest) },
+		GotFirstResponseByte: func() { t.end(&t.firstByte, &t.wroteRequest) },
+	})
+}
+
+// start records the start of a phase, unless it already started
+func (t *phaseTimings) start(ts *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if ts.IsZero() {
+		*ts = time.Now()
+	}
+}
+
+// end adds the duration of a started phase
+func (t *phaseTimings) end(d *time.Duration, ts *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !ts.IsZero() {
+		*d += time.Since(*ts)
+		*ts = time.Time{}
+	}
+}
+
+// add adds the duration of a phase which started at start
+func (t *phaseTimings) add(d *time.Duration, start time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	*d += time.Since(start)
+}
+
+// submit submits the durations of the phases, which are 0 for the phases the request did not go through
+func (t *phaseTimings) submit(s *probe.Submitter) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s.Gauge("timings.dns", t.dns.Seconds())
+	s.Gauge("timings.connect", t.connect.Seconds())
+	s.Gauge("timings.tls_handshake", t.tlsHandshake.Seconds())
+	s.Gauge("timings.first_byte", t.firstByte.Seconds())
+	s.Gauge("timings.transfer", t.transfer.Seconds())
+}
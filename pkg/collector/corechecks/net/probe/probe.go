@@ -0,0 +1,157 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package probe implements the framework shared by the synthetic probe checks. On each run, a probe check tests
+// its target, such as a TCP port, a URL or a DNS name, then reports whether the probe succeeded and how long it
+// took. As any core check, probe checks can be scheduled from autodiscovery templates.
+package probe
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// Prober runs the probe of a check instance against its target
+type Prober interface {
+	// Configure parses the probe specific options of the instance configuration
+	Configure(rawInstance integration.Data) error
+	// Tags returns the tags identifying the target of the probe, added to all its metrics and service checks
+	Tags() []string
+	// Probe runs the probe against its target. It returns an error when the target can't be reached, or does
+	// not answer as expected.
+	Probe(ctx context.Context, s *Submitter) error
+}
+
+// Spec describes a probe check
+type Spec struct {
+	// Name is the name of the check, also used as the prefix of its metrics and service checks
+	Name string
+	// Status is the name of the metric and service check reporting whether the probe succeeded, without prefix
+	Status string
+	// NewProber returns a new prober, configured by each check instance
+	NewProber func() Prober
+}
+
+type instanceConfig struct {
+	Name    string  `yaml:"name"`
+	Timeout float64 `yaml:"timeout"`
+}
+
+// Check is a synthetic probe check
+type Check struct {
+	core.CheckBase
+	spec    Spec
+	prober  Prober
+	timeout time.Duration
+	tags    []string
+}
+
+// Factory returns the factory of the probe check described by spec
+func Factory(spec Spec) optional.Option[func() check.Check] {
+	return optional.NewOption(func() check.Check {
+		return &Check{
+			CheckBase: core.NewCheckBase(spec.Name),
+			spec:      spec,
+		}
+	})
+}
+
+// Configure parses the configuration of the check instance
+func (c *Check) Configure(senderManager sender.SenderManager, integrationConfigDigest uint64, rawInstance integration.Data, rawInitConfig integration.Data, source string) error {
+	// Must be called before c.CommonConfigure
+	c.BuildID(integrationConfigDigest, rawInstance, rawInitConfig)
+
+	if err := c.CommonConfigure(senderManager, rawInitConfig, rawInstance, source); err != nil {
+		return fmt.Errorf("common configure failed: %s", err)
+	}
+
+	var instance instanceConfig
+	if err := yaml.Unmarshal(rawInstance, &instance); err != nil {
+		return fmt.Errorf("invalid instance config: %s", err)
+	}
+	if instance.Timeout < 0 {
+		return fmt.Errorf("invalid timeout %v, it must be positive", instance.Timeout)
+	}
+	c.timeout = defaultTimeout
+	if instance.Timeout > 0 {
+		c.timeout = time.Duration(instance.Timeout * float64(time.Second))
+	}
+
+	prober := c.spec.NewProber()
+	if err := prober.Configure(rawInstance); err != nil {
+		return err
+	}
+	c.prober = prober
+
+	c.tags = nil
+	if instance.Name != "" {
+		c.tags = append(c.tags, "instance:"+instance.Name)
+	}
+	c.tags = append(c.tags, prober.Tags()...)
+	return nil
+}
+
+// Run runs the probe. A failed probe is reported by the metrics and service check of the check, not as a check
+// error.
+func (c *Check) Run() error {
+	sender, err := c.GetSender()
+	if err != nil {
+		return err
+	}
+	s := &Submitter{sender: sender, prefix: c.spec.Name, tags: c.tags}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err = c.prober.Probe(ctx, s)
+	elapsed := time.Since(start)
+
+	if err != nil {
+		s.Gauge(c.spec.Status, 0)
+		s.ServiceCheck(c.spec.Status, servicecheck.ServiceCheckCritical, err.Error())
+	} else {
+		s.Gauge(c.spec.Status, 1)
+		s.Gauge("response_time", elapsed.Seconds())
+		s.ServiceCheck(c.spec.Status, servicecheck.ServiceCheckOK, "")
+	}
+
+	sender.Commit()
+	return nil
+}
+
+// Submitter submits the metrics and service checks of a probe, prefixed with the check name and tagged with the
+// tags of the check instance
+type Submitter struct {
+	sender sender.Sender
+	prefix string
+	tags   []string
+}
+
+// Gauge submits a gauge, with additional tags
+func (s *Submitter) Gauge(name string, value float64, tags ...string) {
+	s.sender.Gauge(s.prefix+"."+name, value, "", s.withTags(tags))
+}
+
+// ServiceCheck submits a service check, with additional tags
+func (s *Submitter) ServiceCheck(name string, status servicecheck.ServiceCheckStatus, message string, tags ...string) {
+	s.sender.ServiceCheck(s.prefix+"."+name, status, "", s.withTags(tags), message)
+}
+
+func (s *Submitter) withTags(tags []string) []string {
+	return append(append(make([]string, 0, len(s.tags)+len(tags)), s.tags...), tags...)
+}
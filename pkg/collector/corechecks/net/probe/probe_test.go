@@ -0,0 +1,100 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package probe
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+type fakeProber struct {
+	Target string `yaml:"target"`
+	err    error
+	// deadline is the timeout of the context of the last probe
+	deadline time.Duration
+}
+
+func (p *fakeProber) Configure(rawInstance integration.Data) error {
+	if err := yaml.Unmarshal(rawInstance, p); err != nil {
+		return err
+	}
+	if p.Target == "" {
+		return errors.New("target is required")
+	}
+	return nil
+}
+
+func (p *fakeProber) Tags() []string {
+	return []string{"target:" + p.Target}
+}
+
+func (p *fakeProber) Probe(ctx context.Context, s *Submitter) error {
+	deadline, _ := ctx.Deadline()
+	p.deadline = time.Until(deadline).Round(time.Second)
+	s.Gauge("custom", 42, "extra:tag")
+	return p.err
+}
+
+func newTestCheck(t *testing.T, instance string, prober *fakeProber) (*Check, *mocksender.MockSender) {
+	c, mockSender := ConfigureCheck(t, Factory(Spec{
+		Name:      "fake_probe",
+		Status:    "can_connect",
+		NewProber: func() Prober { return prober },
+	}), instance)
+	return c.(*Check), mockSender
+}
+
+func TestProbeSuccess(t *testing.T) {
+	prober := &fakeProber{}
+	c, mockSender := newTestCheck(t, "name: test\ntarget: foo\ntimeout: 3", prober)
+
+	require.NoError(t, c.Run())
+
+	tags := []string{"instance:test", "target:foo"}
+	assert.Equal(t, 3*time.Second, prober.deadline)
+	mockSender.AssertMetric(t, "Gauge", "fake_probe.custom", 42, "", []string{"instance:test", "target:foo", "extra:tag"})
+	mockSender.AssertMetric(t, "Gauge", "fake_probe.can_connect", 1, "", tags)
+	mockSender.AssertCalled(t, "Gauge", "fake_probe.response_time", mock.AnythingOfType("float64"), "", tags)
+	mockSender.AssertServiceCheck(t, "fake_probe.can_connect", servicecheck.ServiceCheckOK, "", tags, "")
+	mockSender.AssertNumberOfCalls(t, "Commit", 1)
+}
+
+func TestProbeFailure(t *testing.T) {
+	prober := &fakeProber{err: errors.New("connection refused")}
+	c, mockSender := newTestCheck(t, "target: foo", prober)
+
+	// a failed probe is not a check error
+	require.NoError(t, c.Run())
+
+	tags := []string{"target:foo"}
+	assert.Equal(t, defaultTimeout, prober.deadline)
+	mockSender.AssertMetric(t, "Gauge", "fake_probe.can_connect", 0, "", tags)
+	mockSender.AssertNotCalled(t, "Gauge", "fake_probe.response_time", mock.Anything, mock.Anything, mock.Anything)
+	mockSender.AssertServiceCheck(t, "fake_probe.can_connect", servicecheck.ServiceCheckCritical, "", tags, "connection refused")
+}
+
+func TestProbeConfigErrors(t *testing.T) {
+	option := Factory(Spec{Name: "fake_probe", Status: "can_connect", NewProber: func() Prober { return &fakeProber{} }})
+	factory, _ := option.Get()
+	senderManager := mocksender.CreateDefaultDemultiplexer()
+
+	err := factory().Configure(senderManager, integration.FakeConfigHash, []byte("timeout: 1"), []byte(""), "test")
+	assert.EqualError(t, err, "target is required")
+
+	err = factory().Configure(senderManager, integration.FakeConfigHash, []byte("target: foo\ntimeout: -1"), []byte(""), "test")
+	assert.EqualError(t, err, "invalid timeout -1, it must be positive")
+}
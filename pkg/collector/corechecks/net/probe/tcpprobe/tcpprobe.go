@@ -0,0 +1,83 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package tcpprobe implements the tcp_probe core check, which tests whether a TCP port accepts connections
+package tcpprobe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/net/probe"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+)
+
+// CheckName is the name of the check
+const CheckName = "tcp_probe"
+
+type instanceConfig struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+}
+
+type prober struct {
+	host string
+	port int
+}
+
+// Factory creates a new check factory
+func Factory() optional.Option[func() check.Check] {
+	return probe.Factory(probe.Spec{
+		Name:      CheckName,
+		Status:    "can_connect",
+		NewProber: func() probe.Prober { return &prober{} },
+	})
+}
+
+func (p *prober) Configure(rawInstance integration.Data) error {
+	var instance instanceConfig
+	if err := yaml.Unmarshal(rawInstance, &instance); err != nil {
+		return fmt.Errorf("invalid instance config: %s", err)
+	}
+	if instance.Host == "" {
+		return errors.New("host is required")
+	}
+	if instance.Port <= 0 || instance.Port > 65535 {
+		return fmt.Errorf("invalid port %d", instance.Port)
+	}
+	p.host = instance.Host
+	p.port = instance.Port
+	return nil
+}
+
+func (p *prober) Tags() []string {
+	return []string{"target_host:" + p.host, "port:" + strconv.Itoa(p.port)}
+}
+
+// Probe connects to the first address the host resolves to. The connection time excludes the resolution.
+func (p *prober) Probe(ctx context.Context, s *probe.Submitter) error {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, p.host)
+	if err != nil {
+		return fmt.Errorf("could not resolve %s: %w", p.host, err)
+	}
+	address := net.JoinHostPort(addrs[0].IP.String(), strconv.Itoa(p.port))
+
+	var dialer net.Dialer
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("could not connect to %s: %w", address, err)
+	}
+	s.Gauge("connect_time", time.Since(start).Seconds())
+	return conn.Close()
+}
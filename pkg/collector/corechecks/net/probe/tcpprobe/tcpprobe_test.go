@@ -0,0 +1,61 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package tcpprobe
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/net/probe"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+func TestTCPProbeOpenPort(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	port := l.Addr().(*net.TCPAddr).Port
+
+	c, mockSender := probe.ConfigureCheck(t, Factory(), fmt.Sprintf("host: 127.0.0.1\nport: %d", port))
+	require.NoError(t, c.Run())
+
+	tags := []string{"target_host:127.0.0.1", "port:" + strconv.Itoa(port)}
+	mockSender.AssertMetric(t, "Gauge", "tcp_probe.can_connect", 1, "", tags)
+	mockSender.AssertCalled(t, "Gauge", "tcp_probe.connect_time", mock.AnythingOfType("float64"), "", tags)
+	mockSender.AssertServiceCheck(t, "tcp_probe.can_connect", servicecheck.ServiceCheckOK, "", tags, "")
+}
+
+func TestTCPProbeClosedPort(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	c, mockSender := probe.ConfigureCheck(t, Factory(), fmt.Sprintf("host: 127.0.0.1\nport: %d", port))
+	require.NoError(t, c.Run())
+
+	tags := []string{"target_host:127.0.0.1", "port:" + strconv.Itoa(port)}
+	mockSender.AssertMetric(t, "Gauge", "tcp_probe.can_connect", 0, "", tags)
+	mockSender.AssertNotCalled(t, "Gauge", "tcp_probe.connect_time", mock.Anything, mock.Anything, mock.Anything)
+	mockSender.AssertCalled(t, "ServiceCheck", "tcp_probe.can_connect", servicecheck.ServiceCheckCritical, "", tags, mock.MatchedBy(func(msg string) bool {
+		return msg != ""
+	}))
+}
+
+func TestTCPProbeConfig(t *testing.T) {
+	for instance, expectedErr := range map[string]string{
+		"port: 80":              "host is required",
+		"host: localhost":       "invalid port 0",
+		"host: a\nport: 100000": "invalid port 100000",
+	} {
+		require.EqualError(t, (&prober{}).Configure([]byte(instance)), expectedErr)
+	}
+}
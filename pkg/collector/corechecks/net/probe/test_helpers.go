@@ -0,0 +1,33 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build test
+
+package probe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+)
+
+// ConfigureCheck creates a check from factory, configures it with instance, and returns it with a mock sender
+// accepting all its metrics and service checks
+func ConfigureCheck(t *testing.T, factory optional.Option[func() check.Check], instance string) (check.Check, *mocksender.MockSender) {
+	newCheck, ok := factory.Get()
+	require.True(t, ok)
+	c := newCheck()
+
+	senderManager := mocksender.CreateDefaultDemultiplexer()
+	require.NoError(t, c.Configure(senderManager, integration.FakeConfigHash, []byte(instance), []byte(""), "test"))
+	mockSender := mocksender.NewMockSenderWithSenderManager(c.ID(), senderManager)
+	mockSender.SetupAcceptAll()
+	return c, mockSender
+}
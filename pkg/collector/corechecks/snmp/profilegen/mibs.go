@@ -0,0 +1,193 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package profilegen
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/networkdevice/profile/profiledefinition"
+)
+
+const (
+	sysObjectIDOID = "1.3.6.1.2.1.1.2.0"
+	enterprisesOID = "1.3.6.1.4.1"
+)
+
+// baseProfile is a standard profile that is extended by the generated profile
+// when the device answers on the OID subtree it covers.
+type baseProfile struct {
+	name string
+	mib  string
+	// prefixes are the OID subtrees covered by the profile, any of them being
+	// present in the walk is enough to extend it.
+	prefixes []string
+}
+
+// baseProfiles are checked in order. `_base.yaml` is always extended and
+// hence not listed here.
+var baseProfiles = []baseProfile{
+	{name: "_generic-if.yaml", mib: "IF-MIB", prefixes: []string{"1.3.6.1.2.1.2.2.1", "1.3.6.1.2.1.31.1.1.1"}},
+	{name: "_generic-ip.yaml", mib: "IP-MIB", prefixes: []string{"1.3.6.1.2.1.4.31.1.1"}},
+	{name: "_generic-tcp.yaml", mib: "TCP-MIB", prefixes: []string{"1.3.6.1.2.1.6"}},
+	{name: "_generic-udp.yaml", mib: "UDP-MIB", prefixes: []string{"1.3.6.1.2.1.7"}},
+}
+
+// knownTable describes a MIB table whose columns are turned into metrics when
+// present in the walk.
+type knownTable struct {
+	mib        string
+	table      profiledefinition.SymbolConfig
+	symbols    []profiledefinition.SymbolConfig
+	metricTags []profiledefinition.MetricTagConfig
+	metricType profiledefinition.ProfileMetricType
+}
+
+// entryOID returns the OID of the table entry, under which every column lives.
+func (t knownTable) entryOID() string {
+	return t.table.OID + ".1"
+}
+
+// knownScalar describes a scalar OID that is turned into a metric when
+// present in the walk.
+type knownScalar struct {
+	mib    string
+	symbol profiledefinition.SymbolConfig
+}
+
+func symbol(oid, name string) profiledefinition.SymbolConfig {
+	return profiledefinition.SymbolConfig{OID: oid, Name: name}
+}
+
+func columnTag(tag, oid, name string) profiledefinition.MetricTagConfig {
+	return profiledefinition.MetricTagConfig{Tag: tag, Symbol: profiledefinition.SymbolConfigCompat(symbol(oid, name))}
+}
+
+func indexTag(tag string, index uint) profiledefinition.MetricTagConfig {
+	return profiledefinition.MetricTagConfig{Tag: tag, Index: index}
+}
+
+// knownTables are the tables the generator knows how to turn into metrics.
+// Tables already covered by the extended base profiles are not listed.
+var knownTables = []knownTable{
+	{
+		mib:   "HOST-RESOURCES-MIB",
+		table: symbol("1.3.6.1.2.1.25.2.3", "hrStorageTable"),
+		symbols: []profiledefinition.SymbolConfig{
+			symbol("1.3.6.1.2.1.25.2.3.1.4", "hrStorageAllocationUnits"),
+			symbol("1.3.6.1.2.1.25.2.3.1.5", "hrStorageSize"),
+			symbol("1.3.6.1.2.1.25.2.3.1.6", "hrStorageUsed"),
+			symbol("1.3.6.1.2.1.25.2.3.1.7", "hrStorageAllocationFailures"),
+		},
+		metricTags: []profiledefinition.MetricTagConfig{
+			columnTag("storagedesc", "1.3.6.1.2.1.25.2.3.1.3", "hrStorageDescr"),
+		},
+	},
+	{
+		mib:   "HOST-RESOURCES-MIB",
+		table: symbol("1.3.6.1.2.1.25.3.3", "hrProcessorTable"),
+		symbols: []profiledefinition.SymbolConfig{
+			symbol("1.3.6.1.2.1.25.3.3.1.2", "hrProcessorLoad"),
+		},
+		metricTags: []profiledefinition.MetricTagConfig{
+			indexTag("processorid", 1),
+		},
+	},
+	{
+		mib:   "ENTITY-SENSOR-MIB",
+		table: symbol("1.3.6.1.2.1.99.1.1", "entPhySensorTable"),
+		symbols: []profiledefinition.SymbolConfig{
+			symbol("1.3.6.1.2.1.99.1.1.1.4", "entPhySensorValue"),
+		},
+		metricTags: []profiledefinition.MetricTagConfig{
+			columnTag("sensor_type", "1.3.6.1.2.1.99.1.1.1.1", "entPhySensorType"),
+			indexTag("sensor_id", 1),
+		},
+	},
+	{
+		mib:   "CISCO-PROCESS-MIB",
+		table: symbol("1.3.6.1.4.1.9.9.109.1.1.1", "cpmCPUTotalTable"),
+		symbols: []profiledefinition.SymbolConfig{
+			symbol("1.3.6.1.4.1.9.9.109.1.1.1.1.7", "cpmCPUTotal1minRev"),
+			symbol("1.3.6.1.4.1.9.9.109.1.1.1.1.12", "cpmCPUMemoryUsed"),
+			symbol("1.3.6.1.4.1.9.9.109.1.1.1.1.13", "cpmCPUMemoryFree"),
+		},
+		metricTags: []profiledefinition.MetricTagConfig{
+			indexTag("cpu", 1),
+		},
+	},
+	{
+		mib:   "CISCO-MEMORY-POOL-MIB",
+		table: symbol("1.3.6.1.4.1.9.9.48.1.1", "ciscoMemoryPoolTable"),
+		symbols: []profiledefinition.SymbolConfig{
+			symbol("1.3.6.1.4.1.9.9.48.1.1.1.5", "ciscoMemoryPoolUsed"),
+			symbol("1.3.6.1.4.1.9.9.48.1.1.1.6", "ciscoMemoryPoolFree"),
+		},
+		metricTags: []profiledefinition.MetricTagConfig{
+			columnTag("mem_pool_name", "1.3.6.1.4.1.9.9.48.1.1.1.2", "ciscoMemoryPoolName"),
+		},
+	},
+	{
+		mib:   "FORTINET-FORTIGATE-MIB",
+		table: symbol("1.3.6.1.4.1.12356.101.13.2.1", "fgHaStatsTable"),
+		symbols: []profiledefinition.SymbolConfig{
+			symbol("1.3.6.1.4.1.12356.101.13.2.1.1.3", "fgHaStatsCpuUsage"),
+			symbol("1.3.6.1.4.1.12356.101.13.2.1.1.4", "fgHaStatsMemUsage"),
+		},
+		metricTags: []profiledefinition.MetricTagConfig{
+			columnTag("member_serial", "1.3.6.1.4.1.12356.101.13.2.1.1.2", "fgHaStatsSerial"),
+		},
+	},
+}
+
+// knownScalars are the scalars the generator knows how to turn into metrics.
+var knownScalars = []knownScalar{
+	{mib: "HOST-RESOURCES-MIB", symbol: symbol("1.3.6.1.2.1.25.1.5.0", "hrSystemNumUsers")},
+	{mib: "HOST-RESOURCES-MIB", symbol: symbol("1.3.6.1.2.1.25.1.6.0", "hrSystemProcesses")},
+	{mib: "UCD-SNMP-MIB", symbol: symbol("1.3.6.1.4.1.2021.4.5.0", "memTotalReal")},
+	{mib: "UCD-SNMP-MIB", symbol: symbol("1.3.6.1.4.1.2021.4.6.0", "memAvailReal")},
+	{mib: "UCD-SNMP-MIB", symbol: symbol("1.3.6.1.4.1.2021.11.11.0", "ssCpuIdle")},
+	{mib: "FORTINET-FORTIGATE-MIB", symbol: symbol("1.3.6.1.4.1.12356.101.4.1.3.0", "fgSysCpuUsage")},
+	{mib: "FORTINET-FORTIGATE-MIB", symbol: symbol("1.3.6.1.4.1.12356.101.4.1.4.0", "fgSysMemUsage")},
+	{mib: "FORTINET-FORTIGATE-MIB", symbol: symbol("1.3.6.1.4.1.12356.101.4.1.8.0", "fgSysSesCount")},
+}
+
+// entPhysicalEntryOID is the ENTITY-MIB entPhysicalEntry, used to fill the
+// device metadata from the first physical entity found in the walk.
+const entPhysicalEntryOID = "1.3.6.1.2.1.47.1.1.1.1"
+
+// entityMetadataFields maps device metadata fields to entPhysicalTable columns.
+var entityMetadataFields = []struct {
+	field  string
+	column profiledefinition.SymbolConfig
+}{
+	{field: "version", column: symbol(entPhysicalEntryOID+".10", "entPhysicalSoftwareRev")},
+	{field: "serial_number", column: symbol(entPhysicalEntryOID+".11", "entPhysicalSerialNum")},
+	{field: "model", column: symbol(entPhysicalEntryOID+".13", "entPhysicalModelName")},
+}
+
+// enterpriseVendors maps IANA private enterprise numbers to vendor names.
+var enterpriseVendors = map[string]string{
+	"9":     "cisco",
+	"11":    "hp",
+	"232":   "hp",
+	"311":   "microsoft",
+	"674":   "dell",
+	"789":   "netapp",
+	"1588":  "brocade",
+	"2011":  "huawei",
+	"2021":  "net-snmp",
+	"2620":  "checkpoint",
+	"2636":  "juniper",
+	"3375":  "f5",
+	"6876":  "vmware",
+	"8072":  "net-snmp",
+	"12325": "pfsense",
+	"12356": "fortinet",
+	"14179": "cisco",
+	"14823": "aruba",
+	"25461": "paloaltonetworks",
+	"29671": "meraki",
+	"30065": "arista",
+	"318":   "apc",
+}
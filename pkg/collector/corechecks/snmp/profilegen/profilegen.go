@@ -0,0 +1,342 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package profilegen generates SNMP profiles from the result of a device walk.
+//
+// The generated profile extends the standard base profiles matching the MIBs
+// the device answers on, adds metrics for the other known MIB tables and
+// scalars, and matches the device sysObjectID. It is meant as a starting
+// point to be reviewed, not as a replacement for a curated profile.
+package profilegen
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/configvalidation"
+	"github.com/DataDog/datadog-agent/pkg/networkdevice/profile/profiledefinition"
+	"github.com/DataDog/datadog-agent/pkg/snmp/gosnmplib"
+)
+
+// unmatchedSubtreeDepth is the number of arcs after `enterprises` used to
+// group vendor OIDs that aren't covered by any known MIB.
+const unmatchedSubtreeDepth = 3
+
+// Options holds the settings used to generate a profile.
+type Options struct {
+	// Name is the profile name, derived from the vendor when empty.
+	Name string
+	// Description is the profile description, a default one is used when empty.
+	Description string
+	// SysObjectIDWildcard matches the whole product family of the device
+	// (the sysObjectID with its last arc replaced by `*`) instead of the exact sysObjectID.
+	SysObjectIDWildcard bool
+}
+
+// Result holds a generated profile and what was learnt from the walk.
+type Result struct {
+	Profile *profiledefinition.ProfileDefinition
+	// MatchedMIBs lists the MIBs found in the walk, sorted.
+	MatchedMIBs []string
+	// UnmatchedSubtrees lists the vendor OID subtrees present in the walk but
+	// not covered by the generated profile, sorted. They are good candidates
+	// for manual additions.
+	UnmatchedSubtrees []string
+}
+
+// Generate builds a profile from the PDUs returned by a walk of a device.
+func Generate(pdus []gosnmp.SnmpPDU, opts Options) (*Result, error) {
+	walk := newWalkIndex(pdus)
+
+	sysObjectID, err := walk.sysObjectID()
+	if err != nil {
+		return nil, err
+	}
+	vendor := enterpriseVendors[enterpriseNumber(sysObjectID)]
+
+	profile := profiledefinition.NewProfileDefinition()
+	profile.Name = opts.Name
+	if profile.Name == "" {
+		profile.Name = generatedName(vendor)
+	}
+	profile.Description = opts.Description
+	if profile.Description == "" {
+		profile.Description = fmt.Sprintf("Generated from a walk of a device with sysObjectID %s", sysObjectID)
+	}
+	profile.SysObjectIds = profiledefinition.StringArray{sysObjectIDPattern(sysObjectID, opts.SysObjectIDWildcard)}
+
+	mibs := map[string]struct{}{}
+	var covered []string
+
+	profile.Extends = []string{"_base.yaml"}
+	for _, base := range baseProfiles {
+		for _, prefix := range base.prefixes {
+			if walk.hasSubtree(prefix) {
+				profile.Extends = append(profile.Extends, base.name)
+				mibs[base.mib] = struct{}{}
+				break
+			}
+		}
+	}
+
+	for _, table := range knownTables {
+		metric, ok := tableMetric(walk, table)
+		if !ok {
+			continue
+		}
+		profile.Metrics = append(profile.Metrics, metric)
+		mibs[table.mib] = struct{}{}
+		covered = append(covered, table.table.OID)
+	}
+
+	for _, scalar := range knownScalars {
+		if !walk.has(scalar.symbol.OID) {
+			continue
+		}
+		profile.Metrics = append(profile.Metrics, profiledefinition.MetricsConfig{
+			MIB:    scalar.mib,
+			Symbol: scalar.symbol,
+		})
+		mibs[scalar.mib] = struct{}{}
+		covered = append(covered, scalar.symbol.OID)
+	}
+
+	device := profiledefinition.MetadataResourceConfig{
+		Fields: map[string]profiledefinition.MetadataField{},
+	}
+	if vendor != "" {
+		device.Fields["vendor"] = profiledefinition.MetadataField{Value: vendor}
+	}
+	for _, field := range entityMetadataFields {
+		oid, ok := walk.firstNonEmpty(field.column.OID)
+		if !ok {
+			continue
+		}
+		device.Fields[field.field] = profiledefinition.MetadataField{
+			Symbol: profiledefinition.SymbolConfig{OID: oid, Name: field.column.Name},
+		}
+		mibs["ENTITY-MIB"] = struct{}{}
+	}
+	if len(device.Fields) > 0 {
+		profile.Metadata[profiledefinition.MetadataDeviceResource] = device
+	}
+
+	result := &Result{
+		Profile:           profile,
+		UnmatchedSubtrees: walk.unmatchedEnterpriseSubtrees(covered),
+	}
+	for mib := range mibs {
+		result.MatchedMIBs = append(result.MatchedMIBs, mib)
+	}
+	sort.Strings(result.MatchedMIBs)
+
+	if errs := Validate(profile); len(errs) > 0 {
+		return nil, fmt.Errorf("generated profile is invalid: %s", strings.Join(errs, "; "))
+	}
+	return result, nil
+}
+
+// Validate checks a profile the same way the SNMP check does when loading it
+// from disk, and returns the validation errors.
+// The profile itself isn't modified.
+func Validate(profile *profiledefinition.ProfileDefinition) []string {
+	// round trip through yaml to validate exactly what will be written to disk,
+	// this also keeps the enrichment done by the validation off the given profile
+	content, err := yaml.Marshal(profile)
+	if err != nil {
+		return []string{fmt.Sprintf("cannot marshal profile: %s", err)}
+	}
+	parsed := profiledefinition.NewProfileDefinition()
+	if err := yaml.Unmarshal(content, parsed); err != nil {
+		return []string{fmt.Sprintf("cannot unmarshal profile: %s", err)}
+	}
+	if parsed.Name == "" {
+		return []string{"profile name missing"}
+	}
+	profiledefinition.NormalizeMetrics(parsed.Metrics)
+	var errs []string
+	errs = append(errs, configvalidation.ValidateEnrichMetadata(parsed.Metadata)...)
+	errs = append(errs, configvalidation.ValidateEnrichMetrics(parsed.Metrics)...)
+	errs = append(errs, configvalidation.ValidateEnrichMetricTags(parsed.MetricTags)...)
+	return errs
+}
+
+// Marshal returns the yaml representation of a profile, as found in profile files.
+func Marshal(profile *profiledefinition.ProfileDefinition) ([]byte, error) {
+	return yaml.Marshal(profile)
+}
+
+// tableMetric returns a metric config for the columns of the table that are
+// present in the walk. ok is false if the table has no such column.
+func tableMetric(walk *walkIndex, table knownTable) (profiledefinition.MetricsConfig, bool) {
+	if !walk.hasSubtree(table.entryOID()) {
+		return profiledefinition.MetricsConfig{}, false
+	}
+	metric := profiledefinition.MetricsConfig{
+		MIB:        table.mib,
+		Table:      table.table,
+		MetricType: table.metricType,
+	}
+	for _, sym := range table.symbols {
+		if walk.hasSubtree(sym.OID) {
+			metric.Symbols = append(metric.Symbols, sym)
+		}
+	}
+	if len(metric.Symbols) == 0 {
+		return profiledefinition.MetricsConfig{}, false
+	}
+	for _, tag := range table.metricTags {
+		if tag.Symbol.OID != "" && !walk.hasSubtree(tag.Symbol.OID) {
+			continue
+		}
+		metric.MetricTags = append(metric.MetricTags, tag)
+	}
+	if len(metric.MetricTags) == 0 {
+		// without a discriminating tag only one row would be submitted
+		metric.MetricTags = append(metric.MetricTags, indexTag("index", 1))
+	}
+	return metric, true
+}
+
+// sysObjectIDPattern returns the sysObjectID pattern matching the device.
+func sysObjectIDPattern(sysObjectID string, wildcard bool) string {
+	if !wildcard || !strings.HasPrefix(sysObjectID, enterprisesOID+".") {
+		return sysObjectID
+	}
+	// keep at least the enterprise number and one product arc
+	arcs := strings.Split(strings.TrimPrefix(sysObjectID, enterprisesOID+"."), ".")
+	if len(arcs) < 3 {
+		return sysObjectID
+	}
+	return enterprisesOID + "." + strings.Join(arcs[:len(arcs)-1], ".") + ".*"
+}
+
+// enterpriseNumber returns the IANA private enterprise number of an OID, if any.
+func enterpriseNumber(oid string) string {
+	if !strings.HasPrefix(oid, enterprisesOID+".") {
+		return ""
+	}
+	return strings.SplitN(strings.TrimPrefix(oid, enterprisesOID+"."), ".", 2)[0]
+}
+
+func generatedName(vendor string) string {
+	if vendor == "" {
+		return "generated-device"
+	}
+	return "generated-" + vendor
+}
+
+// walkIndex gives fast prefix lookups over the OIDs of a walk.
+type walkIndex struct {
+	oids   []string
+	values map[string]gosnmp.SnmpPDU
+}
+
+func newWalkIndex(pdus []gosnmp.SnmpPDU) *walkIndex {
+	idx := &walkIndex{values: make(map[string]gosnmp.SnmpPDU, len(pdus))}
+	for _, pdu := range pdus {
+		oid := strings.TrimLeft(pdu.Name, ".")
+		if _, ok := idx.values[oid]; ok {
+			continue
+		}
+		idx.values[oid] = pdu
+		idx.oids = append(idx.oids, oid)
+	}
+	// OIDs sharing a string prefix are contiguous once sorted
+	sort.Strings(idx.oids)
+	return idx
+}
+
+func (w *walkIndex) has(oid string) bool {
+	_, ok := w.values[oid]
+	return ok
+}
+
+// subtree returns the OIDs of the walk strictly under the given OID.
+func (w *walkIndex) subtree(oid string) []string {
+	prefix := oid + "."
+	start := sort.SearchStrings(w.oids, prefix)
+	end := start
+	for end < len(w.oids) && strings.HasPrefix(w.oids[end], prefix) {
+		end++
+	}
+	return w.oids[start:end]
+}
+
+func (w *walkIndex) hasSubtree(oid string) bool {
+	return len(w.subtree(oid)) > 0
+}
+
+// firstNonEmpty returns the first instance of a column holding a non-empty value.
+func (w *walkIndex) firstNonEmpty(column string) (string, bool) {
+	for _, oid := range w.subtree(column) {
+		value, err := gosnmplib.GetValueFromPDU(w.values[oid])
+		if err != nil {
+			continue
+		}
+		str, err := gosnmplib.StandardTypeToString(value)
+		if err != nil || strings.TrimSpace(str) == "" {
+			continue
+		}
+		return oid, true
+	}
+	return "", false
+}
+
+func (w *walkIndex) sysObjectID() (string, error) {
+	pdu, ok := w.values[sysObjectIDOID]
+	if !ok {
+		return "", errors.New("sysObjectID (" + sysObjectIDOID + ") not found in walk")
+	}
+	var sysObjectID string
+	switch value := pdu.Value.(type) {
+	case string:
+		sysObjectID = value
+	case []byte:
+		sysObjectID = string(value)
+	}
+	sysObjectID = strings.TrimLeft(sysObjectID, ".")
+	if _, err := gosnmplib.OIDToInts(sysObjectID); err != nil || sysObjectID == "" {
+		return "", fmt.Errorf("invalid sysObjectID %v", pdu.Value)
+	}
+	return sysObjectID, nil
+}
+
+// unmatchedEnterpriseSubtrees returns the vendor subtrees that have no OID covered.
+func (w *walkIndex) unmatchedEnterpriseSubtrees(covered []string) []string {
+	seen := map[string]struct{}{}
+	var subtrees []string
+	for _, oid := range w.subtree(enterprisesOID) {
+		if isCovered(oid, covered) {
+			continue
+		}
+		arcs := strings.Split(strings.TrimPrefix(oid, enterprisesOID+"."), ".")
+		if len(arcs) > unmatchedSubtreeDepth {
+			arcs = arcs[:unmatchedSubtreeDepth]
+		}
+		subtree := enterprisesOID + "." + strings.Join(arcs, ".")
+		if _, ok := seen[subtree]; ok {
+			continue
+		}
+		seen[subtree] = struct{}{}
+		subtrees = append(subtrees, subtree)
+	}
+	sort.Strings(subtrees)
+	return subtrees
+}
+
+func isCovered(oid string, covered []string) bool {
+	for _, prefix := range covered {
+		if oid == prefix || strings.HasPrefix(oid, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
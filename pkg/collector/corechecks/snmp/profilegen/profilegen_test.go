@@ -0,0 +1,152 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package profilegen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/pkg/networkdevice/profile/profiledefinition"
+	"github.com/DataDog/datadog-agent/pkg/snmp/gosnmplib"
+)
+
+// snmpwalk fixtures shared with the NDM end to end tests
+var fixturesDir = filepath.Join("..", "..", "..", "..", "..", "test", "new-e2e", "tests", "ndm", "snmp", "compose", "data")
+
+func loadFixture(t *testing.T, name string) []gosnmp.SnmpPDU {
+	t.Helper()
+	f, err := os.Open(filepath.Join(fixturesDir, name))
+	require.NoError(t, err)
+	defer f.Close()
+	pdus, err := gosnmplib.ParseSnmprec(f)
+	require.NoError(t, err)
+	return pdus
+}
+
+func metricsByName(profile *profiledefinition.ProfileDefinition) map[string]profiledefinition.MetricsConfig {
+	metrics := map[string]profiledefinition.MetricsConfig{}
+	for _, metric := range profile.Metrics {
+		if metric.IsColumn() {
+			metrics[metric.Table.Name] = metric
+		} else {
+			metrics[metric.Symbol.Name] = metric
+		}
+	}
+	return metrics
+}
+
+func TestGenerateFortinet(t *testing.T) {
+	result, err := Generate(loadFixture(t, "fortinet-fortigate.snmprec"), Options{})
+	require.NoError(t, err)
+	profile := result.Profile
+
+	assert.Equal(t, "generated-fortinet", profile.Name)
+	assert.Equal(t, profiledefinition.StringArray{"1.3.6.1.4.1.12356.101.1.1"}, profile.SysObjectIds)
+	assert.Equal(t, []string{"_base.yaml"}, profile.Extends)
+
+	metrics := metricsByName(profile)
+	assert.Contains(t, metrics, "fgSysCpuUsage")
+	assert.Contains(t, metrics, "fgSysMemUsage")
+	assert.Equal(t, "FORTINET-FORTIGATE-MIB", metrics["fgSysCpuUsage"].MIB)
+
+	device := profile.Metadata["device"]
+	assert.Equal(t, "fortinet", device.Fields["vendor"].Value)
+	assert.Equal(t, profiledefinition.SymbolConfig{OID: "1.3.6.1.2.1.47.1.1.1.1.11.1", Name: "entPhysicalSerialNum"}, device.Fields["serial_number"].Symbol)
+	assert.Equal(t, profiledefinition.SymbolConfig{OID: "1.3.6.1.2.1.47.1.1.1.1.13.1", Name: "entPhysicalModelName"}, device.Fields["model"].Symbol)
+
+	assert.Contains(t, result.MatchedMIBs, "ENTITY-MIB")
+	assert.Contains(t, result.MatchedMIBs, "FORTINET-FORTIGATE-MIB")
+	assert.Contains(t, result.UnmatchedSubtrees, "1.3.6.1.4.1.12356.101.3")
+}
+
+func TestGenerateCiscoCatalyst(t *testing.T) {
+	result, err := Generate(loadFixture(t, "cisco-catalyst.snmprec"), Options{Name: "my-catalyst", SysObjectIDWildcard: true})
+	require.NoError(t, err)
+	profile := result.Profile
+
+	assert.Equal(t, "my-catalyst", profile.Name)
+	assert.Equal(t, profiledefinition.StringArray{"1.3.6.1.4.1.9.1.*"}, profile.SysObjectIds)
+	assert.Equal(t, []string{"_base.yaml", "_generic-if.yaml"}, profile.Extends)
+	assert.Equal(t, "cisco", profile.Metadata["device"].Fields["vendor"].Value)
+	assert.Contains(t, result.MatchedMIBs, "IF-MIB")
+	assert.Contains(t, result.UnmatchedSubtrees, "1.3.6.1.4.1.9.9.91")
+}
+
+func TestGenerateHostResources(t *testing.T) {
+	pdus := append([]gosnmp.SnmpPDU{
+		{Name: ".1.3.6.1.2.1.1.2.0", Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.4.1.8072.3.2.10"},
+	}, loadFixture(t, "generic_host.snmprec")...)
+	result, err := Generate(pdus, Options{})
+	require.NoError(t, err)
+	profile := result.Profile
+
+	assert.Equal(t, "generated-net-snmp", profile.Name)
+	assert.Equal(t, []string{"HOST-RESOURCES-MIB"}, result.MatchedMIBs)
+
+	metrics := metricsByName(profile)
+	require.Contains(t, metrics, "hrStorageTable")
+	storage := metrics["hrStorageTable"]
+	assert.Len(t, storage.Symbols, 4)
+	assert.Equal(t, "storagedesc", storage.MetricTags[0].Tag)
+	require.Contains(t, metrics, "hrProcessorTable")
+	assert.Equal(t, uint(1), metrics["hrProcessorTable"].MetricTags[0].Index)
+	assert.Contains(t, metrics, "hrSystemProcesses")
+	assert.Contains(t, metrics, "hrSystemNumUsers")
+}
+
+func TestGenerateOutputRoundTrip(t *testing.T) {
+	result, err := Generate(loadFixture(t, "public.snmprec"), Options{})
+	require.NoError(t, err)
+
+	content, err := Marshal(result.Profile)
+	require.NoError(t, err)
+
+	parsed := profiledefinition.NewProfileDefinition()
+	require.NoError(t, yaml.Unmarshal(content, parsed))
+	assert.Equal(t, result.Profile.Name, parsed.Name)
+	assert.Equal(t, result.Profile.Extends, parsed.Extends)
+	assert.Len(t, parsed.Metrics, len(result.Profile.Metrics))
+	assert.Empty(t, Validate(parsed))
+}
+
+func TestGenerateMissingSysObjectID(t *testing.T) {
+	_, err := Generate(loadFixture(t, "generic_host.snmprec"), Options{})
+	assert.ErrorContains(t, err, "sysObjectID")
+}
+
+func TestValidate(t *testing.T) {
+	profile := profiledefinition.NewProfileDefinition()
+	profile.Name = "invalid"
+	profile.Metrics = []profiledefinition.MetricsConfig{
+		{Table: profiledefinition.SymbolConfig{OID: "1.2.3", Name: "aTable"}, Symbols: []profiledefinition.SymbolConfig{{OID: "1.2.3.1.1"}}},
+	}
+	errs := Validate(profile)
+	assert.Len(t, errs, 2)
+	// the given profile isn't enriched by the validation
+	assert.Empty(t, profile.Metrics[0].MetricTags)
+}
+
+func TestSysObjectIDPattern(t *testing.T) {
+	for _, tc := range []struct {
+		sysObjectID string
+		wildcard    bool
+		expected    string
+	}{
+		{"1.3.6.1.4.1.9.1.241", false, "1.3.6.1.4.1.9.1.241"},
+		{"1.3.6.1.4.1.9.1.241", true, "1.3.6.1.4.1.9.1.*"},
+		{"1.3.6.1.4.1.8072.3.2.10", true, "1.3.6.1.4.1.8072.3.2.*"},
+		{"1.3.6.1.4.1.9.1", true, "1.3.6.1.4.1.9.1"},
+		{"1.3.6.1.2.1.1", true, "1.3.6.1.2.1.1"},
+	} {
+		assert.Equal(t, tc.expected, sysObjectIDPattern(tc.sysObjectID, tc.wildcard))
+	}
+}
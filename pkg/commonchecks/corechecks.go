@@ -29,6 +29,9 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/embed/process"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/net/network"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/net/ntp"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/net/probe/dnsprobe"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/net/probe/httpprobe"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/net/probe/tcpprobe"
 	ciscosdwan "github.com/DataDog/datadog-agent/pkg/collector/corechecks/network-devices/cisco-sdwan"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/networkpath"
 	nvidia "github.com/DataDog/datadog-agent/pkg/collector/corechecks/nvidia/jetson"
@@ -62,6 +65,9 @@ func RegisterChecks(store workloadmeta.Component, cfg config.Component, telemetr
 	corecheckLoader.RegisterCheck(ntp.CheckName, ntp.Factory())
 	corecheckLoader.RegisterCheck(snmp.CheckName, snmp.Factory())
 	corecheckLoader.RegisterCheck(networkpath.CheckName, networkpath.Factory(telemetry))
+	corecheckLoader.RegisterCheck(tcpprobe.CheckName, tcpprobe.Factory())
+	corecheckLoader.RegisterCheck(httpprobe.CheckName, httpprobe.Factory())
+	corecheckLoader.RegisterCheck(dnsprobe.CheckName, dnsprobe.Factory())
 	corecheckLoader.RegisterCheck(io.CheckName, io.Factory())
 	corecheckLoader.RegisterCheck(filehandles.CheckName, filehandles.Factory())
 	corecheckLoader.RegisterCheck(containerimage.CheckName, containerimage.Factory(store))
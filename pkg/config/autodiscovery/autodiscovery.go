@@ -39,6 +39,12 @@ func DiscoverComponentsFromConfig() ([]config.ConfigurationProviders, []config.L
 		log.Info("Database monitoring aurora discovery is enabled: Adding the aurora listener")
 	}
 
+	// Add the systemd units listener if the collection of systemd units is enabled
+	if config.Datadog().GetBool("systemd_units.enabled") {
+		detectedListeners = append(detectedListeners, config.Listeners{Name: "systemd_units"})
+		log.Info("Systemd units collection is enabled: Adding the systemd units listener")
+	}
+
 	// Auto-add file-based kube service and endpoints config providers based on check config files.
 	if flavor.GetFlavor() == flavor.ClusterAgent {
 		advancedConfigs, _, err := providers.ReadConfigFiles(providers.WithAdvancedADOnly)
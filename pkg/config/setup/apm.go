@@ -112,6 +112,10 @@ func setupAPM(config pkgconfigmodel.Setup) {
 	config.BindEnv("apm_config.connection_limit", "DD_APM_CONNECTION_LIMIT", "DD_CONNECTION_LIMIT")
 	config.BindEnv("apm_config.connection_reset_interval", "DD_APM_CONNECTION_RESET_INTERVAL")
 	config.BindEnv("apm_config.max_sender_retries", "DD_APM_MAX_SENDER_RETRIES")
+	config.BindEnvAndSetDefault("apm_config.disk_buffer.enabled", false, "DD_APM_DISK_BUFFER_ENABLED") //nolint:errcheck
+	config.BindEnv("apm_config.disk_buffer.path", "DD_APM_DISK_BUFFER_PATH")
+	config.BindEnv("apm_config.disk_buffer.max_size_mb", "DD_APM_DISK_BUFFER_MAX_SIZE_MB")
+	config.BindEnv("apm_config.disk_buffer.max_age_seconds", "DD_APM_DISK_BUFFER_MAX_AGE_SECONDS")
 	config.BindEnv("apm_config.profiling_dd_url", "DD_APM_PROFILING_DD_URL")
 	config.BindEnv("apm_config.profiling_additional_endpoints", "DD_APM_PROFILING_ADDITIONAL_ENDPOINTS")
 	config.BindEnv("apm_config.additional_endpoints", "DD_APM_ADDITIONAL_ENDPOINTS")
@@ -297,6 +297,11 @@ func InitConfig(config pkgconfigmodel.Config) {
 	// compatibility with Agent5 behavior/win
 	config.BindEnvAndSetDefault("hostname_fqdn", false)
 
+	// Collect systemd service units into workloadmeta, for tagging and autodiscovery
+	config.BindEnvAndSetDefault("systemd_units.enabled", false)
+	// Path of the systemd private socket, the system bus is used when empty
+	config.BindEnvAndSetDefault("systemd_units.private_socket", "")
+
 	// When enabled, hostname defined in the configuration (datadog.yaml) and starting with `ip-` or `domu` on EC2 is used as
 	// canonical hostname, otherwise the instance-id is used as canonical hostname.
 	config.BindEnvAndSetDefault("hostname_force_config_as_canonical", false)
@@ -566,6 +571,21 @@ func InitConfig(config pkgconfigmodel.Config) {
 	config.BindEnvAndSetDefault("cloud_foundry_container_tagger.retry_count", 10)
 	config.BindEnvAndSetDefault("cloud_foundry_container_tagger.retry_interval", 10)
 
+	// Nomad
+	config.BindEnvAndSetDefault("nomad.address", "")
+	config.BindEnvAndSetDefault("nomad.token", "")
+	config.BindEnvAndSetDefault("nomad.ca_file", "")
+	config.BindEnvAndSetDefault("nomad.cert_file", "")
+	config.BindEnvAndSetDefault("nomad.key_file", "")
+
+	// Consul catalog
+	config.BindEnvAndSetDefault("consul_catalog.address", "")
+	config.BindEnvAndSetDefault("consul_catalog.token", "")
+	config.BindEnvAndSetDefault("consul_catalog.ca_file", "")
+	config.BindEnvAndSetDefault("consul_catalog.cert_file", "")
+	config.BindEnvAndSetDefault("consul_catalog.key_file", "")
+	config.BindEnvAndSetDefault("consul_catalog.node_name", "")
+
 	// Azure
 	config.BindEnvAndSetDefault("azure_hostname_style", "os")
 
@@ -668,6 +688,8 @@ func InitConfig(config pkgconfigmodel.Config) {
 	config.BindEnvAndSetDefault("cluster_checks.exclude_checks", []string{})
 	config.BindEnvAndSetDefault("cluster_checks.exclude_checks_from_dispatching", []string{})
 	config.BindEnvAndSetDefault("cluster_checks.rebalance_period", 10*time.Minute)
+	config.BindEnvAndSetDefault("cluster_checks.node_zone", "")   // Zone reported to the Cluster Agent for topology-aware dispatching. Detected from the node labels if empty.
+	config.BindEnvAndSetDefault("cluster_checks.node_region", "") // Region reported to the Cluster Agent for topology-aware dispatching. Detected from the node labels if empty.
 
 	// Cluster check runner
 	config.BindEnvAndSetDefault("clc_runner_enabled", false)
@@ -743,6 +765,9 @@ func InitConfig(config pkgconfigmodel.Config) {
 	config.BindEnvAndSetDefault("admission_controller.agent_sidecar.image_name", "agent")
 	config.BindEnvAndSetDefault("admission_controller.agent_sidecar.image_tag", "latest")
 	config.BindEnvAndSetDefault("admission_controller.agent_sidecar.cluster_agent.enabled", "true")
+	config.BindEnvAndSetDefault("admission_controller.validate_annotations.enabled", false)
+	config.BindEnvAndSetDefault("admission_controller.validate_annotations.endpoint", "/validateannotations")
+	config.BindEnvAndSetDefault("admission_controller.validate_annotations.mode", "warn") // possible values: warn / reject
 
 	// Declare other keys that don't have a default/env var.
 	// Mostly, keys we use IsSet() on, because IsSet always returns true if a key has a default.
@@ -943,6 +968,25 @@ func InitConfig(config pkgconfigmodel.Config) {
 	config.BindEnvAndSetDefault("remote_updates", false)
 	config.BindEnvAndSetDefault("installer.registry.url", "")
 	config.BindEnvAndSetDefault("installer.registry.auth", "")
+	config.BindEnvAndSetDefault("installer.signature.mode", "")
+	config.BindEnvAndSetDefault("installer.signature.public_key_files", []string{})
+	config.BindEnvAndSetDefault("installer.mirror.url", "")
+	config.BindEnvAndSetDefault("installer.mirror.auth", "")
+	config.BindEnvAndSetDefault("installer.mirror.fallback", "none")
+	// Experiment policy: promotes experiments healthy for the soak duration and rolls back failing ones
+	config.BindEnvAndSetDefault("installer.experiment_policy.enabled", false)
+	config.BindEnvAndSetDefault("installer.experiment_policy.packages", []string{"datadog-agent"})
+	config.BindEnvAndSetDefault("installer.experiment_policy.soak_duration", "30m")
+	config.BindEnvAndSetDefault("installer.experiment_policy.check_interval", "1m")
+	config.BindEnvAndSetDefault("installer.experiment_policy.failure_threshold", 3)
+	config.BindEnvAndSetDefault("installer.experiment_policy.agent_health", true)
+	config.BindEnvAndSetDefault("installer.experiment_policy.healthprobe", true)
+	config.BindEnvAndSetDefault("installer.experiment_policy.healthprobe_url", "")
+	config.BindEnvAndSetDefault("installer.experiment_policy.systemd_units", map[string][]string{"datadog-agent": {"datadog-agent-exp.service"}})
+	config.BindEnvAndSetDefault("installer.experiment_policy.max_restarts", 3)
+	config.BindEnvAndSetDefault("installer.experiment_policy.error_rate.metric", "")
+	config.BindEnvAndSetDefault("installer.experiment_policy.error_rate.url", "http://localhost:5000/telemetry")
+	config.BindEnvAndSetDefault("installer.experiment_policy.error_rate.max_per_minute", 10.0)
 
 	// Data Jobs Monitoring config
 	config.BindEnvAndSetDefault("djm_config.enabled", false)
@@ -1300,6 +1344,11 @@ func dogstatsd(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("dogstatsd_expiry_seconds", 300)
 	// Control how long we keep dogstatsd contexts in memory.
 	config.BindEnvAndSetDefault("dogstatsd_context_expiry_seconds", 20)
+	// Track, and optionally limit, the number of distinct values of the tags of each metric.
+	config.BindEnvAndSetDefault("dogstatsd_tag_cardinality_enabled", false)
+	config.BindEnvAndSetDefault("dogstatsd_tag_cardinality_limit", 0)
+	config.BindEnvAndSetDefault("dogstatsd_tag_cardinality_limit_action", "collapse")
+	config.BindEnvAndSetDefault("dogstatsd_tag_cardinality_window_seconds", 300)
 	config.BindEnvAndSetDefault("dogstatsd_origin_detection", false) // Only supported for socket traffic
 	config.BindEnvAndSetDefault("dogstatsd_origin_detection_client", false)
 	config.BindEnvAndSetDefault("dogstatsd_origin_optout_enabled", true)
@@ -233,6 +233,9 @@ func InitSystemProbeConfig(cfg pkgconfigmodel.Config) {
 	cfg.BindEnvAndSetDefault(join(smNS, "enable_http2_monitoring"), false)
 	cfg.BindEnvAndSetDefault(join(smNS, "enable_kafka_monitoring"), false)
 	cfg.BindEnv(join(smNS, "enable_postgres_monitoring"))
+	cfg.BindEnvAndSetDefault(join(smNS, "enable_memcached_monitoring"), false)
+	cfg.BindEnvAndSetDefault(join(smNS, "enable_cassandra_monitoring"), false)
+	cfg.BindEnvAndSetDefault(join(smNS, "enable_dns_over_tls_monitoring"), false)
 	cfg.BindEnvAndSetDefault(join(smNS, "tls", "istio", "enabled"), false)
 	cfg.BindEnv(join(smNS, "tls", "nodejs", "enabled"))
 	cfg.BindEnvAndSetDefault(join(smjtNS, "enabled"), false)
@@ -249,6 +252,8 @@ func InitSystemProbeConfig(cfg pkgconfigmodel.Config) {
 	cfg.BindEnv(join(smNS, "max_http_stats_buffered"))
 	cfg.BindEnvAndSetDefault(join(smNS, "max_kafka_stats_buffered"), 100000)
 	cfg.BindEnv(join(smNS, "max_postgres_stats_buffered"))
+	cfg.BindEnvAndSetDefault(join(smNS, "max_memcached_stats_buffered"), 100000)
+	cfg.BindEnvAndSetDefault(join(smNS, "max_cassandra_stats_buffered"), 100000)
 	cfg.BindEnv(join(smNS, "max_concurrent_requests"))
 	cfg.BindEnv(join(smNS, "enable_quantization"))
 	cfg.BindEnv(join(smNS, "enable_connection_rollup"))
@@ -283,6 +288,10 @@ func InitSystemProbeConfig(cfg pkgconfigmodel.Config) {
 
 	// list of DNS query types to be recorded
 	cfg.BindEnvAndSetDefault(join(netNS, "dns_recorded_query_types"), []string{})
+	// record a latency distribution per response code in DNS stats
+	cfg.BindEnvAndSetDefault(join(netNS, "enable_dns_latency_sketches"), false)
+	// domain suffixes under which DNS stats are grouped
+	cfg.BindEnvAndSetDefault(join(netNS, "dns_domain_groups"), []string{})
 	// (temporary) enable submitting DNS stats by query type.
 	cfg.BindEnvAndSetDefault(join(netNS, "enable_dns_by_querytype"), false)
 	// connection aggregation with port rollups
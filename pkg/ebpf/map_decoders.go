@@ -0,0 +1,62 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package ebpf
+
+import (
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// MapDecoder decodes the raw keys and values of an eBPF map into the Go types used by the agent
+type MapDecoder interface {
+	DecodeKey(buf []byte) (interface{}, error)
+	DecodeValue(buf []byte) (interface{}, error)
+}
+
+var mapDecodersLock sync.RWMutex
+var mapDecoders = make(map[string]MapDecoder)
+
+// AddMapDecoder registers the key and value types of the maps with the given name,
+// so their content can be decoded when inspecting them
+func AddMapDecoder[K any, V any](mapName string) {
+	mapDecodersLock.Lock()
+	defer mapDecodersLock.Unlock()
+
+	mapDecoders[mapName] = typedMapDecoder[K, V]{}
+}
+
+// GetMapDecoder returns the decoder registered for the maps with the given name
+func GetMapDecoder(mapName string) (MapDecoder, bool) {
+	mapDecodersLock.RLock()
+	defer mapDecodersLock.RUnlock()
+
+	d, ok := mapDecoders[mapName]
+	return d, ok
+}
+
+type typedMapDecoder[K any, V any] struct{}
+
+func (typedMapDecoder[K, V]) DecodeKey(buf []byte) (interface{}, error) {
+	return decodeAs[K](buf)
+}
+
+func (typedMapDecoder[K, V]) DecodeValue(buf []byte) (interface{}, error) {
+	return decodeAs[V](buf)
+}
+
+// decodeAs copies buf into a value of type T, which must have the memory layout of the eBPF type
+func decodeAs[T any](buf []byte) (T, error) {
+	var v T
+	dst := unsafe.Slice((*byte)(unsafe.Pointer(&v)), unsafe.Sizeof(v))
+	if len(buf) != len(dst) {
+		return v, fmt.Errorf("cannot decode %d bytes into %T of size %d", len(buf), v, len(dst))
+	}
+	copy(dst, buf)
+	return v, nil
+}
@@ -0,0 +1,47 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package ebpf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDecoderKey struct {
+	Pid  uint32
+	Port uint16
+	_    [2]byte
+}
+
+func TestMapDecoder(t *testing.T) {
+	AddMapDecoder[testDecoderKey, uint64]("test_decoder_map")
+	t.Cleanup(func() {
+		mapDecodersLock.Lock()
+		delete(mapDecoders, "test_decoder_map")
+		mapDecodersLock.Unlock()
+	})
+
+	_, ok := GetMapDecoder("unknown_map")
+	assert.False(t, ok)
+
+	d, ok := GetMapDecoder("test_decoder_map")
+	require.True(t, ok)
+
+	key, err := d.DecodeKey([]byte{0x2a, 0, 0, 0, 0x50, 0, 0, 0})
+	require.NoError(t, err)
+	assert.Equal(t, testDecoderKey{Pid: 42, Port: 80}, key)
+
+	value, err := d.DecodeValue([]byte{1, 0, 0, 0, 0, 0, 0, 0})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), value)
+
+	_, err = d.DecodeValue([]byte{1, 0})
+	assert.EqualError(t, err, "cannot decode 2 bytes into uint64 of size 8")
+}
@@ -9,6 +9,8 @@ package ebpf
 
 import (
 	"errors"
+	"fmt"
+	"slices"
 	"sync"
 
 	manager "github.com/DataDog/ebpf-manager"
@@ -22,6 +24,7 @@ var mapModuleMapping = make(map[uint32]string)
 
 var progNameMapping = make(map[uint32]string)
 var progModuleMapping = make(map[uint32]string)
+var progAttachMapping = make(map[uint32][]string)
 
 var progIgnoredIds = make(map[ebpf.ProgramID]struct{})
 
@@ -61,6 +64,44 @@ func AddNameMappings(mgr *manager.Manager, module string) {
 		progNameMapping[progid] = name
 		progModuleMapping[progid] = module
 	})
+
+	for _, p := range mgr.Probes {
+		if p == nil {
+			continue
+		}
+		progid := p.ID()
+		if progid == 0 {
+			continue
+		}
+		if hook := probeAttachPoint(p); !slices.Contains(progAttachMapping[progid], hook) {
+			progAttachMapping[progid] = append(progAttachMapping[progid], hook)
+		}
+	}
+}
+
+// probeAttachPoint describes the hook point of a probe, for troubleshooting purposes
+func probeAttachPoint(p *manager.Probe) string {
+	var hook string
+	switch {
+	case p.TracepointCategory != "":
+		hook = p.TracepointCategory + "/" + p.TracepointName
+	case p.HookFuncName != "":
+		hook = p.HookFuncName
+	case p.CGroupPath != "":
+		hook = p.CGroupPath
+	case p.IfName != "":
+		hook = p.IfName
+	case p.IfIndex != 0:
+		hook = fmt.Sprintf("ifindex:%d", p.IfIndex)
+	case p.SocketFD > 0:
+		hook = fmt.Sprintf("socket:%d", p.SocketFD)
+	default:
+		hook = p.EBPFFuncName
+	}
+	if p.BinaryPath != "" {
+		hook = p.BinaryPath + ":" + hook
+	}
+	return hook
 }
 
 // AddNameMappingsCollection adds the full name mappings for ebpf maps in the collection
@@ -111,6 +152,18 @@ func GetModuleFromProgID(id uint32) (string, error) {
 	return getMappingFromID(id, progModuleMapping)
 }
 
+// GetAttachPointsFromProgID returns the hook points of the program with the given id
+func GetAttachPointsFromProgID(id uint32) ([]string, error) {
+	mappingLock.RLock()
+	defer mappingLock.RUnlock()
+
+	attachPoints, ok := progAttachMapping[id]
+	if !ok {
+		return nil, errNoMapping
+	}
+	return append([]string(nil), attachPoints...), nil
+}
+
 // RemoveNameMappings removes the full name mappings for ebpf maps in the manager
 func RemoveNameMappings(mgr *manager.Manager) {
 	maps, err := mgr.GetMaps()
@@ -133,6 +186,7 @@ func RemoveNameMappings(mgr *manager.Manager) {
 	iterateProgs(progs, func(progid uint32, name string) {
 		delete(progNameMapping, progid)
 		delete(progModuleMapping, progid)
+		delete(progAttachMapping, progid)
 	})
 
 	for _, p := range mgr.Probes {
@@ -143,6 +197,7 @@ func RemoveNameMappings(mgr *manager.Manager) {
 		progid := p.ID()
 		delete(progNameMapping, progid)
 		delete(progModuleMapping, progid)
+		delete(progAttachMapping, progid)
 	}
 }
 
@@ -88,12 +88,36 @@ func GetClusterChecks(w io.Writer, checkName string) error {
 	fmt.Fprintf(w, "=== %d agents reporting ===\n", len(cr.Nodes))
 	sort.Slice(cr.Nodes, func(i, j int) bool { return cr.Nodes[i].Name < cr.Nodes[j].Name })
 	table := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
-	fmt.Fprintln(table, "\nName\tRunning checks")
-	for _, n := range cr.Nodes {
-		fmt.Fprintf(table, "%s\t%d\n", n.Name, len(n.Configs))
+	if hasTopology(cr.Nodes) {
+		fmt.Fprintln(table, "\nName\tRunning checks\tZone\tRegion")
+		for _, n := range cr.Nodes {
+			fmt.Fprintf(table, "%s\t%d\t%s\t%s\n", n.Name, len(n.Configs), n.Zone, n.Region)
+		}
+	} else {
+		fmt.Fprintln(table, "\nName\tRunning checks")
+		for _, n := range cr.Nodes {
+			fmt.Fprintf(table, "%s\t%d\n", n.Name, len(n.Configs))
+		}
 	}
 	table.Flush()
 
+	// Print placement decisions of the checks with a topology affinity
+	var placements []types.PlacementResponse
+	for _, p := range cr.Placements {
+		if checkName == "" || p.CheckName == checkName {
+			placements = append(placements, p)
+		}
+	}
+	if len(placements) > 0 {
+		fmt.Fprintf(w, "\n=== %d topology-aware configurations ===\n", len(placements))
+		table = tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(table, "\nCheck\tDigest\tNode\tAffinity\tPlacement")
+		for _, p := range placements {
+			fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", p.CheckName, p.Digest, p.Node, p.Affinity, p.Reason)
+		}
+		table.Flush()
+	}
+
 	// Print per-node configurations
 	for _, node := range cr.Nodes {
 		if len(node.Configs) == 0 {
@@ -152,6 +176,15 @@ func GetEndpointsChecks(w io.Writer, checkName string) error {
 	return nil
 }
 
+func hasTopology(nodes []types.StateNodeResponse) bool {
+	for _, n := range nodes {
+		if n.Zone != "" || n.Region != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func endpointschecksEnabled() bool {
 	for _, provider := range config.Datadog().GetStringSlice("extra_config_providers") {
 		if provider == names.KubeEndpointsRegisterName {
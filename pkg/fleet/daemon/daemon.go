@@ -12,11 +12,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	osexec "os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace"
@@ -30,6 +33,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/fleet/installer/repository"
 	"github.com/DataDog/datadog-agent/pkg/fleet/internal/bootstrap"
 	"github.com/DataDog/datadog-agent/pkg/fleet/internal/exec"
+	"github.com/DataDog/datadog-agent/pkg/fleet/internal/oci"
 	pbgo "github.com/DataDog/datadog-agent/pkg/proto/pbgo/core"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/version"
@@ -38,6 +42,8 @@ import (
 const (
 	// gcInterval is the interval at which the GC will run
 	gcInterval = 1 * time.Hour
+	// mirrorCheckTimeout is the timeout of the requests checking that the packages of a catalog are in the mirror
+	mirrorCheckTimeout = 30 * time.Second
 )
 
 // Daemon is the fleet daemon in charge of remote install, updates and configuration.
@@ -54,6 +60,7 @@ type Daemon interface {
 	GetPackage(pkg string, version string) (Package, error)
 	GetState() (map[string]repository.State, error)
 	GetAPMInjectionStatus() (APMInjectionStatus, error)
+	GetExperimentPolicyStates() map[string]ExperimentPolicyState
 }
 
 type daemonImpl struct {
@@ -66,6 +73,13 @@ type daemonImpl struct {
 	catalog    catalog
 	requests   chan remoteAPIRequest
 	requestsWG sync.WaitGroup
+
+	policy        *experimentPolicy
+	policyStates  map[string]ExperimentPolicyState
+	policyRunning atomic.Bool
+
+	// mirrorClient is the client used to check the catalog packages against the mirror
+	mirrorClient *http.Client
 }
 
 func newInstaller(env *env.Env, installerBin string) installer.Installer {
@@ -88,17 +102,20 @@ func NewDaemon(rcFetcher client.ConfigFetcher, config config.Reader) (Daemon, er
 	}
 	env := env.FromConfig(config)
 	installer := newInstaller(env, installerBin)
-	return newDaemon(rc, installer, env), nil
+	return newDaemon(rc, installer, env, newExperimentPolicy(config)), nil
 }
 
-func newDaemon(rc *remoteConfig, installer installer.Installer, env *env.Env) *daemonImpl {
+func newDaemon(rc *remoteConfig, installer installer.Installer, env *env.Env, policy *experimentPolicy) *daemonImpl {
 	i := &daemonImpl{
-		env:       env,
-		rc:        rc,
-		installer: installer,
-		requests:  make(chan remoteAPIRequest, 32),
-		catalog:   catalog{},
-		stopChan:  make(chan struct{}),
+		env:          env,
+		rc:           rc,
+		installer:    installer,
+		requests:     make(chan remoteAPIRequest, 32),
+		catalog:      catalog{},
+		stopChan:     make(chan struct{}),
+		policy:       policy,
+		policyStates: map[string]ExperimentPolicyState{},
+		mirrorClient: &http.Client{Timeout: mirrorCheckTimeout},
 	}
 	i.refreshState(context.Background())
 	return i
@@ -168,17 +185,46 @@ func (d *daemonImpl) GetPackage(pkg string, version string) (Package, error) {
 func (d *daemonImpl) SetCatalog(c catalog) {
 	d.m.Lock()
 	defer d.m.Unlock()
+	d.setCatalog(c)
+}
+
+// setCatalog sets the catalog and the signature keys it delivers, which are passed to the installer through its env.
+func (d *daemonImpl) setCatalog(c catalog) {
 	d.catalog = c
+	d.env.SignatureCatalogKeys = strings.Join(c.SignatureKeys, "\n")
 }
 
-// Start starts remote config and the garbage collector.
+// Start starts remote config, the garbage collector and the experiment policy.
 func (d *daemonImpl) Start(_ context.Context) error {
 	d.m.Lock()
 	defer d.m.Unlock()
+	if d.policy != nil {
+		log.Infof("Daemon: Experiment policy enabled for packages %v", d.policy.packages)
+	}
 	go func() {
+		// The GC runs on a ticker as policy evaluations would otherwise keep resetting its timer
+		gcTicker := time.NewTicker(gcInterval)
+		defer gcTicker.Stop()
+		var policyTick <-chan time.Time
+		if d.policy != nil {
+			policyTicker := time.NewTicker(d.policy.checkInterval)
+			defer policyTicker.Stop()
+			policyTick = policyTicker.C
+		}
+		policyCtx, cancelPolicy := context.WithCancel(context.Background())
+		defer cancelPolicy()
 		for {
 			select {
-			case <-time.After(gcInterval):
+			case <-policyTick:
+				// Health checks can take a while, they must not delay remote requests.
+				// A tick is skipped if the previous evaluation is still running.
+				if d.policyRunning.CompareAndSwap(false, true) {
+					go func() {
+						defer d.policyRunning.Store(false)
+						d.evaluateExperiments(policyCtx)
+					}()
+				}
+			case <-gcTicker.C:
 				d.m.Lock()
 				err := d.installer.GarbageCollect(context.Background())
 				d.m.Unlock()
@@ -317,10 +363,43 @@ func (d *daemonImpl) stopExperiment(ctx context.Context, pkg string) (err error)
 }
 
 func (d *daemonImpl) handleCatalogUpdate(c catalog) error {
+	log.Infof("Installer: Received catalog update")
+	// The mirror is checked before locking as it may be remote
+	err := d.validateCatalogMirror(context.Background(), c)
+	if err != nil {
+		return err
+	}
 	d.m.Lock()
 	defer d.m.Unlock()
-	log.Infof("Installer: Received catalog update")
-	d.catalog = c
+	d.setCatalog(c)
+	return nil
+}
+
+// validateCatalogMirror checks that the packages of the catalog for this host are available in the mirror.
+// Catalogs with missing packages are rejected when the daemon can't fall back to the package registries.
+func (d *daemonImpl) validateCatalogMirror(ctx context.Context, c catalog) error {
+	if d.env.Mirror == "" {
+		return nil
+	}
+	downloader := oci.NewDownloader(d.env, d.mirrorClient)
+	var errs []error
+	for _, p := range c.Packages {
+		if (p.Arch != "" && p.Arch != runtime.GOARCH) || (p.Platform != "" && p.Platform != runtime.GOOS) {
+			continue
+		}
+		err := downloader.CheckMirror(ctx, p.URL)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("package %s version %s: %w", p.Name, p.Version, err))
+		}
+	}
+	err := errors.Join(errs...)
+	if err == nil {
+		return nil
+	}
+	if oci.ParseMirrorFallback(d.env.MirrorFallback) == oci.MirrorFallbackNone {
+		return fmt.Errorf("catalog packages are not available in mirror %s: %w", d.env.Mirror, err)
+	}
+	log.Warnf("Installer: catalog packages are not available in mirror %s and will be downloaded from their registry: %v", d.env.Mirror, err)
 	return nil
 }
 
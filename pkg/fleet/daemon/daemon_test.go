@@ -11,6 +11,7 @@ package daemon
 import (
 	"context"
 	"encoding/json"
+	"path/filepath"
 	"runtime"
 	"testing"
 
@@ -19,7 +20,10 @@ import (
 
 	"github.com/DataDog/datadog-agent/pkg/config/remote/client"
 	"github.com/DataDog/datadog-agent/pkg/fleet/env"
+	"github.com/DataDog/datadog-agent/pkg/fleet/installer"
 	"github.com/DataDog/datadog-agent/pkg/fleet/installer/repository"
+	"github.com/DataDog/datadog-agent/pkg/fleet/internal/fixtures"
+	"github.com/DataDog/datadog-agent/pkg/fleet/internal/oci"
 	pbgo "github.com/DataDog/datadog-agent/pkg/proto/pbgo/core"
 	"github.com/DataDog/datadog-agent/pkg/remoteconfig/state"
 	"github.com/DataDog/datadog-agent/pkg/version"
@@ -151,7 +155,7 @@ func newTestInstaller() *testInstaller {
 	rcc := newTestRemoteConfigClient()
 	rc := &remoteConfig{client: rcc}
 	i := &testInstaller{
-		daemonImpl: newDaemon(rc, pm, &env.Env{RemoteUpdates: true}),
+		daemonImpl: newDaemon(rc, pm, &env.Env{RemoteUpdates: true}, nil),
 		rcc:        rcc,
 		pm:         pm,
 	}
@@ -234,8 +238,10 @@ func TestUpdateCatalog(t *testing.T) {
 		Platform: runtime.GOOS,
 		Arch:     runtime.GOARCH,
 	}
+	signatureKey := newTestSignatureKey(t)
 	c := catalog{
-		Packages: []Package{testPackage},
+		Packages:      []Package{testPackage},
+		SignatureKeys: []string{signatureKey},
 	}
 	i.rcc.SubmitCatalog(c)
 	pkg, err := i.daemonImpl.GetPackage("test-package", "1.0.0")
@@ -243,9 +249,54 @@ func TestUpdateCatalog(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, testPackage, pkg)
 	assert.Equal(t, c, i.daemonImpl.catalog)
+	assert.Equal(t, signatureKey, i.daemonImpl.env.SignatureCatalogKeys)
 	i.pm.AssertExpectations(t)
 }
 
+func TestUpdateCatalogMirror(t *testing.T) {
+	s := fixtures.NewServer(t)
+	mirror := "file://" + filepath.Join(t.TempDir(), "mirror")
+	err := installer.Mirror(context.Background(), &env.Env{}, installer.MirrorCatalog{Packages: []installer.MirrorPackage{
+		{URL: s.PackageURL(fixtures.FixtureSimpleV1)},
+	}}, mirror)
+	assert.NoError(t, err)
+	// The registry of the packages is never reached
+	s.Close()
+	mirroredPackage := Package{Name: "simple", Version: "v1", URL: s.PackageURL(fixtures.FixtureSimpleV1)}
+	missingPackage := Package{Name: "simple", Version: "v2", URL: s.PackageURL(fixtures.FixtureSimpleV2)}
+	otherPlatformPackage := Package{Name: "simple", Version: "v2", URL: s.PackageURL(fixtures.FixtureSimpleV2), Platform: "plan9"}
+	localPackage := Package{Name: "local", Version: "v1", URL: "file:///opt/datadog-packages/local"}
+
+	tests := []struct {
+		name     string
+		fallback string
+		catalog  catalog
+		valid    bool
+	}{
+		{name: "mirrored", catalog: catalog{Packages: []Package{mirroredPackage, otherPlatformPackage}}, valid: true},
+		{name: "local package", catalog: catalog{Packages: []Package{mirroredPackage, localPackage}}, valid: true},
+		{name: "missing", catalog: catalog{Packages: []Package{mirroredPackage, missingPackage}}},
+		{name: "missing with fallback", fallback: "missing", catalog: catalog{Packages: []Package{mirroredPackage, missingPackage}}, valid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := newTestInstaller()
+			defer i.Stop()
+			i.env.Mirror = mirror
+			i.env.MirrorFallback = tt.fallback
+
+			err := i.handleCatalogUpdate(tt.catalog)
+			if tt.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.catalog, i.catalog)
+			} else {
+				assert.ErrorIs(t, err, oci.ErrNotInMirror)
+				assert.Empty(t, i.catalog.Packages)
+			}
+		})
+	}
+}
+
 func TestRemoteRequest(t *testing.T) {
 	i := newTestInstaller()
 	defer i.Stop()
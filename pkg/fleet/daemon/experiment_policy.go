@@ -0,0 +1,424 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	osexec "os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
+
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	apiutil "github.com/DataDog/datadog-agent/pkg/api/util"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/status/health"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/prometheus"
+)
+
+const (
+	// healthCheckTimeout is the timeout of a single health check
+	healthCheckTimeout = 10 * time.Second
+	// defaultCheckInterval is used when the configured check interval is not positive
+	defaultCheckInterval = time.Minute
+	// agentPackage is the package monitored by the agent health checks
+	agentPackage = "datadog-agent"
+)
+
+// ExperimentDecision is the decision taken by the experiment policy on an experiment.
+type ExperimentDecision string
+
+const (
+	// ExperimentDecisionNone is set while the experiment is soaking.
+	ExperimentDecisionNone ExperimentDecision = ""
+	// ExperimentDecisionPromoted is set when the experiment was healthy for the whole soak duration and was promoted.
+	ExperimentDecisionPromoted ExperimentDecision = "promoted"
+	// ExperimentDecisionRolledBack is set when the experiment failed its health checks and was stopped.
+	ExperimentDecisionRolledBack ExperimentDecision = "rolled_back"
+)
+
+// ExperimentPolicyState is the state of the experiment policy for a package.
+type ExperimentPolicyState struct {
+	Version   string             `json:"version"`
+	StartedAt time.Time          `json:"started_at"`
+	Failures  int                `json:"failures"`
+	Decision  ExperimentDecision `json:"decision,omitempty"`
+	DecidedAt time.Time          `json:"decided_at,omitempty"`
+	Reason    string             `json:"reason,omitempty"`
+}
+
+// healthCheck is a health condition evaluated on experiments.
+type healthCheck interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// experimentPolicy promotes experiments that stay healthy for the soak duration and
+// rolls back experiments failing their health checks.
+type experimentPolicy struct {
+	packages         []string
+	soakDuration     time.Duration
+	checkInterval    time.Duration
+	failureThreshold int
+	// checks are the health checks of each package, an experiment is only judged
+	// by the checks of its own package
+	checks map[string][]healthCheck
+	now    func() time.Time
+}
+
+// newExperimentPolicy returns the experiment policy configured under installer.experiment_policy,
+// or nil if it is disabled.
+func newExperimentPolicy(config config.Reader) *experimentPolicy {
+	if !config.GetBool("installer.experiment_policy.enabled") {
+		return nil
+	}
+	client := apiutil.GetClient(false)
+	client.Timeout = healthCheckTimeout
+	// the agent health, healthprobe and error rate checks monitor the agent
+	var agentChecks []healthCheck
+	if config.GetBool("installer.experiment_policy.agent_health") {
+		ipcAddress, err := pkgconfigsetup.GetIPCAddress(config)
+		if err != nil {
+			log.Warnf("Daemon: could not get the agent IPC address, skipping the agent health check: %v", err)
+		} else {
+			agentChecks = append(agentChecks, &httpHealthCheck{
+				name:   "agent_health",
+				url:    fmt.Sprintf("https://%s:%d/agent/status/health", ipcAddress, config.GetInt("cmd_port")),
+				client: client,
+				authToken: func() (string, error) {
+					err := apiutil.SetAuthToken(config)
+					return apiutil.GetAuthToken(), err
+				},
+			})
+		}
+	}
+	if url := config.GetString("installer.experiment_policy.healthprobe_url"); url != "" {
+		agentChecks = append(agentChecks, &httpHealthCheck{name: "healthprobe", url: url, client: client})
+	} else if port := config.GetInt("health_port"); port > 0 && config.GetBool("installer.experiment_policy.healthprobe") {
+		agentChecks = append(agentChecks, &httpHealthCheck{name: "healthprobe", url: fmt.Sprintf("http://localhost:%d/live", port), client: client})
+	}
+	if metric := config.GetString("installer.experiment_policy.error_rate.metric"); metric != "" {
+		agentChecks = append(agentChecks, &errorRateHealthCheck{
+			url:                config.GetString("installer.experiment_policy.error_rate.url"),
+			metric:             metric,
+			maxErrorsPerMinute: config.GetFloat64("installer.experiment_policy.error_rate.max_per_minute"),
+			client:             client,
+			now:                time.Now,
+		})
+	}
+	checks := map[string][]healthCheck{}
+	if len(agentChecks) > 0 {
+		checks[agentPackage] = agentChecks
+	}
+	// the crash loops are detected on the units of each package
+	for pkg, units := range config.GetStringMapStringSlice("installer.experiment_policy.systemd_units") {
+		if len(units) == 0 {
+			continue
+		}
+		checks[pkg] = append(checks[pkg], &crashLoopHealthCheck{
+			units:       units,
+			maxRestarts: config.GetInt("installer.experiment_policy.max_restarts"),
+			showUnit:    systemctlShow,
+		})
+	}
+
+	checkInterval := config.GetDuration("installer.experiment_policy.check_interval")
+	if checkInterval <= 0 {
+		log.Warnf("Daemon: invalid experiment policy check interval %s, using %s", checkInterval, defaultCheckInterval)
+		checkInterval = defaultCheckInterval
+	}
+	return &experimentPolicy{
+		packages:         config.GetStringSlice("installer.experiment_policy.packages"),
+		soakDuration:     config.GetDuration("installer.experiment_policy.soak_duration"),
+		checkInterval:    checkInterval,
+		failureThreshold: config.GetInt("installer.experiment_policy.failure_threshold"),
+		checks:           checks,
+		now:              time.Now,
+	}
+}
+
+// appliesTo returns true if the policy manages the experiments of the package.
+func (p *experimentPolicy) appliesTo(pkg string) bool {
+	for _, p := range p.packages {
+		if p == pkg {
+			return true
+		}
+	}
+	return false
+}
+
+// check runs the health checks of the package and returns the reasons of their failures.
+func (p *experimentPolicy) check(ctx context.Context, pkg string) error {
+	var errs []error
+	for _, c := range p.checks[pkg] {
+		checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		err := c.Check(checkCtx)
+		cancel()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// httpHealthCheck checks a health endpoint returning a health.Status, such as the agent
+// health endpoint or the healthprobe component.
+type httpHealthCheck struct {
+	name      string
+	url       string
+	client    *http.Client
+	authToken func() (string, error)
+}
+
+func (c *httpHealthCheck) Name() string {
+	return c.name
+}
+
+func (c *httpHealthCheck) Check(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return fmt.Errorf("could not create request: %w", err)
+	}
+	if c.authToken != nil {
+		token, err := c.authToken()
+		if err != nil {
+			return fmt.Errorf("could not get auth token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not reach %s: %w", c.url, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("could not read response: %w", err)
+	}
+	var status health.Status
+	_ = json.Unmarshal(body, &status)
+	if len(status.Unhealthy) > 0 {
+		return fmt.Errorf("unhealthy components: %s", strings.Join(status.Unhealthy, ", "))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// crashLoopHealthCheck detects systemd units restarting in a loop or failed.
+type crashLoopHealthCheck struct {
+	units       []string
+	maxRestarts int
+	showUnit    func(ctx context.Context, unit string) (map[string]string, error)
+}
+
+func (c *crashLoopHealthCheck) Name() string {
+	return "crash_loop"
+}
+
+func (c *crashLoopHealthCheck) Check(ctx context.Context) error {
+	for _, unit := range c.units {
+		properties, err := c.showUnit(ctx, unit)
+		if err != nil {
+			return fmt.Errorf("could not get state of unit %s: %w", unit, err)
+		}
+		if properties["ActiveState"] == "failed" {
+			return fmt.Errorf("unit %s failed", unit)
+		}
+		restarts, err := strconv.Atoi(properties["NRestarts"])
+		if err != nil {
+			return fmt.Errorf("could not parse restarts of unit %s: %w", unit, err)
+		}
+		if restarts > c.maxRestarts {
+			return fmt.Errorf("unit %s restarted %d times", unit, restarts)
+		}
+	}
+	return nil
+}
+
+// systemctlShow returns the restart count and active state of a systemd unit.
+func systemctlShow(ctx context.Context, unit string) (map[string]string, error) {
+	output, err := osexec.CommandContext(ctx, "systemctl", "show", "-p", "NRestarts", "-p", "ActiveState", unit).Output()
+	if err != nil {
+		return nil, err
+	}
+	properties := map[string]string{}
+	for _, line := range strings.Split(string(output), "\n") {
+		if key, value, ok := strings.Cut(line, "="); ok {
+			properties[key] = value
+		}
+	}
+	return properties, nil
+}
+
+// errorRateHealthCheck checks the rate of increase of an error counter exposed by a
+// prometheus telemetry endpoint.
+type errorRateHealthCheck struct {
+	url                string
+	metric             string
+	maxErrorsPerMinute float64
+	client             *http.Client
+	now                func() time.Time
+
+	lastValue float64
+	lastTime  time.Time
+}
+
+func (c *errorRateHealthCheck) Name() string {
+	return "error_rate"
+}
+
+func (c *errorRateHealthCheck) Check(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return fmt.Errorf("could not create request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not reach %s: %w", c.url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("could not read response: %w", err)
+	}
+	families, err := prometheus.ParseMetrics(body)
+	if err != nil {
+		return fmt.Errorf("could not parse metrics: %w", err)
+	}
+	var value float64
+	for _, family := range families {
+		if family.Name != c.metric {
+			continue
+		}
+		for _, sample := range family.Samples {
+			value += float64(sample.Value)
+		}
+	}
+
+	now := c.now()
+	lastValue, lastTime := c.lastValue, c.lastTime
+	c.lastValue, c.lastTime = value, now
+	// The first sample, or a counter reset after a restart, only sets the baseline
+	if lastTime.IsZero() || value < lastValue || !now.After(lastTime) {
+		return nil
+	}
+	rate := (value - lastValue) / now.Sub(lastTime).Minutes()
+	if rate > c.maxErrorsPerMinute {
+		return fmt.Errorf("%s increased by %.2f per minute, above %.2f", c.metric, rate, c.maxErrorsPerMinute)
+	}
+	return nil
+}
+
+// evaluateExperiments runs the health checks of the experiments managed by the policy,
+// promotes the ones healthy for the soak duration and rolls back the ones failing their checks.
+func (d *daemonImpl) evaluateExperiments(ctx context.Context) {
+	d.m.Lock()
+	states, err := d.installer.States()
+	d.m.Unlock()
+	if err != nil {
+		log.Errorf("Daemon: could not get installer state: %v", err)
+		return
+	}
+	for pkg, state := range states {
+		if !d.policy.appliesTo(pkg) || state.Experiment == "" {
+			continue
+		}
+		d.evaluateExperiment(ctx, pkg, state.Experiment)
+	}
+}
+
+func (d *daemonImpl) evaluateExperiment(ctx context.Context, pkg string, version string) {
+	d.m.Lock()
+	s, ok := d.policyStates[pkg]
+	if !ok || s.Version != version || s.Decision != ExperimentDecisionNone {
+		// The soak starts when the daemon first sees the experiment
+		s = ExperimentPolicyState{Version: version, StartedAt: d.policy.now()}
+	}
+	d.policyStates[pkg] = s
+	d.m.Unlock()
+
+	// Health checks reach other services and run without holding the daemon lock
+	checkErr := d.policy.check(ctx, pkg)
+	if ctx.Err() != nil {
+		// The daemon is stopping
+		return
+	}
+
+	d.m.Lock()
+	defer d.m.Unlock()
+	state, err := d.installer.State(pkg)
+	if err != nil || state.Experiment != version {
+		// The experiment was changed while the checks were running
+		return
+	}
+	now := d.policy.now()
+	if checkErr != nil {
+		s.Failures++
+		s.Reason = checkErr.Error()
+		log.Warnf("Daemon: experiment %s of package %s failed its health checks (%d/%d): %v", version, pkg, s.Failures, d.policy.failureThreshold, checkErr)
+		if s.Failures >= d.policy.failureThreshold {
+			d.decideExperiment(ctx, pkg, &s, ExperimentDecisionRolledBack, fmt.Sprintf("health checks failed %d times: %v", s.Failures, checkErr), now)
+		}
+	} else {
+		s.Failures = 0
+		s.Reason = ""
+		if soaked := now.Sub(s.StartedAt); soaked >= d.policy.soakDuration {
+			d.decideExperiment(ctx, pkg, &s, ExperimentDecisionPromoted, fmt.Sprintf("healthy for %s", soaked.Round(time.Second)), now)
+		}
+	}
+	d.policyStates[pkg] = s
+}
+
+func (d *daemonImpl) decideExperiment(ctx context.Context, pkg string, s *ExperimentPolicyState, decision ExperimentDecision, reason string, now time.Time) {
+	var err error
+	span, ctx := tracer.StartSpanFromContext(ctx, "experiment_policy")
+	defer func() { span.Finish(tracer.WithError(err)) }()
+	span.SetTag("package", pkg)
+	span.SetTag("version", s.Version)
+	span.SetTag("decision", string(decision))
+	span.SetTag("reason", reason)
+
+	log.Infof("Daemon: experiment policy %s experiment %s of package %s: %s", decision, s.Version, pkg, reason)
+	switch decision {
+	case ExperimentDecisionPromoted:
+		err = d.promoteExperiment(ctx, pkg)
+	case ExperimentDecisionRolledBack:
+		err = d.stopExperiment(ctx, pkg)
+	}
+	if err != nil {
+		log.Errorf("Daemon: experiment policy could not apply decision %s to package %s: %v", decision, pkg, err)
+		s.Reason = fmt.Sprintf("could not apply decision %s: %v", decision, err)
+		return
+	}
+	s.Decision = decision
+	s.DecidedAt = now
+	s.Reason = reason
+}
+
+// GetExperimentPolicyStates returns the state of the experiment policy for each package.
+func (d *daemonImpl) GetExperimentPolicyStates() map[string]ExperimentPolicyState {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	states := make(map[string]ExperimentPolicyState, len(d.policyStates))
+	for pkg, s := range d.policyStates {
+		states[pkg] = s
+	}
+	return states
+}
@@ -0,0 +1,322 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// for now the installer is not supported on windows
+//go:build !windows
+
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	configmock "github.com/DataDog/datadog-agent/pkg/config/mock"
+	"github.com/DataDog/datadog-agent/pkg/fleet/env"
+	"github.com/DataDog/datadog-agent/pkg/fleet/installer/repository"
+	"github.com/DataDog/datadog-agent/pkg/status/health"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// newTestHealthProbe returns a fake healthprobe server whose health can be toggled.
+func newTestHealthProbe(t *testing.T) (*httptest.Server, *atomic.Bool) {
+	healthy := &atomic.Bool{}
+	healthy.Store(true)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		status := health.Status{Healthy: []string{"forwarder"}}
+		if !healthy.Load() {
+			status = health.Status{Unhealthy: []string{"forwarder"}}
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		_ = json.NewEncoder(w).Encode(status)
+	}))
+	t.Cleanup(s.Close)
+	return s, healthy
+}
+
+func newTestPolicyDaemon(policy *experimentPolicy, state repository.State) (*daemonImpl, *testPackageManager) {
+	pm := &testPackageManager{}
+	pm.On("States").Return(map[string]repository.State{"datadog-agent": state, "datadog-apm-inject": state}, nil)
+	pm.On("State", "datadog-agent").Return(state, nil)
+	rc := &remoteConfig{client: newTestRemoteConfigClient()}
+	return newDaemon(rc, pm, &env.Env{}, policy), pm
+}
+
+func TestExperimentPolicyPromote(t *testing.T) {
+	probe, _ := newTestHealthProbe(t)
+	clock := &testClock{now: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)}
+	policy := &experimentPolicy{
+		packages:         []string{"datadog-agent"},
+		soakDuration:     30 * time.Minute,
+		failureThreshold: 3,
+		checks:           map[string][]healthCheck{"datadog-agent": {&httpHealthCheck{name: "healthprobe", url: probe.URL, client: probe.Client()}}},
+		now:              clock.Now,
+	}
+	d, pm := newTestPolicyDaemon(policy, repository.State{Stable: "7.54.0", Experiment: "7.55.0"})
+
+	d.evaluateExperiments(context.Background())
+	clock.Advance(20 * time.Minute)
+	d.evaluateExperiments(context.Background())
+	pm.AssertNotCalled(t, "PromoteExperiment", mock.Anything, mock.Anything)
+	assert.Equal(t, ExperimentDecisionNone, d.GetExperimentPolicyStates()["datadog-agent"].Decision)
+
+	pm.On("PromoteExperiment", mock.Anything, "datadog-agent").Return(nil).Once()
+	clock.Advance(10 * time.Minute)
+	d.evaluateExperiments(context.Background())
+
+	pm.AssertExpectations(t)
+	states := d.GetExperimentPolicyStates()
+	assert.Len(t, states, 1)
+	assert.Equal(t, ExperimentPolicyState{
+		Version:   "7.55.0",
+		StartedAt: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+		Decision:  ExperimentDecisionPromoted,
+		DecidedAt: time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC),
+		Reason:    "healthy for 30m0s",
+	}, states["datadog-agent"])
+}
+
+func TestExperimentPolicyRollback(t *testing.T) {
+	probe, healthy := newTestHealthProbe(t)
+	clock := &testClock{now: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)}
+	policy := &experimentPolicy{
+		packages:         []string{"datadog-agent"},
+		soakDuration:     30 * time.Minute,
+		failureThreshold: 2,
+		checks:           map[string][]healthCheck{"datadog-agent": {&httpHealthCheck{name: "healthprobe", url: probe.URL, client: probe.Client()}}},
+		now:              clock.Now,
+	}
+	d, pm := newTestPolicyDaemon(policy, repository.State{Stable: "7.54.0", Experiment: "7.55.0"})
+
+	// A single failure followed by a recovery resets the failure count
+	healthy.Store(false)
+	d.evaluateExperiments(context.Background())
+	assert.Equal(t, 1, d.GetExperimentPolicyStates()["datadog-agent"].Failures)
+	healthy.Store(true)
+	clock.Advance(time.Minute)
+	d.evaluateExperiments(context.Background())
+	assert.Equal(t, 0, d.GetExperimentPolicyStates()["datadog-agent"].Failures)
+
+	healthy.Store(false)
+	clock.Advance(time.Minute)
+	d.evaluateExperiments(context.Background())
+	pm.AssertNotCalled(t, "RemoveExperiment", mock.Anything, mock.Anything)
+
+	pm.On("RemoveExperiment", mock.Anything, "datadog-agent").Return(nil).Once()
+	clock.Advance(time.Minute)
+	d.evaluateExperiments(context.Background())
+
+	pm.AssertExpectations(t)
+	s := d.GetExperimentPolicyStates()["datadog-agent"]
+	assert.Equal(t, ExperimentDecisionRolledBack, s.Decision)
+	assert.Equal(t, 2, s.Failures)
+	assert.Equal(t, clock.Now(), s.DecidedAt)
+	assert.Equal(t, "health checks failed 2 times: healthprobe: unhealthy components: forwarder", s.Reason)
+}
+
+func TestExperimentPolicyDecisionError(t *testing.T) {
+	clock := &testClock{now: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)}
+	policy := &experimentPolicy{
+		packages:         []string{"datadog-agent"},
+		failureThreshold: 3,
+		now:              clock.Now,
+	}
+	d, pm := newTestPolicyDaemon(policy, repository.State{Stable: "7.54.0", Experiment: "7.55.0"})
+	pm.On("PromoteExperiment", mock.Anything, "datadog-agent").Return(errors.New("disk full")).Once()
+
+	d.evaluateExperiments(context.Background())
+
+	pm.AssertExpectations(t)
+	s := d.GetExperimentPolicyStates()["datadog-agent"]
+	assert.Equal(t, ExperimentDecisionNone, s.Decision)
+	assert.Contains(t, s.Reason, "could not apply decision promoted")
+}
+
+func TestExperimentPolicyPackageChecks(t *testing.T) {
+	probe, healthy := newTestHealthProbe(t)
+	clock := &testClock{now: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)}
+	policy := &experimentPolicy{
+		packages:         []string{"datadog-agent", "datadog-apm-inject"},
+		soakDuration:     30 * time.Minute,
+		failureThreshold: 3,
+		checks:           map[string][]healthCheck{"datadog-agent": {&httpHealthCheck{name: "healthprobe", url: probe.URL, client: probe.Client()}}},
+		now:              clock.Now,
+	}
+	d, pm := newTestPolicyDaemon(policy, repository.State{Stable: "7.54.0", Experiment: "7.55.0"})
+	pm.On("State", "datadog-apm-inject").Return(repository.State{Stable: "7.54.0", Experiment: "7.55.0"}, nil)
+
+	// The agent being unhealthy doesn't fail the experiments of other packages
+	healthy.Store(false)
+	d.evaluateExperiments(context.Background())
+	states := d.GetExperimentPolicyStates()
+	assert.Equal(t, 1, states["datadog-agent"].Failures)
+	assert.Equal(t, 0, states["datadog-apm-inject"].Failures)
+	assert.Empty(t, states["datadog-apm-inject"].Reason)
+}
+
+func TestExperimentPolicyDoesNotBlockRequests(t *testing.T) {
+	started := make(chan struct{})
+	stopped := make(chan struct{})
+	policy := &experimentPolicy{
+		packages:         []string{"datadog-agent"},
+		checkInterval:    time.Millisecond,
+		failureThreshold: 3,
+		checks: map[string][]healthCheck{"datadog-agent": {&funcHealthCheck{check: func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			close(stopped)
+			return ctx.Err()
+		}}}},
+		now: time.Now,
+	}
+	d, pm := newTestPolicyDaemon(policy, repository.State{Stable: "7.54.0", Experiment: "7.55.0"})
+	pm.On("PromoteExperiment", mock.Anything, "datadog-agent").Return(nil)
+	assert.NoError(t, d.Start(context.Background()))
+	<-started
+
+	// The daemon keeps handling requests while the health checks run
+	d.requestsWG.Add(1)
+	d.requests <- remoteAPIRequest{
+		ID:            "test-request",
+		Package:       "datadog-agent",
+		ExpectedState: expectedState{Stable: "7.54.0", Experiment: "7.55.0"},
+		Method:        methodPromoteExperiment,
+	}
+	d.requestsWG.Wait()
+	pm.AssertCalled(t, "PromoteExperiment", mock.Anything, "datadog-agent")
+
+	// Running health checks are canceled when the daemon stops
+	assert.NoError(t, d.Stop(context.Background()))
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("health check not canceled")
+	}
+}
+
+// funcHealthCheck is a health check running a function.
+type funcHealthCheck struct {
+	check func(ctx context.Context) error
+}
+
+func (c *funcHealthCheck) Name() string {
+	return "func"
+}
+
+func (c *funcHealthCheck) Check(ctx context.Context) error {
+	return c.check(ctx)
+}
+
+func TestNewExperimentPolicy(t *testing.T) {
+	cfg := configmock.New(t)
+	cfg.SetWithoutSource("installer.experiment_policy.enabled", true)
+	cfg.SetWithoutSource("installer.experiment_policy.agent_health", false)
+	cfg.SetWithoutSource("installer.experiment_policy.healthprobe", false)
+	cfg.SetWithoutSource("installer.experiment_policy.check_interval", "0s")
+	cfg.SetWithoutSource("installer.experiment_policy.systemd_units", map[string][]string{
+		"datadog-agent":      {"datadog-agent-exp.service"},
+		"datadog-apm-inject": {},
+	})
+
+	policy := newExperimentPolicy(cfg)
+	assert.Equal(t, defaultCheckInterval, policy.checkInterval)
+	assert.Len(t, policy.checks, 1)
+	assert.Len(t, policy.checks["datadog-agent"], 1)
+	assert.Equal(t, "crash_loop", policy.checks["datadog-agent"][0].Name())
+}
+
+func TestAgentHealthCheck(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(health.Status{Healthy: []string{"forwarder"}, Unhealthy: []string{"aggregator"}})
+	}))
+	defer s.Close()
+
+	check := &httpHealthCheck{name: "agent_health", url: s.URL, client: s.Client()}
+	assert.ErrorContains(t, check.Check(context.Background()), "unexpected status code 401")
+
+	check.authToken = func() (string, error) { return "token", nil }
+	assert.ErrorContains(t, check.Check(context.Background()), "unhealthy components: aggregator")
+}
+
+func TestCrashLoopHealthCheck(t *testing.T) {
+	units := map[string]map[string]string{
+		"datadog-agent-exp.service":       {"ActiveState": "active", "NRestarts": "0"},
+		"datadog-agent-trace-exp.service": {"ActiveState": "active", "NRestarts": "0"},
+	}
+	check := &crashLoopHealthCheck{
+		units:       []string{"datadog-agent-exp.service", "datadog-agent-trace-exp.service"},
+		maxRestarts: 2,
+		showUnit: func(_ context.Context, unit string) (map[string]string, error) {
+			return units[unit], nil
+		},
+	}
+	assert.NoError(t, check.Check(context.Background()))
+
+	units["datadog-agent-trace-exp.service"]["NRestarts"] = "2"
+	assert.NoError(t, check.Check(context.Background()))
+
+	units["datadog-agent-trace-exp.service"]["NRestarts"] = "3"
+	assert.EqualError(t, check.Check(context.Background()), "unit datadog-agent-trace-exp.service restarted 3 times")
+
+	units["datadog-agent-exp.service"]["ActiveState"] = "failed"
+	assert.EqualError(t, check.Check(context.Background()), "unit datadog-agent-exp.service failed")
+}
+
+func TestErrorRateHealthCheck(t *testing.T) {
+	errorCount := &atomic.Int64{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, "# TYPE logs__errors counter\nlogs__errors{source=\"a\"} %d\nlogs__errors{source=\"b\"} 1\n# TYPE other counter\nother 1000\n", errorCount.Load())
+	}))
+	defer s.Close()
+	clock := &testClock{now: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)}
+	check := &errorRateHealthCheck{
+		url:                s.URL,
+		metric:             "logs__errors",
+		maxErrorsPerMinute: 5,
+		client:             s.Client(),
+		now:                clock.Now,
+	}
+
+	// The first sample sets the baseline
+	errorCount.Store(100)
+	assert.NoError(t, check.Check(context.Background()))
+
+	errorCount.Store(110)
+	clock.Advance(2 * time.Minute)
+	assert.NoError(t, check.Check(context.Background()))
+
+	errorCount.Store(130)
+	clock.Advance(2 * time.Minute)
+	assert.EqualError(t, check.Check(context.Background()), "logs__errors increased by 10.00 per minute, above 5.00")
+
+	// Counter reset after a restart
+	errorCount.Store(0)
+	clock.Advance(time.Minute)
+	assert.NoError(t, check.Check(context.Background()))
+}
@@ -28,9 +28,10 @@ const (
 // StatusResponse is the response to the status endpoint.
 type StatusResponse struct {
 	APIResponse
-	Version            string                      `json:"version"`
-	Packages           map[string]repository.State `json:"packages"`
-	ApmInjectionStatus APMInjectionStatus          `json:"apm_injection_status"`
+	Version            string                           `json:"version"`
+	Packages           map[string]repository.State      `json:"packages"`
+	ApmInjectionStatus APMInjectionStatus               `json:"apm_injection_status"`
+	ExperimentPolicy   map[string]ExperimentPolicyState `json:"experiment_policy,omitempty"`
 }
 
 // APMInjectionStatus contains the instrumentation status of the APM injection.
@@ -134,6 +135,7 @@ func (l *localAPIImpl) status(w http.ResponseWriter, _ *http.Request) {
 		Version:            version.AgentVersion,
 		Packages:           packages,
 		ApmInjectionStatus: apmStatus,
+		ExperimentPolicy:   l.daemon.GetExperimentPolicyStates(),
 	}
 }
 
@@ -13,6 +13,7 @@ import (
 	"net"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/fleet/installer/repository"
 	"github.com/DataDog/datadog-agent/pkg/version"
@@ -70,6 +71,11 @@ func (m *testDaemon) GetAPMInjectionStatus() (APMInjectionStatus, error) {
 	return args.Get(0).(APMInjectionStatus), args.Error(1)
 }
 
+func (m *testDaemon) GetExperimentPolicyStates() map[string]ExperimentPolicyState {
+	args := m.Called()
+	return args.Get(0).(map[string]ExperimentPolicyState)
+}
+
 func (m *testDaemon) SetCatalog(catalog catalog) {
 	m.Called(catalog)
 }
@@ -113,6 +119,15 @@ func TestAPIStatus(t *testing.T) {
 	}
 	api.i.On("GetState").Return(installerState, nil)
 	api.i.On("GetAPMInjectionStatus").Return(APMInjectionStatus{}, nil)
+	policyStates := map[string]ExperimentPolicyState{
+		"pkg1": {
+			Version:   "2.0.0",
+			StartedAt: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+			Failures:  1,
+			Reason:    "healthprobe: unhealthy components: forwarder",
+		},
+	}
+	api.i.On("GetExperimentPolicyStates").Return(policyStates)
 
 	resp, err := api.c.Status()
 
@@ -120,6 +135,7 @@ func TestAPIStatus(t *testing.T) {
 	assert.Nil(t, resp.Error)
 	assert.Equal(t, version.AgentVersion, resp.Version)
 	assert.Equal(t, installerState, resp.Packages)
+	assert.Equal(t, policyStates, resp.ExperimentPolicy)
 }
 
 func TestAPIInstall(t *testing.T) {
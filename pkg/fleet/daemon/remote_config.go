@@ -6,7 +6,10 @@
 package daemon
 
 import (
+	"bytes"
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"net/url"
 	"strings"
@@ -76,6 +79,8 @@ type Package struct {
 
 type catalog struct {
 	Packages []Package `json:"packages"`
+	// SignatureKeys are the PEM encoded public keys trusted to sign the packages of the catalog.
+	SignatureKeys []string `json:"signature_keys,omitempty"`
 }
 
 func (c *catalog) getPackage(pkg string, version string, arch string, platform string) (Package, bool) {
@@ -108,7 +113,16 @@ func handleUpdaterCatalogDDUpdate(h handleCatalogUpdate) client.Handler {
 					return
 				}
 			}
+			for _, key := range catalog.SignatureKeys {
+				err := validateSignatureKey(key)
+				if err != nil {
+					log.Errorf("invalid signature key in catalog: %s", err)
+					applyStateCallback(configPath, state.ApplyStatus{State: state.ApplyStateError, Error: err.Error()})
+					return
+				}
+			}
 			mergedCatalog.Packages = append(mergedCatalog.Packages, catalog.Packages...)
+			mergedCatalog.SignatureKeys = append(mergedCatalog.SignatureKeys, catalog.SignatureKeys...)
 		}
 		err := h(mergedCatalog)
 		if err != nil {
@@ -150,6 +164,21 @@ func validatePackage(pkg Package) error {
 	return nil
 }
 
+func validateSignatureKey(key string) error {
+	block, rest := pem.Decode([]byte(key))
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return fmt.Errorf("signature key is not a PEM encoded public key")
+	}
+	if len(bytes.TrimSpace(rest)) != 0 {
+		return fmt.Errorf("signature key contains more than one key")
+	}
+	_, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("could not parse signature key: %w", err)
+	}
+	return nil
+}
+
 const (
 	methodStartExperiment   = "start_experiment"
 	methodStopExperiment    = "stop_experiment"
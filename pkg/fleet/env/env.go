@@ -28,6 +28,12 @@ const (
 	envAgentMajorVersion     = "DD_AGENT_MAJOR_VERSION"
 	envAgentMinorVersion     = "DD_AGENT_MINOR_VERSION"
 	envApmLanguages          = "DD_APM_INSTRUMENTATION_LANGUAGES"
+	envSignatureMode         = "DD_INSTALLER_SIGNATURE_MODE"
+	envSignatureKeyFiles     = "DD_INSTALLER_SIGNATURE_PUBLIC_KEY_FILES"
+	envSignatureCatalogKeys  = "DD_INSTALLER_SIGNATURE_CATALOG_KEYS"
+	envMirrorURL             = "DD_INSTALLER_MIRROR_URL"
+	envMirrorAuth            = "DD_INSTALLER_MIRROR_AUTH"
+	envMirrorFallback        = "DD_INSTALLER_MIRROR_FALLBACK"
 )
 
 var defaultEnv = Env{
@@ -73,6 +79,20 @@ type Env struct {
 	AgentMajorVersion string
 	AgentMinorVersion string
 
+	// SignatureMode is the signature verification mode of the packages: disabled, warn or enforce.
+	SignatureMode string
+	// SignaturePublicKeyFiles are the paths to the PEM encoded public keys trusted to sign packages.
+	SignaturePublicKeyFiles []string
+	// SignatureCatalogKeys are the PEM encoded public keys delivered with the catalog.
+	SignatureCatalogKeys string
+
+	// Mirror is the URL of the mirror packages are resolved from: oci://<registry>/<prefix> or file://<layout path>.
+	Mirror string
+	// MirrorAuth is the authentication method of the mirror registry.
+	MirrorAuth string
+	// MirrorFallback is the rule to fall back to the package registry: none, missing or error.
+	MirrorFallback string
+
 	InstallScript InstallScriptEnv
 }
 
@@ -96,6 +116,14 @@ func FromEnv() *Env {
 		AgentMajorVersion: os.Getenv(envAgentMajorVersion),
 		AgentMinorVersion: os.Getenv(envAgentMinorVersion),
 
+		SignatureMode:           os.Getenv(envSignatureMode),
+		SignaturePublicKeyFiles: listFromEnv(envSignatureKeyFiles),
+		SignatureCatalogKeys:    os.Getenv(envSignatureCatalogKeys),
+
+		Mirror:         os.Getenv(envMirrorURL),
+		MirrorAuth:     os.Getenv(envMirrorAuth),
+		MirrorFallback: os.Getenv(envMirrorFallback),
+
 		InstallScript: installScriptEnvFromEnv(),
 	}
 }
@@ -108,6 +136,13 @@ func FromConfig(config config.Reader) *Env {
 		RemoteUpdates:        config.GetBool("remote_updates"),
 		RegistryOverride:     config.GetString("installer.registry.url"),
 		RegistryAuthOverride: config.GetString("installer.registry.auth"),
+
+		SignatureMode:           config.GetString("installer.signature.mode"),
+		SignaturePublicKeyFiles: config.GetStringSlice("installer.signature.public_key_files"),
+
+		Mirror:         config.GetString("installer.mirror.url"),
+		MirrorAuth:     config.GetString("installer.mirror.auth"),
+		MirrorFallback: config.GetString("installer.mirror.fallback"),
 	}
 }
 
@@ -141,6 +176,24 @@ func (e *Env) ToEnv() []string {
 		slices.Sort(libraries)
 		env = append(env, envApmLibraries+"="+strings.Join(libraries, ","))
 	}
+	if e.SignatureMode != "" {
+		env = append(env, envSignatureMode+"="+e.SignatureMode)
+	}
+	if len(e.SignaturePublicKeyFiles) > 0 {
+		env = append(env, envSignatureKeyFiles+"="+strings.Join(e.SignaturePublicKeyFiles, ","))
+	}
+	if e.SignatureCatalogKeys != "" {
+		env = append(env, envSignatureCatalogKeys+"="+e.SignatureCatalogKeys)
+	}
+	if e.Mirror != "" {
+		env = append(env, envMirrorURL+"="+e.Mirror)
+	}
+	if e.MirrorAuth != "" {
+		env = append(env, envMirrorAuth+"="+e.MirrorAuth)
+	}
+	if e.MirrorFallback != "" {
+		env = append(env, envMirrorFallback+"="+e.MirrorFallback)
+	}
 	env = append(env, overridesByNameToEnv(envRegistryURL, e.RegistryOverrideByImage)...)
 	env = append(env, overridesByNameToEnv(envRegistryAuth, e.RegistryAuthOverrideByImage)...)
 	env = append(env, overridesByNameToEnv(envDefaultPackageInstall, e.DefaultPackagesInstallOverride)...)
@@ -205,6 +258,16 @@ func overridesByNameToEnv[T any](envPrefix string, overridesByPackage map[string
 	return env
 }
 
+func listFromEnv(env string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(env), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func getEnvOrDefault(env string, defaultValue string) string {
 	value := os.Getenv(env)
 	if value == "" {
@@ -53,6 +53,12 @@ func TestFromEnv(t *testing.T) {
 				envDefaultPackageVersion + "_ANOTHER_PACKAGE": "4.5.6",
 				envApmLibraries:                               "java,dotnet:latest,ruby:1.2",
 				envApmInstrumentationEnabled:                  "all",
+				envSignatureMode:                              "enforce",
+				envSignatureKeyFiles:                          "/etc/datadog/a.pem, /etc/datadog/b.pem",
+				envSignatureCatalogKeys:                       "-----BEGIN PUBLIC KEY-----",
+				envMirrorURL:                                  "oci://mirror.example.com/datadog",
+				envMirrorAuth:                                 "ecr",
+				envMirrorFallback:                             "missing",
 			},
 			expected: &Env{
 				APIKey:               "123456",
@@ -81,6 +87,12 @@ func TestFromEnv(t *testing.T) {
 					"dotnet": "latest",
 					"ruby":   "1.2",
 				},
+				SignatureMode:           "enforce",
+				SignaturePublicKeyFiles: []string{"/etc/datadog/a.pem", "/etc/datadog/b.pem"},
+				SignatureCatalogKeys:    "-----BEGIN PUBLIC KEY-----",
+				Mirror:                  "oci://mirror.example.com/datadog",
+				MirrorAuth:              "ecr",
+				MirrorFallback:          "missing",
 				InstallScript: InstallScriptEnv{
 					APMInstrumentationEnabled: APMInstrumentationEnabledAll,
 				},
@@ -189,6 +201,11 @@ func TestToEnv(t *testing.T) {
 					"dotnet": "latest",
 					"ruby":   "1.2",
 				},
+				SignatureMode:           "warn",
+				SignaturePublicKeyFiles: []string{"/etc/datadog/a.pem", "/etc/datadog/b.pem"},
+				SignatureCatalogKeys:    "-----BEGIN PUBLIC KEY-----",
+				Mirror:                  "file:///opt/datadog-mirror",
+				MirrorFallback:          "error",
 			},
 			expected: []string{
 				"DD_API_KEY=123456",
@@ -197,6 +214,11 @@ func TestToEnv(t *testing.T) {
 				"DD_INSTALLER_REGISTRY_URL=registry.example.com",
 				"DD_INSTALLER_REGISTRY_AUTH=auth",
 				"DD_APM_INSTRUMENTATION_LIBRARIES=dotnet:latest,java,ruby:1.2",
+				"DD_INSTALLER_SIGNATURE_MODE=warn",
+				"DD_INSTALLER_SIGNATURE_PUBLIC_KEY_FILES=/etc/datadog/a.pem,/etc/datadog/b.pem",
+				"DD_INSTALLER_SIGNATURE_CATALOG_KEYS=-----BEGIN PUBLIC KEY-----",
+				"DD_INSTALLER_MIRROR_URL=file:///opt/datadog-mirror",
+				"DD_INSTALLER_MIRROR_FALLBACK=error",
 				"DD_INSTALLER_REGISTRY_URL_IMAGE=another.registry.example.com",
 				"DD_INSTALLER_REGISTRY_URL_ANOTHER_IMAGE=yet.another.registry.example.com",
 				"DD_INSTALLER_REGISTRY_AUTH_IMAGE=another.auth",
@@ -179,6 +179,10 @@ func (i *installerImpl) Install(ctx context.Context, url string, args []string)
 	if err != nil {
 		return fmt.Errorf("could not create repository: %w", err)
 	}
+	err = i.repositories.Get(pkg.Name).SetSignature(pkg.Version, packageSignature(pkg))
+	if err != nil {
+		return fmt.Errorf("could not record package signature: %w", err)
+	}
 	err = i.setupPackage(ctx, pkg.Name, args)
 	if err != nil {
 		return fmt.Errorf("could not setup package: %w", err)
@@ -225,6 +229,10 @@ func (i *installerImpl) InstallExperiment(ctx context.Context, url string) error
 	if err != nil {
 		return fmt.Errorf("could not set experiment: %w", err)
 	}
+	err = repository.SetSignature(pkg.Version, packageSignature(pkg))
+	if err != nil {
+		return fmt.Errorf("could not record package signature: %w", err)
+	}
 	return i.startExperiment(ctx, pkg.Name)
 }
 
@@ -361,6 +369,18 @@ func (i *installerImpl) UninstrumentAPMInjector(ctx context.Context, method stri
 	return nil
 }
 
+// packageSignature returns the signature verification of the downloaded package to record in its repository.
+func packageSignature(pkg *oci.DownloadedPackage) repository.Signature {
+	return repository.Signature{
+		Mode:   string(pkg.Signature.Mode),
+		Status: string(pkg.Signature.Status),
+		Format: string(pkg.Signature.Format),
+		Digest: pkg.Signature.Digest,
+		KeyID:  pkg.Signature.KeyID,
+		Error:  pkg.Signature.Error,
+	}
+}
+
 func (i *installerImpl) startExperiment(ctx context.Context, pkg string) error {
 	switch pkg {
 	case packageDatadogAgent:
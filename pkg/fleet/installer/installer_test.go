@@ -65,6 +65,61 @@ func TestInstallStable(t *testing.T) {
 	fixtures.AssertEqualFS(t, s.ConfigFS(fixtures.FixtureSimpleV1), installer.ConfigFS(fixtures.FixtureSimpleV1))
 }
 
+func TestInstallSigned(t *testing.T) {
+	s := fixtures.NewServer(t)
+	installer := newTestPackageManager(t, s, t.TempDir(), t.TempDir())
+	key := fixtures.NewSigningKey(t)
+	installer.downloader = oci.NewDownloader(&env.Env{
+		SignatureMode:        string(oci.SignatureModeEnforce),
+		SignatureCatalogKeys: string(key.PublicKeyPEM()),
+	}, s.Client())
+	s.SignPackage(fixtures.FixtureSimpleV1, key, fixtures.SignatureCosign)
+
+	err := installer.Install(testCtx, s.PackageURL(fixtures.FixtureSimpleV1), nil)
+	assert.NoError(t, err)
+	err = installer.InstallExperiment(testCtx, s.PackageURL(fixtures.FixtureSimpleV2))
+	assert.ErrorContains(t, err, "refusing to extract layers of unverified package")
+
+	r := installer.repositories.Get(fixtures.FixtureSimpleV1.Package)
+	state, err := r.GetState()
+	assert.NoError(t, err)
+	assert.Equal(t, fixtures.FixtureSimpleV1.Version, state.Stable)
+	assert.False(t, state.HasExperiment())
+	if assert.NotNil(t, state.StableSignature) {
+		assert.Equal(t, "enforce", state.StableSignature.Mode)
+		assert.Equal(t, "verified", state.StableSignature.Status)
+		assert.Equal(t, "cosign", state.StableSignature.Format)
+		assert.Equal(t, key.KeyID(), state.StableSignature.KeyID)
+	}
+	fixtures.AssertEqualFS(t, s.PackageFS(fixtures.FixtureSimpleV1), r.StableFS())
+}
+
+func TestInstallFromMirror(t *testing.T) {
+	s := fixtures.NewServer(t)
+	installer := newTestPackageManager(t, s, t.TempDir(), t.TempDir())
+	mirror := "file://" + filepath.Join(t.TempDir(), "mirror")
+	err := Mirror(testCtx, &env.Env{}, MirrorCatalog{Packages: []MirrorPackage{
+		{Name: fixtures.FixtureSimpleV1.Package, Version: fixtures.FixtureSimpleV1.Version, URL: s.PackageURL(fixtures.FixtureSimpleV1)},
+		{Name: fixtures.FixtureSimpleV1.Package, Version: fixtures.FixtureSimpleV1.Version, URL: s.PackageURL(fixtures.FixtureSimpleV1)},
+	}}, mirror)
+	assert.NoError(t, err)
+	installer.downloader = oci.NewDownloader(&env.Env{Mirror: mirror}, s.Client())
+	// The registry of the packages is never reached
+	s.Close()
+
+	err = installer.Install(testCtx, s.PackageURL(fixtures.FixtureSimpleV1), nil)
+	assert.NoError(t, err)
+	err = installer.InstallExperiment(testCtx, s.PackageURL(fixtures.FixtureSimpleV2))
+	assert.ErrorIs(t, err, oci.ErrNotInMirror)
+
+	r := installer.repositories.Get(fixtures.FixtureSimpleV1.Package)
+	state, err := r.GetState()
+	assert.NoError(t, err)
+	assert.Equal(t, fixtures.FixtureSimpleV1.Version, state.Stable)
+	assert.False(t, state.HasExperiment())
+	fixtures.AssertEqualFS(t, s.PackageFS(fixtures.FixtureSimpleV1), r.StableFS())
+}
+
 func TestInstallExperiment(t *testing.T) {
 	s := fixtures.NewServer(t)
 	installer := newTestPackageManager(t, s, t.TempDir(), t.TempDir())
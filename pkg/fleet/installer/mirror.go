@@ -0,0 +1,65 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package installer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
+
+	"github.com/DataDog/datadog-agent/pkg/fleet/env"
+	"github.com/DataDog/datadog-agent/pkg/fleet/internal/oci"
+)
+
+// MirrorCatalog is the list of packages copied to a mirror. It uses the format of the
+// remote updates catalog, so a catalog can be mirrored as is.
+type MirrorCatalog struct {
+	Packages []MirrorPackage `json:"packages"`
+}
+
+// MirrorPackage is a package copied to a mirror. Without URL, the package is pulled
+// from the default registry of the site.
+type MirrorPackage struct {
+	Name    string `json:"package"`
+	Version string `json:"version"`
+	URL     string `json:"url,omitempty"`
+}
+
+// Mirror copies the packages of the catalog and their signatures to the mirror,
+// either a registry (oci://<registry>/<prefix>) or an OCI layout (file://<path>).
+func Mirror(ctx context.Context, env *env.Env, catalog MirrorCatalog, mirror string) error {
+	downloader := oci.NewDownloader(env, http.DefaultClient)
+	mirrored := map[string]struct{}{}
+	for _, p := range catalog.Packages {
+		url := p.URL
+		if url == "" {
+			if p.Name == "" || p.Version == "" {
+				return fmt.Errorf("package without URL must have a name and a version: %+v", p)
+			}
+			url = oci.PackageURL(env, p.Name, p.Version)
+		}
+		// Catalogs list the same package URL once per platform
+		if _, ok := mirrored[url]; ok {
+			continue
+		}
+		err := mirrorPackage(ctx, downloader, url, mirror)
+		if err != nil {
+			return fmt.Errorf("could not mirror package %s version %s: %w", p.Name, p.Version, err)
+		}
+		mirrored[url] = struct{}{}
+	}
+	return nil
+}
+
+func mirrorPackage(ctx context.Context, downloader *oci.Downloader, url string, mirror string) (err error) {
+	span, ctx := tracer.StartSpanFromContext(ctx, "mirror_package")
+	defer func() { span.Finish(tracer.WithError(err)) }()
+	span.SetTag("url", url)
+	span.SetTag("mirror", mirror)
+	return downloader.Mirror(ctx, url, mirror)
+}
@@ -8,6 +8,7 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -16,6 +17,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 
 	"github.com/DataDog/gopsutil/process"
 
@@ -26,6 +28,9 @@ const (
 	previousVersionLink   = "previous"
 	stableVersionLink     = "stable"
 	experimentVersionLink = "experiment"
+
+	// signatureFileSuffix is the suffix of the files recording the signature verification of the packages.
+	signatureFileSuffix = ".signature.json"
 )
 
 var (
@@ -37,10 +42,13 @@ var (
 // On disk the repository is structured as follows:
 // .
 // ├── 7.50.0
+// ├── 7.50.0.signature.json
 // ├── 7.51.0
+// ├── 7.51.0.signature.json
 // ├── stable -> 7.50.0 (symlink)
 // └── experiment -> 7.51.0 (symlink)
 //
+// where the optional .signature.json files record the signature verification of the packages,
 // and the locks directory (if any) is structured as follows:
 // .
 // ├── 7.50.0
@@ -66,6 +74,21 @@ type Repository struct {
 type State struct {
 	Stable     string
 	Experiment string
+
+	// StableSignature and ExperimentSignature are the signature verifications of the packages,
+	// nil if they weren't recorded at install time.
+	StableSignature     *Signature `json:",omitempty"`
+	ExperimentSignature *Signature `json:",omitempty"`
+}
+
+// Signature is the result of the signature verification of a package version.
+type Signature struct {
+	Mode   string `json:"mode"`
+	Status string `json:"status"`
+	Format string `json:"format,omitempty"`
+	Digest string `json:"digest,omitempty"`
+	KeyID  string `json:"key_id,omitempty"`
+	Error  string `json:"error,omitempty"`
 }
 
 // HasStable returns true if the repository has a stable package.
@@ -97,10 +120,61 @@ func (r *Repository) GetState() (State, error) {
 	if err != nil {
 		return State{}, err
 	}
-	return State{
+	state := State{
 		Stable:     repository.stable.Target(),
 		Experiment: repository.experiment.Target(),
-	}, nil
+	}
+	if state.HasStable() {
+		state.StableSignature, err = r.readSignature(state.Stable)
+		if err != nil {
+			return State{}, err
+		}
+	}
+	if state.HasExperiment() {
+		state.ExperimentSignature, err = r.readSignature(state.Experiment)
+		if err != nil {
+			return State{}, err
+		}
+	}
+	return state, nil
+}
+
+// SetSignature records the signature verification of the given package version of the repository.
+func (r *Repository) SetSignature(name string, signature Signature) error {
+	if _, err := os.Stat(filepath.Join(r.rootPath, name)); err != nil {
+		return fmt.Errorf("could not find package %s: %w", name, err)
+	}
+	data, err := json.Marshal(signature)
+	if err != nil {
+		return fmt.Errorf("could not marshal signature: %w", err)
+	}
+	// Write then rename so that a partial file is never read
+	path := filepath.Join(r.rootPath, name+signatureFileSuffix)
+	err = os.WriteFile(path+".tmp", data, 0644)
+	if err != nil {
+		return fmt.Errorf("could not write signature: %w", err)
+	}
+	err = os.Rename(path+".tmp", path)
+	if err != nil {
+		return fmt.Errorf("could not write signature: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) readSignature(name string) (*Signature, error) {
+	data, err := os.ReadFile(filepath.Join(r.rootPath, name+signatureFileSuffix))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read signature of package %s: %w", name, err)
+	}
+	var signature Signature
+	err = json.Unmarshal(data, &signature)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse signature of package %s: %w", name, err)
+	}
+	return &signature, nil
 }
 
 // Create creates a fresh new repository at the given root path
@@ -393,6 +467,18 @@ func (r *repositoryFiles) cleanup(_ context.Context) error {
 			continue
 		}
 
+		// Signature files are kept as long as their package is
+		if name, ok := strings.CutSuffix(file.Name(), signatureFileSuffix); ok {
+			isStable = r.stable.Exists() && r.stable.Target() == name
+			isExperiment = r.experiment.Exists() && r.experiment.Target() == name
+			if !isStable && !isExperiment && !r.lockedPackages[name] {
+				if err := os.Remove(filepath.Join(r.rootPath, file.Name())); err != nil {
+					log.Errorf("could not remove package %s signature, will retry: %v", name, err)
+				}
+			}
+			continue
+		}
+
 		if !r.lockedPackages[file.Name()] {
 			// Package isn't locked, remove it
 			pkgRepositoryPath := filepath.Join(r.rootPath, file.Name())
@@ -194,6 +194,43 @@ func TestDeleteExperimentWithLockedPackage(t *testing.T) {
 	assert.FileExists(t, path.Join(repository.locksPath, "v2", fmt.Sprint(os.Getpid())))
 }
 
+func TestSignature(t *testing.T) {
+	dir := t.TempDir()
+	repository := createTestRepository(t, dir, "v1")
+	experimentDownloadPackagePath := createTestDownloadedPackage(t, dir, "v2")
+	stableSignature := Signature{Mode: "enforce", Status: "verified", Format: "cosign", Digest: "sha256:1234", KeyID: "SHA256:5678"}
+	experimentSignature := Signature{Mode: "warn", Status: "unverified", Error: "no signature found for the package"}
+
+	state, err := repository.GetState()
+	assert.NoError(t, err)
+	assert.Nil(t, state.StableSignature)
+
+	err = repository.SetSignature("v1", stableSignature)
+	assert.NoError(t, err)
+	err = repository.SetSignature("v2", experimentSignature)
+	assert.Error(t, err)
+	err = repository.SetExperiment(testCtx, "v2", experimentDownloadPackagePath)
+	assert.NoError(t, err)
+	err = repository.SetSignature("v2", experimentSignature)
+	assert.NoError(t, err)
+
+	state, err = repository.GetState()
+	assert.NoError(t, err)
+	assert.Equal(t, State{
+		Stable:              "v1",
+		Experiment:          "v2",
+		StableSignature:     &stableSignature,
+		ExperimentSignature: &experimentSignature,
+	}, state)
+
+	err = repository.PromoteExperiment(testCtx)
+	assert.NoError(t, err)
+	state, err = repository.GetState()
+	assert.NoError(t, err)
+	assert.Equal(t, State{Stable: "v2", StableSignature: &experimentSignature}, state)
+	assert.NoFileExists(t, path.Join(repository.rootPath, "v1"+signatureFileSuffix))
+}
+
 func TestLoadRepositories(t *testing.T) {
 	rootDir := t.TempDir()
 	runDir := t.TempDir()
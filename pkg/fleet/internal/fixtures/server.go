@@ -67,7 +67,7 @@ var (
 var fixturesFS embed.FS
 
 func extractLayoutsAndBuildRegistry(t *testing.T, layoutsDir string) *httptest.Server {
-	s := httptest.NewServer(registry.New())
+	s := httptest.NewServer(registry.New(registry.WithReferrersSupport(true)))
 	for _, f := range ociFixtures {
 		layoutDir := path.Join(layoutsDir, f.layoutPath)
 		file, err := fixturesFS.Open(f.layoutPath)
@@ -160,3 +160,10 @@ func (s *Server) URL() string {
 func (s *Server) Close() {
 	s.s.Close()
 }
+
+// NewRegistry starts an empty in-process registry, to use as a mirror, and returns its host.
+func NewRegistry(t *testing.T) string {
+	s := httptest.NewServer(registry.New(registry.WithReferrersSupport(true)))
+	t.Cleanup(s.Close)
+	return strings.TrimPrefix(s.URL, "http://")
+}
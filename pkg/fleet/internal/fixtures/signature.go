@@ -0,0 +1,212 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package fixtures
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"path"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	oci "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/require"
+)
+
+// SignatureFormat is the format of the signatures attached to the fixtures.
+type SignatureFormat int
+
+const (
+	// SignatureCosign is a cosign simple signing signature stored under the sha256-<hex>.sig tag.
+	SignatureCosign SignatureFormat = iota
+	// SignatureBundle is a sigstore bundle with a message signature attached as a referrer.
+	SignatureBundle
+	// SignatureAttestation is a sigstore bundle with a DSSE in-toto attestation attached as a referrer.
+	SignatureAttestation
+)
+
+const (
+	cosignMediaType     types.MediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	bundleMediaType     types.MediaType = "application/vnd.dev.sigstore.bundle.v0.3+json"
+	inTotoPayloadType                   = "application/vnd.in-toto+json"
+	layoutRefAnnotation                 = "org.opencontainers.image.ref.name"
+)
+
+// SigningKey is an ECDSA P-256 key generated to sign the fixtures.
+type SigningKey struct {
+	key *ecdsa.PrivateKey
+}
+
+// NewSigningKey generates a new signing key.
+func NewSigningKey(t *testing.T) *SigningKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return &SigningKey{key: key}
+}
+
+// PublicKeyPEM returns the PEM encoded public key.
+func (k *SigningKey) PublicKeyPEM() []byte {
+	der, err := x509.MarshalPKIXPublicKey(&k.key.PublicKey)
+	if err != nil {
+		panic(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+// KeyID returns the SHA256 fingerprint of the public key.
+func (k *SigningKey) KeyID() string {
+	der, err := x509.MarshalPKIXPublicKey(&k.key.PublicKey)
+	if err != nil {
+		panic(err)
+	}
+	fingerprint := sha256.Sum256(der)
+	return "SHA256:" + hex.EncodeToString(fingerprint[:])
+}
+
+func (k *SigningKey) sign(message []byte) []byte {
+	digest := sha256.Sum256(message)
+	signature, err := ecdsa.SignASN1(rand.Reader, k.key, digest[:])
+	if err != nil {
+		panic(err)
+	}
+	return signature
+}
+
+// SignPackage signs the fixture with the given key. In the registry the signature covers the
+// package index, in the OCI layout it covers the package image as the index includes the signature.
+func (s *Server) SignPackage(f Fixture, key *SigningKey, format SignatureFormat) {
+	t := s.t
+
+	serverURL, err := url.Parse(s.s.URL)
+	require.NoError(t, err)
+	repository, err := name.NewRepository(path.Join(serverURL.Host, f.Package))
+	require.NoError(t, err)
+	index, err := remote.Index(repository.Digest(f.indexDigest))
+	require.NoError(t, err)
+	signature, tag := signatureArtifact(t, index, key, format)
+	if tag != "" {
+		require.NoError(t, remote.Write(repository.Tag(tag), signature))
+	} else {
+		require.NoError(t, remote.Write(repository.Digest(digestOf(t, signature).String()), signature))
+	}
+
+	layoutPath, err := layout.FromPath(path.Join(s.layoutsDir, f.layoutPath))
+	require.NoError(t, err)
+	layoutIndex, err := layoutPath.ImageIndex()
+	require.NoError(t, err)
+	indexManifest, err := layoutIndex.IndexManifest()
+	require.NoError(t, err)
+	image, err := layoutIndex.Image(indexManifest.Manifests[0].Digest)
+	require.NoError(t, err)
+	signature, tag = signatureArtifact(t, image, key, format)
+	if tag != "" {
+		require.NoError(t, layoutPath.AppendImage(signature, layout.WithAnnotations(map[string]string{layoutRefAnnotation: tag})))
+	} else {
+		require.NoError(t, layoutPath.WriteImage(signature))
+		manifest, err := signature.RawManifest()
+		require.NoError(t, err)
+		mediaType, err := signature.MediaType()
+		require.NoError(t, err)
+		require.NoError(t, layoutPath.AppendDescriptor(oci.Descriptor{
+			MediaType:    mediaType,
+			Size:         int64(len(manifest)),
+			Digest:       digestOf(t, signature),
+			ArtifactType: string(bundleMediaType),
+		}))
+	}
+}
+
+type signedManifest interface {
+	Digest() (oci.Hash, error)
+	MediaType() (types.MediaType, error)
+	RawManifest() ([]byte, error)
+}
+
+func digestOf(t *testing.T, m signedManifest) oci.Hash {
+	digest, err := m.Digest()
+	require.NoError(t, err)
+	return digest
+}
+
+// signatureArtifact returns the signature of the manifest, and the tag it is stored under for cosign signatures.
+func signatureArtifact(t *testing.T, signed signedManifest, key *SigningKey, format SignatureFormat) (oci.Image, string) {
+	digest := digestOf(t, signed)
+	raw, err := signed.RawManifest()
+	require.NoError(t, err)
+	mediaType, err := signed.MediaType()
+	require.NoError(t, err)
+
+	if format == SignatureCosign {
+		payload, err := json.Marshal(map[string]interface{}{
+			"critical": map[string]interface{}{
+				"identity": map[string]string{"docker-reference": "fixtures"},
+				"image":    map[string]string{"docker-manifest-digest": digest.String()},
+				"type":     "cosign container image signature",
+			},
+			"optional": nil,
+		})
+		require.NoError(t, err)
+		image, err := mutate.Append(empty.Image, mutate.Addendum{
+			Layer:       static.NewLayer(payload, cosignMediaType),
+			Annotations: map[string]string{"dev.cosignproject.cosign/signature": base64.StdEncoding.EncodeToString(key.sign(payload))},
+		})
+		require.NoError(t, err)
+		return mutate.MediaType(image, types.OCIManifestSchema1), fmt.Sprintf("%s-%s.sig", digest.Algorithm, digest.Hex)
+	}
+
+	var bundle map[string]interface{}
+	if format == SignatureBundle {
+		rawDigest, err := hex.DecodeString(digest.Hex)
+		require.NoError(t, err)
+		bundle = map[string]interface{}{
+			"messageSignature": map[string]interface{}{
+				"messageDigest": map[string]interface{}{"algorithm": "SHA2_256", "digest": rawDigest},
+				"signature":     key.sign(raw),
+			},
+		}
+	} else {
+		statement, err := json.Marshal(map[string]interface{}{
+			"_type":         "https://in-toto.io/Statement/v1",
+			"subject":       []interface{}{map[string]interface{}{"name": "fixtures", "digest": map[string]string{digest.Algorithm: digest.Hex}}},
+			"predicateType": "https://slsa.dev/provenance/v1",
+			"predicate":     map[string]interface{}{},
+		})
+		require.NoError(t, err)
+		pae := fmt.Sprintf("DSSEv1 %d %s %d %s", len(inTotoPayloadType), inTotoPayloadType, len(statement), statement)
+		bundle = map[string]interface{}{
+			"dsseEnvelope": map[string]interface{}{
+				"payload":     statement,
+				"payloadType": inTotoPayloadType,
+				"signatures":  []interface{}{map[string]interface{}{"sig": key.sign([]byte(pae))}},
+			},
+		}
+	}
+	bundle["mediaType"] = string(bundleMediaType)
+	bundle["verificationMaterial"] = map[string]interface{}{"publicKey": map[string]string{"hint": key.KeyID()}}
+	rawBundle, err := json.Marshal(bundle)
+	require.NoError(t, err)
+
+	image, err := mutate.Append(empty.Image, mutate.Addendum{Layer: static.NewLayer(rawBundle, bundleMediaType)})
+	require.NoError(t, err)
+	image = mutate.ConfigMediaType(mutate.MediaType(image, types.OCIManifestSchema1), bundleMediaType)
+	subject := mutate.Subject(image, oci.Descriptor{MediaType: mediaType, Size: int64(len(raw)), Digest: digest})
+	return subject.(oci.Image), ""
+}
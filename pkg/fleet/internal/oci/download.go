@@ -60,10 +60,11 @@ const (
 
 // DownloadedPackage is the downloaded package.
 type DownloadedPackage struct {
-	Image   oci.Image
-	Name    string
-	Version string
-	Size    uint64
+	Image     oci.Image
+	Name      string
+	Version   string
+	Size      uint64
+	Signature SignatureVerification
 }
 
 // Downloader is the Downloader used by the installer to download packages.
@@ -87,18 +88,31 @@ func (d *Downloader) Download(ctx context.Context, packageURL string) (*Download
 	if err != nil {
 		return nil, fmt.Errorf("could not parse package URL: %w", err)
 	}
-	var image oci.Image
+	var index oci.ImageIndex
+	var signatures signatureSource
 	switch url.Scheme {
 	case "oci":
-		image, err = d.downloadRegistry(ctx, strings.TrimPrefix(packageURL, "oci://"))
+		if d.env.Mirror == "" {
+			index, signatures, err = d.downloadRegistry(ctx, strings.TrimPrefix(packageURL, "oci://"))
+			break
+		}
+		index, signatures, err = d.downloadMirror(ctx, packageURL)
+		if err != nil && d.shouldFallback(err) {
+			log.Warnf("Could not download package from mirror %s, falling back to %s: %v", d.env.Mirror, packageURL, err)
+			index, signatures, err = d.downloadRegistry(ctx, strings.TrimPrefix(packageURL, "oci://"))
+		}
 	case "file":
-		image, err = d.downloadFile(url.Path)
+		index, signatures, err = d.downloadFile(url.Path)
 	default:
 		return nil, fmt.Errorf("unsupported package URL scheme: %s", url.Scheme)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("could not download package from %s: %w", packageURL, err)
 	}
+	image, err := d.downloadIndex(index)
+	if err != nil {
+		return nil, fmt.Errorf("could not download package from %s: %w", packageURL, err)
+	}
 	manifest, err := image.Manifest()
 	if err != nil {
 		return nil, fmt.Errorf("could not get image manifest: %w", err)
@@ -119,12 +133,20 @@ func (d *Downloader) Download(ctx context.Context, packageURL string) (*Download
 			return nil, fmt.Errorf("could not parse package size: %w", err)
 		}
 	}
+	signature := d.verifySignature(ctx, signatures, index, image)
+	switch signature.Status {
+	case SignatureStatusVerified:
+		log.Infof("Verified signature of package %s version %s with key %s", name, version, signature.KeyID)
+	case SignatureStatusUnverified:
+		log.Warnf("Could not verify signature of package %s version %s: %s", name, version, signature.Error)
+	}
 	log.Debugf("Successfully downloaded package from %s", packageURL)
 	return &DownloadedPackage{
-		Image:   image,
-		Name:    name,
-		Version: version,
-		Size:    size,
+		Image:     image,
+		Name:      name,
+		Version:   version,
+		Size:      size,
+		Signature: signature,
 	}, nil
 }
 
@@ -178,29 +200,30 @@ func getRefAndKeychain(env *env.Env, url string) urlWithKeychain {
 	}
 }
 
-func (d *Downloader) downloadRegistry(ctx context.Context, url string) (oci.Image, error) {
+func (d *Downloader) downloadRegistry(ctx context.Context, url string) (oci.ImageIndex, signatureSource, error) {
 	refAndKeychain := getRefAndKeychain(d.env, url)
 	ref, err := name.ParseReference(refAndKeychain.ref)
 	if err != nil {
-		return nil, fmt.Errorf("could not parse reference: %w", err)
+		return nil, nil, fmt.Errorf("could not parse reference: %w", err)
 	}
-	index, err := remote.Index(ref, remote.WithContext(ctx), remote.WithAuthFromKeychain(refAndKeychain.keychain), remote.WithTransport(httptrace.WrapRoundTripper(d.client.Transport)))
+	options := []remote.Option{remote.WithAuthFromKeychain(refAndKeychain.keychain), remote.WithTransport(httptrace.WrapRoundTripper(d.client.Transport))}
+	index, err := remote.Index(ref, append(options, remote.WithContext(ctx))...)
 	if err != nil {
-		return nil, fmt.Errorf("could not download image: %w", err)
+		return nil, nil, fmt.Errorf("could not download image: %w", err)
 	}
-	return d.downloadIndex(index)
+	return index, &registrySignatureSource{repository: ref.Context(), options: options}, nil
 }
 
-func (d *Downloader) downloadFile(path string) (oci.Image, error) {
+func (d *Downloader) downloadFile(path string) (oci.ImageIndex, signatureSource, error) {
 	layoutPath, err := layout.FromPath(path)
 	if err != nil {
-		return nil, fmt.Errorf("could not get layout from path: %w", err)
+		return nil, nil, fmt.Errorf("could not get layout from path: %w", err)
 	}
 	imageIndex, err := layoutPath.ImageIndex()
 	if err != nil {
-		return nil, fmt.Errorf("could not get image index: %w", err)
+		return nil, nil, fmt.Errorf("could not get image index: %w", err)
 	}
-	return d.downloadIndex(imageIndex)
+	return imageIndex, &layoutSignatureSource{index: imageIndex}, nil
 }
 
 func (d *Downloader) downloadIndex(index oci.ImageIndex) (oci.Image, error) {
@@ -216,6 +239,10 @@ func (d *Downloader) downloadIndex(index oci.ImageIndex) (oci.Image, error) {
 		if manifest.Platform != nil && !manifest.Platform.Satisfies(platform) {
 			continue
 		}
+		if isSignatureArtifact(manifest) {
+			// Signatures can be stored next to the package in OCI layouts
+			continue
+		}
 		image, err := index.Image(manifest.Digest)
 		if err != nil {
 			return nil, fmt.Errorf("could not get image: %w", err)
@@ -226,7 +253,11 @@ func (d *Downloader) downloadIndex(index oci.ImageIndex) (oci.Image, error) {
 }
 
 // ExtractLayers extracts the layers of the downloaded package with the given media type to the given directory.
+// Layers of packages without a valid signature are not extracted when the signature verification is enforced.
 func (d *DownloadedPackage) ExtractLayers(mediaType types.MediaType, dir string) error {
+	if d.Signature.Mode == SignatureModeEnforce && d.Signature.Status != SignatureStatusVerified {
+		return fmt.Errorf("refusing to extract layers of unverified package %s: %s", d.Name, d.Signature.Error)
+	}
 	layers, err := d.Image.Layers()
 	if err != nil {
 		return fmt.Errorf("could not get image layers: %w", err)
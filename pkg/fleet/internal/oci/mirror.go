@@ -0,0 +1,348 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package oci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	oci "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/match"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	httptrace "gopkg.in/DataDog/dd-trace-go.v1/contrib/net/http"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// MirrorFallback is the rule applied when a package can't be downloaded from the mirror.
+type MirrorFallback string
+
+const (
+	// MirrorFallbackNone only resolves packages from the mirror.
+	MirrorFallbackNone MirrorFallback = "none"
+	// MirrorFallbackMissing resolves the packages missing from the mirror from their registry.
+	MirrorFallbackMissing MirrorFallback = "missing"
+	// MirrorFallbackError resolves the packages from their registry on any mirror error.
+	MirrorFallbackError MirrorFallback = "error"
+)
+
+const (
+	// AnnotationRepository is the annotation used to identify the repository of a package mirrored in an OCI layout.
+	AnnotationRepository = "com.datadoghq.package.repository"
+)
+
+// ErrNotInMirror is returned when a package is not found in the mirror.
+var ErrNotInMirror = errors.New("package not found in mirror")
+
+// ParseMirrorFallback parses the mirror fallback rule. Unknown rules never fall back.
+func ParseMirrorFallback(fallback string) MirrorFallback {
+	switch MirrorFallback(strings.ToLower(fallback)) {
+	case MirrorFallbackNone, "":
+		return MirrorFallbackNone
+	case MirrorFallbackMissing:
+		return MirrorFallbackMissing
+	case MirrorFallbackError:
+		return MirrorFallbackError
+	default:
+		log.Warnf("unknown mirror fallback rule %s, packages will only be resolved from the mirror", fallback)
+		return MirrorFallbackNone
+	}
+}
+
+// mirrorRef is the reference of a package in a mirror: the path of its repository, made of
+// its registry and its repository in the registry, and its tag or digest.
+type mirrorRef struct {
+	repository string
+	tag        string
+	digest     string
+}
+
+func parseMirrorRef(packageURL string) (mirrorRef, error) {
+	if !strings.HasPrefix(packageURL, "oci://") {
+		return mirrorRef{}, fmt.Errorf("only oci:// packages can be mirrored: %s", packageURL)
+	}
+	ref, err := name.ParseReference(strings.TrimPrefix(packageURL, "oci://"))
+	if err != nil {
+		return mirrorRef{}, fmt.Errorf("could not parse reference: %w", err)
+	}
+	// packages with the same name in different registries or namespaces must not collide.
+	// The port of the registry, if any, is not valid in a repository path.
+	registry := strings.ReplaceAll(ref.Context().RegistryStr(), ":", "_")
+	r := mirrorRef{repository: path.Join(registry, ref.Context().RepositoryStr())}
+	switch ref := ref.(type) {
+	case name.Digest:
+		r.digest = ref.DigestStr()
+	case name.Tag:
+		r.tag = ref.TagStr()
+	}
+	return r, nil
+}
+
+// String returns the reference relative to the mirror: <repository>:<tag> or <repository>@<digest>.
+func (r mirrorRef) String() string {
+	if r.digest != "" {
+		return r.repository + "@" + r.digest
+	}
+	return r.repository + ":" + r.tag
+}
+
+// mirrorRegistryRef returns the reference of the package in a mirror registry.
+func mirrorRegistryRef(mirror *url.URL, ref mirrorRef) (name.Reference, error) {
+	prefix := strings.TrimSuffix(mirror.Host+mirror.Path, "/")
+	return name.ParseReference(prefix + "/" + ref.String())
+}
+
+// matchMirrorRef returns a matcher of the descriptors of the package in a mirror layout.
+func matchMirrorRef(ref mirrorRef) match.Matcher {
+	if ref.digest != "" {
+		return func(descriptor oci.Descriptor) bool {
+			return descriptor.Annotations[AnnotationRepository] == ref.repository && descriptor.Digest.String() == ref.digest
+		}
+	}
+	return match.Annotation(layoutRefAnnotation, ref.String())
+}
+
+func (d *Downloader) mirrorOptions() []remote.Option {
+	return []remote.Option{remote.WithAuthFromKeychain(getKeychain(d.env.MirrorAuth)), remote.WithTransport(httptrace.WrapRoundTripper(d.client.Transport))}
+}
+
+// downloadMirror downloads the package from the mirror.
+func (d *Downloader) downloadMirror(ctx context.Context, packageURL string) (oci.ImageIndex, signatureSource, error) {
+	ref, err := parseMirrorRef(packageURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	mirror, err := url.Parse(d.env.Mirror)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not parse mirror URL: %w", err)
+	}
+	switch mirror.Scheme {
+	case "oci":
+		mirrorRef, err := mirrorRegistryRef(mirror, ref)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not parse mirror reference: %w", err)
+		}
+		options := d.mirrorOptions()
+		index, err := remote.Index(mirrorRef, append(options, remote.WithContext(ctx))...)
+		if isNotFound(err) {
+			return nil, nil, fmt.Errorf("%w: %s", ErrNotInMirror, mirrorRef)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not download image from mirror: %w", err)
+		}
+		return index, &registrySignatureSource{repository: mirrorRef.Context(), options: options}, nil
+	case "file":
+		layoutPath, err := layout.FromPath(mirror.Path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not get mirror layout: %w", err)
+		}
+		root, err := layoutPath.ImageIndex()
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not get mirror index: %w", err)
+		}
+		rootManifest, err := root.IndexManifest()
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not get mirror index manifest: %w", err)
+		}
+		matcher := matchMirrorRef(ref)
+		for _, descriptor := range rootManifest.Manifests {
+			if !matcher(descriptor) {
+				continue
+			}
+			index, err := root.ImageIndex(descriptor.Digest)
+			if err != nil {
+				return nil, nil, fmt.Errorf("could not get image index from mirror: %w", err)
+			}
+			// Signatures are stored next to the packages at the root of the mirror
+			return index, &layoutSignatureSource{index: root}, nil
+		}
+		return nil, nil, fmt.Errorf("%w: %s", ErrNotInMirror, ref)
+	default:
+		return nil, nil, fmt.Errorf("unsupported mirror URL scheme: %s", mirror.Scheme)
+	}
+}
+
+// shouldFallback returns true if the package should be downloaded from its registry after
+// failing to download it from the mirror.
+func (d *Downloader) shouldFallback(err error) bool {
+	switch ParseMirrorFallback(d.env.MirrorFallback) {
+	case MirrorFallbackMissing:
+		return errors.Is(err, ErrNotInMirror)
+	case MirrorFallbackError:
+		return true
+	default:
+		return false
+	}
+}
+
+// CheckMirror checks that the package is available in the mirror. Only oci:// packages are
+// downloaded from the mirror, others such as file:// packages are not checked.
+func (d *Downloader) CheckMirror(ctx context.Context, packageURL string) error {
+	if !strings.HasPrefix(packageURL, "oci://") {
+		return nil
+	}
+	_, _, err := d.downloadMirror(ctx, packageURL)
+	return err
+}
+
+// signatureArtifacts are the signatures of a package copied to a mirror.
+type signatureArtifacts struct {
+	// cosign are the cosign signatures indexed by their tag.
+	cosign  map[string]oci.Image
+	bundles []oci.Image
+}
+
+// packageSignatures returns the signatures of the index and the images of a package.
+func packageSignatures(ctx context.Context, source signatureSource, index oci.ImageIndex) (signatureArtifacts, error) {
+	artifacts := signatureArtifacts{cosign: map[string]oci.Image{}}
+	indexDigest, err := index.Digest()
+	if err != nil {
+		return artifacts, fmt.Errorf("could not get index digest: %w", err)
+	}
+	indexManifest, err := index.IndexManifest()
+	if err != nil {
+		return artifacts, fmt.Errorf("could not get index manifest: %w", err)
+	}
+	digests := []oci.Hash{indexDigest}
+	for _, descriptor := range indexManifest.Manifests {
+		digests = append(digests, descriptor.Digest)
+	}
+	for _, digest := range digests {
+		signature, err := source.cosignSignature(ctx, digest)
+		if err != nil {
+			return artifacts, fmt.Errorf("could not get cosign signature of %s: %w", digest, err)
+		}
+		if signature != nil {
+			artifacts.cosign[cosignTag(digest)] = signature
+		}
+		bundles, err := source.sigstoreBundles(ctx, digest)
+		if err != nil {
+			return artifacts, fmt.Errorf("could not get sigstore bundles of %s: %w", digest, err)
+		}
+		artifacts.bundles = append(artifacts.bundles, bundles...)
+	}
+	return artifacts, nil
+}
+
+// Mirror copies the package and its signatures from its registry to the mirror, either a
+// registry (oci://<registry>/<prefix>) or an OCI layout shared by all the packages (file://<path>).
+func (d *Downloader) Mirror(ctx context.Context, packageURL string, mirrorURL string) error {
+	ref, err := parseMirrorRef(packageURL)
+	if err != nil {
+		return err
+	}
+	mirror, err := url.Parse(mirrorURL)
+	if err != nil {
+		return fmt.Errorf("could not parse mirror URL: %w", err)
+	}
+	index, source, err := d.downloadRegistry(ctx, strings.TrimPrefix(packageURL, "oci://"))
+	if err != nil {
+		return fmt.Errorf("could not download package from %s: %w", packageURL, err)
+	}
+	signatures, err := packageSignatures(ctx, source, index)
+	if err != nil {
+		return fmt.Errorf("could not get signatures of package %s: %w", packageURL, err)
+	}
+
+	switch mirror.Scheme {
+	case "oci":
+		err = mirrorToRegistry(ctx, d.mirrorOptions(), mirror, ref, index, signatures)
+	case "file":
+		err = mirrorToLayout(mirror.Path, ref, index, signatures)
+	default:
+		return fmt.Errorf("unsupported mirror URL scheme: %s", mirror.Scheme)
+	}
+	if err != nil {
+		return fmt.Errorf("could not mirror package %s: %w", packageURL, err)
+	}
+	log.Infof("Mirrored package %s to %s with %d signatures", packageURL, mirrorURL, len(signatures.cosign)+len(signatures.bundles))
+	return nil
+}
+
+func mirrorToRegistry(ctx context.Context, options []remote.Option, mirror *url.URL, ref mirrorRef, index oci.ImageIndex, signatures signatureArtifacts) error {
+	mirrorRef, err := mirrorRegistryRef(mirror, ref)
+	if err != nil {
+		return fmt.Errorf("could not parse mirror reference: %w", err)
+	}
+	options = append([]remote.Option{remote.WithContext(ctx)}, options...)
+	err = remote.WriteIndex(mirrorRef, index, options...)
+	if err != nil {
+		return fmt.Errorf("could not write index: %w", err)
+	}
+	repository := mirrorRef.Context()
+	for tag, signature := range signatures.cosign {
+		err = remote.Write(repository.Tag(tag), signature, options...)
+		if err != nil {
+			return fmt.Errorf("could not write cosign signature: %w", err)
+		}
+	}
+	for _, bundle := range signatures.bundles {
+		digest, err := bundle.Digest()
+		if err != nil {
+			return fmt.Errorf("could not get bundle digest: %w", err)
+		}
+		err = remote.Write(repository.Digest(digest.String()), bundle, options...)
+		if err != nil {
+			return fmt.Errorf("could not write sigstore bundle: %w", err)
+		}
+	}
+	return nil
+}
+
+func mirrorToLayout(dir string, ref mirrorRef, index oci.ImageIndex, signatures signatureArtifacts) error {
+	layoutPath, err := layout.FromPath(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		layoutPath, err = layout.Write(dir, empty.Index)
+	}
+	if err != nil {
+		return fmt.Errorf("could not open mirror layout: %w", err)
+	}
+	root, err := layoutPath.ImageIndex()
+	if err != nil {
+		return fmt.Errorf("could not get mirror index: %w", err)
+	}
+	mirrored, err := partial.FindManifests(root, matchMirrorRef(ref))
+	if err != nil {
+		return fmt.Errorf("could not get mirror index manifest: %w", err)
+	}
+	// Packages referenced by digest are immutable, replacing them would drop the tags of the existing descriptor
+	if ref.digest == "" || len(mirrored) == 0 {
+		annotations := map[string]string{AnnotationRepository: ref.repository}
+		if ref.tag != "" {
+			annotations[layoutRefAnnotation] = ref.String()
+		}
+		err = layoutPath.ReplaceIndex(index, matchMirrorRef(ref), layout.WithAnnotations(annotations))
+		if err != nil {
+			return fmt.Errorf("could not write index: %w", err)
+		}
+	}
+	for tag, signature := range signatures.cosign {
+		err = layoutPath.ReplaceImage(signature, match.Annotation(layoutRefAnnotation, tag), layout.WithAnnotations(map[string]string{layoutRefAnnotation: tag}))
+		if err != nil {
+			return fmt.Errorf("could not write cosign signature: %w", err)
+		}
+	}
+	for _, bundle := range signatures.bundles {
+		digest, err := bundle.Digest()
+		if err != nil {
+			return fmt.Errorf("could not get bundle digest: %w", err)
+		}
+		err = layoutPath.ReplaceImage(bundle, match.Digests(digest))
+		if err != nil {
+			return fmt.Errorf("could not write sigstore bundle: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// for now the installer is not supported on windows
+//go:build !windows
+
+package oci
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/fleet/env"
+	"github.com/DataDog/datadog-agent/pkg/fleet/internal/fixtures"
+)
+
+func TestMirror(t *testing.T) {
+	mirrors := map[string]func(t *testing.T) string{
+		"registry": func(t *testing.T) string { return "oci://" + fixtures.NewRegistry(t) + "/datadog/" },
+		"layout":   func(t *testing.T) string { return "file://" + filepath.Join(t.TempDir(), "mirror") },
+	}
+	for name, newMirror := range mirrors {
+		t.Run(name, func(t *testing.T) {
+			s := newTestDownloadServer(t)
+			key := fixtures.NewSigningKey(t)
+			s.SignPackage(fixtures.FixtureSimpleV1, key, fixtures.SignatureCosign)
+			s.SignPackage(fixtures.FixtureSimpleV2, key, fixtures.SignatureBundle)
+			mirror := newMirror(t)
+
+			for _, f := range []fixtures.Fixture{fixtures.FixtureSimpleV1, fixtures.FixtureSimpleV2} {
+				err := s.Downloader().Mirror(context.Background(), s.PackageURL(f), mirror)
+				require.NoError(t, err)
+				// Mirroring is idempotent
+				err = s.Downloader().Mirror(context.Background(), s.PackageURL(f), mirror)
+				require.NoError(t, err)
+			}
+
+			// The registry of the package is never reached
+			s.Close()
+			d := s.DownloaderWithEnv(&env.Env{
+				Mirror:               mirror,
+				SignatureMode:        string(SignatureModeEnforce),
+				SignatureCatalogKeys: string(key.PublicKeyPEM()),
+			})
+			for _, f := range []fixtures.Fixture{fixtures.FixtureSimpleV1, fixtures.FixtureSimpleV2} {
+				downloadedPackage, err := d.Download(context.Background(), s.PackageURL(f))
+				require.NoError(t, err)
+				assert.Equal(t, f.Version, downloadedPackage.Version)
+				assert.Equal(t, SignatureStatusVerified, downloadedPackage.Signature.Status, downloadedPackage.Signature.Error)
+
+				tmpDir := t.TempDir()
+				err = downloadedPackage.ExtractLayers(DatadogPackageLayerMediaType, tmpDir)
+				assert.NoError(t, err)
+				fixtures.AssertEqualFS(t, s.PackageFS(f), os.DirFS(tmpDir))
+			}
+		})
+	}
+}
+
+func TestMirrorFallback(t *testing.T) {
+	tests := []struct {
+		name        string
+		fallback    MirrorFallback
+		unreachable bool
+		err         error
+	}{
+		{name: "missing, no fallback", fallback: MirrorFallbackNone, err: ErrNotInMirror},
+		{name: "missing, fallback on missing", fallback: MirrorFallbackMissing},
+		{name: "missing, fallback on error", fallback: MirrorFallbackError},
+		{name: "unreachable, fallback on missing", fallback: MirrorFallbackMissing, unreachable: true, err: os.ErrNotExist},
+		{name: "unreachable, fallback on error", fallback: MirrorFallbackError, unreachable: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestDownloadServer(t)
+			mirror := filepath.Join(t.TempDir(), "mirror")
+			if !tt.unreachable {
+				require.NoError(t, s.Downloader().Mirror(context.Background(), s.PackageURL(fixtures.FixtureSimpleV1), "file://"+mirror))
+			}
+			d := s.DownloaderWithEnv(&env.Env{Mirror: "file://" + mirror, MirrorFallback: string(tt.fallback)})
+
+			downloadedPackage, err := d.Download(context.Background(), s.PackageURL(fixtures.FixtureSimpleV2))
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				assert.ErrorIs(t, d.CheckMirror(context.Background(), s.PackageURL(fixtures.FixtureSimpleV2)), tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, fixtures.FixtureSimpleV2.Version, downloadedPackage.Version)
+		})
+	}
+}
+
+func TestCheckMirrorLocalPackage(t *testing.T) {
+	s := newTestDownloadServer(t)
+	d := s.DownloaderWithEnv(&env.Env{Mirror: "file://" + filepath.Join(t.TempDir(), "mirror")})
+
+	// Local packages are not downloaded from the mirror
+	assert.NoError(t, d.CheckMirror(context.Background(), "file:///opt/datadog-packages/agent"))
+	assert.ErrorIs(t, d.CheckMirror(context.Background(), s.PackageURL(fixtures.FixtureSimpleV1)), os.ErrNotExist)
+}
+
+func TestParseMirrorRef(t *testing.T) {
+	ref, err := parseMirrorRef("oci://gcr.io/datadoghq/agent-package:7.55.0-1")
+	assert.NoError(t, err)
+	assert.Equal(t, mirrorRef{repository: "gcr.io/datadoghq/agent-package", tag: "7.55.0-1"}, ref)
+	assert.Equal(t, "gcr.io/datadoghq/agent-package:7.55.0-1", ref.String())
+
+	ref, err = parseMirrorRef("oci://docker.io/datadog/agent-package-dev@sha256:2fa082d512a120a814e32ddb80454efce56595b5c84a37cc1a9f90cf9cc7ba85")
+	assert.NoError(t, err)
+	assert.Equal(t, "index.docker.io/datadog/agent-package-dev@sha256:2fa082d512a120a814e32ddb80454efce56595b5c84a37cc1a9f90cf9cc7ba85", ref.String())
+
+	// packages with the same name from other registries or namespaces don't collide
+	ref, err = parseMirrorRef("oci://registry.example.com:5000/custom/agent-package:7.55.0-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "registry.example.com_5000/custom/agent-package:7.55.0-1", ref.String())
+	mirror, err := url.Parse("oci://mirror.example.com/datadog")
+	require.NoError(t, err)
+	mirrorRef, err := mirrorRegistryRef(mirror, ref)
+	assert.NoError(t, err)
+	assert.Equal(t, "mirror.example.com/datadog/registry.example.com_5000/custom/agent-package:7.55.0-1", mirrorRef.String())
+
+	_, err = parseMirrorRef("file:///opt/datadog-packages/agent")
+	assert.Error(t, err)
+}
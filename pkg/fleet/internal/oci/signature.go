@@ -0,0 +1,541 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package oci
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	oci "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+
+	"github.com/DataDog/datadog-agent/pkg/fleet/env"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// CosignSimpleSigningMediaType is the media type of the payload of cosign signatures.
+	CosignSimpleSigningMediaType types.MediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	// CosignSignatureAnnotation is the layer annotation holding the base64 encoded cosign signature.
+	CosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+	// SigstoreBundleMediaType is the media type of sigstore bundles attached as referrers.
+	SigstoreBundleMediaType types.MediaType = "application/vnd.dev.sigstore.bundle.v0.3+json"
+	// InTotoPayloadType is the DSSE payload type of in-toto attestations.
+	InTotoPayloadType = "application/vnd.in-toto+json"
+
+	cosignSignatureType = "cosign container image signature"
+	cosignTagSuffix     = ".sig"
+	layoutRefAnnotation = "org.opencontainers.image.ref.name"
+	maxSignatureSize    = 1 << 20 // 1MiB
+)
+
+// SignatureMode is the signature verification mode of the packages.
+type SignatureMode string
+
+const (
+	// SignatureModeDisabled disables the signature verification.
+	SignatureModeDisabled SignatureMode = "disabled"
+	// SignatureModeWarn verifies the signatures but only logs a warning for unverified packages.
+	SignatureModeWarn SignatureMode = "warn"
+	// SignatureModeEnforce refuses to extract unverified packages.
+	SignatureModeEnforce SignatureMode = "enforce"
+)
+
+// SignatureStatus is the result of the signature verification of a package.
+type SignatureStatus string
+
+const (
+	// SignatureStatusSkipped is the status of packages downloaded with the verification disabled.
+	SignatureStatusSkipped SignatureStatus = "skipped"
+	// SignatureStatusVerified is the status of packages with a valid signature from a trusted key.
+	SignatureStatusVerified SignatureStatus = "verified"
+	// SignatureStatusUnverified is the status of packages without a valid signature from a trusted key.
+	SignatureStatusUnverified SignatureStatus = "unverified"
+)
+
+// SignatureFormat is the format of a package signature.
+type SignatureFormat string
+
+const (
+	// SignatureFormatCosign is a cosign simple signing signature stored under the sha256-<hex>.sig tag.
+	SignatureFormatCosign SignatureFormat = "cosign"
+	// SignatureFormatSigstoreBundle is a sigstore bundle attached to the package as a referrer.
+	SignatureFormatSigstoreBundle SignatureFormat = "sigstore-bundle"
+)
+
+// SignatureVerification is the result of the signature verification of a downloaded package.
+type SignatureVerification struct {
+	Mode   SignatureMode
+	Status SignatureStatus
+	Format SignatureFormat
+	// Digest is the digest of the manifest covered by the signature.
+	Digest string
+	// KeyID is the SHA256 fingerprint of the public key that verified the signature.
+	KeyID string
+	// Error is the reason why the package couldn't be verified.
+	Error string
+}
+
+// ParseSignatureMode parses the given signature mode, an empty mode disables the verification.
+// Unknown modes enforce the verification.
+func ParseSignatureMode(mode string) SignatureMode {
+	switch SignatureMode(strings.ToLower(strings.TrimSpace(mode))) {
+	case "", SignatureModeDisabled:
+		return SignatureModeDisabled
+	case SignatureModeWarn:
+		return SignatureModeWarn
+	case SignatureModeEnforce:
+		return SignatureModeEnforce
+	default:
+		log.Warnf("unknown package signature mode %q, enforcing signature verification", mode)
+		return SignatureModeEnforce
+	}
+}
+
+// publicKey is a public key trusted to sign packages.
+type publicKey struct {
+	id  string
+	key crypto.PublicKey
+}
+
+// parsePublicKeys parses the PEM encoded public keys.
+func parsePublicKeys(data []byte) ([]publicKey, error) {
+	var keys []publicKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "PUBLIC KEY" {
+			continue
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse public key: %w", err)
+		}
+		fingerprint := sha256.Sum256(block.Bytes)
+		keys = append(keys, publicKey{
+			id:  "SHA256:" + hex.EncodeToString(fingerprint[:]),
+			key: key,
+		})
+	}
+	if len(bytes.TrimSpace(data)) != 0 {
+		return nil, errors.New("trailing data after public keys")
+	}
+	return keys, nil
+}
+
+// loadPublicKeys loads the public keys configured locally and delivered with the catalog.
+func loadPublicKeys(env *env.Env) ([]publicKey, error) {
+	var keys []publicKey
+	for _, path := range env.SignaturePublicKeyFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read public key file: %w", err)
+		}
+		fileKeys, err := parsePublicKeys(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key file %s: %w", path, err)
+		}
+		keys = append(keys, fileKeys...)
+	}
+	catalogKeys, err := parsePublicKeys([]byte(env.SignatureCatalogKeys))
+	if err != nil {
+		return nil, fmt.Errorf("invalid catalog public keys: %w", err)
+	}
+	return append(keys, catalogKeys...), nil
+}
+
+// verify checks the signature of the message with the public key. ECDSA and RSA signatures
+// are computed over the SHA256 digest of the message.
+func (k publicKey) verify(message []byte, signature []byte) error {
+	digest := sha256.Sum256(message)
+	switch key := k.key.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest[:], signature) {
+			return errors.New("invalid ECDSA signature")
+		}
+		return nil
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature)
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, message, signature) {
+			return errors.New("invalid ed25519 signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported public key type %T", k.key)
+	}
+}
+
+// signatureSource retrieves the signatures attached to a package.
+type signatureSource interface {
+	// cosignSignature returns the cosign signature image of the manifest with the given digest, if any.
+	cosignSignature(ctx context.Context, digest oci.Hash) (oci.Image, error)
+	// sigstoreBundles returns the sigstore bundle artifacts referring to the manifest with the given digest.
+	sigstoreBundles(ctx context.Context, digest oci.Hash) ([]oci.Image, error)
+}
+
+func cosignTag(digest oci.Hash) string {
+	return fmt.Sprintf("%s-%s%s", digest.Algorithm, digest.Hex, cosignTagSuffix)
+}
+
+// isSignatureArtifact returns true if the descriptor references a signature instead of a package image.
+func isSignatureArtifact(descriptor oci.Descriptor) bool {
+	return types.MediaType(descriptor.ArtifactType) == SigstoreBundleMediaType ||
+		strings.HasSuffix(descriptor.Annotations[layoutRefAnnotation], cosignTagSuffix)
+}
+
+// registrySignatureSource retrieves signatures from the repository of a package in a registry.
+type registrySignatureSource struct {
+	repository name.Repository
+	options    []remote.Option
+}
+
+func isNotFound(err error) bool {
+	var transportErr *transport.Error
+	return errors.As(err, &transportErr) && transportErr.StatusCode == http.StatusNotFound
+}
+
+func (s *registrySignatureSource) cosignSignature(ctx context.Context, digest oci.Hash) (oci.Image, error) {
+	options := append([]remote.Option{remote.WithContext(ctx)}, s.options...)
+	image, err := remote.Image(s.repository.Tag(cosignTag(digest)), options...)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	return image, err
+}
+
+func (s *registrySignatureSource) sigstoreBundles(ctx context.Context, digest oci.Hash) ([]oci.Image, error) {
+	options := append([]remote.Option{remote.WithContext(ctx), remote.WithFilter("artifactType", string(SigstoreBundleMediaType))}, s.options...)
+	referrers, err := remote.Referrers(s.repository.Digest(digest.String()), options...)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := referrers.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+	var bundles []oci.Image
+	for _, descriptor := range manifest.Manifests {
+		if types.MediaType(descriptor.ArtifactType) != SigstoreBundleMediaType {
+			continue
+		}
+		image, err := remote.Image(s.repository.Digest(descriptor.Digest.String()), options...)
+		if err != nil {
+			return nil, err
+		}
+		bundles = append(bundles, image)
+	}
+	return bundles, nil
+}
+
+// layoutSignatureSource retrieves signatures stored next to a package in an OCI layout.
+type layoutSignatureSource struct {
+	index oci.ImageIndex
+}
+
+func (s *layoutSignatureSource) cosignSignature(_ context.Context, digest oci.Hash) (oci.Image, error) {
+	manifest, err := s.index.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+	for _, descriptor := range manifest.Manifests {
+		if descriptor.Annotations[layoutRefAnnotation] == cosignTag(digest) {
+			return s.index.Image(descriptor.Digest)
+		}
+	}
+	return nil, nil
+}
+
+func (s *layoutSignatureSource) sigstoreBundles(_ context.Context, digest oci.Hash) ([]oci.Image, error) {
+	manifest, err := s.index.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+	var bundles []oci.Image
+	for _, descriptor := range manifest.Manifests {
+		if types.MediaType(descriptor.ArtifactType) != SigstoreBundleMediaType {
+			continue
+		}
+		image, err := s.index.Image(descriptor.Digest)
+		if err != nil {
+			return nil, err
+		}
+		imageManifest, err := image.Manifest()
+		if err != nil {
+			return nil, err
+		}
+		if imageManifest.Subject != nil && imageManifest.Subject.Digest == digest {
+			bundles = append(bundles, image)
+		}
+	}
+	return bundles, nil
+}
+
+// signedManifest is a manifest of the package that may be covered by a signature.
+type signedManifest struct {
+	digest oci.Hash
+	raw    []byte
+}
+
+// verifySignature verifies that the index or the image of the package is signed by one of the trusted keys.
+func (d *Downloader) verifySignature(ctx context.Context, source signatureSource, index oci.ImageIndex, image oci.Image) SignatureVerification {
+	mode := ParseSignatureMode(d.env.SignatureMode)
+	if mode == SignatureModeDisabled {
+		return SignatureVerification{Mode: mode, Status: SignatureStatusSkipped}
+	}
+	unverified := func(err error) SignatureVerification {
+		return SignatureVerification{Mode: mode, Status: SignatureStatusUnverified, Error: err.Error()}
+	}
+
+	keys, err := loadPublicKeys(d.env)
+	if err != nil {
+		return unverified(err)
+	}
+	if len(keys) == 0 {
+		return unverified(errors.New("no public key configured to verify package signatures"))
+	}
+
+	var manifests []signedManifest
+	for _, m := range []interface {
+		Digest() (oci.Hash, error)
+		RawManifest() ([]byte, error)
+	}{index, image} {
+		digest, err := m.Digest()
+		if err != nil {
+			return unverified(fmt.Errorf("could not get manifest digest: %w", err))
+		}
+		raw, err := m.RawManifest()
+		if err != nil {
+			return unverified(fmt.Errorf("could not get raw manifest: %w", err))
+		}
+		manifests = append(manifests, signedManifest{digest: digest, raw: raw})
+	}
+
+	var errs []error
+	for _, manifest := range manifests {
+		signature, err := source.cosignSignature(ctx, manifest.digest)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not get cosign signature of %s: %w", manifest.digest, err))
+		} else if signature != nil {
+			keyID, err := verifyCosignSignature(signature, manifest, keys)
+			if err == nil {
+				return SignatureVerification{Mode: mode, Status: SignatureStatusVerified, Format: SignatureFormatCosign, Digest: manifest.digest.String(), KeyID: keyID}
+			}
+			errs = append(errs, fmt.Errorf("invalid cosign signature of %s: %w", manifest.digest, err))
+		}
+
+		bundles, err := source.sigstoreBundles(ctx, manifest.digest)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not get sigstore bundles of %s: %w", manifest.digest, err))
+		}
+		for _, bundle := range bundles {
+			keyID, err := verifySigstoreBundle(bundle, manifest, keys)
+			if err == nil {
+				return SignatureVerification{Mode: mode, Status: SignatureStatusVerified, Format: SignatureFormatSigstoreBundle, Digest: manifest.digest.String(), KeyID: keyID}
+			}
+			errs = append(errs, fmt.Errorf("invalid sigstore bundle for %s: %w", manifest.digest, err))
+		}
+	}
+	if len(errs) == 0 {
+		return unverified(errors.New("no signature found for the package"))
+	}
+	return unverified(errors.Join(errs...))
+}
+
+// readArtifactLayer reads the blob of the given layer descriptor of a signature artifact.
+func readArtifactLayer(image oci.Image, descriptor oci.Descriptor) ([]byte, error) {
+	layer, err := image.LayerByDigest(descriptor.Digest)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := layer.Compressed()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(io.LimitReader(reader, maxSignatureSize))
+}
+
+// cosignPayload is the simple signing payload signed by cosign.
+type cosignPayload struct {
+	Critical struct {
+		Type  string `json:"type"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+func verifyCosignSignature(signature oci.Image, manifest signedManifest, keys []publicKey) (string, error) {
+	signatureManifest, err := signature.Manifest()
+	if err != nil {
+		return "", err
+	}
+	var errs []error
+	for _, descriptor := range signatureManifest.Layers {
+		if descriptor.MediaType != CosignSimpleSigningMediaType {
+			continue
+		}
+		rawSignature, err := base64.StdEncoding.DecodeString(descriptor.Annotations[CosignSignatureAnnotation])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not decode signature: %w", err))
+			continue
+		}
+		payload, err := readArtifactLayer(signature, descriptor)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not read payload: %w", err))
+			continue
+		}
+		keyID, err := verifyWithKeys(keys, payload, rawSignature)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		// The payload is only trusted once its signature is verified
+		var p cosignPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			errs = append(errs, fmt.Errorf("could not parse payload: %w", err))
+			continue
+		}
+		if p.Critical.Type != cosignSignatureType {
+			errs = append(errs, fmt.Errorf("unexpected payload type %q", p.Critical.Type))
+			continue
+		}
+		if p.Critical.Image.DockerManifestDigest != manifest.digest.String() {
+			errs = append(errs, fmt.Errorf("payload covers %s", p.Critical.Image.DockerManifestDigest))
+			continue
+		}
+		return keyID, nil
+	}
+	if len(errs) == 0 {
+		return "", errors.New("no signature layer")
+	}
+	return "", errors.Join(errs...)
+}
+
+// sigstoreBundle is the subset of a sigstore bundle used to verify packages.
+type sigstoreBundle struct {
+	MediaType        string `json:"mediaType"`
+	MessageSignature *struct {
+		MessageDigest struct {
+			Algorithm string `json:"algorithm"`
+			Digest    []byte `json:"digest"`
+		} `json:"messageDigest"`
+		Signature []byte `json:"signature"`
+	} `json:"messageSignature"`
+	DSSEEnvelope *struct {
+		Payload     []byte `json:"payload"`
+		PayloadType string `json:"payloadType"`
+		Signatures  []struct {
+			Sig []byte `json:"sig"`
+		} `json:"signatures"`
+	} `json:"dsseEnvelope"`
+}
+
+// inTotoStatement is the subset of an in-toto statement used to verify packages.
+type inTotoStatement struct {
+	Subject []struct {
+		Digest map[string]string `json:"digest"`
+	} `json:"subject"`
+}
+
+// dssePAE returns the DSSE pre-authentication encoding of the payload, which is what gets signed.
+func dssePAE(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+func verifySigstoreBundle(artifact oci.Image, manifest signedManifest, keys []publicKey) (string, error) {
+	artifactManifest, err := artifact.Manifest()
+	if err != nil {
+		return "", err
+	}
+	for _, descriptor := range artifactManifest.Layers {
+		if descriptor.MediaType != SigstoreBundleMediaType {
+			continue
+		}
+		raw, err := readArtifactLayer(artifact, descriptor)
+		if err != nil {
+			return "", fmt.Errorf("could not read bundle: %w", err)
+		}
+		var bundle sigstoreBundle
+		if err := json.Unmarshal(raw, &bundle); err != nil {
+			return "", fmt.Errorf("could not parse bundle: %w", err)
+		}
+		switch {
+		case bundle.MessageSignature != nil:
+			messageDigest := bundle.MessageSignature.MessageDigest
+			if messageDigest.Algorithm != "SHA2_256" || hex.EncodeToString(messageDigest.Digest) != manifest.digest.Hex {
+				return "", errors.New("bundle message digest doesn't match the package")
+			}
+			return verifyWithKeys(keys, manifest.raw, bundle.MessageSignature.Signature)
+		case bundle.DSSEEnvelope != nil:
+			envelope := bundle.DSSEEnvelope
+			if envelope.PayloadType != InTotoPayloadType {
+				return "", fmt.Errorf("unsupported DSSE payload type %q", envelope.PayloadType)
+			}
+			var errs []error
+			for _, signature := range envelope.Signatures {
+				keyID, err := verifyWithKeys(keys, dssePAE(envelope.PayloadType, envelope.Payload), signature.Sig)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				var statement inTotoStatement
+				if err := json.Unmarshal(envelope.Payload, &statement); err != nil {
+					return "", fmt.Errorf("could not parse in-toto statement: %w", err)
+				}
+				for _, subject := range statement.Subject {
+					if subject.Digest[manifest.digest.Algorithm] == manifest.digest.Hex {
+						return keyID, nil
+					}
+				}
+				return "", errors.New("attestation subjects don't include the package")
+			}
+			if len(errs) == 0 {
+				return "", errors.New("no signature in DSSE envelope")
+			}
+			return "", errors.Join(errs...)
+		default:
+			return "", errors.New("bundle has no signature")
+		}
+	}
+	return "", errors.New("no bundle layer")
+}
+
+// verifyWithKeys returns the ID of the first key verifying the signature of the message.
+func verifyWithKeys(keys []publicKey, message []byte, signature []byte) (string, error) {
+	for _, key := range keys {
+		if key.verify(message, signature) == nil {
+			return key.id, nil
+		}
+	}
+	return "", errors.New("signature doesn't match any trusted public key")
+}
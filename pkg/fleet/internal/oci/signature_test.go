@@ -0,0 +1,123 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// for now the installer is not supported on windows
+//go:build !windows
+
+package oci
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/fleet/env"
+	"github.com/DataDog/datadog-agent/pkg/fleet/internal/fixtures"
+)
+
+func TestDownloadSigned(t *testing.T) {
+	formats := map[string]struct {
+		format   fixtures.SignatureFormat
+		expected SignatureFormat
+	}{
+		"cosign":      {fixtures.SignatureCosign, SignatureFormatCosign},
+		"bundle":      {fixtures.SignatureBundle, SignatureFormatSigstoreBundle},
+		"attestation": {fixtures.SignatureAttestation, SignatureFormatSigstoreBundle},
+	}
+	for name, tt := range formats {
+		t.Run(name, func(t *testing.T) {
+			s := newTestDownloadServer(t)
+			key := fixtures.NewSigningKey(t)
+			s.SignPackage(fixtures.FixtureSimpleV1, key, tt.format)
+			d := s.DownloaderWithEnv(&env.Env{
+				SignatureMode:        string(SignatureModeEnforce),
+				SignatureCatalogKeys: string(key.PublicKeyPEM()),
+			})
+
+			for _, url := range []string{s.PackageURL(fixtures.FixtureSimpleV1), s.PackageLayoutURL(fixtures.FixtureSimpleV1)} {
+				downloadedPackage, err := d.Download(context.Background(), url)
+				require.NoError(t, err)
+				assert.Equal(t, SignatureStatusVerified, downloadedPackage.Signature.Status, downloadedPackage.Signature.Error)
+				assert.Equal(t, tt.expected, downloadedPackage.Signature.Format)
+				assert.Equal(t, key.KeyID(), downloadedPackage.Signature.KeyID)
+
+				tmpDir := t.TempDir()
+				err = downloadedPackage.ExtractLayers(DatadogPackageLayerMediaType, tmpDir)
+				assert.NoError(t, err)
+				fixtures.AssertEqualFS(t, s.PackageFS(fixtures.FixtureSimpleV1), os.DirFS(tmpDir))
+			}
+		})
+	}
+}
+
+func TestDownloadSignedKeyFile(t *testing.T) {
+	s := newTestDownloadServer(t)
+	key := fixtures.NewSigningKey(t)
+	s.SignPackage(fixtures.FixtureSimpleV1, key, fixtures.SignatureCosign)
+	keyFile := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(keyFile, append(fixtures.NewSigningKey(t).PublicKeyPEM(), key.PublicKeyPEM()...), 0644))
+	d := s.DownloaderWithEnv(&env.Env{
+		SignatureMode:           string(SignatureModeEnforce),
+		SignaturePublicKeyFiles: []string{keyFile},
+	})
+
+	downloadedPackage, err := d.Download(context.Background(), s.PackageURL(fixtures.FixtureSimpleV1))
+	require.NoError(t, err)
+	assert.Equal(t, SignatureStatusVerified, downloadedPackage.Signature.Status)
+	assert.Equal(t, key.KeyID(), downloadedPackage.Signature.KeyID)
+}
+
+func TestDownloadUnverified(t *testing.T) {
+	tests := []struct {
+		name    string
+		sign    bool
+		mode    SignatureMode
+		noKey   bool
+		status  SignatureStatus
+		extract bool
+	}{
+		{name: "unsigned enforce", mode: SignatureModeEnforce, status: SignatureStatusUnverified},
+		{name: "unsigned warn", mode: SignatureModeWarn, status: SignatureStatusUnverified, extract: true},
+		{name: "untrusted key enforce", sign: true, mode: SignatureModeEnforce, status: SignatureStatusUnverified},
+		{name: "no key enforce", sign: true, noKey: true, mode: SignatureModeEnforce, status: SignatureStatusUnverified},
+		{name: "disabled", mode: SignatureModeDisabled, status: SignatureStatusSkipped, extract: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestDownloadServer(t)
+			if tt.sign {
+				s.SignPackage(fixtures.FixtureSimpleV1, fixtures.NewSigningKey(t), fixtures.SignatureCosign)
+			}
+			e := &env.Env{SignatureMode: string(tt.mode)}
+			if !tt.noKey {
+				e.SignatureCatalogKeys = string(fixtures.NewSigningKey(t).PublicKeyPEM())
+			}
+			d := s.DownloaderWithEnv(e)
+
+			downloadedPackage, err := d.Download(context.Background(), s.PackageURL(fixtures.FixtureSimpleV1))
+			require.NoError(t, err)
+			assert.Equal(t, tt.mode, downloadedPackage.Signature.Mode)
+			assert.Equal(t, tt.status, downloadedPackage.Signature.Status)
+			err = downloadedPackage.ExtractLayers(DatadogPackageLayerMediaType, t.TempDir())
+			if tt.extract {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, "refusing to extract layers of unverified package")
+			}
+		})
+	}
+}
+
+func TestParseSignatureMode(t *testing.T) {
+	assert.Equal(t, SignatureModeDisabled, ParseSignatureMode(""))
+	assert.Equal(t, SignatureModeDisabled, ParseSignatureMode("disabled"))
+	assert.Equal(t, SignatureModeWarn, ParseSignatureMode("Warn"))
+	assert.Equal(t, SignatureModeEnforce, ParseSignatureMode("enforce"))
+	assert.Equal(t, SignatureModeEnforce, ParseSignatureMode("enforcing"))
+}
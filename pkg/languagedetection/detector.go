@@ -22,6 +22,17 @@ import (
 
 var cliDetectors = []languagemodels.Detector{
 	detectors.JRubyDetector{},
+	detectors.PHPFPMDetector{},
+	detectors.BEAMDetector{},
+}
+
+// privilegedVersionLanguages are the languages detected from the command line
+// whose version is known to the privileged detection, which also refines
+// Erlang into Elixir for releases.
+var privilegedVersionLanguages = map[languagemodels.LanguageName]struct{}{
+	languagemodels.PHP:    {},
+	languagemodels.Erlang: {},
+	languagemodels.Elixir: {},
 }
 
 type languageFromCLI struct {
@@ -32,6 +43,10 @@ type languageFromCLI struct {
 // rubyPattern is a regexp validator for the ruby prefix
 var rubyPattern = regexp.MustCompile(`^ruby\d+\.\d+$`)
 
+// phpPattern is a regexp validator for the php prefix, matching versioned
+// binaries such as php8.2 or php-fpm8.2
+var phpPattern = regexp.MustCompile(`^php(-fpm|-cgi)?\d+(\.\d+)?$`)
+
 // knownPrefixes maps languages names to their prefix
 var knownPrefixes = map[string]languageFromCLI{
 	"python": {name: languagemodels.Python},
@@ -41,6 +56,9 @@ var knownPrefixes = map[string]languageFromCLI{
 	"ruby": {name: languagemodels.Ruby, validator: func(exe string) bool {
 		return rubyPattern.MatchString(exe)
 	}},
+	"php": {name: languagemodels.PHP, validator: func(exe string) bool {
+		return phpPattern.MatchString(exe)
+	}},
 }
 
 // exactMatches maps an exact exe name match to a prefix
@@ -57,6 +75,13 @@ var exactMatches = map[string]languageFromCLI{
 
 	"ruby":  {name: languagemodels.Ruby},
 	"rubyw": {name: languagemodels.Ruby},
+
+	"php":     {name: languagemodels.PHP},
+	"php-fpm": {name: languagemodels.PHP},
+	"php-cgi": {name: languagemodels.PHP},
+
+	"beam":     {name: languagemodels.Erlang},
+	"beam.smp": {name: languagemodels.Erlang},
 }
 
 // languageNameFromCmdline returns a process's language from its command.
@@ -99,7 +124,7 @@ func DetectLanguage(procs []languagemodels.Process, sysprobeConfig config.Reader
 	}()
 
 	langs := make([]*languagemodels.Language, len(procs))
-	unknownPids := make([]int32, 0, len(procs))
+	privilegedPids := make([]int32, 0, len(procs))
 	langsToModify := make(map[int32]*languagemodels.Language, len(procs))
 	for i, proc := range procs {
 		// Language-specific detectors should precede matches on the command/exe
@@ -116,20 +141,18 @@ func DetectLanguage(procs []languagemodels.Process, sysprobeConfig config.Reader
 			}
 		}
 
-		if langs[i] != nil {
-			continue
+		if langs[i] == nil {
+			exe := getExe(proc.GetCmdline())
+			languageName := languageNameFromCommand(exe)
+			if languageName == languagemodels.Unknown {
+				languageName = languageNameFromCommand(proc.GetCommand())
+			}
+			langs[i] = &languagemodels.Language{Name: languageName}
 		}
 
-		exe := getExe(proc.GetCmdline())
-		languageName := languageNameFromCommand(exe)
-		if languageName == languagemodels.Unknown {
-			languageName = languageNameFromCommand(proc.GetCommand())
-		}
-		lang := &languagemodels.Language{Name: languageName}
-		langs[i] = lang
-		if lang.Name == languagemodels.Unknown {
-			unknownPids = append(unknownPids, proc.GetPid())
-			langsToModify[proc.GetPid()] = lang
+		if needsPrivilegedDetection(langs[i]) {
+			privilegedPids = append(privilegedPids, proc.GetPid())
+			langsToModify[proc.GetPid()] = langs[i]
 		}
 	}
 
@@ -148,19 +171,35 @@ func DetectLanguage(procs []languagemodels.Process, sysprobeConfig config.Reader
 			return langs
 		}
 
-		privilegedLangs, err := util.DetectLanguage(unknownPids)
+		privilegedLangs, err := util.DetectLanguage(privilegedPids)
 		if err != nil {
 			log.Warn("[language detection] Failed to request language:", err)
 			return langs
 		}
 
-		for i, pid := range unknownPids {
+		for i, pid := range privilegedPids {
+			// keep the language detected from the command line if the
+			// privileged detection can't tell
+			if privilegedLangs[i].Name == languagemodels.Unknown {
+				continue
+			}
 			*langsToModify[pid] = privilegedLangs[i]
 		}
 	}
 	return langs
 }
 
+// needsPrivilegedDetection returns whether the privileged detection should be
+// requested for a language detected from the command line: either it is
+// unknown, or the privileged detection knows its version.
+func needsPrivilegedDetection(lang *languagemodels.Language) bool {
+	if lang.Name == languagemodels.Unknown {
+		return true
+	}
+	_, found := privilegedVersionLanguages[lang.Name]
+	return found && lang.Version == ""
+}
+
 func privilegedLanguageDetectionEnabled(sysProbeConfig config.Reader) bool {
 	if sysProbeConfig == nil {
 		return false
@@ -112,6 +112,36 @@ func TestDetectLanguage(t *testing.T) {
 			comm:     "java",
 			expected: languagemodels.Ruby,
 		},
+		{
+			name:     "php",
+			cmdline:  []string{"php", "artisan", "queue:work"},
+			comm:     "php",
+			expected: languagemodels.PHP,
+		},
+		{
+			name:     "php-fpm master",
+			cmdline:  []string{"php-fpm: master process (/usr/local/etc/php-fpm.conf)"},
+			comm:     "php-fpm",
+			expected: languagemodels.PHP,
+		},
+		{
+			name:     "phpunit is not PHP",
+			cmdline:  []string{"phpunit", "tests"},
+			comm:     "phpunit",
+			expected: languagemodels.Unknown,
+		},
+		{
+			name:     "erlang",
+			cmdline:  []string{"/usr/lib/erlang/erts-14.1/bin/beam.smp", "--", "-root", "/usr/lib/erlang", "-progname", "erl", "--", "-noshell"},
+			comm:     "beam.smp",
+			expected: languagemodels.Erlang,
+		},
+		{
+			name:     "elixir",
+			cmdline:  []string{"/usr/lib/erlang/erts-14.1/bin/beam.smp", "--", "-root", "/usr/lib/erlang", "--", "-elixir_root", "/usr/lib/elixir/lib", "-s", "elixir", "start_cli"},
+			comm:     "beam.smp",
+			expected: languagemodels.Elixir,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			process := []languagemodels.Process{makeProcess(tc.cmdline, tc.comm)}
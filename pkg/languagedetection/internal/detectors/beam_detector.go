@@ -0,0 +1,54 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package detectors
+
+import (
+	"path/filepath"
+
+	"github.com/DataDog/datadog-agent/pkg/languagedetection/languagemodels"
+)
+
+// BEAMDetector is a languagedetection.Detector that detects the processes of
+// the BEAM virtual machine, running either Erlang or Elixir.
+type BEAMDetector struct{}
+
+// DetectLanguage detects the beam.smp processes, and tells Elixir from Erlang
+// with the arguments passed to the VM by the elixir launcher and by Mix
+// releases.
+func (d BEAMDetector) DetectLanguage(process languagemodels.Process) (languagemodels.Language, error) {
+	if !isBEAMExecutable(process.GetCommand()) {
+		cmdline := process.GetCmdline()
+		if len(cmdline) == 0 || !isBEAMExecutable(filepath.Base(cmdline[0])) {
+			return languagemodels.Language{Name: languagemodels.Unknown}, nil
+		}
+	}
+
+	if runsElixir(process.GetCmdline()) {
+		return languagemodels.Language{Name: languagemodels.Elixir}, nil
+	}
+	return languagemodels.Language{Name: languagemodels.Erlang}, nil
+}
+
+func isBEAMExecutable(name string) bool {
+	return name == "beam.smp" || name == "beam"
+}
+
+// runsElixir returns whether the arguments of a BEAM process start Elixir:
+// the elixir launcher passes -elixir_root, and both the launcher and Mix
+// releases run "-s elixir start_cli".
+func runsElixir(cmdline []string) bool {
+	for i, arg := range cmdline {
+		switch arg {
+		case "-elixir_root", "start_cli":
+			return true
+		case "-s":
+			if i+1 < len(cmdline) && cmdline[i+1] == "elixir" {
+				return true
+			}
+		}
+	}
+	return false
+}
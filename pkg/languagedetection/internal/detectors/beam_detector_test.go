@@ -0,0 +1,62 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package detectors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/languagedetection/languagemodels"
+	"github.com/DataDog/datadog-agent/pkg/proto/pbgo/languagedetection"
+)
+
+func TestBEAMDetector(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		cmdline  []string
+		comm     string
+		expected languagemodels.LanguageName
+	}{
+		{
+			name:     "erlang",
+			cmdline:  []string{"/usr/lib/erlang/erts-14.1/bin/beam.smp", "--", "-root", "/usr/lib/erlang", "-progname", "erl", "--", "-home", "/root", "--", "-noshell", "-s", "myapp"},
+			comm:     "beam.smp",
+			expected: languagemodels.Erlang,
+		},
+		{
+			name:     "elixir launcher",
+			cmdline:  []string{"/usr/lib/erlang/erts-14.1/bin/beam.smp", "--", "-root", "/usr/lib/erlang", "-progname", "erl", "--", "-elixir_root", "/usr/lib/elixir/lib", "-noshell", "-s", "elixir", "start_cli"},
+			comm:     "beam.smp",
+			expected: languagemodels.Elixir,
+		},
+		{
+			name:     "mix release",
+			cmdline:  []string{"/app/erts-14.1/bin/beam.smp", "--", "-root", "/app", "-progname", "erl", "--", "-noshell", "-s", "elixir", "start_cli", "-mode", "embedded"},
+			comm:     "beam.smp",
+			expected: languagemodels.Elixir,
+		},
+		{
+			name:     "truncated command",
+			cmdline:  []string{"/usr/lib/erlang/erts-14.1/bin/beam.smp"},
+			comm:     "1_scheduler",
+			expected: languagemodels.Erlang,
+		},
+		{
+			name:     "not beam",
+			cmdline:  []string{"erl_child_setup", "1024"},
+			comm:     "erl_child_setup",
+			expected: languagemodels.Unknown,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			lang, err := BEAMDetector{}.DetectLanguage(&languagedetection.Process{Cmdline: tc.cmdline, Command: tc.comm})
+			require.NoError(t, err)
+			assert.Equal(t, languagemodels.Language{Name: tc.expected}, lang)
+		})
+	}
+}
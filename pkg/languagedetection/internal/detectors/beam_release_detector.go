@@ -0,0 +1,107 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package detectors
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	dderrors "github.com/DataDog/datadog-agent/pkg/errors"
+	"github.com/DataDog/datadog-agent/pkg/languagedetection/languagemodels"
+	"github.com/DataDog/datadog-agent/pkg/util/kernel"
+)
+
+// elixirAppVersionPattern matches the version in the elixir.app resource file
+// of the Elixir standard library, e.g. {vsn,"1.15.7"}.
+var elixirAppVersionPattern = regexp.MustCompile(`\{vsn,\s*"([^"]+)"\}`)
+
+// BEAMReleaseDetector is a languagedetection.Detector that detects the
+// language and the version of BEAM processes from the layout of the Erlang
+// installation or of the release they run.
+type BEAMReleaseDetector struct {
+	hostProc string
+}
+
+// NewBEAMReleaseDetector returns a new BEAMReleaseDetector
+func NewBEAMReleaseDetector() BEAMReleaseDetector {
+	return BEAMReleaseDetector{hostProc: kernel.ProcFSRoot()}
+}
+
+// ProcessDependent returns true: the same beam.smp binary runs both Erlang
+// and Elixir, so the language can't be cached by binary.
+func (d BEAMReleaseDetector) ProcessDependent() bool {
+	return true
+}
+
+// DetectLanguage detects the beam.smp processes. The root of the installation
+// or release is given by the -root argument of the VM:
+//   - Elixir is detected with the -elixir_root argument of the elixir
+//     launcher, whose elixir.app file holds the version, or with the
+//     lib/elixir-<version> directory of releases.
+//   - Otherwise, the OTP version comes from the releases/<major>/OTP_VERSION
+//     file of the installation.
+func (d BEAMReleaseDetector) DetectLanguage(process languagemodels.Process) (languagemodels.Language, error) {
+	name, err := exeName(d.hostProc, process.GetPid())
+	if err != nil {
+		return languagemodels.Language{}, err
+	}
+	if !isBEAMExecutable(name) {
+		return languagemodels.Language{}, dderrors.NewNotFound("beam binary")
+	}
+
+	args := cmdline(d.hostProc, process)
+	rootfs := procPath(d.hostProc, process.GetPid(), "root")
+	vmRoot := argValue(args, "-root")
+
+	if elixirRoot := argValue(args, "-elixir_root"); elixirRoot != "" {
+		content, err := os.ReadFile(filepath.Join(rootfs, elixirRoot, "elixir", "ebin", "elixir.app"))
+		if err == nil {
+			if match := elixirAppVersionPattern.FindSubmatch(content); match != nil {
+				return languagemodels.Language{Name: languagemodels.Elixir, Version: string(match[1])}, nil
+			}
+		}
+		return languagemodels.Language{Name: languagemodels.Elixir}, nil
+	}
+
+	if vmRoot != "" {
+		if libs, _ := filepath.Glob(filepath.Join(rootfs, vmRoot, "lib", "elixir-*")); len(libs) > 0 {
+			sort.Strings(libs)
+			version := strings.TrimPrefix(filepath.Base(libs[len(libs)-1]), "elixir-")
+			return languagemodels.Language{Name: languagemodels.Elixir, Version: version}, nil
+		}
+	}
+
+	if runsElixir(args) {
+		return languagemodels.Language{Name: languagemodels.Elixir}, nil
+	}
+
+	lang := languagemodels.Language{Name: languagemodels.Erlang}
+	if vmRoot != "" {
+		if versions, _ := filepath.Glob(filepath.Join(rootfs, vmRoot, "releases", "*", "OTP_VERSION")); len(versions) > 0 {
+			sort.Strings(versions)
+			if content, err := os.ReadFile(versions[len(versions)-1]); err == nil {
+				lang.Version = strings.TrimSpace(string(content))
+			}
+		}
+	}
+
+	return lang, nil
+}
+
+// argValue returns the value following a flag in a command line.
+func argValue(args []string, flag string) string {
+	for i, arg := range args {
+		if arg == flag && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package detectors
+
+import (
+	"bytes"
+	"debug/elf"
+	"io"
+)
+
+// sectionChunkSize is the size of the chunks in which ELF sections are
+// searched, so that large sections are not read at once.
+const sectionChunkSize = 1 << 20
+
+// findInSection searches an ELF section for needle, and returns up to
+// suffixLen bytes that follow its first occurrence.
+func findInSection(bin *elf.File, name string, needle []byte, suffixLen int) ([]byte, bool) {
+	sec := bin.Section(name)
+	if sec == nil || sec.Type == elf.SHT_NOBITS {
+		return nil, false
+	}
+
+	r := sec.Open()
+	overlap := len(needle) + suffixLen
+	chunk := make([]byte, sectionChunkSize)
+	var buf []byte
+
+	for {
+		n, err := io.ReadFull(r, chunk)
+		buf = append(buf, chunk[:n]...)
+		eof := err != nil
+
+		if idx := bytes.Index(buf, needle); idx != -1 {
+			start := idx + len(needle)
+			if end := start + suffixLen; end <= len(buf) {
+				return buf[start:end], true
+			}
+			if eof {
+				return buf[start:], true
+			}
+			// read the rest of the suffix
+			continue
+		}
+
+		if eof {
+			return nil, false
+		}
+
+		// keep the end of the buffer, in case the needle spans two chunks
+		if len(buf) > overlap {
+			copy(buf, buf[len(buf)-overlap:])
+			buf = buf[:overlap]
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package detectors
+
+import (
+	"debug/elf"
+	"regexp"
+
+	dderrors "github.com/DataDog/datadog-agent/pkg/errors"
+	"github.com/DataDog/datadog-agent/pkg/languagedetection/languagemodels"
+	"github.com/DataDog/datadog-agent/pkg/util/kernel"
+)
+
+// phpExePattern matches the names of the PHP interpreters: php, php-fpm and
+// php-cgi, optionally suffixed with their version by distributions.
+var phpExePattern = regexp.MustCompile(`^php(?:-fpm|-cgi)?(\d+(?:\.\d+)?)?$`)
+
+// phpVersionHeader is the X-Powered-By header sent by PHP, which embeds its
+// full version in the binary, e.g. "X-Powered-By: PHP/8.2.7".
+var phpVersionHeader = []byte("X-Powered-By: PHP/")
+
+var phpFullVersionPattern = regexp.MustCompile(`^\d+\.\d+\.\d+[0-9A-Za-z.\-]*`)
+
+// PHPDetector is a languagedetection.Detector that detects PHP interpreters,
+// including the PHP-FPM master and pool processes.
+type PHPDetector struct {
+	hostProc string
+}
+
+// NewPHPDetector returns a new PHPDetector
+func NewPHPDetector() PHPDetector {
+	return PHPDetector{hostProc: kernel.ProcFSRoot()}
+}
+
+// DetectLanguage detects PHP interpreters by the name of their binary. The
+// version is read from the binary, or from its name if that fails.
+func (d PHPDetector) DetectLanguage(process languagemodels.Process) (languagemodels.Language, error) {
+	name, err := exeName(d.hostProc, process.GetPid())
+	if err != nil {
+		return languagemodels.Language{}, err
+	}
+
+	match := phpExePattern.FindStringSubmatch(name)
+	if match == nil {
+		return languagemodels.Language{}, dderrors.NewNotFound("php binary")
+	}
+
+	lang := languagemodels.Language{Name: languagemodels.PHP, Version: match[1]}
+
+	bin, err := elf.Open(procPath(d.hostProc, process.GetPid(), "exe"))
+	if err != nil {
+		return lang, nil
+	}
+	defer bin.Close()
+
+	if suffix, found := findInSection(bin, ".rodata", phpVersionHeader, 32); found {
+		if version := phpFullVersionPattern.Find(suffix); version != nil {
+			lang.Version = string(version)
+		}
+	}
+
+	return lang, nil
+}
@@ -0,0 +1,52 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package detectors
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/languagedetection/languagemodels"
+)
+
+const (
+	phpFPMMasterTitle = "php-fpm: master process"
+	phpFPMPoolTitle   = "php-fpm: pool "
+)
+
+// phpVersionPattern matches the PHP version in the name of a PHP binary, such
+// as php-fpm8.2, or in the path of a PHP configuration file, such as
+// /etc/php/8.2/fpm/php-fpm.conf.
+var phpVersionPattern = regexp.MustCompile(`php(?:-fpm)?/?(\d+\.\d+)`)
+
+// PHPFPMDetector is a languagedetection.Detector that detects PHP-FPM
+// processes, which replace their command line with a process title.
+type PHPFPMDetector struct{}
+
+// DetectLanguage detects the PHP-FPM master and pool processes. The version
+// is taken from the name of the binary or from the path of the configuration
+// file, when it includes it.
+func (d PHPFPMDetector) DetectLanguage(process languagemodels.Process) (languagemodels.Language, error) {
+	cmdline := process.GetCmdline()
+	if len(cmdline) == 0 {
+		return languagemodels.Language{Name: languagemodels.Unknown}, nil
+	}
+
+	title := strings.Join(cmdline, " ")
+	if !strings.HasPrefix(title, phpFPMMasterTitle) && !strings.HasPrefix(title, phpFPMPoolTitle) {
+		return languagemodels.Language{Name: languagemodels.Unknown}, nil
+	}
+
+	lang := languagemodels.Language{Name: languagemodels.PHP}
+	for _, s := range []string{process.GetCommand(), title} {
+		if match := phpVersionPattern.FindStringSubmatch(s); match != nil {
+			lang.Version = match[1]
+			break
+		}
+	}
+
+	return lang, nil
+}
@@ -0,0 +1,56 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package detectors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/languagedetection/languagemodels"
+	"github.com/DataDog/datadog-agent/pkg/proto/pbgo/languagedetection"
+)
+
+func TestPHPFPMDetector(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		cmdline  []string
+		comm     string
+		expected languagemodels.Language
+	}{
+		{
+			name:     "master with versioned configuration",
+			cmdline:  []string{"php-fpm: master process (/etc/php/8.2/fpm/php-fpm.conf)"},
+			comm:     "php-fpm",
+			expected: languagemodels.Language{Name: languagemodels.PHP, Version: "8.2"},
+		},
+		{
+			name:     "pool with versioned binary",
+			cmdline:  []string{"php-fpm:", "pool", "www"},
+			comm:     "php-fpm7.4",
+			expected: languagemodels.Language{Name: languagemodels.PHP, Version: "7.4"},
+		},
+		{
+			name:     "master without version",
+			cmdline:  []string{"php-fpm: master process (/usr/local/etc/php-fpm.conf)"},
+			comm:     "php-fpm",
+			expected: languagemodels.Language{Name: languagemodels.PHP},
+		},
+		{
+			name:     "not php-fpm",
+			cmdline:  []string{"nginx: master process /usr/sbin/nginx"},
+			comm:     "nginx",
+			expected: languagemodels.Language{Name: languagemodels.Unknown},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			lang, err := PHPFPMDetector{}.DetectLanguage(&languagedetection.Process{Cmdline: tc.cmdline, Command: tc.comm})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, lang)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package detectors
+
+import (
+	"debug/elf"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dderrors "github.com/DataDog/datadog-agent/pkg/errors"
+	"github.com/DataDog/datadog-agent/pkg/languagedetection/languagemodels"
+	"github.com/DataDog/datadog-agent/pkg/proto/pbgo/languagedetection"
+)
+
+// testMarker is searched in the read-only data of the test binary
+var testMarker = "dd-language-detection-test-marker:1.2.3"
+
+const testPid = 42
+
+// fakeProcFS creates the procfs directory of a process whose executable has
+// the given name, and returns the procfs root.
+func fakeProcFS(t *testing.T, exe string, files map[string]string) string {
+	hostProc := t.TempDir()
+	procDir := filepath.Join(hostProc, "42")
+	require.NoError(t, os.MkdirAll(procDir, 0755))
+
+	exePath := filepath.Join(t.TempDir(), exe)
+	require.NoError(t, os.WriteFile(exePath, []byte("not an ELF binary"), 0755))
+	require.NoError(t, os.Symlink(exePath, filepath.Join(procDir, "exe")))
+
+	for name, content := range files {
+		path := filepath.Join(procDir, "root", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	return hostProc
+}
+
+func TestFindInSection(t *testing.T) {
+	bin, err := elf.Open("/proc/self/exe")
+	require.NoError(t, err)
+	defer bin.Close()
+
+	suffix, found := findInSection(bin, ".rodata", []byte(strings.TrimSuffix(testMarker, "1.2.3")), 5)
+	require.True(t, found)
+	assert.Equal(t, "1.2.3", string(suffix))
+
+	_, found = findInSection(bin, ".rodata", []byte(strings.ToUpper(testMarker)), 5)
+	assert.False(t, found)
+}
+
+func TestRustcVersionFromComment(t *testing.T) {
+	version, found := rustcVersionFromComment([]byte("GCC: (GNU) 13.2.1\x00rustc version 1.75.0 (82e1608df 2023-12-21)\x00"))
+	assert.True(t, found)
+	assert.Equal(t, "1.75.0", version)
+
+	_, found = rustcVersionFromComment([]byte("GCC: (GNU) 13.2.1\x00"))
+	assert.False(t, found)
+}
+
+func TestRustDetectorNotRust(t *testing.T) {
+	// the test binary is a Go binary
+	_, err := RustDetector{hostProc: "/proc"}.DetectLanguage(&languagedetection.Process{Pid: int32(os.Getpid())})
+	assert.True(t, dderrors.IsNotFound(err))
+}
+
+func TestPHPDetector(t *testing.T) {
+	hostProc := fakeProcFS(t, "php-fpm8.2", nil)
+	lang, err := PHPDetector{hostProc: hostProc}.DetectLanguage(&languagedetection.Process{Pid: testPid})
+	require.NoError(t, err)
+	assert.Equal(t, languagemodels.Language{Name: languagemodels.PHP, Version: "8.2"}, lang)
+
+	hostProc = fakeProcFS(t, "phpunit", nil)
+	_, err = PHPDetector{hostProc: hostProc}.DetectLanguage(&languagedetection.Process{Pid: testPid})
+	assert.True(t, dderrors.IsNotFound(err))
+}
+
+func TestBEAMReleaseDetector(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		exe      string
+		cmdline  []string
+		files    map[string]string
+		expected languagemodels.Language
+	}{
+		{
+			name:    "erlang installation",
+			exe:     "beam.smp",
+			cmdline: []string{"/usr/lib/erlang/erts-14.1/bin/beam.smp", "--", "-root", "/usr/lib/erlang", "-progname", "erl"},
+			files: map[string]string{
+				"usr/lib/erlang/releases/26/OTP_VERSION": "26.1.2\n",
+			},
+			expected: languagemodels.Language{Name: languagemodels.Erlang, Version: "26.1.2"},
+		},
+		{
+			name:    "elixir launcher",
+			exe:     "beam.smp",
+			cmdline: []string{"/usr/lib/erlang/erts-14.1/bin/beam.smp", "--", "-root", "/usr/lib/erlang", "--", "-elixir_root", "/usr/lib/elixir/lib", "-s", "elixir", "start_cli"},
+			files: map[string]string{
+				"usr/lib/elixir/lib/elixir/ebin/elixir.app": `{application,elixir,[{description,"elixir"},{vsn,"1.15.7"},{modules,[]}]}.`,
+			},
+			expected: languagemodels.Language{Name: languagemodels.Elixir, Version: "1.15.7"},
+		},
+		{
+			name:    "elixir release",
+			exe:     "beam.smp",
+			cmdline: []string{"/app/erts-14.1/bin/beam.smp", "--", "-root", "/app", "-progname", "erl", "--", "-boot", "/app/releases/0.1.0/start"},
+			files: map[string]string{
+				"app/lib/elixir-1.16.0/ebin/elixir.app": "",
+				"app/lib/myapp-0.1.0/ebin/myapp.app":    "",
+			},
+			expected: languagemodels.Language{Name: languagemodels.Elixir, Version: "1.16.0"},
+		},
+		{
+			name:     "erlang release without OTP version",
+			exe:      "beam.smp",
+			cmdline:  []string{"/app/erts-14.1/bin/beam.smp", "--", "-root", "/app"},
+			expected: languagemodels.Language{Name: languagemodels.Erlang},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			hostProc := fakeProcFS(t, tc.exe, tc.files)
+			lang, err := BEAMReleaseDetector{hostProc: hostProc}.DetectLanguage(&languagedetection.Process{Pid: testPid, Cmdline: tc.cmdline})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, lang)
+		})
+	}
+
+	hostProc := fakeProcFS(t, "python3", nil)
+	_, err := BEAMReleaseDetector{hostProc: hostProc}.DetectLanguage(&languagedetection.Process{Pid: testPid})
+	assert.True(t, dderrors.IsNotFound(err))
+}
@@ -0,0 +1,45 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package detectors
+
+import (
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/languagedetection/languagemodels"
+)
+
+// procPath returns the path of a file in the procfs directory of a process.
+func procPath(hostProc string, pid int32, elem ...string) string {
+	return path.Join(append([]string{hostProc, strconv.FormatInt(int64(pid), 10)}, elem...)...)
+}
+
+// exeName returns the name of the executable of a process.
+func exeName(hostProc string, pid int32) (string, error) {
+	exe, err := os.Readlink(procPath(hostProc, pid, "exe"))
+	if err != nil {
+		return "", err
+	}
+	return path.Base(strings.TrimSuffix(exe, " (deleted)")), nil
+}
+
+// cmdline returns the command line of a process, reading it from procfs if
+// the caller did not provide it.
+func cmdline(hostProc string, process languagemodels.Process) []string {
+	if provided := process.GetCmdline(); len(provided) > 0 {
+		return provided
+	}
+
+	content, err := os.ReadFile(procPath(hostProc, process.GetPid(), "cmdline"))
+	if err != nil {
+		return nil
+	}
+	return strings.Split(strings.TrimRight(string(content), "\x00"), "\x00")
+}
@@ -0,0 +1,84 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package detectors
+
+import (
+	"bytes"
+	"debug/elf"
+	"fmt"
+	"strings"
+
+	dderrors "github.com/DataDog/datadog-agent/pkg/errors"
+	"github.com/DataDog/datadog-agent/pkg/languagedetection/languagemodels"
+	"github.com/DataDog/datadog-agent/pkg/util/kernel"
+)
+
+const rustcCommentPrefix = "rustc version "
+
+// rustcSourcePath is embedded in the panic locations of the standard library
+// of binaries built by rustc, e.g. /rustc/<commit hash>/library/core/...
+var rustcSourcePath = []byte("/rustc/")
+
+// RustDetector is a languagedetection.Detector that detects Rust binaries.
+type RustDetector struct {
+	hostProc string
+}
+
+// NewRustDetector returns a new RustDetector
+func NewRustDetector() RustDetector {
+	return RustDetector{hostProc: kernel.ProcFSRoot()}
+}
+
+// DetectLanguage detects Rust binaries with the metadata left by rustc. The
+// version comes from the .comment section, which is kept by most builds; the
+// binaries without it are recognized by the paths of the standard library in
+// their read-only data, without version.
+func (d RustDetector) DetectLanguage(process languagemodels.Process) (languagemodels.Language, error) {
+	bin, err := elf.Open(procPath(d.hostProc, process.GetPid(), "exe"))
+	if err != nil {
+		return languagemodels.Language{}, fmt.Errorf("open: %v", err)
+	}
+	defer bin.Close()
+
+	if sec := bin.Section(".comment"); sec != nil {
+		if comment, err := sec.Data(); err == nil {
+			if version, ok := rustcVersionFromComment(comment); ok {
+				return languagemodels.Language{Name: languagemodels.Rust, Version: version}, nil
+			}
+		}
+	}
+
+	// dynamic libraries and proc macros built by rustc have a .rustc section
+	if bin.Section(".rustc") != nil {
+		return languagemodels.Language{Name: languagemodels.Rust}, nil
+	}
+
+	if _, found := findInSection(bin, ".rodata", rustcSourcePath, 0); found {
+		return languagemodels.Language{Name: languagemodels.Rust}, nil
+	}
+
+	return languagemodels.Language{}, dderrors.NewNotFound("rustc metadata")
+}
+
+// rustcVersionFromComment returns the version of rustc in the content of an
+// ELF .comment section, made of NUL-terminated strings such as
+// "rustc version 1.75.0 (82e1608df 2023-12-21)".
+func rustcVersionFromComment(comment []byte) (string, bool) {
+	for _, entry := range bytes.Split(comment, []byte{0}) {
+		s := string(entry)
+		if !strings.HasPrefix(s, rustcCommentPrefix) {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(s, rustcCommentPrefix))
+		if len(fields) == 0 {
+			return "", true
+		}
+		return fields[0], true
+	}
+	return "", false
+}
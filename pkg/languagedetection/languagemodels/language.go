@@ -22,6 +22,14 @@ const (
 	//nolint:revive // TODO(PROC) Fix revive linter
 	Ruby LanguageName = "ruby"
 	//nolint:revive // TODO(PROC) Fix revive linter
+	PHP LanguageName = "php"
+	//nolint:revive // TODO(PROC) Fix revive linter
+	Rust LanguageName = "rust"
+	//nolint:revive // TODO(PROC) Fix revive linter
+	Erlang LanguageName = "erlang"
+	//nolint:revive // TODO(PROC) Fix revive linter
+	Elixir LanguageName = "elixir"
+	//nolint:revive // TODO(PROC) Fix revive linter
 	Unknown LanguageName = ""
 )
 
@@ -30,6 +30,16 @@ import (
 
 var detectorsWithPrivilege = []languagemodels.Detector{
 	detectors.NewGoDetector(),
+	detectors.NewRustDetector(),
+	detectors.NewPHPDetector(),
+	detectors.NewBEAMReleaseDetector(),
+}
+
+// processDependentDetector is implemented by the detectors whose result
+// depends on the process, and not only on its binary, such as the detectors
+// of virtual machines running different languages.
+type processDependentDetector interface {
+	ProcessDependent() bool
 }
 
 var (
@@ -84,16 +94,23 @@ func (l *LanguageDetector) DetectWithPrivileges(procs []languagemodels.Process)
 		}
 
 		var lang languagemodels.Language
+		cacheable := true
 		for _, detector := range l.detectors {
-			var err error
-			lang, err = detector.DetectLanguage(proc)
+			detected, err := detector.DetectLanguage(proc)
 			if err != nil {
 				handleDetectorError(err)
 				continue
 			}
-			languages[i] = lang
+			lang = detected
+			if d, ok := detector.(processDependentDetector); ok && d.ProcessDependent() {
+				cacheable = false
+			}
+			break
+		}
+		languages[i] = lang
+		if cacheable {
+			l.binaryIDCache.Add(bin, lang)
 		}
-		l.binaryIDCache.Add(bin, lang)
 	}
 	return languages
 }
@@ -25,6 +25,10 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
+// systemdUnitServiceType is the type of the AD services of systemd units,
+// see comp/core/autodiscovery/listeners.
+const systemdUnitServiceType = "systemd_unit"
+
 // Scheduler creates and deletes new sources and services to start or stop
 // log collection based on information from autodiscovery.
 //
@@ -196,7 +200,15 @@ func (s *Scheduler) createSources(config integration.Config) ([]*sourcesPkg.LogS
 			cfg.Service = commonGlobalOptions.Service
 		}
 
-		if service != nil {
+		if service != nil && service.Type == systemdUnitServiceType {
+			// logs of a systemd unit are read from the journal, restricted
+			// to the unit unless the template says otherwise.
+			cfg.Type = logsConfig.JournaldType
+			if len(cfg.IncludeSystemUnits) == 0 {
+				cfg.IncludeSystemUnits = []string{service.Identifier}
+			}
+			cfg.Identifier = service.Identifier
+		} else if service != nil {
 			// a config defined in a container label or a pod annotation does not always contain a type,
 			// override it here to ensure that the config won't be dropped at validation.
 			if (cfg.Type == logsConfig.FileType || cfg.Type == logsConfig.TCPType || cfg.Type == logsConfig.UDPType) && (config.Provider == names.Kubernetes || config.Provider == names.Container || config.Provider == names.KubeContainer || config.Provider == logsConfig.FileType) {
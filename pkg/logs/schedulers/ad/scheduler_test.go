@@ -79,6 +79,28 @@ func TestScheduleTCPConfig(t *testing.T) {
 	assert.Equal(t, "a1887023ed72a2b0d083ef465e8edfe4932a25731d4bda2f39f288f70af3405b", logSource.Config.Identifier)
 }
 
+func TestScheduleSystemdUnitConfig(t *testing.T) {
+	scheduler, spy := setup()
+	configSource := integration.Config{
+		LogsConfig:    []byte("logs:\n- service: foo\n  source: nginx\n"),
+		ADIdentifiers: []string{"_systemd.nginx.service"},
+		Provider:      names.File,
+		TaggerEntity:  "systemd_unit://nginx.service",
+		ServiceID:     "systemd_unit://nginx.service",
+		ClusterCheck:  false,
+	}
+
+	scheduler.Schedule([]integration.Config{configSource})
+
+	require.Equal(t, 1, len(spy.Events))
+	require.True(t, spy.Events[0].Add)
+	logSource := spy.Events[0].Source
+	assert.Equal(t, "foo", logSource.Config.Service)
+	assert.Equal(t, config.JournaldType, logSource.Config.Type)
+	assert.Equal(t, []string{"nginx.service"}, logSource.Config.IncludeSystemUnits)
+	assert.Equal(t, "nginx.service", logSource.Config.Identifier)
+}
+
 func TestScheduleUDPConfig(t *testing.T) {
 	scheduler, spy := setup()
 	configSource := integration.Config{
@@ -0,0 +1,30 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build systemd
+
+package journald
+
+import (
+	"github.com/coreos/go-systemd/sdjournal"
+
+	"github.com/DataDog/datadog-agent/comp/core/tagger"
+	"github.com/DataDog/datadog-agent/comp/core/tagger/types"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// getSystemdUnitTags returns the tags of the systemd unit the entry comes from,
+// they are only available when the systemd units collection is enabled.
+func (t *Tailer) getSystemdUnitTags(entry *sdjournal.JournalEntry) []string {
+	unit, exists := entry.Fields[sdjournal.SD_JOURNAL_FIELD_SYSTEMD_UNIT]
+	if !exists || unit == "" {
+		return nil
+	}
+	tags, err := tagger.Tag("systemd_unit://"+unit, types.HighCardinality)
+	if err != nil {
+		log.Debug(err)
+	}
+	return tags
+}
@@ -437,6 +437,8 @@ func (t *Tailer) getTags(entry *sdjournal.JournalEntry) []string {
 	var tags []string
 	if t.isContainerEntry(entry) {
 		tags = t.getContainerTags(t.getContainerID(entry))
+	} else {
+		tags = t.getSystemdUnitTags(entry)
 	}
 	return tags
 }
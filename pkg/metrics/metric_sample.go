@@ -8,6 +8,8 @@ package metrics
 import (
 	taggertypes "github.com/DataDog/datadog-agent/pkg/tagger/types"
 	"github.com/DataDog/datadog-agent/pkg/tagset"
+
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/quantile"
 )
 
 // MetricType is the representation of an aggregator metric type
@@ -108,6 +110,9 @@ type MetricSample struct {
 	ListenerID      string
 	NoIndex         bool
 	Source          MetricSource
+	// Sketch holds the values of a distribution aggregated by the client. When
+	// set, it is merged as a whole instead of Value being inserted.
+	Sketch *quantile.Sketch
 }
 
 // Implement the MetricSampleContext interface
@@ -86,6 +86,20 @@ type Config struct {
 	// EnablePostgresMonitoring specifies whether the tracer should monitor Postgres traffic.
 	EnablePostgresMonitoring bool
 
+	// EnableMemcachedMonitoring specifies whether the tracer should monitor Memcached traffic.
+	// The stats are not part of the connections payload yet, they are only exposed by the
+	// /debug/memcached_monitoring endpoint.
+	EnableMemcachedMonitoring bool
+
+	// EnableCassandraMonitoring specifies whether the tracer should monitor Cassandra traffic.
+	// The stats are not part of the connections payload yet, they are only exposed by the
+	// /debug/cassandra_monitoring endpoint.
+	EnableCassandraMonitoring bool
+
+	// EnableDNSOverTLSMonitoring specifies whether the tracer should collect DNS stats from DNS over TLS traffic,
+	// decrypted by the TLS uprobes. It is relevant *only* when CollectDNSStats and TLS monitoring are enabled.
+	EnableDNSOverTLSMonitoring bool
+
 	// EnableNativeTLSMonitoring specifies whether the USM should monitor HTTPS traffic via native libraries.
 	// Supported libraries: OpenSSL, GnuTLS, LibCrypto.
 	EnableNativeTLSMonitoring bool
@@ -186,6 +200,14 @@ type Config struct {
 	// get flushed on every client request (default 30s check interval)
 	MaxPostgresStatsBuffered int
 
+	// MaxMemcachedStatsBuffered represents the maximum number of Memcached stats we'll buffer in memory. These stats
+	// get flushed on every client request (default 30s check interval)
+	MaxMemcachedStatsBuffered int
+
+	// MaxCassandraStatsBuffered represents the maximum number of Cassandra stats we'll buffer in memory. These stats
+	// get flushed on every client request (default 30s check interval)
+	MaxCassandraStatsBuffered int
+
 	// MaxConnectionsStateBuffered represents the maximum number of state objects that we'll store in memory. These state objects store
 	// the stats for a connection so we can accurately determine traffic change between client requests.
 	MaxConnectionsStateBuffered int
@@ -241,6 +263,14 @@ type Config struct {
 	// RecordedQueryTypes enables specific DNS query types to be recorded
 	RecordedQueryTypes []string
 
+	// EnableDNSLatencySketches specifies whether DNS stats should hold a latency distribution per response code.
+	// The distributions are not part of the connections payload, they are only exposed by the /debug/dns_monitoring endpoint.
+	EnableDNSLatencySketches bool
+
+	// DNSDomainGroups is a list of domain suffixes. DNS stats of the domains matching one of them are grouped under
+	// a single `*.<suffix>` domain, to bound the cardinality of the stats.
+	DNSDomainGroups []string
+
 	// HTTP replace rules
 	HTTPReplaceRules []*ReplaceRule
 
@@ -350,17 +380,22 @@ func New() *Config {
 
 		NPMRingbuffersEnabled: cfg.GetBool(join(netNS, "enable_ringbuffers")),
 
-		EnableHTTPMonitoring:      cfg.GetBool(join(smNS, "enable_http_monitoring")),
-		EnableHTTP2Monitoring:     cfg.GetBool(join(smNS, "enable_http2_monitoring")),
-		EnableKafkaMonitoring:     cfg.GetBool(join(smNS, "enable_kafka_monitoring")),
-		EnablePostgresMonitoring:  cfg.GetBool(join(smNS, "enable_postgres_monitoring")),
-		EnableNativeTLSMonitoring: cfg.GetBool(join(smNS, "tls", "native", "enabled")),
-		EnableIstioMonitoring:     cfg.GetBool(join(smNS, "tls", "istio", "enabled")),
-		EnableNodeJSMonitoring:    cfg.GetBool(join(smNS, "tls", "nodejs", "enabled")),
-		MaxUSMConcurrentRequests:  uint32(cfg.GetInt(join(smNS, "max_concurrent_requests"))),
-		MaxHTTPStatsBuffered:      cfg.GetInt(join(smNS, "max_http_stats_buffered")),
-		MaxKafkaStatsBuffered:     cfg.GetInt(join(smNS, "max_kafka_stats_buffered")),
-		MaxPostgresStatsBuffered:  cfg.GetInt(join(smNS, "max_postgres_stats_buffered")),
+		EnableHTTPMonitoring:       cfg.GetBool(join(smNS, "enable_http_monitoring")),
+		EnableHTTP2Monitoring:      cfg.GetBool(join(smNS, "enable_http2_monitoring")),
+		EnableKafkaMonitoring:      cfg.GetBool(join(smNS, "enable_kafka_monitoring")),
+		EnablePostgresMonitoring:   cfg.GetBool(join(smNS, "enable_postgres_monitoring")),
+		EnableMemcachedMonitoring:  cfg.GetBool(join(smNS, "enable_memcached_monitoring")),
+		EnableCassandraMonitoring:  cfg.GetBool(join(smNS, "enable_cassandra_monitoring")),
+		EnableDNSOverTLSMonitoring: cfg.GetBool(join(smNS, "enable_dns_over_tls_monitoring")),
+		EnableNativeTLSMonitoring:  cfg.GetBool(join(smNS, "tls", "native", "enabled")),
+		EnableIstioMonitoring:      cfg.GetBool(join(smNS, "tls", "istio", "enabled")),
+		EnableNodeJSMonitoring:     cfg.GetBool(join(smNS, "tls", "nodejs", "enabled")),
+		MaxUSMConcurrentRequests:   uint32(cfg.GetInt(join(smNS, "max_concurrent_requests"))),
+		MaxHTTPStatsBuffered:       cfg.GetInt(join(smNS, "max_http_stats_buffered")),
+		MaxKafkaStatsBuffered:      cfg.GetInt(join(smNS, "max_kafka_stats_buffered")),
+		MaxPostgresStatsBuffered:   cfg.GetInt(join(smNS, "max_postgres_stats_buffered")),
+		MaxMemcachedStatsBuffered:  cfg.GetInt(join(smNS, "max_memcached_stats_buffered")),
+		MaxCassandraStatsBuffered:  cfg.GetInt(join(smNS, "max_cassandra_stats_buffered")),
 
 		MaxTrackedHTTPConnections: cfg.GetInt64(join(smNS, "max_tracked_http_connections")),
 		HTTPNotificationThreshold: cfg.GetInt64(join(smNS, "http_notification_threshold")),
@@ -379,7 +414,9 @@ func New() *Config {
 
 		EnableMonotonicCount: cfg.GetBool(join(spNS, "windows.enable_monotonic_count")),
 
-		RecordedQueryTypes: cfg.GetStringSlice(join(netNS, "dns_recorded_query_types")),
+		RecordedQueryTypes:       cfg.GetStringSlice(join(netNS, "dns_recorded_query_types")),
+		EnableDNSLatencySketches: cfg.GetBool(join(netNS, "enable_dns_latency_sketches")),
+		DNSDomainGroups:          cfg.GetStringSlice(join(netNS, "dns_domain_groups")),
 
 		EnableProcessEventMonitoring: cfg.GetBool(join(evNS, "network_process", "enabled")),
 		MaxProcessesTracked:          cfg.GetInt(join(evNS, "network_process", "max_processes_tracked")),
@@ -13,7 +13,8 @@ import (
 
 // DNSKey generates a key suitable for looking up DNS stats based on a ConnectionStats object
 func DNSKey(c *ConnectionStats) (dns.Key, bool) {
-	if c == nil || c.DPort != 53 {
+	// DNS over TLS servers listen on port 853
+	if c == nil || (c.DPort != 53 && c.DPort != 853) {
 		return dns.Key{}, false
 	}
 
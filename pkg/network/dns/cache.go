@@ -241,6 +241,17 @@ type translation struct {
 	ips map[util.Address]time.Time
 }
 
+// reset empties the translation so it can be reused
+func (t *translation) reset() {
+	// Recycle buffer if necessary
+	if t.ips == nil || len(t.ips) > maxIPBufferSize {
+		t.ips = make(map[util.Address]time.Time, 30)
+	}
+	for k := range t.ips {
+		delete(t.ips, k)
+	}
+}
+
 func (t *translation) add(addr util.Address, ttl time.Duration) {
 	if _, ok := t.ips[addr]; ok {
 		return
@@ -0,0 +1,102 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package debugging provides debug-friendly representations of internal data structures
+package debugging
+
+import (
+	"github.com/DataDog/sketches-go/ddsketch"
+	"github.com/google/gopacket/layers"
+
+	"github.com/DataDog/datadog-agent/pkg/network"
+	"github.com/DataDog/datadog-agent/pkg/network/dns"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// key represents a (server, domain, query type) tuple
+type key struct {
+	Server    string
+	Domain    string
+	QueryType string
+}
+
+// RcodeStats consolidates the count and latency information of the responses with a certain response code.
+// Latencies are in nanoseconds, and are only reported when DNS latency sketches are enabled.
+type RcodeStats struct {
+	Count      uint32
+	LatencyP50 float64
+	LatencyP95 float64
+	LatencyP99 float64
+	latencies  *ddsketch.DDSketch
+}
+
+// Summary represents a (debug-friendly) aggregated view of the DNS stats
+// matching a (server, domain, query type) tuple
+type Summary struct {
+	key
+	Timeouts uint32
+	ByRcode  map[string]RcodeStats
+}
+
+// DNS returns a debug-friendly representation of the DNS stats of the given connections
+func DNS(conns []network.ConnectionStats) []Summary {
+	resMap := make(map[key]*Summary)
+	for i := range conns {
+		c := &conns[i]
+		dnsKey, ok := network.DNSKey(c)
+		if !ok {
+			continue
+		}
+		for domain, statsByType := range c.DNSStats {
+			for qtype, stats := range statsByType {
+				tempKey := key{
+					Server:    dnsKey.ServerIP.String(),
+					Domain:    dns.ToString(domain),
+					QueryType: layers.DNSType(qtype).String(),
+				}
+				summary, ok := resMap[tempKey]
+				if !ok {
+					summary = &Summary{key: tempKey, ByRcode: make(map[string]RcodeStats)}
+					resMap[tempKey] = summary
+				}
+				summary.Timeouts += stats.Timeouts
+				for rcode, count := range stats.CountByRcode {
+					rcodeName := layers.DNSResponseCode(rcode).String()
+					rcodeStats := summary.ByRcode[rcodeName]
+					rcodeStats.Count += count
+					if sketch := stats.Latencies[rcode]; sketch != nil {
+						if rcodeStats.latencies == nil {
+							rcodeStats.latencies = sketch.Copy()
+						} else if err := rcodeStats.latencies.MergeWith(sketch); err != nil {
+							log.Debugf("could not merge DNS latencies: %v", err)
+						}
+					}
+					summary.ByRcode[rcodeName] = rcodeStats
+				}
+			}
+		}
+	}
+
+	all := make([]Summary, 0, len(resMap))
+	for _, summary := range resMap {
+		for rcode, stats := range summary.ByRcode {
+			stats.LatencyP50 = getSketchQuantile(stats.latencies, 0.5)
+			stats.LatencyP95 = getSketchQuantile(stats.latencies, 0.95)
+			stats.LatencyP99 = getSketchQuantile(stats.latencies, 0.99)
+			summary.ByRcode[rcode] = stats
+		}
+		all = append(all, *summary)
+	}
+	return all
+}
+
+func getSketchQuantile(sketch *ddsketch.DDSketch, percentile float64) float64 {
+	if sketch == nil {
+		return 0.0
+	}
+
+	val, _ := sketch.GetValueAtQuantile(percentile)
+	return val
+}
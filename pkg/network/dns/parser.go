@@ -9,6 +9,7 @@ package dns
 
 import (
 	"bytes"
+	"strings"
 	"syscall"
 	"time"
 
@@ -69,6 +70,15 @@ type dnsParser struct {
 	collectDNSStats    bool
 	collectDNSDomains  bool
 	recordedQueryTypes map[layers.DNSType]struct{}
+	domainGroups       []domainGroup
+}
+
+// domainGroup is a domain suffix under which the stats of all its subdomains are grouped
+type domainGroup struct {
+	// suffix is the domain suffix, including the leading dot
+	suffix []byte
+	// hostname is the name the subdomains are reported as, `*.<suffix>`
+	hostname Hostname
 }
 
 func newDNSParser(layerType gopacket.LayerType, cfg *config.Config) *dnsParser {
@@ -105,6 +115,7 @@ func newDNSParser(layerType gopacket.LayerType, cfg *config.Config) *dnsParser {
 		collectDNSStats:    cfg.CollectDNSStats,
 		collectDNSDomains:  cfg.CollectDNSDomains,
 		recordedQueryTypes: queryTypes,
+		domainGroups:       getDomainGroups(cfg),
 	}
 }
 
@@ -194,7 +205,7 @@ func (p *dnsParser) parseAnswerInto(
 		pktInfo.pktType = query
 		pktInfo.queryType = QueryType(question.Type)
 		if p.collectDNSDomains {
-			pktInfo.question = ToHostname(string(question.Name))
+			pktInfo.question = p.questionHostname(question.Name)
 		} else {
 			pktInfo.question = ToHostname("")
 		}
@@ -217,6 +228,16 @@ func (p *dnsParser) parseAnswerInto(
 	return nil
 }
 
+// questionHostname returns the hostname the stats of the queried domain are reported under
+func (p *dnsParser) questionHostname(name []byte) Hostname {
+	for _, group := range p.domainGroups {
+		if len(name) > len(group.suffix) && bytes.EqualFold(name[len(name)-len(group.suffix):], group.suffix) {
+			return group.hostname
+		}
+	}
+	return ToHostname(string(name))
+}
+
 func (*dnsParser) extractCNAME(domainQueried []byte, records []layers.DNSResourceRecord) []byte {
 	alias := domainQueried
 	for _, record := range records {
@@ -274,6 +295,25 @@ func getRecordedQueryTypes(cfg *config.Config) map[layers.DNSType]struct{} {
 	return queryTypes
 }
 
+func getDomainGroups(cfg *config.Config) []domainGroup {
+	var groups []domainGroup
+	for _, suffix := range cfg.DNSDomainGroups {
+		suffix = strings.Trim(strings.TrimPrefix(strings.TrimSpace(strings.ToLower(suffix)), "*"), ".")
+		if suffix == "" {
+			log.Warnf("Empty DNS domain group, skipping")
+			continue
+		}
+		groups = append(groups, domainGroup{
+			suffix:   []byte("." + suffix),
+			hostname: ToHostname("*." + suffix),
+		})
+	}
+	if len(groups) > 0 {
+		log.Infof("Grouping dns stats of %d domain suffixes", len(groups))
+	}
+	return groups
+}
+
 // inplaceASCIILower is an optimized, replace inplace version of bytes.ToLower
 // for byte slices knowing they only contain ASCII characters.
 func inplaceASCIILower(s []byte) {
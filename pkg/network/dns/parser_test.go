@@ -0,0 +1,176 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build (windows && npm) || linux_bpf
+
+package dns
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+)
+
+type pcapPacket struct {
+	data []byte
+	ts   time.Time
+}
+
+func readPcap(t *testing.T, name string) []pcapPacket {
+	f, err := os.Open(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	defer f.Close()
+
+	r, err := pcapgo.NewReader(f)
+	require.NoError(t, err)
+	require.Equal(t, layers.LinkTypeEthernet, r.LinkType())
+
+	var packets []pcapPacket
+	for {
+		data, ci, err := r.ReadPacketData()
+		if errors.Is(err, io.EOF) {
+			return packets
+		}
+		require.NoError(t, err)
+		packets = append(packets, pcapPacket{data: data, ts: ci.Timestamp})
+	}
+}
+
+func newTestParser(domainGroups ...string) *dnsParser {
+	return newDNSParser(layers.LayerTypeEthernet, &config.Config{
+		CollectDNSStats:    true,
+		CollectDNSDomains:  true,
+		RecordedQueryTypes: []string{"A", "AAAA"},
+		DNSDomainGroups:    domainGroups,
+	})
+}
+
+func TestParseUDPPcap(t *testing.T) {
+	p := newTestParser("*.eu.example.com")
+	packets := readPcap(t, "dns_udp.pcap")
+	require.Len(t, packets, 4)
+
+	key := Key{
+		ServerIP:   util.AddressFromString("10.0.0.53"),
+		ClientIP:   util.AddressFromString("10.0.0.1"),
+		ClientPort: 40000,
+		Protocol:   syscall.IPPROTO_UDP,
+	}
+
+	tr := &translation{}
+	tr.reset()
+	var info dnsPacketInfo
+	require.NoError(t, p.ParseInto(packets[0].data, tr, &info))
+	assert.Equal(t, dnsPacketInfo{transactionID: 0x1001, key: key, pktType: query, question: ToHostname("www.Example.com"), queryType: TypeA}, info)
+
+	// the response follows the CNAME to the A record
+	tr.reset()
+	info = dnsPacketInfo{}
+	require.NoError(t, p.ParseInto(packets[1].data, tr, &info))
+	assert.Equal(t, dnsPacketInfo{transactionID: 0x1001, key: key, pktType: successfulResponse, queryType: TypeA}, info)
+	assert.Equal(t, "www.example.com", ToString(tr.dns))
+	assert.Contains(t, tr.ips, util.AddressFromString("93.184.216.34"))
+
+	// the domain matches a domain group
+	tr.reset()
+	info = dnsPacketInfo{}
+	require.NoError(t, p.ParseInto(packets[2].data, tr, &info))
+	assert.Equal(t, "*.eu.example.com", ToString(info.question))
+}
+
+func TestParseTCPPcap(t *testing.T) {
+	p := newTestParser()
+	packets := readPcap(t, "dns_tcp.pcap")
+	require.Len(t, packets, 2)
+
+	key := Key{
+		ServerIP:   util.AddressFromString("10.0.0.53"),
+		ClientIP:   util.AddressFromString("10.0.0.1"),
+		ClientPort: 40001,
+		Protocol:   syscall.IPPROTO_TCP,
+	}
+
+	tr := &translation{}
+	tr.reset()
+	var info dnsPacketInfo
+	require.NoError(t, p.ParseInto(packets[0].data, tr, &info))
+	assert.Equal(t, dnsPacketInfo{transactionID: 0x2001, key: key, pktType: query, question: ToHostname("foo.example.org"), queryType: TypeAAAA}, info)
+
+	info = dnsPacketInfo{}
+	require.NoError(t, p.ParseInto(packets[1].data, tr, &info))
+	assert.Equal(t, dnsPacketInfo{transactionID: 0x2001, key: key, pktType: failedResponse, rCode: uint8(layers.DNSResponseCodeNXDomain)}, info)
+}
+
+func TestParseSkippedQueryType(t *testing.T) {
+	p := newDNSParser(layers.LayerTypeEthernet, &config.Config{CollectDNSStats: true, CollectDNSDomains: true})
+	packets := readPcap(t, "dns_tcp.pcap")
+
+	tr := &translation{}
+	tr.reset()
+	var info dnsPacketInfo
+	// only A queries are recorded by default
+	assert.Equal(t, errSkippedPayload, p.ParseInto(packets[0].data, tr, &info))
+}
+
+func TestPcapStats(t *testing.T) {
+	p := newTestParser("eu.example.com")
+	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000, true)
+
+	var keys []Key
+	for _, file := range []string{"dns_udp.pcap", "dns_tcp.pcap"} {
+		for _, pkt := range readPcap(t, file) {
+			tr := &translation{}
+			tr.reset()
+			var info dnsPacketInfo
+			require.NoError(t, p.ParseInto(pkt.data, tr, &info))
+			sk.ProcessPacketInfo(info, pkt.ts)
+			keys = append(keys, info.key)
+		}
+	}
+	stats := sk.GetAndResetAllStats()
+	require.Len(t, stats, 2)
+
+	udpStats := stats[keys[0]]
+	require.Len(t, udpStats, 2)
+	www := udpStats[ToHostname("www.Example.com")][TypeA]
+	assert.Equal(t, map[uint32]uint32{0: 1}, www.CountByRcode)
+	assert.Equal(t, uint64(2000), www.SuccessLatencySum)
+	require.Contains(t, www.Latencies, uint32(0))
+	p50, err := www.Latencies[0].GetValueAtQuantile(0.5)
+	require.NoError(t, err)
+	assert.InEpsilon(t, float64(2*time.Millisecond), p50, latencyRelativeAccuracy)
+
+	grouped := udpStats[ToHostname("*.eu.example.com")][TypeA]
+	assert.Equal(t, uint64(4000), grouped.SuccessLatencySum)
+
+	tcpStats := stats[keys[len(keys)-1]][ToHostname("foo.example.org")][TypeAAAA]
+	assert.Equal(t, map[uint32]uint32{uint32(layers.DNSResponseCodeNXDomain): 1}, tcpStats.CountByRcode)
+	assert.Equal(t, uint64(20000), tcpStats.FailureLatencySum)
+	require.Contains(t, tcpStats.Latencies, uint32(layers.DNSResponseCodeNXDomain))
+	assert.Equal(t, 1.0, tcpStats.Latencies[uint32(layers.DNSResponseCodeNXDomain)].GetCount())
+}
+
+func TestDomainGroups(t *testing.T) {
+	p := newTestParser("example.com", " *.Corp.Internal. ", "")
+
+	assert.Equal(t, "*.example.com", ToString(p.questionHostname([]byte("a.example.com"))))
+	assert.Equal(t, "*.example.com", ToString(p.questionHostname([]byte("b.c.EXAMPLE.com"))))
+	assert.Equal(t, "*.corp.internal", ToString(p.questionHostname([]byte("db.corp.internal"))))
+	// the suffix itself, and domains only sharing the end of its name, are not grouped
+	assert.Equal(t, "example.com", ToString(p.questionHostname([]byte("example.com"))))
+	assert.Equal(t, "myexample.com", ToString(p.questionHostname([]byte("myexample.com"))))
+}
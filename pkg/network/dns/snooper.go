@@ -83,7 +83,7 @@ func newSocketFilterSnooper(cfg *config.Config, source packetSource) (*socketFil
 	cache := newReverseDNSCache(dnsCacheSize, dnsCacheExpirationPeriod)
 	var statKeeper *dnsStatKeeper
 	if cfg.CollectDNSStats {
-		statKeeper = newDNSStatkeeper(cfg.DNSTimeout, int64(cfg.MaxDNSStats))
+		statKeeper = newDNSStatkeeper(cfg.DNSTimeout, int64(cfg.MaxDNSStats), cfg.EnableDNSLatencySketches)
 		log.Infof("DNS Stats Collection has been enabled. Maximum number of stats objects: %d", cfg.MaxDNSStats)
 		if cfg.CollectDNSDomains {
 			log.Infof("DNS domain collection has been enabled")
@@ -231,14 +231,6 @@ func (s *socketFilterSnooper) logDNSStats() {
 }
 
 func (s *socketFilterSnooper) getCachedTranslation() *translation {
-	t := s.translation
-
-	// Recycle buffer if necessary
-	if t.ips == nil || len(t.ips) > maxIPBufferSize {
-		t.ips = make(map[util.Address]time.Time, 30)
-	}
-	for k := range t.ips {
-		delete(t.ips, k)
-	}
-	return t
+	s.translation.reset()
+	return s.translation
 }
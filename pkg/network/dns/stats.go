@@ -12,6 +12,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/DataDog/sketches-go/ddsketch"
+
 	"github.com/DataDog/datadog-agent/pkg/telemetry"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
@@ -34,6 +36,9 @@ const (
 
 	// See WaitForDomain
 	waitForDomainTimeout = 5 * time.Second
+
+	// latencyRelativeAccuracy is the relative accuracy of the latency sketches, same as the USM ones
+	latencyRelativeAccuracy = 0.01
 )
 
 var statsTelemetry = struct {
@@ -76,9 +81,11 @@ type dnsStatKeeper struct {
 	processedStats   int64
 	droppedStats     int64
 	maxStats         int64
+	// latencySketches enables the collection of the latency distributions by response code
+	latencySketches bool
 }
 
-func newDNSStatkeeper(timeout time.Duration, maxStats int64) *dnsStatKeeper {
+func newDNSStatkeeper(timeout time.Duration, maxStats int64, latencySketches bool) *dnsStatKeeper {
 	statsKeeper := &dnsStatKeeper{
 		stats:            make(StatsByKeyByNameByType),
 		state:            make(map[stateKey]stateValue),
@@ -86,6 +93,7 @@ func newDNSStatkeeper(timeout time.Duration, maxStats int64) *dnsStatKeeper {
 		exit:             make(chan struct{}),
 		maxSize:          maxStateMapSize,
 		maxStats:         maxStats,
+		latencySketches:  latencySketches,
 	}
 
 	ticker := time.NewTicker(statsKeeper.expirationPeriod)
@@ -169,12 +177,36 @@ func (d *dnsStatKeeper) ProcessPacketInfo(info dnsPacketInfo, ts time.Time) {
 		} else if info.pktType == failedResponse {
 			byqtype.FailureLatencySum += latency
 		}
+		if d.latencySketches {
+			addLatency(&byqtype, uint32(info.rCode), latency)
+		}
 	}
 	stats[start.qtype] = byqtype
 	allStats[start.question] = stats
 	d.stats[info.key] = allStats
 }
 
+// addLatency records a latency, given in microseconds, into the sketch of the given response code.
+// The sketch holds nanoseconds, like the other latency sketches of the network stats.
+func addLatency(stats *Stats, rcode uint32, latency uint64) {
+	if stats.Latencies == nil {
+		stats.Latencies = make(map[uint32]*ddsketch.DDSketch)
+	}
+	sketch, ok := stats.Latencies[rcode]
+	if !ok {
+		var err error
+		sketch, err = ddsketch.NewDefaultDDSketch(latencyRelativeAccuracy)
+		if err != nil {
+			log.Debugf("could not create DNS latency sketch: %v", err)
+			return
+		}
+		stats.Latencies[rcode] = sketch
+	}
+	if err := sketch.Add(float64(latency * 1000)); err != nil {
+		log.Debugf("could not add DNS latency to sketch: %v", err)
+	}
+}
+
 func (d *dnsStatKeeper) GetAndResetAllStats() StatsByKeyByNameByType {
 	d.mux.Lock()
 	defer d.mux.Unlock()
@@ -234,6 +266,13 @@ func (d *dnsStatKeeper) Snapshot() StatsByKeyByNameByType {
 					rcodeCopy[rcode] = count
 				}
 				statsCopy.CountByRcode = rcodeCopy
+				if statsCopy.Latencies != nil {
+					latenciesCopy := make(map[uint32]*ddsketch.DDSketch, len(statsCopy.Latencies))
+					for rcode, sketch := range statsCopy.Latencies {
+						latenciesCopy[rcode] = sketch.Copy()
+					}
+					statsCopy.Latencies = latenciesCopy
+				}
 				snapshot[key][domain][qtype] = statsCopy
 			}
 		}
@@ -42,7 +42,7 @@ func testLatency(
 	expectedTimeouts uint32,
 ) {
 	var d = ToHostname("abc.com")
-	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000)
+	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000, false)
 	key := getSampleDNSKey()
 	qPkt := dnsPacketInfo{transactionID: 1, pktType: query, key: key, question: d, queryType: TypeA}
 	then := time.Now()
@@ -78,8 +78,39 @@ func TestTimeout(t *testing.T) {
 	testLatency(t, successfulResponse, delta, 0, 0, 1)
 }
 
+func TestLatencySketches(t *testing.T) {
+	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000, true)
+	key := getSampleDNSKey()
+	d := ToHostname("abc.com")
+	then := time.Now()
+	for i, latency := range []time.Duration{time.Millisecond, 2 * time.Millisecond, 3 * time.Millisecond} {
+		id := uint16(i)
+		sk.ProcessPacketInfo(dnsPacketInfo{transactionID: id, pktType: query, key: key, question: d, queryType: TypeA}, then)
+		sk.ProcessPacketInfo(dnsPacketInfo{transactionID: id, pktType: successfulResponse, key: key, queryType: TypeA}, then.Add(latency))
+	}
+	sk.ProcessPacketInfo(dnsPacketInfo{transactionID: 10, pktType: query, key: key, question: d, queryType: TypeA}, then)
+	sk.ProcessPacketInfo(dnsPacketInfo{transactionID: 10, pktType: failedResponse, rCode: 3, key: key, queryType: TypeA}, then.Add(5*time.Millisecond))
+
+	stats := sk.GetAndResetAllStats()[key][d][TypeA]
+	require.Len(t, stats.Latencies, 2)
+
+	noError := stats.Latencies[0]
+	require.NotNil(t, noError)
+	assert.Equal(t, 3.0, noError.GetCount())
+	p50, err := noError.GetValueAtQuantile(0.5)
+	require.NoError(t, err)
+	assert.InEpsilon(t, float64(2*time.Millisecond), p50, latencyRelativeAccuracy)
+
+	nxDomain := stats.Latencies[3]
+	require.NotNil(t, nxDomain)
+	assert.Equal(t, 1.0, nxDomain.GetCount())
+	max, err := nxDomain.GetMaxValue()
+	require.NoError(t, err)
+	assert.InEpsilon(t, float64(5*time.Millisecond), max, latencyRelativeAccuracy)
+}
+
 func TestExpiredStateRemoval(t *testing.T) {
-	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000)
+	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000, false)
 	key := getSampleDNSKey()
 	var d = ToHostname("abc.com")
 	qPkt1 := dnsPacketInfo{transactionID: 1, pktType: query, key: key, question: d, queryType: TypeA}
@@ -123,7 +154,7 @@ func BenchmarkStats(b *testing.B) {
 			b.ResetTimer()
 			b.ReportAllocs()
 			for i := 0; i < b.N; i++ {
-				sk := newDNSStatkeeper(1000*time.Second, 10000)
+				sk := newDNSStatkeeper(1000*time.Second, 10000, false)
 				for j := 0; j < numPackets; j++ {
 					sk.ProcessPacketInfo(packets[j], ts)
 				}
@@ -0,0 +1,94 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package dns
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/google/gopacket/layers"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+)
+
+const (
+	// dnsHeaderSize is the size of the header of a DNS message
+	dnsHeaderSize = 12
+	// dnsRecordCountsOffset is the offset of the answer, authority and additional record counts in the header
+	dnsRecordCountsOffset = 6
+)
+
+// TLSStatKeeper computes DNS stats from the DNS over TLS messages decrypted by the USM TLS uprobes
+type TLSStatKeeper struct {
+	mux         sync.Mutex
+	parser      *dnsParser
+	statKeeper  *dnsStatKeeper
+	translation *translation
+}
+
+// NewTLSStatKeeper returns a new TLSStatKeeper
+func NewTLSStatKeeper(cfg *config.Config) *TLSStatKeeper {
+	return &TLSStatKeeper{
+		// the messages read from TLS connections have no network layers
+		parser:      newDNSParser(layers.LayerTypeDNS, cfg),
+		statKeeper:  newDNSStatkeeper(cfg.DNSTimeout, int64(cfg.MaxDNSStats), cfg.EnableDNSLatencySketches),
+		translation: new(translation),
+	}
+}
+
+// Process processes the beginning of a DNS message read from or written to the DNS over TLS connection identified
+// by key. originalSize is the size of the whole buffer passed to the TLS library, of which payload may only hold
+// the beginning.
+func (k *TLSStatKeeper) Process(key Key, payload []byte, originalSize int, ts time.Time) error {
+	msg, ok := tlsDNSMessage(payload, originalSize)
+	if !ok {
+		return errSkippedPayload
+	}
+
+	k.mux.Lock()
+	defer k.mux.Unlock()
+
+	k.translation.reset()
+	pktInfo := dnsPacketInfo{}
+	if err := k.parser.ParseInto(msg, k.translation, &pktInfo); err != nil {
+		return err
+	}
+	pktInfo.key = key
+	k.statKeeper.ProcessPacketInfo(pktInfo, ts)
+	return nil
+}
+
+// GetAndResetAllStats returns the DNS stats collected since the last call
+func (k *TLSStatKeeper) GetAndResetAllStats() StatsByKeyByNameByType {
+	return k.statKeeper.GetAndResetAllStats()
+}
+
+// Close releases the resources of the stat keeper
+func (k *TLSStatKeeper) Close() {
+	k.statKeeper.Close()
+}
+
+// tlsDNSMessage returns the DNS message held by a TLS payload. Over TLS, DNS messages are prefixed by their
+// length (RFC 7858, section 3.3), which libraries either write along with the message or in a separate call.
+// As the payload only holds the beginning of the buffer, the record counts of truncated messages are cleared,
+// so their header, question and response code can still be decoded. The payload is modified in place.
+func tlsDNSMessage(payload []byte, originalSize int) ([]byte, bool) {
+	msg, msgSize := payload, originalSize
+	if len(payload) >= 2 && int(binary.BigEndian.Uint16(payload)) == originalSize-2 {
+		msg, msgSize = payload[2:], originalSize-2
+	}
+	if len(msg) < dnsHeaderSize {
+		// a standalone length prefix, or a payload too short to be a DNS message
+		return nil, false
+	}
+	if len(msg) < msgSize {
+		clear(msg[dnsRecordCountsOffset:dnsHeaderSize])
+	}
+	return msg, true
+}
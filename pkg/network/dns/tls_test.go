@@ -0,0 +1,105 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package dns
+
+import (
+	"encoding/binary"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+)
+
+func serializeDNS(t *testing.T, d *layers.DNS) []byte {
+	buf := gopacket.NewSerializeBuffer()
+	require.NoError(t, d.SerializeTo(buf, gopacket.SerializeOptions{FixLengths: true}))
+	return buf.Bytes()
+}
+
+func withLengthPrefix(msg []byte) []byte {
+	return append(binary.BigEndian.AppendUint16(nil, uint16(len(msg))), msg...)
+}
+
+func TestTLSDNSMessage(t *testing.T) {
+	msg := serializeDNS(t, &layers.DNS{
+		ID:        42,
+		QR:        true,
+		Questions: []layers.DNSQuestion{{Name: []byte("example.com"), Type: layers.DNSTypeA, Class: layers.DNSClassIN}},
+		Answers: []layers.DNSResourceRecord{
+			{Name: []byte("example.com"), Type: layers.DNSTypeA, Class: layers.DNSClassIN, TTL: 60, IP: net.IPv4(192, 0, 2, 1)},
+		},
+	})
+
+	t.Run("with length prefix", func(t *testing.T) {
+		payload := withLengthPrefix(msg)
+		got, ok := tlsDNSMessage(payload, len(payload))
+		require.True(t, ok)
+		assert.Equal(t, msg, got)
+	})
+
+	t.Run("without length prefix", func(t *testing.T) {
+		got, ok := tlsDNSMessage(append([]byte(nil), msg...), len(msg))
+		require.True(t, ok)
+		assert.Equal(t, msg, got)
+	})
+
+	t.Run("standalone length prefix", func(t *testing.T) {
+		_, ok := tlsDNSMessage(msg[:2], 2)
+		assert.False(t, ok)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		payload := withLengthPrefix(msg)
+		got, ok := tlsDNSMessage(payload[:len(payload)-4], len(payload))
+		require.True(t, ok)
+
+		var d layers.DNS
+		require.NoError(t, d.DecodeFromBytes(got, gopacket.NilDecodeFeedback))
+		assert.Equal(t, uint16(42), d.ID)
+		assert.Equal(t, "example.com", string(d.Questions[0].Name))
+		assert.Empty(t, d.Answers)
+	})
+}
+
+func TestTLSStatKeeper(t *testing.T) {
+	k := NewTLSStatKeeper(&config.Config{
+		CollectDNSStats:          true,
+		CollectDNSDomains:        true,
+		DNSTimeout:               DNSTimeoutSecs * time.Second,
+		MaxDNSStats:              100,
+		EnableDNSLatencySketches: true,
+	})
+	t.Cleanup(k.Close)
+
+	key := Key{
+		ServerIP:   util.AddressFromString("1.1.1.1"),
+		ClientIP:   util.AddressFromString("10.0.0.1"),
+		ClientPort: 50000,
+		Protocol:   syscall.IPPROTO_TCP,
+	}
+	question := []layers.DNSQuestion{{Name: []byte("example.com"), Type: layers.DNSTypeA, Class: layers.DNSClassIN}}
+	query := withLengthPrefix(serializeDNS(t, &layers.DNS{ID: 7, RD: true, Questions: question}))
+	response := withLengthPrefix(serializeDNS(t, &layers.DNS{ID: 7, QR: true, ResponseCode: layers.DNSResponseCodeServFail, Questions: question}))
+
+	now := time.Now()
+	require.NoError(t, k.Process(key, query, len(query), now))
+	require.NoError(t, k.Process(key, response, len(response), now.Add(3*time.Millisecond)))
+
+	stats := k.GetAndResetAllStats()[key][ToHostname("example.com")][TypeA]
+	assert.Equal(t, map[uint32]uint32{uint32(layers.DNSResponseCodeServFail): 1}, stats.CountByRcode)
+	assert.Equal(t, uint64(3000), stats.FailureLatencySum)
+	require.Contains(t, stats.Latencies, uint32(layers.DNSResponseCodeServFail))
+}
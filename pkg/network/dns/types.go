@@ -7,10 +7,12 @@
 package dns
 
 import (
+	"github.com/DataDog/sketches-go/ddsketch"
 	"github.com/google/gopacket/layers"
 
 	"github.com/DataDog/datadog-agent/pkg/process/util"
 	"github.com/DataDog/datadog-agent/pkg/util/intern"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
 var si = intern.NewStringInterner()
@@ -92,4 +94,35 @@ type Stats struct {
 	SuccessLatencySum uint64
 	FailureLatencySum uint64
 	CountByRcode      map[uint32]uint32
+	// Latencies holds the distribution of the latencies (in nanoseconds) by response code.
+	// It is only populated when DNS latency sketches are enabled.
+	Latencies map[uint32]*ddsketch.DDSketch
+}
+
+// CombineWith merges the data of other into s
+func (s *Stats) CombineWith(other Stats) {
+	s.Timeouts += other.Timeouts
+	s.SuccessLatencySum += other.SuccessLatencySum
+	s.FailureLatencySum += other.FailureLatencySum
+	if len(other.CountByRcode) > 0 && s.CountByRcode == nil {
+		s.CountByRcode = make(map[uint32]uint32, len(other.CountByRcode))
+	}
+	for rcode, count := range other.CountByRcode {
+		s.CountByRcode[rcode] += count
+	}
+
+	for rcode, sketch := range other.Latencies {
+		if s.Latencies == nil {
+			s.Latencies = make(map[uint32]*ddsketch.DDSketch, len(other.Latencies))
+		}
+		prev, ok := s.Latencies[rcode]
+		if !ok {
+			// other keeps its sketches, they must not be merged into later on
+			s.Latencies[rcode] = sketch.Copy()
+			continue
+		}
+		if err := prev.MergeWith(sketch); err != nil {
+			log.Debugf("could not merge DNS latencies: %v", err)
+		}
+	}
 }
@@ -8,6 +8,10 @@ package dns
 import (
 	"math/rand"
 	"testing"
+
+	"github.com/DataDog/sketches-go/ddsketch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHostnameFromBytesAllocs(t *testing.T) {
@@ -29,3 +33,38 @@ func randomString(b []byte) []byte {
 	}
 	return b
 }
+
+func TestStatsCombineWith(t *testing.T) {
+	newSketch := func(values ...float64) *ddsketch.DDSketch {
+		sketch, err := ddsketch.NewDefaultDDSketch(0.01)
+		require.NoError(t, err)
+		for _, v := range values {
+			require.NoError(t, sketch.Add(v))
+		}
+		return sketch
+	}
+
+	var s Stats
+	first := newSketch(10)
+	s.CombineWith(Stats{
+		Timeouts:          1,
+		SuccessLatencySum: 10,
+		CountByRcode:      map[uint32]uint32{0: 1},
+		Latencies:         map[uint32]*ddsketch.DDSketch{0: first},
+	})
+	s.CombineWith(Stats{
+		FailureLatencySum: 20,
+		CountByRcode:      map[uint32]uint32{0: 2, 3: 1},
+		Latencies:         map[uint32]*ddsketch.DDSketch{0: newSketch(5, 6), 3: newSketch(20)},
+	})
+
+	assert.Equal(t, uint32(1), s.Timeouts)
+	assert.Equal(t, uint64(10), s.SuccessLatencySum)
+	assert.Equal(t, uint64(20), s.FailureLatencySum)
+	assert.Equal(t, map[uint32]uint32{0: 3, 3: 1}, s.CountByRcode)
+	require.Len(t, s.Latencies, 2)
+	assert.Equal(t, 3.0, s.Latencies[0].GetCount())
+	assert.Equal(t, 1.0, s.Latencies[3].GetCount())
+	// the sketches of the combined stats are left untouched
+	assert.Equal(t, 1.0, first.GetCount())
+}
@@ -0,0 +1,113 @@
+#ifndef __CASSANDRA_DECODING_H
+#define __CASSANDRA_DECODING_H
+
+#include "bpf_builtins.h"
+#include "bpf_telemetry.h"
+
+#include "protocols/sockfd.h"
+
+#include "protocols/helpers/pktbuf.h"
+#include "protocols/cassandra/decoding-maps.h"
+#include "protocols/cassandra/defs.h"
+#include "protocols/cassandra/helpers.h"
+#include "protocols/cassandra/types.h"
+#include "protocols/cassandra/usm-events.h"
+#include "protocols/read_into_buffer.h"
+
+PKTBUF_READ_INTO_BUFFER(cassandra_request, CASSANDRA_BUFFER_SIZE, BLK_SIZE)
+PKTBUF_READ_INTO_BUFFER(cassandra_response, CASSANDRA_RESPONSE_BUFFER_SIZE, BLK_SIZE)
+
+// Enqueues a batch of events to the user-space. To spare stack size, we take a scratch buffer from the map, copy
+// the connection tuple and the transaction to it, and then enqueue the event.
+static __always_inline void cassandra_batch_enqueue_wrapper(conn_tuple_t *tuple, cassandra_transaction_t *tx) {
+    u32 zero = 0;
+    cassandra_event_t *event = bpf_map_lookup_elem(&cassandra_scratch_buffer, &zero);
+    if (!event) {
+        return;
+    }
+
+    bpf_memcpy(&event->tuple, tuple, sizeof(conn_tuple_t));
+    bpf_memcpy(&event->tx, tx, sizeof(cassandra_transaction_t));
+    cassandra_batch_enqueue(event);
+}
+
+// Reads the frame header at the beginning of the packet. Starting v5, frames are wrapped in segments, in which case
+// the frame header follows the segment header. Returns true if a valid frame header was found.
+static __always_inline bool read_cassandra_frame_header(pktbuf_t pkt, struct cassandra_frame_header *header) {
+    u32 data_off = pktbuf_data_offset(pkt);
+    u32 data_end = pktbuf_data_end(pkt);
+
+    if (data_off + sizeof(*header) > data_end) {
+        return false;
+    }
+    pktbuf_load_bytes(pkt, data_off, header, sizeof(*header));
+    if (is_cassandra_frame_header(header)) {
+        return true;
+    }
+
+    data_off += CASSANDRA_SEGMENT_HEADER_SIZE;
+    if (data_off + sizeof(*header) > data_end) {
+        return false;
+    }
+    pktbuf_load_bytes(pkt, data_off, header, sizeof(*header));
+    return is_cassandra_frame_header(header);
+}
+
+// Main processing logic for the Cassandra protocol. A request frame creates a new transaction for its stream, and the
+// response frame of the same stream completes it and sends it to userspace, where the opcode, the keyspace and table,
+// and the error code are decoded from the stored fragments. Only the first frame of a packet is considered.
+static __always_inline void cassandra_entrypoint(pktbuf_t pkt, conn_tuple_t *conn_tuple, __u8 tags) {
+    struct cassandra_frame_header header = {};
+    if (!read_cassandra_frame_header(pkt, &header)) {
+        return;
+    }
+
+    cassandra_key_t key = {};
+    bpf_memcpy(&key.tup, conn_tuple, sizeof(conn_tuple_t));
+    key.stream = header.stream;
+
+    u32 data_off = pktbuf_data_offset(pkt);
+    if ((header.version & CASSANDRA_RESPONSE_FLAG) == 0) {
+        cassandra_transaction_t new_transaction = {};
+        new_transaction.request_started = bpf_ktime_get_ns();
+        pktbuf_read_into_buffer_cassandra_request((char *)new_transaction.request_fragment, pkt, data_off);
+        new_transaction.original_request_size = pktbuf_data_end(pkt) - data_off;
+        new_transaction.tags = tags;
+        bpf_map_update_elem(&cassandra_in_flight, &key, &new_transaction, BPF_ANY);
+        return;
+    }
+
+    cassandra_transaction_t *transaction = bpf_map_lookup_elem(&cassandra_in_flight, &key);
+    if (!transaction) {
+        return;
+    }
+    pktbuf_read_into_buffer_cassandra_response((char *)transaction->response_fragment, pkt, data_off);
+    transaction->response_last_seen = bpf_ktime_get_ns();
+    cassandra_batch_enqueue_wrapper(conn_tuple, transaction);
+    bpf_map_delete_elem(&cassandra_in_flight, &key);
+}
+
+// Entrypoint to process plaintext Cassandra traffic. Pulls the connection tuple and the packet buffer from the map and
+// calls the main processing function. In-flight transactions are keyed by stream, so they can't be looked up on TCP
+// termination, and are removed by the map cleaner in userspace instead.
+SEC("socket/cassandra_process")
+int socket__cassandra_process(struct __sk_buff* skb) {
+    skb_info_t skb_info = {};
+    conn_tuple_t conn_tuple = {};
+
+    if (!fetch_dispatching_arguments(&conn_tuple, &skb_info)) {
+        return 0;
+    }
+
+    if (is_tcp_termination(&skb_info)) {
+        return 0;
+    }
+
+    normalize_tuple(&conn_tuple);
+
+    pktbuf_t pkt = pktbuf_from_skb(skb, &skb_info);
+    cassandra_entrypoint(pkt, &conn_tuple, NO_TAGS);
+    return 0;
+}
+
+#endif
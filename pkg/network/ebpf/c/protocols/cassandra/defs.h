@@ -0,0 +1,46 @@
+#ifndef __CASSANDRA_DEFS_H
+#define __CASSANDRA_DEFS_H
+
+// The CQL native protocol versions we support, as described in
+// https://github.com/apache/cassandra/blob/trunk/doc/native_protocol_v5.spec
+#define CASSANDRA_MIN_VERSION 3
+#define CASSANDRA_MAX_VERSION 5
+// The most significant bit of the version byte is set in responses.
+#define CASSANDRA_RESPONSE_FLAG 0x80
+#define CASSANDRA_VERSION_MASK 0x7f
+// Only the 5 least significant bits of the flags byte are defined.
+#define CASSANDRA_MAX_FLAGS 0x1f
+// The maximum length of a frame body is 256MB.
+#define CASSANDRA_MAX_BODY_LEN (256 * 1024 * 1024)
+// Starting v5, frames are wrapped in segments once the connection is established. Uncompressed segments have a 6 bytes
+// header (17 bits payload length, 1 bit self contained flag, 6 bits padding, 24 bits CRC).
+#define CASSANDRA_SEGMENT_HEADER_SIZE 6
+
+// Opcodes of the frames.
+#define CASSANDRA_OPCODE_ERROR 0x00
+#define CASSANDRA_OPCODE_STARTUP 0x01
+#define CASSANDRA_OPCODE_READY 0x02
+#define CASSANDRA_OPCODE_AUTHENTICATE 0x03
+#define CASSANDRA_OPCODE_OPTIONS 0x05
+#define CASSANDRA_OPCODE_SUPPORTED 0x06
+#define CASSANDRA_OPCODE_QUERY 0x07
+#define CASSANDRA_OPCODE_RESULT 0x08
+#define CASSANDRA_OPCODE_PREPARE 0x09
+#define CASSANDRA_OPCODE_EXECUTE 0x0A
+#define CASSANDRA_OPCODE_REGISTER 0x0B
+#define CASSANDRA_OPCODE_EVENT 0x0C
+#define CASSANDRA_OPCODE_BATCH 0x0D
+#define CASSANDRA_OPCODE_AUTH_CHALLENGE 0x0E
+#define CASSANDRA_OPCODE_AUTH_RESPONSE 0x0F
+#define CASSANDRA_OPCODE_AUTH_SUCCESS 0x10
+
+// Every frame starts with a 9 bytes header (protocol v3 and above).
+struct cassandra_frame_header {
+    __u8 version;
+    __u8 flags;
+    __s16 stream; // Big-endian: use bpf_ntohs to read this field
+    __u8 opcode;
+    __u32 length; // Big-endian: use bpf_ntohl to read this field
+} __attribute__((packed));
+
+#endif // __CASSANDRA_DEFS_H
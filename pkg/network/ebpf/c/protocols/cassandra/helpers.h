@@ -0,0 +1,80 @@
+#ifndef __CASSANDRA_HELPERS_H
+#define __CASSANDRA_HELPERS_H
+
+#include "protocols/classification/common.h"
+#include "protocols/cassandra/defs.h"
+
+// Returns true if the opcode is one a client can send.
+static __always_inline bool is_cassandra_request_opcode(__u8 opcode) {
+    switch (opcode) {
+    case CASSANDRA_OPCODE_STARTUP:
+    case CASSANDRA_OPCODE_OPTIONS:
+    case CASSANDRA_OPCODE_QUERY:
+    case CASSANDRA_OPCODE_PREPARE:
+    case CASSANDRA_OPCODE_EXECUTE:
+    case CASSANDRA_OPCODE_REGISTER:
+    case CASSANDRA_OPCODE_BATCH:
+    case CASSANDRA_OPCODE_AUTH_RESPONSE:
+        return true;
+    default:
+        return false;
+    }
+}
+
+// Returns true if the opcode is one a server can send.
+static __always_inline bool is_cassandra_response_opcode(__u8 opcode) {
+    switch (opcode) {
+    case CASSANDRA_OPCODE_ERROR:
+    case CASSANDRA_OPCODE_READY:
+    case CASSANDRA_OPCODE_AUTHENTICATE:
+    case CASSANDRA_OPCODE_SUPPORTED:
+    case CASSANDRA_OPCODE_RESULT:
+    case CASSANDRA_OPCODE_EVENT:
+    case CASSANDRA_OPCODE_AUTH_CHALLENGE:
+    case CASSANDRA_OPCODE_AUTH_SUCCESS:
+        return true;
+    default:
+        return false;
+    }
+}
+
+// Checks if the buffer starts with a valid CQL native protocol frame header.
+static __always_inline bool is_cassandra_frame_header(struct cassandra_frame_header *hdr) {
+    __u8 version = hdr->version & CASSANDRA_VERSION_MASK;
+    if (version < CASSANDRA_MIN_VERSION || version > CASSANDRA_MAX_VERSION) {
+        return false;
+    }
+    if (hdr->flags > CASSANDRA_MAX_FLAGS) {
+        return false;
+    }
+    if (bpf_ntohl(hdr->length) > CASSANDRA_MAX_BODY_LEN) {
+        return false;
+    }
+    if (hdr->version & CASSANDRA_RESPONSE_FLAG) {
+        return is_cassandra_response_opcode(hdr->opcode);
+    }
+    return is_cassandra_request_opcode(hdr->opcode);
+}
+
+// Classifies the connection from the client side only: the buffer must start with a request frame header, and the
+// frame length must match the bytes we read. If the whole payload fits in the buffer, the frame must end exactly at the
+// end of the payload, otherwise it must extend past it.
+static __always_inline bool is_cassandra(const char *buf, __u32 buf_size) {
+    CHECK_PRELIMINARY_BUFFER_CONDITIONS(buf, buf_size, sizeof(struct cassandra_frame_header));
+
+    struct cassandra_frame_header *hdr = (struct cassandra_frame_header *)buf;
+    if (hdr->version & CASSANDRA_RESPONSE_FLAG) {
+        return false;
+    }
+    if (!is_cassandra_frame_header(hdr)) {
+        return false;
+    }
+
+    __u32 frame_len = sizeof(struct cassandra_frame_header) + bpf_ntohl(hdr->length);
+    if (buf_size < CLASSIFICATION_MAX_BUFFER) {
+        return frame_len == buf_size;
+    }
+    return frame_len >= buf_size;
+}
+
+#endif // __CASSANDRA_HELPERS_H
@@ -0,0 +1,42 @@
+#ifndef __CASSANDRA_TYPES_H
+#define __CASSANDRA_TYPES_H
+
+#include "conn_tuple.h"
+
+// Controls the number of Cassandra transactions read from userspace at a time.
+#define CASSANDRA_BATCH_SIZE 25
+
+// Maximum length of the request frame to send to userspace, including the frame header. Long enough to hold the
+// beginning of a query, or a prepared statement id.
+#define CASSANDRA_BUFFER_SIZE 128
+
+// Maximum length of the response frame to send to userspace, including the frame header. Long enough to hold an error
+// code, or the id of a prepared statement.
+#define CASSANDRA_RESPONSE_BUFFER_SIZE 32
+
+// Requests are multiplexed over a connection, and are identified by their stream id.
+typedef struct {
+    conn_tuple_t tup;
+    __s16 stream;
+} cassandra_key_t;
+
+// Cassandra transaction information we store in the kernel.
+typedef struct {
+    // The beginning of the request frame, stored up to CASSANDRA_BUFFER_SIZE bytes.
+    char request_fragment[CASSANDRA_BUFFER_SIZE];
+    // The beginning of the response frame, stored up to CASSANDRA_RESPONSE_BUFFER_SIZE bytes.
+    char response_fragment[CASSANDRA_RESPONSE_BUFFER_SIZE];
+    __u64 request_started;
+    __u64 response_last_seen;
+    // The actual size of the request stored in request_fragment.
+    __u32 original_request_size;
+    __u8 tags;
+} cassandra_transaction_t;
+
+// The struct we send to userspace, containing the connection tuple and the transaction information.
+typedef struct {
+    conn_tuple_t tuple;
+    cassandra_transaction_t tx;
+} cassandra_event_t;
+
+#endif
@@ -0,0 +1,9 @@
+#ifndef __CASSANDRA_USM_EVENTS_H
+#define __CASSANDRA_USM_EVENTS_H
+
+#include "protocols/events.h"
+#include "protocols/cassandra/types.h"
+
+USM_EVENTS_INIT(cassandra, cassandra_event_t, CASSANDRA_BATCH_SIZE);
+
+#endif
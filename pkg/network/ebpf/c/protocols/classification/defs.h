@@ -63,6 +63,8 @@ typedef enum {
     PROTOCOL_AMQP,
     PROTOCOL_REDIS,
     PROTOCOL_MYSQL,
+    PROTOCOL_MEMCACHED,
+    PROTOCOL_CASSANDRA,
     __LAYER_APPLICATION_MAX = LAYER_APPLICATION_MAX,
 
     __LAYER_ENCRYPTION_MIN = LAYER_ENCRYPTION_BIT,
@@ -152,6 +154,9 @@ typedef enum {
     PROG_POSTGRES,
     PROG_POSTGRES_PROCESS_PARSE_MESSAGE,
     PROG_POSTGRES_TERMINATION,
+    PROG_MEMCACHED,
+    PROG_CASSANDRA,
+    PROG_DNS_TLS,
     // Add before this value.
     PROG_MAX,
 } protocol_prog_t;
@@ -17,6 +17,10 @@
 #include "protocols/kafka/usm-events.h"
 #include "protocols/postgres/helpers.h"
 #include "protocols/postgres/usm-events.h"
+#include "protocols/memcached/helpers.h"
+#include "protocols/memcached/usm-events.h"
+#include "protocols/cassandra/helpers.h"
+#include "protocols/cassandra/usm-events.h"
 
 __maybe_unused static __always_inline protocol_prog_t protocol_to_program(protocol_t proto) {
     switch(proto) {
@@ -28,6 +32,10 @@ __maybe_unused static __always_inline protocol_prog_t protocol_to_program(protoc
         return PROG_KAFKA;
     case PROTOCOL_POSTGRES:
         return PROG_POSTGRES;
+    case PROTOCOL_MEMCACHED:
+        return PROG_MEMCACHED;
+    case PROTOCOL_CASSANDRA:
+        return PROG_CASSANDRA;
     default:
         if (proto != PROTOCOL_UNKNOWN) {
             log_debug("protocol doesn't have a matching program: %d", proto);
@@ -77,6 +85,10 @@ static __always_inline void classify_protocol_for_dispatcher(protocol_t *protoco
         *protocol = PROTOCOL_HTTP2;
     } else if (is_postgres_monitoring_enabled() && is_postgres(buf, size)) {
         *protocol = PROTOCOL_POSTGRES;
+    } else if (is_memcached_monitoring_enabled() && is_memcached(buf, size)) {
+        *protocol = PROTOCOL_MEMCACHED;
+    } else if (is_cassandra_monitoring_enabled() && is_cassandra(buf, size)) {
+        *protocol = PROTOCOL_CASSANDRA;
     } else {
         *protocol = PROTOCOL_UNKNOWN;
     }
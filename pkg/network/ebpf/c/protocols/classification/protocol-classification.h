@@ -7,6 +7,7 @@
 #include "port_range.h"
 
 #include "protocols/amqp/helpers.h"
+#include "protocols/cassandra/helpers.h"
 #include "protocols/classification/common.h"
 #include "protocols/classification/defs.h"
 #include "protocols/classification/maps.h"
@@ -19,6 +20,7 @@
 #include "protocols/http/classification-helpers.h"
 #include "protocols/http2/helpers.h"
 #include "protocols/kafka/kafka-classification.h"
+#include "protocols/memcached/helpers.h"
 #include "protocols/mongo/helpers.h"
 #include "protocols/mysql/helpers.h"
 #include "protocols/redis/helpers.h"
@@ -66,6 +68,20 @@ static __always_inline bool is_protocol_classification_supported() {
     return val > 0;
 }
 
+// The memcached and cassandra classifiers are gated on the same constants as their USM dispatcher programs, as they
+// are prone to false positives on connections using other binary protocols.
+static __always_inline bool is_memcached_classification_enabled() {
+    __u64 val = 0;
+    LOAD_CONSTANT("memcached_monitoring_enabled", val);
+    return val > 0;
+}
+
+static __always_inline bool is_cassandra_classification_enabled() {
+    __u64 val = 0;
+    LOAD_CONSTANT("cassandra_monitoring_enabled", val);
+    return val > 0;
+}
+
 // updates the the protocol stack and adds the current layer to the routing skip list
 static __always_inline void update_protocol_information(usm_context_t *usm_ctx, protocol_stack_t *stack, protocol_t proto) {
     set_protocol(stack, proto);
@@ -100,7 +116,7 @@ static __always_inline protocol_t classify_applayer_protocols(const char *buf, _
     return PROTOCOL_UNKNOWN;
 }
 
-// Checks if a given buffer is redis, mongo, postgres, or mysql.
+// Checks if a given buffer is redis, mongo, postgres, mysql, memcached or cassandra.
 static __always_inline protocol_t classify_db_protocols(conn_tuple_t *tup, const char *buf, __u32 size) {
     if (is_redis(buf, size)) {
         return PROTOCOL_REDIS;
@@ -118,6 +134,14 @@ static __always_inline protocol_t classify_db_protocols(conn_tuple_t *tup, const
         return PROTOCOL_MYSQL;
     }
 
+    if (is_memcached_classification_enabled() && is_memcached(buf, size)) {
+        return PROTOCOL_MEMCACHED;
+    }
+
+    if (is_cassandra_classification_enabled() && is_cassandra(buf, size)) {
+        return PROTOCOL_CASSANDRA;
+    }
+
     return PROTOCOL_UNKNOWN;
 }
 
@@ -0,0 +1,12 @@
+#ifndef __DNS_TLS_MAPS_H
+#define __DNS_TLS_MAPS_H
+
+#include "bpf_helpers.h"
+#include "map-defs.h"
+
+#include "protocols/dnstls/types.h"
+
+// Acts as a scratch buffer for DNS over TLS events, for preparing events before they are sent to userspace.
+BPF_PERCPU_ARRAY_MAP(dns_tls_scratch_buffer, dns_tls_event_t, 1)
+
+#endif
@@ -0,0 +1,40 @@
+#ifndef __DNS_TLS_DECODING_H
+#define __DNS_TLS_DECODING_H
+
+#include "bpf_builtins.h"
+#include "bpf_telemetry.h"
+
+#include "protocols/classification/dispatcher-maps.h"
+#include "protocols/dnstls/decoding-maps.h"
+#include "protocols/dnstls/types.h"
+#include "protocols/dnstls/usm-events.h"
+#include "protocols/read_into_buffer.h"
+
+READ_INTO_USER_BUFFER(dns_tls_payload, DNS_TLS_BUFFER_SIZE)
+
+// Entrypoint to process DNS over TLS traffic. Every decrypted buffer is sent to userspace along with its connection
+// tuple and a timestamp, where the DNS messages are decoded and the responses are matched with their queries.
+SEC("uprobe/dns_tls_process")
+int uprobe__dns_tls_process(struct pt_regs *ctx) {
+    const __u32 zero = 0;
+
+    tls_dispatcher_arguments_t *args = bpf_map_lookup_elem(&tls_dispatcher_arguments, &zero);
+    if (args == NULL) {
+        return 0;
+    }
+
+    // To spare stack size, we prepare the event in a scratch buffer taken from the map.
+    dns_tls_event_t *event = bpf_map_lookup_elem(&dns_tls_scratch_buffer, &zero);
+    if (event == NULL) {
+        return 0;
+    }
+
+    event->tuple = args->tup;
+    event->timestamp = bpf_ktime_get_ns();
+    event->original_size = args->data_end;
+    read_into_user_buffer_dns_tls_payload(event->payload, args->buffer_ptr);
+    dns_tls_batch_enqueue(event);
+    return 0;
+}
+
+#endif
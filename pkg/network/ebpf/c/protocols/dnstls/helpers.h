@@ -0,0 +1,14 @@
+#ifndef __DNS_TLS_HELPERS_H
+#define __DNS_TLS_HELPERS_H
+
+#include "conn_tuple.h"
+
+#include "protocols/dnstls/types.h"
+
+// Returns true if the given connection is a DNS over TLS connection. DNS over TLS is not classified from the payload,
+// as the messages hold no magic value: the connections are identified by the port of the server instead.
+static __always_inline bool is_dns_tls(conn_tuple_t *tup) {
+    return tup->sport == DNS_TLS_PORT || tup->dport == DNS_TLS_PORT;
+}
+
+#endif
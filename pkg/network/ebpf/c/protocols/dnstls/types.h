@@ -0,0 +1,26 @@
+#ifndef __DNS_TLS_TYPES_H
+#define __DNS_TLS_TYPES_H
+
+#include "conn_tuple.h"
+
+// Controls the number of DNS over TLS messages read from userspace at a time.
+#define DNS_TLS_BATCH_SIZE 12
+
+// Maximum length of the DNS message to send to userspace. Long enough to hold the header and the question of any
+// message, and the first records of most responses.
+#define DNS_TLS_BUFFER_SIZE 256
+
+// The port DNS over TLS servers listen on, as defined by RFC 7858.
+#define DNS_TLS_PORT 853
+
+// The struct we send to userspace, containing the connection tuple and the beginning of the DNS message.
+typedef struct {
+    conn_tuple_t tuple;
+    // The time the message was read or written, as returned by bpf_ktime_get_ns.
+    __u64 timestamp;
+    // The size of the buffer passed to the TLS library. Only its first DNS_TLS_BUFFER_SIZE bytes are stored in payload.
+    __u32 original_size;
+    char payload[DNS_TLS_BUFFER_SIZE];
+} dns_tls_event_t;
+
+#endif
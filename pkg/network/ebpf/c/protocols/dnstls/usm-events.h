@@ -0,0 +1,9 @@
+#ifndef __DNS_TLS_USM_EVENTS_H
+#define __DNS_TLS_USM_EVENTS_H
+
+#include "protocols/events.h"
+#include "protocols/dnstls/types.h"
+
+USM_EVENTS_INIT(dns_tls, dns_tls_event_t, DNS_TLS_BATCH_SIZE);
+
+#endif
@@ -0,0 +1,15 @@
+#ifndef __MEMCACHED_MAPS_H
+#define __MEMCACHED_MAPS_H
+
+#include "bpf_helpers.h"
+#include "map-defs.h"
+
+#include "protocols/memcached/types.h"
+
+// Keeps track of in-flight Memcached transactions
+BPF_HASH_MAP(memcached_in_flight, conn_tuple_t, memcached_transaction_t, 0)
+
+// Acts as a scratch buffer for Memcached events, for preparing events before they are sent to userspace.
+BPF_PERCPU_ARRAY_MAP(memcached_scratch_buffer, memcached_event_t, 1)
+
+#endif
@@ -0,0 +1,107 @@
+#ifndef __MEMCACHED_DECODING_H
+#define __MEMCACHED_DECODING_H
+
+#include "bpf_builtins.h"
+#include "bpf_telemetry.h"
+
+#include "protocols/sockfd.h"
+
+#include "protocols/helpers/pktbuf.h"
+#include "protocols/memcached/decoding-maps.h"
+#include "protocols/memcached/defs.h"
+#include "protocols/memcached/helpers.h"
+#include "protocols/memcached/types.h"
+#include "protocols/memcached/usm-events.h"
+#include "protocols/read_into_buffer.h"
+
+PKTBUF_READ_INTO_BUFFER(memcached_request, MEMCACHED_BUFFER_SIZE, BLK_SIZE)
+PKTBUF_READ_INTO_BUFFER(memcached_response, MEMCACHED_RESPONSE_BUFFER_SIZE, BLK_SIZE)
+
+// Enqueues a batch of events to the user-space. To spare stack size, we take a scratch buffer from the map, copy
+// the connection tuple and the transaction to it, and then enqueue the event.
+static __always_inline void memcached_batch_enqueue_wrapper(conn_tuple_t *tuple, memcached_transaction_t *tx) {
+    u32 zero = 0;
+    memcached_event_t *event = bpf_map_lookup_elem(&memcached_scratch_buffer, &zero);
+    if (!event) {
+        return;
+    }
+
+    bpf_memcpy(&event->tuple, tuple, sizeof(conn_tuple_t));
+    bpf_memcpy(&event->tx, tx, sizeof(memcached_transaction_t));
+    memcached_batch_enqueue(event);
+}
+
+static void __always_inline memcached_tcp_termination(conn_tuple_t *tup) {
+    bpf_map_delete_elem(&memcached_in_flight, tup);
+    flip_tuple(tup);
+    bpf_map_delete_elem(&memcached_in_flight, tup);
+}
+
+// Returns true if the given buffer, holding the beginning of a packet, is a memcached request.
+static __always_inline bool is_memcached_request(const char *buf, __u32 buf_size) {
+    if (is_memcached_text_command(buf, buf_size)) {
+        return true;
+    }
+    return is_memcached_binary(buf, buf_size) && (__u8)buf[0] == MEMCACHED_BINARY_REQUEST_MAGIC;
+}
+
+// Main processing logic for the Memcached protocol. A packet starting with a command creates a new transaction,
+// overriding the previous one of the connection if any. Any other packet of a connection with an in-flight
+// transaction is considered to be the beginning of its response: the transaction is completed and sent to userspace,
+// where the command, the key and the status are decoded from the stored fragments.
+static __always_inline void memcached_entrypoint(pktbuf_t pkt, conn_tuple_t *conn_tuple, __u8 tags) {
+    u32 data_off = pktbuf_data_offset(pkt);
+    u32 data_end = pktbuf_data_end(pkt);
+    if (data_off >= data_end) {
+        return;
+    }
+    u32 size = data_end - data_off;
+
+    char head[MEMCACHED_RESPONSE_BUFFER_SIZE] = {0};
+    pktbuf_read_into_buffer_memcached_response(head, pkt, data_off);
+    u32 head_size = size < sizeof(head) ? size : sizeof(head);
+
+    if (is_memcached_request(head, head_size)) {
+        memcached_transaction_t new_transaction = {};
+        new_transaction.request_started = bpf_ktime_get_ns();
+        pktbuf_read_into_buffer_memcached_request((char *)new_transaction.request_fragment, pkt, data_off);
+        new_transaction.original_request_size = size;
+        new_transaction.tags = tags;
+        bpf_map_update_elem(&memcached_in_flight, conn_tuple, &new_transaction, BPF_ANY);
+        return;
+    }
+
+    memcached_transaction_t *transaction = bpf_map_lookup_elem(&memcached_in_flight, conn_tuple);
+    if (!transaction) {
+        return;
+    }
+    bpf_memcpy(transaction->response_fragment, head, sizeof(head));
+    transaction->response_last_seen = bpf_ktime_get_ns();
+    memcached_batch_enqueue_wrapper(conn_tuple, transaction);
+    bpf_map_delete_elem(&memcached_in_flight, conn_tuple);
+}
+
+// Entrypoint to process plaintext Memcached traffic. Pulls the connection tuple and the packet buffer from the map and
+// calls the main processing function. If the packet is a TCP termination, it calls the termination function.
+SEC("socket/memcached_process")
+int socket__memcached_process(struct __sk_buff* skb) {
+    skb_info_t skb_info = {};
+    conn_tuple_t conn_tuple = {};
+
+    if (!fetch_dispatching_arguments(&conn_tuple, &skb_info)) {
+        return 0;
+    }
+
+    if (is_tcp_termination(&skb_info)) {
+        memcached_tcp_termination(&conn_tuple);
+        return 0;
+    }
+
+    normalize_tuple(&conn_tuple);
+
+    pktbuf_t pkt = pktbuf_from_skb(skb, &skb_info);
+    memcached_entrypoint(pkt, &conn_tuple, NO_TAGS);
+    return 0;
+}
+
+#endif
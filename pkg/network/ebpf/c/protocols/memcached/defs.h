@@ -0,0 +1,29 @@
+#ifndef __MEMCACHED_DEFS_H
+#define __MEMCACHED_DEFS_H
+
+// The shortest text command we classify is "mg k\r\n".
+#define MEMCACHED_MIN_TEXT_LEN 6
+
+// Binary protocol magic bytes, as described in
+// https://github.com/memcached/memcached/wiki/BinaryProtocolRevamped#packet-structure
+#define MEMCACHED_BINARY_REQUEST_MAGIC 0x80
+#define MEMCACHED_BINARY_RESPONSE_MAGIC 0x81
+// The highest opcode defined by the binary protocol (GATKQ).
+#define MEMCACHED_BINARY_MAX_OPCODE 0x24
+// Keys are limited to 250 bytes by the server.
+#define MEMCACHED_MAX_KEY_LEN 250
+
+// Every binary packet starts with a 24 bytes header.
+struct memcached_binary_header {
+    __u8 magic;
+    __u8 opcode;
+    __u16 key_len; // Big-endian: use bpf_ntohs to read this field
+    __u8 extras_len;
+    __u8 data_type;
+    __u16 vbucket_or_status; // Big-endian: use bpf_ntohs to read this field
+    __u32 body_len; // Big-endian: use bpf_ntohl to read this field
+    __u32 opaque;
+    __u64 cas;
+} __attribute__((packed));
+
+#endif // __MEMCACHED_DEFS_H
@@ -0,0 +1,69 @@
+#ifndef __MEMCACHED_HELPERS_H
+#define __MEMCACHED_HELPERS_H
+
+#include "protocols/classification/common.h"
+#include "protocols/memcached/defs.h"
+
+// Checks if the buffer starts with the given text command followed by a space and the first character of a key.
+#define MEMCACHED_CHECK_COMMAND(buf, buf_size, command)                                                 \
+    (buf_size > sizeof(command) && !bpf_memcmp(buf, command " ", sizeof(command)) &&                  \
+        buf[sizeof(command)] > ' ' && buf[sizeof(command)] < 0x7f)
+
+// Checks if the buffer is a memcached text protocol storage, retrieval, deletion or meta command.
+// https://github.com/memcached/memcached/blob/master/doc/protocol.txt
+static __always_inline bool is_memcached_text_command(const char *buf, __u32 buf_size) {
+    CHECK_PRELIMINARY_BUFFER_CONDITIONS(buf, buf_size, MEMCACHED_MIN_TEXT_LEN);
+
+    switch (buf[0]) {
+    case 'a':
+        return MEMCACHED_CHECK_COMMAND(buf, buf_size, "add") || MEMCACHED_CHECK_COMMAND(buf, buf_size, "append");
+    case 'c':
+        return MEMCACHED_CHECK_COMMAND(buf, buf_size, "cas");
+    case 'd':
+        return MEMCACHED_CHECK_COMMAND(buf, buf_size, "delete") || MEMCACHED_CHECK_COMMAND(buf, buf_size, "decr");
+    case 'g':
+        return MEMCACHED_CHECK_COMMAND(buf, buf_size, "get") || MEMCACHED_CHECK_COMMAND(buf, buf_size, "gets") ||
+            MEMCACHED_CHECK_COMMAND(buf, buf_size, "gat") || MEMCACHED_CHECK_COMMAND(buf, buf_size, "gats");
+    case 'i':
+        return MEMCACHED_CHECK_COMMAND(buf, buf_size, "incr");
+    case 'm':
+        return MEMCACHED_CHECK_COMMAND(buf, buf_size, "mg") || MEMCACHED_CHECK_COMMAND(buf, buf_size, "ms") ||
+            MEMCACHED_CHECK_COMMAND(buf, buf_size, "md") || MEMCACHED_CHECK_COMMAND(buf, buf_size, "ma");
+    case 'p':
+        return MEMCACHED_CHECK_COMMAND(buf, buf_size, "prepend");
+    case 'r':
+        return MEMCACHED_CHECK_COMMAND(buf, buf_size, "replace");
+    case 's':
+        return MEMCACHED_CHECK_COMMAND(buf, buf_size, "set");
+    case 't':
+        return MEMCACHED_CHECK_COMMAND(buf, buf_size, "touch");
+    default:
+        return false;
+    }
+}
+
+// Checks if the buffer is a memcached binary protocol request or response header.
+static __always_inline bool is_memcached_binary(const char *buf, __u32 buf_size) {
+    CHECK_PRELIMINARY_BUFFER_CONDITIONS(buf, buf_size, sizeof(struct memcached_binary_header));
+
+    struct memcached_binary_header *hdr = (struct memcached_binary_header *)buf;
+    if (hdr->magic != MEMCACHED_BINARY_REQUEST_MAGIC && hdr->magic != MEMCACHED_BINARY_RESPONSE_MAGIC) {
+        return false;
+    }
+    // The data type field is reserved, and always 0.
+    if (hdr->opcode > MEMCACHED_BINARY_MAX_OPCODE || hdr->data_type != 0) {
+        return false;
+    }
+
+    __u16 key_len = bpf_ntohs(hdr->key_len);
+    if (key_len > MEMCACHED_MAX_KEY_LEN) {
+        return false;
+    }
+    return bpf_ntohl(hdr->body_len) >= (__u32)key_len + hdr->extras_len;
+}
+
+static __always_inline bool is_memcached(const char *buf, __u32 buf_size) {
+    return is_memcached_text_command(buf, buf_size) || is_memcached_binary(buf, buf_size);
+}
+
+#endif // __MEMCACHED_HELPERS_H
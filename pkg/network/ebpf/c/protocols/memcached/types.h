@@ -0,0 +1,35 @@
+#ifndef __MEMCACHED_TYPES_H
+#define __MEMCACHED_TYPES_H
+
+#include "conn_tuple.h"
+
+// Controls the number of Memcached transactions read from userspace at a time.
+#define MEMCACHED_BATCH_SIZE 25
+
+// Maximum length of the memcached request to send to userspace. Long enough to hold the command and a key prefix.
+#define MEMCACHED_BUFFER_SIZE 64
+
+// Maximum length of the memcached response to send to userspace. Long enough to hold the status line, or the
+// binary header.
+#define MEMCACHED_RESPONSE_BUFFER_SIZE 24
+
+// Memcached transaction information we store in the kernel.
+typedef struct {
+    // The beginning of the request, stored up to MEMCACHED_BUFFER_SIZE bytes.
+    char request_fragment[MEMCACHED_BUFFER_SIZE];
+    // The beginning of the response, stored up to MEMCACHED_RESPONSE_BUFFER_SIZE bytes.
+    char response_fragment[MEMCACHED_RESPONSE_BUFFER_SIZE];
+    __u64 request_started;
+    __u64 response_last_seen;
+    // The actual size of the request stored in request_fragment.
+    __u32 original_request_size;
+    __u8 tags;
+} memcached_transaction_t;
+
+// The struct we send to userspace, containing the connection tuple and the transaction information.
+typedef struct {
+    conn_tuple_t tuple;
+    memcached_transaction_t tx;
+} memcached_event_t;
+
+#endif
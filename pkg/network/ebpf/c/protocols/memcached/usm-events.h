@@ -0,0 +1,9 @@
+#ifndef __MEMCACHED_USM_EVENTS_H
+#define __MEMCACHED_USM_EVENTS_H
+
+#include "protocols/events.h"
+#include "protocols/memcached/types.h"
+
+USM_EVENTS_INIT(memcached, memcached_event_t, MEMCACHED_BATCH_SIZE);
+
+#endif
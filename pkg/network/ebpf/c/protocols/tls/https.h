@@ -22,6 +22,8 @@
 #include "protocols/redis/helpers.h"
 #include "protocols/classification/dispatcher-helpers.h"
 #include "protocols/classification/dispatcher-maps.h"
+#include "protocols/dnstls/helpers.h"
+#include "protocols/dnstls/usm-events.h"
 #include "protocols/http/buffer.h"
 #include "protocols/http/types.h"
 #include "protocols/http/maps.h"
@@ -66,6 +68,26 @@ update_stack:
 }
 
 static __always_inline void tls_process(struct pt_regs *ctx, conn_tuple_t *t, void *buffer_ptr, size_t len, __u64 tags) {
+    const __u32 zero = 0;
+
+    // DNS over TLS is identified by the port of the server, before any classification attempt, as the Kafka
+    // classification tail call below never returns.
+    if (is_dns_tls_monitoring_enabled() && is_dns_tls(t)) {
+        tls_dispatcher_arguments_t *args = bpf_map_lookup_elem(&tls_dispatcher_arguments, &zero);
+        if (args == NULL) {
+            return;
+        }
+        *args = (tls_dispatcher_arguments_t){
+            .tup = *t,
+            .tags = tags,
+            .buffer_ptr = buffer_ptr,
+            .data_end = len,
+            .data_off = 0,
+        };
+        bpf_tail_call_compat(ctx, &tls_process_progs, PROG_DNS_TLS);
+        return;
+    }
+
     conn_tuple_t final_tuple = {0};
     conn_tuple_t normalized_tuple = *t;
     normalize_tuple(&normalized_tuple);
@@ -77,7 +99,6 @@ static __always_inline void tls_process(struct pt_regs *ctx, conn_tuple_t *t, vo
         return;
     }
 
-    const __u32 zero = 0;
     protocol_t protocol = get_protocol_from_stack(stack, LAYER_APPLICATION);
     if (protocol == PROTOCOL_UNKNOWN) {
         char *request_fragment = bpf_map_lookup_elem(&tls_classification_heap, &zero);
@@ -21,6 +21,9 @@
 #include "protocols/http2/decoding-tls.h"
 #include "protocols/kafka/kafka-parsing.h"
 #include "protocols/postgres/decoding.h"
+#include "protocols/memcached/decoding.h"
+#include "protocols/cassandra/decoding.h"
+#include "protocols/dnstls/decoding.h"
 #include "protocols/sockfd-probes.h"
 #include "protocols/tls/java/erpc_dispatcher.h"
 #include "protocols/tls/java/erpc_handlers.h"
@@ -75,6 +78,9 @@ int tracepoint__net__netif_receive_skb(void *ctx) {
     terminated_http2_batch_flush(ctx);
     kafka_batch_flush(ctx);
     postgres_batch_flush(ctx);
+    memcached_batch_flush(ctx);
+    cassandra_batch_flush(ctx);
+    dns_tls_batch_flush(ctx);
     return 0;
 }
 
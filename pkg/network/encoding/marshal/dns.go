@@ -108,6 +108,8 @@ func (f *dnsFormatter) Domains() []string {
 	return domains
 }
 
+// The latency sketches of the DNS stats have no protobuf representation, they are only exposed by the
+// /debug/dns_monitoring endpoint of system-probe.
 func formatDNSStatsByDomainByQueryType(builder *model.ConnectionBuilder, stats map[dns.Hostname]map[dns.QueryType]dns.Stats, domainSet map[string]int) {
 	for d, bytype := range stats {
 		pos, ok := domainSet[dns.ToString(d)]
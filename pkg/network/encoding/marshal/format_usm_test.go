@@ -114,6 +114,23 @@ func TestFormatProtocols(t *testing.T) {
 				},
 			},
 		},
+		{
+			// only exposed by the debug endpoints of system-probe
+			name:     "memcached protocol",
+			protocol: protocols.Stack{Application: protocols.Memcached},
+			want: &model.ProtocolStack{
+				Stack: nil,
+			},
+		},
+		{
+			name:     "cassandra protocol over tls",
+			protocol: protocols.Stack{Encryption: protocols.TLS, Application: protocols.Cassandra},
+			want: &model.ProtocolStack{
+				Stack: []model.ProtocolType{
+					model.ProtocolType_protocolTLS,
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -86,6 +86,11 @@ func formatProtocol(proto protocols.ProtocolType) model.ProtocolType {
 		return model.ProtocolType_protocolRedis
 	case protocols.MySQL:
 		return model.ProtocolType_protocolMySQL
+	case protocols.Memcached, protocols.Cassandra, protocols.DNS:
+		// these protocols don't have a protobuf representation yet, the
+		// Memcached and Cassandra stats are only exposed by the debug
+		// endpoints of system-probe
+		return model.ProtocolType_protocolUnknown
 	default:
 		log.Warnf("missing protobuf representation for protocol %d", proto)
 		return model.ProtocolType_protocolUnknown
@@ -0,0 +1,214 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package cassandra
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+)
+
+// This file contains the decoding of the request and response frames captured by the eBPF programs.
+// The file does not have any build tag, so the decoders can be tested without loading the eBPF programs.
+
+const (
+	frameHeaderSize   = 9
+	minVersion        = 3
+	maxVersion        = 5
+	responseFlag      = 0x80
+	versionMask       = 0x7f
+	maxFlags          = 0x1f
+	segmentHeaderSize = 6
+
+	// resultKindPrepared is the kind of the RESULT responses to PREPARE requests.
+	resultKindPrepared = 0x0004
+	// batchKindQuery is the kind of the statements of a BATCH request given as a query string.
+	batchKindQuery = 0
+	// batchKindPrepared is the kind of the statements of a BATCH request given as a prepared id.
+	batchKindPrepared = 1
+)
+
+// Request is a decoded CQL request frame.
+type Request struct {
+	Opcode Opcode
+	Stream int16
+	// TableName is the keyspace qualified (when it is in the query) table of QUERY and
+	// PREPARE requests, and of the first statement of BATCH requests.
+	TableName string
+	// PreparedID is the id of the statement of EXECUTE requests, and of the first
+	// statement of BATCH requests.
+	PreparedID string
+}
+
+// Response is a decoded CQL response frame.
+type Response struct {
+	Opcode    Opcode
+	Stream    int16
+	ErrorCode ErrorCode
+	// PreparedID is the id of the statement of RESULT responses to PREPARE requests.
+	PreparedID string
+}
+
+type frameHeader struct {
+	version uint8
+	stream  int16
+	opcode  Opcode
+}
+
+// parseFrameHeader parses the frame header at the beginning of the fragment, or after
+// the segment header which wraps the frames starting v5, and returns the frame body.
+func parseFrameHeader(fragment []byte) (frameHeader, []byte, bool) {
+	if h, ok := parseFrameHeaderAt(fragment); ok {
+		return h, fragment[frameHeaderSize:], true
+	}
+	if len(fragment) > segmentHeaderSize {
+		if h, ok := parseFrameHeaderAt(fragment[segmentHeaderSize:]); ok {
+			return h, fragment[segmentHeaderSize+frameHeaderSize:], true
+		}
+	}
+	return frameHeader{}, nil, false
+}
+
+func parseFrameHeaderAt(b []byte) (frameHeader, bool) {
+	if len(b) < frameHeaderSize {
+		return frameHeader{}, false
+	}
+	version := b[0] & versionMask
+	if version < minVersion || version > maxVersion || b[1] > maxFlags {
+		return frameHeader{}, false
+	}
+	return frameHeader{
+		version: b[0],
+		stream:  int16(binary.BigEndian.Uint16(b[2:4])),
+		opcode:  Opcode(b[4]),
+	}, true
+}
+
+// DecodeRequest decodes the beginning of a request frame.
+func DecodeRequest(fragment []byte) (req Request, ok bool) {
+	h, body, ok := parseFrameHeader(fragment)
+	if !ok || h.version&responseFlag != 0 {
+		return req, false
+	}
+	req.Opcode = h.opcode
+	req.Stream = h.stream
+
+	switch h.opcode {
+	case QueryOpcode, PrepareOpcode:
+		query, queryTruncated := readLongString(body)
+		req.TableName = extractTableName(query, queryTruncated)
+	case ExecuteOpcode:
+		req.PreparedID, _ = readShortBytes(body)
+	case BatchOpcode:
+		// <type><n><kind><statement>...
+		if len(body) < 4 {
+			break
+		}
+		statement := body[4:]
+		switch body[3] {
+		case batchKindQuery:
+			query, queryTruncated := readLongString(statement)
+			req.TableName = extractTableName(query, queryTruncated)
+		case batchKindPrepared:
+			req.PreparedID, _ = readShortBytes(statement)
+		}
+	}
+	return req, true
+}
+
+// DecodeResponse decodes the beginning of a response frame.
+func DecodeResponse(fragment []byte) (resp Response, ok bool) {
+	h, body, ok := parseFrameHeader(fragment)
+	if !ok || h.version&responseFlag == 0 {
+		return resp, false
+	}
+	resp.Opcode = h.opcode
+	resp.Stream = h.stream
+	resp.ErrorCode = NoError
+
+	switch h.opcode {
+	case ErrorOpcode:
+		if len(body) < 4 {
+			return resp, false
+		}
+		resp.ErrorCode = ErrorCode(binary.BigEndian.Uint32(body))
+	case ResultOpcode:
+		if len(body) >= 4 && binary.BigEndian.Uint32(body) == resultKindPrepared {
+			resp.PreparedID, _ = readShortBytes(body[4:])
+		}
+	}
+	return resp, true
+}
+
+// readLongString reads a [long string], an int length followed by the bytes of the
+// string, and returns the part of it in b, and whether it is incomplete.
+func readLongString(b []byte) ([]byte, bool) {
+	if len(b) < 4 {
+		return nil, true
+	}
+	n := binary.BigEndian.Uint32(b)
+	s := b[4:]
+	if uint64(n) > uint64(len(s)) {
+		return s, true
+	}
+	return s[:n], false
+}
+
+// readShortBytes reads a [short bytes], a short length followed by the bytes. It
+// returns false if the bytes are not entirely in b.
+func readShortBytes(b []byte) (string, bool) {
+	if len(b) < 2 {
+		return "", false
+	}
+	n := int(binary.BigEndian.Uint16(b))
+	if n > len(b)-2 {
+		return "", false
+	}
+	return string(b[2 : 2+n]), true
+}
+
+// extractTableName returns the table a CQL statement operates on, or an empty string if
+// it can't be found. The table is the identifier following the FROM (SELECT and DELETE),
+// INTO (INSERT), UPDATE, TRUNCATE or TABLE (CREATE, ALTER and DROP) keywords, and
+// includes the keyspace if the statement qualifies it. truncated tells whether the query
+// is incomplete, in which case an identifier at its end is ignored.
+func extractTableName(query []byte, truncated bool) string {
+	expectTable := false
+	for len(query) > 0 {
+		start := bytes.IndexFunc(query, isIdentifierChar)
+		if start < 0 {
+			return ""
+		}
+		query = query[start:]
+		end := bytes.IndexFunc(query, func(r rune) bool { return !isIdentifierChar(r) })
+		if end < 0 {
+			end = len(query)
+			if truncated {
+				return ""
+			}
+		}
+		token := query[:end]
+		query = query[end:]
+
+		if expectTable {
+			switch strings.ToUpper(string(token)) {
+			// CREATE TABLE IF NOT EXISTS, DROP TABLE IF EXISTS, TRUNCATE TABLE
+			case "IF", "NOT", "EXISTS", "TABLE", "COLUMNFAMILY":
+				continue
+			}
+			return string(bytes.ReplaceAll(token, []byte(`"`), nil))
+		}
+		switch strings.ToUpper(string(token)) {
+		case "FROM", "INTO", "UPDATE", "TRUNCATE", "TABLE", "COLUMNFAMILY":
+			expectTable = true
+		}
+	}
+	return ""
+}
+
+func isIdentifierChar(r rune) bool {
+	return r == '_' || r == '.' || r == '"' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
@@ -0,0 +1,219 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package cassandra
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The sizes of the fragments captured by the eBPF program, see types.h
+const (
+	requestFragmentSize  = 128
+	responseFragmentSize = 32
+)
+
+func truncate(b []byte, size int) []byte {
+	if len(b) > size {
+		return b[:size]
+	}
+	return b
+}
+
+// encodeFrame encodes a frame of the given protocol version, with the response flag
+// set when response is true.
+func encodeFrame(version uint8, response bool, stream int16, opcode Opcode, body []byte) []byte {
+	b := make([]byte, frameHeaderSize, frameHeaderSize+len(body))
+	b[0] = version
+	if response {
+		b[0] |= responseFlag
+	}
+	binary.BigEndian.PutUint16(b[2:4], uint16(stream))
+	b[4] = uint8(opcode)
+	binary.BigEndian.PutUint32(b[5:9], uint32(len(body)))
+	return append(b, body...)
+}
+
+// encodeSegment wraps a frame in an uncompressed v5 segment. The CRCs aren't computed
+// as the decoder doesn't check them.
+func encodeSegment(frame []byte) []byte {
+	header := make([]byte, segmentHeaderSize)
+	header[0] = uint8(len(frame))
+	header[1] = uint8(len(frame) >> 8)
+	header[2] = uint8(len(frame)>>16)&0x01 | 0x02 // self contained
+	return append(header, frame...)
+}
+
+func longString(s string) []byte {
+	return binary.BigEndian.AppendUint32(nil, uint32(len(s)))
+}
+
+func queryBody(query string) []byte {
+	body := append(longString(query), query...)
+	// consistency ONE, no flags
+	return append(body, 0x00, 0x01, 0x00)
+}
+
+func shortBytes(b string) []byte {
+	return append(binary.BigEndian.AppendUint16(nil, uint16(len(b))), b...)
+}
+
+func TestDecodeRequest(t *testing.T) {
+	preparedID := "\x8f\x12\x93\x05\xab\x00\x11\x22\x33\x44\x55\x66\x77\x88\x99\xaa"
+	batchBody := append([]byte{0x00, 0x00, 0x02, batchKindQuery}, longString("INSERT INTO shop.orders (id) VALUES (?)")...)
+	batchBody = append(batchBody, "INSERT INTO shop.orders (id) VALUES (?)"...)
+
+	tests := []struct {
+		name       string
+		frame      []byte
+		opcode     Opcode
+		tableName  string
+		preparedID string
+	}{
+		{
+			name:      "select",
+			frame:     encodeFrame(4, false, 12, QueryOpcode, queryBody("SELECT * FROM shop.users WHERE id = 1")),
+			opcode:    QueryOpcode,
+			tableName: "shop.users",
+		},
+		{
+			name:      "insert v5 segment",
+			frame:     encodeSegment(encodeFrame(5, false, 12, QueryOpcode, queryBody(`INSERT INTO "Shop"."Users" (id) VALUES (1)`))),
+			opcode:    QueryOpcode,
+			tableName: "Shop.Users",
+		},
+		{
+			name:      "update",
+			frame:     encodeFrame(4, false, 12, QueryOpcode, queryBody("update users using ttl 10 set name = 'a' where id = 1")),
+			opcode:    QueryOpcode,
+			tableName: "users",
+		},
+		{
+			name:      "create table",
+			frame:     encodeFrame(4, false, 12, QueryOpcode, queryBody("CREATE TABLE IF NOT EXISTS shop.carts (id int PRIMARY KEY)")),
+			opcode:    QueryOpcode,
+			tableName: "shop.carts",
+		},
+		{
+			name:      "truncated query",
+			frame:     encodeFrame(4, false, 12, QueryOpcode, queryBody("SELECT id, name, email, address, phone, created_at, updated_at, status FROM shop.users_with_a_really_long_table_name_that_gets_cut")),
+			opcode:    QueryOpcode,
+			tableName: "",
+		},
+		{
+			name:      "prepare",
+			frame:     encodeFrame(4, false, 3, PrepareOpcode, append(longString("DELETE FROM shop.sessions WHERE id = ?"), "DELETE FROM shop.sessions WHERE id = ?"...)),
+			opcode:    PrepareOpcode,
+			tableName: "shop.sessions",
+		},
+		{
+			name:       "execute",
+			frame:      encodeFrame(4, false, 3, ExecuteOpcode, shortBytes(preparedID)),
+			opcode:     ExecuteOpcode,
+			preparedID: preparedID,
+		},
+		{
+			name:      "batch",
+			frame:     encodeFrame(4, false, 3, BatchOpcode, batchBody),
+			opcode:    BatchOpcode,
+			tableName: "shop.orders",
+		},
+		{
+			name:   "options",
+			frame:  encodeFrame(4, false, 0, OptionsOpcode, nil),
+			opcode: OptionsOpcode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, ok := DecodeRequest(truncate(tt.frame, requestFragmentSize))
+			require.True(t, ok)
+			assert.Equal(t, tt.opcode, req.Opcode)
+			assert.Equal(t, tt.tableName, req.TableName)
+			assert.Equal(t, tt.preparedID, req.PreparedID)
+		})
+	}
+}
+
+func TestDecodeRequestErrors(t *testing.T) {
+	for name, frame := range map[string][]byte{
+		"empty":            nil,
+		"short":            {0x04, 0x00, 0x00},
+		"response":         encodeFrame(4, true, 0, ReadyOpcode, nil),
+		"protocol version": encodeFrame(2, false, 0, QueryOpcode, nil),
+		"flags":            {0x04, 0xff, 0x00, 0x00, 0x07, 0x00, 0x00, 0x00, 0x00},
+	} {
+		_, ok := DecodeRequest(frame)
+		assert.False(t, ok, name)
+	}
+}
+
+func TestDecodeResponse(t *testing.T) {
+	preparedID := "\x01\x02\x03\x04\x05\x06\x07\x08\x09\x0a\x0b\x0c\x0d\x0e\x0f\x10"
+	preparedBody := append(binary.BigEndian.AppendUint32(nil, resultKindPrepared), shortBytes(preparedID)...)
+	// metadata, truncated by the fragment
+	preparedBody = append(preparedBody, make([]byte, 32)...)
+
+	tests := []struct {
+		name       string
+		frame      []byte
+		opcode     Opcode
+		errorCode  ErrorCode
+		preparedID string
+	}{
+		{
+			name:      "rows",
+			frame:     encodeFrame(4, true, 12, ResultOpcode, binary.BigEndian.AppendUint32(nil, 0x0002)),
+			opcode:    ResultOpcode,
+			errorCode: NoError,
+		},
+		{
+			name:       "prepared",
+			frame:      encodeFrame(4, true, 3, ResultOpcode, preparedBody),
+			opcode:     ResultOpcode,
+			errorCode:  NoError,
+			preparedID: preparedID,
+		},
+		{
+			name:      "read timeout",
+			frame:     encodeFrame(4, true, 12, ErrorOpcode, append(binary.BigEndian.AppendUint32(nil, 0x1200), "Operation timed out"...)),
+			opcode:    ErrorOpcode,
+			errorCode: 0x1200,
+		},
+		{
+			name:      "syntax error v5 segment",
+			frame:     encodeSegment(encodeFrame(5, true, 12, ErrorOpcode, binary.BigEndian.AppendUint32(nil, 0x2000))),
+			opcode:    ErrorOpcode,
+			errorCode: 0x2000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, ok := DecodeResponse(truncate(tt.frame, responseFragmentSize))
+			require.True(t, ok)
+			assert.Equal(t, tt.opcode, resp.Opcode)
+			assert.Equal(t, tt.errorCode, resp.ErrorCode)
+			assert.Equal(t, tt.preparedID, resp.PreparedID)
+		})
+	}
+
+	_, ok := DecodeResponse(encodeFrame(4, false, 0, QueryOpcode, nil))
+	assert.False(t, ok)
+	_, ok = DecodeResponse(encodeFrame(4, true, 0, ErrorOpcode, nil))
+	assert.False(t, ok)
+}
+
+func TestErrorCodeString(t *testing.T) {
+	assert.Equal(t, "read_timeout", ErrorCode(0x1200).String())
+	assert.Equal(t, "server_error", ErrorCode(0).String())
+	assert.Equal(t, "0x9999", ErrorCode(0x9999).String())
+	assert.Empty(t, NoError.String())
+}
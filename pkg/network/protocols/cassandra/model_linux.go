@@ -0,0 +1,116 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package cassandra
+
+import (
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/pkg/network/protocols"
+	"github.com/DataDog/datadog-agent/pkg/network/types"
+)
+
+// EventWrapper wraps an ebpf event and provides additional methods to extract information from it.
+// We use this wrapper to avoid decoding the same frames multiple times.
+type EventWrapper struct {
+	*EbpfEvent
+
+	decoded      bool
+	valid        bool
+	request      Request
+	response     Response
+	tableName    string
+	tableNameSet bool
+}
+
+// NewEventWrapper creates a new EventWrapper from an ebpf event.
+func NewEventWrapper(e *EbpfEvent) *EventWrapper {
+	return &EventWrapper{EbpfEvent: e}
+}
+
+// ConnTuple returns the connection tuple for the transaction
+func (e *EventWrapper) ConnTuple() types.ConnectionKey {
+	return types.ConnectionKey{
+		SrcIPHigh: e.Tuple.Saddr_h,
+		SrcIPLow:  e.Tuple.Saddr_l,
+		DstIPHigh: e.Tuple.Daddr_h,
+		DstIPLow:  e.Tuple.Daddr_l,
+		SrcPort:   e.Tuple.Sport,
+		DstPort:   e.Tuple.Dport,
+	}
+}
+
+// getRequestFragment returns the actual request fragment from the event.
+func (e *EbpfTx) getRequestFragment() []byte {
+	if e.Original_request_size > uint32(len(e.Request_fragment)) {
+		return e.Request_fragment[:]
+	}
+	return e.Request_fragment[:e.Original_request_size]
+}
+
+func (e *EventWrapper) decode() {
+	if e.decoded {
+		return
+	}
+	e.decoded = true
+	var requestOK, responseOK bool
+	e.request, requestOK = DecodeRequest(e.Tx.getRequestFragment())
+	e.response, responseOK = DecodeResponse(e.Tx.Response_fragment[:])
+	e.valid = requestOK && responseOK && e.request.Stream == e.response.Stream
+}
+
+// Valid returns true if both the request and the response frames were decoded.
+func (e *EventWrapper) Valid() bool {
+	e.decode()
+	return e.valid
+}
+
+// Request returns the decoded request frame.
+func (e *EventWrapper) Request() Request {
+	e.decode()
+	return e.request
+}
+
+// Response returns the decoded response frame.
+func (e *EventWrapper) Response() Response {
+	e.decode()
+	return e.response
+}
+
+// TableName returns the table the request operates on. The table of statements executed by id is resolved with the
+// given function, which can be nil.
+func (e *EventWrapper) TableName(resolvePrepared func(id string) string) string {
+	if !e.tableNameSet {
+		e.tableName = e.Request().TableName
+		if id := e.request.PreparedID; id != "" && resolvePrepared != nil {
+			e.tableName = resolvePrepared(id)
+		}
+		e.tableNameSet = true
+	}
+	return e.tableName
+}
+
+// RequestLatency returns the latency of the request in nanoseconds
+func (e *EventWrapper) RequestLatency() float64 {
+	if uint64(e.Tx.Request_started) == 0 || uint64(e.Tx.Response_last_seen) == 0 {
+		return 0
+	}
+	return protocols.NSTimestampToFloat(e.Tx.Response_last_seen - e.Tx.Request_started)
+}
+
+const template = `
+ebpfTx{
+	Opcode: %q,
+	Table Name: %q,
+	Error: %q,
+	Latency: %f
+}`
+
+// String returns a string representation of the underlying event
+func (e *EventWrapper) String() string {
+	return fmt.Sprintf(template, e.Request().Opcode, e.Request().TableName, e.Response().ErrorCode, e.RequestLatency())
+}
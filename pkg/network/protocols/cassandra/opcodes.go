@@ -0,0 +1,143 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package cassandra
+
+import "fmt"
+
+// Opcode is the type of a CQL native protocol frame.
+// https://github.com/apache/cassandra/blob/trunk/doc/native_protocol_v5.spec
+type Opcode uint8
+
+const (
+	// ErrorOpcode is the opcode of ERROR responses.
+	ErrorOpcode Opcode = 0x00
+	// StartupOpcode is the opcode of STARTUP requests.
+	StartupOpcode Opcode = 0x01
+	// ReadyOpcode is the opcode of READY responses.
+	ReadyOpcode Opcode = 0x02
+	// AuthenticateOpcode is the opcode of AUTHENTICATE responses.
+	AuthenticateOpcode Opcode = 0x03
+	// OptionsOpcode is the opcode of OPTIONS requests.
+	OptionsOpcode Opcode = 0x05
+	// SupportedOpcode is the opcode of SUPPORTED responses.
+	SupportedOpcode Opcode = 0x06
+	// QueryOpcode is the opcode of QUERY requests.
+	QueryOpcode Opcode = 0x07
+	// ResultOpcode is the opcode of RESULT responses.
+	ResultOpcode Opcode = 0x08
+	// PrepareOpcode is the opcode of PREPARE requests.
+	PrepareOpcode Opcode = 0x09
+	// ExecuteOpcode is the opcode of EXECUTE requests.
+	ExecuteOpcode Opcode = 0x0A
+	// RegisterOpcode is the opcode of REGISTER requests.
+	RegisterOpcode Opcode = 0x0B
+	// EventOpcode is the opcode of EVENT responses.
+	EventOpcode Opcode = 0x0C
+	// BatchOpcode is the opcode of BATCH requests.
+	BatchOpcode Opcode = 0x0D
+	// AuthChallengeOpcode is the opcode of AUTH_CHALLENGE responses.
+	AuthChallengeOpcode Opcode = 0x0E
+	// AuthResponseOpcode is the opcode of AUTH_RESPONSE requests.
+	AuthResponseOpcode Opcode = 0x0F
+	// AuthSuccessOpcode is the opcode of AUTH_SUCCESS responses.
+	AuthSuccessOpcode Opcode = 0x10
+)
+
+// String returns the string representation of the opcode.
+func (o Opcode) String() string {
+	switch o {
+	case ErrorOpcode:
+		return "ERROR"
+	case StartupOpcode:
+		return "STARTUP"
+	case ReadyOpcode:
+		return "READY"
+	case AuthenticateOpcode:
+		return "AUTHENTICATE"
+	case OptionsOpcode:
+		return "OPTIONS"
+	case SupportedOpcode:
+		return "SUPPORTED"
+	case QueryOpcode:
+		return "QUERY"
+	case ResultOpcode:
+		return "RESULT"
+	case PrepareOpcode:
+		return "PREPARE"
+	case ExecuteOpcode:
+		return "EXECUTE"
+	case RegisterOpcode:
+		return "REGISTER"
+	case EventOpcode:
+		return "EVENT"
+	case BatchOpcode:
+		return "BATCH"
+	case AuthChallengeOpcode:
+		return "AUTH_CHALLENGE"
+	case AuthResponseOpcode:
+		return "AUTH_RESPONSE"
+	case AuthSuccessOpcode:
+		return "AUTH_SUCCESS"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ErrorCode is the code of an ERROR response.
+type ErrorCode int32
+
+// NoError is used for the transactions whose response isn't an error.
+const NoError ErrorCode = -1
+
+// String returns the string representation of the error code.
+func (c ErrorCode) String() string {
+	switch c {
+	case NoError:
+		return ""
+	case 0x0000:
+		return "server_error"
+	case 0x000A:
+		return "protocol_error"
+	case 0x0100:
+		return "bad_credentials"
+	case 0x1000:
+		return "unavailable"
+	case 0x1001:
+		return "overloaded"
+	case 0x1002:
+		return "is_bootstrapping"
+	case 0x1003:
+		return "truncate_error"
+	case 0x1100:
+		return "write_timeout"
+	case 0x1200:
+		return "read_timeout"
+	case 0x1300:
+		return "read_failure"
+	case 0x1400:
+		return "function_failure"
+	case 0x1500:
+		return "write_failure"
+	case 0x1600:
+		return "cdc_write_failure"
+	case 0x1700:
+		return "cas_write_unknown"
+	case 0x2000:
+		return "syntax_error"
+	case 0x2100:
+		return "unauthorized"
+	case 0x2200:
+		return "invalid"
+	case 0x2300:
+		return "config_error"
+	case 0x2400:
+		return "already_exists"
+	case 0x2500:
+		return "unprepared"
+	default:
+		return fmt.Sprintf("0x%04x", int32(c))
+	}
+}
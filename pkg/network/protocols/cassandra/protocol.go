@@ -0,0 +1,198 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package cassandra
+
+import (
+	"io"
+	"unsafe"
+
+	"github.com/cilium/ebpf"
+	"github.com/davecgh/go-spew/spew"
+
+	manager "github.com/DataDog/ebpf-manager"
+
+	ddebpf "github.com/DataDog/datadog-agent/pkg/ebpf"
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols/events"
+	"github.com/DataDog/datadog-agent/pkg/network/usm/buildmode"
+	"github.com/DataDog/datadog-agent/pkg/network/usm/utils"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// InFlightMap is the name of the in-flight map.
+	InFlightMap      = "cassandra_in_flight"
+	scratchBufferMap = "cassandra_scratch_buffer"
+	processTailCall  = "socket__cassandra_process"
+	eventStream      = "cassandra"
+)
+
+// protocol holds the state of the cassandra protocol monitoring.
+type protocol struct {
+	cfg            *config.Config
+	telemetry      *Telemetry
+	eventsConsumer *events.Consumer[EbpfEvent]
+	mapCleaner     *ddebpf.MapCleaner[EbpfKey, EbpfTx]
+	statskeeper    *StatKeeper
+}
+
+// Spec is the protocol spec for the cassandra protocol.
+var Spec = &protocols.ProtocolSpec{
+	Factory: newCassandraProtocol,
+	Maps: []*manager.Map{
+		{
+			Name: InFlightMap,
+		},
+		{
+			Name: scratchBufferMap,
+		},
+		{
+			Name: "cassandra_batch_events",
+		},
+		{
+			Name: "cassandra_batch_state",
+		},
+		{
+			Name: "cassandra_batches",
+		},
+	},
+	TailCalls: []manager.TailCallRoute{
+		{
+			ProgArrayName: protocols.ProtocolDispatcherProgramsMap,
+			Key:           uint32(protocols.ProgramCassandra),
+			ProbeIdentificationPair: manager.ProbeIdentificationPair{
+				EBPFFuncName: processTailCall,
+			},
+		},
+	},
+}
+
+func newCassandraProtocol(cfg *config.Config) (protocols.Protocol, error) {
+	if !cfg.EnableCassandraMonitoring {
+		return nil, nil
+	}
+
+	return &protocol{
+		cfg:         cfg,
+		telemetry:   NewTelemetry(),
+		statskeeper: NewStatkeeper(cfg),
+	}, nil
+}
+
+// Name returns the name of the protocol.
+func (p *protocol) Name() string {
+	return "cassandra"
+}
+
+// ConfigureOptions add the necessary options for the cassandra monitoring to work, to be used by the manager.
+func (p *protocol) ConfigureOptions(mgr *manager.Manager, opts *manager.Options) {
+	opts.MapSpecEditors[InFlightMap] = manager.MapSpecEditor{
+		MaxEntries: p.cfg.MaxUSMConcurrentRequests,
+		EditorFlag: manager.EditMaxEntries,
+	}
+	utils.EnableOption(opts, "cassandra_monitoring_enabled")
+	// Configure event stream
+	events.Configure(p.cfg, eventStream, mgr, opts)
+}
+
+// PreStart runs setup required before starting the protocol.
+func (p *protocol) PreStart(mgr *manager.Manager) (err error) {
+	p.eventsConsumer, err = events.NewConsumer(
+		eventStream,
+		mgr,
+		p.processCassandra,
+	)
+	if err != nil {
+		return
+	}
+
+	p.eventsConsumer.Start()
+
+	return
+}
+
+// PostStart starts the map cleaner.
+func (p *protocol) PostStart(mgr *manager.Manager) error {
+	// Setup map cleaner after manager start.
+	p.setupMapCleaner(mgr)
+	return nil
+}
+
+// Stop stops all resources associated with the protocol.
+func (p *protocol) Stop(*manager.Manager) {
+	// mapCleaner handles nil pointer receivers
+	p.mapCleaner.Stop()
+
+	if p.eventsConsumer != nil {
+		p.eventsConsumer.Stop()
+	}
+}
+
+// DumpMaps dumps map contents for debugging.
+func (p *protocol) DumpMaps(w io.Writer, mapName string, currentMap *ebpf.Map) {
+	if mapName == InFlightMap { // maps/cassandra_in_flight (BPF_MAP_TYPE_HASH), key EbpfKey, value EbpfTx
+		var key EbpfKey
+		var value EbpfTx
+		protocols.WriteMapDumpHeader(w, currentMap, mapName, key, value)
+		iter := currentMap.Iterate()
+		for iter.Next(unsafe.Pointer(&key), unsafe.Pointer(&value)) {
+			spew.Fdump(w, key, value)
+		}
+	}
+}
+
+// GetStats returns a map of Cassandra stats.
+func (p *protocol) GetStats() *protocols.ProtocolStats {
+	p.eventsConsumer.Sync()
+	p.telemetry.Log()
+
+	return &protocols.ProtocolStats{
+		Type:  protocols.Cassandra,
+		Stats: p.statskeeper.GetAndResetAllStats(),
+	}
+}
+
+// IsBuildModeSupported returns always true, as cassandra module is supported by all modes.
+func (*protocol) IsBuildModeSupported(buildmode.Type) bool {
+	return true
+}
+
+func (p *protocol) processCassandra(events []EbpfEvent) {
+	for i := range events {
+		eventWrapper := NewEventWrapper(&events[i])
+		if eventWrapper.Valid() {
+			p.statskeeper.Process(eventWrapper)
+		}
+		p.telemetry.Count(eventWrapper)
+	}
+}
+
+func (p *protocol) setupMapCleaner(mgr *manager.Manager) {
+	cassandraInflight, _, err := mgr.GetMap(InFlightMap)
+	if err != nil {
+		log.Errorf("error getting %s map: %s", InFlightMap, err)
+		return
+	}
+	mapCleaner, err := ddebpf.NewMapCleaner[EbpfKey, EbpfTx](cassandraInflight, 1024)
+	if err != nil {
+		log.Errorf("error creating map cleaner: %s", err)
+		return
+	}
+
+	// Clean up requests without response, including the ones of closed connections, as the in-flight transactions are
+	// keyed by stream and can't be removed on TCP termination. We currently use the same TTL as HTTP, but we plan to
+	// rename this variable to be more generic.
+	ttl := p.cfg.HTTPIdleConnectionTTL.Nanoseconds()
+	mapCleaner.Clean(p.cfg.HTTPMapCleanerInterval, nil, nil, func(now int64, _ EbpfKey, val EbpfTx) bool {
+		started := int64(val.Request_started)
+		return started > 0 && (now-started) > ttl
+	})
+
+	p.mapCleaner = mapCleaner
+}
@@ -0,0 +1,125 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package cassandra
+
+import (
+	"sync"
+
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/network/types"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// maxPreparedStatements is the number of prepared statements whose table is remembered.
+const maxPreparedStatements = 4096
+
+// StatKeeper is a struct to hold the records for the cassandra protocol
+type StatKeeper struct {
+	stats      map[Key]*RequestStat
+	statsMutex sync.RWMutex
+	maxEntries int
+
+	// preparedTables maps the ids of the prepared statements to their table, as EXECUTE
+	// requests only have the id of the statement.
+	preparedTables *simplelru.LRU[preparedKey, string]
+}
+
+// preparedKey identifies a prepared statement. Ids are only unique for a given server,
+// which is the destination of the normalized connection tuple.
+type preparedKey struct {
+	serverIPHigh uint64
+	serverIPLow  uint64
+	serverPort   uint16
+	id           string
+}
+
+// NewStatkeeper creates a new StatKeeper
+func NewStatkeeper(c *config.Config) *StatKeeper {
+	preparedTables, _ := simplelru.NewLRU[preparedKey, string](maxPreparedStatements, nil)
+	newStatKeeper := &StatKeeper{
+		maxEntries:     c.MaxCassandraStatsBuffered,
+		preparedTables: preparedTables,
+	}
+	newStatKeeper.resetNoLock()
+	return newStatKeeper
+}
+
+// Process processes the cassandra transaction
+func (s *StatKeeper) Process(tx *EventWrapper) {
+	s.statsMutex.Lock()
+	defer s.statsMutex.Unlock()
+
+	connKey := tx.ConnTuple()
+	tableName := tx.TableName(func(id string) string {
+		return s.resolvePreparedNoLock(connKey, id)
+	})
+	if tx.Request().Opcode == PrepareOpcode && tx.Response().PreparedID != "" && tableName != "" {
+		s.preparedTables.Add(newPreparedKey(connKey, tx.Response().PreparedID), tableName)
+	}
+
+	key := Key{
+		Opcode:        tx.Request().Opcode,
+		TableName:     tableName,
+		ErrorCode:     tx.Response().ErrorCode,
+		ConnectionKey: connKey,
+	}
+	requestStats, ok := s.stats[key]
+	if !ok {
+		if len(s.stats) >= s.maxEntries {
+			return
+		}
+		requestStats = new(RequestStat)
+		s.stats[key] = requestStats
+	}
+	requestStats.StaticTags = uint64(tx.Tx.Tags)
+	requestStats.Count++
+	if requestStats.Count == 1 {
+		requestStats.FirstLatencySample = tx.RequestLatency()
+		return
+	}
+	if requestStats.Latencies == nil {
+		if err := requestStats.initSketch(); err != nil {
+			return
+		}
+		if err := requestStats.Latencies.Add(requestStats.FirstLatencySample); err != nil {
+			return
+		}
+	}
+	if err := requestStats.Latencies.Add(tx.RequestLatency()); err != nil {
+		log.Debugf("could not add request latency to ddsketch: %v", err)
+	}
+}
+
+func newPreparedKey(connKey types.ConnectionKey, id string) preparedKey {
+	return preparedKey{
+		serverIPHigh: connKey.DstIPHigh,
+		serverIPLow:  connKey.DstIPLow,
+		serverPort:   connKey.DstPort,
+		id:           id,
+	}
+}
+
+func (s *StatKeeper) resolvePreparedNoLock(connKey types.ConnectionKey, id string) string {
+	tableName, _ := s.preparedTables.Get(newPreparedKey(connKey, id))
+	return tableName
+}
+
+// GetAndResetAllStats returns all the records and resets the statskeeper
+func (s *StatKeeper) GetAndResetAllStats() map[Key]*RequestStat {
+	s.statsMutex.Lock()
+	defer s.statsMutex.Unlock()
+	ret := s.stats // No deep copy needed since `s.statskeeper` gets reset
+	s.resetNoLock()
+	return ret
+}
+
+func (s *StatKeeper) resetNoLock() {
+	s.stats = make(map[Key]*RequestStat)
+}
@@ -0,0 +1,65 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package cassandra
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+)
+
+func newTestEvent(tuple ConnTuple, request, response []byte) *EventWrapper {
+	event := &EbpfEvent{
+		Tuple: tuple,
+		Tx: EbpfTx{
+			Request_started:       1,
+			Response_last_seen:    10,
+			Original_request_size: uint32(len(request)),
+		},
+	}
+	copy(event.Tx.Request_fragment[:], request)
+	copy(event.Tx.Response_fragment[:], response)
+	return NewEventWrapper(event)
+}
+
+func TestStatKeeperPreparedStatementsByServer(t *testing.T) {
+	cfg := config.New()
+	cfg.MaxCassandraStatsBuffered = 100
+	s := NewStatkeeper(cfg)
+
+	preparedID := "\x01\x02\x03\x04\x05\x06\x07\x08\x09\x0a\x0b\x0c\x0d\x0e\x0f\x10"
+	prepareResponse := append(binary.BigEndian.AppendUint32(nil, resultKindPrepared), shortBytes(preparedID)...)
+	executeRequest := encodeFrame(4, false, 2, ExecuteOpcode, append(shortBytes(preparedID), 0x00, 0x01, 0x00))
+	executeResponse := encodeFrame(4, true, 2, ResultOpcode, binary.BigEndian.AppendUint32(nil, 0x0001))
+
+	serverA := ConnTuple{Saddr_l: 1, Daddr_l: 10, Sport: 40000, Dport: 9042}
+	s.Process(newTestEvent(serverA,
+		encodeFrame(4, false, 1, PrepareOpcode, queryBody("SELECT * FROM shop.orders WHERE id = ?")),
+		encodeFrame(4, true, 1, ResultOpcode, prepareResponse)))
+
+	// The statement is executed on another connection to the same server
+	sameServer := ConnTuple{Saddr_l: 1, Daddr_l: 10, Sport: 40001, Dport: 9042}
+	s.Process(newTestEvent(sameServer, executeRequest, executeResponse))
+	// A statement with the same id prepared on another server is unknown
+	serverB := ConnTuple{Saddr_l: 1, Daddr_l: 20, Sport: 40002, Dport: 9042}
+	s.Process(newTestEvent(serverB, executeRequest, executeResponse))
+
+	tables := make(map[uint64]string)
+	for k := range s.GetAndResetAllStats() {
+		if k.Opcode == ExecuteOpcode {
+			tables[k.DstIPLow] = k.TableName
+		}
+	}
+	require.Len(t, tables, 2)
+	assert.Equal(t, "shop.orders", tables[10])
+	assert.Equal(t, "", tables[20])
+}
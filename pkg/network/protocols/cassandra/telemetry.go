@@ -0,0 +1,84 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package cassandra
+
+import (
+	libtelemetry "github.com/DataDog/datadog-agent/pkg/network/protocols/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// Telemetry is a struct to hold the telemetry for the cassandra protocol
+type Telemetry struct {
+	metricGroup *libtelemetry.MetricGroup
+
+	queries  *libtelemetry.Counter
+	prepares *libtelemetry.Counter
+	executes *libtelemetry.Counter
+	batches  *libtelemetry.Counter
+	others   *libtelemetry.Counter
+	errors   *libtelemetry.Counter
+	// failedTableNameExtraction counts the QUERY, PREPARE and EXECUTE requests whose table couldn't be found
+	failedTableNameExtraction *libtelemetry.Counter
+	// invalidEvents counts the transactions whose request or response frame couldn't be decoded
+	invalidEvents *libtelemetry.Counter
+}
+
+// NewTelemetry creates a new Telemetry
+func NewTelemetry() *Telemetry {
+	metricGroup := libtelemetry.NewMetricGroup("usm.cassandra")
+
+	return &Telemetry{
+		metricGroup:               metricGroup,
+		queries:                   metricGroup.NewCounter("requests", "opcode:query", libtelemetry.OptStatsd),
+		prepares:                  metricGroup.NewCounter("requests", "opcode:prepare", libtelemetry.OptStatsd),
+		executes:                  metricGroup.NewCounter("requests", "opcode:execute", libtelemetry.OptStatsd),
+		batches:                   metricGroup.NewCounter("requests", "opcode:batch", libtelemetry.OptStatsd),
+		others:                    metricGroup.NewCounter("requests", "opcode:other", libtelemetry.OptStatsd),
+		errors:                    metricGroup.NewCounter("errors", libtelemetry.OptStatsd),
+		failedTableNameExtraction: metricGroup.NewCounter("failed_table_name_extraction", libtelemetry.OptStatsd),
+		invalidEvents:             metricGroup.NewCounter("invalid_events", libtelemetry.OptStatsd),
+	}
+}
+
+// Count increments the telemetry counters based on the event data. It must be called
+// after the event is processed by the StatKeeper, which resolves its table.
+func (t *Telemetry) Count(eventWrapper *EventWrapper) {
+	if !eventWrapper.Valid() {
+		t.invalidEvents.Add(1)
+		return
+	}
+
+	switch eventWrapper.Request().Opcode {
+	case QueryOpcode:
+		t.queries.Add(1)
+	case PrepareOpcode:
+		t.prepares.Add(1)
+	case ExecuteOpcode:
+		t.executes.Add(1)
+	case BatchOpcode:
+		t.batches.Add(1)
+	default:
+		t.others.Add(1)
+	}
+
+	switch eventWrapper.Request().Opcode {
+	case QueryOpcode, PrepareOpcode, ExecuteOpcode:
+		if eventWrapper.TableName(nil) == "" {
+			t.failedTableNameExtraction.Add(1)
+		}
+	}
+
+	if eventWrapper.Response().ErrorCode != NoError {
+		t.errors.Add(1)
+	}
+}
+
+// Log logs the cassandra stats summary
+func (t *Telemetry) Log() {
+	log.Debugf("cassandra stats summary: %s", t.metricGroup.Summary())
+}
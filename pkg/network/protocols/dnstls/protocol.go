@@ -0,0 +1,198 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+
+// Package dnstls implements the monitoring of DNS over TLS traffic, decrypted by the USM TLS uprobes. The DNS
+// messages are turned into the same DNS stats as the ones collected by the DNS snooper.
+package dnstls
+
+import (
+	"io"
+	"syscall"
+	"time"
+
+	"github.com/cilium/ebpf"
+
+	manager "github.com/DataDog/ebpf-manager"
+
+	ddebpf "github.com/DataDog/datadog-agent/pkg/ebpf"
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/network/dns"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols/events"
+	libtelemetry "github.com/DataDog/datadog-agent/pkg/network/protocols/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/network/usm/buildmode"
+	"github.com/DataDog/datadog-agent/pkg/network/usm/utils"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	scratchBufferMap   = "dns_tls_scratch_buffer"
+	tlsProcessTailCall = "uprobe__dns_tls_process"
+	eventStream        = "dns_tls"
+)
+
+// protocol holds the state of the DNS over TLS monitoring.
+type protocol struct {
+	cfg            *config.Config
+	eventsConsumer *events.Consumer[EbpfEvent]
+	statKeeper     *dns.TLSStatKeeper
+
+	metricGroup    *libtelemetry.MetricGroup
+	messages       *libtelemetry.Counter
+	decodingErrors *libtelemetry.Counter
+}
+
+// Spec is the protocol spec for the DNS over TLS monitoring.
+var Spec = &protocols.ProtocolSpec{
+	Factory: newDNSTLSProtocol,
+	Maps: []*manager.Map{
+		{
+			Name: scratchBufferMap,
+		},
+		{
+			Name: "dns_tls_batch_events",
+		},
+		{
+			Name: "dns_tls_batch_state",
+		},
+		{
+			Name: "dns_tls_batches",
+		},
+	},
+	TailCalls: []manager.TailCallRoute{
+		{
+			ProgArrayName: protocols.TLSDispatcherProgramsMap,
+			Key:           uint32(protocols.ProgramDNSTLS),
+			ProbeIdentificationPair: manager.ProbeIdentificationPair{
+				EBPFFuncName: tlsProcessTailCall,
+			},
+		},
+	},
+}
+
+func newDNSTLSProtocol(cfg *config.Config) (protocols.Protocol, error) {
+	if !cfg.EnableDNSOverTLSMonitoring {
+		return nil, nil
+	}
+	if !cfg.DNSInspection || !cfg.CollectDNSStats {
+		log.Warn("DNS over TLS monitoring is enabled, but DNS stats collection is disabled")
+		return nil, nil
+	}
+
+	metricGroup := libtelemetry.NewMetricGroup("usm.dns_tls")
+	return &protocol{
+		cfg:            cfg,
+		metricGroup:    metricGroup,
+		messages:       metricGroup.NewCounter("messages", libtelemetry.OptStatsd),
+		decodingErrors: metricGroup.NewCounter("decoding_errors", libtelemetry.OptStatsd),
+	}, nil
+}
+
+// Name returns the name of the protocol.
+func (p *protocol) Name() string {
+	return "DNS over TLS"
+}
+
+// ConfigureOptions add the necessary options for the DNS over TLS monitoring to work, to be used by the manager.
+func (p *protocol) ConfigureOptions(mgr *manager.Manager, opts *manager.Options) {
+	utils.EnableOption(opts, "dns_tls_monitoring_enabled")
+	// Configure event stream
+	events.Configure(p.cfg, eventStream, mgr, opts)
+}
+
+// PreStart runs setup required before starting the protocol.
+func (p *protocol) PreStart(mgr *manager.Manager) (err error) {
+	p.statKeeper = dns.NewTLSStatKeeper(p.cfg)
+	p.eventsConsumer, err = events.NewConsumer(
+		eventStream,
+		mgr,
+		p.processDNSTLS,
+	)
+	if err != nil {
+		return
+	}
+
+	p.eventsConsumer.Start()
+
+	return
+}
+
+// PostStart is a no-op.
+func (p *protocol) PostStart(*manager.Manager) error {
+	return nil
+}
+
+// Stop stops all resources associated with the protocol.
+func (p *protocol) Stop(*manager.Manager) {
+	if p.eventsConsumer != nil {
+		p.eventsConsumer.Stop()
+	}
+	if p.statKeeper != nil {
+		p.statKeeper.Close()
+	}
+}
+
+// DumpMaps is a no-op, as no state is kept in the kernel.
+func (p *protocol) DumpMaps(io.Writer, string, *ebpf.Map) {}
+
+// GetStats returns the DNS stats of the DNS over TLS traffic.
+func (p *protocol) GetStats() *protocols.ProtocolStats {
+	p.eventsConsumer.Sync()
+	log.Debugf("dns over tls stats summary: %s", p.metricGroup.Summary())
+
+	return &protocols.ProtocolStats{
+		Type:  protocols.DNS,
+		Stats: p.statKeeper.GetAndResetAllStats(),
+	}
+}
+
+// IsBuildModeSupported returns always true, as DNS over TLS monitoring is supported by all modes.
+func (*protocol) IsBuildModeSupported(buildmode.Type) bool {
+	return true
+}
+
+func (p *protocol) processDNSTLS(events []EbpfEvent) {
+	// the event timestamps are monotonic, while the DNS stat keeper expects wall clock times
+	now := time.Now()
+	monotonicNow, err := ddebpf.NowNanoseconds()
+	if err != nil {
+		log.Debugf("could not get monotonic time: %s", err)
+		return
+	}
+
+	for i := range events {
+		event := &events[i]
+		p.messages.Add(1)
+
+		key, ok := dnsKey(&event.Tuple)
+		if !ok || (!p.cfg.CollectLocalDNS && key.ServerIP.IsLoopback()) {
+			continue
+		}
+		size := min(int(event.Original_size), BufferSize)
+		ts := now.Add(-time.Duration(monotonicNow - int64(event.Timestamp)))
+		if err := p.statKeeper.Process(key, event.Payload[:size], int(event.Original_size), ts); err != nil {
+			p.decodingErrors.Add(1)
+		}
+	}
+}
+
+// dnsKey returns the DNS key of a connection, whose server is the side using the DNS over TLS port
+func dnsKey(tuple *ConnTuple) (dns.Key, bool) {
+	saddr := util.FromLowHigh(tuple.Saddr_l, tuple.Saddr_h)
+	daddr := util.FromLowHigh(tuple.Daddr_l, tuple.Daddr_h)
+	key := dns.Key{Protocol: syscall.IPPROTO_TCP}
+	switch {
+	case tuple.Dport == ServerPort:
+		key.ServerIP, key.ClientIP, key.ClientPort = daddr, saddr, tuple.Sport
+	case tuple.Sport == ServerPort:
+		key.ServerIP, key.ClientIP, key.ClientPort = saddr, daddr, tuple.Dport
+	default:
+		return dns.Key{}, false
+	}
+	return key, true
+}
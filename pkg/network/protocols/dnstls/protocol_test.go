@@ -0,0 +1,37 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package dnstls
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/network/dns"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+)
+
+func TestDNSKey(t *testing.T) {
+	client, server := util.AddressFromString("10.0.0.1"), util.AddressFromString("1.1.1.1")
+	clientLow, clientHigh := util.ToLowHigh(client)
+	serverLow, serverHigh := util.ToLowHigh(server)
+	expected := dns.Key{ServerIP: server, ClientIP: client, ClientPort: 50000, Protocol: syscall.IPPROTO_TCP}
+
+	// the tuples of the TLS hooks may be in either direction
+	key, ok := dnsKey(&ConnTuple{Saddr_l: clientLow, Saddr_h: clientHigh, Daddr_l: serverLow, Daddr_h: serverHigh, Sport: 50000, Dport: ServerPort})
+	assert.True(t, ok)
+	assert.Equal(t, expected, key)
+
+	key, ok = dnsKey(&ConnTuple{Saddr_l: serverLow, Saddr_h: serverHigh, Daddr_l: clientLow, Daddr_h: clientHigh, Sport: ServerPort, Dport: 50000})
+	assert.True(t, ok)
+	assert.Equal(t, expected, key)
+
+	_, ok = dnsKey(&ConnTuple{Saddr_l: clientLow, Daddr_l: serverLow, Sport: 50000, Dport: 443})
+	assert.False(t, ok)
+}
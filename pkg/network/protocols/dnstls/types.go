@@ -0,0 +1,22 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build ignore
+
+package dnstls
+
+/*
+#include "../../ebpf/c/protocols/dnstls/types.h"
+*/
+import "C"
+
+type ConnTuple = C.conn_tuple_t
+
+type EbpfEvent C.dns_tls_event_t
+
+const (
+	BufferSize = C.DNS_TLS_BUFFER_SIZE
+	ServerPort = C.DNS_TLS_PORT
+)
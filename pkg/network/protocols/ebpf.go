@@ -65,6 +65,12 @@ const (
 	ProgramPostgresParseMessage ProgramType = C.PROG_POSTGRES_PROCESS_PARSE_MESSAGE
 	// ProgramPostgresTermination is tail call to process Postgres termination.
 	ProgramPostgresTermination ProgramType = C.PROG_POSTGRES_TERMINATION
+	// ProgramMemcached is the Golang representation of the C.PROG_MEMCACHED enum
+	ProgramMemcached ProgramType = C.PROG_MEMCACHED
+	// ProgramCassandra is the Golang representation of the C.PROG_CASSANDRA enum
+	ProgramCassandra ProgramType = C.PROG_CASSANDRA
+	// ProgramDNSTLS is the Golang representation of the C.PROG_DNS_TLS enum
+	ProgramDNSTLS ProgramType = C.PROG_DNS_TLS
 )
 
 // Application layer of the protocol stack.
@@ -111,6 +117,10 @@ func toProtocolType(protoNum uint8, layerBit uint16) ProtocolType {
 		return Redis
 	case C.PROTOCOL_MYSQL:
 		return MySQL
+	case C.PROTOCOL_MEMCACHED:
+		return Memcached
+	case C.PROTOCOL_CASSANDRA:
+		return Cassandra
 	default:
 		log.Errorf("unknown eBPF protocol type: %x", protocol)
 		return Unknown
@@ -134,6 +134,7 @@ func (p *protocol) ConfigureOptions(mgr *manager.Manager, opts *manager.Options)
 }
 
 func (p *protocol) PreStart(mgr *manager.Manager) (err error) {
+	ddebpf.AddMapDecoder[netebpf.ConnTuple, EbpfTx](inFlightMap)
 	p.eventsConsumer, err = events.NewConsumer(
 		"http",
 		mgr,
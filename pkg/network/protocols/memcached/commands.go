@@ -0,0 +1,199 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package memcached
+
+// Command represents a memcached command supported by our decoder.
+type Command uint8
+
+const (
+	// UnknownCommand represents an unknown command.
+	UnknownCommand Command = iota
+	// GetCommand represents a get command, and its binary protocol variants (getq, getk, getkq).
+	GetCommand
+	// GetsCommand represents a gets command.
+	GetsCommand
+	// GatCommand represents a gat (get and touch) command, and its binary protocol variants.
+	GatCommand
+	// GatsCommand represents a gats command.
+	GatsCommand
+	// SetCommand represents a set command.
+	SetCommand
+	// AddCommand represents an add command.
+	AddCommand
+	// ReplaceCommand represents a replace command.
+	ReplaceCommand
+	// AppendCommand represents an append command.
+	AppendCommand
+	// PrependCommand represents a prepend command.
+	PrependCommand
+	// CasCommand represents a cas (check and set) command.
+	CasCommand
+	// DeleteCommand represents a delete command.
+	DeleteCommand
+	// IncrCommand represents an incr command.
+	IncrCommand
+	// DecrCommand represents a decr command.
+	DecrCommand
+	// TouchCommand represents a touch command.
+	TouchCommand
+	// MetaGetCommand represents a mg (meta get) command.
+	MetaGetCommand
+	// MetaSetCommand represents a ms (meta set) command.
+	MetaSetCommand
+	// MetaDeleteCommand represents a md (meta delete) command.
+	MetaDeleteCommand
+	// MetaArithmeticCommand represents a ma (meta arithmetic) command.
+	MetaArithmeticCommand
+)
+
+// String returns the string representation of the command.
+func (c Command) String() string {
+	switch c {
+	case GetCommand:
+		return "get"
+	case GetsCommand:
+		return "gets"
+	case GatCommand:
+		return "gat"
+	case GatsCommand:
+		return "gats"
+	case SetCommand:
+		return "set"
+	case AddCommand:
+		return "add"
+	case ReplaceCommand:
+		return "replace"
+	case AppendCommand:
+		return "append"
+	case PrependCommand:
+		return "prepend"
+	case CasCommand:
+		return "cas"
+	case DeleteCommand:
+		return "delete"
+	case IncrCommand:
+		return "incr"
+	case DecrCommand:
+		return "decr"
+	case TouchCommand:
+		return "touch"
+	case MetaGetCommand:
+		return "mg"
+	case MetaSetCommand:
+		return "ms"
+	case MetaDeleteCommand:
+		return "md"
+	case MetaArithmeticCommand:
+		return "ma"
+	default:
+		return "unknown"
+	}
+}
+
+// commandFromText returns the Command of a text protocol command name.
+func commandFromText(name string) Command {
+	switch name {
+	case "get":
+		return GetCommand
+	case "gets":
+		return GetsCommand
+	case "gat":
+		return GatCommand
+	case "gats":
+		return GatsCommand
+	case "set":
+		return SetCommand
+	case "add":
+		return AddCommand
+	case "replace":
+		return ReplaceCommand
+	case "append":
+		return AppendCommand
+	case "prepend":
+		return PrependCommand
+	case "cas":
+		return CasCommand
+	case "delete":
+		return DeleteCommand
+	case "incr":
+		return IncrCommand
+	case "decr":
+		return DecrCommand
+	case "touch":
+		return TouchCommand
+	case "mg":
+		return MetaGetCommand
+	case "ms":
+		return MetaSetCommand
+	case "md":
+		return MetaDeleteCommand
+	case "ma":
+		return MetaArithmeticCommand
+	default:
+		return UnknownCommand
+	}
+}
+
+// commandFromOpcode returns the Command of a binary protocol opcode. Quiet variants, which
+// only get a response on failure, are folded into their regular command.
+// https://github.com/memcached/memcached/wiki/BinaryProtocolRevamped#command-opcodes
+func commandFromOpcode(opcode uint8) Command {
+	switch opcode {
+	case 0x00, 0x09, 0x0c, 0x0d:
+		return GetCommand
+	case 0x01, 0x11:
+		return SetCommand
+	case 0x02, 0x12:
+		return AddCommand
+	case 0x03, 0x13:
+		return ReplaceCommand
+	case 0x04, 0x14:
+		return DeleteCommand
+	case 0x05, 0x15:
+		return IncrCommand
+	case 0x06, 0x16:
+		return DecrCommand
+	case 0x0e, 0x19:
+		return AppendCommand
+	case 0x0f, 0x1a:
+		return PrependCommand
+	case 0x1c:
+		return TouchCommand
+	case 0x1d, 0x1e, 0x23, 0x24:
+		return GatCommand
+	default:
+		return UnknownCommand
+	}
+}
+
+// Status is the outcome of a memcached command.
+type Status uint8
+
+const (
+	// UnknownStatus is the status of responses we couldn't decode.
+	UnknownStatus Status = iota
+	// Hit is the status of commands which found or updated their item (VALUE, STORED, DELETED, HD...).
+	Hit
+	// Miss is the status of commands which didn't find their item, or whose condition
+	// wasn't met (END without value, NOT_FOUND, NOT_STORED, EXISTS, EN...).
+	Miss
+	// Error is the status of commands which failed (ERROR, CLIENT_ERROR, SERVER_ERROR...).
+	Error
+)
+
+// String returns the string representation of the status.
+func (s Status) String() string {
+	switch s {
+	case Hit:
+		return "hit"
+	case Miss:
+		return "miss"
+	case Error:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,197 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package memcached
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+)
+
+// This file contains the decoding of the request and response fragments captured by the eBPF programs.
+// The file does not have any build tag, so the decoders can be tested without loading the eBPF programs.
+
+const (
+	binaryRequestMagic  = 0x80
+	binaryResponseMagic = 0x81
+	binaryHeaderSize    = 24
+
+	// keyDelimiters are the characters splitting memcached keys in segments.
+	keyDelimiters = ":./_-|#"
+	// maxKeyPrefixSegments is the maximum number of segments kept in a key prefix.
+	maxKeyPrefixSegments = 3
+	// maxKeySegmentLength is the length above which a key segment is considered to be an identifier.
+	maxKeySegmentLength = 32
+	// keyPlaceholder replaces the variable part of a key.
+	keyPlaceholder = "*"
+)
+
+// Request is a decoded memcached request fragment.
+type Request struct {
+	Command Command
+	// KeyPrefix is the quantized key of the (first) item of the request.
+	KeyPrefix string
+	Binary    bool
+}
+
+// DecodeRequest decodes the beginning of a memcached request. truncated tells whether
+// the fragment is shorter than the request. ok is false if the fragment is neither a
+// text nor a binary memcached request.
+func DecodeRequest(fragment []byte, truncated bool) (req Request, ok bool) {
+	if len(fragment) == 0 {
+		return req, false
+	}
+	if fragment[0] == binaryRequestMagic {
+		return decodeBinaryRequest(fragment)
+	}
+	return decodeTextRequest(fragment, truncated)
+}
+
+// decodeTextRequest decodes a request of the text protocol: `<command> <key> [<args>]\r\n`.
+func decodeTextRequest(fragment []byte, truncated bool) (req Request, ok bool) {
+	name, rest, found := bytes.Cut(fragment, []byte(" "))
+	if !found {
+		return req, false
+	}
+	req.Command = commandFromText(string(name))
+	if req.Command == UnknownCommand {
+		return req, false
+	}
+
+	end := bytes.IndexAny(rest, " \r\n")
+	keyTruncated := false
+	if end < 0 {
+		end = len(rest)
+		keyTruncated = truncated
+	}
+	if end == 0 {
+		return req, false
+	}
+	req.KeyPrefix = QuantizeKey(rest[:end], keyTruncated)
+	return req, true
+}
+
+// decodeBinaryRequest decodes a request of the binary protocol, whose key follows the
+// header and the extras.
+func decodeBinaryRequest(fragment []byte) (req Request, ok bool) {
+	if len(fragment) < binaryHeaderSize {
+		return req, false
+	}
+	req.Binary = true
+	req.Command = commandFromOpcode(fragment[1])
+	keyLen := int(binary.BigEndian.Uint16(fragment[2:4]))
+	keyStart := binaryHeaderSize + int(fragment[4])
+	if keyLen == 0 || keyStart >= len(fragment) {
+		return req, true
+	}
+	key := fragment[keyStart:]
+	keyTruncated := len(key) < keyLen
+	if !keyTruncated {
+		key = key[:keyLen]
+	}
+	req.KeyPrefix = QuantizeKey(key, keyTruncated)
+	return req, true
+}
+
+// DecodeResponse returns the status of a memcached response from its beginning.
+func DecodeResponse(fragment []byte) Status {
+	if len(fragment) == 0 {
+		return UnknownStatus
+	}
+	if fragment[0] == binaryResponseMagic {
+		return decodeBinaryResponse(fragment)
+	}
+	return decodeTextResponse(fragment)
+}
+
+// decodeTextResponse decodes the first line of a text protocol response.
+// https://github.com/memcached/memcached/blob/master/doc/protocol.txt
+func decodeTextResponse(fragment []byte) Status {
+	word := fragment
+	if i := bytes.IndexAny(fragment, " \r\n"); i >= 0 {
+		word = fragment[:i]
+	}
+	switch string(word) {
+	case "VALUE", "STORED", "DELETED", "TOUCHED", "HD", "VA":
+		return Hit
+	case "END", "NOT_FOUND", "NOT_STORED", "EXISTS", "EN", "NF", "NS", "EX":
+		return Miss
+	case "ERROR", "CLIENT_ERROR", "SERVER_ERROR":
+		return Error
+	}
+	// incr and decr respond with the new value of the item
+	if len(word) > 0 && isDigits(word) {
+		return Hit
+	}
+	return UnknownStatus
+}
+
+// decodeBinaryResponse decodes the status of a binary protocol response header.
+// https://github.com/memcached/memcached/wiki/BinaryProtocolRevamped#response-status
+func decodeBinaryResponse(fragment []byte) Status {
+	if len(fragment) < 8 {
+		return UnknownStatus
+	}
+	switch binary.BigEndian.Uint16(fragment[6:8]) {
+	case 0x0000:
+		return Hit
+	// key not found, key exists, item not stored
+	case 0x0001, 0x0002, 0x0005:
+		return Miss
+	default:
+		return Error
+	}
+}
+
+// QuantizeKey returns the prefix of a memcached key, to group requests of similar keys
+// without keeping track of every key. The key is split in segments by the usual
+// delimiters, and the segments are kept up to the first one looking like an identifier,
+// which is replaced, with the rest of the key, by a placeholder. For instance
+// "user:1234:profile" becomes "user:*". truncated tells whether the key is incomplete,
+// in which case its last segment is considered variable.
+func QuantizeKey(key []byte, truncated bool) string {
+	var b strings.Builder
+	for segments := 0; len(key) > 0; segments++ {
+		segment := key
+		i := bytes.IndexAny(key, keyDelimiters)
+		if i >= 0 {
+			segment = key[:i]
+		}
+		if segments == maxKeyPrefixSegments || isVariableKeySegment(segment) || (i < 0 && truncated) {
+			b.WriteString(keyPlaceholder)
+			break
+		}
+		b.Write(segment)
+		if i < 0 {
+			break
+		}
+		b.WriteByte(key[i])
+		key = key[i+1:]
+	}
+	return b.String()
+}
+
+// isVariableKeySegment returns true if the segment looks like an identifier.
+func isVariableKeySegment(segment []byte) bool {
+	if len(segment) > maxKeySegmentLength {
+		return true
+	}
+	for _, c := range segment {
+		if c >= '0' && c <= '9' {
+			return true
+		}
+	}
+	return false
+}
+
+func isDigits(b []byte) bool {
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,203 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package memcached
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeBinaryPacket encodes a binary protocol packet, with a request or response
+// magic, whose vbucket or status field is set to status.
+func encodeBinaryPacket(magic, opcode uint8, status uint16, extras []byte, key string, value string) []byte {
+	b := make([]byte, binaryHeaderSize, binaryHeaderSize+len(extras)+len(key)+len(value))
+	b[0] = magic
+	b[1] = opcode
+	binary.BigEndian.PutUint16(b[2:4], uint16(len(key)))
+	b[4] = uint8(len(extras))
+	binary.BigEndian.PutUint16(b[6:8], status)
+	binary.BigEndian.PutUint32(b[8:12], uint32(len(extras)+len(key)+len(value)))
+	b = append(b, extras...)
+	b = append(b, key...)
+	return append(b, value...)
+}
+
+// The sizes of the fragments captured by the eBPF program, see types.h
+const (
+	requestFragmentSize  = 64
+	responseFragmentSize = 24
+)
+
+// fragment returns the beginning of the request, as captured by the eBPF program.
+func fragment(packet []byte) ([]byte, bool) {
+	if len(packet) > requestFragmentSize {
+		return packet[:requestFragmentSize], true
+	}
+	return packet, false
+}
+
+func TestDecodeTextRequest(t *testing.T) {
+	tests := []struct {
+		request   string
+		command   Command
+		keyPrefix string
+	}{
+		{"get user:1234:profile\r\n", GetCommand, "user:*"},
+		{"gets session\r\n", GetsCommand, "session"},
+		{"get a:b c:d\r\n", GetCommand, "a:b"},
+		{"set config.features.flags 0 3600 5\r\nhello\r\n", SetCommand, "config.features.flags"},
+		{"add cache:page:home:en 0 0 2\r\nhi\r\n", AddCommand, "cache:page:home:*"},
+		{"delete 42\r\n", DeleteCommand, "*"},
+		{"incr counter_views_a3f 1\r\n", IncrCommand, "counter_views_*"},
+		{"touch rate-limit-user 10\r\n", TouchCommand, "rate-limit-user"},
+		{"mg user:abc v t\r\n", MetaGetCommand, "user:abc"},
+		{"ms user:abc 2 T90\r\nhi\r\n", MetaSetCommand, "user:abc"},
+		{"get " + strings.Repeat("k", 40) + "\r\n", GetCommand, "*"},
+		// the key is cut by the end of the fragment
+		{"get users:profiles:" + strings.Repeat("x", 100) + "\r\n", GetCommand, "users:profiles:*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.request, func(t *testing.T) {
+			req, ok := DecodeRequest(fragment([]byte(tt.request)))
+			require.True(t, ok)
+			assert.Equal(t, tt.command, req.Command)
+			assert.Equal(t, tt.keyPrefix, req.KeyPrefix)
+			assert.False(t, req.Binary)
+		})
+	}
+}
+
+func TestDecodeTextRequestErrors(t *testing.T) {
+	for _, request := range []string{
+		"",
+		"GET / HTTP/1.1\r\n",
+		"stats\r\n",
+		"version\r\n",
+		"get \r\n",
+	} {
+		_, ok := DecodeRequest(fragment([]byte(request)))
+		assert.False(t, ok, request)
+	}
+}
+
+func TestDecodeBinaryRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		packet    []byte
+		command   Command
+		keyPrefix string
+	}{
+		{
+			name:      "get",
+			packet:    encodeBinaryPacket(binaryRequestMagic, 0x00, 0, nil, "user:1234", ""),
+			command:   GetCommand,
+			keyPrefix: "user:*",
+		},
+		{
+			name:      "quiet get with key",
+			packet:    encodeBinaryPacket(binaryRequestMagic, 0x0d, 0, nil, "session:abc", ""),
+			command:   GetCommand,
+			keyPrefix: "session:abc",
+		},
+		{
+			name:      "set with extras",
+			packet:    encodeBinaryPacket(binaryRequestMagic, 0x01, 0, make([]byte, 8), "config:flags", "value"),
+			command:   SetCommand,
+			keyPrefix: "config:flags",
+		},
+		{
+			name:      "truncated key",
+			packet:    encodeBinaryPacket(binaryRequestMagic, 0x01, 0, make([]byte, 8), "cache:pages:"+strings.Repeat("p", 60), "value"),
+			command:   SetCommand,
+			keyPrefix: "cache:pages:*",
+		},
+		{
+			name:    "noop",
+			packet:  encodeBinaryPacket(binaryRequestMagic, 0x0a, 0, nil, "", ""),
+			command: UnknownCommand,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, ok := DecodeRequest(fragment(tt.packet))
+			require.True(t, ok)
+			assert.Equal(t, tt.command, req.Command)
+			assert.Equal(t, tt.keyPrefix, req.KeyPrefix)
+			assert.True(t, req.Binary)
+		})
+	}
+
+	_, ok := DecodeRequest([]byte{binaryRequestMagic, 0x00}, false)
+	assert.False(t, ok)
+}
+
+func TestDecodeResponse(t *testing.T) {
+	tests := []struct {
+		response string
+		status   Status
+	}{
+		{"VALUE user:1 0 5\r\nhello\r\nEND\r\n", Hit},
+		{"END\r\n", Miss},
+		{"STORED\r\n", Hit},
+		{"NOT_STORED\r\n", Miss},
+		{"EXISTS\r\n", Miss},
+		{"DELETED\r\n", Hit},
+		{"NOT_FOUND\r\n", Miss},
+		{"TOUCHED\r\n", Hit},
+		{"43\r\n", Hit},
+		{"HD\r\n", Hit},
+		{"VA 2 t90\r\nhi\r\n", Hit},
+		{"EN\r\n", Miss},
+		{"ERROR\r\n", Error},
+		{"CLIENT_ERROR bad data chunk\r\n", Error},
+		{"SERVER_ERROR out of memory storing object\r\n", Error},
+		{"VERSION 1.6.21\r\n", UnknownStatus},
+		{"", UnknownStatus},
+		{string(encodeBinaryPacket(binaryResponseMagic, 0x00, 0x0000, make([]byte, 4), "", "hello")), Hit},
+		{string(encodeBinaryPacket(binaryResponseMagic, 0x00, 0x0001, nil, "", "Not found")), Miss},
+		{string(encodeBinaryPacket(binaryResponseMagic, 0x01, 0x0002, nil, "", "Data exists for key.")), Miss},
+		{string(encodeBinaryPacket(binaryResponseMagic, 0x01, 0x0003, nil, "", "Too large.")), Error},
+		{string(encodeBinaryPacket(binaryResponseMagic, 0x01, 0x0082, nil, "", "Out of memory")), Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.response, func(t *testing.T) {
+			response := []byte(tt.response)
+			if len(response) > responseFragmentSize {
+				response = response[:responseFragmentSize]
+			}
+			assert.Equal(t, tt.status, DecodeResponse(response))
+		})
+	}
+}
+
+func TestQuantizeKey(t *testing.T) {
+	tests := []struct {
+		key       string
+		truncated bool
+		expected  string
+	}{
+		{"config", false, "config"},
+		{"user:1234:profile", false, "user:*"},
+		{"user:", false, "user:"},
+		{"a.b.c.d", false, "a.b.c.*"},
+		{"sessions/eu/3f2a", false, "sessions/eu/*"},
+		{"sessions|active", false, "sessions|active"},
+		{"prefix:partial", true, "prefix:*"},
+		{"v2", false, "*"},
+		{"", false, ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, QuantizeKey([]byte(tt.key), tt.truncated), tt.key)
+	}
+}
@@ -0,0 +1,113 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package memcached
+
+import (
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/pkg/network/protocols"
+	"github.com/DataDog/datadog-agent/pkg/network/types"
+)
+
+// EventWrapper wraps an ebpf event and provides additional methods to extract information from it.
+// We use this wrapper to avoid decoding the same fragments multiple times.
+type EventWrapper struct {
+	*EbpfEvent
+
+	requestSet bool
+	request    Request
+	requestOK  bool
+	statusSet  bool
+	status     Status
+}
+
+// NewEventWrapper creates a new EventWrapper from an ebpf event.
+func NewEventWrapper(e *EbpfEvent) *EventWrapper {
+	return &EventWrapper{EbpfEvent: e}
+}
+
+// ConnTuple returns the connection tuple for the transaction
+func (e *EventWrapper) ConnTuple() types.ConnectionKey {
+	return types.ConnectionKey{
+		SrcIPHigh: e.Tuple.Saddr_h,
+		SrcIPLow:  e.Tuple.Saddr_l,
+		DstIPHigh: e.Tuple.Daddr_h,
+		DstIPLow:  e.Tuple.Daddr_l,
+		SrcPort:   e.Tuple.Sport,
+		DstPort:   e.Tuple.Dport,
+	}
+}
+
+// getRequestFragment returns the actual request fragment from the event.
+func (e *EbpfTx) getRequestFragment() []byte {
+	if e.Original_request_size > uint32(len(e.Request_fragment)) {
+		return e.Request_fragment[:]
+	}
+	return e.Request_fragment[:e.Original_request_size]
+}
+
+func (e *EventWrapper) decodeRequest() {
+	if !e.requestSet {
+		e.request, e.requestOK = DecodeRequest(e.Tx.getRequestFragment(), e.Tx.Original_request_size > uint32(len(e.Tx.Request_fragment)))
+		e.requestSet = true
+	}
+}
+
+// Valid returns true if the request was decoded, and the response is a known one.
+func (e *EventWrapper) Valid() bool {
+	e.decodeRequest()
+	return e.requestOK && e.Status() != UnknownStatus
+}
+
+// Command returns the command of the request.
+func (e *EventWrapper) Command() Command {
+	e.decodeRequest()
+	return e.request.Command
+}
+
+// KeyPrefix returns the quantized key of the request.
+func (e *EventWrapper) KeyPrefix() string {
+	e.decodeRequest()
+	return e.request.KeyPrefix
+}
+
+// Binary returns true if the request uses the binary protocol.
+func (e *EventWrapper) Binary() bool {
+	e.decodeRequest()
+	return e.request.Binary
+}
+
+// Status returns the status of the response.
+func (e *EventWrapper) Status() Status {
+	if !e.statusSet {
+		e.status = DecodeResponse(e.Tx.Response_fragment[:])
+		e.statusSet = true
+	}
+	return e.status
+}
+
+// RequestLatency returns the latency of the request in nanoseconds
+func (e *EventWrapper) RequestLatency() float64 {
+	if uint64(e.Tx.Request_started) == 0 || uint64(e.Tx.Response_last_seen) == 0 {
+		return 0
+	}
+	return protocols.NSTimestampToFloat(e.Tx.Response_last_seen - e.Tx.Request_started)
+}
+
+const template = `
+ebpfTx{
+	Command: %q,
+	Key Prefix: %q,
+	Status: %q,
+	Latency: %f
+}`
+
+// String returns a string representation of the underlying event
+func (e *EventWrapper) String() string {
+	return fmt.Sprintf(template, e.Command(), e.KeyPrefix(), e.Status(), e.RequestLatency())
+}
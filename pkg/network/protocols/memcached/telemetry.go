@@ -0,0 +1,69 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package memcached
+
+import (
+	libtelemetry "github.com/DataDog/datadog-agent/pkg/network/protocols/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// Telemetry is a struct to hold the telemetry for the memcached protocol
+type Telemetry struct {
+	metricGroup *libtelemetry.MetricGroup
+
+	textRequests   *libtelemetry.Counter
+	binaryRequests *libtelemetry.Counter
+	hits           *libtelemetry.Counter
+	misses         *libtelemetry.Counter
+	errors         *libtelemetry.Counter
+	// invalidEvents counts the transactions whose request or response couldn't be decoded
+	invalidEvents *libtelemetry.Counter
+}
+
+// NewTelemetry creates a new Telemetry
+func NewTelemetry() *Telemetry {
+	metricGroup := libtelemetry.NewMetricGroup("usm.memcached")
+
+	return &Telemetry{
+		metricGroup:    metricGroup,
+		textRequests:   metricGroup.NewCounter("requests", "protocol:text", libtelemetry.OptStatsd),
+		binaryRequests: metricGroup.NewCounter("requests", "protocol:binary", libtelemetry.OptStatsd),
+		hits:           metricGroup.NewCounter("responses", "status:hit", libtelemetry.OptStatsd),
+		misses:         metricGroup.NewCounter("responses", "status:miss", libtelemetry.OptStatsd),
+		errors:         metricGroup.NewCounter("responses", "status:error", libtelemetry.OptStatsd),
+		invalidEvents:  metricGroup.NewCounter("invalid_events", libtelemetry.OptStatsd),
+	}
+}
+
+// Count increments the telemetry counters based on the event data
+func (t *Telemetry) Count(eventWrapper *EventWrapper) {
+	if !eventWrapper.Valid() {
+		t.invalidEvents.Add(1)
+		return
+	}
+
+	if eventWrapper.Binary() {
+		t.binaryRequests.Add(1)
+	} else {
+		t.textRequests.Add(1)
+	}
+
+	switch eventWrapper.Status() {
+	case Hit:
+		t.hits.Add(1)
+	case Miss:
+		t.misses.Add(1)
+	case Error:
+		t.errors.Add(1)
+	}
+}
+
+// Log logs the memcached stats summary
+func (t *Telemetry) Log() {
+	log.Debugf("memcached stats summary: %s", t.metricGroup.Summary())
+}
@@ -31,6 +31,12 @@ const (
 	MySQL
 	// GRPC protocol
 	GRPC
+	// Memcached protocol
+	Memcached
+	// Cassandra protocol
+	Cassandra
+	// DNS protocol, monitored by USM over TLS only
+	DNS
 )
 
 // String returns the string representation of the protocol
@@ -58,6 +64,12 @@ func (p ProtocolType) String() string {
 		return "MySQL"
 	case GRPC:
 		return "gRPC"
+	case Memcached:
+		return "Memcached"
+	case Cassandra:
+		return "Cassandra"
+	case DNS:
+		return "DNS"
 	default:
 		// shouldn't happen
 		return "Invalid"
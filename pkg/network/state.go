@@ -18,8 +18,10 @@ import (
 	telemetryComponent "github.com/DataDog/datadog-agent/comp/core/telemetry"
 	"github.com/DataDog/datadog-agent/pkg/network/dns"
 	"github.com/DataDog/datadog-agent/pkg/network/protocols"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols/cassandra"
 	"github.com/DataDog/datadog-agent/pkg/network/protocols/http"
 	"github.com/DataDog/datadog-agent/pkg/network/protocols/kafka"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols/memcached"
 	"github.com/DataDog/datadog-agent/pkg/network/protocols/postgres"
 	"github.com/DataDog/datadog-agent/pkg/network/slice"
 	"github.com/DataDog/datadog-agent/pkg/process/util"
@@ -43,6 +45,8 @@ var stateTelemetry = struct {
 	http2StatsDropped      *telemetry.StatCounterWrapper
 	kafkaStatsDropped      *telemetry.StatCounterWrapper
 	postgresStatsDropped   *telemetry.StatCounterWrapper
+	memcachedStatsDropped  *telemetry.StatCounterWrapper
+	cassandraStatsDropped  *telemetry.StatCounterWrapper
 	dnsPidCollisions       *telemetry.StatCounterWrapper
 	incomingDirectionFixes telemetry.Counter
 	outgoingDirectionFixes telemetry.Counter
@@ -57,6 +61,8 @@ var stateTelemetry = struct {
 	telemetry.NewStatCounterWrapper(stateModuleName, "http2_stats_dropped", []string{}, "Counter measuring the number of http2 stats dropped"),
 	telemetry.NewStatCounterWrapper(stateModuleName, "kafka_stats_dropped", []string{}, "Counter measuring the number of kafka stats dropped"),
 	telemetry.NewStatCounterWrapper(stateModuleName, "postgres_stats_dropped", []string{}, "Counter measuring the number of postgres stats dropped"),
+	telemetry.NewStatCounterWrapper(stateModuleName, "memcached_stats_dropped", []string{}, "Counter measuring the number of memcached stats dropped"),
+	telemetry.NewStatCounterWrapper(stateModuleName, "cassandra_stats_dropped", []string{}, "Counter measuring the number of cassandra stats dropped"),
 	telemetry.NewStatCounterWrapper(stateModuleName, "dns_pid_collisions", []string{}, "Counter measuring the number of DNS PID collisions"),
 	telemetry.NewCounter(stateModuleName, "incoming_direction_fixes", []string{}, "Counter measuring the number of udp direction fixes for incoming connections"),
 	telemetry.NewCounter(stateModuleName, "outgoing_direction_fixes", []string{}, "Counter measuring the number of udp/tcp direction fixes for outgoing connections"),
@@ -123,11 +129,13 @@ type State interface {
 
 // Delta represents a delta of network data compared to the last call to State.
 type Delta struct {
-	Conns    []ConnectionStats
-	HTTP     map[http.Key]*http.RequestStats
-	HTTP2    map[http.Key]*http.RequestStats
-	Kafka    map[kafka.Key]*kafka.RequestStats
-	Postgres map[postgres.Key]*postgres.RequestStat
+	Conns     []ConnectionStats
+	HTTP      map[http.Key]*http.RequestStats
+	HTTP2     map[http.Key]*http.RequestStats
+	Kafka     map[kafka.Key]*kafka.RequestStats
+	Postgres  map[postgres.Key]*postgres.RequestStat
+	Memcached map[memcached.Key]*memcached.RequestStat
+	Cassandra map[cassandra.Key]*cassandra.RequestStat
 }
 
 type lastStateTelemetry struct {
@@ -141,6 +149,8 @@ type lastStateTelemetry struct {
 	http2StatsDropped     int64
 	kafkaStatsDropped     int64
 	postgresStatsDropped  int64
+	memcachedStatsDropped int64
+	cassandraStatsDropped int64
 	dnsPidCollisions      int64
 }
 
@@ -237,12 +247,14 @@ type client struct {
 	closed    *closedConnections
 	stats     map[StatCookie]StatCounters
 	// maps by dns key the domain (string) to stats structure
-	dnsStats           dns.StatsByKeyByNameByType
-	httpStatsDelta     map[http.Key]*http.RequestStats
-	http2StatsDelta    map[http.Key]*http.RequestStats
-	kafkaStatsDelta    map[kafka.Key]*kafka.RequestStats
-	postgresStatsDelta map[postgres.Key]*postgres.RequestStat
-	lastTelemetries    map[ConnTelemetryType]int64
+	dnsStats            dns.StatsByKeyByNameByType
+	httpStatsDelta      map[http.Key]*http.RequestStats
+	http2StatsDelta     map[http.Key]*http.RequestStats
+	kafkaStatsDelta     map[kafka.Key]*kafka.RequestStats
+	postgresStatsDelta  map[postgres.Key]*postgres.RequestStat
+	memcachedStatsDelta map[memcached.Key]*memcached.RequestStat
+	cassandraStatsDelta map[cassandra.Key]*cassandra.RequestStat
+	lastTelemetries     map[ConnTelemetryType]int64
 }
 
 func (c *client) Reset() {
@@ -258,6 +270,8 @@ func (c *client) Reset() {
 	c.http2StatsDelta = make(map[http.Key]*http.RequestStats)
 	c.kafkaStatsDelta = make(map[kafka.Key]*kafka.RequestStats)
 	c.postgresStatsDelta = make(map[postgres.Key]*postgres.RequestStat)
+	c.memcachedStatsDelta = make(map[memcached.Key]*memcached.RequestStat)
+	c.cassandraStatsDelta = make(map[cassandra.Key]*cassandra.RequestStat)
 }
 
 type networkState struct {
@@ -277,6 +291,8 @@ type networkState struct {
 	maxHTTPStats                int
 	maxKafkaStats               int
 	maxPostgresStats            int
+	maxMemcachedStats           int
+	maxCassandraStats           int
 	enableConnectionRollup      bool
 	processEventConsumerEnabled bool
 
@@ -286,7 +302,7 @@ type networkState struct {
 }
 
 // NewState creates a new network state
-func NewState(_ telemetryComponent.Component, clientExpiry time.Duration, maxClosedConns uint32, maxClientStats, maxDNSStats, maxHTTPStats, maxKafkaStats, maxPostgresStats int, enableConnectionRollup bool, processEventConsumerEnabled bool) State {
+func NewState(_ telemetryComponent.Component, clientExpiry time.Duration, maxClosedConns uint32, maxClientStats, maxDNSStats, maxHTTPStats, maxKafkaStats, maxPostgresStats, maxMemcachedStats, maxCassandraStats int, enableConnectionRollup bool, processEventConsumerEnabled bool) State {
 	ns := &networkState{
 		clients:                map[string]*client{},
 		clientExpiry:           clientExpiry,
@@ -296,6 +312,8 @@ func NewState(_ telemetryComponent.Component, clientExpiry time.Duration, maxClo
 		maxHTTPStats:           maxHTTPStats,
 		maxKafkaStats:          maxKafkaStats,
 		maxPostgresStats:       maxPostgresStats,
+		maxMemcachedStats:      maxMemcachedStats,
+		maxCassandraStats:      maxCassandraStats,
 		enableConnectionRollup: enableConnectionRollup,
 		mergeStatsBuffers: [2][]byte{
 			make([]byte, ConnectionByteKeyMaxLen),
@@ -389,6 +407,10 @@ func (ns *networkState) GetDelta(
 	if len(dnsStats) > 0 {
 		ns.storeDNSStats(dnsStats)
 	}
+	// DNS over TLS stats are collected by USM, and attached to the connections along with the other DNS stats
+	if dotStats, ok := usmStats[protocols.DNS].(dns.StatsByKeyByNameByType); ok && len(dotStats) > 0 {
+		ns.storeDNSStats(dotStats)
+	}
 
 	aggr := newConnectionAggregator((len(closed)+len(active))/2, ns.enableConnectionRollup, ns.processEventConsumerEnabled, client.dnsStats)
 	active = filterConnections(active, func(c *ConnectionStats) bool {
@@ -415,15 +437,23 @@ func (ns *networkState) GetDelta(
 		case protocols.Postgres:
 			stats := protocolStats.(map[postgres.Key]*postgres.RequestStat)
 			ns.storePostgresStats(stats)
+		case protocols.Memcached:
+			stats := protocolStats.(map[memcached.Key]*memcached.RequestStat)
+			ns.storeMemcachedStats(stats)
+		case protocols.Cassandra:
+			stats := protocolStats.(map[cassandra.Key]*cassandra.RequestStat)
+			ns.storeCassandraStats(stats)
 		}
 	}
 
 	return Delta{
-		Conns:    append(active, closed...),
-		HTTP:     client.httpStatsDelta,
-		HTTP2:    client.http2StatsDelta,
-		Kafka:    client.kafkaStatsDelta,
-		Postgres: client.postgresStatsDelta,
+		Conns:     append(active, closed...),
+		HTTP:      client.httpStatsDelta,
+		HTTP2:     client.http2StatsDelta,
+		Kafka:     client.kafkaStatsDelta,
+		Postgres:  client.postgresStatsDelta,
+		Memcached: client.memcachedStatsDelta,
+		Cassandra: client.cassandraStatsDelta,
 	}
 }
 
@@ -477,11 +507,14 @@ func (ns *networkState) logTelemetry() {
 	http2StatsDroppedDelta := stateTelemetry.http2StatsDropped.Load() - ns.lastTelemetry.http2StatsDropped
 	kafkaStatsDroppedDelta := stateTelemetry.kafkaStatsDropped.Load() - ns.lastTelemetry.kafkaStatsDropped
 	postgresStatsDroppedDelta := stateTelemetry.postgresStatsDropped.Load() - ns.lastTelemetry.postgresStatsDropped
+	memcachedStatsDroppedDelta := stateTelemetry.memcachedStatsDropped.Load() - ns.lastTelemetry.memcachedStatsDropped
+	cassandraStatsDroppedDelta := stateTelemetry.cassandraStatsDropped.Load() - ns.lastTelemetry.cassandraStatsDropped
 	dnsPidCollisionsDelta := stateTelemetry.dnsPidCollisions.Load() - ns.lastTelemetry.dnsPidCollisions
 
 	// Flush log line if any metric is non-zero
 	if connDroppedDelta > 0 || closedConnDroppedDelta > 0 || dnsStatsDroppedDelta > 0 || httpStatsDroppedDelta > 0 ||
-		http2StatsDroppedDelta > 0 || kafkaStatsDroppedDelta > 0 || postgresStatsDroppedDelta > 0 {
+		http2StatsDroppedDelta > 0 || kafkaStatsDroppedDelta > 0 || postgresStatsDroppedDelta > 0 ||
+		memcachedStatsDroppedDelta > 0 || cassandraStatsDroppedDelta > 0 {
 		s := "State telemetry: "
 		s += " [%d connections dropped due to stats]"
 		s += " [%d closed connections dropped]"
@@ -490,6 +523,8 @@ func (ns *networkState) logTelemetry() {
 		s += " [%d HTTP2 stats dropped]"
 		s += " [%d Kafka stats dropped]"
 		s += " [%d postgres stats dropped]"
+		s += " [%d memcached stats dropped]"
+		s += " [%d cassandra stats dropped]"
 		log.Warnf(s,
 			connDroppedDelta,
 			closedConnDroppedDelta,
@@ -498,6 +533,8 @@ func (ns *networkState) logTelemetry() {
 			http2StatsDroppedDelta,
 			kafkaStatsDroppedDelta,
 			postgresStatsDroppedDelta,
+			memcachedStatsDroppedDelta,
+			cassandraStatsDroppedDelta,
 		)
 	}
 
@@ -527,6 +564,8 @@ func (ns *networkState) logTelemetry() {
 	ns.lastTelemetry.http2StatsDropped = stateTelemetry.http2StatsDropped.Load()
 	ns.lastTelemetry.kafkaStatsDropped = stateTelemetry.kafkaStatsDropped.Load()
 	ns.lastTelemetry.postgresStatsDropped = stateTelemetry.postgresStatsDropped.Load()
+	ns.lastTelemetry.memcachedStatsDropped = stateTelemetry.memcachedStatsDropped.Load()
+	ns.lastTelemetry.cassandraStatsDropped = stateTelemetry.cassandraStatsDropped.Load()
 	ns.lastTelemetry.dnsPidCollisions = stateTelemetry.dnsPidCollisions.Load()
 }
 
@@ -652,12 +691,7 @@ func (ns *networkState) storeDNSStats(stats dns.StatsByKeyByNameByType) {
 
 					// If we've seen DNS stats for this key already, let's combine the two
 					if prev, ok := client.dnsStats[key][domain][qtype]; ok {
-						prev.Timeouts += dnsStats.Timeouts
-						prev.SuccessLatencySum += dnsStats.SuccessLatencySum
-						prev.FailureLatencySum += dnsStats.FailureLatencySum
-						for rcode, count := range dnsStats.CountByRcode {
-							prev.CountByRcode[rcode] += count
-						}
+						prev.CombineWith(dnsStats)
 						client.dnsStats[key][domain][qtype] = prev
 						continue
 					}
@@ -800,21 +834,85 @@ func (ns *networkState) storePostgresStats(allStats map[postgres.Key]*postgres.R
 	}
 }
 
+// storeMemcachedStats stores the latest Memcached stats for all clients
+func (ns *networkState) storeMemcachedStats(allStats map[memcached.Key]*memcached.RequestStat) {
+	if len(ns.clients) == 1 {
+		for _, client := range ns.clients {
+			if len(client.memcachedStatsDelta) == 0 && len(allStats) <= ns.maxMemcachedStats {
+				// optimization for the common case:
+				// if there is only one client and no previous state, no memory allocation is needed
+				client.memcachedStatsDelta = allStats
+				return
+			}
+		}
+	}
+
+	for key, stats := range allStats {
+		for _, client := range ns.clients {
+			prevStats, ok := client.memcachedStatsDelta[key]
+			if !ok && len(client.memcachedStatsDelta) >= ns.maxMemcachedStats {
+				stateTelemetry.memcachedStatsDropped.Inc()
+				continue
+			}
+
+			if prevStats != nil {
+				prevStats.CombineWith(stats)
+				client.memcachedStatsDelta[key] = prevStats
+			} else {
+				client.memcachedStatsDelta[key] = stats
+			}
+		}
+	}
+}
+
+// storeCassandraStats stores the latest Cassandra stats for all clients
+func (ns *networkState) storeCassandraStats(allStats map[cassandra.Key]*cassandra.RequestStat) {
+	if len(ns.clients) == 1 {
+		for _, client := range ns.clients {
+			if len(client.cassandraStatsDelta) == 0 && len(allStats) <= ns.maxCassandraStats {
+				// optimization for the common case:
+				// if there is only one client and no previous state, no memory allocation is needed
+				client.cassandraStatsDelta = allStats
+				return
+			}
+		}
+	}
+
+	for key, stats := range allStats {
+		for _, client := range ns.clients {
+			prevStats, ok := client.cassandraStatsDelta[key]
+			if !ok && len(client.cassandraStatsDelta) >= ns.maxCassandraStats {
+				stateTelemetry.cassandraStatsDropped.Inc()
+				continue
+			}
+
+			if prevStats != nil {
+				prevStats.CombineWith(stats)
+				client.cassandraStatsDelta[key] = prevStats
+			} else {
+				client.cassandraStatsDelta[key] = stats
+			}
+		}
+	}
+}
+
 func (ns *networkState) getClient(clientID string) *client {
 	if c, ok := ns.clients[clientID]; ok {
 		return c
 	}
 	closedConnections := &closedConnections{conns: make([]ConnectionStats, 0, minClosedCapacity), byCookie: make(map[StatCookie]int)}
 	c := &client{
-		lastFetch:          time.Now(),
-		stats:              make(map[StatCookie]StatCounters),
-		closed:             closedConnections,
-		dnsStats:           dns.StatsByKeyByNameByType{},
-		httpStatsDelta:     map[http.Key]*http.RequestStats{},
-		http2StatsDelta:    map[http.Key]*http.RequestStats{},
-		kafkaStatsDelta:    map[kafka.Key]*kafka.RequestStats{},
-		postgresStatsDelta: map[postgres.Key]*postgres.RequestStat{},
-		lastTelemetries:    make(map[ConnTelemetryType]int64),
+		lastFetch:           time.Now(),
+		stats:               make(map[StatCookie]StatCounters),
+		closed:              closedConnections,
+		dnsStats:            dns.StatsByKeyByNameByType{},
+		httpStatsDelta:      map[http.Key]*http.RequestStats{},
+		http2StatsDelta:     map[http.Key]*http.RequestStats{},
+		kafkaStatsDelta:     map[kafka.Key]*kafka.RequestStats{},
+		postgresStatsDelta:  map[postgres.Key]*postgres.RequestStat{},
+		memcachedStatsDelta: map[memcached.Key]*memcached.RequestStat{},
+		cassandraStatsDelta: map[cassandra.Key]*cassandra.RequestStat{},
+		lastTelemetries:     make(map[ConnTelemetryType]int64),
 	}
 	ns.clients[clientID] = c
 	return c
@@ -1398,12 +1496,7 @@ func (ac *aggregateConnection) merge(c *ConnectionStats) {
 					continue
 				}
 
-				queryStats.FailureLatencySum += stats.FailureLatencySum
-				queryStats.SuccessLatencySum += stats.SuccessLatencySum
-				queryStats.Timeouts += stats.Timeouts
-				for rcode, count := range stats.CountByRcode {
-					queryStats.CountByRcode[rcode] += count
-				}
+				queryStats.CombineWith(stats)
 				hostStats[q] = queryStats
 			}
 		}
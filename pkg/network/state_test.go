@@ -587,7 +587,7 @@ func TestNoPriorRegistrationActiveConnections(t *testing.T) {
 func TestCleanupClient(t *testing.T) {
 	clientID := "1"
 
-	state := NewState(nil, 100*time.Millisecond, 50000, 75000, 75000, 7500, 75000, 75000, false, false)
+	state := NewState(nil, 100*time.Millisecond, 50000, 75000, 75000, 7500, 75000, 75000, 75000, 75000, false, false)
 	clients := state.(*networkState).getClients()
 	assert.Equal(t, 0, len(clients))
 
@@ -1672,6 +1672,36 @@ func TestDNSStatsWithMultipleClients(t *testing.T) {
 	assert.EqualValues(t, 3, rcode)
 }
 
+func TestDNSOverTLSStats(t *testing.T) {
+	c := ConnectionStats{
+		Pid:    123,
+		Type:   TCP,
+		Family: AFINET,
+		Source: util.AddressFromString("10.0.0.1"),
+		Dest:   util.AddressFromString("1.1.1.1"),
+		SPort:  50000,
+		DPort:  853,
+		Cookie: 1,
+	}
+	dKey := dns.Key{ClientIP: c.Source, ClientPort: c.SPort, ServerIP: c.Dest, Protocol: getIPProtocol(c.Type)}
+	domain := dns.ToHostname("foo.com")
+	dotStats := dns.StatsByKeyByNameByType{
+		dKey: {domain: {dns.TypeA: dns.Stats{CountByRcode: map[uint32]uint32{DNSResponseCodeNoError: 2}}}},
+	}
+
+	client := "client"
+	state := newDefaultState()
+	state.RegisterClient(client)
+	assert.Len(t, state.GetDelta(client, latestEpochTime(), nil, nil, nil).Conns, 0)
+
+	c.Monotonic = StatCounters{SentBytes: 100, RecvBytes: 200}
+	c.LastUpdateEpoch = latestEpochTime()
+	delta := state.GetDelta(client, latestEpochTime(), []ConnectionStats{c}, nil, map[protocols.ProtocolType]interface{}{protocols.DNS: dotStats})
+	require.Len(t, delta.Conns, 1)
+	require.Contains(t, delta.Conns[0].DNSStats, domain)
+	assert.EqualValues(t, 2, delta.Conns[0].DNSStats[domain][dns.TypeA].CountByRcode[DNSResponseCodeNoError])
+}
+
 func TestHTTPStats(t *testing.T) {
 	t.Run("status code", func(t *testing.T) {
 		testHTTPStats(t, true)
@@ -2900,7 +2930,7 @@ func latestEpochTime() uint64 {
 
 func newDefaultState() *networkState {
 	// Using values from ebpf.NewConfig()
-	return NewState(nil, 2*time.Minute, 50000, 75000, 75000, 7500, 7500, 7500, false, false).(*networkState)
+	return NewState(nil, 2*time.Minute, 50000, 75000, 75000, 7500, 7500, 7500, 7500, 7500, false, false).(*networkState)
 }
 
 func getIPProtocol(nt ConnectionType) uint8 {
@@ -231,6 +231,8 @@ func NewTracer(config *config.Config, _ telemetryComponent.Component) (Tracer, e
 		ConstantEditors: []manager.ConstantEditor{
 			boolConst("tcpv6_enabled", config.CollectTCPv6Conns),
 			boolConst("udpv6_enabled", config.CollectUDPv6Conns),
+			boolConst("memcached_monitoring_enabled", config.EnableMemcachedMonitoring),
+			boolConst("cassandra_monitoring_enabled", config.EnableCassandraMonitoring),
 		},
 		DefaultKProbeMaxActive: maxActive,
 		BypassEnabled:          config.BypassEnabled,
@@ -277,6 +279,7 @@ func NewTracer(config *config.Config, _ telemetryComponent.Component) (Tracer, e
 	}
 	m.DumpHandler = dumpMapsHandler
 	ddebpf.AddNameMappings(m, "npm_tracer")
+	addMapDecoders()
 
 	batchMgr, err := newConnBatchManager(m)
 	if err != nil {
@@ -804,3 +807,13 @@ func (h *cookieHasher) Hash(stats *network.ConnectionStats) {
 	}
 	stats.Cookie = h.hash.Sum64()
 }
+
+// addMapDecoders registers the types of the tracer maps, so they can be decoded when inspected
+func addMapDecoders() {
+	ddebpf.AddMapDecoder[netebpf.ConnTuple, netebpf.ConnStats](probes.ConnMap)
+	ddebpf.AddMapDecoder[netebpf.ConnTuple, netebpf.TCPStats](probes.TCPStatsMap)
+	ddebpf.AddMapDecoder[netebpf.ConnTuple, uint32](probes.TCPRetransmitsMap)
+	ddebpf.AddMapDecoder[netebpf.PortBinding, uint32](probes.PortBindingsMap)
+	ddebpf.AddMapDecoder[netebpf.PortBinding, uint32](probes.UDPPortBindingsMap)
+	ddebpf.AddMapDecoder[netebpf.ConnTuple, netebpf.ProtocolStackWrapper](probes.ConnectionProtocolMap)
+}
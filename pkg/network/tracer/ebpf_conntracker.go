@@ -454,6 +454,7 @@ func getManager(cfg *config.Config, buf io.ReaderAt, opts manager.Options) (*man
 		return nil, err
 	}
 	ddebpf.AddNameMappings(mgr.Manager, "npm_conntracker")
+	ddebpf.AddMapDecoder[netebpf.ConntrackTuple, netebpf.ConntrackTuple](probes.ConntrackMap)
 	return mgr.Manager, nil
 }
 
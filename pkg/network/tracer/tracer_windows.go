@@ -84,6 +84,8 @@ func NewTracer(config *config.Config, telemetry telemetry.Component) (*Tracer, e
 		config.MaxHTTPStatsBuffered,
 		config.MaxKafkaStatsBuffered,
 		config.MaxPostgresStatsBuffered,
+		config.MaxMemcachedStatsBuffered,
+		config.MaxCassandraStatsBuffered,
 		config.EnableNPMConnectionRollup,
 		config.EnableProcessEventMonitoring,
 	)
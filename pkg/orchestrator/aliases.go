@@ -83,6 +83,12 @@ const (
 	K8sLimitRange = pkgorchestratormodel.K8sLimitRange
 	// K8sStorageClass alias for pkgorchestratormodel.K8sStorageClass
 	K8sStorageClass = pkgorchestratormodel.K8sStorageClass
+	// K8sPodDisruptionBudget alias for pkgorchestratormodel.K8sPodDisruptionBudget
+	K8sPodDisruptionBudget = pkgorchestratormodel.K8sPodDisruptionBudget
+	// K8sEndpointSlice alias for pkgorchestratormodel.K8sEndpointSlice
+	K8sEndpointSlice = pkgorchestratormodel.K8sEndpointSlice
+	// K8sPriorityClass alias for pkgorchestratormodel.K8sPriorityClass
+	K8sPriorityClass = pkgorchestratormodel.K8sPriorityClass
 	// ECSTask alias for pkgorchestratormodel.ECSTask
 	ECSTask = pkgorchestratormodel.ECSTask
 )
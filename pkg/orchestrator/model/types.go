@@ -83,6 +83,12 @@ const (
 	K8sLimitRange = 25
 	// K8sStorageClass represents a Kubernetes StorageClass
 	K8sStorageClass = 26
+	// K8sPodDisruptionBudget represents a Kubernetes PodDisruptionBudget
+	K8sPodDisruptionBudget = 27
+	// K8sEndpointSlice represents a Kubernetes EndpointSlice
+	K8sEndpointSlice = 28
+	// K8sPriorityClass represents a Kubernetes PriorityClass
+	K8sPriorityClass = 29
 	// ECSTask represents an ECS Task
 	ECSTask = 150
 )
@@ -116,6 +122,9 @@ func NodeTypes() []NodeType {
 		K8sNetworkPolicy,
 		K8sLimitRange,
 		K8sStorageClass,
+		K8sPodDisruptionBudget,
+		K8sEndpointSlice,
+		K8sPriorityClass,
 		ECSTask,
 	}
 }
@@ -174,6 +183,12 @@ func (n NodeType) String() string {
 		return "LimitRange"
 	case K8sStorageClass:
 		return "StorageClass"
+	case K8sPodDisruptionBudget:
+		return "PodDisruptionBudget"
+	case K8sEndpointSlice:
+		return "EndpointSlice"
+	case K8sPriorityClass:
+		return "PriorityClass"
 	case K8sUnsetType:
 		return "UnsetType"
 	case ECSTask:
@@ -213,6 +228,9 @@ func (n NodeType) Orchestrator() string {
 		K8sNetworkPolicy,
 		K8sLimitRange,
 		K8sStorageClass,
+		K8sPodDisruptionBudget,
+		K8sEndpointSlice,
+		K8sPriorityClass,
 		K8sUnsetType:
 		return "k8s"
 	case ECSTask:
@@ -21,7 +21,8 @@ type RuleBucket struct {
 // AddRule adds a rule to the bucket
 func (rb *RuleBucket) AddRule(rule *Rule) error {
 	for _, r := range rb.rules {
-		if r.ID == rule.ID {
+		// the steps of a sequence share the ID of their rule
+		if r.ID == rule.ID && (r.sequence == nil || r.sequence != rule.sequence) {
 			return &ErrRuleLoad{Definition: rule.Definition, Err: ErrDefinitionIDConflict}
 		}
 	}
@@ -19,6 +19,9 @@ var (
 	// ErrRuleWithoutExpression is returned when there is no expression
 	ErrRuleWithoutExpression = errors.New("no rule expression")
 
+	// ErrRuleWithExpressionAndSequence is returned when both an expression and a sequence are defined
+	ErrRuleWithExpressionAndSequence = errors.New("only one of 'expression' and 'sequence' can be defined")
+
 	// ErrRuleIDPattern is returned when there is no expression
 	ErrRuleIDPattern = errors.New("rule ID pattern error")
 
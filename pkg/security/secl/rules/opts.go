@@ -27,6 +27,7 @@ type Opts struct {
 	ReservedRuleIDs          []RuleID
 	EventTypeEnabled         map[eval.EventType]bool
 	StateScopes              map[Scope]VariableProviderFactory
+	SequenceKeys             map[SequenceKey][]eval.Field
 	Logger                   log.Logger
 }
 
@@ -66,6 +67,12 @@ func (o *Opts) WithStateScopes(stateScopes map[Scope]VariableProviderFactory) *O
 	return o
 }
 
+// WithSequenceKeys set the fields identifying each sequence correlation key
+func (o *Opts) WithSequenceKeys(sequenceKeys map[SequenceKey][]eval.Field) *Opts {
+	o.SequenceKeys = sequenceKeys
+	return o
+}
+
 // NewRuleOpts returns rule options
 func NewRuleOpts(eventTypeEnabled map[eval.EventType]bool) *Opts {
 	var ruleOpts Opts
@@ -82,6 +89,11 @@ func NewRuleOpts(eventTypeEnabled map[eval.EventType]bool) *Opts {
 					return ctx.Event.(*model.Event).ContainerContext
 				})
 			},
+		}).
+		WithSequenceKeys(map[SequenceKey][]eval.Field{
+			ProcessSequenceKey:   {"process.pid", "process.created_at"},
+			ContainerSequenceKey: {"container.id"},
+			CGroupSequenceKey:    {"cgroup.id"},
 		})
 
 	return &ruleOpts
@@ -128,11 +128,23 @@ RULES:
 			continue
 		}
 
-		if ruleDef.Expression == "" && !ruleDef.Disabled && ruleDef.Combine == "" {
+		if ruleDef.Expression == "" && ruleDef.Sequence == nil && !ruleDef.Disabled && ruleDef.Combine == "" {
 			errs = multierror.Append(errs, &ErrRuleLoad{Definition: ruleDef, Err: ErrRuleWithoutExpression})
 			continue
 		}
 
+		if ruleDef.Sequence != nil {
+			if ruleDef.Expression != "" {
+				errs = multierror.Append(errs, &ErrRuleLoad{Definition: ruleDef, Err: ErrRuleWithExpressionAndSequence})
+				continue
+			}
+
+			if err := ruleDef.Sequence.Check(); err != nil {
+				errs = multierror.Append(errs, &ErrRuleLoad{Definition: ruleDef, Err: err})
+				continue
+			}
+		}
+
 		policy.AddRule(ruleDef)
 	}
 
@@ -111,6 +111,7 @@ type RuleDefinition struct {
 	Every                  time.Duration       `yaml:"every"`
 	Silent                 bool                `yaml:"silent"`
 	GroupID                string              `yaml:"group_id"`
+	Sequence               *SequenceDefinition `yaml:"sequence"`
 	Policy                 *Policy
 }
 
@@ -130,6 +131,7 @@ func applyOverride(rd1, rd2 *RuleDefinition) {
 	// for backward compatibility, by default only the expression is copied if no options
 	if len(rd2.OverrideOptions.Fields) == 0 {
 		rd1.Expression = rd2.Expression
+		rd1.Sequence = rd2.Sequence
 	} else if slices.Contains(rd2.OverrideOptions.Fields, OverrideAllFields) {
 		// keep the original policy
 		policy := rd1.Policy
@@ -139,6 +141,7 @@ func applyOverride(rd1, rd2 *RuleDefinition) {
 	} else {
 		if slices.Contains(rd2.OverrideOptions.Fields, OverrideExpressionField) {
 			rd1.Expression = rd2.Expression
+			rd1.Sequence = rd2.Sequence
 		}
 		if slices.Contains(rd2.OverrideOptions.Fields, OverrideActionFields) {
 			rd1.Actions = rd2.Actions
@@ -170,6 +173,24 @@ func (rd *RuleDefinition) MergeWith(rd2 *RuleDefinition) error {
 type Rule struct {
 	*eval.Rule
 	Definition *RuleDefinition
+
+	// sequence is set for the steps of a sequence rule
+	sequence     *sequence
+	sequenceStep int
+}
+
+// IsSequence returns whether the rule is a sequence rule
+func (r *Rule) IsSequence() bool {
+	return r.sequence != nil
+}
+
+// ruleSteps returns the rules evaluated against the events, i.e. the steps of a sequence rule
+// or the rule itself
+func (r *Rule) ruleSteps() []*Rule {
+	if r.sequence != nil {
+		return r.sequence.steps
+	}
+	return []*Rule{r}
 }
 
 // RuleSetListener describes the methods implemented by an object used to be
@@ -446,28 +467,38 @@ func (rs *RuleSet) AddRule(parsingContext *ast.ParsingContext, ruleDef *RuleDefi
 		tags = append(tags, k+":"+v)
 	}
 
-	rule := &Rule{
-		Rule:       eval.NewRule(ruleDef.ID, ruleDef.Expression, rs.evalOpts, tags...),
-		Definition: ruleDef,
-	}
+	var rule *Rule
+	if ruleDef.Sequence != nil {
+		seq, err := rs.compileSequence(parsingContext, ruleDef, tags)
+		if err != nil {
+			return nil, err
+		}
+		// the last step is notified when the sequence is complete
+		rule = seq.steps[len(seq.steps)-1]
+	} else {
+		rule = &Rule{
+			Rule:       eval.NewRule(ruleDef.ID, ruleDef.Expression, rs.evalOpts, tags...),
+			Definition: ruleDef,
+		}
 
-	if err := rule.Parse(parsingContext); err != nil {
-		return nil, &ErrRuleLoad{Definition: ruleDef, Err: &ErrRuleSyntax{Err: err}}
-	}
+		if err := rule.Parse(parsingContext); err != nil {
+			return nil, &ErrRuleLoad{Definition: ruleDef, Err: &ErrRuleSyntax{Err: err}}
+		}
 
-	if err := rule.GenEvaluator(rs.model, parsingContext); err != nil {
-		return nil, &ErrRuleLoad{Definition: ruleDef, Err: err}
-	}
+		if err := rule.GenEvaluator(rs.model, parsingContext); err != nil {
+			return nil, &ErrRuleLoad{Definition: ruleDef, Err: err}
+		}
 
-	eventType, err := GetRuleEventType(rule.Rule)
-	if err != nil {
-		return nil, &ErrRuleLoad{Definition: ruleDef, Err: err}
-	}
+		eventType, err := GetRuleEventType(rule.Rule)
+		if err != nil {
+			return nil, &ErrRuleLoad{Definition: ruleDef, Err: err}
+		}
 
-	// ignore event types not supported
-	if _, exists := rs.opts.EventTypeEnabled["*"]; !exists {
-		if _, exists := rs.opts.EventTypeEnabled[eventType]; !exists {
-			return nil, &ErrRuleLoad{Definition: ruleDef, Err: ErrEventTypeNotEnabled}
+		// ignore event types not supported
+		if _, exists := rs.opts.EventTypeEnabled["*"]; !exists {
+			if _, exists := rs.opts.EventTypeEnabled[eventType]; !exists {
+				return nil, &ErrRuleLoad{Definition: ruleDef, Err: ErrEventTypeNotEnabled}
+			}
 		}
 	}
 
@@ -490,20 +521,26 @@ func (rs *RuleSet) AddRule(parsingContext *ast.ParsingContext, ruleDef *RuleDefi
 		}
 	}
 
-	for _, event := range rule.GetEvaluator().EventTypes {
-		bucket, exists := rs.eventRuleBuckets[event]
-		if !exists {
-			bucket = &RuleBucket{}
-			rs.eventRuleBuckets[event] = bucket
-		}
+	// the steps of a sequence are added in reverse order so that an event matching
+	// consecutive steps only moves the sequence forward by one step
+	steps := rule.ruleSteps()
+	for i := len(steps) - 1; i >= 0; i-- {
+		step := steps[i]
+		for _, event := range step.GetEvaluator().EventTypes {
+			bucket, exists := rs.eventRuleBuckets[event]
+			if !exists {
+				bucket = &RuleBucket{}
+				rs.eventRuleBuckets[event] = bucket
+			}
 
-		if err := bucket.AddRule(rule); err != nil {
-			return nil, err
+			if err := bucket.AddRule(step); err != nil {
+				return nil, err
+			}
 		}
-	}
 
-	// Merge the fields of the new rule with the existing list of fields of the ruleset
-	rs.AddFields(rule.GetEvaluator().GetFields())
+		// Merge the fields of the new rule with the existing list of fields of the ruleset
+		rs.AddFields(step.GetEvaluator().GetFields())
+	}
 
 	rs.rules[ruleDef.ID] = rule
 
@@ -591,9 +628,11 @@ func (rs *RuleSet) GetFieldValues(field eval.Field) []eval.FieldValue {
 	var values []eval.FieldValue
 
 	for _, rule := range rs.rules {
-		rv := rule.GetFieldValues(field)
-		if len(rv) > 0 {
-			values = append(values, rv...)
+		for _, step := range rule.ruleSteps() {
+			rv := step.GetFieldValues(field)
+			if len(rv) > 0 {
+				values = append(values, rv...)
+			}
 		}
 	}
 
@@ -696,6 +735,10 @@ func (rs *RuleSet) Evaluate(event eval.Event) bool {
 	for _, rule := range bucket.rules {
 		utils.PprofDoWithoutContext(rule.GetPprofLabels(), func() {
 			if rule.GetEvaluator().Eval(ctx) {
+				// the steps of a sequence only match once the whole sequence is complete
+				if rule.sequence != nil && !rule.sequence.match(ctx, rule.sequenceStep) {
+					return
+				}
 
 				if rs.logger.IsTracing() {
 					rs.logger.Tracef("Rule `%s` matches with event `%s`\n", rule.ID, event)
@@ -0,0 +1,257 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package rules holds rules related files
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/ast"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+)
+
+const (
+	// defaultSequenceMaxKeys is the default number of correlation keys for which the progress
+	// of a sequence is tracked
+	defaultSequenceMaxKeys = 4096
+
+	// sequenceTimestampField is the field used to get the time of an event, the time of the
+	// evaluation is used if the model doesn't provide it
+	sequenceTimestampField = "event.timestamp"
+)
+
+// SequenceKey defines the scope in which the events of a sequence are correlated
+type SequenceKey string
+
+const (
+	// ProcessSequenceKey correlates the events of a same process
+	ProcessSequenceKey SequenceKey = "process"
+	// ContainerSequenceKey correlates the events of a same container
+	ContainerSequenceKey SequenceKey = "container"
+	// CGroupSequenceKey correlates the events of a same cgroup
+	CGroupSequenceKey SequenceKey = "cgroup"
+)
+
+// SequenceStepDefinition describes a step of a sequence
+type SequenceStepDefinition struct {
+	Expression string `yaml:"expression"`
+}
+
+// SequenceDefinition describes the 'sequence' section of a rule. The rule matches once events
+// matching each step have been seen in order, for the same correlation key, within the window.
+type SequenceDefinition struct {
+	Steps  []*SequenceStepDefinition `yaml:"steps"`
+	Key    SequenceKey               `yaml:"key"`
+	Window time.Duration             `yaml:"window"`
+	// Count is the number of events matching the last step required for the rule to match
+	Count int `yaml:"count"`
+	// MaxKeys is the number of correlation keys tracked, the oldest ones are evicted first
+	MaxKeys int `yaml:"max_keys"`
+}
+
+// Check returns an error if the sequence is invalid
+func (s *SequenceDefinition) Check() error {
+	if len(s.Steps) < 2 {
+		return errors.New("a sequence requires at least 2 steps")
+	}
+
+	for i, step := range s.Steps {
+		if step == nil || step.Expression == "" {
+			return fmt.Errorf("no expression for step %d of the sequence", i+1)
+		}
+	}
+
+	if s.Key == "" {
+		return errors.New("a sequence requires a correlation key")
+	}
+
+	if s.Window <= 0 {
+		return errors.New("a sequence requires a positive window")
+	}
+
+	if s.Count < 0 {
+		return errors.New("the count of a sequence can't be negative")
+	}
+
+	if s.MaxKeys < 0 {
+		return errors.New("the max keys of a sequence can't be negative")
+	}
+
+	return nil
+}
+
+// sequenceState holds the progress of a sequence for a correlation key
+type sequenceState struct {
+	start int64 // time of the first step, in nanoseconds
+	next  int   // index of the next expected step
+	count int   // number of events matching the last step
+}
+
+// sequence holds the compiled steps of a sequence rule and its progress for each correlation key
+type sequence struct {
+	steps     []*Rule
+	keys      []eval.Evaluator
+	timestamp eval.Evaluator
+	window    int64
+	count     int
+
+	lock   sync.Mutex
+	states *simplelru.LRU[string, *sequenceState]
+}
+
+// key returns the correlation key of the event, ok is false if the event can't be correlated
+func (s *sequence) key(ctx *eval.Context) (string, bool) {
+	var builder strings.Builder
+	for i, evaluator := range s.keys {
+		if i > 0 {
+			builder.WriteByte('/')
+		}
+
+		switch value := evaluator.Eval(ctx).(type) {
+		case string:
+			if value == "" {
+				return "", false
+			}
+			builder.WriteString(value)
+		case int:
+			builder.WriteString(strconv.Itoa(value))
+		default:
+			return "", false
+		}
+	}
+	return builder.String(), true
+}
+
+func (s *sequence) now(ctx *eval.Context) int64 {
+	if s.timestamp != nil {
+		if ts, ok := s.timestamp.Eval(ctx).(int); ok && ts != 0 {
+			return int64(ts)
+		}
+	}
+	return ctx.Now().UnixNano()
+}
+
+// match records that the event matched the given step and returns whether the sequence is complete
+func (s *sequence) match(ctx *eval.Context, step int) bool {
+	key, ok := s.key(ctx)
+	if !ok {
+		return false
+	}
+	now := s.now(ctx)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	state, found := s.states.Get(key)
+	if found && now-state.start > s.window {
+		s.states.Remove(key)
+		found = false
+	}
+
+	if step == 0 {
+		// restart the window as long as the second step hasn't been seen
+		if !found || state.next == 1 {
+			s.states.Add(key, &sequenceState{start: now, next: 1})
+		}
+		return false
+	}
+
+	if !found || state.next != step {
+		return false
+	}
+
+	if step < len(s.steps)-1 {
+		state.next++
+		return false
+	}
+
+	state.count++
+	if state.count < s.count {
+		return false
+	}
+
+	s.states.Remove(key)
+	return true
+}
+
+// compileSequence compiles the steps of a sequence rule
+func (rs *RuleSet) compileSequence(parsingContext *ast.ParsingContext, ruleDef *RuleDefinition, tags []string) (*sequence, error) {
+	def := ruleDef.Sequence
+	if err := def.Check(); err != nil {
+		return nil, &ErrRuleLoad{Definition: ruleDef, Err: err}
+	}
+
+	fields, exists := rs.opts.SequenceKeys[def.Key]
+	if !exists {
+		return nil, &ErrRuleLoad{Definition: ruleDef, Err: fmt.Errorf("unknown sequence key '%s'", def.Key)}
+	}
+
+	seq := &sequence{
+		window: def.Window.Nanoseconds(),
+		count:  def.Count,
+	}
+
+	for _, field := range fields {
+		evaluator, err := rs.model.GetEvaluator(field, "")
+		if err != nil {
+			return nil, &ErrRuleLoad{Definition: ruleDef, Err: fmt.Errorf("sequence key '%s' not supported: %w", def.Key, err)}
+		}
+		seq.keys = append(seq.keys, evaluator)
+	}
+
+	if evaluator, err := rs.model.GetEvaluator(sequenceTimestampField, ""); err == nil {
+		seq.timestamp = evaluator
+	}
+
+	maxKeys := def.MaxKeys
+	if maxKeys == 0 {
+		maxKeys = defaultSequenceMaxKeys
+	}
+	states, err := simplelru.NewLRU[string, *sequenceState](maxKeys, nil)
+	if err != nil {
+		return nil, &ErrRuleLoad{Definition: ruleDef, Err: err}
+	}
+	seq.states = states
+
+	for i, step := range def.Steps {
+		rule := &Rule{
+			Rule:         eval.NewRule(ruleDef.ID, step.Expression, rs.evalOpts, tags...),
+			Definition:   ruleDef,
+			sequence:     seq,
+			sequenceStep: i,
+		}
+
+		if err := rule.Parse(parsingContext); err != nil {
+			return nil, &ErrRuleLoad{Definition: ruleDef, Err: &ErrRuleSyntax{Err: fmt.Errorf("step %d: %w", i+1, err)}}
+		}
+
+		if err := rule.GenEvaluator(rs.model, parsingContext); err != nil {
+			return nil, &ErrRuleLoad{Definition: ruleDef, Err: fmt.Errorf("step %d: %w", i+1, err)}
+		}
+
+		eventType, err := GetRuleEventType(rule.Rule)
+		if err != nil {
+			return nil, &ErrRuleLoad{Definition: ruleDef, Err: fmt.Errorf("step %d: %w", i+1, err)}
+		}
+
+		if _, exists := rs.opts.EventTypeEnabled["*"]; !exists {
+			if _, exists := rs.opts.EventTypeEnabled[eventType]; !exists {
+				return nil, &ErrRuleLoad{Definition: ruleDef, Err: ErrEventTypeNotEnabled}
+			}
+		}
+
+		seq.steps = append(seq.steps, rule)
+	}
+
+	return seq, nil
+}
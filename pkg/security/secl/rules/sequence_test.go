@@ -0,0 +1,250 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+// Package rules holds rules related files
+package rules
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/ast"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
+)
+
+type sequenceHandler struct {
+	matches []string
+}
+
+func (h *sequenceHandler) RuleMatch(rule *Rule, event eval.Event) bool {
+	h.matches = append(h.matches, rule.ID+":"+event.GetType())
+	return true
+}
+
+func (h *sequenceHandler) EventDiscarderFound(_ *RuleSet, _ eval.Event, _ string, _ eval.EventType) {}
+
+func newSequenceRuleSet(t *testing.T, sequence *SequenceDefinition) (*RuleSet, *sequenceHandler) {
+	rs := newRuleSet()
+	handler := &sequenceHandler{}
+	rs.AddListener(handler)
+
+	ruleDef := &RuleDefinition{
+		ID:       "shadow_then_bind",
+		Sequence: sequence,
+	}
+	_, err := rs.AddRule(ast.NewParsingContext(), ruleDef)
+	require.NoError(t, err)
+
+	return rs, handler
+}
+
+func newSequenceEvent(eventType model.EventType, pid int, ts time.Duration) *model.Event {
+	ev := model.NewFakeEvent()
+	ev.Type = uint32(eventType)
+	ev.TimestampRaw = uint64(ts)
+	ev.SetFieldValue("process.pid", pid)
+	return ev
+}
+
+func newOpenEvent(pid int, ts time.Duration, path string) *model.Event {
+	ev := newSequenceEvent(model.FileOpenEventType, pid, ts)
+	ev.SetFieldValue("open.file.path", path)
+	return ev
+}
+
+func newBindEvent(pid int, ts time.Duration) *model.Event {
+	ev := newSequenceEvent(model.BindEventType, pid, ts)
+	ev.SetFieldValue("bind.addr.family", syscall.AF_INET)
+	return ev
+}
+
+func shadowThenBind() *SequenceDefinition {
+	return &SequenceDefinition{
+		Steps: []*SequenceStepDefinition{
+			{Expression: `open.file.path == "/etc/shadow"`},
+			{Expression: `bind.addr.family == AF_INET`},
+		},
+		Key:    ProcessSequenceKey,
+		Window: 30 * time.Second,
+	}
+}
+
+func TestSequenceRule(t *testing.T) {
+	t.Run("in order", func(t *testing.T) {
+		rs, handler := newSequenceRuleSet(t, shadowThenBind())
+
+		assert.False(t, rs.Evaluate(newBindEvent(1, time.Second)))
+		assert.False(t, rs.Evaluate(newOpenEvent(1, 2*time.Second, "/etc/shadow")))
+		assert.True(t, rs.Evaluate(newBindEvent(1, 3*time.Second)))
+		assert.Equal(t, []string{"shadow_then_bind:bind"}, handler.matches)
+
+		// the sequence starts over once complete
+		assert.False(t, rs.Evaluate(newBindEvent(1, 4*time.Second)))
+	})
+
+	t.Run("correlation key", func(t *testing.T) {
+		rs, handler := newSequenceRuleSet(t, shadowThenBind())
+
+		assert.False(t, rs.Evaluate(newOpenEvent(1, time.Second, "/etc/shadow")))
+		assert.False(t, rs.Evaluate(newBindEvent(2, 2*time.Second)))
+		assert.Empty(t, handler.matches)
+	})
+
+	t.Run("window", func(t *testing.T) {
+		rs, handler := newSequenceRuleSet(t, shadowThenBind())
+
+		assert.False(t, rs.Evaluate(newOpenEvent(1, time.Second, "/etc/shadow")))
+		assert.False(t, rs.Evaluate(newBindEvent(1, 32*time.Second)))
+		assert.Empty(t, handler.matches)
+
+		// a new first step restarts the window
+		assert.False(t, rs.Evaluate(newOpenEvent(1, 40*time.Second, "/etc/shadow")))
+		assert.False(t, rs.Evaluate(newOpenEvent(1, 60*time.Second, "/etc/shadow")))
+		assert.True(t, rs.Evaluate(newBindEvent(1, 85*time.Second)))
+	})
+
+	t.Run("count", func(t *testing.T) {
+		sequence := shadowThenBind()
+		sequence.Count = 3
+		rs, handler := newSequenceRuleSet(t, sequence)
+
+		assert.False(t, rs.Evaluate(newOpenEvent(1, time.Second, "/etc/shadow")))
+		assert.False(t, rs.Evaluate(newBindEvent(1, 2*time.Second)))
+		assert.False(t, rs.Evaluate(newBindEvent(1, 3*time.Second)))
+		assert.True(t, rs.Evaluate(newBindEvent(1, 4*time.Second)))
+		assert.Len(t, handler.matches, 1)
+	})
+
+	t.Run("consecutive steps of a same event", func(t *testing.T) {
+		rs, handler := newSequenceRuleSet(t, &SequenceDefinition{
+			Steps: []*SequenceStepDefinition{
+				{Expression: `open.file.path == "/etc/shadow"`},
+				{Expression: `open.file.path =~ "/etc/*"`},
+			},
+			Key:    ProcessSequenceKey,
+			Window: 30 * time.Second,
+		})
+
+		assert.False(t, rs.Evaluate(newOpenEvent(1, time.Second, "/etc/shadow")))
+		assert.True(t, rs.Evaluate(newOpenEvent(1, 2*time.Second, "/etc/passwd")))
+		assert.Len(t, handler.matches, 1)
+	})
+
+	t.Run("container key", func(t *testing.T) {
+		sequence := shadowThenBind()
+		sequence.Key = ContainerSequenceKey
+		rs, handler := newSequenceRuleSet(t, sequence)
+
+		open := newOpenEvent(1, time.Second, "/etc/shadow")
+		open.ContainerContext.ContainerID = "abc"
+		bind := newBindEvent(2, 2*time.Second)
+		bind.ContainerContext.ContainerID = "abc"
+
+		// events outside of a container aren't correlated
+		assert.False(t, rs.Evaluate(newOpenEvent(3, time.Second, "/etc/shadow")))
+		assert.False(t, rs.Evaluate(newBindEvent(3, 2*time.Second)))
+
+		assert.False(t, rs.Evaluate(open))
+		assert.True(t, rs.Evaluate(bind))
+		assert.Len(t, handler.matches, 1)
+	})
+
+	t.Run("max keys", func(t *testing.T) {
+		sequence := shadowThenBind()
+		sequence.MaxKeys = 2
+		rs, handler := newSequenceRuleSet(t, sequence)
+
+		for pid := 1; pid <= 3; pid++ {
+			assert.False(t, rs.Evaluate(newOpenEvent(pid, time.Second, "/etc/shadow")))
+		}
+		// the oldest key was evicted
+		assert.False(t, rs.Evaluate(newBindEvent(1, 2*time.Second)))
+		assert.True(t, rs.Evaluate(newBindEvent(3, 2*time.Second)))
+		assert.Len(t, handler.matches, 1)
+	})
+}
+
+func TestSequenceRuleSet(t *testing.T) {
+	rs, _ := newSequenceRuleSet(t, shadowThenBind())
+	rule := rs.GetRules()["shadow_then_bind"]
+	require.NotNil(t, rule)
+	assert.True(t, rule.IsSequence())
+
+	assert.ElementsMatch(t, []eval.FieldValue{{Value: "/etc/shadow", Type: eval.ScalarValueType}}, rs.GetFieldValues("open.file.path"))
+	assert.True(t, rs.HasRulesForEventType("open"))
+	assert.True(t, rs.HasRulesForEventType("bind"))
+}
+
+func TestSequenceRuleLoading(t *testing.T) {
+	valid := shadowThenBind()
+
+	testPolicy := &PolicyDef{
+		Rules: []*RuleDefinition{
+			{
+				ID:       "valid",
+				Sequence: valid,
+			},
+			{
+				ID:         "expression_and_sequence",
+				Expression: `open.file.path == "/tmp/test"`,
+				Sequence:   valid,
+			},
+			{
+				ID: "single_step",
+				Sequence: &SequenceDefinition{
+					Steps:  valid.Steps[:1],
+					Key:    ProcessSequenceKey,
+					Window: time.Second,
+				},
+			},
+			{
+				ID: "no_window",
+				Sequence: &SequenceDefinition{
+					Steps: valid.Steps,
+					Key:   ProcessSequenceKey,
+				},
+			},
+			{
+				ID: "unknown_key",
+				Sequence: &SequenceDefinition{
+					Steps:  valid.Steps,
+					Key:    "host",
+					Window: time.Second,
+				},
+			},
+			{
+				ID: "invalid_step",
+				Sequence: &SequenceDefinition{
+					Steps: []*SequenceStepDefinition{
+						{Expression: `open.file.path == "/etc/shadow"`},
+						{Expression: `bind.addr.family ==`},
+					},
+					Key:    ProcessSequenceKey,
+					Window: time.Second,
+				},
+			},
+		},
+	}
+
+	rs, err := loadPolicy(t, testPolicy, PolicyLoaderOpts{})
+	require.NotNil(t, err)
+	require.Len(t, err.Errors, 5)
+	assert.ErrorContains(t, err.Errors[0], "rule `expression_and_sequence` error: only one of 'expression' and 'sequence' can be defined")
+	assert.ErrorContains(t, err.Errors[1], "rule `single_step` error: a sequence requires at least 2 steps")
+	assert.ErrorContains(t, err.Errors[2], "rule `no_window` error: a sequence requires a positive window")
+	assert.ErrorContains(t, err.Errors[3], "rule `unknown_key` error: unknown sequence key 'host'")
+	assert.ErrorContains(t, err.Errors[4], "rule `invalid_step` error: syntax error `step 2:")
+
+	assert.Contains(t, rs.rules, "valid")
+	assert.NotContains(t, rs.rules, "unknown_key")
+	assert.NotContains(t, rs.rules, "invalid_step")
+}
@@ -47,4 +47,10 @@ const (
 	sns                     = "sns"
 	sqs                     = "sqs"
 	functionURL             = "lambda-function-url"
+	kafka                   = "kafka"
+	msk                     = "msk"
+	stepFunctions           = "states"
+	firehose                = "firehose"
+	activeMQ                = "activemq"
+	rabbitMQ                = "rabbitmq"
 )
@@ -189,3 +189,56 @@ func (lp *LifecycleProcessor) initFromLambdaFunctionURLEvent(event events.Lambda
 	lp.addTag(tagFunctionTriggerEventSourceArn, fmt.Sprintf("arn:aws:lambda:%v:%v:url:%v", region, accountID, functionName))
 	lp.addTags(trigger.GetTagsFromLambdaFunctionURLRequest(event))
 }
+
+func (lp *LifecycleProcessor) initFromKafkaEvent(event events.KafkaEvent) {
+	if !lp.DetectLambdaLibrary() && lp.InferredSpansEnabled {
+		lp.GetInferredSpan().EnrichInferredSpanWithKafkaEvent(event)
+	}
+
+	lp.requestHandler.event = event
+	if strings.EqualFold(event.EventSource, "aws:kafka") {
+		lp.addTag(tagFunctionTriggerEventSource, msk)
+	} else {
+		lp.addTag(tagFunctionTriggerEventSource, kafka)
+	}
+	lp.addTag(tagFunctionTriggerEventSourceArn, trigger.ExtractKafkaEventARN(event))
+}
+
+// initFromStepFunctionEvent doesn't create an inferred span, the state machine
+// execution is traced on its own and the invocation is linked to it through
+// the extracted trace context.
+func (lp *LifecycleProcessor) initFromStepFunctionEvent(event events.StepFunctionPayload) {
+	lp.requestHandler.event = event
+	lp.addTag(tagFunctionTriggerEventSource, stepFunctions)
+	lp.addTag(tagFunctionTriggerEventSourceArn, trigger.ExtractStepFunctionEventARN(event))
+}
+
+func (lp *LifecycleProcessor) initFromKinesisFirehoseEvent(event events.KinesisFirehoseEvent) {
+	if !lp.DetectLambdaLibrary() && lp.InferredSpansEnabled {
+		lp.GetInferredSpan().EnrichInferredSpanWithKinesisFirehoseEvent(event)
+	}
+
+	lp.requestHandler.event = event
+	lp.addTag(tagFunctionTriggerEventSource, firehose)
+	lp.addTag(tagFunctionTriggerEventSourceArn, trigger.ExtractKinesisFirehoseEventARN(event))
+}
+
+func (lp *LifecycleProcessor) initFromActiveMQEvent(event events.ActiveMQEvent) {
+	if !lp.DetectLambdaLibrary() && lp.InferredSpansEnabled {
+		lp.GetInferredSpan().EnrichInferredSpanWithActiveMQEvent(event)
+	}
+
+	lp.requestHandler.event = event
+	lp.addTag(tagFunctionTriggerEventSource, activeMQ)
+	lp.addTag(tagFunctionTriggerEventSourceArn, trigger.ExtractActiveMQEventARN(event))
+}
+
+func (lp *LifecycleProcessor) initFromRabbitMQEvent(event events.RabbitMQEvent) {
+	if !lp.DetectLambdaLibrary() && lp.InferredSpansEnabled {
+		lp.GetInferredSpan().EnrichInferredSpanWithRabbitMQEvent(event)
+	}
+
+	lp.requestHandler.event = event
+	lp.addTag(tagFunctionTriggerEventSource, rabbitMQ)
+	lp.addTag(tagFunctionTriggerEventSourceArn, trigger.ExtractRabbitMQEventARN(event))
+}
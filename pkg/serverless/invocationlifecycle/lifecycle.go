@@ -230,6 +230,53 @@ func (lp *LifecycleProcessor) OnInvokeStart(startDetails *InvocationStartDetails
 		}
 		ev = event
 		lp.initFromLambdaFunctionURLEvent(event, region, account, resource)
+	case trigger.KafkaEvent:
+		var event events.KafkaEvent
+		if err := json.Unmarshal(payloadBytes, &event); err != nil {
+			log.Debugf("Failed to unmarshal %s event: %s", kafka, err)
+			break
+		}
+		ev = event
+		lp.initFromKafkaEvent(event)
+	case trigger.StepFunctionEvent:
+		var event events.StepFunctionEvent
+		if err := json.Unmarshal(payloadBytes, &event); err != nil {
+			log.Debugf("Failed to unmarshal %s event: %s", stepFunctions, err)
+			break
+		}
+		// The context object is either the whole payload or under the Payload key
+		if event.Payload.Execution.ID == "" {
+			if err := json.Unmarshal(payloadBytes, &event.Payload); err != nil {
+				log.Debugf("Failed to unmarshal %s event: %s", stepFunctions, err)
+				break
+			}
+		}
+		ev = event.Payload
+		lp.initFromStepFunctionEvent(event.Payload)
+	case trigger.KinesisFirehoseEvent:
+		var event events.KinesisFirehoseEvent
+		if err := json.Unmarshal(payloadBytes, &event); err != nil {
+			log.Debugf("Failed to unmarshal %s event: %s", firehose, err)
+			break
+		}
+		ev = event
+		lp.initFromKinesisFirehoseEvent(event)
+	case trigger.ActiveMQEvent:
+		var event events.ActiveMQEvent
+		if err := json.Unmarshal(payloadBytes, &event); err != nil {
+			log.Debugf("Failed to unmarshal %s event: %s", activeMQ, err)
+			break
+		}
+		ev = event
+		lp.initFromActiveMQEvent(event)
+	case trigger.RabbitMQEvent:
+		var event events.RabbitMQEvent
+		if err := json.Unmarshal(payloadBytes, &event); err != nil {
+			log.Debugf("Failed to unmarshal %s event: %s", rabbitMQ, err)
+			break
+		}
+		ev = event
+		lp.initFromRabbitMQEvent(event)
 	default:
 		log.Debug("Skipping adding trigger types and inferred spans as a non-supported payload was received.")
 	}
@@ -1160,9 +1160,208 @@ func TestTriggerTypesLifecycleEventForEventBridge(t *testing.T) {
 	}, testProcessor.GetTags())
 }
 
+func TestTriggerTypesLifecycleEventForMSK(t *testing.T) {
+	startDetails := &InvocationStartDetails{
+		InvokeEventRawPayload: getTriggerEventFromFile("msk.json"),
+		InvokedFunctionARN:    "arn:aws:lambda:us-east-1:123456789012:function:my-function",
+	}
+
+	testProcessor := &LifecycleProcessor{
+		DetectLambdaLibrary: func() bool { return false },
+		ProcessTrace:        func(*api.Payload) {},
+	}
+
+	testProcessor.OnInvokeStart(startDetails)
+	testProcessor.OnInvokeEnd(&InvocationEndDetails{
+		RequestID: "test-request-id",
+	})
+	assert.Equal(t, map[string]string{
+		"cold_start":                        "false",
+		"function_trigger.event_source_arn": "arn:aws:kafka:us-east-1:123456789012:cluster/vpc-2priv-2pub/751d2973-a626-431c-9d4e-d7975eb44dd7-2",
+		"request_id":                        "test-request-id",
+		"function_trigger.event_source":     "msk",
+	}, testProcessor.GetTags())
+}
+
+func TestTriggerTypesLifecycleEventForSelfManagedKafka(t *testing.T) {
+	startDetails := &InvocationStartDetails{
+		InvokeEventRawPayload: getTriggerEventFromFile("kafka-self-managed.json"),
+		InvokedFunctionARN:    "arn:aws:lambda:us-east-1:123456789012:function:my-function",
+	}
+
+	testProcessor := &LifecycleProcessor{
+		DetectLambdaLibrary: func() bool { return false },
+		ProcessTrace:        func(*api.Payload) {},
+	}
+
+	testProcessor.OnInvokeStart(startDetails)
+	testProcessor.OnInvokeEnd(&InvocationEndDetails{
+		RequestID: "test-request-id",
+	})
+	assert.Equal(t, map[string]string{
+		"cold_start":                    "false",
+		"request_id":                    "test-request-id",
+		"function_trigger.event_source": "kafka",
+	}, testProcessor.GetTags())
+}
+
+func TestTriggerTypesLifecycleEventForMSKWithDdContext(t *testing.T) {
+	startInvocationTime := time.Now()
+	duration := 1 * time.Second
+	endInvocationTime := startInvocationTime.Add(duration)
+
+	var tracePayload *api.Payload
+
+	startDetails := &InvocationStartDetails{
+		InvokeEventRawPayload: getTriggerEventFromFile("msk.json"),
+		InvokedFunctionARN:    "arn:aws:lambda:us-east-1:123456789012:function:my-function",
+		StartTime:             startInvocationTime,
+	}
+
+	testProcessor := &LifecycleProcessor{
+		DetectLambdaLibrary:  func() bool { return false },
+		ProcessTrace:         func(payload *api.Payload) { tracePayload = payload },
+		InferredSpansEnabled: true,
+	}
+
+	testProcessor.OnInvokeStart(startDetails)
+	testProcessor.OnInvokeEnd(&InvocationEndDetails{
+		RequestID: "test-request-id",
+		EndTime:   endInvocationTime,
+		IsError:   false,
+	})
+
+	spans := tracePayload.TracerPayload.Chunks[0].Spans
+	assert.Equal(t, 2, len(spans))
+	kafkaSpan := spans[1]
+	assert.Equal(t, "aws.kafka", kafkaSpan.Name)
+	// These IDs are decoded from the msk.json event sample's record headers
+	assert.Equal(t, uint64(2684756524522091840), kafkaSpan.TraceID)
+	assert.Equal(t, uint64(7431398482019833808), kafkaSpan.ParentID)
+}
+
+func TestTriggerTypesLifecycleEventForStepFunctions(t *testing.T) {
+	startDetails := &InvocationStartDetails{
+		InvokeEventRawPayload: getTriggerEventFromFile("step-functions.json"),
+		InvokedFunctionARN:    "arn:aws:lambda:us-east-1:123456789012:function:my-function",
+	}
+
+	testProcessor := &LifecycleProcessor{
+		DetectLambdaLibrary: func() bool { return false },
+		ProcessTrace:        func(*api.Payload) {},
+	}
+
+	testProcessor.OnInvokeStart(startDetails)
+	testProcessor.OnInvokeEnd(&InvocationEndDetails{
+		RequestID: "test-request-id",
+	})
+	assert.Equal(t, map[string]string{
+		"cold_start":                        "false",
+		"function_trigger.event_source_arn": "arn:aws:states:us-east-1:123456789012:stateMachine:OrderStateMachine",
+		"request_id":                        "test-request-id",
+		"function_trigger.event_source":     "states",
+	}, testProcessor.GetTags())
+}
+
+func TestTriggerTypesLifecycleEventForStepFunctionsTraceContext(t *testing.T) {
+	startDetails := &InvocationStartDetails{
+		InvokeEventRawPayload: getTriggerEventFromFile("step-functions.json"),
+		InvokedFunctionARN:    "arn:aws:lambda:us-east-1:123456789012:function:my-function",
+	}
+
+	testProcessor := &LifecycleProcessor{
+		DetectLambdaLibrary: func() bool { return false },
+		ProcessTrace:        func(*api.Payload) {},
+	}
+
+	testProcessor.OnInvokeStart(startDetails)
+	// The trace context is derived from the execution ARN, the state name and
+	// the state entered time of the context object
+	assert.Equal(t, uint64(3399033768845791875), testProcessor.GetExecutionInfo().TraceID)
+	assert.Equal(t, uint64(1953275354297693198), testProcessor.GetExecutionInfo().parentID)
+}
+
+func TestTriggerTypesLifecycleEventForFirehose(t *testing.T) {
+	startDetails := &InvocationStartDetails{
+		InvokeEventRawPayload: getTriggerEventFromFile("firehose.json"),
+		InvokedFunctionARN:    "arn:aws:lambda:us-east-1:123456789012:function:my-function",
+	}
+
+	testProcessor := &LifecycleProcessor{
+		DetectLambdaLibrary: func() bool { return false },
+		ProcessTrace:        func(*api.Payload) {},
+	}
+
+	testProcessor.OnInvokeStart(startDetails)
+	testProcessor.OnInvokeEnd(&InvocationEndDetails{
+		RequestID: "test-request-id",
+	})
+	assert.Equal(t, map[string]string{
+		"cold_start":                        "false",
+		"function_trigger.event_source_arn": "arn:aws:firehose:us-east-1:123456789012:deliverystream/my-delivery-stream",
+		"request_id":                        "test-request-id",
+		"function_trigger.event_source":     "firehose",
+	}, testProcessor.GetTags())
+}
+
+func TestTriggerTypesLifecycleEventForActiveMQ(t *testing.T) {
+	startDetails := &InvocationStartDetails{
+		InvokeEventRawPayload: getTriggerEventFromFile("activemq.json"),
+		InvokedFunctionARN:    "arn:aws:lambda:us-east-1:123456789012:function:my-function",
+	}
+
+	testProcessor := &LifecycleProcessor{
+		DetectLambdaLibrary: func() bool { return false },
+		ProcessTrace:        func(*api.Payload) {},
+	}
+
+	testProcessor.OnInvokeStart(startDetails)
+	testProcessor.OnInvokeEnd(&InvocationEndDetails{
+		RequestID: "test-request-id",
+	})
+	assert.Equal(t, map[string]string{
+		"cold_start":                        "false",
+		"function_trigger.event_source_arn": "arn:aws:mq:us-west-2:111122223333:broker:test:b-9bcfa592-423a-4942-879d-eb284b418fc8",
+		"request_id":                        "test-request-id",
+		"function_trigger.event_source":     "activemq",
+	}, testProcessor.GetTags())
+}
+
+func TestTriggerTypesLifecycleEventForRabbitMQ(t *testing.T) {
+	startDetails := &InvocationStartDetails{
+		InvokeEventRawPayload: getTriggerEventFromFile("rabbitmq.json"),
+		InvokedFunctionARN:    "arn:aws:lambda:us-east-1:123456789012:function:my-function",
+	}
+
+	testProcessor := &LifecycleProcessor{
+		DetectLambdaLibrary: func() bool { return false },
+		ProcessTrace:        func(*api.Payload) {},
+	}
+
+	testProcessor.OnInvokeStart(startDetails)
+	testProcessor.OnInvokeEnd(&InvocationEndDetails{
+		RequestID: "test-request-id",
+	})
+	assert.Equal(t, map[string]string{
+		"cold_start":                        "false",
+		"function_trigger.event_source_arn": "arn:aws:mq:us-west-2:111122223333:broker:pizzaBroker:b-9bcfa592-423a-4942-879d-eb284b418fc8",
+		"request_id":                        "test-request-id",
+		"function_trigger.event_source":     "rabbitmq",
+	}, testProcessor.GetTags())
+}
+
 // Helper function for reading test file
 func getEventFromFile(filename string) []byte {
-	event, err := os.ReadFile("../trace/testdata/event_samples/" + filename)
+	return readEventFile("../trace/testdata/event_samples/" + filename)
+}
+
+// Helper function for reading a test file shared with the trigger package
+func getTriggerEventFromFile(filename string) []byte {
+	return readEventFile("../trigger/testData/" + filename)
+}
+
+func readEventFile(path string) []byte {
+	event, err := os.ReadFile(path)
 	if err != nil {
 		panic(err)
 	}
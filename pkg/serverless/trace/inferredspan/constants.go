@@ -9,9 +9,11 @@ const (
 	// Below are used for inferred span tagging and enrichment
 	apiID            = "apiid"
 	apiName          = "apiname"
+	bootstrapServers = "bootstrap_servers"
 	bucketARN        = "bucket_arn"
 	bucketName       = "bucketname"
 	connectionID     = "connection_id"
+	destination      = "destination"
 	detailType       = "detail_type"
 	endpoint         = "endpoint"
 	eventID          = "event_id"
@@ -24,13 +26,16 @@ const (
 	httpProtocol     = "http.protocol"
 	httpSourceIP     = "http.source_ip"
 	httpUserAgent    = "http.user_agent"
+	invocationID     = "invocation_id"
 	messageDirection = "message_direction"
 	messageID        = "message_id"
 	metadataType     = "type"
 	objectKey        = "object_key"
 	objectSize       = "object_size"
 	objectETag       = "object_etag"
+	offset           = "offset"
 	operationName    = "operation_name"
+	partition        = "partition"
 	partitionKey     = "partition_key"
 	queueName        = "queuename"
 	receiptHandle    = "receipt_handle"
@@ -47,6 +52,7 @@ const (
 	tableName        = "tablename"
 	topicName        = "topicname"
 	topicARN         = "topic_arn"
+	virtualHost      = "virtual_host"
 
 	// Below are used for parsing and setting the event sources
 	sns = "sns"
@@ -359,6 +359,123 @@ func (inferredSpan *InferredSpan) EnrichInferredSpanWithDynamoDBEvent(eventPaylo
 	}
 }
 
+// EnrichInferredSpanWithKafkaEvent uses the parsed event
+// payload to enrich the current inferred span. It applies a
+// specific set of data to the span expected from an Amazon MSK
+// or a self-managed Apache Kafka event.
+func (inferredSpan *InferredSpan) EnrichInferredSpanWithKafkaEvent(eventPayload events.KafkaEvent) {
+	eventRecord, ok := eventPayload.FirstRecord()
+	if !ok {
+		return
+	}
+	topic := eventRecord.Topic
+	serviceName := DetermineServiceName(serviceMapping, topic, "lambda_kafka", "kafka")
+	inferredSpan.IsAsync = true
+	inferredSpan.Span.Name = "aws.kafka"
+	inferredSpan.Span.Service = serviceName
+	inferredSpan.Span.Start = eventRecord.Timestamp.UnixNano()
+	inferredSpan.Span.Resource = topic
+	inferredSpan.Span.Type = "web"
+	inferredSpan.Span.Meta = map[string]string{
+		operationName:    "aws.kafka",
+		resourceNames:    topic,
+		topicName:        topic,
+		partition:        strconv.FormatInt(eventRecord.Partition, 10),
+		offset:           strconv.FormatInt(eventRecord.Offset, 10),
+		bootstrapServers: eventPayload.BootstrapServers,
+	}
+
+	// Self-managed Kafka events have no ARN
+	if eventPayload.EventSourceArn != "" {
+		inferredSpan.Span.Meta[eventSourceArn] = eventPayload.EventSourceArn
+	}
+}
+
+// EnrichInferredSpanWithKinesisFirehoseEvent uses the parsed event
+// payload to enrich the current inferred span. It applies a
+// specific set of data to the span expected from a Kinesis Data
+// Firehose transformation event.
+func (inferredSpan *InferredSpan) EnrichInferredSpanWithKinesisFirehoseEvent(eventPayload events.KinesisFirehoseEvent) {
+	if len(eventPayload.Records) == 0 {
+		return
+	}
+	eventRecord := eventPayload.Records[0]
+	parts := strings.Split(eventPayload.DeliveryStreamArn, "/")
+	parsedStreamName := parts[len(parts)-1]
+	serviceName := DetermineServiceName(serviceMapping, parsedStreamName, "lambda_firehose", "firehose")
+	inferredSpan.IsAsync = true
+	inferredSpan.Span.Name = "aws.firehose"
+	inferredSpan.Span.Service = serviceName
+	inferredSpan.Span.Start = eventRecord.ApproximateArrivalTimestamp.UnixNano()
+	inferredSpan.Span.Resource = parsedStreamName
+	inferredSpan.Span.Type = "web"
+	inferredSpan.Span.Meta = map[string]string{
+		operationName:  "aws.firehose",
+		resourceNames:  parsedStreamName,
+		streamName:     parsedStreamName,
+		eventSourceArn: eventPayload.DeliveryStreamArn,
+		invocationID:   eventPayload.InvocationID,
+	}
+}
+
+// EnrichInferredSpanWithActiveMQEvent uses the parsed event
+// payload to enrich the current inferred span. It applies a
+// specific set of data to the span expected from an Amazon MQ
+// for ActiveMQ event.
+func (inferredSpan *InferredSpan) EnrichInferredSpanWithActiveMQEvent(eventPayload events.ActiveMQEvent) {
+	if len(eventPayload.Messages) == 0 {
+		return
+	}
+	message := eventPayload.Messages[0]
+	destinationName := message.Destination.PhysicalName
+	serviceName := DetermineServiceName(serviceMapping, destinationName, "lambda_mq", "mq")
+	inferredSpan.IsAsync = true
+	inferredSpan.Span.Name = "aws.mq"
+	inferredSpan.Span.Service = serviceName
+	inferredSpan.Span.Start = calculateStartTime(message.BrokerInTime)
+	inferredSpan.Span.Resource = destinationName
+	inferredSpan.Span.Type = "web"
+	inferredSpan.Span.Meta = map[string]string{
+		operationName:  "aws.mq",
+		resourceNames:  destinationName,
+		destination:    destinationName,
+		eventSourceArn: eventPayload.EventSourceArn,
+		messageID:      message.MessageID,
+	}
+}
+
+// EnrichInferredSpanWithRabbitMQEvent uses the parsed event
+// payload to enrich the current inferred span. It applies a
+// specific set of data to the span expected from an Amazon MQ
+// for RabbitMQ event.
+func (inferredSpan *InferredSpan) EnrichInferredSpanWithRabbitMQEvent(eventPayload events.RabbitMQEvent) {
+	queueKey, message, ok := eventPayload.FirstMessage()
+	if !ok {
+		return
+	}
+	// Messages are grouped by "<queue name>::<virtual host>"
+	parsedQueueName, parsedVirtualHost, _ := strings.Cut(queueKey, "::")
+	serviceName := DetermineServiceName(serviceMapping, parsedQueueName, "lambda_mq", "mq")
+	inferredSpan.IsAsync = true
+	inferredSpan.Span.Name = "aws.mq"
+	inferredSpan.Span.Service = serviceName
+	// RabbitMQ messages only have a timestamp with a second precision in a
+	// locale-dependent format, the invocation start time is used instead
+	inferredSpan.Span.Start = inferredSpan.CurrentInvocationStartTime.UnixNano()
+	inferredSpan.Span.Resource = parsedQueueName
+	inferredSpan.Span.Type = "web"
+	inferredSpan.Span.Meta = map[string]string{
+		operationName:  "aws.mq",
+		resourceNames:  parsedQueueName,
+		queueName:      parsedQueueName,
+		virtualHost:    parsedVirtualHost,
+		eventSourceArn: eventPayload.EventSourceArn,
+	}
+	if message.BasicProperties.MessageID != nil {
+		inferredSpan.Span.Meta[messageID] = *message.BasicProperties.MessageID
+	}
+}
+
 // CalculateStartTime converts AWS event timeEpochs to nanoseconds
 func calculateStartTime(epoch int64) int64 {
 	return epoch * 1e6
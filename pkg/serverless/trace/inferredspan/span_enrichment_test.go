@@ -19,7 +19,8 @@ import (
 )
 
 const (
-	dataFile = "../testdata/event_samples/"
+	dataFile        = "../testdata/event_samples/"
+	triggerDataFile = "../../trigger/testData/"
 )
 
 // TestGetServiceMapping checks if the function correctly parses the input string into a map.
@@ -1011,6 +1012,137 @@ func TestRemapsSpecificInferredSpanServiceNamesFromDynamoDBEvent(t *testing.T) {
 	assert.Equal(t, "dynamodb", span2.Service)
 }
 
+func TestEnrichInferredSpanWithKafkaEvent(t *testing.T) {
+	var kafkaRequest events.KafkaEvent
+	_ = json.Unmarshal(getTriggerEventFromFile("msk.json"), &kafkaRequest)
+	inferredSpan := mockInferredSpan()
+	inferredSpan.EnrichInferredSpanWithKafkaEvent(kafkaRequest)
+	span := inferredSpan.Span
+	assert.Equal(t, uint64(7353030974370088224), span.TraceID)
+	assert.Equal(t, uint64(8048964810003407541), span.SpanID)
+	assert.Equal(t, int64(1545084650987000000), span.Start)
+	assert.Equal(t, "kafka", span.Service)
+	assert.Equal(t, "aws.kafka", span.Name)
+	assert.Equal(t, "mytopic", span.Resource)
+	assert.Equal(t, "web", span.Type)
+	assert.Equal(t, "aws.kafka", span.Meta[operationName])
+	assert.Equal(t, "mytopic", span.Meta[resourceNames])
+	assert.Equal(t, "mytopic", span.Meta[topicName])
+	assert.Equal(t, "0", span.Meta[partition])
+	assert.Equal(t, "15", span.Meta[offset])
+	assert.Equal(t, "b-2.demo-cluster-1.a1bcde.c1.kafka.us-east-1.amazonaws.com:9092,b-1.demo-cluster-1.a1bcde.c1.kafka.us-east-1.amazonaws.com:9092", span.Meta[bootstrapServers])
+	assert.Equal(t, "arn:aws:kafka:us-east-1:123456789012:cluster/vpc-2priv-2pub/751d2973-a626-431c-9d4e-d7975eb44dd7-2", span.Meta[eventSourceArn])
+	assert.True(t, inferredSpan.IsAsync)
+}
+
+func TestEnrichInferredSpanWithSelfManagedKafkaEvent(t *testing.T) {
+	var kafkaRequest events.KafkaEvent
+	_ = json.Unmarshal(getTriggerEventFromFile("kafka-self-managed.json"), &kafkaRequest)
+	inferredSpan := mockInferredSpan()
+	inferredSpan.EnrichInferredSpanWithKafkaEvent(kafkaRequest)
+	span := inferredSpan.Span
+	assert.Equal(t, "orders", span.Resource)
+	assert.Equal(t, "1", span.Meta[partition])
+	assert.Equal(t, "42", span.Meta[offset])
+	assert.NotContains(t, span.Meta, eventSourceArn)
+	assert.True(t, inferredSpan.IsAsync)
+}
+
+func TestRemapsSpecificInferredSpanServiceNamesFromKafkaEvent(t *testing.T) {
+	// Store the original service mapping
+	origServiceMapping := GetServiceMapping()
+
+	// Clean up: Reset the service mapping to its original state after this test
+	defer func() {
+		SetServiceMapping(origServiceMapping)
+	}()
+	// Set up the service mapping
+	newServiceMapping := map[string]string{
+		"mytopic": "accepted-name",
+	}
+	SetServiceMapping(newServiceMapping)
+	// Load the original event
+	var kafkaRequest events.KafkaEvent
+	_ = json.Unmarshal(getTriggerEventFromFile("msk.json"), &kafkaRequest)
+
+	inferredSpan := mockInferredSpan()
+	inferredSpan.EnrichInferredSpanWithKafkaEvent(kafkaRequest)
+
+	span1 := inferredSpan.Span
+	assert.Equal(t, "aws.kafka", span1.Meta[operationName])
+	assert.Equal(t, "accepted-name", span1.Service)
+
+	// Load a self-managed event on another topic
+	var kafkaRequest2 events.KafkaEvent
+	_ = json.Unmarshal(getTriggerEventFromFile("kafka-self-managed.json"), &kafkaRequest2)
+
+	inferredSpan2 := mockInferredSpan()
+	inferredSpan2.EnrichInferredSpanWithKafkaEvent(kafkaRequest2)
+
+	span2 := inferredSpan2.Span
+	assert.Equal(t, "aws.kafka", span2.Meta[operationName])
+	assert.Equal(t, "kafka", span2.Service)
+}
+
+func TestEnrichInferredSpanWithKinesisFirehoseEvent(t *testing.T) {
+	var firehoseRequest events.KinesisFirehoseEvent
+	_ = json.Unmarshal(getTriggerEventFromFile("firehose.json"), &firehoseRequest)
+	inferredSpan := mockInferredSpan()
+	inferredSpan.EnrichInferredSpanWithKinesisFirehoseEvent(firehoseRequest)
+	span := inferredSpan.Span
+	assert.Equal(t, int64(1495072949453000000), span.Start)
+	assert.Equal(t, "firehose", span.Service)
+	assert.Equal(t, "aws.firehose", span.Name)
+	assert.Equal(t, "my-delivery-stream", span.Resource)
+	assert.Equal(t, "web", span.Type)
+	assert.Equal(t, "aws.firehose", span.Meta[operationName])
+	assert.Equal(t, "my-delivery-stream", span.Meta[resourceNames])
+	assert.Equal(t, "my-delivery-stream", span.Meta[streamName])
+	assert.Equal(t, "arn:aws:firehose:us-east-1:123456789012:deliverystream/my-delivery-stream", span.Meta[eventSourceArn])
+	assert.Equal(t, "invocationIdExample", span.Meta[invocationID])
+	assert.True(t, inferredSpan.IsAsync)
+}
+
+func TestEnrichInferredSpanWithActiveMQEvent(t *testing.T) {
+	var activeMQRequest events.ActiveMQEvent
+	_ = json.Unmarshal(getTriggerEventFromFile("activemq.json"), &activeMQRequest)
+	inferredSpan := mockInferredSpan()
+	inferredSpan.EnrichInferredSpanWithActiveMQEvent(activeMQRequest)
+	span := inferredSpan.Span
+	assert.Equal(t, int64(1598827811958000000), span.Start)
+	assert.Equal(t, "mq", span.Service)
+	assert.Equal(t, "aws.mq", span.Name)
+	assert.Equal(t, "testQueue", span.Resource)
+	assert.Equal(t, "web", span.Type)
+	assert.Equal(t, "aws.mq", span.Meta[operationName])
+	assert.Equal(t, "testQueue", span.Meta[resourceNames])
+	assert.Equal(t, "testQueue", span.Meta[destination])
+	assert.Equal(t, "arn:aws:mq:us-west-2:111122223333:broker:test:b-9bcfa592-423a-4942-879d-eb284b418fc8", span.Meta[eventSourceArn])
+	assert.Equal(t, "ID:b-9bcfa592-423a-4942-879d-eb284b418fc8-1.mq.us-west-2.amazonaws.com-37557-1234520418293-4:1:1:1:1", span.Meta[messageID])
+	assert.True(t, inferredSpan.IsAsync)
+}
+
+func TestEnrichInferredSpanWithRabbitMQEvent(t *testing.T) {
+	var rabbitMQRequest events.RabbitMQEvent
+	_ = json.Unmarshal(getTriggerEventFromFile("rabbitmq.json"), &rabbitMQRequest)
+	inferredSpan := mockInferredSpan()
+	inferredSpan.CurrentInvocationStartTime = time.Unix(1598827811, 0)
+	inferredSpan.EnrichInferredSpanWithRabbitMQEvent(rabbitMQRequest)
+	span := inferredSpan.Span
+	assert.Equal(t, int64(1598827811000000000), span.Start)
+	assert.Equal(t, "mq", span.Service)
+	assert.Equal(t, "aws.mq", span.Name)
+	assert.Equal(t, "pizzaQueue", span.Resource)
+	assert.Equal(t, "web", span.Type)
+	assert.Equal(t, "aws.mq", span.Meta[operationName])
+	assert.Equal(t, "pizzaQueue", span.Meta[resourceNames])
+	assert.Equal(t, "pizzaQueue", span.Meta[queueName])
+	assert.Equal(t, "/", span.Meta[virtualHost])
+	assert.Equal(t, "arn:aws:mq:us-west-2:111122223333:broker:pizzaBroker:b-9bcfa592-423a-4942-879d-eb284b418fc8", span.Meta[eventSourceArn])
+	assert.Equal(t, "a4f1d2c8-5e2b-4d69-8c3f-0b6a1e2d3c4f", span.Meta[messageID])
+	assert.True(t, inferredSpan.IsAsync)
+}
+
 func TestFormatISOStartTime(t *testing.T) {
 	isotime := "2022-01-31T14:13:41.637Z"
 	startTime := formatISOStartTime(isotime)
@@ -1029,6 +1161,12 @@ func getEventFromFile(filename string) []byte {
 	return event
 }
 
+// getTriggerEventFromFile reads a test file shared with the trigger package
+func getTriggerEventFromFile(filename string) []byte {
+	event, _ := os.ReadFile(triggerDataFile + filename)
+	return event
+}
+
 func mockInferredSpan() InferredSpan {
 	var inferredSpan InferredSpan
 	inferredSpan.Span = &pb.Span{}
@@ -7,7 +7,9 @@
 package propagation
 
 import (
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"regexp"
@@ -46,8 +48,14 @@ var (
 	errorUnsupportedValueType   = errors.New("Unsupported value type in _datadog payload")
 	errorUnsupportedTypeValue   = errors.New("Unsupported Type in _datadog payload")
 	errorCouldNotUnmarshal      = errors.New("Could not unmarshal the invocation event payload")
+	errorNoStepFunctionContext  = errors.New("No execution and state found in Step Functions context object")
 )
 
+// jmsPropertyNameReplacer decodes the JMS property names used by tracers to
+// propagate trace context. JMS property names must be valid Java identifiers,
+// so dashes and dots are encoded.
+var jmsPropertyNameReplacer = strings.NewReplacer("__dash__", "-", "__dot__", ".")
+
 // extractTraceContextfromAWSTraceHeader extracts trace context from the
 // AWSTraceHeader directly. Unlike the other carriers in this file, it should
 // not be passed to the tracer.Propagator, instead extracting context directly.
@@ -220,3 +228,85 @@ func rawPayloadCarrier(rawPayload []byte) (tracer.TextMapReader, error) {
 func headersCarrier(hdrs map[string]string) (tracer.TextMapReader, error) {
 	return tracer.TextMapCarrier(hdrs), nil
 }
+
+// kafkaRecordCarrier returns the tracer.TextMapReader used to extract trace
+// context from the headers of an events.KafkaRecord type.
+func kafkaRecordCarrier(record events.KafkaRecord) (tracer.TextMapReader, error) {
+	carrier := make(tracer.TextMapCarrier)
+	for _, header := range record.Headers {
+		for key, value := range header {
+			carrier[key] = string(value)
+		}
+	}
+	return carrier, nil
+}
+
+// activeMQMessageCarrier returns the tracer.TextMapReader used to extract
+// trace context from the properties of an events.ActiveMQMessage type.
+func activeMQMessageCarrier(message events.ActiveMQMessage) (tracer.TextMapReader, error) {
+	carrier := make(tracer.TextMapCarrier, len(message.Properties))
+	for key, value := range message.Properties {
+		carrier[jmsPropertyNameReplacer.Replace(key)] = value
+	}
+	return carrier, nil
+}
+
+// rabbitMQMessageCarrier returns the tracer.TextMapReader used to extract
+// trace context from the headers of an events.RabbitMQMessage type. String
+// header values are serialized by Lambda as an array of bytes.
+func rabbitMQMessageCarrier(message events.RabbitMQMessage) (tracer.TextMapReader, error) {
+	carrier := make(tracer.TextMapCarrier, len(message.BasicProperties.Headers))
+	for key, value := range message.BasicProperties.Headers {
+		switch v := value.(type) {
+		case string:
+			carrier[key] = v
+		case float64:
+			carrier[key] = strconv.FormatFloat(v, 'f', -1, 64)
+		case map[string]interface{}:
+			values, ok := v["bytes"].([]interface{})
+			if !ok {
+				continue
+			}
+			bytes := make([]byte, 0, len(values))
+			for _, b := range values {
+				if n, ok := b.(float64); ok {
+					bytes = append(bytes, byte(n))
+				}
+			}
+			carrier[key] = string(bytes)
+		}
+	}
+	return carrier, nil
+}
+
+// extractTraceContextFromStepFunctionContext derives the trace context from
+// the Step Functions context object. Like the Datadog Lambda libraries, the
+// trace ID is derived from the execution ARN and the parent ID from the
+// execution ARN, the state name and the state entered time, so that the
+// invocation is linked to the trace of the state machine execution. It should
+// not be passed to the tracer.Propagator, instead extracting context directly.
+func extractTraceContextFromStepFunctionContext(event events.StepFunctionPayload) (*TraceContext, error) {
+	executionID := event.Execution.ID
+	stateName := event.State.Name
+	stateEnteredTime := event.State.EnteredTime
+	if executionID == "" || stateName == "" || stateEnteredTime == "" {
+		return nil, errorNoStepFunctionContext
+	}
+
+	return &TraceContext{
+		TraceID:          deterministicSha256Hash(executionID, false),
+		ParentID:         deterministicSha256Hash(executionID+"#"+stateName+"#"+stateEnteredTime, true),
+		SamplingPriority: sampler.PriorityAutoKeep,
+	}, nil
+}
+
+// deterministicSha256Hash returns the higher or the lower 64 bits of the
+// SHA-256 hash of the given string, with the most significant bit cleared.
+func deterministicSha256Hash(s string, higher bool) uint64 {
+	sum := sha256.Sum256([]byte(s))
+	bits := sum[8:16]
+	if higher {
+		bits = sum[0:8]
+	}
+	return binary.BigEndian.Uint64(bits) & 0x7fffffffffffffff
+}
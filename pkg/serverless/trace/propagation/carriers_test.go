@@ -735,3 +735,159 @@ func TestHeadersCarrier(t *testing.T) {
 		})
 	}
 }
+
+func TestKafkaRecordCarrier(t *testing.T) {
+	testcases := []struct {
+		name   string
+		event  events.KafkaRecord
+		expMap map[string]string
+	}{
+		{
+			name:   "no-headers",
+			event:  events.KafkaRecord{},
+			expMap: headersMapEmpty,
+		},
+		{
+			name: "datadog-headers",
+			event: events.KafkaRecord{
+				Headers: []map[string]events.ByteArray{
+					{"x-datadog-trace-id": events.ByteArray(dd.trace.asStr)},
+					{"x-datadog-parent-id": events.ByteArray(dd.span.asStr)},
+					{"x-datadog-sampling-priority": events.ByteArray(dd.priority.asStr)},
+				},
+			},
+			expMap: map[string]string{
+				"x-datadog-trace-id":          dd.trace.asStr,
+				"x-datadog-parent-id":         dd.span.asStr,
+				"x-datadog-sampling-priority": dd.priority.asStr,
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tm, err := kafkaRecordCarrier(tc.event)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expMap, getMapFromCarrier(tm))
+		})
+	}
+}
+
+func TestActiveMQMessageCarrier(t *testing.T) {
+	testcases := []struct {
+		name   string
+		event  events.ActiveMQMessage
+		expMap map[string]string
+	}{
+		{
+			name:   "no-properties",
+			event:  events.ActiveMQMessage{},
+			expMap: headersMapEmpty,
+		},
+		{
+			name: "jms-encoded-properties",
+			event: events.ActiveMQMessage{
+				Properties: map[string]string{
+					"x__dash__datadog__dash__trace__dash__id":          dd.trace.asStr,
+					"x__dash__datadog__dash__parent__dash__id":         dd.span.asStr,
+					"x__dash__datadog__dash__sampling__dash__priority": dd.priority.asStr,
+					"index": "1",
+				},
+			},
+			expMap: map[string]string{
+				"x-datadog-trace-id":          dd.trace.asStr,
+				"x-datadog-parent-id":         dd.span.asStr,
+				"x-datadog-sampling-priority": dd.priority.asStr,
+				"index":                       "1",
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tm, err := activeMQMessageCarrier(tc.event)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expMap, getMapFromCarrier(tm))
+		})
+	}
+}
+
+func TestRabbitMQMessageCarrier(t *testing.T) {
+	toBytes := func(s string) map[string]interface{} {
+		values := make([]interface{}, 0, len(s))
+		for _, b := range []byte(s) {
+			values = append(values, float64(b))
+		}
+		return map[string]interface{}{"bytes": values}
+	}
+
+	testcases := []struct {
+		name   string
+		event  events.RabbitMQMessage
+		expMap map[string]string
+	}{
+		{
+			name:   "no-headers",
+			event:  events.RabbitMQMessage{},
+			expMap: headersMapEmpty,
+		},
+		{
+			name: "mixed-headers",
+			event: events.RabbitMQMessage{
+				BasicProperties: events.RabbitMQBasicProperties{
+					Headers: map[string]interface{}{
+						"x-datadog-trace-id":          toBytes(dd.trace.asStr),
+						"x-datadog-parent-id":         dd.span.asStr,
+						"x-datadog-sampling-priority": float64(2),
+						"unsupported":                 []interface{}{"a"},
+					},
+				},
+			},
+			expMap: map[string]string{
+				"x-datadog-trace-id":          dd.trace.asStr,
+				"x-datadog-parent-id":         dd.span.asStr,
+				"x-datadog-sampling-priority": dd.priority.asStr,
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tm, err := rabbitMQMessageCarrier(tc.event)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expMap, getMapFromCarrier(tm))
+		})
+	}
+}
+
+func TestExtractTraceContextFromStepFunctionContext(t *testing.T) {
+	event := events.StepFunctionPayload{
+		Execution: events.StepFunctionExecution{
+			ID: "arn:aws:states:us-east-1:123456789012:execution:OrderStateMachine:aa6c9316-713a-41d4-9c30-61131716744f",
+		},
+		State: events.StepFunctionState{
+			Name:        "ProcessOrder",
+			EnteredTime: "2024-01-10T12:00:00.100Z",
+		},
+	}
+
+	ctx, err := extractTraceContextFromStepFunctionContext(event)
+	assert.NoError(t, err)
+	assert.Equal(t, &TraceContext{
+		TraceID:          3399033768845791875,
+		ParentID:         1953275354297693198,
+		SamplingPriority: sampler.PriorityAutoKeep,
+	}, ctx)
+
+	// retries of the same state are linked to a different parent
+	event.State.EnteredTime = "2024-01-10T12:00:05.100Z"
+	retryCtx, err := extractTraceContextFromStepFunctionContext(event)
+	assert.NoError(t, err)
+	assert.Equal(t, ctx.TraceID, retryCtx.TraceID)
+	assert.NotEqual(t, ctx.ParentID, retryCtx.ParentID)
+
+	event.State.Name = ""
+	ctx, err = extractTraceContextFromStepFunctionContext(event)
+	assert.Nil(t, ctx)
+	assert.Equal(t, errorNoStepFunctionContext, err)
+}
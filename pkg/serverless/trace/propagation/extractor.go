@@ -33,6 +33,8 @@ var (
 	errorNoContextFound            = errors.New("No trace context found")
 	errorNoSQSRecordFound          = errors.New("No sqs message records found for trace context extraction")
 	errorNoSNSRecordFound          = errors.New("No sns message records found for trace context extraction")
+	errorNoKafkaRecordFound        = errors.New("No kafka records found for trace context extraction")
+	errorNoMQMessageFound          = errors.New("No mq messages found for trace context extraction")
 	errorNoTraceIDFound            = errors.New("No trace ID found")
 	errorNoParentIDFound           = errors.New("No parent ID found")
 )
@@ -112,6 +114,28 @@ func (e Extractor) extract(event interface{}) (*TraceContext, error) {
 		carrier, err = headersCarrier(ev.Headers)
 	case events.LambdaFunctionURLRequest:
 		carrier, err = headersCarrier(ev.Headers)
+	case events.KafkaEvent:
+		// look for context in just the first record
+		record, ok := ev.FirstRecord()
+		if !ok {
+			return nil, errorNoKafkaRecordFound
+		}
+		carrier, err = kafkaRecordCarrier(record)
+	case events.ActiveMQEvent:
+		// look for context in just the first message
+		if len(ev.Messages) == 0 {
+			return nil, errorNoMQMessageFound
+		}
+		carrier, err = activeMQMessageCarrier(ev.Messages[0])
+	case events.RabbitMQEvent:
+		// look for context in just the first message
+		_, message, ok := ev.FirstMessage()
+		if !ok {
+			return nil, errorNoMQMessageFound
+		}
+		carrier, err = rabbitMQMessageCarrier(message)
+	case events.StepFunctionPayload:
+		return extractTraceContextFromStepFunctionContext(ev)
 	default:
 		err = errorUnsupportedExtractionType
 	}
@@ -255,6 +255,106 @@ func TestExtractorExtract(t *testing.T) {
 			expNoErr: false,
 		},
 
+		// events.KafkaEvent
+		{
+			name: "kafka-event-no-records",
+			events: []interface{}{
+				events.KafkaEvent{},
+			},
+			expCtx:   nil,
+			expNoErr: false,
+		},
+		{
+			name: "kafka-event",
+			events: []interface{}{
+				events.KafkaEvent{
+					Records: map[string][]events.KafkaRecord{
+						"topic-0": {
+							{
+								Headers: []map[string]events.ByteArray{
+									{"x-datadog-trace-id": events.ByteArray(dd.trace.asStr)},
+									{"x-datadog-parent-id": events.ByteArray(dd.span.asStr)},
+									{"x-datadog-sampling-priority": events.ByteArray(dd.priority.asStr)},
+								},
+							},
+						},
+					},
+				},
+			},
+			expCtx:   ddTraceContext,
+			expNoErr: true,
+		},
+
+		// events.ActiveMQEvent
+		{
+			name: "activemq-event-no-messages",
+			events: []interface{}{
+				events.ActiveMQEvent{},
+			},
+			expCtx:   nil,
+			expNoErr: false,
+		},
+		{
+			name: "activemq-event",
+			events: []interface{}{
+				events.ActiveMQEvent{
+					Messages: []events.ActiveMQMessage{
+						{
+							Properties: map[string]string{
+								"x__dash__datadog__dash__trace__dash__id":          dd.trace.asStr,
+								"x__dash__datadog__dash__parent__dash__id":         dd.span.asStr,
+								"x__dash__datadog__dash__sampling__dash__priority": dd.priority.asStr,
+							},
+						},
+					},
+				},
+			},
+			expCtx:   ddTraceContext,
+			expNoErr: true,
+		},
+
+		// events.RabbitMQEvent
+		{
+			name: "rabbitmq-event-no-messages",
+			events: []interface{}{
+				events.RabbitMQEvent{},
+			},
+			expCtx:   nil,
+			expNoErr: false,
+		},
+		{
+			name: "rabbitmq-event",
+			events: []interface{}{
+				events.RabbitMQEvent{
+					MessagesByQueue: map[string][]events.RabbitMQMessage{
+						"queue::/": {
+							{
+								BasicProperties: events.RabbitMQBasicProperties{
+									Headers: map[string]interface{}{
+										"x-datadog-trace-id":          dd.trace.asStr,
+										"x-datadog-parent-id":         dd.span.asStr,
+										"x-datadog-sampling-priority": dd.priority.asStr,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expCtx:   ddTraceContext,
+			expNoErr: true,
+		},
+
+		// events.StepFunctionPayload
+		{
+			name: "step-function-no-context",
+			events: []interface{}{
+				events.StepFunctionPayload{},
+			},
+			expCtx:   nil,
+			expNoErr: false,
+		},
+
 		// events.SQSMessage
 		{
 			name: "unable-to-get-carrier",
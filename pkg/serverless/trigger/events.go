@@ -76,6 +76,21 @@ const (
 
 	// LambdaFunctionURLEvent describes an event from an HTTP lambda function URL invocation
 	LambdaFunctionURLEvent
+
+	// KafkaEvent describes an event from Amazon MSK or from a self-managed Apache Kafka cluster
+	KafkaEvent
+
+	// StepFunctionEvent describes a task invocation from a Step Functions state machine
+	StepFunctionEvent
+
+	// KinesisFirehoseEvent describes a data transformation event from Kinesis Data Firehose
+	KinesisFirehoseEvent
+
+	// ActiveMQEvent describes an event from an Amazon MQ for ActiveMQ broker
+	ActiveMQEvent
+
+	// RabbitMQEvent describes an event from an Amazon MQ for RabbitMQ broker
+	RabbitMQEvent
 )
 
 // eventParseFunc defines the signature of AWS event parsing functions
@@ -110,6 +125,11 @@ var (
 		{isAppSyncResolverEvent, AppSyncResolverEvent},
 		{isEventBridgeEvent, EventBridgeEvent},
 		{isLambdaFunctionURLEvent, LambdaFunctionURLEvent},
+		{isKafkaEvent, KafkaEvent},
+		{isStepFunctionEvent, StepFunctionEvent},
+		{isKinesisFirehoseEvent, KinesisFirehoseEvent},
+		{isActiveMQEvent, ActiveMQEvent},
+		{isRabbitMQEvent, RabbitMQEvent},
 		// Ultimately check this is a Kong API Gateway event as a last resort.
 		// This is because Kong API Gateway events are a subset of API Gateway events
 		// as of https://github.com/Kong/kong/blob/348c980/kong/plugins/aws-lambda/request-util.lua#L248-L260
@@ -270,6 +290,38 @@ func isLambdaFunctionURLEvent(event map[string]any) bool {
 	return strings.Contains(lambdaURL, "lambda-url")
 }
 
+func isKafkaEvent(event map[string]any) bool {
+	eventSource, ok := json.GetNestedValue(event, "eventsource").(string)
+	return ok && (eventSource == "aws:kafka" || eventSource == "selfmanagedkafka")
+}
+
+// Step Functions pass their context object either as the whole task input or
+// under the payload key, depending on how the Lambda task is defined.
+func isStepFunctionEvent(event map[string]any) bool {
+	if payload, ok := json.GetNestedValue(event, "payload").(map[string]any); ok {
+		event = payload
+	}
+	return json.GetNestedValue(event, "execution", "id") != nil &&
+		json.GetNestedValue(event, "state", "name") != nil &&
+		json.GetNestedValue(event, "statemachine", "id") != nil
+}
+
+func isKinesisFirehoseEvent(event map[string]any) bool {
+	return json.GetNestedValue(event, "invocationid") != nil &&
+		json.GetNestedValue(event, "deliverystreamarn") != nil &&
+		json.GetNestedValue(event, "records") != nil
+}
+
+func isActiveMQEvent(event map[string]any) bool {
+	eventSource, ok := json.GetNestedValue(event, "eventsource").(string)
+	return ok && eventSource == "aws:mq"
+}
+
+func isRabbitMQEvent(event map[string]any) bool {
+	eventSource, ok := json.GetNestedValue(event, "eventsource").(string)
+	return ok && eventSource == "aws:rmq"
+}
+
 func eventRecordsKeyExists(event map[string]any, key string) bool {
 	records, ok := json.GetNestedValue(event, "records").([]interface{})
 	if !ok {
@@ -336,6 +388,16 @@ func (et AWSEventType) String() string {
 		return "EventBridgeEvent"
 	case LambdaFunctionURLEvent:
 		return "LambdaFunctionURLEvent"
+	case KafkaEvent:
+		return "KafkaEvent"
+	case StepFunctionEvent:
+		return "StepFunctionEvent"
+	case KinesisFirehoseEvent:
+		return "KinesisFirehoseEvent"
+	case ActiveMQEvent:
+		return "ActiveMQEvent"
+	case RabbitMQEvent:
+		return "RabbitMQEvent"
 	default:
 		return fmt.Sprintf("EventType(%d)", et)
 	}
@@ -14,6 +14,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"encoding/base64"
+	"sort"
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
@@ -334,3 +335,164 @@ type LambdaFunctionURLRequestContextHTTPDescription struct {
 	SourceIP  string
 	UserAgent string
 }
+
+// KafkaEvent mirrors events.KafkaEvent type, removing unused fields. It is
+// used for both Amazon MSK and self-managed Apache Kafka events.
+type KafkaEvent struct {
+	EventSource      string
+	EventSourceArn   string
+	BootstrapServers string
+	Records          map[string][]KafkaRecord
+}
+
+// FirstRecord returns the first record of the partition whose key sorts
+// first, so that the same record is picked on every call.
+func (e KafkaEvent) FirstRecord() (KafkaRecord, bool) {
+	keys := make([]string, 0, len(e.Records))
+	for key, records := range e.Records {
+		if len(records) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return KafkaRecord{}, false
+	}
+	sort.Strings(keys)
+	return e.Records[keys[0]][0], true
+}
+
+// KafkaRecord mirrors events.KafkaRecord type, removing unused fields.
+type KafkaRecord struct {
+	Topic     string
+	Partition int64
+	Offset    int64
+	Timestamp events.MilliSecondsEpochTime
+	Headers   []map[string]ByteArray
+}
+
+// ByteArray is a byte slice serialized as a JSON array of numbers, as done for
+// the Kafka record headers and the RabbitMQ header values.
+type ByteArray []byte
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *ByteArray) UnmarshalJSON(data []byte) error {
+	var values []int
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	bytes := make([]byte, 0, len(values))
+	for _, v := range values {
+		bytes = append(bytes, byte(v))
+	}
+	*b = bytes
+	return nil
+}
+
+// StepFunctionEvent is used for unmarshalling the context object of a Step
+// Functions state machine when it is passed under the Payload key of the
+// Lambda task input. AWS Go libraries do not provide this type of event for
+// deserialization.
+type StepFunctionEvent struct {
+	Payload StepFunctionPayload
+}
+
+// StepFunctionPayload is the Step Functions context object, passed with
+// "Payload.$": "$$" in the Lambda task definition.
+type StepFunctionPayload struct {
+	Execution    StepFunctionExecution
+	State        StepFunctionState
+	StateMachine StepFunctionStateMachine
+}
+
+// StepFunctionExecution is the execution part of the Step Functions context
+// object.
+type StepFunctionExecution struct {
+	ID        string
+	Name      string
+	StartTime string
+}
+
+// StepFunctionState is the state part of the Step Functions context object.
+type StepFunctionState struct {
+	Name        string
+	EnteredTime string
+	RetryCount  int
+}
+
+// StepFunctionStateMachine is the state machine part of the Step Functions
+// context object.
+type StepFunctionStateMachine struct {
+	ID   string
+	Name string
+}
+
+// KinesisFirehoseEvent mirrors events.KinesisFirehoseEvent type, removing
+// unused fields.
+type KinesisFirehoseEvent struct {
+	InvocationID      string
+	DeliveryStreamArn string
+	Region            string
+	Records           []KinesisFirehoseEventRecord
+}
+
+// KinesisFirehoseEventRecord mirrors events.KinesisFirehoseEventRecord type,
+// removing unused fields.
+type KinesisFirehoseEventRecord struct {
+	RecordID                    string
+	ApproximateArrivalTimestamp events.MilliSecondsEpochTime
+}
+
+// ActiveMQEvent mirrors events.ActiveMQEvent type, removing unused fields.
+type ActiveMQEvent struct {
+	EventSource    string
+	EventSourceArn string
+	Messages       []ActiveMQMessage
+}
+
+// ActiveMQMessage mirrors events.ActiveMQMessage type, removing unused fields.
+type ActiveMQMessage struct {
+	MessageID    string
+	Destination  ActiveMQDestination
+	BrokerInTime int64
+	Properties   map[string]string
+}
+
+// ActiveMQDestination mirrors events.ActiveMQDestination type.
+type ActiveMQDestination struct {
+	PhysicalName string
+}
+
+// RabbitMQEvent mirrors events.RabbitMQEvent type, removing unused fields.
+type RabbitMQEvent struct {
+	EventSource     string
+	EventSourceArn  string
+	MessagesByQueue map[string][]RabbitMQMessage `json:"rmqMessagesByQueue"`
+}
+
+// FirstMessage returns the first message of the queue whose key sorts first,
+// along with that key, so that the same message is picked on every call.
+func (e RabbitMQEvent) FirstMessage() (string, RabbitMQMessage, bool) {
+	keys := make([]string, 0, len(e.MessagesByQueue))
+	for key, messages := range e.MessagesByQueue {
+		if len(messages) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return "", RabbitMQMessage{}, false
+	}
+	sort.Strings(keys)
+	return keys[0], e.MessagesByQueue[keys[0]][0], true
+}
+
+// RabbitMQMessage mirrors events.RabbitMQMessage type, removing unused fields.
+type RabbitMQMessage struct {
+	BasicProperties RabbitMQBasicProperties
+}
+
+// RabbitMQBasicProperties mirrors events.RabbitMQBasicProperties type,
+// removing unused fields.
+type RabbitMQBasicProperties struct {
+	Headers   map[string]interface{}
+	MessageID *string
+}
@@ -37,6 +37,12 @@ func TestEventPayloadParsing(t *testing.T) {
 		"sns.json":                            isSNSEvent,
 		"sqs.json":                            isSQSEvent,
 		"lambdaurl.json":                      isLambdaFunctionURLEvent,
+		"msk.json":                            isKafkaEvent,
+		"kafka-self-managed.json":             isKafkaEvent,
+		"step-functions.json":                 isStepFunctionEvent,
+		"firehose.json":                       isKinesisFirehoseEvent,
+		"activemq.json":                       isActiveMQEvent,
+		"rabbitmq.json":                       isRabbitMQEvent,
 	}
 	for testFile, testFunc := range testCases {
 		file, err := os.Open(fmt.Sprintf("%v/%v", testDir, testFile))
@@ -71,13 +77,22 @@ func TestEventPayloadParsingWrong(t *testing.T) {
 		"sns.json":                            isSNSEvent,
 		"sqs.json":                            isSQSEvent,
 		"lambdaurl.json":                      isLambdaFunctionURLEvent,
+		"msk.json":                            isKafkaEvent,
+		"step-functions.json":                 isStepFunctionEvent,
+		"firehose.json":                       isKinesisFirehoseEvent,
+		"activemq.json":                       isActiveMQEvent,
+		"rabbitmq.json":                       isRabbitMQEvent,
+	}
+	// other samples of the same event type as a test case
+	sameEventFiles := map[string]string{
+		"kafka-self-managed.json": "msk.json",
 	}
 	for correctTestFile, testFunc := range testCases {
 		wrongTestFiles, err := os.ReadDir(testDir)
 		assert.NoError(t, err)
 
 		for _, wrongTestFile := range wrongTestFiles {
-			if correctTestFile == wrongTestFile.Name() {
+			if correctTestFile == wrongTestFile.Name() || sameEventFiles[wrongTestFile.Name()] == correctTestFile {
 				// skip testing the correct case
 				continue
 			}
@@ -115,6 +130,12 @@ func TestGetEventType(t *testing.T) {
 		"sns.json":                            SNSEvent,
 		"sqs.json":                            SQSEvent,
 		"lambdaurl.json":                      LambdaFunctionURLEvent,
+		"msk.json":                            KafkaEvent,
+		"kafka-self-managed.json":             KafkaEvent,
+		"step-functions.json":                 StepFunctionEvent,
+		"firehose.json":                       KinesisFirehoseEvent,
+		"activemq.json":                       ActiveMQEvent,
+		"rabbitmq.json":                       RabbitMQEvent,
 	}
 
 	for testFile, expectedEventType := range testCases {
@@ -101,6 +101,33 @@ func ExtractSQSEventARN(event events.SQSEvent) string {
 	return event.Records[0].EventSourceARN
 }
 
+// ExtractKafkaEventARN returns an ARN from a KafkaEvent. Self-managed Apache
+// Kafka events have no ARN.
+func ExtractKafkaEventARN(event events.KafkaEvent) string {
+	return event.EventSourceArn
+}
+
+// ExtractStepFunctionEventARN returns the state machine ARN from a
+// StepFunctionPayload
+func ExtractStepFunctionEventARN(event events.StepFunctionPayload) string {
+	return event.StateMachine.ID
+}
+
+// ExtractKinesisFirehoseEventARN returns an ARN from a KinesisFirehoseEvent
+func ExtractKinesisFirehoseEventARN(event events.KinesisFirehoseEvent) string {
+	return event.DeliveryStreamArn
+}
+
+// ExtractActiveMQEventARN returns the broker ARN from an ActiveMQEvent
+func ExtractActiveMQEventARN(event events.ActiveMQEvent) string {
+	return event.EventSourceArn
+}
+
+// ExtractRabbitMQEventARN returns the broker ARN from a RabbitMQEvent
+func ExtractRabbitMQEventARN(event events.RabbitMQEvent) string {
+	return event.EventSourceArn
+}
+
 // GetTagsFromAPIGatewayEvent returns a tagset containing http tags from an
 // APIGatewayProxyRequest
 func GetTagsFromAPIGatewayEvent(event events.APIGatewayProxyRequest) map[string]string {
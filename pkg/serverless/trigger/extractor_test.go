@@ -228,6 +228,56 @@ func TestExtractSQSEventARN(t *testing.T) {
 	assert.Equal(t, "test-arn", arn)
 }
 
+func TestExtractKafkaEventARN(t *testing.T) {
+	event := events.KafkaEvent{
+		EventSource:    "aws:kafka",
+		EventSourceArn: "test-arn",
+	}
+
+	arn := ExtractKafkaEventARN(event)
+	assert.Equal(t, "test-arn", arn)
+
+	arn = ExtractKafkaEventARN(events.KafkaEvent{EventSource: "SelfManagedKafka"})
+	assert.Equal(t, "", arn)
+}
+
+func TestExtractStepFunctionEventARN(t *testing.T) {
+	event := events.StepFunctionPayload{
+		Execution:    events.StepFunctionExecution{ID: "execution-arn"},
+		StateMachine: events.StepFunctionStateMachine{ID: "test-arn"},
+	}
+
+	arn := ExtractStepFunctionEventARN(event)
+	assert.Equal(t, "test-arn", arn)
+}
+
+func TestExtractKinesisFirehoseEventARN(t *testing.T) {
+	event := events.KinesisFirehoseEvent{
+		DeliveryStreamArn: "test-arn",
+	}
+
+	arn := ExtractKinesisFirehoseEventARN(event)
+	assert.Equal(t, "test-arn", arn)
+}
+
+func TestExtractActiveMQEventARN(t *testing.T) {
+	event := events.ActiveMQEvent{
+		EventSourceArn: "test-arn",
+	}
+
+	arn := ExtractActiveMQEventARN(event)
+	assert.Equal(t, "test-arn", arn)
+}
+
+func TestExtractRabbitMQEventARN(t *testing.T) {
+	event := events.RabbitMQEvent{
+		EventSourceArn: "test-arn",
+	}
+
+	arn := ExtractRabbitMQEventARN(event)
+	assert.Equal(t, "test-arn", arn)
+}
+
 func TestExtractFunctionURLEventARN(t *testing.T) {
 	event := events.APIGatewayProxyRequest{
 		Headers: map[string]string{
@@ -0,0 +1,107 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package gosnmplib
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// rxBufSize is the size of the buffer used to read engine discovery reports,
+// it matches the default maximum message size advertised by gosnmp.
+const rxBufSize = 65535
+
+// ProbeEngineID sends an unauthenticated SNMPv3 engine discovery request
+// (RFC 3414 section 4) to the target of the given session and returns the
+// authoritative engine ID found in the report sent back by the agent.
+//
+// The discovery request carries neither a user name nor credentials, so it can
+// be used to cheaply find SNMPv3 agents before trying to authenticate. The
+// authoritative engine ID, boots and time are stored in the security parameters
+// of the session, so that its next request doesn't repeat the discovery. The
+// session must be connected and use SNMPv3.
+func ProbeEngineID(session *gosnmp.GoSNMP) (string, error) {
+	if session.Version != gosnmp.Version3 {
+		return "", fmt.Errorf("engine ID discovery requires SNMP v3, got %s", session.Version)
+	}
+	if session.Conn == nil {
+		return "", errors.New("session is not connected")
+	}
+
+	msgID := uint32(rand.Int31())
+	packet := &gosnmp.SnmpPacket{
+		Version:            gosnmp.Version3,
+		MsgFlags:           gosnmp.Reportable | gosnmp.NoAuthNoPriv,
+		SecurityModel:      gosnmp.UserSecurityModel,
+		SecurityParameters: &gosnmp.UsmSecurityParameters{},
+		PDUType:            gosnmp.GetRequest,
+		MsgID:              msgID,
+		RequestID:          msgID,
+	}
+	request, err := packet.MarshalMsg()
+	if err != nil {
+		return "", fmt.Errorf("error marshalling engine discovery request: %w", err)
+	}
+
+	buf := make([]byte, rxBufSize)
+	for attempt := 0; attempt <= session.Retries; attempt++ {
+		if err = session.Conn.SetDeadline(time.Now().Add(session.Timeout)); err != nil {
+			return "", err
+		}
+		if _, err = session.Conn.Write(request); err != nil {
+			continue
+		}
+
+		var n int
+		n, err = session.Conn.Read(buf)
+		if err != nil {
+			continue
+		}
+		var response *gosnmp.SnmpPacket
+		response, err = session.SnmpDecodePacket(buf[:n])
+		if err != nil {
+			continue
+		}
+		if response.MsgID != msgID {
+			err = fmt.Errorf("unexpected message ID %d in engine discovery report", response.MsgID)
+			continue
+		}
+		usm, ok := response.SecurityParameters.(*gosnmp.UsmSecurityParameters)
+		if !ok || usm.AuthoritativeEngineID == "" {
+			return "", errors.New("no authoritative engine ID in engine discovery report")
+		}
+		if err = seedSecurityParameters(session, usm); err != nil {
+			return "", fmt.Errorf("error storing the discovered engine ID: %w", err)
+		}
+		return usm.AuthoritativeEngineID, nil
+	}
+	return "", fmt.Errorf("engine discovery request failed: %w", err)
+}
+
+// seedSecurityParameters stores the authoritative engine parameters found by
+// the discovery in the security parameters of the session, like gosnmp does
+// after its own discovery, and localizes the keys for that engine.
+func seedSecurityParameters(session *gosnmp.GoSNMP, discovered *gosnmp.UsmSecurityParameters) error {
+	usm, ok := session.SecurityParameters.(*gosnmp.UsmSecurityParameters)
+	if !ok {
+		return fmt.Errorf("unsupported security parameters %T", session.SecurityParameters)
+	}
+	if usm.AuthoritativeEngineID != discovered.AuthoritativeEngineID {
+		usm.AuthoritativeEngineID = discovered.AuthoritativeEngineID
+		usm.SecretKey = nil
+		usm.PrivacyKey = nil
+	}
+	usm.AuthoritativeEngineBoots = discovered.AuthoritativeEngineBoots
+	usm.AuthoritativeEngineTime = discovered.AuthoritativeEngineTime
+	if session.ContextEngineID == "" {
+		session.ContextEngineID = discovered.AuthoritativeEngineID
+	}
+	return usm.InitSecurityKeys()
+}
@@ -0,0 +1,155 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package gosnmplib
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startFakeAgent answers the first SNMPv3 request it receives with an engine
+// discovery report carrying the given engine ID.
+func startFakeAgent(t *testing.T, engineID string) *net.UDPAddr {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 65535)
+		n, addr, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		decoder := &gosnmp.GoSNMP{
+			Version:            gosnmp.Version3,
+			SecurityModel:      gosnmp.UserSecurityModel,
+			MsgFlags:           gosnmp.NoAuthNoPriv,
+			SecurityParameters: &gosnmp.UsmSecurityParameters{UserName: "agent"},
+		}
+		request, err := decoder.SnmpDecodePacket(buf[:n])
+		if err != nil {
+			return
+		}
+		report := &gosnmp.SnmpPacket{
+			Version:       gosnmp.Version3,
+			MsgFlags:      gosnmp.NoAuthNoPriv,
+			SecurityModel: gosnmp.UserSecurityModel,
+			SecurityParameters: &gosnmp.UsmSecurityParameters{
+				AuthoritativeEngineID:    engineID,
+				AuthoritativeEngineBoots: 1,
+				AuthoritativeEngineTime:  100,
+			},
+			PDUType:   gosnmp.Report,
+			MsgID:     request.MsgID,
+			RequestID: request.RequestID,
+			Variables: []gosnmp.SnmpPDU{
+				// usmStatsUnknownEngineIDs
+				{Name: "1.3.6.1.6.3.15.1.1.4.0", Type: gosnmp.Counter32, Value: uint(1)},
+			},
+		}
+		response, err := report.MarshalMsg()
+		if err != nil {
+			return
+		}
+		conn.WriteToUDP(response, addr) //nolint:errcheck
+	}()
+
+	return conn.LocalAddr().(*net.UDPAddr)
+}
+
+func TestProbeEngineID(t *testing.T) {
+	addr := startFakeAgent(t, "\x80\x00\x1f\x88\x80\x12\x34")
+	session := &gosnmp.GoSNMP{
+		Target:             addr.IP.String(),
+		Port:               uint16(addr.Port),
+		Transport:          "udp",
+		Version:            gosnmp.Version3,
+		Timeout:            2 * time.Second,
+		SecurityModel:      gosnmp.UserSecurityModel,
+		MsgFlags:           gosnmp.NoAuthNoPriv,
+		SecurityParameters: &gosnmp.UsmSecurityParameters{UserName: "admin"},
+	}
+	require.NoError(t, session.Connect())
+	defer session.Conn.Close()
+
+	engineID, err := ProbeEngineID(session)
+	require.NoError(t, err)
+	assert.Equal(t, "\x80\x00\x1f\x88\x80\x12\x34", engineID)
+
+	// the session doesn't need to discover the engine again
+	usm := session.SecurityParameters.(*gosnmp.UsmSecurityParameters)
+	assert.Equal(t, "\x80\x00\x1f\x88\x80\x12\x34", usm.AuthoritativeEngineID)
+	assert.Equal(t, uint32(1), usm.AuthoritativeEngineBoots)
+	assert.Equal(t, uint32(100), usm.AuthoritativeEngineTime)
+	assert.Equal(t, "\x80\x00\x1f\x88\x80\x12\x34", session.ContextEngineID)
+}
+
+func TestProbeEngineIDLocalizesKeys(t *testing.T) {
+	addr := startFakeAgent(t, "\x80\x00\x1f\x88\x80\x12\x34")
+	session := &gosnmp.GoSNMP{
+		Target:        addr.IP.String(),
+		Port:          uint16(addr.Port),
+		Transport:     "udp",
+		Version:       gosnmp.Version3,
+		Timeout:       2 * time.Second,
+		SecurityModel: gosnmp.UserSecurityModel,
+		MsgFlags:      gosnmp.AuthPriv,
+		SecurityParameters: &gosnmp.UsmSecurityParameters{
+			UserName:                 "admin",
+			AuthenticationProtocol:   gosnmp.SHA,
+			AuthenticationPassphrase: "authpassword",
+			PrivacyProtocol:          gosnmp.AES,
+			PrivacyPassphrase:        "privpassword",
+		},
+	}
+	require.NoError(t, session.Connect())
+	defer session.Conn.Close()
+
+	_, err := ProbeEngineID(session)
+	require.NoError(t, err)
+
+	usm := session.SecurityParameters.(*gosnmp.UsmSecurityParameters)
+	assert.NotEmpty(t, usm.SecretKey)
+	assert.NotEmpty(t, usm.PrivacyKey)
+}
+
+func TestProbeEngineIDNoAgent(t *testing.T) {
+	// Reserve a port nobody answers on
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	defer conn.Close()
+	addr := conn.LocalAddr().(*net.UDPAddr)
+
+	session := &gosnmp.GoSNMP{
+		Target:             addr.IP.String(),
+		Port:               uint16(addr.Port),
+		Transport:          "udp",
+		Version:            gosnmp.Version3,
+		Timeout:            100 * time.Millisecond,
+		Retries:            1,
+		SecurityModel:      gosnmp.UserSecurityModel,
+		MsgFlags:           gosnmp.NoAuthNoPriv,
+		SecurityParameters: &gosnmp.UsmSecurityParameters{UserName: "admin"},
+	}
+	require.NoError(t, session.Connect())
+	defer session.Conn.Close()
+
+	_, err = ProbeEngineID(session)
+	assert.ErrorContains(t, err, "engine discovery request failed")
+}
+
+func TestProbeEngineIDRequiresV3(t *testing.T) {
+	_, err := ProbeEngineID(&gosnmp.GoSNMP{Version: gosnmp.Version2c})
+	assert.EqualError(t, err, "engine ID discovery requires SNMP v3, got 2c")
+
+	_, err = ProbeEngineID(&gosnmp.GoSNMP{Version: gosnmp.Version3})
+	assert.EqualError(t, err, "session is not connected")
+}
@@ -0,0 +1,120 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package gosnmplib
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+const (
+	// lldpRemManAddrIfSubtypeOID is a column of LLDP-MIB::lldpRemManAddrTable,
+	// the management address of the neighbour is part of the row index
+	lldpRemManAddrIfSubtypeOID = "1.0.8802.1.1.2.1.4.2.1.3"
+	// cdpCacheAddressTypeOID and cdpCacheAddressOID are columns of
+	// CISCO-CDP-MIB::cdpCacheTable
+	cdpCacheAddressTypeOID = "1.3.6.1.4.1.9.9.23.1.2.1.1.3"
+	cdpCacheAddressOID     = "1.3.6.1.4.1.9.9.23.1.2.1.1.4"
+
+	// lldpManAddrSubtypeIPv4 and lldpManAddrSubtypeIPv6 are the IANA address
+	// family numbers used by lldpRemManAddrSubtype
+	lldpManAddrSubtypeIPv4 = "1"
+	lldpManAddrSubtypeIPv6 = "2"
+	// cdpAddressTypeIP is the CiscoNetworkProtocol value for IP addresses
+	cdpAddressTypeIP = 1
+)
+
+// FetchNeighborAddresses returns the management addresses of the LLDP and CDP
+// neighbours of the device the session is connected to. Devices that don't
+// implement one of the MIBs are not considered an error, the returned list is
+// simply empty.
+func FetchNeighborAddresses(session *gosnmp.GoSNMP) ([]net.IP, error) {
+	var addresses []net.IP
+	seen := make(map[string]bool)
+	add := func(ip net.IP) {
+		if ip == nil || ip.IsUnspecified() || ip.IsLoopback() || seen[ip.String()] {
+			return
+		}
+		seen[ip.String()] = true
+		addresses = append(addresses, ip)
+	}
+
+	lldpPDUs, err := walkAll(session, lldpRemManAddrIfSubtypeOID)
+	if err != nil {
+		return nil, fmt.Errorf("error walking LLDP remote management addresses: %w", err)
+	}
+	for _, pdu := range lldpPDUs {
+		add(parseLLDPRemManAddrIndex(strings.TrimPrefix(strings.TrimPrefix(pdu.Name, "."), lldpRemManAddrIfSubtypeOID+".")))
+	}
+
+	cdpTypePDUs, err := walkAll(session, cdpCacheAddressTypeOID)
+	if err != nil {
+		return nil, fmt.Errorf("error walking CDP cache address types: %w", err)
+	}
+	ipIndexes := make(map[string]bool, len(cdpTypePDUs))
+	for _, pdu := range cdpTypePDUs {
+		if gosnmp.ToBigInt(pdu.Value).Int64() == cdpAddressTypeIP {
+			ipIndexes[strings.TrimPrefix(strings.TrimPrefix(pdu.Name, "."), cdpCacheAddressTypeOID+".")] = true
+		}
+	}
+	cdpAddressPDUs, err := walkAll(session, cdpCacheAddressOID)
+	if err != nil {
+		return nil, fmt.Errorf("error walking CDP cache addresses: %w", err)
+	}
+	for _, pdu := range cdpAddressPDUs {
+		index := strings.TrimPrefix(strings.TrimPrefix(pdu.Name, "."), cdpCacheAddressOID+".")
+		value, ok := pdu.Value.([]byte)
+		if !ok || !ipIndexes[index] || len(value) != net.IPv4len {
+			continue
+		}
+		add(net.IP(value))
+	}
+
+	return addresses, nil
+}
+
+// parseLLDPRemManAddrIndex extracts the management address from an index of
+// lldpRemManAddrTable. The index is made of:
+//
+//	1 lldpRemTimeMark
+//	1 lldpRemLocalPortNum
+//	1 lldpRemIndex
+//	1 lldpRemManAddrSubtype (1 for IPv4, 2 for IPv6)
+//	5|17 lldpRemManAddr, prefixed by its length (4 for IPv4 and 16 for IPv6)
+func parseLLDPRemManAddrIndex(index string) net.IP {
+	indexElems := strings.Split(index, ".")
+	if len(indexElems) < 5 {
+		return nil
+	}
+	subtype := indexElems[3]
+	length, err := strconv.Atoi(indexElems[4])
+	if err != nil || len(indexElems) != 5+length {
+		return nil
+	}
+	if !(subtype == lldpManAddrSubtypeIPv4 && length == net.IPv4len) && !(subtype == lldpManAddrSubtypeIPv6 && length == net.IPv6len) {
+		return nil
+	}
+	ip := make(net.IP, 0, length)
+	for _, elem := range indexElems[5:] {
+		b, err := strconv.ParseUint(elem, 10, 8)
+		if err != nil {
+			return nil
+		}
+		ip = append(ip, byte(b))
+	}
+	return ip
+}
+
+func walkAll(session *gosnmp.GoSNMP, rootOID string) ([]gosnmp.SnmpPDU, error) {
+	if session.Version == gosnmp.Version1 {
+		return session.WalkAll(rootOID)
+	}
+	return session.BulkWalkAll(rootOID)
+}
@@ -0,0 +1,34 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package gosnmplib
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLLDPRemManAddrIndex(t *testing.T) {
+	for _, tc := range []struct {
+		index    string
+		expected net.IP
+	}{
+		{"0.5.1.1.4.10.0.0.2", net.IPv4(10, 0, 0, 2).To4()},
+		{"0.7.3.2.16.254.128.0.0.0.0.0.0.0.0.0.0.0.0.0.1", net.ParseIP("fe80::1")},
+		// MAC address subtype
+		{"0.5.1.6.6.0.28.115.1.2.3", nil},
+		// length doesn't match the subtype
+		{"0.5.1.1.6.10.0.0.2.0.0", nil},
+		// truncated index
+		{"0.5.1.1.4.10.0", nil},
+		{"0.5.1", nil},
+		// invalid byte
+		{"0.5.1.1.4.10.0.0.256", nil},
+	} {
+		assert.Equal(t, tc.expected, parseLLDPRemManAddrIndex(tc.index), tc.index)
+	}
+}
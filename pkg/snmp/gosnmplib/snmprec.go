@@ -0,0 +1,120 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package gosnmplib
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// ParseSnmprec reads a walk recorded in the snmpsim `.snmprec` format
+// (`<oid>|<type>|<value>`, one PDU per line) and returns it as a list of PDUs.
+//
+// The numeric type tags follow the BER type numbers; a trailing `x` means the
+// value is hex encoded. snmpsim variation modules (e.g. `2:delay`) and
+// entries with unsupported types are skipped.
+func ParseSnmprec(r io.Reader) ([]gosnmp.SnmpPDU, error) {
+	var pdus []gosnmp.SnmpPDU
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "|", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("line %d: expected `oid|type|value`, got %q", lineNum, line)
+		}
+		pdu, ok, err := snmprecPDU(parts[0], parts[1], parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		if ok {
+			pdus = append(pdus, pdu)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return pdus, nil
+}
+
+func snmprecPDU(oid string, tag string, rawValue string) (gosnmp.SnmpPDU, bool, error) {
+	if strings.Contains(tag, ":") {
+		// variation modules change the value at runtime, there is nothing static to read
+		return gosnmp.SnmpPDU{}, false, nil
+	}
+	hexEncoded := strings.HasSuffix(tag, "x")
+	tag = strings.TrimSuffix(strings.TrimSuffix(tag, "x"), "e")
+	berType, err := strconv.Atoi(tag)
+	if err != nil {
+		return gosnmp.SnmpPDU{}, false, fmt.Errorf("invalid type %q for OID %s", tag, oid)
+	}
+	value := []byte(rawValue)
+	if hexEncoded {
+		value, err = hex.DecodeString(rawValue)
+		if err != nil {
+			return gosnmp.SnmpPDU{}, false, fmt.Errorf("invalid hex value for OID %s: %w", oid, err)
+		}
+	}
+	pdu := gosnmp.SnmpPDU{
+		Name: strings.TrimLeft(oid, "."),
+		Type: gosnmp.Asn1BER(berType),
+	}
+	switch pdu.Type {
+	case gosnmp.OctetString, gosnmp.Opaque:
+		pdu.Value = value
+	case gosnmp.ObjectIdentifier:
+		pdu.Value = string(value)
+	case gosnmp.IPAddress:
+		pdu.Value, err = snmprecIPAddress(value, hexEncoded)
+	case gosnmp.Integer:
+		pdu.Value, err = strconv.Atoi(string(value))
+	case gosnmp.Counter32, gosnmp.Gauge32:
+		var v uint64
+		v, err = strconv.ParseUint(string(value), 10, 32)
+		pdu.Value = uint(v)
+	case gosnmp.TimeTicks:
+		var v uint64
+		v, err = strconv.ParseUint(string(value), 10, 32)
+		pdu.Value = uint32(v)
+	case gosnmp.Counter64:
+		pdu.Value, err = strconv.ParseUint(string(value), 10, 64)
+	case gosnmp.Null:
+		pdu.Value = nil
+	default:
+		return gosnmp.SnmpPDU{}, false, nil
+	}
+	if err != nil {
+		return gosnmp.SnmpPDU{}, false, fmt.Errorf("invalid %s value for OID %s: %w", pdu.Type, oid, err)
+	}
+	return pdu, true, nil
+}
+
+// snmprecIPAddress returns an IpAddress value as a dotted quad, as gosnmp
+// decodes them. Hex encoded values hold the 4 bytes of the address.
+func snmprecIPAddress(value []byte, hexEncoded bool) (string, error) {
+	if hexEncoded {
+		if len(value) != net.IPv4len {
+			return "", fmt.Errorf("expected %d bytes, got %d", net.IPv4len, len(value))
+		}
+		return net.IP(value).String(), nil
+	}
+	if ip := net.ParseIP(string(value)); ip == nil || ip.To4() == nil {
+		return "", fmt.Errorf("%q is not an IPv4 address", value)
+	}
+	return string(value), nil
+}
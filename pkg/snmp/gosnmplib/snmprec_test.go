@@ -0,0 +1,63 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package gosnmplib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSnmprec(t *testing.T) {
+	input := `1.3.6.1.2.1.1.2.0|6|1.3.6.1.4.1.9.1.241
+1.3.6.1.2.1.1.3.0|67|1727368662
+1.3.6.1.2.1.1.5.0|4|catalyst-6000.example
+1.3.6.1.2.1.31.1.1.1.1.8|4x|4769312f302f36
+# a comment
+
+1.3.6.1.2.1.2.2.1.1.1|2|-1
+1.3.6.1.2.1.2.2.1.10.1|65|42
+1.3.6.1.2.1.31.1.1.1.6.1|70|18446744073709551615
+1.3.6.1.2.1.4.20.1.1.10.0.0.1|64|10.0.0.1
+1.3.6.1.2.1.4.20.1.1.192.168.1.254|64x|c0a801fe
+1.3.6.1.2.1.1.8.0|2:delay|value=1,wait=10
+`
+	pdus, err := ParseSnmprec(strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, []gosnmp.SnmpPDU{
+		{Name: "1.3.6.1.2.1.1.2.0", Type: gosnmp.ObjectIdentifier, Value: "1.3.6.1.4.1.9.1.241"},
+		{Name: "1.3.6.1.2.1.1.3.0", Type: gosnmp.TimeTicks, Value: uint32(1727368662)},
+		{Name: "1.3.6.1.2.1.1.5.0", Type: gosnmp.OctetString, Value: []byte("catalyst-6000.example")},
+		{Name: "1.3.6.1.2.1.31.1.1.1.1.8", Type: gosnmp.OctetString, Value: []byte("Gi1/0/6")},
+		{Name: "1.3.6.1.2.1.2.2.1.1.1", Type: gosnmp.Integer, Value: -1},
+		{Name: "1.3.6.1.2.1.2.2.1.10.1", Type: gosnmp.Counter32, Value: uint(42)},
+		{Name: "1.3.6.1.2.1.31.1.1.1.6.1", Type: gosnmp.Counter64, Value: uint64(18446744073709551615)},
+		{Name: "1.3.6.1.2.1.4.20.1.1.10.0.0.1", Type: gosnmp.IPAddress, Value: "10.0.0.1"},
+		{Name: "1.3.6.1.2.1.4.20.1.1.192.168.1.254", Type: gosnmp.IPAddress, Value: "192.168.1.254"},
+	}, pdus)
+}
+
+func TestParseSnmprecErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		input string
+	}{
+		{"missing fields", "1.3.6.1.2.1.1.5.0|4"},
+		{"bad type", "1.3.6.1.2.1.1.5.0|foo|bar"},
+		{"bad hex", "1.3.6.1.2.1.1.5.0|4x|zz"},
+		{"bad integer", "1.3.6.1.2.1.1.7.0|2|seven"},
+		{"bad ip address", "1.3.6.1.2.1.4.20.1.1.10.0.0.1|64|10.0.0"},
+		{"bad hex ip address", "1.3.6.1.2.1.4.20.1.1.10.0.0.1|64x|0a0000"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseSnmprec(strings.NewReader(tc.input))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -25,9 +25,10 @@ import (
 )
 
 const (
-	defaultPort    = 161
-	defaultTimeout = 5
-	defaultRetries = 3
+	defaultPort          = 161
+	defaultTimeout       = 5
+	defaultRetries       = 3
+	defaultCrawlMaxDepth = 3
 )
 
 // ListenerConfig holds global configuration for SNMP discovery
@@ -82,6 +83,12 @@ type Config struct {
 
 	PingConfig snmpintegration.PingConfig `mapstructure:"ping"`
 
+	// ProbeEngineID sends an unauthenticated engine ID discovery request to
+	// find SNMPv3 devices before trying credentials
+	ProbeEngineID bool `mapstructure:"probe_engine_id"`
+
+	Crawl CrawlConfig `mapstructure:"crawl"`
+
 	// Legacy
 	NetworkLegacy      string `mapstructure:"network"`
 	VersionLegacy      string `mapstructure:"version"`
@@ -92,6 +99,16 @@ type Config struct {
 	PrivProtocolLegacy string `mapstructure:"privacy_protocol"`
 }
 
+// CrawlConfig holds configuration for discovering devices by following the
+// LLDP and CDP neighbours of seed devices
+type CrawlConfig struct {
+	Enabled         bool     `mapstructure:"enabled"`
+	Seeds           []string `mapstructure:"seeds"`
+	MaxDepthConfig  *int     `mapstructure:"max_depth"`
+	MaxDepth        int
+	AllowedNetworks []string `mapstructure:"allowed_networks"`
+}
+
 type intOrBoolPtr interface {
 	*int | *bool
 }
@@ -150,6 +167,12 @@ func NewListenerConfig() (ListenerConfig, error) {
 		if config.Retries == 0 {
 			config.Retries = defaultRetries
 		}
+		// A max depth of 0 only checks the seeds
+		if config.Crawl.MaxDepthConfig != nil {
+			config.Crawl.MaxDepth = *config.Crawl.MaxDepthConfig
+		} else {
+			config.Crawl.MaxDepth = defaultCrawlMaxDepth
+		}
 		if config.CollectDeviceMetadataConfig != nil {
 			config.CollectDeviceMetadata = *config.CollectDeviceMetadataConfig
 		} else {
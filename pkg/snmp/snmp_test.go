@@ -466,6 +466,67 @@ network_devices:
 	assert.Equal(t, "127.2.0.0/30", legacyConfig.Network)
 }
 
+func Test_CrawlConfig(t *testing.T) {
+	config.Datadog().SetConfigType("yaml")
+	err := config.Datadog().ReadConfig(strings.NewReader(`
+network_devices:
+  autodiscovery:
+    configs:
+     - user: admin
+       probe_engine_id: true
+       crawl:
+         enabled: true
+         seeds:
+           - 10.0.0.1
+           - 10.0.1.1
+         allowed_networks:
+           - 10.0.0.0/16
+     - community_string: public
+       network_address: 127.1.0.0/30
+       crawl:
+         enabled: true
+         seeds:
+           - 127.1.0.1
+         max_depth: 1
+     - community_string: public
+       network_address: 127.2.0.0/30
+       crawl:
+         enabled: true
+         seeds:
+           - 127.2.0.1
+         max_depth: 0
+`))
+	assert.NoError(t, err)
+
+	conf, err := NewListenerConfig()
+	assert.NoError(t, err)
+
+	one, zero := 1, 0
+	assert.True(t, conf.Configs[0].ProbeEngineID)
+	assert.Equal(t, CrawlConfig{
+		Enabled:         true,
+		Seeds:           []string{"10.0.0.1", "10.0.1.1"},
+		MaxDepth:        3,
+		AllowedNetworks: []string{"10.0.0.0/16"},
+	}, conf.Configs[0].Crawl)
+
+	assert.False(t, conf.Configs[1].ProbeEngineID)
+	assert.Equal(t, CrawlConfig{
+		Enabled:        true,
+		Seeds:          []string{"127.1.0.1"},
+		MaxDepthConfig: &one,
+		MaxDepth:       1,
+	}, conf.Configs[1].Crawl)
+
+	// only the seeds are checked
+	assert.Equal(t, CrawlConfig{
+		Enabled:        true,
+		Seeds:          []string{"127.2.0.1"},
+		MaxDepthConfig: &zero,
+		MaxDepth:       0,
+	}, conf.Configs[2].Crawl)
+}
+
 func Test_NamespaceConfig(t *testing.T) {
 	// Default Namespace
 	config.Datadog().SetConfigType("yaml")
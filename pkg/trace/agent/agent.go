@@ -55,6 +55,23 @@ const (
 	tagDecisionMaker = "_dd.p.dm"
 )
 
+// Names of the samplers reported in the trace stream as having decided to keep or drop a trace.
+const (
+	samplerRare          = "rare"
+	samplerProbabilistic = "probabilistic"
+	samplerErrors        = "errors"
+	samplerPriority      = "priority"
+	samplerNoPriority    = "no_priority"
+	samplerUserDrop      = "user_drop"
+)
+
+// Reasons reported in the trace stream for the chunks dropped before sampling.
+const (
+	dropReasonInvalid         = "invalid"
+	dropReasonIgnoreResources = "ignore_resources"
+	dropReasonTagFilters      = "tag_filters"
+)
+
 // TraceWriter provides a way to write trace chunks
 type TraceWriter interface {
 	// Stop stops the TraceWriter and attempts to flush whatever is left in the senders buffers.
@@ -96,6 +113,7 @@ type Agent struct {
 	RemoteConfigHandler   *remoteconfighandler.RemoteConfigHandler
 	TelemetryCollector    telemetry.TelemetryCollector
 	DebugServer           *api.DebugServer
+	TraceStream           *api.TraceStream
 	Statsd                statsd.ClientInterface
 	Timing                timing.Reporter
 
@@ -158,6 +176,7 @@ func NewAgent(ctx context.Context, conf *config.AgentConfig, telemetryCollector
 		conf:                  conf,
 		ctx:                   ctx,
 		DebugServer:           api.NewDebugServer(conf),
+		TraceStream:           api.NewTraceStream(),
 		Statsd:                statsd,
 		Timing:                timing,
 	}
@@ -320,6 +339,7 @@ func (a *Agent) Process(p *api.Payload) {
 		if err != nil {
 			log.Debugf("Dropping invalid trace: %s", err)
 			ts.SpansDropped.Add(tracen)
+			a.streamDroppedChunk(now, p.TracerPayload.Env, chunk, traceutil.GetRoot(chunk.Spans), dropReasonInvalid)
 			p.RemoveChunk(i)
 			continue
 		}
@@ -331,6 +351,7 @@ func (a *Agent) Process(p *api.Payload) {
 			log.Debugf("Trace rejected by ignore resources rules. root: %v", root)
 			ts.TracesFiltered.Inc()
 			ts.SpansFiltered.Add(tracen)
+			a.streamDroppedChunk(now, p.TracerPayload.Env, chunk, root, dropReasonIgnoreResources)
 			p.RemoveChunk(i)
 			continue
 		}
@@ -339,10 +360,20 @@ func (a *Agent) Process(p *api.Payload) {
 			log.Debugf("Trace rejected as it fails to meet tag requirements. root: %v", root)
 			ts.TracesFiltered.Inc()
 			ts.SpansFiltered.Add(tracen)
+			a.streamDroppedChunk(now, p.TracerPayload.Env, chunk, root, dropReasonTagFilters)
 			p.RemoveChunk(i)
 			continue
 		}
 
+		// Keep the resources as received to report them in the trace stream.
+		var rawResources []string
+		if a.TraceStream.Enabled() {
+			rawResources = make([]string, len(chunk.Spans))
+			for j, span := range chunk.Spans {
+				rawResources[j] = span.Resource
+			}
+		}
+
 		// Extra sanitization steps of the trace.
 		for _, span := range chunk.Spans {
 			for k, v := range a.conf.GlobalTags {
@@ -377,7 +408,18 @@ func (a *Agent) Process(p *api.Payload) {
 			statsInput.Traces = append(statsInput.Traces, *pt.Clone())
 		}
 
-		keep, numEvents := a.sample(now, ts, pt)
+		var streamed *api.StreamedChunk
+		if rawResources != nil {
+			// spans may be removed by sampling, capture them now
+			streamed = newStreamedChunk(now, pt, rawResources)
+		}
+		keep, numEvents, decider := a.sample(now, ts, pt)
+		if streamed != nil {
+			streamed.Priority = pt.TraceChunk.Priority
+			streamed.Kept = keep
+			streamed.Sampler = decider
+			a.TraceStream.Publish(streamed)
+		}
 		if !keep && len(pt.TraceChunk.Spans) == 0 {
 			// The entire trace was dropped and no spans were kept.
 			p.RemoveChunk(i)
@@ -546,12 +588,13 @@ func (a *Agent) ProcessStats(in *pb.ClientStatsPayload, lang, tracerVersion stri
 	a.ClientStatsAggregator.In <- a.processStats(in, lang, tracerVersion)
 }
 
-// sample performs all sampling on the processedTrace modifying it as needed and returning if the trace should be kept and the number of events in the trace
-func (a *Agent) sample(now time.Time, ts *info.TagStats, pt *traceutil.ProcessedTrace) (keep bool, numEvents int) {
+// sample performs all sampling on the processedTrace modifying it as needed and returning if the trace should be kept,
+// the number of events in the trace and the name of the sampler which decided to keep or drop it.
+func (a *Agent) sample(now time.Time, ts *info.TagStats, pt *traceutil.ProcessedTrace) (keep bool, numEvents int, decider string) {
 	// We have a `keep` that is different from pt's `DroppedTrace` field as `DroppedTrace` will be sent to intake.
 	// For example: We want to maintain the overall trace level sampling decision for a trace with Analytics Events
 	// where a trace might be marked as DroppedTrace true, but we still sent analytics events in that ProcessedTrace.
-	keep, checkAnalyticsEvents := a.traceSampling(now, ts, pt)
+	keep, checkAnalyticsEvents, decider := a.traceSampling(now, ts, pt)
 
 	var events []*pb.Span
 	if checkAnalyticsEvents {
@@ -569,7 +612,7 @@ func (a *Agent) sample(now time.Time, ts *info.TagStats, pt *traceutil.Processed
 		}
 	}
 
-	return keep, len(events)
+	return keep, len(events), decider
 }
 
 // isManualUserDrop returns true if and only if the ProcessedTrace is marked as Priority User Drop
@@ -591,10 +634,10 @@ func isManualUserDrop(pt *traceutil.ProcessedTrace) bool {
 }
 
 // traceSampling reports whether the chunk should be kept as a trace, setting "DroppedTrace" on the chunk
-func (a *Agent) traceSampling(now time.Time, ts *info.TagStats, pt *traceutil.ProcessedTrace) (keep bool, checkAnalyticsEvents bool) {
-	sampled, check := a.runSamplers(now, ts, *pt)
+func (a *Agent) traceSampling(now time.Time, ts *info.TagStats, pt *traceutil.ProcessedTrace) (keep bool, checkAnalyticsEvents bool, decider string) {
+	sampled, check, decider := a.runSamplers(now, ts, *pt)
 	pt.TraceChunk.DroppedTrace = !sampled
-	return sampled, check
+	return sampled, check, decider
 }
 
 // getAnalyzedEvents returns any sampled analytics events in the ProcessedTrace
@@ -606,29 +649,29 @@ func (a *Agent) getAnalyzedEvents(pt *traceutil.ProcessedTrace, ts *info.TagStat
 }
 
 // runSamplers runs the agent's configured samplers on pt and returns the sampling decision along
-// with the sampling rate.
+// with the name of the sampler which made it.
 //
 // The rare sampler is run first, catching all rare traces early. If the probabilistic sampler is
 // enabled, it is run on the trace, followed by the error sampler. Otherwise, If the trace has a
 // priority set, the sampling priority is used with the Priority Sampler. When there is no priority
 // set, the NoPrioritySampler is run. Finally, if the trace has not been sampled by the other
 // samplers, the error sampler is run.
-func (a *Agent) runSamplers(now time.Time, ts *info.TagStats, pt traceutil.ProcessedTrace) (keep bool, checkAnalyticsEvents bool) {
+func (a *Agent) runSamplers(now time.Time, ts *info.TagStats, pt traceutil.ProcessedTrace) (keep bool, checkAnalyticsEvents bool, decider string) {
 	// run this early to make sure the signature gets counted by the RareSampler.
 	rare := a.RareSampler.Sample(now, pt.TraceChunk, pt.TracerEnv)
 
 	if a.conf.ProbabilisticSamplerEnabled {
 		if rare {
-			return true, true
+			return true, true, samplerRare
 		}
 		if a.ProbabilisticSampler.Sample(pt.Root) {
 			pt.TraceChunk.Tags[tagDecisionMaker] = probabilitySampling
-			return true, true
+			return true, true, samplerProbabilistic
 		}
 		if traceContainsError(pt.TraceChunk.Spans) {
-			return a.ErrorsSampler.Sample(now, pt.TraceChunk.Spans, pt.Root, pt.TracerEnv), true
+			return a.ErrorsSampler.Sample(now, pt.TraceChunk.Spans, pt.Root, pt.TracerEnv), true, samplerErrors
 		}
-		return false, true
+		return false, true, samplerProbabilistic
 	}
 
 	priority, hasPriority := sampler.GetSamplingPriority(pt.TraceChunk)
@@ -642,31 +685,33 @@ func (a *Agent) runSamplers(now time.Time, ts *info.TagStats, pt traceutil.Proce
 		// Note that we DON'T skip single span sampling. We only do this for historical
 		// reasons and analytics events are deprecated so hopefully this can all go away someday.
 		if isManualUserDrop(&pt) {
-			return false, false
+			return false, false, samplerUserDrop
 		}
 	} else { // This path to be deleted once manualUserDrop detection is available on all tracers for P < 1.
 		if priority < 0 {
-			return false, false
+			return false, false, samplerUserDrop
 		}
 	}
 
 	if rare {
-		return true, true
+		return true, true, samplerRare
 	}
 
+	decider = samplerNoPriority
 	if hasPriority {
+		decider = samplerPriority
 		if a.PrioritySampler.Sample(now, pt.TraceChunk, pt.Root, pt.TracerEnv, pt.ClientDroppedP0sWeight) {
-			return true, true
+			return true, true, decider
 		}
 	} else if a.NoPrioritySampler.Sample(now, pt.TraceChunk.Spans, pt.Root, pt.TracerEnv) {
-		return true, true
+		return true, true, decider
 	}
 
 	if traceContainsError(pt.TraceChunk.Spans) {
-		return a.ErrorsSampler.Sample(now, pt.TraceChunk.Spans, pt.Root, pt.TracerEnv), true
+		return a.ErrorsSampler.Sample(now, pt.TraceChunk.Spans, pt.Root, pt.TracerEnv), true, samplerErrors
 	}
 
-	return false, true
+	return false, true, decider
 }
 
 func traceContainsError(trace pb.Trace) bool {
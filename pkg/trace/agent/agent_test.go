@@ -156,6 +156,84 @@ func TestProcess(t *testing.T) {
 		assert.Equal("SELECT name FROM people WHERE age = ? AND extra = ?", span.Meta["sql.query"])
 	})
 
+	t.Run("TraceStream", func(t *testing.T) {
+		cfg := config.New()
+		cfg.Endpoints[0].APIKey = "test"
+		ctx, cancel := context.WithCancel(context.Background())
+		agnt := NewTestAgent(ctx, cfg, telemetry.NewNoopCollector())
+		defer cancel()
+		chunks, unsubscribe := agnt.TraceStream.Subscribe(api.TraceStreamFilters{Service: "db"})
+		defer unsubscribe()
+
+		now := time.Now()
+		span := &pb.Span{
+			TraceID:  1,
+			SpanID:   1,
+			Service:  "db",
+			Name:     "query",
+			Resource: "SELECT name FROM people WHERE age = 42",
+			Type:     "sql",
+			Start:    now.Add(-time.Second).UnixNano(),
+			Duration: (500 * time.Millisecond).Nanoseconds(),
+		}
+		chunk := testutil.TraceChunkWithSpan(span)
+		chunk.Priority = int32(sampler.PriorityUserKeep)
+		agnt.Process(&api.Payload{
+			TracerPayload: testutil.TracerPayloadWithChunk(chunk),
+			Source:        info.NewReceiverStats().GetTagStats(info.Tags{}),
+		})
+
+		assert := assert.New(t)
+		require.Len(t, chunks, 1)
+		c := <-chunks
+		assert.EqualValues(1, c.TraceID)
+		assert.Equal("db", c.Service)
+		assert.Equal("SELECT name FROM people WHERE age = ?", c.Resource)
+		assert.True(c.Kept)
+		assert.Equal("priority", c.Sampler)
+		assert.EqualValues(sampler.PriorityUserKeep, c.Priority)
+		require.Len(t, c.Spans, 1)
+		assert.Equal("SELECT name FROM people WHERE age = ?", c.Spans[0].Resource)
+		assert.Equal("SELECT name FROM people WHERE age = 42", c.Spans[0].RawResource)
+	})
+
+	t.Run("TraceStreamDropped", func(t *testing.T) {
+		cfg := config.New()
+		cfg.Endpoints[0].APIKey = "test"
+		cfg.Ignore["resource"] = []string{"^INSERT.*"}
+		ctx, cancel := context.WithCancel(context.Background())
+		agnt := NewTestAgent(ctx, cfg, telemetry.NewNoopCollector())
+		defer cancel()
+		chunks, unsubscribe := agnt.TraceStream.Subscribe(api.TraceStreamFilters{})
+		defer unsubscribe()
+
+		now := time.Now()
+		span := &pb.Span{
+			TraceID:  1,
+			SpanID:   1,
+			Service:  "db",
+			Name:     "query",
+			Resource: "INSERT INTO people VALUES (42)",
+			Type:     "sql",
+			Start:    now.Add(-time.Second).UnixNano(),
+			Duration: (500 * time.Millisecond).Nanoseconds(),
+		}
+		agnt.Process(&api.Payload{
+			TracerPayload: testutil.TracerPayloadWithChunk(testutil.TraceChunkWithSpan(span)),
+			Source:        info.NewReceiverStats().GetTagStats(info.Tags{}),
+		})
+
+		assert := assert.New(t)
+		require.Len(t, chunks, 1)
+		c := <-chunks
+		assert.EqualValues(1, c.TraceID)
+		assert.Equal("INSERT INTO people VALUES (42)", c.Resource)
+		assert.False(c.Kept)
+		assert.Equal("", c.Sampler)
+		assert.Equal("ignore_resources", c.DropReason)
+		require.Len(t, c.Spans, 1)
+	})
+
 	t.Run("ReplacerMetrics", func(t *testing.T) {
 		cfg := config.New()
 		cfg.Endpoints[0].APIKey = "test"
@@ -1258,7 +1336,7 @@ func TestSampling(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			a := configureAgent(tt.agentConfig)
 			for _, tc := range tt.testCases {
-				sampled, _ := a.traceSampling(time.Now(), &info.TagStats{}, &tc.trace)
+				sampled, _, _ := a.traceSampling(time.Now(), &info.TagStats{}, &tc.trace)
 				assert.EqualValues(t, tc.wantSampled, sampled)
 			}
 		})
@@ -1337,12 +1415,12 @@ func TestSample(t *testing.T) {
 			conf:              cfg,
 		}
 		t.Run(name, func(t *testing.T) {
-			keep, _ := a.traceSampling(now, info.NewReceiverStats().GetTagStats(info.Tags{}), &tt.trace)
+			keep, _, _ := a.traceSampling(now, info.NewReceiverStats().GetTagStats(info.Tags{}), &tt.trace)
 			assert.Equal(t, tt.keep, keep)
 			assert.Equal(t, !tt.keep, tt.trace.TraceChunk.DroppedTrace)
 			cfg.Features["error_rare_sample_tracer_drop"] = struct{}{}
 			defer delete(cfg.Features, "error_rare_sample_tracer_drop")
-			keep, _ = a.traceSampling(now, info.NewReceiverStats().GetTagStats(info.Tags{}), &tt.trace)
+			keep, _, _ = a.traceSampling(now, info.NewReceiverStats().GetTagStats(info.Tags{}), &tt.trace)
 			assert.Equal(t, tt.keepWithFeature, keep)
 			assert.Equal(t, !tt.keepWithFeature, tt.trace.TraceChunk.DroppedTrace)
 		})
@@ -1373,7 +1451,7 @@ func TestSampleManualUserDropNoAnalyticsEvents(t *testing.T) {
 		EventProcessor:    newEventProcessor(cfg, statsd),
 		conf:              cfg,
 	}
-	keep, _ := a.sample(now, info.NewReceiverStats().GetTagStats(info.Tags{}), &pt)
+	keep, _, _ := a.sample(now, info.NewReceiverStats().GetTagStats(info.Tags{}), &pt)
 	assert.False(t, keep)
 	assert.Empty(t, pt.Root.Metrics["_dd.analyzed"])
 }
@@ -2001,7 +2079,7 @@ func TestSampleWithPriorityNone(t *testing.T) {
 	}
 	// before := traceutil.CopyTraceChunk(pt.TraceChunk)
 	before := pt.TraceChunk.ShallowCopy()
-	keep, numEvents := agnt.sample(time.Now(), info.NewReceiverStats().GetTagStats(info.Tags{}), &pt)
+	keep, numEvents, _ := agnt.sample(time.Now(), info.NewReceiverStats().GetTagStats(info.Tags{}), &pt)
 	assert.True(t, keep) // Score Sampler should keep the trace.
 	assert.False(t, pt.TraceChunk.DroppedTrace)
 	assert.Equal(t, before, pt.TraceChunk)
@@ -2360,7 +2438,7 @@ func TestSingleSpanPlusAnalyticsEvents(t *testing.T) {
 	var b bytes.Buffer
 	oldLogger := log.SetLogger(log.NewBufferLogger(&b))
 	defer func() { log.SetLogger(oldLogger) }()
-	keep, numEvents := traceAgent.sample(time.Now(), info.NewReceiverStats().GetTagStats(info.Tags{}), payload)
+	keep, numEvents, _ := traceAgent.sample(time.Now(), info.NewReceiverStats().GetTagStats(info.Tags{}), payload)
 	assert.Equal(t, "[WARN] Detected both analytics events AND single span sampling in the same trace. Single span sampling wins because App Analytics is deprecated.", b.String())
 	assert.False(t, keep) //The sampling decision was FALSE but the trace itself is marked as not dropped
 	assert.False(t, payload.TraceChunk.DroppedTrace)
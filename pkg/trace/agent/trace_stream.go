@@ -0,0 +1,56 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package agent
+
+import (
+	"time"
+
+	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/trace"
+	"github.com/DataDog/datadog-agent/pkg/trace/api"
+	"github.com/DataDog/datadog-agent/pkg/trace/traceutil"
+)
+
+// newStreamedChunk returns the description of the processed chunk of pt reported
+// in the trace stream. rawResources holds the resources of its spans before
+// obfuscation. The sampling decision is left to the caller.
+func newStreamedChunk(now time.Time, pt *traceutil.ProcessedTrace, rawResources []string) *api.StreamedChunk {
+	c := &api.StreamedChunk{
+		Time:  now,
+		Env:   pt.TracerEnv,
+		Spans: make([]api.StreamedSpan, 0, len(pt.TraceChunk.Spans)),
+	}
+	if pt.Root != nil {
+		c.TraceID = pt.Root.TraceID
+		c.Service = pt.Root.Service
+		c.Resource = pt.Root.Resource
+	}
+	for i, span := range pt.TraceChunk.Spans {
+		s := api.StreamedSpan{
+			SpanID:   span.SpanID,
+			ParentID: span.ParentID,
+			Service:  span.Service,
+			Name:     span.Name,
+			Resource: span.Resource,
+		}
+		if i < len(rawResources) && rawResources[i] != span.Resource {
+			s.RawResource = rawResources[i]
+		}
+		c.Spans = append(c.Spans, s)
+	}
+	return c
+}
+
+// streamDroppedChunk reports in the trace stream a chunk dropped before sampling,
+// along with the reason why it was dropped. Its resources are not obfuscated.
+func (a *Agent) streamDroppedChunk(now time.Time, env string, chunk *pb.TraceChunk, root *pb.Span, reason string) {
+	if !a.TraceStream.Enabled() {
+		return
+	}
+	c := newStreamedChunk(now, &traceutil.ProcessedTrace{TraceChunk: chunk, Root: root, TracerEnv: env}, nil)
+	c.Priority = chunk.Priority
+	c.DropReason = reason
+	a.TraceStream.Publish(c)
+}
@@ -0,0 +1,220 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"github.com/DataDog/datadog-agent/pkg/trace/log"
+)
+
+// traceStreamBufferSize specifies the number of chunks buffered for each stream
+// subscriber. Chunks are dropped for subscribers which can't keep up.
+const traceStreamBufferSize = 1000
+
+// StreamedChunk describes a trace chunk received by the agent along with the
+// outcome of its processing, as reported by the trace stream.
+type StreamedChunk struct {
+	// Time is the time at which the chunk was processed.
+	Time time.Time `json:"time"`
+	// TraceID is the (lower 64 bits) ID of the trace.
+	TraceID uint64 `json:"trace_id"`
+	// Env is the env of the tracer which sent the chunk.
+	Env string `json:"env,omitempty"`
+	// Service and Resource are the service and the obfuscated resource of the
+	// root span of the chunk.
+	Service  string `json:"service"`
+	Resource string `json:"resource"`
+	// Priority is the sampling priority of the chunk.
+	Priority int32 `json:"priority"`
+	// Kept reports whether the chunk was kept by the samplers.
+	Kept bool `json:"kept"`
+	// Sampler is the name of the sampler which decided to keep or drop the chunk.
+	// It is empty for chunks dropped before sampling.
+	Sampler string `json:"sampler"`
+	// DropReason is set for chunks dropped before sampling: "invalid" when they
+	// fail normalization, "ignore_resources" or "tag_filters" when filtered out.
+	DropReason string `json:"drop_reason,omitempty"`
+	// Spans are the spans of the chunk, as received.
+	Spans []StreamedSpan `json:"spans"`
+}
+
+// StreamedSpan describes a span of a StreamedChunk.
+type StreamedSpan struct {
+	SpanID   uint64 `json:"span_id"`
+	ParentID uint64 `json:"parent_id"`
+	Service  string `json:"service"`
+	Name     string `json:"name"`
+	// Resource is the resource of the span after obfuscation.
+	Resource string `json:"resource"`
+	// RawResource is the resource of the span before obfuscation, it is only set
+	// when obfuscation changed it.
+	RawResource string `json:"raw_resource,omitempty"`
+}
+
+// TraceStreamFilters specifies which chunks a stream subscriber receives. Empty
+// filters match all chunks.
+type TraceStreamFilters struct {
+	// Service matches chunks having a span with this exact service.
+	Service string
+	// Resource matches chunks having a span whose resource, before or after
+	// obfuscation, contains this string.
+	Resource string
+	// TraceID matches the chunks of this trace.
+	TraceID uint64
+}
+
+// match reports whether c matches the filters.
+func (f *TraceStreamFilters) match(c *StreamedChunk) bool {
+	if f.TraceID != 0 && c.TraceID != f.TraceID {
+		return false
+	}
+	if f.Service == "" && f.Resource == "" {
+		return true
+	}
+	serviceOK, resourceOK := f.Service == "", f.Resource == ""
+	for _, s := range c.Spans {
+		if !serviceOK && s.Service == f.Service {
+			serviceOK = true
+		}
+		if !resourceOK && (strings.Contains(s.Resource, f.Resource) || strings.Contains(s.RawResource, f.Resource)) {
+			resourceOK = true
+		}
+	}
+	return serviceOK && resourceOK
+}
+
+type traceStreamSubscriber struct {
+	filters TraceStreamFilters
+	out     chan *StreamedChunk
+	dropped *atomic.Int64
+}
+
+// TraceStream broadcasts the chunks processed by the agent to the clients of the
+// /debug/traces endpoint. Publishing is a no-op while nobody is listening.
+type TraceStream struct {
+	mu          sync.RWMutex
+	subscribers map[*traceStreamSubscriber]struct{}
+	count       *atomic.Int32 // number of subscribers
+}
+
+// NewTraceStream returns a new TraceStream.
+func NewTraceStream() *TraceStream {
+	return &TraceStream{
+		subscribers: make(map[*traceStreamSubscriber]struct{}),
+		count:       atomic.NewInt32(0),
+	}
+}
+
+// Enabled reports whether anybody is listening to the stream. It is safe to call
+// on a nil TraceStream.
+func (ts *TraceStream) Enabled() bool {
+	return ts != nil && ts.count.Load() > 0
+}
+
+// Publish sends c to all the subscribers whose filters match it, without blocking.
+func (ts *TraceStream) Publish(c *StreamedChunk) {
+	if !ts.Enabled() {
+		return
+	}
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	for s := range ts.subscribers {
+		if !s.filters.match(c) {
+			continue
+		}
+		select {
+		case s.out <- c:
+		default:
+			s.dropped.Inc()
+		}
+	}
+}
+
+// Subscribe registers a new subscriber receiving the chunks matching filters on
+// the returned channel. The returned function must be called to unregister it.
+func (ts *TraceStream) Subscribe(filters TraceStreamFilters) (<-chan *StreamedChunk, func()) {
+	s, unsubscribe := ts.subscribe(filters)
+	return s.out, unsubscribe
+}
+
+// subscribe registers a new subscriber. The returned function must be called
+// to unregister it.
+func (ts *TraceStream) subscribe(filters TraceStreamFilters) (*traceStreamSubscriber, func()) {
+	s := &traceStreamSubscriber{
+		filters: filters,
+		out:     make(chan *StreamedChunk, traceStreamBufferSize),
+		dropped: atomic.NewInt64(0),
+	}
+	ts.mu.Lock()
+	ts.subscribers[s] = struct{}{}
+	ts.count.Inc()
+	ts.mu.Unlock()
+	return s, func() {
+		ts.mu.Lock()
+		delete(ts.subscribers, s)
+		ts.count.Dec()
+		ts.mu.Unlock()
+	}
+}
+
+// ServeHTTP streams the chunks processed by the agent as newline delimited JSON,
+// until the client goes away. The service, resource and trace_id query string
+// parameters can be used to filter them.
+func (ts *TraceStream) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	q := req.URL.Query()
+	filters := TraceStreamFilters{
+		Service:  q.Get("service"),
+		Resource: q.Get("resource"),
+	}
+	if v := q.Get("trace_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			http.Error(w, "trace_id must be an unsigned integer", http.StatusBadRequest)
+			return
+		}
+		filters.TraceID = id
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	// the debug server has a write timeout, which doesn't apply to a stream
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		log.Debugf("Unable to clear write deadline of trace stream: %v", err)
+	}
+
+	s, unsubscribe := ts.subscribe(filters)
+	defer unsubscribe()
+	log.Infof("Trace stream client connected from %s (filters: %+v)", req.RemoteAddr, filters)
+	defer func() {
+		log.Infof("Trace stream client disconnected from %s (%d chunks dropped)", req.RemoteAddr, s.dropped.Load())
+	}()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	enc := json.NewEncoder(w)
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case c := <-s.out:
+			if err := enc.Encode(c); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceStreamFilters(t *testing.T) {
+	c := &StreamedChunk{
+		TraceID: 42,
+		Spans: []StreamedSpan{
+			{Service: "web", Resource: "GET /users"},
+			{Service: "db", Resource: "SELECT * FROM users WHERE id = ?", RawResource: "SELECT * FROM users WHERE id = 7"},
+		},
+	}
+	for _, tt := range []struct {
+		filters TraceStreamFilters
+		match   bool
+	}{
+		{TraceStreamFilters{}, true},
+		{TraceStreamFilters{TraceID: 42}, true},
+		{TraceStreamFilters{TraceID: 43}, false},
+		{TraceStreamFilters{Service: "db"}, true},
+		{TraceStreamFilters{Service: "cache"}, false},
+		{TraceStreamFilters{Resource: "/users"}, true},
+		{TraceStreamFilters{Resource: "id = 7"}, true},
+		{TraceStreamFilters{Service: "web", Resource: "SELECT"}, true},
+		{TraceStreamFilters{Service: "web", Resource: "DELETE"}, false},
+	} {
+		assert.Equal(t, tt.match, tt.filters.match(c), "%+v", tt.filters)
+	}
+}
+
+func TestTraceStream(t *testing.T) {
+	ts := NewTraceStream()
+	assert.False(t, ts.Enabled())
+	// publishing without subscribers is a no-op
+	ts.Publish(&StreamedChunk{TraceID: 1})
+
+	server := httptest.NewServer(ts)
+	defer server.Close()
+
+	t.Run("bad-request", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "?trace_id=abc")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "?service=web")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Eventually(t, ts.Enabled, time.Second, 10*time.Millisecond)
+
+		ts.Publish(&StreamedChunk{TraceID: 1, Spans: []StreamedSpan{{Service: "db"}}})
+		ts.Publish(&StreamedChunk{TraceID: 2, Kept: true, Sampler: "priority", Spans: []StreamedSpan{{Service: "web"}}})
+
+		var c StreamedChunk
+		line, err := bufio.NewReader(resp.Body).ReadBytes('\n')
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(line, &c))
+		assert.EqualValues(t, 2, c.TraceID)
+		assert.True(t, c.Kept)
+		assert.Equal(t, "priority", c.Sampler)
+	})
+
+	assert.Eventually(t, func() bool { return !ts.Enabled() }, time.Second, 10*time.Millisecond)
+}
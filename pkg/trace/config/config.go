@@ -190,6 +190,27 @@ type WriterConfig struct {
 	FlushPeriodSeconds float64 `mapstructure:"flush_period_seconds"`
 }
 
+// DiskBufferConfig specifies the configuration of the on-disk buffer used by the
+// writers to keep payloads which couldn't be sent to the intake.
+type DiskBufferConfig struct {
+	// Enabled specifies whether payloads are buffered on disk instead of being
+	// dropped when the intake is unreachable.
+	Enabled bool
+
+	// Path specifies the directory where payloads are buffered. Each writer
+	// and endpoint uses its own sub-directory.
+	Path string
+
+	// MaxSizeBytes specifies the maximum size of the payloads buffered on disk
+	// by each writer and endpoint. The oldest payloads are dropped to make room
+	// for new ones.
+	MaxSizeBytes int64
+
+	// MaxAge specifies the maximum age of a buffered payload. Older payloads are
+	// dropped instead of being sent.
+	MaxAge time.Duration
+}
+
 // FargateOrchestratorName is a Fargate orchestrator name.
 type FargateOrchestratorName string
 
@@ -340,6 +361,9 @@ type AgentConfig struct {
 	// case, the sender will drop failed payloads when it is unable to enqueue
 	// them for another retry.
 	MaxSenderRetries int
+	// DiskBuffer specifies the configuration of the on-disk buffering of payloads
+	// which couldn't be sent to the intake.
+	DiskBuffer DiskBufferConfig
 	// HTTP client used in writer connections. If nil, default client values will be used.
 	HTTPClientFunc func() *http.Client `json:"-"`
 
@@ -500,6 +524,10 @@ func New() *AgentConfig {
 		TraceWriter:             new(WriterConfig),
 		ConnectionResetInterval: 0, // disabled
 		MaxSenderRetries:        4,
+		DiskBuffer: DiskBufferConfig{
+			MaxSizeBytes: 256 * 1024 * 1024, // 256MB
+			MaxAge:       6 * time.Hour,
+		},
 
 		StatsdHost:    "localhost",
 		StatsdPort:    8125,
@@ -34,6 +34,7 @@ var (
 
 	traceWriterInfo TraceWriterInfo
 	statsWriterInfo StatsWriterInfo
+	diskBufferInfo  map[string]DiskBufferInfo
 
 	watchdogInfo  watchdog.Info
 	rateByService map[string]float64
@@ -88,6 +89,9 @@ const (
   {{if gt .Status.TraceWriter.Errors.Load 0}}WARNING: Traces API errors (1 min): {{.Status.TraceWriter.Errors.Load}}{{end}}
   Stats: {{.Status.StatsWriter.Payloads.Load}} payloads, {{.Status.StatsWriter.StatsBuckets.Load}} stats buckets, {{.Status.StatsWriter.Bytes.Load}} bytes
   {{if gt .Status.StatsWriter.Errors.Load 0}}WARNING: Stats API errors (1 min): {{.Status.StatsWriter.Errors.Load}}{{end}}
+  {{ range $name, $db := .Status.DiskBuffer }}
+  Disk buffer ({{ $name }}): {{ $db.Payloads }} payloads, {{ $db.Bytes }} bytes buffered; {{ $db.Written }} written, {{ $db.Replayed }} replayed, {{ $db.Dropped }} dropped
+  {{end}}
 `
 
 	notRunningTmplSrc = `{{.Banner}}
@@ -213,12 +217,13 @@ type StatusInfo struct {
 		Version   string
 		GitCommit string
 	} `json:"version"`
-	Receiver      []TagStats         `json:"receiver"`
-	RateByService map[string]float64 `json:"ratebyservice_filtered"`
-	TraceWriter   TraceWriterInfo    `json:"trace_writer"`
-	StatsWriter   StatsWriterInfo    `json:"stats_writer"`
-	Watchdog      watchdog.Info      `json:"watchdog"`
-	Config        config.AgentConfig `json:"config"`
+	Receiver      []TagStats                `json:"receiver"`
+	RateByService map[string]float64        `json:"ratebyservice_filtered"`
+	TraceWriter   TraceWriterInfo           `json:"trace_writer"`
+	StatsWriter   StatsWriterInfo           `json:"stats_writer"`
+	DiskBuffer    map[string]DiskBufferInfo `json:"disk_buffer"`
+	Watchdog      watchdog.Info             `json:"watchdog"`
+	Config        config.AgentConfig        `json:"config"`
 }
 
 func getProgramBanner(version string) (string, string) {
@@ -332,6 +337,7 @@ func initInfo(conf *config.AgentConfig) error {
 	expvar.Publish("receiver", expvar.Func(publishReceiverStats))
 	expvar.Publish("trace_writer", expvar.Func(publishTraceWriterInfo))
 	expvar.Publish("stats_writer", expvar.Func(publishStatsWriterInfo))
+	expvar.Publish("disk_buffer", expvar.Func(publishDiskBufferInfo))
 	expvar.Publish("ratebyservice", expvar.Func(publishRateByService))
 	expvar.Publish("ratebyservice_filtered", expvar.Func(publishRateByServiceFiltered))
 	expvar.Publish("watchdog", expvar.Func(publishWatchdogInfo))
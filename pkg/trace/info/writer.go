@@ -100,3 +100,33 @@ func (swi StatsWriterInfo) MarshalJSON() ([]byte, error) {
 	}
 	return json.Marshal(asMap)
 }
+
+// DiskBufferInfo represents the state of the on-disk buffer of a writer.
+type DiskBufferInfo struct {
+	// Payloads and Bytes are the number and total size of the payloads
+	// currently buffered on disk.
+	Payloads int64
+	Bytes    int64
+	// Written, Replayed and Dropped count the payloads written to disk, read
+	// back from disk to be sent and dropped because of the size or age limits
+	// since the last report.
+	Written  int64
+	Replayed int64
+	Dropped  int64
+}
+
+// UpdateDiskBufferInfo updates the state of the on-disk buffer of the given writer.
+func UpdateDiskBufferInfo(writer string, dbi DiskBufferInfo) {
+	infoMu.Lock()
+	defer infoMu.Unlock()
+	if diskBufferInfo == nil {
+		diskBufferInfo = make(map[string]DiskBufferInfo)
+	}
+	diskBufferInfo[writer] = dbi
+}
+
+func publishDiskBufferInfo() interface{} {
+	infoMu.RLock()
+	defer infoMu.RUnlock()
+	return diskBufferInfo
+}
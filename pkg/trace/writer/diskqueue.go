@@ -0,0 +1,372 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package writer
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"github.com/DataDog/datadog-agent/pkg/trace/info"
+	"github.com/DataDog/datadog-agent/pkg/trace/log"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+)
+
+const (
+	// diskQueueFileExt is the extension of payload files stored in the disk queue.
+	diskQueueFileExt = ".payload"
+	// diskQueueTmpExt is the extension of payload files being written. They are
+	// renamed once fully written and synced, so leftovers are incomplete writes.
+	diskQueueTmpExt = ".tmp"
+)
+
+// diskEntry describes a payload stored in the disk queue.
+type diskEntry struct {
+	path    string    // path to the payload file
+	size    int64     // size of the file
+	created time.Time // time at which the payload was first buffered
+}
+
+// diskQueue is a size and age bounded FIFO of payloads stored on disk. It is used
+// by a sender to keep payloads which could not be sent instead of dropping them,
+// so that they can be replayed once the intake is reachable again, including after
+// a restart of the agent.
+//
+// A payload popped from the queue stays on disk until it is either acknowledged
+// (sent or rejected by the intake) or pushed back, so a crash while it is being
+// sent results in it being replayed on the next start.
+type diskQueue struct {
+	dir      string
+	maxBytes int64
+	maxAge   time.Duration
+
+	mu      sync.Mutex
+	entries []diskEntry // buffered entries, oldest first
+	size    int64       // total size of entries
+	seq     uint64      // sequence number, disambiguates files created at the same time
+
+	written  *atomic.Int64 // payloads written to disk
+	replayed *atomic.Int64 // payloads read back from disk to be sent
+	dropped  *atomic.Int64 // payloads dropped because of the size or age limits
+}
+
+// newDiskQueue returns a disk queue storing payloads in dir. Payloads left over
+// by a previous run are loaded and will be replayed first.
+func newDiskQueue(dir string, maxBytes int64, maxAge time.Duration) (*diskQueue, error) {
+	if maxBytes <= 0 {
+		return nil, errors.New("disk queue size limit must be positive")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("error creating disk buffer directory: %w", err)
+	}
+	q := &diskQueue{
+		dir:      dir,
+		maxBytes: maxBytes,
+		maxAge:   maxAge,
+		written:  atomic.NewInt64(0),
+		replayed: atomic.NewInt64(0),
+		dropped:  atomic.NewInt64(0),
+	}
+	if err := q.load(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// load scans the queue directory for payloads left by a previous run.
+func (q *diskQueue) load() error {
+	files, err := os.ReadDir(q.dir)
+	if err != nil {
+		return fmt.Errorf("error reading disk buffer directory: %w", err)
+	}
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		path := filepath.Join(q.dir, f.Name())
+		switch filepath.Ext(f.Name()) {
+		case diskQueueTmpExt:
+			// incomplete write from a previous run
+			_ = os.Remove(path)
+			continue
+		case diskQueueFileExt:
+		default:
+			continue
+		}
+		created, seq, ok := parseDiskQueueFileName(f.Name())
+		if !ok {
+			log.Debugf("Ignoring unexpected file in disk buffer: %s", path)
+			continue
+		}
+		fi, err := f.Info()
+		if err != nil {
+			continue
+		}
+		q.entries = append(q.entries, diskEntry{path: path, size: fi.Size(), created: created})
+		q.size += fi.Size()
+		if seq >= q.seq {
+			q.seq = seq + 1
+		}
+	}
+	// file names start with a fixed width timestamp, so lexical order is creation order
+	sort.Slice(q.entries, func(i, j int) bool { return q.entries[i].path < q.entries[j].path })
+	if n := len(q.entries); n > 0 {
+		log.Infof("Found %d payloads (%d bytes) to replay in disk buffer %s", n, q.size, q.dir)
+	}
+	q.mu.Lock()
+	q.evictLocked(time.Now())
+	q.mu.Unlock()
+	return nil
+}
+
+// parseDiskQueueFileName returns the creation time and the sequence number
+// encoded in the name of a payload file.
+func parseDiskQueueFileName(name string) (time.Time, uint64, bool) {
+	parts := strings.Split(strings.TrimSuffix(name, diskQueueFileExt), "-")
+	if len(parts) != 2 {
+		return time.Time{}, 0, false
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	seq, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	return time.Unix(0, nanos), seq, true
+}
+
+// put stores p on disk. The oldest payloads are dropped if the queue goes over
+// its size limit. A payload which was previously read from the queue is put back
+// at the front of the queue in order to preserve ordering.
+func (q *diskQueue) put(p *payload) error {
+	if p.diskEntry != nil {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		q.entries = append([]diskEntry{*p.diskEntry}, q.entries...)
+		q.size += p.diskEntry.size
+		q.evictLocked(time.Now())
+		return nil
+	}
+
+	now := time.Now()
+	q.mu.Lock()
+	name := fmt.Sprintf("%020d-%06d%s", now.UnixNano(), q.seq, diskQueueFileExt)
+	q.seq++
+	q.mu.Unlock()
+
+	path := filepath.Join(q.dir, name)
+	size, err := writeDiskQueueFile(path, p)
+	if err != nil {
+		return err
+	}
+	q.written.Inc()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.entries = append(q.entries, diskEntry{path: path, size: size, created: now})
+	q.size += size
+	q.evictLocked(now)
+	return nil
+}
+
+// writeDiskQueueFile atomically writes p to path and returns the size of the file.
+// The file is made of the length of the encoded headers as a big endian uint32,
+// the JSON encoded headers and the body.
+func writeDiskQueueFile(path string, p *payload) (int64, error) {
+	headers, err := json.Marshal(p.headers)
+	if err != nil {
+		return 0, err
+	}
+	tmp := strings.TrimSuffix(path, diskQueueFileExt) + diskQueueTmpExt
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return 0, err
+	}
+	var hlen [4]byte
+	binary.BigEndian.PutUint32(hlen[:], uint32(len(headers)))
+	_, err = f.Write(hlen[:])
+	if err == nil {
+		_, err = f.Write(headers)
+	}
+	if err == nil {
+		_, err = f.Write(p.body.Bytes())
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return 0, err
+	}
+	syncDir(filepath.Dir(path))
+	return int64(len(hlen) + len(headers) + p.body.Len()), nil
+}
+
+// syncDir flushes the directory entry of newly renamed files to disk. Errors are
+// ignored since not all platforms support syncing directories.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	_ = d.Sync()
+	_ = d.Close()
+}
+
+// pop reads the oldest payload from the queue. It returns nil if the queue is
+// empty. The payload file is kept on disk until ack is called.
+func (q *diskQueue) pop() *payload {
+	for {
+		q.mu.Lock()
+		q.evictLocked(time.Now())
+		if len(q.entries) == 0 {
+			q.mu.Unlock()
+			return nil
+		}
+		e := q.entries[0]
+		q.entries = q.entries[1:]
+		q.size -= e.size
+		q.mu.Unlock()
+
+		p, err := readDiskQueueFile(e)
+		if err != nil {
+			log.Errorf("Dropping unreadable payload from disk buffer %s: %v", e.path, err)
+			_ = os.Remove(e.path)
+			q.dropped.Inc()
+			continue
+		}
+		q.replayed.Inc()
+		return p
+	}
+}
+
+// readDiskQueueFile reads the payload stored in the file of e.
+func readDiskQueueFile(e diskEntry) (*payload, error) {
+	f, err := os.Open(e.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var hlen [4]byte
+	if _, err := io.ReadFull(f, hlen[:]); err != nil {
+		return nil, err
+	}
+	rawHeaders := make([]byte, binary.BigEndian.Uint32(hlen[:]))
+	if _, err := io.ReadFull(f, rawHeaders); err != nil {
+		return nil, err
+	}
+	headers := make(map[string]string)
+	if err := json.Unmarshal(rawHeaders, &headers); err != nil {
+		return nil, err
+	}
+	p := newPayload(headers)
+	if _, err := p.body.ReadFrom(f); err != nil {
+		ppool.Put(p)
+		return nil, err
+	}
+	p.diskEntry = &e
+	return p, nil
+}
+
+// ack removes the file backing p, if any, once it doesn't need to be replayed anymore.
+func (q *diskQueue) ack(p *payload) {
+	if p.diskEntry == nil {
+		return
+	}
+	if err := os.Remove(p.diskEntry.path); err != nil && !os.IsNotExist(err) {
+		log.Warnf("Error removing payload from disk buffer: %v", err)
+	}
+	p.diskEntry = nil
+}
+
+// evictLocked drops expired payloads and the oldest payloads over the size limit.
+// q.mu must be held.
+func (q *diskQueue) evictLocked(now time.Time) {
+	for len(q.entries) > 0 {
+		e := q.entries[0]
+		if q.size <= q.maxBytes && (q.maxAge <= 0 || now.Sub(e.created) <= q.maxAge) {
+			return
+		}
+		if err := os.Remove(e.path); err != nil && !os.IsNotExist(err) {
+			log.Warnf("Error removing payload from disk buffer: %v", err)
+		}
+		q.entries = q.entries[1:]
+		q.size -= e.size
+		q.dropped.Inc()
+	}
+}
+
+// len returns the number of payloads in the queue.
+func (q *diskQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.entries)
+}
+
+// info returns the current state of the queue. Counters are reset.
+func (q *diskQueue) info() info.DiskBufferInfo {
+	q.mu.Lock()
+	payloads, size := int64(len(q.entries)), q.size
+	q.mu.Unlock()
+	return info.DiskBufferInfo{
+		Payloads: payloads,
+		Bytes:    size,
+		Written:  q.written.Swap(0),
+		Replayed: q.replayed.Swap(0),
+		Dropped:  q.dropped.Swap(0),
+	}
+}
+
+// reportDiskBuffer reports the aggregated state of the disk queues of senders
+// under the given metric prefix, and publishes it as the disk buffer info of
+// the given writer.
+func reportDiskBuffer(statsd statsd.ClientInterface, prefix, writer string, senders []*sender) {
+	var (
+		dbi     info.DiskBufferInfo
+		enabled bool
+	)
+	for _, s := range senders {
+		if s.cfg.diskQueue == nil {
+			continue
+		}
+		enabled = true
+		si := s.cfg.diskQueue.info()
+		dbi.Payloads += si.Payloads
+		dbi.Bytes += si.Bytes
+		dbi.Written += si.Written
+		dbi.Replayed += si.Replayed
+		dbi.Dropped += si.Dropped
+	}
+	if !enabled {
+		return
+	}
+	_ = statsd.Gauge(prefix+".disk_buffer.payloads", float64(dbi.Payloads), nil, 1)
+	_ = statsd.Gauge(prefix+".disk_buffer.bytes", float64(dbi.Bytes), nil, 1)
+	_ = statsd.Count(prefix+".disk_buffer.written", dbi.Written, nil, 1)
+	_ = statsd.Count(prefix+".disk_buffer.replayed", dbi.Replayed, nil, 1)
+	_ = statsd.Count(prefix+".disk_buffer.dropped", dbi.Dropped, nil, 1)
+	info.UpdateDiskBufferInfo(writer, dbi)
+}
@@ -0,0 +1,102 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package writer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDiskPayload(body string) *payload {
+	p := newPayload(map[string]string{"Content-Type": "application/msgpack"})
+	p.body.WriteString(body)
+	return p
+}
+
+func popBodies(q *diskQueue) []string {
+	var bodies []string
+	for p := q.pop(); p != nil; p = q.pop() {
+		bodies = append(bodies, p.body.String())
+		q.ack(p)
+	}
+	return bodies
+}
+
+func TestDiskQueue(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		q, err := newDiskQueue(t.TempDir(), 1<<20, time.Hour)
+		require.NoError(t, err)
+		for _, body := range []string{"a", "b", "c"} {
+			require.NoError(t, q.put(testDiskPayload(body)))
+		}
+		assert.Equal(t, 3, q.len())
+
+		p := q.pop()
+		require.NotNil(t, p)
+		assert.Equal(t, "a", p.body.String())
+		assert.Equal(t, "application/msgpack", p.headers["Content-Type"])
+		// a replayed payload which fails again goes back to the front
+		require.NoError(t, q.put(p))
+		assert.Equal(t, []string{"a", "b", "c"}, popBodies(q))
+
+		files, err := os.ReadDir(q.dir)
+		require.NoError(t, err)
+		assert.Empty(t, files)
+
+		dbi := q.info()
+		assert.EqualValues(t, 3, dbi.Written)
+		assert.EqualValues(t, 4, dbi.Replayed)
+		assert.EqualValues(t, 0, dbi.Payloads)
+	})
+
+	t.Run("size", func(t *testing.T) {
+		q, err := newDiskQueue(t.TempDir(), 1<<20, time.Hour)
+		require.NoError(t, err)
+		require.NoError(t, q.put(testDiskPayload("a")))
+		// room for two payloads of the same size
+		q.maxBytes = 2 * q.size
+		for _, body := range []string{"b", "c", "d"} {
+			require.NoError(t, q.put(testDiskPayload(body)))
+		}
+		assert.Equal(t, []string{"c", "d"}, popBodies(q))
+		assert.EqualValues(t, 2, q.info().Dropped)
+	})
+
+	t.Run("age", func(t *testing.T) {
+		q, err := newDiskQueue(t.TempDir(), 1<<20, time.Hour)
+		require.NoError(t, err)
+		require.NoError(t, q.put(testDiskPayload("old")))
+		require.NoError(t, q.put(testDiskPayload("new")))
+		q.entries[0].created = time.Now().Add(-2 * time.Hour)
+		assert.Equal(t, []string{"new"}, popBodies(q))
+		assert.EqualValues(t, 1, q.info().Dropped)
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		dir := t.TempDir()
+		q, err := newDiskQueue(dir, 1<<20, time.Hour)
+		require.NoError(t, err)
+		for _, body := range []string{"a", "b", "c"} {
+			require.NoError(t, q.put(testDiskPayload(body)))
+		}
+		// "a" was being sent when the agent crashed
+		require.NotNil(t, q.pop())
+		// as was the write of another payload
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "00000000000000000001-000001.tmp"), []byte("partial"), 0600))
+
+		q, err = newDiskQueue(dir, 1<<20, time.Hour)
+		require.NoError(t, err)
+		require.NoError(t, q.put(testDiskPayload("d")))
+		assert.Equal(t, []string{"a", "b", "c", "d"}, popBodies(q))
+		_, err = os.Stat(filepath.Join(dir, "00000000000000000001-000001.tmp"))
+		assert.True(t, os.IsNotExist(err))
+	})
+}
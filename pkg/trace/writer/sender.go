@@ -16,7 +16,9 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -54,11 +56,33 @@ func newSenders(cfg *config.AgentConfig, r eventRecorder, path string, climit, q
 			apiKey:     endpoint.APIKey,
 			recorder:   r,
 			userAgent:  fmt.Sprintf("Datadog Trace Agent/%s/%s", cfg.AgentVersion, cfg.GitCommit),
+			diskQueue:  newSenderDiskQueue(cfg.DiskBuffer, url),
 		}, statsd)
 	}
 	return senders
 }
 
+// newSenderDiskQueue returns the disk queue used by the sender targeting url, or nil
+// if disk buffering is disabled or the queue could not be created.
+func newSenderDiskQueue(cfg config.DiskBufferConfig, url *url.URL) *diskQueue {
+	if !cfg.Enabled {
+		return nil
+	}
+	// each sender gets its own directory, named after its destination
+	name := strings.Map(func(r rune) rune {
+		if ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') || r == '.' || r == '-' {
+			return r
+		}
+		return '_'
+	}, url.Host+url.Path)
+	dq, err := newDiskQueue(filepath.Join(cfg.Path, name), cfg.MaxSizeBytes, cfg.MaxAge)
+	if err != nil {
+		log.Errorf("Disk buffering disabled for %s: %v", url.Host, err)
+		return nil
+	}
+	return dq
+}
+
 // eventRecorder implementations are able to take note of events happening in
 // the sender.
 type eventRecorder interface {
@@ -136,6 +160,9 @@ type senderConfig struct {
 	recorder eventRecorder
 	// userAgent is the computed user agent we'll use when communicating with Datadog
 	userAgent string
+	// diskQueue, when set, stores payloads which can't be queued or sent so that
+	// they are replayed later instead of being dropped.
+	diskQueue *diskQueue
 }
 
 // sender is responsible for sending payloads to a given URL. It uses a size-limited
@@ -150,6 +177,12 @@ type sender struct {
 	mu     sync.RWMutex // guards closed
 	closed bool         // closed reports if the loop is stopped
 	statsd statsd.ClientInterface
+
+	healthy  *atomic.Bool   // healthy reports whether the last payload reached the intake
+	done     chan struct{}  // closed to stop the replay loop
+	wg       sync.WaitGroup // waits for the replay loop
+	spillc   chan *payload  // payloads waiting to be written to the disk queue
+	spilling *atomic.Int32  // payloads pushed to spillc and not yet written
 }
 
 // newSender returns a new sender based on the given config cfg.
@@ -160,13 +193,101 @@ func newSender(cfg *senderConfig, statsd statsd.ClientInterface) *sender {
 		inflight:   atomic.NewInt32(0),
 		maxRetries: int32(cfg.maxRetries),
 		statsd:     statsd,
+		healthy:    atomic.NewBool(true),
+		done:       make(chan struct{}),
+		spilling:   atomic.NewInt32(0),
 	}
 	for i := 0; i < cfg.maxConns; i++ {
 		go s.loop()
 	}
+	if cfg.diskQueue != nil {
+		s.spillc = make(chan *payload, spillQueueSize)
+		go s.spillLoop()
+		s.wg.Add(1)
+		go s.replayLoop()
+	}
 	return &s
 }
 
+// spillQueueSize specifies the maximum number of payloads waiting to be written to
+// the disk queue. It is small so that spilling doesn't significantly raise the
+// memory used by the sender's payloads, beyond its queue.
+const spillQueueSize = 8
+
+// replayInterval specifies how often the sender tries to replay payloads from its disk queue.
+var replayInterval = time.Second
+
+// replayLoop moves payloads from the disk queue back to the sender's queue whenever
+// there is room for them. While the intake is unreachable, only one payload at a
+// time is replayed to probe it.
+func (s *sender) replayLoop() {
+	defer s.wg.Done()
+	tick := time.NewTicker(replayInterval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-tick.C:
+			s.replay()
+		}
+	}
+}
+
+// replay pushes payloads from the disk queue onto the sender's queue, without blocking.
+func (s *sender) replay() {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for !s.closed && len(s.queue) < cap(s.queue) {
+		if !s.healthy.Load() && s.inflight.Load() > 0 {
+			return
+		}
+		p := s.cfg.diskQueue.pop()
+		if p == nil {
+			return
+		}
+		s.inflight.Inc()
+		select {
+		case s.queue <- p:
+		default:
+			// the queue filled up in the meantime
+			if s.spill(p) {
+				s.inflight.Dec()
+				return
+			}
+			s.ackPayload(p)
+			s.releasePayload(p, eventTypeDropped, &eventData{bytes: p.body.Len(), count: 1})
+			return
+		}
+	}
+}
+
+// spillLoop writes the payloads pushed while the sender's queue is full, or while
+// older payloads are buffered, to the disk queue. Writing them synchronously would
+// block Push on disk syncs. Payloads pushed while spillc is full are dropped.
+func (s *sender) spillLoop() {
+	for p := range s.spillc {
+		if s.spill(p) {
+			s.inflight.Dec()
+		} else {
+			s.releasePayload(p, eventTypeDropped, &eventData{bytes: p.body.Len(), count: 1})
+		}
+		s.spilling.Dec()
+	}
+}
+
+// spill stores p in the disk queue and releases it. It returns false if the
+// payload could not be stored, in which case it is left untouched.
+func (s *sender) spill(p *payload) bool {
+	if err := s.cfg.diskQueue.put(p); err != nil {
+		log.Errorf("Error writing payload to disk buffer: %v", err)
+		return false
+	}
+	p.diskEntry = nil
+	ppool.Put(p)
+	return true
+}
+
 // loop runs the main sender loop.
 func (s *sender) loop() {
 	for p := range s.queue {
@@ -186,11 +307,16 @@ func (s *sender) backoff(attempt int) {
 // Stop stops the sender. It attempts to wait for all inflight payloads to complete
 // with a timeout of 5 seconds.
 func (s *sender) Stop() {
+	close(s.done)
+	s.wg.Wait()
 	s.WaitForInflight()
 	s.mu.Lock()
 	s.closed = true
 	s.mu.Unlock()
 	close(s.queue)
+	if s.spillc != nil {
+		close(s.spillc)
+	}
 }
 
 // WaitForInflight blocks until all in progress payloads are sent,
@@ -218,6 +344,12 @@ func (s *sender) Push(p *payload) {
 		s.mu.RUnlock()
 		return
 	}
+	if s.cfg.diskQueue != nil {
+		// the lock is held until the payload is pushed, as Stop closes spillc
+		s.pushOrSpill(p)
+		s.mu.RUnlock()
+		return
+	}
 	s.mu.RUnlock()
 	select {
 	case s.queue <- p:
@@ -228,6 +360,34 @@ func (s *sender) Push(p *payload) {
 	s.inflight.Inc()
 }
 
+// pushOrSpill pushes p onto the sender's queue or, if the queue is full or older
+// payloads are buffered on disk, onto spillc to be written to the disk queue. It
+// never blocks: if the disk can't keep up, p is dropped. It must be called with
+// s.mu held.
+func (s *sender) pushOrSpill(p *payload) {
+	// payloads already on disk, or being written to it, are older and must be
+	// sent first
+	if s.cfg.diskQueue.len() == 0 && s.spilling.Load() == 0 {
+		select {
+		case s.queue <- p:
+			s.inflight.Inc()
+			return
+		default:
+		}
+	}
+	// spilled payloads are inflight until they are written, so that they
+	// are waited for when stopping
+	s.spilling.Inc()
+	s.inflight.Inc()
+	select {
+	case s.spillc <- p:
+	default:
+		s.spilling.Dec()
+		_ = s.statsd.Count("datadog.trace_agent.sender.spill_dropped", 1, nil, 1)
+		s.releasePayload(p, eventTypeDropped, &eventData{bytes: p.body.Len(), count: 1})
+	}
+}
+
 // sendPayload sends the payload p to the destination URL.
 func (s *sender) sendPayload(p *payload) {
 	for attempt := 0; ; attempt++ {
@@ -263,7 +423,13 @@ func (s *sender) sendOnce(p *payload) bool {
 		// request failed again, but can be retried
 		s.mu.RLock()
 		defer s.mu.RUnlock()
+		s.healthy.Store(false)
 		if s.closed {
+			if s.cfg.diskQueue != nil && s.spill(p) {
+				// sender is stopped, the payload will be sent on next start
+				s.inflight.Dec()
+				return true
+			}
 			s.releasePayload(p, eventTypeDropped, stats)
 			// sender is stopped
 			return true
@@ -276,6 +442,12 @@ func (s *sender) sendOnce(p *payload) bool {
 			log.Warnf("Retried payload %d times: %s", r, err.Error())
 		}
 		if p.retries.Load() >= s.maxRetries {
+			if s.cfg.diskQueue != nil && s.spill(p) {
+				log.Debugf("Buffering payload on disk after %d retries, due to: %v.\n", p.retries.Load(), err)
+				s.recordEvent(eventTypeRetry, stats)
+				s.inflight.Dec()
+				return true
+			}
 			log.Warnf("Dropping Payload after %d retries, due to: %v.\n", p.retries.Load(), err)
 			// queue is full; since this is the oldest payload, we drop it
 			s.releasePayload(p, eventTypeDropped, stats)
@@ -284,10 +456,14 @@ func (s *sender) sendOnce(p *payload) bool {
 		s.recordEvent(eventTypeRetry, stats)
 		return false
 	case nil:
+		s.healthy.Store(true)
+		s.ackPayload(p)
 		s.releasePayload(p, eventTypeSent, stats)
 	default:
 		// this is a fatal error, we have to drop this payload
+		s.healthy.Store(true)
 		log.Warnf("Dropping Payload due to non-retryable error: %v.\n", err)
+		s.ackPayload(p)
 		s.releasePayload(p, eventTypeRejected, stats)
 	}
 	return true
@@ -306,6 +482,13 @@ func waitForSenders(senders []*sender) {
 	wg.Wait()
 }
 
+// ackPayload removes p from the disk queue if it was replayed from it.
+func (s *sender) ackPayload(p *payload) {
+	if s.cfg.diskQueue != nil {
+		s.cfg.diskQueue.ack(p)
+	}
+}
+
 // releasePayload releases the payload p and records the specified event. The payload
 // should not be used again after a release.
 func (s *sender) releasePayload(p *payload, t eventType, data *eventData) {
@@ -381,6 +564,8 @@ type payload struct {
 	body    *bytes.Buffer     // request body
 	headers map[string]string // request headers
 	retries *atomic.Int32     // number of retries sending this payload
+
+	diskEntry *diskEntry // set when the payload was replayed from a disk queue
 }
 
 // ppool is a pool of payloads.
@@ -401,6 +586,7 @@ func newPayload(headers map[string]string) *payload {
 	p.body.Reset()
 	p.headers = headers
 	p.retries.Store(0)
+	p.diskEntry = nil
 	return p
 }
 
@@ -230,6 +230,126 @@ func TestSender(t *testing.T) {
 			assert.True(time.Since(start)-failed[i].duration < time.Second)
 		}
 	})
+
+	t.Run("disk-buffer", func(t *testing.T) {
+		assert := assert.New(t)
+		server := newTestServer()
+		defer server.Close()
+		defer useBackoffDuration(0)()
+		defer func(old time.Duration) { replayInterval = old }(replayInterval)
+		replayInterval = 10 * time.Millisecond
+
+		dq, err := newDiskQueue(t.TempDir(), 1<<20, time.Hour)
+		assert.NoError(err)
+		cfg := testSenderConfig(server.URL)
+		cfg.maxConns = 1
+		cfg.maxQueued = 1
+		cfg.diskQueue = dq
+		s := newSender(cfg, statsd)
+
+		// the first payload exhausts its retries and is buffered on disk, the
+		// others are buffered behind it instead of blocking the caller
+		s.Push(expectResponses(503, 503, 503, 503, 200))
+		for i := 0; i < 5; i++ {
+			s.Push(expectResponses(200))
+		}
+		assert.Eventually(func() bool {
+			return server.Accepted() == 6 && dq.len() == 0
+		}, 5*time.Second, 10*time.Millisecond)
+		s.Stop()
+
+		assert.Equal(4, server.Retried(), "retry")
+		assert.Equal(0, server.Failed(), "failed")
+		files, err := os.ReadDir(dq.dir)
+		assert.NoError(err)
+		assert.Empty(files)
+	})
+
+	t.Run("disk-buffer-write-error", func(t *testing.T) {
+		assert := assert.New(t)
+		server := newTestServerWithLatency(50 * time.Millisecond)
+		defer server.Close()
+
+		dir := t.TempDir()
+		dq, err := newDiskQueue(dir, 1<<20, time.Hour)
+		assert.NoError(err)
+		// payloads can't be written to disk anymore
+		assert.NoError(os.RemoveAll(dir))
+
+		var recorder mockRecorder
+		cfg := testSenderConfig(server.URL)
+		cfg.maxConns = 1
+		cfg.maxQueued = 1
+		cfg.diskQueue = dq
+		cfg.recorder = &recorder
+		s := newSender(cfg, statsd)
+		for i := 0; i < 5; i++ {
+			s.Push(expectResponses(200))
+		}
+		s.Stop()
+
+		// the payloads which could not be buffered are released and reported as dropped
+		dropped := len(recorder.data(eventTypeDropped))
+		assert.NotZero(dropped)
+		assert.Equal(5, server.Accepted()+dropped)
+		assert.Zero(s.inflight.Load())
+		assert.Zero(s.spilling.Load())
+	})
+
+	t.Run("disk-buffer-full", func(t *testing.T) {
+		assert := assert.New(t)
+		dq, err := newDiskQueue(t.TempDir(), 1<<20, time.Hour)
+		assert.NoError(err)
+
+		var recorder mockRecorder
+		cfg := testSenderConfig("http://localhost")
+		// neither the queue nor the spilled payloads are consumed
+		cfg.maxConns = 0
+		cfg.maxQueued = 1
+		cfg.recorder = &recorder
+		s := newSender(cfg, statsd)
+		s.cfg.diskQueue = dq
+		s.spillc = make(chan *payload, spillQueueSize)
+
+		// the payloads which can't be spilled are dropped instead of blocking
+		for i := 0; i < 1+spillQueueSize+2; i++ {
+			s.Push(expectResponses(200))
+		}
+		assert.Len(s.queue, 1)
+		assert.Len(s.spillc, spillQueueSize)
+		assert.Len(recorder.data(eventTypeDropped), 2)
+		assert.EqualValues(1+spillQueueSize, s.inflight.Load())
+	})
+
+	t.Run("disk-buffer-stop", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+		defer useBackoffDuration(time.Millisecond)()
+
+		dir := t.TempDir()
+		dq, err := newDiskQueue(dir, 1<<20, time.Hour)
+		assert.NoError(t, err)
+		cfg := testSenderConfig(server.URL)
+		cfg.maxRetries = 1000
+		cfg.diskQueue = dq
+		s := newSender(cfg, statsd)
+		s.Push(expectResponses(200))
+		// the sender gets stopped while the payload is being retried
+		s.mu.Lock()
+		s.closed = true
+		s.mu.Unlock()
+		assert.Eventually(t, func() bool { return s.inflight.Load() == 0 }, 5*time.Second, 10*time.Millisecond)
+		close(s.done)
+		close(s.queue)
+		close(s.spillc)
+
+		// the payload is replayed on restart
+		dq, err = newDiskQueue(dir, 1<<20, time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, dq.len())
+	})
 }
 
 func TestPayload(t *testing.T) {
@@ -354,6 +354,7 @@ func (w *DatadogStatsWriter) report() {
 	_ = w.statsd.Count("datadog.trace_agent.stats_writer.retries", w.stats.Retries.Swap(0), nil, 1)
 	_ = w.statsd.Count("datadog.trace_agent.stats_writer.splits", w.stats.Splits.Swap(0), nil, 1)
 	_ = w.statsd.Count("datadog.trace_agent.stats_writer.errors", w.stats.Errors.Swap(0), nil, 1)
+	reportDiskBuffer(w.statsd, "datadog.trace_agent.stats_writer", "stats", w.senders)
 }
 
 // recordEvent implements eventRecorder.
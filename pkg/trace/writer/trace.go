@@ -314,6 +314,7 @@ func (w *TraceWriter) report() {
 	_ = w.statsd.Count("datadog.trace_agent.trace_writer.traces", w.stats.Traces.Swap(0), nil, 1)
 	_ = w.statsd.Count("datadog.trace_agent.trace_writer.events", w.stats.Events.Swap(0), nil, 1)
 	_ = w.statsd.Count("datadog.trace_agent.trace_writer.spans", w.stats.Spans.Swap(0), nil, 1)
+	reportDiskBuffer(w.statsd, "datadog.trace_agent.trace_writer", "traces", w.senders)
 }
 
 var _ eventRecorder = (*TraceWriter)(nil)
@@ -41,3 +41,27 @@ func fromV1alpha2ListContainerStatsResponse(from *v1alpha2.ListContainerStatsRes
 func v1alpha2ContainerStatsFilter(from *runtimeapi.ContainerStatsFilter) *v1alpha2.ContainerStatsFilter {
 	return (*v1alpha2.ContainerStatsFilter)(unsafe.Pointer(from))
 }
+
+func fromV1alpha2ListContainersResponse(from *v1alpha2.ListContainersResponse) *runtimeapi.ListContainersResponse {
+	return (*runtimeapi.ListContainersResponse)(unsafe.Pointer(from))
+}
+
+func fromV1alpha2ContainerStatusResponse(from *v1alpha2.ContainerStatusResponse) *runtimeapi.ContainerStatusResponse {
+	return (*runtimeapi.ContainerStatusResponse)(unsafe.Pointer(from))
+}
+
+func fromV1alpha2ListPodSandboxResponse(from *v1alpha2.ListPodSandboxResponse) *runtimeapi.ListPodSandboxResponse {
+	return (*runtimeapi.ListPodSandboxResponse)(unsafe.Pointer(from))
+}
+
+func fromV1alpha2PodSandboxStatusResponse(from *v1alpha2.PodSandboxStatusResponse) *runtimeapi.PodSandboxStatusResponse {
+	return (*runtimeapi.PodSandboxStatusResponse)(unsafe.Pointer(from))
+}
+
+func fromV1alpha2ListImagesResponse(from *v1alpha2.ListImagesResponse) *runtimeapi.ListImagesResponse {
+	return (*runtimeapi.ListImagesResponse)(unsafe.Pointer(from))
+}
+
+func fromV1alpha2ImageStatusResponse(from *v1alpha2.ImageStatusResponse) *runtimeapi.ImageStatusResponse {
+	return (*runtimeapi.ImageStatusResponse)(unsafe.Pointer(from))
+}
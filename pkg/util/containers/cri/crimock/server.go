@@ -0,0 +1,289 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build cri
+
+package crimock
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	criv1 "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// FakeCRIServer is an in-process CRI server serving the CRI v1 API on a unix
+// socket, backed by in-memory containers, pod sandboxes and images.
+type FakeCRIServer struct {
+	criv1.UnimplementedRuntimeServiceServer
+	criv1.UnimplementedImageServiceServer
+
+	// RuntimeName and RuntimeVersion are returned by the Version call
+	RuntimeName    string
+	RuntimeVersion string
+	// EventsUnimplemented makes GetContainerEvents return an Unimplemented
+	// status, like runtimes without evented PLEG support do
+	EventsUnimplemented bool
+
+	mu             sync.Mutex
+	containers     map[string]*criv1.ContainerStatus
+	containerInfo  map[string]map[string]string
+	containerPods  map[string]string
+	sandboxes      map[string]*criv1.PodSandboxStatus
+	images         map[string]*criv1.Image
+	imageInfo      map[string]map[string]string
+	subscribers    []chan *criv1.ContainerEventResponse
+	subscribedChan chan struct{}
+}
+
+// NewFakeCRIServer returns an empty FakeCRIServer for the given runtime.
+func NewFakeCRIServer(runtimeName string) *FakeCRIServer {
+	return &FakeCRIServer{
+		RuntimeName:    runtimeName,
+		RuntimeVersion: "1.0.0",
+		containers:     make(map[string]*criv1.ContainerStatus),
+		containerInfo:  make(map[string]map[string]string),
+		containerPods:  make(map[string]string),
+		sandboxes:      make(map[string]*criv1.PodSandboxStatus),
+		images:         make(map[string]*criv1.Image),
+		imageInfo:      make(map[string]map[string]string),
+		subscribedChan: make(chan struct{}, 1),
+	}
+}
+
+// Start serves the CRI API until the end of the test, and returns the path of
+// the socket to connect to.
+func (s *FakeCRIServer) Start(t testing.TB) string {
+	t.Helper()
+
+	socketPath := filepath.Join(t.TempDir(), "cri.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("cannot listen on %s: %v", socketPath, err)
+	}
+
+	server := grpc.NewServer()
+	criv1.RegisterRuntimeServiceServer(server, s)
+	criv1.RegisterImageServiceServer(server, s)
+
+	go server.Serve(listener) //nolint:errcheck
+	t.Cleanup(server.Stop)
+
+	return socketPath
+}
+
+// PutContainer adds or updates a container of the given pod sandbox. info is
+// returned as the verbose information of the container status.
+func (s *FakeCRIServer) PutContainer(podSandboxID string, container *criv1.ContainerStatus, info map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.containers[container.Id] = container
+	s.containerInfo[container.Id] = info
+	s.containerPods[container.Id] = podSandboxID
+}
+
+// DeleteContainer removes a container.
+func (s *FakeCRIServer) DeleteContainer(containerID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.containers, containerID)
+	delete(s.containerInfo, containerID)
+	delete(s.containerPods, containerID)
+}
+
+// PutPodSandbox adds or updates a pod sandbox.
+func (s *FakeCRIServer) PutPodSandbox(sandbox *criv1.PodSandboxStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sandboxes[sandbox.Id] = sandbox
+}
+
+// PutImage adds or updates an image. info is returned as the verbose
+// information of the image status.
+func (s *FakeCRIServer) PutImage(image *criv1.Image, info map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.images[image.Id] = image
+	s.imageInfo[image.Id] = info
+}
+
+// DeleteImage removes an image.
+func (s *FakeCRIServer) DeleteImage(imageID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.images, imageID)
+	delete(s.imageInfo, imageID)
+}
+
+// WaitForSubscriber blocks until a client subscribes to the container events.
+func (s *FakeCRIServer) WaitForSubscriber(ctx context.Context) error {
+	select {
+	case <-s.subscribedChan:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SendEvent sends a container event to the subscribed clients.
+func (s *FakeCRIServer) SendEvent(event *criv1.ContainerEventResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, subscriber := range s.subscribers {
+		subscriber <- event
+	}
+}
+
+// Version implements the CRI RuntimeService.
+func (s *FakeCRIServer) Version(_ context.Context, _ *criv1.VersionRequest) (*criv1.VersionResponse, error) {
+	return &criv1.VersionResponse{
+		Version:           "0.1.0",
+		RuntimeName:       s.RuntimeName,
+		RuntimeVersion:    s.RuntimeVersion,
+		RuntimeApiVersion: "v1",
+	}, nil
+}
+
+// ListContainers implements the CRI RuntimeService.
+func (s *FakeCRIServer) ListContainers(_ context.Context, _ *criv1.ListContainersRequest) (*criv1.ListContainersResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp := &criv1.ListContainersResponse{}
+	for id, container := range s.containers {
+		resp.Containers = append(resp.Containers, &criv1.Container{
+			Id:           id,
+			PodSandboxId: s.containerPods[id],
+			Metadata:     container.Metadata,
+			Image:        container.Image,
+			ImageRef:     container.ImageRef,
+			State:        container.State,
+			CreatedAt:    container.CreatedAt,
+			Labels:       container.Labels,
+			Annotations:  container.Annotations,
+		})
+	}
+	return resp, nil
+}
+
+// ContainerStatus implements the CRI RuntimeService.
+func (s *FakeCRIServer) ContainerStatus(_ context.Context, req *criv1.ContainerStatusRequest) (*criv1.ContainerStatusResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	container, found := s.containers[req.ContainerId]
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "container %q not found", req.ContainerId)
+	}
+	resp := &criv1.ContainerStatusResponse{Status: container}
+	if req.Verbose {
+		resp.Info = s.containerInfo[req.ContainerId]
+	}
+	return resp, nil
+}
+
+// ListPodSandbox implements the CRI RuntimeService.
+func (s *FakeCRIServer) ListPodSandbox(_ context.Context, _ *criv1.ListPodSandboxRequest) (*criv1.ListPodSandboxResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp := &criv1.ListPodSandboxResponse{}
+	for id, sandbox := range s.sandboxes {
+		resp.Items = append(resp.Items, &criv1.PodSandbox{
+			Id:          id,
+			Metadata:    sandbox.Metadata,
+			State:       sandbox.State,
+			CreatedAt:   sandbox.CreatedAt,
+			Labels:      sandbox.Labels,
+			Annotations: sandbox.Annotations,
+		})
+	}
+	return resp, nil
+}
+
+// PodSandboxStatus implements the CRI RuntimeService.
+func (s *FakeCRIServer) PodSandboxStatus(_ context.Context, req *criv1.PodSandboxStatusRequest) (*criv1.PodSandboxStatusResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sandbox, found := s.sandboxes[req.PodSandboxId]
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "pod sandbox %q not found", req.PodSandboxId)
+	}
+	return &criv1.PodSandboxStatusResponse{Status: sandbox}, nil
+}
+
+// GetContainerEvents implements the CRI RuntimeService.
+func (s *FakeCRIServer) GetContainerEvents(_ *criv1.GetEventsRequest, stream criv1.RuntimeService_GetContainerEventsServer) error {
+	if s.EventsUnimplemented {
+		return status.Error(codes.Unimplemented, "method GetContainerEvents not implemented")
+	}
+
+	events := make(chan *criv1.ContainerEventResponse, 10)
+	s.mu.Lock()
+	s.subscribers = append(s.subscribers, events)
+	s.mu.Unlock()
+
+	select {
+	case s.subscribedChan <- struct{}{}:
+	default:
+	}
+
+	for {
+		select {
+		case event := <-events:
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// ListImages implements the CRI ImageService.
+func (s *FakeCRIServer) ListImages(_ context.Context, _ *criv1.ListImagesRequest) (*criv1.ListImagesResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp := &criv1.ListImagesResponse{}
+	for _, image := range s.images {
+		resp.Images = append(resp.Images, image)
+	}
+	return resp, nil
+}
+
+// ImageStatus implements the CRI ImageService.
+func (s *FakeCRIServer) ImageStatus(_ context.Context, req *criv1.ImageStatusRequest) (*criv1.ImageStatusResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	image, found := s.images[req.Image.GetImage()]
+	if !found {
+		// images can be looked up by any of their references
+		for _, img := range s.images {
+			for _, ref := range append(append([]string{}, img.RepoTags...), img.RepoDigests...) {
+				if ref == req.Image.GetImage() {
+					image = img
+				}
+			}
+		}
+	}
+	if image == nil {
+		// the CRI returns an empty status for unknown images
+		return &criv1.ImageStatusResponse{}, nil
+	}
+	resp := &criv1.ImageStatusResponse{Image: image}
+	if req.Verbose {
+		resp.Info = s.imageInfo[image.Id]
+	}
+	return resp, nil
+}
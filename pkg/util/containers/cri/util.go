@@ -48,13 +48,15 @@ type CRIUtil struct {
 	initRetry retry.Retrier
 
 	sync.Mutex
-	clientV1          criv1.RuntimeServiceClient
-	clientV1alpha2    criv1alpha2.RuntimeServiceClient
-	runtime           string
-	runtimeVersion    string
-	queryTimeout      time.Duration
-	connectionTimeout time.Duration
-	socketPath        string
+	clientV1            criv1.RuntimeServiceClient
+	clientV1alpha2      criv1alpha2.RuntimeServiceClient
+	imageClientV1       criv1.ImageServiceClient
+	imageClientV1alpha2 criv1alpha2.ImageServiceClient
+	runtime             string
+	runtimeVersion      string
+	queryTimeout        time.Duration
+	connectionTimeout   time.Duration
+	socketPath          string
 }
 
 // init makes an empty CRIUtil bootstrap itself.
@@ -132,6 +134,20 @@ func GetUtil() (*CRIUtil, error) {
 	return globalCRIUtil, nil
 }
 
+// NewUtil returns a CRIUtil connected to the CRI socket at the given path.
+// Unlike GetUtil, it is not shared and the connection is not retried.
+func NewUtil(socketPath string, connectionTimeout, queryTimeout time.Duration) (*CRIUtil, error) {
+	c := &CRIUtil{
+		queryTimeout:      queryTimeout,
+		connectionTimeout: connectionTimeout,
+		socketPath:        socketPath,
+	}
+	if err := c.init(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
 // GetContainerStats returns the stats for the container with the given ID
 func (c *CRIUtil) GetContainerStats(containerID string) (*criv1.ContainerStats, error) {
 	stats, err := c.listContainerStatsWithFilter(&criv1.ContainerStatsFilter{Id: containerID})
@@ -171,9 +187,11 @@ func (c *CRIUtil) detectAPIVersion(conn *grpc.ClientConn) error {
 	if _, err := clientV1.Version(ctx, &criv1.VersionRequest{}); err == nil {
 		log.Info("Using CRI v1 API")
 		c.clientV1 = clientV1
+		c.imageClientV1 = criv1.NewImageServiceClient(conn)
 	} else if status.Code(err) == codes.Unimplemented {
 		log.Info("Using CRI v1alpha2 API")
 		c.clientV1alpha2 = criv1alpha2.NewRuntimeServiceClient(conn)
+		c.imageClientV1alpha2 = criv1alpha2.NewImageServiceClient(conn)
 	} else {
 		return err
 	}
@@ -224,3 +242,124 @@ func (c *CRIUtil) listContainerStatsWithFilter(filter *criv1.ContainerStatsFilte
 	}
 	return stats, nil
 }
+
+// ListContainers returns the containers known by the runtime, pod sandboxes excluded
+func (c *CRIUtil) ListContainers() ([]*criv1.Container, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.queryTimeout)
+	defer cancel()
+
+	if c.clientV1 != nil {
+		r, err := c.clientV1.ListContainers(ctx, &criv1.ListContainersRequest{})
+		if err != nil {
+			return nil, err
+		}
+		return r.GetContainers(), nil
+	}
+
+	r, err := c.clientV1alpha2.ListContainers(ctx, &criv1alpha2.ListContainersRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return fromV1alpha2ListContainersResponse(r).GetContainers(), nil
+}
+
+// ContainerStatus returns the status of the container with the given ID,
+// along with the runtime specific verbose information
+func (c *CRIUtil) ContainerStatus(containerID string) (*criv1.ContainerStatusResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.queryTimeout)
+	defer cancel()
+
+	if c.clientV1 != nil {
+		return c.clientV1.ContainerStatus(ctx, &criv1.ContainerStatusRequest{ContainerId: containerID, Verbose: true})
+	}
+
+	r, err := c.clientV1alpha2.ContainerStatus(ctx, &criv1alpha2.ContainerStatusRequest{ContainerId: containerID, Verbose: true})
+	if err != nil {
+		return nil, err
+	}
+	return fromV1alpha2ContainerStatusResponse(r), nil
+}
+
+// ListPodSandboxes returns the pod sandboxes known by the runtime
+func (c *CRIUtil) ListPodSandboxes() ([]*criv1.PodSandbox, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.queryTimeout)
+	defer cancel()
+
+	if c.clientV1 != nil {
+		r, err := c.clientV1.ListPodSandbox(ctx, &criv1.ListPodSandboxRequest{})
+		if err != nil {
+			return nil, err
+		}
+		return r.GetItems(), nil
+	}
+
+	r, err := c.clientV1alpha2.ListPodSandbox(ctx, &criv1alpha2.ListPodSandboxRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return fromV1alpha2ListPodSandboxResponse(r).GetItems(), nil
+}
+
+// PodSandboxStatus returns the status of the pod sandbox with the given ID
+func (c *CRIUtil) PodSandboxStatus(podSandboxID string) (*criv1.PodSandboxStatusResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.queryTimeout)
+	defer cancel()
+
+	if c.clientV1 != nil {
+		return c.clientV1.PodSandboxStatus(ctx, &criv1.PodSandboxStatusRequest{PodSandboxId: podSandboxID})
+	}
+
+	r, err := c.clientV1alpha2.PodSandboxStatus(ctx, &criv1alpha2.PodSandboxStatusRequest{PodSandboxId: podSandboxID})
+	if err != nil {
+		return nil, err
+	}
+	return fromV1alpha2PodSandboxStatusResponse(r), nil
+}
+
+// ListImages returns the images known by the runtime
+func (c *CRIUtil) ListImages() ([]*criv1.Image, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.queryTimeout)
+	defer cancel()
+
+	if c.imageClientV1 != nil {
+		r, err := c.imageClientV1.ListImages(ctx, &criv1.ListImagesRequest{})
+		if err != nil {
+			return nil, err
+		}
+		return r.GetImages(), nil
+	}
+
+	r, err := c.imageClientV1alpha2.ListImages(ctx, &criv1alpha2.ListImagesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return fromV1alpha2ListImagesResponse(r).GetImages(), nil
+}
+
+// ImageStatus returns the status of the image with the given ID or reference,
+// along with the runtime specific verbose information
+func (c *CRIUtil) ImageStatus(image string) (*criv1.ImageStatusResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.queryTimeout)
+	defer cancel()
+
+	if c.imageClientV1 != nil {
+		return c.imageClientV1.ImageStatus(ctx, &criv1.ImageStatusRequest{Image: &criv1.ImageSpec{Image: image}, Verbose: true})
+	}
+
+	r, err := c.imageClientV1alpha2.ImageStatus(ctx, &criv1alpha2.ImageStatusRequest{Image: &criv1alpha2.ImageSpec{Image: image}, Verbose: true})
+	if err != nil {
+		return nil, err
+	}
+	return fromV1alpha2ImageStatusResponse(r), nil
+}
+
+// GetContainerEvents subscribes to the container events of the runtime until
+// the given context is cancelled. Events are only available with the CRI v1
+// API, and runtimes may not implement them, in which case the returned stream
+// fails with an Unimplemented status.
+func (c *CRIUtil) GetContainerEvents(ctx context.Context) (criv1.RuntimeService_GetContainerEventsClient, error) {
+	if c.clientV1 == nil {
+		return nil, status.Error(codes.Unimplemented, "container events require the CRI v1 API")
+	}
+	return c.clientV1.GetContainerEvents(ctx, &criv1.GetEventsRequest{})
+}
@@ -15,4 +15,6 @@ const (
 	SecretsInformer InformerName = "v1/secrets"
 	// WebhooksInformer holds the name of the informer
 	WebhooksInformer InformerName = "admissionregistration.k8s.io/v1/mutatingwebhookconfigurations"
+	// ValidatingWebhooksInformer holds the name of the informer
+	ValidatingWebhooksInformer InformerName = "admissionregistration.k8s.io/v1/validatingwebhookconfigurations"
 )
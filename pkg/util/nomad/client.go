@@ -0,0 +1,80 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build nomad
+
+package nomad
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+)
+
+// blockingQueryWaitTime is the maximum duration of the blocking queries
+const blockingQueryWaitTime = 5 * time.Minute
+
+// NewClient returns a Nomad API client configured from the nomad section of
+// the agent configuration. Unset options fall back to the standard NOMAD_*
+// environment variables, and to the local Nomad agent.
+func NewClient() (*api.Client, error) {
+	clientCfg := api.DefaultConfig()
+
+	if address := config.Datadog().GetString("nomad.address"); address != "" {
+		clientCfg.Address = address
+	}
+	if token := config.Datadog().GetString("nomad.token"); token != "" {
+		clientCfg.SecretID = token
+	}
+	if caFile := config.Datadog().GetString("nomad.ca_file"); caFile != "" {
+		clientCfg.TLSConfig.CACert = caFile
+	}
+	if certFile := config.Datadog().GetString("nomad.cert_file"); certFile != "" {
+		clientCfg.TLSConfig.ClientCert = certFile
+	}
+	if keyFile := config.Datadog().GetString("nomad.key_file"); keyFile != "" {
+		clientCfg.TLSConfig.ClientKey = keyFile
+	}
+
+	return api.NewClient(clientCfg)
+}
+
+// LocalNodeID returns the ID of the node of the Nomad client agent the
+// client is connected to.
+func LocalNodeID(client *api.Client) (string, error) {
+	self, err := client.Agent().Self()
+	if err != nil {
+		return "", err
+	}
+
+	nodeID := self.Stats["client"]["node_id"]
+	if nodeID == "" {
+		return "", errors.New("the Nomad agent is not running in client mode")
+	}
+
+	return nodeID, nil
+}
+
+// NodeAllocations returns the allocations of a node. If waitIndex is not
+// zero, the call blocks until the allocations change after this index, or
+// until a timeout. The returned index can be used as the waitIndex of the
+// next call.
+func NodeAllocations(ctx context.Context, client *api.Client, nodeID string, waitIndex uint64) ([]*api.Allocation, uint64, error) {
+	q := &api.QueryOptions{
+		WaitIndex: waitIndex,
+		WaitTime:  blockingQueryWaitTime,
+	}
+
+	allocs, meta, err := client.Nodes().Allocations(nodeID, q.WithContext(ctx))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return allocs, meta.LastIndex, nil
+}
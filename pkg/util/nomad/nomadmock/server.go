@@ -0,0 +1,116 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build nomad
+
+// Package nomadmock implements a stand-in of the Nomad HTTP API for tests.
+package nomadmock
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+)
+
+// FakeNomadServer serves the parts of the Nomad HTTP API used to discover the
+// allocations of a client node, including blocking queries.
+type FakeNomadServer struct {
+	nodeID string
+
+	mu      sync.Mutex
+	index   uint64
+	allocs  []*api.Allocation
+	changed chan struct{}
+}
+
+// NewFakeNomadServer returns a FakeNomadServer for a client node without
+// allocations.
+func NewFakeNomadServer(nodeID string) *FakeNomadServer {
+	return &FakeNomadServer{
+		nodeID:  nodeID,
+		index:   1,
+		changed: make(chan struct{}),
+	}
+}
+
+// Start serves the API until the end of the test, and returns its address.
+func (s *FakeNomadServer) Start(t testing.TB) string {
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+// SetAllocations replaces the allocations of the node, and unblocks the
+// pending blocking queries.
+func (s *FakeNomadServer) SetAllocations(allocs ...*api.Allocation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.allocs = allocs
+	s.index++
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// ServeHTTP implements http.Handler.
+func (s *FakeNomadServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/v1/agent/self":
+		s.writeJSON(w, 0, &api.AgentSelf{
+			Stats: map[string]map[string]string{
+				"client": {"node_id": s.nodeID},
+			},
+		})
+
+	case r.URL.Path == "/v1/node/"+s.nodeID+"/allocations":
+		waitIndex, _ := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64)
+		waitTime := 5 * time.Minute
+		if wait := r.URL.Query().Get("wait"); wait != "" {
+			if d, err := time.ParseDuration(wait); err == nil {
+				waitTime = d
+			}
+		}
+
+		s.mu.Lock()
+		index, changed := s.index, s.changed
+		s.mu.Unlock()
+
+		if waitIndex >= index {
+			select {
+			case <-changed:
+			case <-time.After(waitTime):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		s.mu.Lock()
+		index, allocs := s.index, s.allocs
+		s.mu.Unlock()
+
+		s.writeJSON(w, index, allocs)
+
+	case strings.HasPrefix(r.URL.Path, "/v1/node/"):
+		http.Error(w, "node not found", http.StatusNotFound)
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *FakeNomadServer) writeJSON(w http.ResponseWriter, index uint64, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Nomad-Index", strconv.FormatUint(index, 10))
+	w.Header().Set("X-Nomad-LastContact", "0")
+	w.Header().Set("X-Nomad-KnownLeader", "true")
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,191 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build nomad
+
+package nomad
+
+import (
+	"sort"
+
+	"github.com/hashicorp/nomad/api"
+)
+
+const (
+	// TaskEntityPrefix is the prefix of the entity names of Nomad tasks
+	TaskEntityPrefix = "nomad_task://"
+
+	taskStateRunning   = "running"
+	defaultNetworkMode = "host"
+)
+
+// Task is a running task of a Nomad allocation.
+type Task struct {
+	AllocID   string
+	Name      string
+	Namespace string
+	JobID     string
+	Group     string
+	Driver    string
+
+	// Hosts maps the network modes of the task to the IP address its ports
+	// are allocated on.
+	Hosts map[string]string
+	// Ports are the ports allocated to the task, sorted by value.
+	Ports []Port
+
+	// Meta merges the job, group and task meta, the most specific level
+	// taking precedence.
+	Meta map[string]string
+	// ServiceTags are the tags of the services registered for the task.
+	ServiceTags []string
+}
+
+// Port is a port allocated to a task.
+type Port struct {
+	Label string
+	Value int
+}
+
+// EntityName returns the entity name of the task.
+func (t *Task) EntityName() string {
+	return TaskEntityName(t.AllocID, t.Name)
+}
+
+// TaskEntityName returns the entity name of the task of an allocation.
+func TaskEntityName(allocID string, task string) string {
+	return TaskEntityPrefix + allocID + "/" + task
+}
+
+// RunningTasks returns the running tasks of the running allocations, sorted
+// by entity name.
+func RunningTasks(allocs []*api.Allocation) []*Task {
+	var tasks []*Task
+
+	for _, alloc := range allocs {
+		if alloc.ClientStatus != api.AllocClientStatusRunning {
+			continue
+		}
+
+		group := findTaskGroup(alloc.Job, alloc.TaskGroup)
+
+		for name, state := range alloc.TaskStates {
+			if state == nil || state.State != taskStateRunning {
+				continue
+			}
+
+			tasks = append(tasks, buildTask(alloc, group, name))
+		}
+	}
+
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].EntityName() < tasks[j].EntityName()
+	})
+
+	return tasks
+}
+
+func buildTask(alloc *api.Allocation, group *api.TaskGroup, name string) *Task {
+	task := &Task{
+		AllocID:   alloc.ID,
+		Name:      name,
+		Namespace: alloc.Namespace,
+		JobID:     alloc.JobID,
+		Group:     alloc.TaskGroup,
+		Hosts:     make(map[string]string),
+		Meta:      make(map[string]string),
+	}
+
+	if alloc.Job != nil {
+		mergeMeta(task.Meta, alloc.Job.Meta)
+	}
+
+	if group != nil {
+		mergeMeta(task.Meta, group.Meta)
+
+		for _, service := range group.Services {
+			// group services are attributed to the task they reference, or
+			// to the only task of the group
+			if service.TaskName == name || (service.TaskName == "" && len(group.Tasks) == 1) {
+				task.ServiceTags = append(task.ServiceTags, service.Tags...)
+			}
+		}
+
+		for _, groupTask := range group.Tasks {
+			if groupTask.Name != name {
+				continue
+			}
+
+			task.Driver = groupTask.Driver
+			mergeMeta(task.Meta, groupTask.Meta)
+			for _, service := range groupTask.Services {
+				task.ServiceTags = append(task.ServiceTags, service.Tags...)
+			}
+		}
+	}
+
+	if resources := alloc.AllocatedResources; resources != nil {
+		networks := resources.Shared.Networks
+		if taskResources, found := resources.Tasks[name]; found && taskResources != nil {
+			networks = append(append([]*api.NetworkResource{}, networks...), taskResources.Networks...)
+		}
+
+		ports := make(map[string]int)
+		for _, network := range networks {
+			if network == nil {
+				continue
+			}
+
+			if network.IP != "" {
+				mode := network.Mode
+				if mode == "" {
+					mode = defaultNetworkMode
+				}
+				task.Hosts[mode] = network.IP
+			}
+
+			for _, port := range append(append([]api.Port{}, network.ReservedPorts...), network.DynamicPorts...) {
+				ports[port.Label] = port.Value
+			}
+		}
+
+		for _, port := range resources.Shared.Ports {
+			ports[port.Label] = port.Value
+		}
+
+		for label, value := range ports {
+			task.Ports = append(task.Ports, Port{Label: label, Value: value})
+		}
+
+		sort.Slice(task.Ports, func(i, j int) bool {
+			if task.Ports[i].Value == task.Ports[j].Value {
+				return task.Ports[i].Label < task.Ports[j].Label
+			}
+			return task.Ports[i].Value < task.Ports[j].Value
+		})
+	}
+
+	return task
+}
+
+func findTaskGroup(job *api.Job, name string) *api.TaskGroup {
+	if job == nil {
+		return nil
+	}
+
+	for _, group := range job.TaskGroups {
+		if group != nil && group.Name != nil && *group.Name == name {
+			return group
+		}
+	}
+
+	return nil
+}
+
+func mergeMeta(dst map[string]string, src map[string]string) {
+	for k, v := range src {
+		dst[k] = v
+	}
+}
@@ -0,0 +1,105 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build nomad
+
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunningTasks(t *testing.T) {
+	groupName := "web"
+	allocs := []*api.Allocation{
+		{
+			ID:           "alloc1",
+			Namespace:    "default",
+			JobID:        "frontend",
+			TaskGroup:    groupName,
+			ClientStatus: api.AllocClientStatusRunning,
+			Job: &api.Job{
+				Meta: map[string]string{"team": "web", "owner": "job"},
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: &groupName,
+						Meta: map[string]string{"owner": "group"},
+						Services: []*api.Service{
+							{Name: "nginx", Tags: []string{"group-nginx"}, TaskName: "nginx"},
+							{Name: "shared", Tags: []string{"group-shared"}},
+						},
+						Tasks: []*api.Task{
+							{
+								Name:     "nginx",
+								Driver:   "docker",
+								Meta:     map[string]string{"owner": "task"},
+								Services: []*api.Service{{Name: "nginx-task", Tags: []string{"task-nginx"}}},
+							},
+							{Name: "sidecar", Driver: "raw_exec"},
+						},
+					},
+				},
+			},
+			TaskStates: map[string]*api.TaskState{
+				"nginx":   {State: "running"},
+				"sidecar": {State: "dead"},
+			},
+			AllocatedResources: &api.AllocatedResources{
+				Shared: api.AllocatedSharedResources{
+					Networks: []*api.NetworkResource{
+						{Mode: "bridge", IP: "10.0.0.1", DynamicPorts: []api.Port{{Label: "http", Value: 28080, To: 80}}},
+					},
+					Ports: []api.PortMapping{{Label: "metrics", Value: 29090, To: 9090}},
+				},
+				Tasks: map[string]*api.AllocatedTaskResources{
+					"nginx": {
+						Networks: []*api.NetworkResource{
+							{IP: "10.0.0.1", ReservedPorts: []api.Port{{Label: "admin", Value: 8000}}},
+						},
+					},
+				},
+			},
+		},
+		{
+			ID:           "alloc2",
+			JobID:        "batch",
+			TaskGroup:    "work",
+			ClientStatus: "complete",
+			TaskStates: map[string]*api.TaskState{
+				"worker": {State: "running"},
+			},
+		},
+	}
+
+	assert.Equal(t, []*Task{
+		{
+			AllocID:   "alloc1",
+			Name:      "nginx",
+			Namespace: "default",
+			JobID:     "frontend",
+			Group:     "web",
+			Driver:    "docker",
+			Hosts: map[string]string{
+				"bridge": "10.0.0.1",
+				"host":   "10.0.0.1",
+			},
+			Ports: []Port{
+				{Label: "admin", Value: 8000},
+				{Label: "http", Value: 28080},
+				{Label: "metrics", Value: 29090},
+			},
+			Meta:        map[string]string{"team": "web", "owner": "task"},
+			ServiceTags: []string{"group-nginx", "task-nginx"},
+		},
+	}, RunningTasks(allocs))
+}
+
+func TestTaskEntityName(t *testing.T) {
+	task := &Task{AllocID: "alloc1", Name: "nginx"}
+	assert.Equal(t, "nomad_task://alloc1/nginx", task.EntityName())
+}
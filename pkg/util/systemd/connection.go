@@ -0,0 +1,41 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build systemd
+
+package systemd
+
+import (
+	"context"
+
+	"github.com/coreos/go-systemd/v22/dbus"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// DefaultPrivateSocket is the path of the systemd private socket.
+const DefaultPrivateSocket = "/run/systemd/private"
+
+// NewConnection returns a connection to systemd.
+//
+// If privateSocket is set, it is used to connect directly to systemd.
+// Otherwise, the agent connects to the host private socket when running in a
+// container, and to the system bus (falling back to the private socket) when
+// running on the host.
+func NewConnection(ctx context.Context, privateSocket string) (*dbus.Conn, error) {
+	if privateSocket != "" {
+		return NewSystemdConnection(privateSocket)
+	}
+	if config.IsContainerized() {
+		return NewSystemdConnection("/host" + DefaultPrivateSocket)
+	}
+	conn, err := dbus.NewSystemConnectionContext(ctx)
+	if err != nil {
+		log.Debugf("Error getting new connection using system bus socket: %v", err)
+		return NewSystemdConnection(DefaultPrivateSocket)
+	}
+	return conn, nil
+}
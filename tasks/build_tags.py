@@ -33,6 +33,7 @@ ALL_TAGS = {
     "kubelet",
     "linux_bpf",
     "netcgo",  # Force the use of the CGO resolver. This will also have the effect of making the binary non-static
+    "nomad",
     "npm",
     "oracle",
     "orchestrator",
@@ -69,6 +70,7 @@ AGENT_TAGS = {
     "kubeapiserver",
     "kubelet",
     "netcgo",
+    "nomad",
     "oracle",
     "orchestrator",
     "otlp",
@@ -92,6 +94,7 @@ AGENT_HEROKU_TAGS = AGENT_TAGS.difference(
         "jetson",
         "kubeapiserver",
         "kubelet",
+        "nomad",
         "oracle",
         "orchestrator",
         "podman",
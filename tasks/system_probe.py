@@ -463,6 +463,15 @@ def ninja_cgo_type_files(nw: NinjaWriter):
             "pkg/network/protocols/postgres/types.go": [
                 "pkg/network/ebpf/c/protocols/postgres/types.h",
             ],
+            "pkg/network/protocols/memcached/types.go": [
+                "pkg/network/ebpf/c/protocols/memcached/types.h",
+            ],
+            "pkg/network/protocols/cassandra/types.go": [
+                "pkg/network/ebpf/c/protocols/cassandra/types.h",
+            ],
+            "pkg/network/protocols/dnstls/types.go": [
+                "pkg/network/ebpf/c/protocols/dnstls/types.h",
+            ],
             "pkg/ebpf/telemetry/types.go": [
                 "pkg/ebpf/c/telemetry_types.h",
             ],